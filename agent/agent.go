@@ -39,7 +39,10 @@ import (
 	"github.com/coder/clistat"
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentlogsink"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentservices"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/agent/proto/resourcesmonitor"
@@ -91,12 +94,17 @@ type Options struct {
 	Execer                       agentexec.Execer
 
 	ExperimentalDevcontainersEnabled bool
+	ContainerRuntime                 agentcontainers.Runtime  // Detected on first use if empty.
 	ContainerAPIOptions              []agentcontainers.Option // Enable ExperimentalDevcontainersEnabled for these to be effective.
+
+	// LogShipper ships script output and connection events to external log
+	// sinks. It is optional, and owned by the caller.
+	LogShipper *agentlogsink.Shipper
 }
 
 type Client interface {
-	ConnectRPC26(ctx context.Context) (
-		proto.DRPCAgentClient26, tailnetproto.DRPCTailnetClient26, error,
+	ConnectRPC210(ctx context.Context) (
+		proto.DRPCAgentClient210, tailnetproto.DRPCTailnetClient210, error,
 	)
 	RewriteDERPMap(derpMap *tailcfg.DERPMap)
 }
@@ -184,6 +192,8 @@ func New(options Options) Agent {
 		sshMaxTimeout:                      options.SSHMaxTimeout,
 		subsystems:                         options.Subsystems,
 		logSender:                          agentsdk.NewLogSender(options.Logger),
+		logShipper:                         options.LogShipper,
+		sessionRecorder:                    agentrecord.NewManager(options.Logger.Named("session-recorder"), quartz.NewReal()),
 		blockFileTransfer:                  options.BlockFileTransfer,
 
 		prometheusRegistry: prometheusRegistry,
@@ -193,6 +203,16 @@ func New(options Options) Agent {
 		experimentalDevcontainersEnabled: options.ExperimentalDevcontainersEnabled,
 		containerAPIOptions:              options.ContainerAPIOptions,
 	}
+	// Detecting the container runtime runs external commands, so it is
+	// deferred until a container is first accessed.
+	a.containerRuntime = sync.OnceValue(func() agentcontainers.Runtime {
+		if options.ContainerRuntime != "" {
+			return options.ContainerRuntime
+		}
+		runtime := agentcontainers.DetectRuntime(a.hardCtx, a.execer)
+		a.logger.Info(a.hardCtx, "detected container runtime", slog.F("runtime", runtime))
+		return runtime
+	})
 	// Initially, we have a closed channel, reflecting the fact that we are not initially connected.
 	// Each time we connect we replace the channel (while holding the closeMutex) with a new one
 	// that gets closed on disconnection.  This is used to wait for graceful disconnection from the
@@ -247,6 +267,7 @@ type agent struct {
 	manifest                           atomic.Pointer[agentsdk.Manifest] // manifest is atomic because values can change after reconnection.
 	reportMetadataInterval             time.Duration
 	scriptRunner                       *agentscripts.Runner
+	serviceSupervisor                  *agentservices.Supervisor
 	announcementBanners                atomic.Pointer[[]codersdk.BannerConfig] // announcementBanners is atomic because it is periodically updated.
 	announcementBannersRefreshInterval time.Duration
 	sessionToken                       atomic.Pointer[string]
@@ -264,7 +285,10 @@ type agent struct {
 	reportConnectionsMu     sync.Mutex
 	reportConnections       []*proto.ReportConnectionRequest
 
-	logSender *agentsdk.LogSender
+	logSender  *agentsdk.LogSender
+	logShipper *agentlogsink.Shipper
+
+	sessionRecorder *agentrecord.Manager
 
 	prometheusRegistry *prometheus.Registry
 	// metrics are prometheus registered metrics that will be collected and
@@ -273,6 +297,7 @@ type agent struct {
 	execer  agentexec.Execer
 
 	experimentalDevcontainersEnabled bool
+	containerRuntime                 func() agentcontainers.Runtime
 	containerAPIOptions              []agentcontainers.Option
 	containerAPI                     atomic.Pointer[agentcontainers.API] // Set by apiHandler.
 }
@@ -293,24 +318,13 @@ func (a *agent) init() {
 		WorkingDirectory:    func() string { return a.manifest.Load().Directory },
 		BlockFileTransfer:   a.blockFileTransfer,
 		ReportConnection: func(id uuid.UUID, magicType agentssh.MagicSessionType, ip string) func(code int, reason string) {
-			var connectionType proto.Connection_Type
-			switch magicType {
-			case agentssh.MagicSessionTypeSSH:
-				connectionType = proto.Connection_SSH
-			case agentssh.MagicSessionTypeVSCode:
-				connectionType = proto.Connection_VSCODE
-			case agentssh.MagicSessionTypeJetBrains:
-				connectionType = proto.Connection_JETBRAINS
-			case agentssh.MagicSessionTypeUnknown:
-				connectionType = proto.Connection_TYPE_UNSPECIFIED
-			default:
-				a.logger.Error(a.hardCtx, "unhandled magic session type when reporting connection", slog.F("magic_type", magicType))
-				connectionType = proto.Connection_TYPE_UNSPECIFIED
-			}
-
-			return a.reportConnection(id, connectionType, ip)
+			return a.reportConnection(id, a.sshConnectionType(magicType), ip)
+		},
+		RecordSession: func(magicType agentssh.MagicSessionType, command string, height, width uint16) *agentrecord.Recorder {
+			return a.sessionRecorder.Start(a.sshConnectionType(magicType), command, height, width)
 		},
 
+		ContainerRuntime:                 a.containerRuntime,
 		ExperimentalDevContainersEnabled: a.experimentalDevcontainersEnabled,
 	})
 	if err != nil {
@@ -318,18 +332,23 @@ func (a *agent) init() {
 	}
 	a.sshServer = sshSrv
 	a.scriptRunner = agentscripts.New(agentscripts.Options{
-		LogDir:      a.logDir,
-		DataDirBase: a.scriptDataDir,
-		Logger:      a.logger,
-		SSHServer:   sshSrv,
-		Filesystem:  a.filesystem,
-		GetScriptLogger: func(logSourceID uuid.UUID) agentscripts.ScriptLogger {
-			return a.logSender.GetScriptLogger(logSourceID)
-		},
+		LogDir:          a.logDir,
+		DataDirBase:     a.scriptDataDir,
+		Logger:          a.logger,
+		SSHServer:       sshSrv,
+		Filesystem:      a.filesystem,
+		GetScriptLogger: a.scriptLogger,
 	})
 	// Register runner metrics. If the prom registry is nil, the metrics
 	// will not report anywhere.
 	a.scriptRunner.RegisterMetrics(a.prometheusRegistry)
+	a.serviceSupervisor = agentservices.New(agentservices.Options{
+		LogDir:          a.logDir,
+		Logger:          a.logger.Named("services"),
+		SSHServer:       sshSrv,
+		Filesystem:      a.filesystem,
+		GetScriptLogger: a.scriptLogger,
+	})
 
 	a.reconnectingPTYServer = reconnectingpty.NewServer(
 		a.logger.Named("reconnecting-pty"),
@@ -341,6 +360,12 @@ func (a *agent) init() {
 		a.reconnectingPTYTimeout,
 		func(s *reconnectingpty.Server) {
 			s.ExperimentalDevcontainersEnabled = a.experimentalDevcontainersEnabled
+			// Persistent reconnecting ptys are adopted from this directory
+			// when the agent restarts.
+			s.PersistentDir = filepath.Join(a.tempDir, "coder-rpty")
+			s.RecordSession = func(command string, height, width uint16) *agentrecord.Recorder {
+				return a.sessionRecorder.Start(proto.Connection_RECONNECTING_PTY, command, height, width)
+			}
 		},
 	)
 	go a.runLoop()
@@ -751,6 +776,24 @@ func (a *agent) setLifecycle(state codersdk.WorkspaceAgentLifecycle) {
 	}
 }
 
+// sshConnectionType returns the connection type reported for SSH sessions of
+// the given type.
+func (a *agent) sshConnectionType(magicType agentssh.MagicSessionType) proto.Connection_Type {
+	switch magicType {
+	case agentssh.MagicSessionTypeSSH:
+		return proto.Connection_SSH
+	case agentssh.MagicSessionTypeVSCode:
+		return proto.Connection_VSCODE
+	case agentssh.MagicSessionTypeJetBrains:
+		return proto.Connection_JETBRAINS
+	case agentssh.MagicSessionTypeUnknown:
+		return proto.Connection_TYPE_UNSPECIFIED
+	default:
+		a.logger.Error(a.hardCtx, "unhandled magic session type", slog.F("magic_type", magicType))
+		return proto.Connection_TYPE_UNSPECIFIED
+	}
+}
+
 // reportConnectionsLoop reports connections to the agent for auditing.
 func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 	for {
@@ -821,16 +864,18 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 			slog.F("ip", ip),
 		)
 	} else {
+		conn := &proto.Connection{
+			Id:         id[:],
+			Action:     proto.Connection_CONNECT,
+			Type:       connectionType,
+			Timestamp:  timestamppb.New(time.Now()),
+			Ip:         ip,
+			StatusCode: 0,
+			Reason:     nil,
+		}
+		a.shipConnection(conn)
 		a.reportConnections = append(a.reportConnections, &proto.ReportConnectionRequest{
-			Connection: &proto.Connection{
-				Id:         id[:],
-				Action:     proto.Connection_CONNECT,
-				Type:       connectionType,
-				Timestamp:  timestamppb.New(time.Now()),
-				Ip:         ip,
-				StatusCode: 0,
-				Reason:     nil,
-			},
+			Connection: conn,
 		})
 		select {
 		case a.reportConnectionsUpdate <- struct{}{}:
@@ -851,16 +896,18 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 			return
 		}
 
+		conn := &proto.Connection{
+			Id:         id[:],
+			Action:     proto.Connection_DISCONNECT,
+			Type:       connectionType,
+			Timestamp:  timestamppb.New(time.Now()),
+			Ip:         ip,
+			StatusCode: int32(code), //nolint:gosec
+			Reason:     &reason,
+		}
+		a.shipConnection(conn)
 		a.reportConnections = append(a.reportConnections, &proto.ReportConnectionRequest{
-			Connection: &proto.Connection{
-				Id:         id[:],
-				Action:     proto.Connection_DISCONNECT,
-				Type:       connectionType,
-				Timestamp:  timestamppb.New(time.Now()),
-				Ip:         ip,
-				StatusCode: int32(code), //nolint:gosec
-				Reason:     &reason,
-			},
+			Connection: conn,
 		})
 		select {
 		case a.reportConnectionsUpdate <- struct{}{}:
@@ -908,7 +955,7 @@ func (a *agent) run() (retErr error) {
 	a.sessionToken.Store(&sessionToken)
 
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs
-	aAPI, tAPI, err := a.client.ConnectRPC26(a.hardCtx)
+	aAPI, tAPI, err := a.client.ConnectRPC210(a.hardCtx)
 	if err != nil {
 		return err
 	}
@@ -986,6 +1033,20 @@ func (a *agent) run() (retErr error) {
 	// gracefulShutdownBehaviorRemain.
 	connMan.startAgentAPI("report connections", gracefulShutdownBehaviorRemain, a.reportConnectionsLoop)
 
+	// Session recordings are part of auditing too, and sessions may still
+	// be closing during graceful shutdown.
+	connMan.startAgentAPI("upload session recordings", gracefulShutdownBehaviorRemain,
+		func(ctx context.Context, _ proto.DRPCAgentClient24) error {
+			return a.sessionRecorder.SendLoop(ctx, aAPI)
+		})
+
+	// The status of services feeds the health of the workspace, keep
+	// reporting it while services are stopped during graceful shutdown.
+	connMan.startAgentAPI("report services", gracefulShutdownBehaviorRemain,
+		func(ctx context.Context, _ proto.DRPCAgentClient24) error {
+			return a.serviceSupervisor.SendLoop(ctx, aAPI)
+		})
+
 	// channels to sync goroutines below
 	//  handle manifest
 	//       |
@@ -1071,10 +1132,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 		if err != nil {
 			return xerrors.Errorf("fetch metadata: %w", err)
 		}
-		a.logger.Info(ctx, "fetched manifest", slog.F("manifest", mp))
+		a.logger.Info(ctx, "fetched manifest", slog.F("manifest", redactProtoManifest(mp)))
 		manifest, err := agentsdk.ManifestFromProto(mp)
 		if err != nil {
-			a.logger.Critical(ctx, "failed to convert manifest", slog.F("manifest", mp), slog.Error(err))
+			a.logger.Critical(ctx, "failed to convert manifest", slog.F("manifest", redactProtoManifest(mp)), slog.Error(err))
 			return xerrors.Errorf("convert manifest: %w", err)
 		}
 		if manifest.AgentID == uuid.Nil {
@@ -1107,6 +1168,14 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 			return xerrors.Errorf("update workspace agent startup: %w", err)
 		}
 
+		// Secrets are written on every manifest fetch so that changes are
+		// picked up when the agent reconnects, and before the startup
+		// scripts run so that they can rely on them.
+		writeSecretFiles(ctx, a.logger, a.filesystem, manifest.Secrets)
+
+		a.sessionRecorder.SetEnabled(manifest.SessionRecordingEnabled)
+		a.setLogSinkResource(manifest)
+
 		oldManifest := a.manifest.Swap(&manifest)
 		manifestOK.complete(nil)
 		sentResult = true
@@ -1144,6 +1213,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 			if err != nil {
 				return xerrors.Errorf("init script runner: %w", err)
 			}
+			err = a.serviceSupervisor.Init(manifest.Services)
+			if err != nil {
+				return xerrors.Errorf("init service supervisor: %w", err)
+			}
 			err = a.trackGoroutine(func() {
 				start := time.Now()
 				// Here we use the graceful context because the script runner is
@@ -1176,6 +1249,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 				}
 				a.metrics.startupScriptSeconds.WithLabelValues(label).Set(dur)
 				a.scriptRunner.StartCron()
+				// Services may depend on the workspace being initialized by
+				// the startup scripts, so they are started afterwards, even
+				// if a script failed.
+				a.serviceSupervisor.Start()
 			})
 			if err != nil {
 				return xerrors.Errorf("track conn goroutine: %w", err)
@@ -1309,6 +1386,14 @@ func (a *agent) updateCommandEnv(current []string) (updated []string, err error)
 		envs[k] = os.ExpandEnv(v)
 	}
 
+	// Secrets of the workspace owner override the template, but are not
+	// expanded as their values are opaque.
+	for _, secret := range manifest.Secrets {
+		if secret.EnvName != "" {
+			envs[secret.EnvName] = secret.Value
+		}
+	}
+
 	// Agent-level environment variables should take over all. This is
 	// used for setting agent-specific variables like CODER_AGENT_TOKEN
 	// and GIT_ASKPASS.
@@ -1598,6 +1683,7 @@ func (a *agent) runDERPMapSubscriber(ctx context.Context, tClient tailnetproto.D
 		}
 		dm := tailnet.DERPMapFromProto(dmp)
 		a.client.RewriteDERPMap(dm)
+		network.SetDERPQUICPorts(tailnet.DERPQUICPortsFromProto(dmp))
 		network.SetDERPMap(dm)
 	}
 }
@@ -1750,7 +1836,7 @@ func (a *agent) HandleHTTPDebugManifest(w http.ResponseWriter, r *http.Request)
 	}
 
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(sdkManifest); err != nil {
+	if err := json.NewEncoder(w).Encode(redactManifest(*sdkManifest)); err != nil {
 		a.logger.Error(a.hardCtx, "write debug manifest", slog.Error(err))
 	}
 }
@@ -1843,6 +1929,11 @@ func (a *agent) Close() error {
 		a.logger.Error(a.hardCtx, "script runner close", slog.Error(err))
 	}
 
+	err = a.serviceSupervisor.Close()
+	if err != nil {
+		a.logger.Error(a.hardCtx, "service supervisor close", slog.Error(err))
+	}
+
 	// Wait for the graceful shutdown to complete, but don't wait forever so
 	// that we don't break user expectations.
 	go func() {
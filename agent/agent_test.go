@@ -48,9 +48,12 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/agent/agentlogsink"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/agent/reconnectingpty"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -63,6 +66,16 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// Persistent reconnecting ptys run the agent binary, which is the test
+	// binary here, as their holder.
+	if len(os.Args) > 1 && os.Args[1] == reconnectingpty.HolderCommand {
+		err := reconnectingpty.HolderCLI()
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
 }
 
@@ -1059,6 +1072,183 @@ func TestAgent_SCP(t *testing.T) {
 	assertConnectionReport(t, agentClient, proto.Connection_SSH, 0, "")
 }
 
+func TestAgent_FileTransferAPI(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	dir := t.TempDir()
+
+	// Upload and download a file.
+	data := make([]byte, 256<<10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	filePath := filepath.Join(dir, "file.bin")
+	err := conn.UploadFile(ctx, filePath, 0o600, bytes.NewReader(data))
+	require.NoError(t, err)
+	info, err := conn.StatFile(ctx, filePath)
+	require.NoError(t, err)
+	require.Equal(t, filePath, info.Path)
+	require.Equal(t, "file.bin", info.Name)
+	require.EqualValues(t, len(data), info.Size)
+	require.False(t, info.IsDir)
+	if runtime.GOOS != "windows" {
+		require.Equal(t, os.FileMode(0o600), info.Mode.Perm())
+	}
+	rc, err := conn.DownloadFile(ctx, filePath)
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	// Upload and download a directory.
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "a", "b"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a", "b", "c.txt"), []byte("hello"), 0o644))
+	var buf bytes.Buffer
+	require.NoError(t, agentfiles.Tar(&buf, src))
+	dirPath := filepath.Join(dir, "uploaded")
+	err = conn.UploadDirectory(ctx, dirPath, &buf)
+	require.NoError(t, err)
+	got, err = os.ReadFile(filepath.Join(dirPath, "a", "b", "c.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(got))
+	info, err = conn.StatFile(ctx, dirPath)
+	require.NoError(t, err)
+	require.True(t, info.IsDir)
+	rc, err = conn.DownloadFile(ctx, dirPath)
+	require.NoError(t, err)
+	downloaded := t.TempDir()
+	err = agentfiles.Untar(downloaded, rc)
+	_ = rc.Close()
+	require.NoError(t, err)
+	got, err = os.ReadFile(filepath.Join(downloaded, "a", "b", "c.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(got))
+
+	// Missing files and parent directories are reported as not found.
+	_, err = conn.StatFile(ctx, filepath.Join(dir, "missing"))
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	err = conn.UploadFile(ctx, filepath.Join(dir, "missing", "file"), 0o644, strings.NewReader("x"))
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
+func TestAgent_FileTransferAPIConnectionReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		err := conn.UploadFile(ctx, filepath.Join(t.TempDir(), "file"), 0o644, strings.NewReader("hello"))
+		require.NoError(t, err)
+		assertConnectionReport(t, agentClient, proto.Connection_SSH, 0, "")
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.BlockFileTransfer = true
+		})
+		_, err := conn.DownloadFile(ctx, filepath.Join(t.TempDir(), "file"))
+		require.Error(t, err)
+		assertConnectionReport(t, agentClient, proto.Connection_SSH, 1, "status 403")
+	})
+}
+
+func TestAgent_Exec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The commands are POSIX shell specific")
+	}
+
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	t.Run("Output", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		dir := t.TempDir()
+		var stdout, stderr bytes.Buffer
+		session, err := conn.Exec(ctx, workspacesdk.ExecRequest{
+			Command:          `echo "out $EXEC_VAR"; echo err >&2; pwd; exit 3`,
+			Env:              []string{"EXEC_VAR=value"},
+			WorkingDirectory: dir,
+		}, &stdout, &stderr)
+		require.NoError(t, err)
+		defer session.Close()
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+		require.Equal(t, "out value\n"+dir+"\n", stdout.String())
+		require.Equal(t, "err\n", stderr.String())
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		var stdout bytes.Buffer
+		session, err := conn.Exec(ctx, workspacesdk.ExecRequest{Command: "cat"}, &stdout, nil)
+		require.NoError(t, err)
+		defer session.Close()
+		_, err = session.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, session.CloseStdin())
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+		require.Equal(t, "hello", stdout.String())
+	})
+
+	t.Run("Signal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		pr, pw := io.Pipe()
+		session, err := conn.Exec(ctx, workspacesdk.ExecRequest{
+			Command: `trap 'echo trapped; exit 7' TERM; echo ready; while true; do sleep 0.1; done`,
+		}, pw, nil)
+		require.NoError(t, err)
+		defer session.Close()
+		ready := make([]byte, len("ready\n"))
+		_, err = io.ReadFull(pr, ready)
+		require.NoError(t, err)
+		require.NoError(t, session.Signal(ctx, "TERM"))
+		rest := make(chan []byte, 1)
+		go func() {
+			b, _ := io.ReadAll(pr)
+			rest <- b
+		}()
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 7, code)
+		_ = pw.Close()
+		require.Equal(t, "trapped\n", string(testutil.RequireReceive(ctx, t, rest)))
+	})
+
+	t.Run("ConnectionReport", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		session, err := conn.Exec(ctx, workspacesdk.ExecRequest{Command: "exit 5"}, nil, nil)
+		require.NoError(t, err)
+		defer session.Close()
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 5, code)
+		assertConnectionReport(t, agentClient, proto.Connection_SSH, 5, "")
+	})
+}
+
 func TestAgent_FileTransferBlocked(t *testing.T) {
 	t.Parallel()
 
@@ -1156,6 +1346,25 @@ func TestAgent_FileTransferBlocked(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("HTTP API", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.BlockFileTransfer = true
+		})
+		tempFile := filepath.Join(t.TempDir(), "file")
+		err := conn.UploadFile(ctx, tempFile, 0o644, strings.NewReader("hello world"))
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+		require.NoFileExists(t, tempFile)
+		_, err = conn.DownloadFile(ctx, tempFile)
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
 }
 
 func TestAgent_EnvironmentVariables(t *testing.T) {
@@ -1198,6 +1407,47 @@ func TestAgent_EnvironmentVariableExpansion(t *testing.T) {
 	require.Equal(t, expect, strings.TrimSpace(string(output)))
 }
 
+func TestAgent_Secrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	filePath := filepath.Join(t.TempDir(), "secrets", "token")
+	//nolint:dogsled
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{
+		EnvironmentVariables: map[string]string{
+			"OPENAI_API_KEY": "from-template",
+		},
+		Secrets: []agentsdk.WorkspaceAgentSecret{
+			{Name: "openai", EnvName: "OPENAI_API_KEY", Value: "sk-123"},
+			{Name: "token", FilePath: filePath, Value: "file-secret"},
+		},
+	}, 0)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+
+	command := "sh -c 'echo $OPENAI_API_KEY'"
+	if runtime.GOOS == "windows" {
+		command = "cmd.exe /c echo %OPENAI_API_KEY%"
+	}
+	output, err := session.Output(command)
+	require.NoError(t, err)
+	// Secrets take precedence over the environment of the template.
+	require.Equal(t, "sk-123", strings.TrimSpace(string(output)))
+
+	// The file is written before the manifest that sessions depend on is
+	// applied.
+	content, err := afero.ReadFile(fs, filePath)
+	require.NoError(t, err)
+	require.Equal(t, "file-secret", string(content))
+	info, err := fs.Stat(filePath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
 func TestAgent_CoderEnvVars(t *testing.T) {
 	t.Parallel()
 
@@ -1770,6 +2020,189 @@ func TestAgent_Startup(t *testing.T) {
 	})
 }
 
+func TestAgent_Services(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the service uses a POSIX shell command")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	service := codersdk.WorkspaceAgentService{
+		ID:            uuid.New(),
+		LogSourceID:   uuid.New(),
+		Name:          "sleeper",
+		Command:       "sleep 300",
+		RestartPolicy: codersdk.WorkspaceAgentServiceRestartPolicyAlways,
+	}
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		Services: []codersdk.WorkspaceAgentService{service},
+	}, 0)
+
+	// The service is started since there are no startup scripts to wait for.
+	require.Eventually(t, func() bool {
+		for _, update := range client.GetServiceUpdates() {
+			if bytes.Equal(update.Id, service.ID[:]) && update.Status == proto.ServiceStatus_RUNNING {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	resp, err := conn.Services(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.Services, 1)
+	require.Equal(t, "sleeper", resp.Services[0].Name)
+	require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, resp.Services[0].Status)
+
+	_, err = conn.RestartService(ctx, "sleeper")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		for _, update := range client.GetServiceUpdates() {
+			if update.Restarts == 1 && update.Status == proto.ServiceStatus_RUNNING {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	_, err = conn.RestartService(ctx, "unknown")
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
+func TestAgent_Processes(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("listening ports of processes are only inspected on Linux in this test")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port) //nolint:gosec
+
+	cmd := exec.Command("sleep", "300")
+	require.NoError(t, cmd.Start())
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	resp, err := conn.Processes(ctx)
+	require.NoError(t, err)
+	var self, sleep *workspacesdk.AgentProcess
+	for i, p := range resp.Processes {
+		switch int(p.PID) {
+		case os.Getpid():
+			self = &resp.Processes[i]
+		case cmd.Process.Pid:
+			sleep = &resp.Processes[i]
+		}
+	}
+	require.NotNil(t, self, "the test process is listed")
+	require.Contains(t, self.ListeningPorts, port)
+	require.NotZero(t, self.MemoryRSS)
+	require.NotNil(t, sleep, "the child process is listed")
+	require.Equal(t, "sleep 300", sleep.Cmdline)
+	require.EqualValues(t, os.Getpid(), sleep.PPID)
+
+	// The agent itself can't be signaled.
+	err = conn.SignalProcess(ctx, int32(os.Getpid()), workspacesdk.AgentProcessSignalTERM) //nolint:gosec
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	err = conn.SignalProcess(ctx, int32(cmd.Process.Pid), "STOP") //nolint:gosec
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	require.NoError(t, conn.SignalProcess(ctx, int32(cmd.Process.Pid), workspacesdk.AgentProcessSignalTERM)) //nolint:gosec
+	err = testutil.TryReceive(ctx, t, exited)
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, "signal: terminated", exitErr.Error())
+
+	err = conn.SignalProcess(ctx, int32(cmd.Process.Pid), workspacesdk.AgentProcessSignalTERM) //nolint:gosec
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
+func TestAgent_LogShipper(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the script uses a POSIX shell command")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	path := filepath.Join(t.TempDir(), "agent-logs.jsonl")
+	shipper := agentlogsink.New(agentlogsink.Options{
+		Logger:  testutil.Logger(t),
+		Sources: []agentlogsink.Source{agentlogsink.SourceScript, agentlogsink.SourceConnection},
+	}, agentlogsink.NewFileSink(path, 1, 1))
+	t.Cleanup(func() {
+		assert.NoError(t, shipper.Close())
+	})
+
+	logSourceID := uuid.New()
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		WorkspaceName: "myworkspace",
+		Scripts: []codersdk.WorkspaceAgentScript{{
+			ID:          uuid.New(),
+			LogSourceID: logSourceID,
+			DisplayName: "hello",
+			Script:      "echo hello-from-script",
+			RunOnStart:  true,
+		}},
+	}, 0, func(_ *agenttest.Client, o *agent.Options) {
+		o.LogShipper = shipper
+	})
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	require.NoError(t, session.Run("true"))
+	_ = session.Close()
+	_ = sshClient.Close()
+
+	type line struct {
+		Source     string            `json:"source"`
+		Message    string            `json:"message"`
+		Attributes map[string]string `json:"attributes"`
+		Resource   map[string]string `json:"resource"`
+	}
+	var script, connect *line
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		for _, raw := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var l line
+			if json.Unmarshal([]byte(raw), &l) != nil {
+				continue
+			}
+			switch {
+			case l.Source == "script" && l.Message == "hello-from-script":
+				script = &l
+			case l.Source == "connection" && l.Attributes["coder.connection.action"] == "connect":
+				connect = &l
+			}
+		}
+		return script != nil && connect != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	require.Equal(t, "hello", script.Attributes["coder.log_source.name"])
+	require.Equal(t, logSourceID.String(), script.Attributes["coder.log_source.id"])
+	require.Equal(t, "myworkspace", script.Resource["coder.workspace.name"])
+	require.Equal(t, "ssh", connect.Attributes["coder.connection.type"])
+}
+
 //nolint:paralleltest // This test sets an environment variable.
 func TestAgent_ReconnectingPTY(t *testing.T) {
 	if runtime.GOOS == "windows" {
@@ -1919,6 +2352,84 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYPersistent(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	tempDir := t.TempDir()
+	withTempDir := func(_ *agenttest.Client, o *agent.Options) {
+		o.TempDir = tempDir
+	}
+	persistent := workspacesdk.AgentReconnectingPTYInitWithBackendType("persistent")
+	id := uuid.New()
+
+	//nolint:dogsled
+	conn, _, _, _, agnt := setupAgent(t, agentsdk.Manifest{}, 0, withTempDir)
+	netConn, err := conn.ReconnectingPTY(ctx, id, 80, 80, "bash --norc", persistent)
+	require.NoError(t, err)
+	defer netConn.Close()
+	tr := testutil.NewTerminalReader(t, netConn)
+	require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+		return strings.Contains(line, "$ ") || strings.Contains(line, "# ")
+	}), "find prompt")
+	data, err := json.Marshal(workspacesdk.ReconnectingPTYRequest{
+		Data: "echo persisted-$((1+1))\r",
+	})
+	require.NoError(t, err)
+	_, err = netConn.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+		return strings.Contains(line, "persisted-2")
+	}), "find echo output")
+
+	sessions, err := conn.ReconnectingPTYSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+	require.Equal(t, id, sessions.Sessions[0].ID)
+	require.Equal(t, "persistent", sessions.Sessions[0].BackendType)
+	require.Equal(t, "bash --norc", sessions.Sessions[0].Command)
+	require.EqualValues(t, 1, sessions.Sessions[0].Connections)
+
+	// The holder outlives the agent, and a new agent adopts the pty.
+	_ = netConn.Close()
+	require.NoError(t, agnt.Close())
+	//nolint:dogsled
+	conn, _, _, _, _ = setupAgent(t, agentsdk.Manifest{}, 0, withTempDir)
+	require.True(t, conn.AwaitReachable(ctx))
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		sessions, err = conn.ReconnectingPTYSessions(ctx)
+		return err == nil && len(sessions.Sessions) == 1
+	}, testutil.IntervalFast)
+	require.Equal(t, id, sessions.Sessions[0].ID)
+
+	// The scrollback is replayed.
+	netConn, err = conn.ReconnectingPTY(ctx, id, 80, 80, "bash --norc", persistent)
+	require.NoError(t, err)
+	defer netConn.Close()
+	tr = testutil.NewTerminalReader(t, netConn)
+	require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+		return strings.Contains(line, "persisted-2")
+	}), "find echo output")
+
+	err = conn.KillReconnectingPTY(ctx, id)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.ReadUntil(ctx, nil), io.EOF)
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		sessions, err = conn.ReconnectingPTYSessions(ctx)
+		return err == nil && len(sessions.Sessions) == 0
+	}, testutil.IntervalFast)
+	testutil.Eventually(ctx, t, func(context.Context) bool {
+		_, err := os.Stat(filepath.Join(tempDir, "coder-rpty", id.String()))
+		return errors.Is(err, os.ErrNotExist)
+	}, testutil.IntervalFast)
+
+	err = conn.KillReconnectingPTY(ctx, id)
+	require.ErrorContains(t, err, "not found")
+}
+
 // This tests end-to-end functionality of connecting to a running container
 // and executing a command. It creates a real Docker container and runs a
 // command. As such, it does not run by default in CI.
@@ -1986,6 +2497,100 @@ func TestAgent_ReconnectingPTYContainer(t *testing.T) {
 	require.ErrorIs(t, tr.ReadUntil(ctx, nil), io.EOF)
 }
 
+// This tests end-to-end functionality of transferring files to and from a
+// running container over SSH, using both exec (as scp and rsync do) and SFTP.
+// It creates a real Docker container and installs the OpenSSH SFTP server in
+// it. As such, it does not run by default in CI.
+// You can run it manually as follows:
+//
+// CODER_TEST_USE_DOCKER=1 go test -count=1 ./agent -run TestAgent_FileTransferContainer
+func TestAgent_FileTransferContainer(t *testing.T) {
+	t.Parallel()
+	if os.Getenv("CODER_TEST_USE_DOCKER") != "1" {
+		t.Skip("Set CODER_TEST_USE_DOCKER=1 to run this test")
+	}
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err, "Could not connect to docker")
+	ct, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "alpine",
+		Tag:        "latest",
+		Cmd:        []string{"sh", "-c", "apk add --no-cache openssh-sftp-server && adduser -D coder && sleep infinity"},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	require.NoError(t, err, "Could not start container")
+	defer func() {
+		err := pool.Purge(ct)
+		require.NoError(t, err, "Could not stop container")
+	}()
+	// Wait for the SFTP server to be installed and the user to be created.
+	require.Eventually(t, func() bool {
+		code, err := ct.Exec([]string{"test", "-d", "/home/coder"}, dockertest.ExecOptions{})
+		return err == nil && code == 0
+	}, testutil.WaitLong, testutil.IntervalSlow, "Container did not start in time")
+
+	// nolint: dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+		o.ExperimentalDevcontainersEnabled = true
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	newSession := func() *ssh.Session {
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		require.NoError(t, session.Setenv(agentssh.ContainerEnvironmentVariable, ct.Container.ID))
+		require.NoError(t, session.Setenv(agentssh.ContainerUserEnvironmentVariable, "coder"))
+		return session
+	}
+
+	// Binary data must survive the round trip, which it would not if a TTY
+	// was allocated in the container.
+	data := make([]byte, 64<<10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	session := newSession()
+	session.Stdin = bytes.NewReader(data)
+	err = session.Run("cat > upload.bin")
+	require.NoError(t, err)
+	session = newSession()
+	got, err := session.Output("cat /home/coder/upload.bin")
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	session = newSession()
+	w, err := session.StdinPipe()
+	require.NoError(t, err)
+	r, err := session.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, session.RequestSubsystem("sftp"))
+	client, err := sftp.NewClientPipe(r, w)
+	require.NoError(t, err)
+	defer client.Close()
+	wd, err := client.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, "/home/coder", wd, "SFTP should start in the home directory of the container user")
+	file, err := client.Open("upload.bin")
+	require.NoError(t, err)
+	got, err = io.ReadAll(file)
+	require.NoError(t, err)
+	_ = file.Close()
+	require.Equal(t, data, got)
+	file, err = client.Create("sftp.txt")
+	require.NoError(t, err)
+	_ = file.Close()
+
+	// Files created over SFTP are owned by the container user.
+	session = newSession()
+	owner, err := session.Output("stat -c %U /home/coder/sftp.txt")
+	require.NoError(t, err)
+	require.Equal(t, "coder", strings.TrimSpace(string(owner)))
+}
+
 // This tests end-to-end functionality of auto-starting a devcontainer.
 // It runs "devcontainer up" which creates a real Docker container. As
 // such, it does not run by default in CI.
@@ -2624,6 +3229,9 @@ func TestAgent_DebugServer(t *testing.T) {
 	//nolint:dogsled
 	conn, _, _, _, agnt := setupAgent(t, agentsdk.Manifest{
 		DERPMap: derpMap,
+		Secrets: []agentsdk.WorkspaceAgentSecret{
+			{Name: "openai", EnvName: "OPENAI_API_KEY", Value: "sk-123"},
+		},
 	}, 0, func(c *agenttest.Client, o *agent.Options) {
 		o.ExchangeToken = func(context.Context) (string, error) {
 			return "token", nil
@@ -2726,6 +3334,10 @@ func TestAgent_DebugServer(t *testing.T) {
 		var v agentsdk.Manifest
 		require.NoError(t, json.NewDecoder(res.Body).Decode(&v))
 		require.NotNil(t, v)
+		// Secret values must never be exposed by the debug server.
+		require.Len(t, v.Secrets, 1)
+		require.Equal(t, "OPENAI_API_KEY", v.Secrets[0].EnvName)
+		require.NotEqual(t, "sk-123", v.Secrets[0].Value)
 	})
 
 	t.Run("Logs", func(t *testing.T) {
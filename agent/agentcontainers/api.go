@@ -43,6 +43,7 @@ type API struct {
 	logger            slog.Logger
 	watcher           watcher.Watcher
 	execer            agentexec.Execer
+	runtime           Runtime
 	cl                Lister
 	dccli             DevcontainerCLI
 	clock             quartz.Clock
@@ -80,8 +81,17 @@ func WithExecer(execer agentexec.Execer) Option {
 	}
 }
 
+// WithRuntime sets the container runtime used to list containers and to
+// recreate devcontainers. If not set, the runtime is detected.
+func WithRuntime(runtime Runtime) Option {
+	return func(api *API) {
+		api.runtime = runtime
+	}
+}
+
 // WithLister sets the agentcontainers.Lister implementation to use.
-// The default implementation uses the Docker CLI to list containers.
+// The default implementation uses the CLI of the container runtime to list
+// containers.
 func WithLister(cl Lister) Option {
 	return func(api *API) {
 		api.cl = cl
@@ -186,8 +196,12 @@ func NewAPI(logger slog.Logger, options ...Option) *API {
 	for _, opt := range options {
 		opt(api)
 	}
+	if api.runtime == "" && (api.cl == nil || api.dccli == nil) {
+		api.runtime = DetectRuntime(ctx, api.execer)
+		logger.Debug(ctx, "detected container runtime", slog.F("runtime", api.runtime))
+	}
 	if api.cl == nil {
-		api.cl = NewDocker(api.execer)
+		api.cl = NewLister(api.execer, api.runtime)
 	}
 	if api.dccli == nil {
 		api.dccli = NewDevcontainerCLI(logger.Named("devcontainer-cli"), api.execer)
@@ -682,7 +696,11 @@ func (api *API) recreateDevcontainer(dc codersdk.WorkspaceAgentDevcontainer, con
 
 	logger.Debug(ctx, "starting devcontainer recreation")
 
-	_, err = api.dccli.Up(ctx, dc.WorkspaceFolder, configPath, WithOutput(infoW, errW), WithRemoveExistingContainer())
+	upOpts := []DevcontainerCLIUpOptions{WithOutput(infoW, errW), WithRemoveExistingContainer()}
+	if api.runtime != "" && api.runtime != RuntimeDocker {
+		upOpts = append(upOpts, WithDockerPath(api.runtime.CLI()))
+	}
+	_, err = api.dccli.Up(ctx, dc.WorkspaceFolder, configPath, upOpts...)
 	if err != nil {
 		// No need to log if the API is closing (context canceled), as this
 		// is expected behavior when the API is shutting down.
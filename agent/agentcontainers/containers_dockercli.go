@@ -25,24 +25,28 @@ import (
 )
 
 // DockerEnvInfoer is an implementation of agentssh.EnvInfoer that returns
-// information about a container.
+// information about a container. It works with any container runtime that
+// has a Docker-compatible CLI.
 type DockerEnvInfoer struct {
 	usershell.SystemEnvInfo
+	runtime   Runtime
 	container string
 	user      *user.User
 	userShell string
 	env       []string
+	noTTY     bool
 }
 
-// EnvInfo returns information about the environment of a container.
-func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerUser string) (*DockerEnvInfoer, error) {
+// EnvInfo returns information about the environment of a container managed
+// by the given runtime.
+func EnvInfo(ctx context.Context, execer agentexec.Execer, runtime Runtime, container, containerUser string) (*DockerEnvInfoer, error) {
 	var dei DockerEnvInfoer
+	dei.runtime = runtime
 	dei.container = container
 
 	if containerUser == "" {
 		// Get the "default" user of the container if no user is specified.
-		// TODO: handle different container runtimes.
-		cmd, args := wrapDockerExec(container, "", "whoami")
+		cmd, args := wrapDockerExec(runtime, container, "", "whoami")
 		stdout, stderr, err := run(ctx, execer, cmd, args...)
 		if err != nil {
 			return nil, xerrors.Errorf("get container user: run whoami: %w: %s", err, stderr)
@@ -54,7 +58,7 @@ func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerU
 	}
 	// Now that we know the username, get the required info from the container.
 	// We can't assume the presence of `getent` so we'll just have to sniff /etc/passwd.
-	cmd, args := wrapDockerExec(container, containerUser, "cat", "/etc/passwd")
+	cmd, args := wrapDockerExec(runtime, container, containerUser, "cat", "/etc/passwd")
 	stdout, stderr, err := run(ctx, execer, cmd, args...)
 	if err != nil {
 		return nil, xerrors.Errorf("get container user: read /etc/passwd: %w: %q", err, stderr)
@@ -104,7 +108,7 @@ func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerU
 	// We need to inspect the container labels for remoteEnv and append these to
 	// the resulting docker exec command.
 	// ref: https://code.visualstudio.com/docs/devcontainers/attach-container
-	env, err := devcontainerEnv(ctx, execer, container)
+	env, err := devcontainerEnv(ctx, execer, runtime, container)
 	if err != nil { // best effort.
 		return nil, xerrors.Errorf("read devcontainer remoteEnv: %w", err)
 	}
@@ -123,21 +127,33 @@ func (dei *DockerEnvInfoer) Shell(string) (string, error) {
 	return dei.userShell, nil
 }
 
+// WithoutTTY returns a copy of the DockerEnvInfoer that runs commands without
+// allocating a TTY. This is required for commands that are not run in a PTY,
+// such as scp or rsync, as docker refuses to allocate a TTY when stdin is not
+// a terminal and a TTY would mangle binary data.
+func (dei *DockerEnvInfoer) WithoutTTY() *DockerEnvInfoer {
+	c := *dei
+	c.noTTY = true
+	return &c
+}
+
 func (dei *DockerEnvInfoer) ModifyCommand(cmd string, args ...string) (string, []string) {
-	// Wrap the command with `docker exec` and run it as the container user.
+	// Wrap the command with `docker exec` (or the equivalent for the
+	// runtime) and run it as the container user.
 	// There is some additional munging here regarding the container user and environment.
-	dockerArgs := []string{
-		"exec",
+	dockerArgs := []string{"exec", "--interactive"}
+	if !dei.noTTY {
 		// The assumption is that this command will be a shell command, so allocate a PTY.
-		"--interactive",
-		"--tty",
+		dockerArgs = append(dockerArgs, "--tty")
+	}
+	dockerArgs = append(dockerArgs,
 		// Run the command as the user in the container.
 		"--user",
 		dei.user.Username,
 		// Set the working directory to the user's home directory as a sane default.
 		"--workdir",
 		dei.user.HomeDir,
-	}
+	)
 
 	// Append the environment variables from the container.
 	for _, e := range dei.env {
@@ -146,13 +162,29 @@ func (dei *DockerEnvInfoer) ModifyCommand(cmd string, args ...string) (string, [
 
 	// Append the container name and the command.
 	dockerArgs = append(dockerArgs, dei.container, cmd)
-	return "docker", append(dockerArgs, args...)
+	return dei.runtime.CLI(), append(dockerArgs, args...)
+}
+
+// sftpServerScript starts the OpenSSH SFTP server in a container. Its location
+// differs between distributions, so the common ones are tried in turn.
+const sftpServerScript = `for p in /usr/lib/openssh/sftp-server /usr/libexec/openssh/sftp-server /usr/lib/ssh/sftp-server /usr/libexec/sftp-server "$(command -v sftp-server)"; do
+	if [ -x "$p" ]; then exec "$p"; fi
+done
+echo "sftp-server not found, install the OpenSSH SFTP server in the container to use SFTP" >&2
+exit 127`
+
+// SFTPCommand returns a command that serves the SFTP protocol on stdin and
+// stdout from inside the container, so that file operations are performed as
+// the container user. It requires the OpenSSH SFTP server to be installed in
+// the container.
+func (dei *DockerEnvInfoer) SFTPCommand() (string, []string) {
+	return dei.WithoutTTY().ModifyCommand("/bin/sh", "-c", sftpServerScript)
 }
 
 // devcontainerEnv is a helper function that inspects the container labels to
 // find the required environment variables for running a command in the container.
-func devcontainerEnv(ctx context.Context, execer agentexec.Execer, container string) ([]string, error) {
-	stdout, stderr, err := runDockerInspect(ctx, execer, container)
+func devcontainerEnv(ctx context.Context, execer agentexec.Execer, runtime Runtime, container string) ([]string, error) {
+	stdout, stderr, err := runDockerInspect(ctx, execer, runtime, container)
 	if err != nil {
 		return nil, xerrors.Errorf("inspect container: %w: %q", err, stderr)
 	}
@@ -204,13 +236,13 @@ func devcontainerEnv(ctx context.Context, execer agentexec.Execer, container str
 // with a docker exec command that runs as the given user in the given
 // container. This is used to fetch information about a container prior to
 // running the actual command.
-func wrapDockerExec(containerName, userName, cmd string, args ...string) (string, []string) {
+func wrapDockerExec(runtime Runtime, containerName, userName, cmd string, args ...string) (string, []string) {
 	dockerArgs := []string{"exec", "--interactive"}
 	if userName != "" {
 		dockerArgs = append(dockerArgs, "--user", userName)
 	}
 	dockerArgs = append(dockerArgs, containerName, cmd)
-	return "docker", append(dockerArgs, args...)
+	return runtime.CLI(), append(dockerArgs, args...)
 }
 
 // Helper function to run a command and return its stdout and stderr.
@@ -228,23 +260,44 @@ func run(ctx context.Context, execer agentexec.Execer, cmd string, args ...strin
 	return stdout, stderr, err
 }
 
-// DockerCLILister is a ContainerLister that lists containers using the docker CLI
+// DockerCLILister is a ContainerLister that lists containers using the docker
+// CLI, or a Docker-compatible CLI such as podman or nerdctl.
 type DockerCLILister struct {
-	execer agentexec.Execer
+	execer  agentexec.Execer
+	runtime Runtime
 }
 
 var _ Lister = &DockerCLILister{}
 
-func NewDocker(execer agentexec.Execer) Lister {
+// NewLister returns a Lister that lists containers using the CLI of the given
+// runtime.
+func NewLister(execer agentexec.Execer, runtime Runtime) Lister {
 	return &DockerCLILister{
-		execer: agentexec.DefaultExecer,
+		execer:  execer,
+		runtime: runtime,
 	}
 }
 
+// NewDocker returns a Lister that lists containers using the docker CLI.
+func NewDocker(execer agentexec.Execer) Lister {
+	return NewLister(execer, RuntimeDocker)
+}
+
+// NewPodman returns a Lister that lists containers using the podman CLI.
+func NewPodman(execer agentexec.Execer) Lister {
+	return NewLister(execer, RuntimePodman)
+}
+
+// NewNerdctl returns a Lister that lists containerd containers using the
+// nerdctl CLI.
+func NewNerdctl(execer agentexec.Execer) Lister {
+	return NewLister(execer, RuntimeNerdctl)
+}
+
 func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentListContainersResponse, error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 	// List all container IDs, one per line, with no truncation
-	cmd := dcl.execer.CommandContext(ctx, "docker", "ps", "--all", "--quiet", "--no-trunc")
+	cmd := dcl.execer.CommandContext(ctx, dcl.runtime.CLI(), "ps", "--all", "--quiet", "--no-trunc")
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
 	if err := cmd.Run(); err != nil {
@@ -252,7 +305,7 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 		// - docker not installed
 		// - docker not running
 		// - no permissions to talk to docker
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run docker ps: %w: %q", err, strings.TrimSpace(stderrBuf.String()))
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run %s ps: %w: %q", dcl.runtime.CLI(), err, strings.TrimSpace(stderrBuf.String()))
 	}
 
 	ids := make([]string, 0)
@@ -265,7 +318,7 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 		ids = append(ids, tmp)
 	}
 	if err := scanner.Err(); err != nil {
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("scan docker ps output: %w", err)
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("scan %s ps output: %w", dcl.runtime.CLI(), err)
 	}
 
 	res := codersdk.WorkspaceAgentListContainersResponse{
@@ -288,9 +341,9 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 	// will still contain valid JSON. We will just end up missing
 	// information about the removed container. We could potentially
 	// log this error, but I'm not sure it's worth it.
-	dockerInspectStdout, dockerInspectStderr, err := runDockerInspect(ctx, dcl.execer, ids...)
+	dockerInspectStdout, dockerInspectStderr, err := runDockerInspect(ctx, dcl.execer, dcl.runtime, ids...)
 	if err != nil {
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run docker inspect: %w: %s", err, dockerInspectStderr)
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run %s inspect: %w: %s", dcl.runtime.CLI(), err, dockerInspectStderr)
 	}
 
 	if len(dockerInspectStderr) > 0 {
@@ -310,16 +363,19 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 // runDockerInspect is a helper function that runs `docker inspect` on the given
 // container IDs and returns the parsed output.
 // The stderr output is also returned for logging purposes.
-func runDockerInspect(ctx context.Context, execer agentexec.Execer, ids ...string) (stdout, stderr []byte, err error) {
+func runDockerInspect(ctx context.Context, execer agentexec.Execer, runtime Runtime, ids ...string) (stdout, stderr []byte, err error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
-	cmd := execer.CommandContext(ctx, "docker", append([]string{"inspect"}, ids...)...)
+	cmd := execer.CommandContext(ctx, runtime.CLI(), append([]string{"inspect"}, ids...)...)
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
 	err = cmd.Run()
 	stdout = bytes.TrimSpace(stdoutBuf.Bytes())
 	stderr = bytes.TrimSpace(stderrBuf.Bytes())
 	if err != nil {
-		if bytes.Contains(stderr, []byte("No such object:")) {
+		// Docker reports "No such object", podman "no such object" and
+		// nerdctl "no such container".
+		lowerStderr := bytes.ToLower(stderr)
+		if bytes.Contains(lowerStderr, []byte("no such object")) || bytes.Contains(lowerStderr, []byte("no such container")) {
 			// This can happen if a container is deleted between the time we check for its existence and the time we inspect it.
 			return stdout, stderr, nil
 		}
@@ -333,6 +389,7 @@ func runDockerInspect(ctx context.Context, execer agentexec.Execer, ids ...strin
 type dockerInspect struct {
 	ID              string                       `json:"Id"`
 	Created         time.Time                    `json:"Created"`
+	Image           string                       `json:"Image"`
 	Config          dockerInspectConfig          `json:"Config"`
 	Name            string                       `json:"Name"`
 	Mounts          []dockerInspectMount         `json:"Mounts"`
@@ -405,9 +462,16 @@ func convertDockerInspect(raw []byte) ([]codersdk.WorkspaceAgentContainer, []str
 	hostPortContainers := make(map[int][]string)
 
 	for _, in := range ins {
+		// Docker and podman report the image name in the config. nerdctl
+		// only reports it at the top level, where docker reports the image
+		// ID instead.
+		if in.Config.Image == "" {
+			in.Config.Image = in.Image
+		}
 		out := codersdk.WorkspaceAgentContainer{
 			CreatedAt: in.Created,
-			// Remove the leading slash from the container name
+			// Remove the leading slash from the container name, podman
+			// and nerdctl do not add one.
 			FriendlyName: strings.TrimPrefix(in.Name, "/"),
 			ID:           in.ID,
 			Image:        in.Config.Image,
@@ -511,6 +575,11 @@ func convertDockerPort(in string) (uint16, string, error) {
 
 // convenience function to check if an IP address is loopback or unspecified
 func isLoopbackOrUnspecified(ips string) bool {
+	if ips == "" {
+		// Podman omits the host IP when a port is published on all
+		// interfaces.
+		return true
+	}
 	nip := net.ParseIP(ips)
 	if nip == nil {
 		return false // technically correct, I suppose
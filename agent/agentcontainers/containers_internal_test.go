@@ -2,6 +2,7 @@ package agentcontainers
 
 import (
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
 	"time"
@@ -17,6 +18,7 @@ func TestWrapDockerExec(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name          string
+		runtime       Runtime
 		containerUser string
 		cmdArgs       []string
 		wantCmd       []string
@@ -39,18 +41,68 @@ func TestWrapDockerExec(t *testing.T) {
 			cmdArgs:       []string{"my-cmd"},
 			wantCmd:       []string{"docker", "exec", "--interactive", "my-container", "my-cmd"},
 		},
+		{
+			name:          "podman",
+			runtime:       RuntimePodman,
+			containerUser: "my-user",
+			cmdArgs:       []string{"my-cmd"},
+			wantCmd:       []string{"podman", "exec", "--interactive", "--user", "my-user", "my-container", "my-cmd"},
+		},
+		{
+			name:          "nerdctl",
+			runtime:       RuntimeNerdctl,
+			containerUser: "my-user",
+			cmdArgs:       []string{"my-cmd"},
+			wantCmd:       []string{"nerdctl", "exec", "--interactive", "--user", "my-user", "my-container", "my-cmd"},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt // appease the linter even though this isn't needed anymore
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			actualCmd, actualArgs := wrapDockerExec("my-container", tt.containerUser, tt.cmdArgs[0], tt.cmdArgs[1:]...)
+			actualCmd, actualArgs := wrapDockerExec(tt.runtime, "my-container", tt.containerUser, tt.cmdArgs[0], tt.cmdArgs[1:]...)
 			assert.Equal(t, tt.wantCmd[0], actualCmd)
 			assert.Equal(t, tt.wantCmd[1:], actualArgs)
 		})
 	}
 }
 
+func TestDockerEnvInfoerModifyCommand(t *testing.T) {
+	t.Parallel()
+
+	dei := &DockerEnvInfoer{
+		container: "my-container",
+		user:      &user.User{Username: "my-user", HomeDir: "/home/my-user"},
+		env:       []string{"FOO=bar"},
+	}
+
+	cmd, args := dei.ModifyCommand("my-cmd", "arg1")
+	assert.Equal(t, "docker", cmd)
+	assert.Equal(t, []string{"exec", "--interactive", "--tty", "--user", "my-user", "--workdir", "/home/my-user", "--env", "FOO=bar", "my-container", "my-cmd", "arg1"}, args)
+
+	// Commands run without a PTY must not allocate a TTY in the container.
+	cmd, args = dei.WithoutTTY().ModifyCommand("my-cmd", "arg1")
+	assert.Equal(t, "docker", cmd)
+	assert.Equal(t, []string{"exec", "--interactive", "--user", "my-user", "--workdir", "/home/my-user", "--env", "FOO=bar", "my-container", "my-cmd", "arg1"}, args)
+
+	// The original is not modified.
+	_, args = dei.ModifyCommand("my-cmd")
+	assert.Contains(t, args, "--tty")
+
+	cmd, args = dei.SFTPCommand()
+	assert.Equal(t, "docker", cmd)
+	assert.NotContains(t, args, "--tty")
+	assert.Equal(t, []string{"--user", "my-user"}, args[2:4])
+	assert.Equal(t, []string{"my-container", "/bin/sh", "-c", sftpServerScript}, args[len(args)-4:])
+
+	// Commands are run with the CLI of the container runtime.
+	dei.runtime = RuntimePodman
+	cmd, _ = dei.ModifyCommand("my-cmd")
+	assert.Equal(t, "podman", cmd)
+	cmd, _ = dei.SFTPCommand()
+	assert.Equal(t, "podman", cmd)
+}
+
 func TestConvertDockerPort(t *testing.T) {
 	t.Parallel()
 
@@ -391,6 +443,69 @@ func TestConvertDockerInspect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "podman_simple",
+			expect: []codersdk.WorkspaceAgentContainer{
+				{
+					CreatedAt:    time.Date(2025, 4, 2, 9, 14, 23, 461732051, time.UTC),
+					ID:           "9c4a3fbd7e0d6fc5e6f1b0a4c3d2e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2",
+					FriendlyName: "upbeat_carson",
+					Image:        "docker.io/library/debian:bookworm",
+					Labels: map[string]string{
+						"devcontainer.config_file":  "/home/coder/src/.devcontainer/devcontainer.json",
+						"devcontainer.local_folder": "/home/coder/src",
+					},
+					Running: true,
+					Status:  "running",
+					Ports: []codersdk.WorkspaceAgentContainerPort{
+						{
+							Network:  "tcp",
+							Port:     8080,
+							HostPort: 8080,
+							HostIP:   "",
+						},
+					},
+					Volumes: map[string]string{
+						"/home/coder/src": "/workspaces/src",
+					},
+				},
+			},
+		},
+		{
+			name: "nerdctl_simple",
+			expect: []codersdk.WorkspaceAgentContainer{
+				{
+					CreatedAt:    time.Date(2025, 4, 2, 10, 12, 1, 563425178, time.UTC),
+					ID:           "2f9d1c7b4e8a3d6f0b5c9e2a7d4f1b8c3e6a9d2f5b8c1e4a7d0f3b6c9e2a5d8f",
+					FriendlyName: "debian-2f9d1",
+					Image:        "docker.io/library/debian:bookworm",
+					Labels: map[string]string{
+						"foo":                                    "bar",
+						"io.containerd.image.config.stop-signal": "SIGTERM",
+						"nerdctl/hostname":                       "2f9d1c7b4e8a",
+						"nerdctl/name":                           "debian-2f9d1",
+						"nerdctl/namespace":                      "default",
+						"nerdctl/networks":                       `["bridge"]`,
+						"nerdctl/platform":                       "linux/amd64",
+						"nerdctl/ports":                          `[{"HostPort":8080,"ContainerPort":8080,"Protocol":"tcp","HostIP":"127.0.0.1"}]`,
+						"nerdctl/state-dir":                      "/var/lib/nerdctl/1935db59/containers/default/2f9d1c7b4e8a3d6f0b5c9e2a7d4f1b8c3e6a9d2f5b8c1e4a7d0f3b6c9e2a5d8f",
+					},
+					Running: false,
+					Status:  "exited with code 137",
+					Ports: []codersdk.WorkspaceAgentContainerPort{
+						{
+							Network:  "tcp",
+							Port:     8080,
+							HostPort: 8080,
+							HostIP:   "127.0.0.1",
+						},
+					},
+					Volumes: map[string]string{
+						"/tmp/test/a": "/var/coder/a",
+					},
+				},
+			},
+		},
 	} {
 		// nolint:paralleltest // variable recapture no longer required
 		t.Run(tt.name, func(t *testing.T) {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -104,7 +103,7 @@ func TestIntegrationDocker(t *testing.T) {
 			}
 			// Test that EnvInfo is able to correctly modify a command to be
 			// executed inside the container.
-			dei, err := agentcontainers.EnvInfo(ctx, agentexec.DefaultExecer, ct.Container.ID, "")
+			dei, err := agentcontainers.EnvInfo(ctx, agentexec.DefaultExecer, agentcontainers.RuntimeDocker, ct.Container.ID, "")
 			require.NoError(t, err, "Expected no error from DockerEnvInfo()")
 			ptyWrappedCmd, ptyWrappedArgs := dei.ModifyCommand("/bin/sh", "--norc")
 			ptyCmd, ptyPs, err := pty.Start(agentexec.DefaultExecer.PTYCommandContext(ctx, ptyWrappedCmd, ptyWrappedArgs...))
@@ -246,7 +245,7 @@ func TestDockerEnvInfoer(t *testing.T) {
 			})
 
 			ctx := testutil.Context(t, testutil.WaitShort)
-			dei, err := agentcontainers.EnvInfo(ctx, agentexec.DefaultExecer, ct.Container.ID, tt.containerUser)
+			dei, err := agentcontainers.EnvInfo(ctx, agentexec.DefaultExecer, agentcontainers.RuntimeDocker, ct.Container.ID, tt.containerUser)
 			require.NoError(t, err, "Expected no error from DockerEnvInfo()")
 
 			u, err := dei.User()
@@ -267,13 +266,12 @@ func TestDockerEnvInfoer(t *testing.T) {
 
 			// Test that the environment variables are present in modified command
 			// output.
-			envCmd, envArgs := dei.ModifyCommand("env")
+			// We're not running in a tty, so no TTY may be allocated.
+			envCmd, envArgs := dei.WithoutTTY().ModifyCommand("env")
 			for _, env := range tt.expectedEnv {
 				require.Subset(t, envArgs, []string{"--env", env})
 			}
 			// Run the command in the container and check the output
-			// HACK: we remove the --tty argument because we're not running in a tty
-			envArgs = slices.DeleteFunc(envArgs, func(s string) bool { return s == "--tty" })
 			stdout, stderr, err := run(ctx, agentexec.DefaultExecer, envCmd, envArgs...)
 			require.Empty(t, stderr, "Expected no stderr output")
 			require.NoError(t, err, "Expected no error from running command")
@@ -39,8 +39,17 @@ func WithOutput(stdout, stderr io.Writer) DevcontainerCLIUpOptions {
 	}
 }
 
+// WithDockerPath sets the Docker-compatible CLI used by the devcontainer
+// CLI, e.g. podman.
+func WithDockerPath(path string) DevcontainerCLIUpOptions {
+	return func(o *devcontainerCLIUpConfig) {
+		o.dockerPath = path
+	}
+}
+
 type devcontainerCLIUpConfig struct {
 	removeExistingContainer bool
+	dockerPath              string
 	stdout                  io.Writer
 	stderr                  io.Writer
 }
@@ -86,6 +95,9 @@ func (d *devcontainerCLI) Up(ctx context.Context, workspaceFolder, configPath st
 	if conf.removeExistingContainer {
 		args = append(args, "--remove-existing-container")
 	}
+	if conf.dockerPath != "" {
+		args = append(args, "--docker-path", conf.dockerPath)
+	}
 	cmd := d.execer.CommandContext(ctx, "devcontainer", args...)
 
 	// Capture stdout for parsing and stream logs for both default and provided writers.
@@ -99,6 +99,16 @@ func TestDevcontainerCLI_ArgsAndParsing(t *testing.T) {
 				wantArgs:  "up --log-format json --workspace-folder /test/workspace --remove-existing-container",
 				wantError: false,
 			},
+			{
+				name:      "success with docker path",
+				logFile:   "up.log",
+				workspace: "/test/workspace",
+				opts: []agentcontainers.DevcontainerCLIUpOptions{
+					agentcontainers.WithDockerPath("podman"),
+				},
+				wantArgs:  "up --log-format json --workspace-folder /test/workspace --docker-path podman",
+				wantError: false,
+			},
 		}
 
 		for _, tt := range tests {
@@ -0,0 +1,71 @@
+package agentcontainers
+
+import (
+	"context"
+	"time"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+)
+
+// Runtime is a container runtime with a Docker-compatible CLI. The value is
+// the name of the CLI binary used to talk to the runtime.
+type Runtime string
+
+const (
+	// RuntimeDocker is the Docker container runtime.
+	RuntimeDocker Runtime = "docker"
+	// RuntimePodman is the Podman container runtime, which is commonly
+	// used on rootless hosts.
+	RuntimePodman Runtime = "podman"
+	// RuntimeNerdctl is the containerd container runtime, managed
+	// with nerdctl.
+	RuntimeNerdctl Runtime = "nerdctl"
+)
+
+// Runtimes is the list of supported container runtimes in the order in which
+// they are probed by DetectRuntime.
+var Runtimes = []Runtime{RuntimeDocker, RuntimePodman, RuntimeNerdctl}
+
+// detectRuntimeTimeout is the maximum time to wait for a single runtime to
+// respond during detection.
+const detectRuntimeTimeout = 5 * time.Second
+
+// Valid returns true if the runtime is supported.
+func (r Runtime) Valid() bool {
+	for _, rt := range Runtimes {
+		if r == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// CLI returns the name of the CLI binary for the runtime. The zero value
+// defaults to docker.
+func (r Runtime) CLI() string {
+	if r == "" {
+		return string(RuntimeDocker)
+	}
+	return string(r)
+}
+
+// DetectRuntime returns the first container runtime that is able to list
+// containers. Note that a podman-docker or nerdctl shim installed as docker
+// is detected as docker, which is fine as the CLIs are compatible. If no
+// runtime responds, RuntimeDocker is returned so that errors surface from
+// the docker CLI as before.
+func DetectRuntime(ctx context.Context, execer agentexec.Execer) Runtime {
+	for _, rt := range Runtimes {
+		if probeRuntime(ctx, execer, rt) {
+			return rt
+		}
+	}
+	return RuntimeDocker
+}
+
+func probeRuntime(ctx context.Context, execer agentexec.Execer, rt Runtime) bool {
+	ctx, cancel := context.WithTimeout(ctx, detectRuntimeTimeout)
+	defer cancel()
+	_, _, err := run(ctx, execer, rt.CLI(), "ps", "--quiet")
+	return err == nil
+}
@@ -0,0 +1,187 @@
+package agentcontainers_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/pty"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// fakeRuntimeExecer implements the agentexec.Execer interface and simulates
+// the CLIs of container runtimes. Only the runtimes in fixtures respond, with
+// the containers in the given testdata directory. Other runtimes fail as if
+// they were not installed.
+type fakeRuntimeExecer struct {
+	t        *testing.T
+	fixtures map[agentcontainers.Runtime]string
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeRuntimeExecer) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	f.mu.Lock()
+	f.calls = append(f.calls, strings.Join(append([]string{name}, args...), " "))
+	f.mu.Unlock()
+
+	fixture, ok := f.fixtures[agentcontainers.Runtime(name)]
+	if !ok || len(args) == 0 {
+		return exec.CommandContext(ctx, "sh", "-c", `echo "$0: command not found" >&2; exit 127`, name)
+	}
+	inspectPath := filepath.Join("testdata", fixture, "docker_inspect.json")
+	switch args[0] {
+	case "ps":
+		if !assert.Contains(f.t, args, "--quiet") {
+			break
+		}
+		bs, err := os.ReadFile(inspectPath)
+		require.NoError(f.t, err)
+		var ins []struct {
+			ID string `json:"Id"`
+		}
+		require.NoError(f.t, json.Unmarshal(bs, &ins))
+		ids := make([]string, 0, len(ins))
+		for _, in := range ins {
+			ids = append(ids, in.ID)
+		}
+		return exec.CommandContext(ctx, "echo", strings.Join(ids, "\n"))
+	case "inspect":
+		return exec.CommandContext(ctx, "cat", inspectPath)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", `echo "unknown command: $*" >&2; exit 1`, name)
+}
+
+func (*fakeRuntimeExecer) PTYCommandContext(ctx context.Context, name string, args ...string) *pty.Cmd {
+	return pty.CommandContext(ctx, name, args...)
+}
+
+func (f *fakeRuntimeExecer) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func TestDetectRuntime(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name      string
+		available []agentcontainers.Runtime
+		want      agentcontainers.Runtime
+	}{
+		{
+			name: "none",
+			want: agentcontainers.RuntimeDocker,
+		},
+		{
+			name:      "docker",
+			available: []agentcontainers.Runtime{agentcontainers.RuntimeDocker},
+			want:      agentcontainers.RuntimeDocker,
+		},
+		{
+			name:      "podman",
+			available: []agentcontainers.Runtime{agentcontainers.RuntimePodman},
+			want:      agentcontainers.RuntimePodman,
+		},
+		{
+			name:      "nerdctl",
+			available: []agentcontainers.Runtime{agentcontainers.RuntimeNerdctl},
+			want:      agentcontainers.RuntimeNerdctl,
+		},
+		{
+			name:      "docker preferred",
+			available: []agentcontainers.Runtime{agentcontainers.RuntimeNerdctl, agentcontainers.RuntimePodman, agentcontainers.RuntimeDocker},
+			want:      agentcontainers.RuntimeDocker,
+		},
+		{
+			name:      "podman preferred over nerdctl",
+			available: []agentcontainers.Runtime{agentcontainers.RuntimeNerdctl, agentcontainers.RuntimePodman},
+			want:      agentcontainers.RuntimePodman,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+
+			execer := &fakeRuntimeExecer{t: t, fixtures: map[agentcontainers.Runtime]string{}}
+			for _, rt := range tt.available {
+				execer.fixtures[rt] = "container_simple"
+			}
+			got := agentcontainers.DetectRuntime(ctx, execer)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRuntimeLister(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name      string
+		runtime   agentcontainers.Runtime
+		newLister func(*fakeRuntimeExecer) agentcontainers.Lister
+		fixture   string
+		wantNames []string
+	}{
+		{
+			name:      "docker",
+			runtime:   agentcontainers.RuntimeDocker,
+			newLister: func(e *fakeRuntimeExecer) agentcontainers.Lister { return agentcontainers.NewDocker(e) },
+			fixture:   "container_simple",
+			wantNames: []string{"eloquent_kowalevski"},
+		},
+		{
+			name:      "podman",
+			runtime:   agentcontainers.RuntimePodman,
+			newLister: func(e *fakeRuntimeExecer) agentcontainers.Lister { return agentcontainers.NewPodman(e) },
+			fixture:   "podman_simple",
+			wantNames: []string{"upbeat_carson"},
+		},
+		{
+			name:      "nerdctl",
+			runtime:   agentcontainers.RuntimeNerdctl,
+			newLister: func(e *fakeRuntimeExecer) agentcontainers.Lister { return agentcontainers.NewNerdctl(e) },
+			fixture:   "nerdctl_simple",
+			wantNames: []string{"debian-2f9d1"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+
+			execer := &fakeRuntimeExecer{
+				t:        t,
+				fixtures: map[agentcontainers.Runtime]string{tt.runtime: tt.fixture},
+			}
+			res, err := tt.newLister(execer).List(ctx)
+			require.NoError(t, err)
+			require.Empty(t, res.Warnings)
+			names := make([]string, 0, len(res.Containers))
+			for _, c := range res.Containers {
+				names = append(names, c.FriendlyName)
+			}
+			require.Equal(t, tt.wantNames, names)
+
+			// Only the CLI of the runtime is used.
+			calls := execer.Calls()
+			require.Len(t, calls, 2)
+			require.Equal(t, string(tt.runtime)+" ps --all --quiet --no-trunc", calls[0])
+			require.Equal(t, string(tt.runtime)+" inspect "+res.Containers[0].ID, calls[1])
+
+			// A runtime that is not available returns an error.
+			_, err = agentcontainers.NewLister(execer, "missing").List(ctx)
+			require.ErrorContains(t, err, "run missing ps")
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Package agentfiles implements the archive format used to transfer
+// directories to and from workspace agents.
+package agentfiles
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Tar writes the contents of the directory to w as a tar archive. Entry names
+// are relative to the directory. Regular files, directories and symbolic
+// links are archived, other files such as sockets are skipped. Symbolic links
+// are archived as links and are not followed.
+func Tar(w io.Writer, directory string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		switch {
+		case info.Mode().IsRegular(), info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		// Use unix paths in the tar archive.
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// Owners are not preserved, files are owned by the user that
+		// extracts them.
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		// Copy at most the size in the header, the file may grow while it
+		// is archived.
+		_, err = io.CopyN(tw, f, header.Size)
+		return err
+	})
+	if err != nil {
+		return xerrors.Errorf("archive %q: %w", directory, err)
+	}
+	return tw.Close()
+}
+
+// Untar extracts the tar archive read from r into the directory, creating it
+// if it does not exist. Existing files are overwritten. Entries that would be
+// extracted outside of the directory are rejected.
+func Untar(directory string, r io.Reader) error {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return xerrors.Errorf("create directory %q: %w", directory, err)
+	}
+	// The root prevents entries from escaping the directory, including
+	// through symbolic links extracted earlier.
+	root, err := os.OpenRoot(directory)
+	if err != nil {
+		return xerrors.Errorf("open directory %q: %w", directory, err)
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read archive: %w", err)
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if name == "." {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return xerrors.Errorf("archive entry %q is outside of the destination", header.Name)
+		}
+		// #nosec G115 - Safe conversion as tar header mode fits within uint32
+		mode := fs.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirAll(root, name, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := mkdirAll(root, filepath.Dir(name), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(root, name, mode, tr); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := mkdirAll(root, filepath.Dir(name), 0o755); err != nil {
+				return err
+			}
+			// Replace an existing file, as os.Symlink does not.
+			if err := root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return xerrors.Errorf("replace %q: %w", header.Name, err)
+			}
+			// The parent was resolved within the root above, so the link
+			// is created inside the directory.
+			if err := os.Symlink(header.Linkname, filepath.Join(directory, name)); err != nil {
+				return xerrors.Errorf("create symlink %q: %w", header.Name, err)
+			}
+		default:
+			// Other file types are not transferred.
+		}
+	}
+}
+
+// mkdirAll creates the directory and any missing parents within the root.
+func mkdirAll(root *os.Root, name string, mode fs.FileMode) error {
+	if name == "." {
+		return nil
+	}
+	var dir string
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		err := root.Mkdir(dir, mode|0o700)
+		if err == nil || errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return xerrors.Errorf("create directory %q: %w", dir, err)
+	}
+	info, err := root.Stat(name)
+	if err != nil {
+		return xerrors.Errorf("stat %q: %w", name, err)
+	}
+	if !info.IsDir() {
+		return xerrors.Errorf("%q is not a directory", name)
+	}
+	return nil
+}
+
+func writeFile(root *os.Root, name string, mode fs.FileMode, r io.Reader) error {
+	f, err := root.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return xerrors.Errorf("create file %q: %w", name, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("write file %q: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return xerrors.Errorf("close file %q: %w", name, err)
+	}
+	// The mode is only applied when the file is created, so apply it to
+	// files that are overwritten too.
+	return chmod(root, name, mode)
+}
+
+func chmod(root *os.Root, name string, mode fs.FileMode) error {
+	f, err := root.Open(name)
+	if err != nil {
+		return xerrors.Errorf("open file %q: %w", name, err)
+	}
+	defer f.Close()
+	if err := f.Chmod(mode); err != nil {
+		return xerrors.Errorf("chmod %q: %w", name, err)
+	}
+	return nil
+}
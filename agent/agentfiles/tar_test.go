@@ -0,0 +1,119 @@
+package agentfiles_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentfiles"
+)
+
+func TestTarUntar(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "a", "b"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "empty"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "top.txt"), []byte("top"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a", "b", "script.sh"), []byte("#!/bin/sh\n"), 0o755))
+	if runtime.GOOS != "windows" {
+		require.NoError(t, os.Symlink("a/b/script.sh", filepath.Join(src, "link")))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, agentfiles.Tar(&buf, src))
+
+	dst := filepath.Join(t.TempDir(), "nested", "dst")
+	require.NoError(t, agentfiles.Untar(dst, &buf))
+
+	got, err := os.ReadFile(filepath.Join(dst, "top.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "top", string(got))
+	got, err = os.ReadFile(filepath.Join(dst, "a", "b", "script.sh"))
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\n", string(got))
+	info, err := os.Stat(filepath.Join(dst, "empty"))
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	if runtime.GOOS != "windows" {
+		info, err = os.Stat(filepath.Join(dst, "a", "b", "script.sh"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(dst, "top.txt"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		link, err := os.Readlink(filepath.Join(dst, "link"))
+		require.NoError(t, err)
+		require.Equal(t, "a/b/script.sh", link)
+	}
+
+	// Extracting again overwrites the existing files.
+	require.NoError(t, os.WriteFile(filepath.Join(src, "top.txt"), []byte("changed"), 0o600))
+	buf.Reset()
+	require.NoError(t, agentfiles.Tar(&buf, src))
+	require.NoError(t, agentfiles.Untar(dst, &buf))
+	got, err = os.ReadFile(filepath.Join(dst, "top.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "changed", string(got))
+}
+
+func TestUntarOutsideDestination(t *testing.T) {
+	t.Parallel()
+
+	archive := func(t *testing.T, headers ...*tar.Header) *bytes.Buffer {
+		t.Helper()
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, h := range headers {
+			require.NoError(t, tw.WriteHeader(h))
+			if h.Size > 0 {
+				_, err := tw.Write(bytes.Repeat([]byte("x"), int(h.Size)))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	t.Run("ParentDirectory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		dst := filepath.Join(dir, "dst")
+		err := agentfiles.Untar(dst, archive(t, &tar.Header{
+			Typeflag: tar.TypeReg, Name: "../escaped.txt", Mode: 0o644, Size: 1,
+		}))
+		require.ErrorContains(t, err, "outside of the destination")
+		require.NoFileExists(t, filepath.Join(dir, "escaped.txt"))
+	})
+
+	t.Run("AbsolutePath", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := agentfiles.Untar(filepath.Join(dir, "dst"), archive(t, &tar.Header{
+			Typeflag: tar.TypeReg, Name: filepath.ToSlash(filepath.Join(dir, "escaped.txt")), Mode: 0o644, Size: 1,
+		}))
+		require.Error(t, err)
+		require.NoFileExists(t, filepath.Join(dir, "escaped.txt"))
+	})
+
+	t.Run("Symlink", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require elevated privileges on Windows")
+		}
+		dir := t.TempDir()
+		outside := filepath.Join(dir, "outside")
+		require.NoError(t, os.Mkdir(outside, 0o755))
+		err := agentfiles.Untar(filepath.Join(dir, "dst"), archive(t,
+			&tar.Header{Typeflag: tar.TypeSymlink, Name: "link", Linkname: outside, Mode: 0o777},
+			&tar.Header{Typeflag: tar.TypeReg, Name: "link/escaped.txt", Mode: 0o644, Size: 1},
+		))
+		require.Error(t, err)
+		require.NoFileExists(t, filepath.Join(outside, "escaped.txt"))
+	})
+}
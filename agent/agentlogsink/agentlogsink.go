@@ -0,0 +1,377 @@
+// Package agentlogsink ships the logs of the agent, the output of scripts
+// and connection events to sinks outside of Coder, such as a local file, a
+// syslog server or an OpenTelemetry collector.
+//
+// Shipping never blocks the caller: every sink has a bounded queue that is
+// drained by its own goroutine, and records are dropped when the queue is
+// full, e.g. because the sink is unreachable.
+package agentlogsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// defaultQueueSize is the number of records buffered per sink.
+	defaultQueueSize = 4096
+	// maxBatchSize is the maximum number of records written to a sink at
+	// once.
+	maxBatchSize = 512
+	// writeTimeout bounds the time a sink may take to write a batch.
+	writeTimeout = 10 * time.Second
+	// closeTimeout bounds the time spent flushing queued records on close.
+	closeTimeout = 5 * time.Second
+	// minRetryDelay and maxRetryDelay bound the exponential backoff between
+	// attempts to write to a failing sink.
+	minRetryDelay = 500 * time.Millisecond
+	maxRetryDelay = 30 * time.Second
+)
+
+// Source is the origin of a record.
+type Source string
+
+const (
+	// SourceAgent is the log output of the agent itself.
+	SourceAgent Source = "agent"
+	// SourceScript is the output of scripts and services.
+	SourceScript Source = "script"
+	// SourceConnection are connections to and disconnections from the
+	// workspace.
+	SourceConnection Source = "connection"
+)
+
+// Sources are all sources of records.
+var Sources = []Source{SourceAgent, SourceScript, SourceConnection}
+
+// Record is a single log line.
+type Record struct {
+	Time    time.Time
+	Source  Source
+	Level   codersdk.LogLevel
+	Message string
+	// Attributes describe the record, e.g. the log source of a script.
+	Attributes map[string]string
+	// Resource describes the workspace agent that produced the record, it
+	// is set by the shipper.
+	Resource map[string]string
+}
+
+// Sink writes records to a destination outside of Coder.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Write writes a batch of records. The batch is retried if an error is
+	// returned, unless it is wrapped with Permanent.
+	Write(ctx context.Context, records []Record) error
+	Close() error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error of a sink as not retryable, the batch that failed
+// to be written is dropped.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent returns whether the error was marked with Permanent.
+func IsPermanent(err error) bool {
+	var perm permanentError
+	return errors.As(err, &perm)
+}
+
+// Options are a set of options for the shipper.
+type Options struct {
+	// Logger is used to report failing sinks. It must not write to the
+	// slog sink of the shipper.
+	Logger slog.Logger
+	// Sources limits the records that are shipped, all sources are shipped
+	// if it is empty.
+	Sources []Source
+	// QueueSize is the number of records buffered per sink.
+	QueueSize int
+	Clock     quartz.Clock
+}
+
+// Shipper sends records to sinks.
+type Shipper struct {
+	logger    slog.Logger
+	clock     quartz.Clock
+	sources   map[Source]bool
+	queueSize int
+	resource  atomic.Pointer[map[string]string]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	queues []*queue
+	closed atomic.Bool
+}
+
+type queue struct {
+	sink   Sink
+	notify chan struct{}
+
+	mu      sync.Mutex
+	records []Record
+	dropped int
+}
+
+// New starts a shipper that sends records to the sinks.
+func New(opts Options, sinks ...Sink) *Shipper {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	sources := opts.Sources
+	if len(sources) == 0 {
+		sources = Sources
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Shipper{
+		logger:    opts.Logger,
+		clock:     opts.Clock,
+		sources:   make(map[Source]bool, len(sources)),
+		queueSize: opts.QueueSize,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for _, source := range sources {
+		s.sources[source] = true
+	}
+	for _, sink := range sinks {
+		q := &queue{
+			sink:   sink,
+			notify: make(chan struct{}, 1),
+		}
+		s.queues = append(s.queues, q)
+		s.wg.Add(1)
+		go s.run(q)
+	}
+	return s
+}
+
+// Enabled returns whether records of the source are shipped. It is safe to
+// call on a nil shipper.
+func (s *Shipper) Enabled(source Source) bool {
+	return s != nil && len(s.queues) > 0 && s.sources[source]
+}
+
+// SetResource sets the attributes that describe the workspace agent, they
+// are added to every record shipped afterwards.
+func (s *Shipper) SetResource(resource map[string]string) {
+	resource = maps.Clone(resource)
+	s.resource.Store(&resource)
+}
+
+// Ship queues the record for all sinks. It never blocks, the record is
+// dropped for a sink whose queue is full.
+func (s *Shipper) Ship(record Record) {
+	if !s.Enabled(record.Source) || s.closed.Load() {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = s.clock.Now()
+	}
+	if resource := s.resource.Load(); resource != nil {
+		record.Resource = *resource
+	}
+	for _, q := range s.queues {
+		q.mu.Lock()
+		if len(q.records) >= s.queueSize {
+			q.dropped++
+			q.mu.Unlock()
+			continue
+		}
+		q.records = append(q.records, record)
+		q.mu.Unlock()
+		select {
+		case q.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close flushes the queued records and closes the sinks.
+func (s *Shipper) Close() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	var errs []error
+	for _, q := range s.queues {
+		if err := q.sink.Close(); err != nil {
+			errs = append(errs, xerrors.Errorf("close %s sink: %w", q.sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run writes the records queued for a sink until the shipper is closed.
+func (s *Shipper) run(q *queue) {
+	defer s.wg.Done()
+
+	logger := s.logger.With(slog.F("sink", q.sink.Name()))
+	var (
+		retryDelay time.Duration
+		failing    bool
+	)
+	for {
+		select {
+		case <-q.notify:
+		case <-s.ctx.Done():
+			s.flush(q, logger)
+			return
+		}
+
+		for {
+			batch := q.peek()
+			if len(batch) == 0 {
+				break
+			}
+			ctx, cancel := context.WithTimeout(s.ctx, writeTimeout)
+			err := q.sink.Write(ctx, batch)
+			cancel()
+			if err != nil && !IsPermanent(err) {
+				if s.ctx.Err() != nil {
+					break
+				}
+				if !failing {
+					logger.Warn(s.ctx, "write to log sink failed, retrying", slog.Error(err))
+					failing = true
+				}
+				retryDelay = min(max(retryDelay*2, minRetryDelay), maxRetryDelay)
+				timer := s.clock.NewTimer(retryDelay, "logsink", "retry")
+				select {
+				case <-timer.C:
+				case <-s.ctx.Done():
+					timer.Stop()
+				}
+				if s.ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+			if err != nil {
+				logger.Warn(s.ctx, "log sink rejected records, dropping them", slog.F("count", len(batch)), slog.Error(err))
+			} else if failing {
+				logger.Info(s.ctx, "write to log sink recovered")
+			}
+			failing = false
+			retryDelay = 0
+			if dropped := q.pop(len(batch)); dropped > 0 {
+				logger.Warn(s.ctx, "log sink queue was full, records were dropped", slog.F("count", dropped))
+			}
+		}
+	}
+}
+
+// flush makes a last attempt to write the queued records when the shipper
+// is closed.
+func (s *Shipper) flush(q *queue, logger slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	for {
+		batch := q.peek()
+		if len(batch) == 0 {
+			return
+		}
+		err := q.sink.Write(ctx, batch)
+		if err != nil {
+			logger.Warn(ctx, "flush log sink failed, dropping queued records", slog.Error(err))
+			return
+		}
+		q.pop(len(batch))
+	}
+}
+
+// peek returns the next batch of queued records without removing them, so
+// they are retried if writing them fails.
+func (q *queue) peek() []Record {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := min(len(q.records), maxBatchSize)
+	return q.records[:n:n]
+}
+
+// pop removes the first n records and returns the number of records that
+// were dropped since the last call.
+func (q *queue) pop(n int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// Records are only appended by Ship, so the first n records are the
+	// ones returned by peek.
+	clear(q.records[:n])
+	q.records = q.records[n:]
+	dropped := q.dropped
+	q.dropped = 0
+	return dropped
+}
+
+// SlogSink returns a sink for the logger of the agent that ships its
+// entries.
+func (s *Shipper) SlogSink() slog.Sink {
+	return slogSink{shipper: s}
+}
+
+type slogSink struct {
+	shipper *Shipper
+}
+
+func (k slogSink) LogEntry(_ context.Context, e slog.SinkEntry) {
+	if !k.shipper.Enabled(SourceAgent) {
+		return
+	}
+	attrs := make(map[string]string, len(e.Fields)+1)
+	if len(e.LoggerNames) > 0 {
+		attrs["coder.logger"] = strings.Join(e.LoggerNames, ".")
+	}
+	for _, f := range e.Fields {
+		attrs[f.Name] = fmt.Sprint(f.Value)
+	}
+	k.shipper.Ship(Record{
+		Time:       e.Time,
+		Source:     SourceAgent,
+		Level:      levelFromSlog(e.Level),
+		Message:    e.Message,
+		Attributes: attrs,
+	})
+}
+
+func (slogSink) Sync() {}
+
+func levelFromSlog(level slog.Level) codersdk.LogLevel {
+	switch {
+	case level >= slog.LevelError:
+		return codersdk.LogLevelError
+	case level >= slog.LevelWarn:
+		return codersdk.LogLevelWarn
+	case level >= slog.LevelInfo:
+		return codersdk.LogLevelInfo
+	default:
+		return codersdk.LogLevelDebug
+	}
+}
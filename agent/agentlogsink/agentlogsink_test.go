@@ -0,0 +1,397 @@
+package agentlogsink_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+	protobuf "google.golang.org/protobuf/proto"
+
+	collectorlogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/agent/agentlogsink"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestShipper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deliver", func(t *testing.T) {
+		t.Parallel()
+
+		sink := newFakeSink()
+		s := agentlogsink.New(agentlogsink.Options{Logger: testutil.Logger(t)}, sink)
+		s.SetResource(map[string]string{"coder.agent.name": "main"})
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceScript, Level: codersdk.LogLevelInfo, Message: "hello"})
+
+		require.Eventually(t, func() bool {
+			return len(sink.messages()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		got := sink.records()[0]
+		require.Equal(t, "hello", got.Message)
+		require.Equal(t, "main", got.Resource["coder.agent.name"])
+		require.False(t, got.Time.IsZero())
+		require.NoError(t, s.Close())
+		require.True(t, sink.isClosed())
+	})
+
+	t.Run("Sources", func(t *testing.T) {
+		t.Parallel()
+
+		sink := newFakeSink()
+		s := agentlogsink.New(agentlogsink.Options{
+			Logger:  testutil.Logger(t),
+			Sources: []agentlogsink.Source{agentlogsink.SourceConnection},
+		}, sink)
+		require.True(t, s.Enabled(agentlogsink.SourceConnection))
+		require.False(t, s.Enabled(agentlogsink.SourceAgent))
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: "ignored"})
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceConnection, Message: "connect"})
+		require.NoError(t, s.Close())
+		require.Equal(t, []string{"connect"}, sink.messages())
+
+		var nilShipper *agentlogsink.Shipper
+		require.False(t, nilShipper.Enabled(agentlogsink.SourceAgent))
+	})
+
+	t.Run("DropWhenFull", func(t *testing.T) {
+		t.Parallel()
+
+		sink := newFakeSink()
+		sink.block = make(chan struct{})
+		s := agentlogsink.New(agentlogsink.Options{Logger: testutil.Logger(t), QueueSize: 2}, sink)
+
+		// The first record is being written while the next ones are queued.
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: "1"})
+		require.Eventually(t, func() bool {
+			return sink.attempts() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		for i := 2; i <= 5; i++ {
+			s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: strconv.Itoa(i)})
+		}
+		close(sink.block)
+		require.NoError(t, s.Close())
+		// The queue held the record being written and one more.
+		require.Equal(t, []string{"1", "2"}, sink.messages())
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		sink := newFakeSink()
+		sink.errs = []error{xerrors.New("unavailable"), xerrors.New("unavailable")}
+		s := agentlogsink.New(agentlogsink.Options{Logger: testutil.Logger(t)}, sink)
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: "retried"})
+
+		require.Eventually(t, func() bool {
+			return len(sink.messages()) == 1
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, 3, sink.attempts())
+		require.NoError(t, s.Close())
+	})
+
+	t.Run("Permanent", func(t *testing.T) {
+		t.Parallel()
+
+		sink := newFakeSink()
+		sink.errs = []error{agentlogsink.Permanent(xerrors.New("bad request"))}
+		s := agentlogsink.New(agentlogsink.Options{Logger: testutil.Logger(t)}, sink)
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: "rejected"})
+		require.Eventually(t, func() bool {
+			return sink.attempts() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		s.Ship(agentlogsink.Record{Source: agentlogsink.SourceAgent, Message: "accepted"})
+		require.NoError(t, s.Close())
+		require.Equal(t, []string{"accepted"}, sink.messages())
+	})
+
+	t.Run("SlogSink", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := newFakeSink()
+		s := agentlogsink.New(agentlogsink.Options{Logger: testutil.Logger(t)}, sink)
+		logger := slog.Make(s.SlogSink()).Named("agent").Leveled(slog.LevelDebug)
+		logger.Warn(ctx, "something happened", slog.F("count", 3))
+		require.NoError(t, s.Close())
+
+		records := sink.records()
+		require.Len(t, records, 1)
+		require.Equal(t, agentlogsink.SourceAgent, records[0].Source)
+		require.Equal(t, codersdk.LogLevelWarn, records[0].Level)
+		require.Equal(t, "something happened", records[0].Message)
+		require.Equal(t, map[string]string{"coder.logger": "agent", "count": "3"}, records[0].Attributes)
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	path := filepath.Join(t.TempDir(), "agent.log")
+	sink := agentlogsink.NewFileSink(path, 1, 1)
+	err := sink.Write(ctx, []agentlogsink.Record{
+		{Time: time.Now(), Source: agentlogsink.SourceScript, Level: codersdk.LogLevelInfo, Message: "one", Attributes: map[string]string{"a": "b"}},
+		{Time: time.Now(), Source: agentlogsink.SourceScript, Level: codersdk.LogLevelError, Message: "two"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	require.Equal(t, "one", line["message"])
+	require.Equal(t, "script", line["source"])
+	require.Equal(t, "info", line["level"])
+	require.Equal(t, map[string]any{"a": "b"}, line["attributes"])
+}
+
+func TestSyslogSink(t *testing.T) {
+	t.Parallel()
+
+	record := agentlogsink.Record{
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Source:     agentlogsink.SourceConnection,
+		Level:      codersdk.LogLevelWarn,
+		Message:    "hello world\n",
+		Attributes: map[string]string{"coder.connection.type": "ssh", "quote": `a"b]`},
+		Resource:   map[string]string{"coder.agent.name": "main"},
+	}
+	assertMessage := func(t *testing.T, msg string) {
+		t.Helper()
+		// Facility user (1) and severity warning (4).
+		require.True(t, strings.HasPrefix(msg, "<12>1 2024-01-02T03:04:05.000000Z "), msg)
+		require.Contains(t, msg, " coder-agent "+strconv.Itoa(os.Getpid())+" connection ")
+		require.True(t, strings.HasSuffix(msg,
+			`[coder@32473 coder.agent.name="main" coder.connection.type="ssh" quote="a\"b\]"] hello world`), msg)
+	}
+
+	t.Run("UDP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		sink, err := agentlogsink.NewSyslogSink("udp://" + conn.LocalAddr().String())
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(ctx, []agentlogsink.Record{record}))
+		require.NoError(t, sink.Close())
+
+		buf := make([]byte, 4096)
+		_ = conn.SetReadDeadline(time.Now().Add(testutil.WaitShort))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assertMessage(t, string(buf[:n]))
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		sink, err := agentlogsink.NewSyslogSink("tcp://" + ln.Addr().String())
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(ctx, []agentlogsink.Record{record, record}))
+		require.NoError(t, sink.Close())
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for range 2 {
+			length, err := r.ReadString(' ')
+			require.NoError(t, err)
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			require.NoError(t, err)
+			msg := make([]byte, n)
+			_, err = io.ReadFull(r, msg)
+			require.NoError(t, err)
+			assertMessage(t, string(msg))
+		}
+	})
+
+	t.Run("InvalidScheme", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentlogsink.NewSyslogSink("http://localhost:514")
+		require.Error(t, err)
+	})
+}
+
+func TestOTLPSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Export", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		requests := make(chan *collectorlogspb.ExportLogsServiceRequest, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+			assert.Equal(t, "secret", r.Header.Get("Authorization"))
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var req collectorlogspb.ExportLogsServiceRequest
+			if !assert.NoError(t, protobuf.Unmarshal(body, &req)) {
+				return
+			}
+			requests <- &req
+		}))
+		t.Cleanup(srv.Close)
+
+		sink, err := agentlogsink.NewOTLPSink(srv.URL+"/v1/logs", http.Header{"Authorization": []string{"secret"}})
+		require.NoError(t, err)
+		resource := map[string]string{"coder.agent.name": "main"}
+		err = sink.Write(ctx, []agentlogsink.Record{
+			{Time: time.Now(), Source: agentlogsink.SourceScript, Level: codersdk.LogLevelError, Message: "one", Resource: resource},
+			{Time: time.Now(), Source: agentlogsink.SourceAgent, Level: codersdk.LogLevelDebug, Message: "two", Resource: resource},
+		})
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		req := testutil.TryReceive(ctx, t, requests)
+		require.Len(t, req.ResourceLogs, 1)
+		rl := req.ResourceLogs[0]
+		require.Equal(t, "coder.agent.name", rl.Resource.Attributes[0].Key)
+		require.Equal(t, "main", rl.Resource.Attributes[0].Value.GetStringValue())
+		logs := rl.ScopeLogs[0].LogRecords
+		require.Len(t, logs, 2)
+		require.Equal(t, "one", logs[0].Body.GetStringValue())
+		require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, logs[0].SeverityNumber)
+		require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG, logs[1].SeverityNumber)
+		require.Equal(t, "coder.log.source", logs[0].Attributes[0].Key)
+		require.Equal(t, "script", logs[0].Attributes[0].Value.GetStringValue())
+	})
+
+	t.Run("StatusCodes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var status atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(int(status.Load()))
+		}))
+		t.Cleanup(srv.Close)
+		sink, err := agentlogsink.NewOTLPSink(srv.URL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = sink.Close() })
+
+		records := []agentlogsink.Record{{Time: time.Now(), Message: "hello"}}
+		status.Store(http.StatusServiceUnavailable)
+		err = sink.Write(ctx, records)
+		require.Error(t, err)
+		require.False(t, agentlogsink.IsPermanent(err))
+
+		status.Store(http.StatusBadRequest)
+		err = sink.Write(ctx, records)
+		require.Error(t, err)
+		require.True(t, agentlogsink.IsPermanent(err))
+	})
+}
+
+type fakeSink struct {
+	// block delays every write until it is closed.
+	block chan struct{}
+
+	mu      sync.Mutex
+	errs    []error
+	written []agentlogsink.Record
+	attempt int
+	closed  bool
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{}
+}
+
+func (*fakeSink) Name() string {
+	return "fake"
+}
+
+func (f *fakeSink) Write(ctx context.Context, records []agentlogsink.Record) error {
+	f.mu.Lock()
+	f.attempt++
+	f.mu.Unlock()
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.written = append(f.written, records...)
+	return nil
+}
+
+func (f *fakeSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSink) records() []agentlogsink.Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]agentlogsink.Record(nil), f.written...)
+}
+
+func (f *fakeSink) messages() []string {
+	var messages []string
+	for _, r := range f.records() {
+		messages = append(messages, r.Message)
+	}
+	return messages
+}
+
+func (f *fakeSink) attempts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempt
+}
+
+func (f *fakeSink) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
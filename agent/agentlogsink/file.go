@@ -0,0 +1,74 @@
+package agentlogsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileSink writes records as JSON lines to a file that is rotated when it
+// reaches its maximum size.
+type FileSink struct {
+	writer *lumberjack.Logger
+}
+
+var _ Sink = &FileSink{}
+
+// NewFileSink creates a sink that writes to path. The file is rotated when
+// it exceeds maxSizeMB megabytes and at most maxBackups rotated files are
+// kept.
+func NewFileSink(path string, maxSizeMB, maxBackups int) *FileSink {
+	return &FileSink{
+		writer: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+		},
+	}
+}
+
+func (*FileSink) Name() string {
+	return "file"
+}
+
+type fileRecord struct {
+	Time       time.Time         `json:"time"`
+	Source     Source            `json:"source"`
+	Level      string            `json:"level"`
+	Message    string            `json:"message"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Resource   map[string]string `json:"resource,omitempty"`
+}
+
+func (f *FileSink) Write(_ context.Context, records []Record) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		err := enc.Encode(fileRecord{
+			Time:       r.Time.UTC(),
+			Source:     r.Source,
+			Level:      string(r.Level),
+			Message:    r.Message,
+			Attributes: r.Attributes,
+			Resource:   r.Resource,
+		})
+		if err != nil {
+			return Permanent(xerrors.Errorf("encode record: %w", err))
+		}
+	}
+	// A batch is written at once so that lumberjack does not rotate the
+	// file in the middle of a line.
+	_, err := f.writer.Write(buf.Bytes())
+	if err != nil {
+		return xerrors.Errorf("write log file: %w", err)
+	}
+	return nil
+}
+
+func (f *FileSink) Close() error {
+	return f.writer.Close()
+}
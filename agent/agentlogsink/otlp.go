@@ -0,0 +1,173 @@
+package agentlogsink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"golang.org/x/xerrors"
+	protobuf "google.golang.org/protobuf/proto"
+
+	collectorlogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// otlpScopeName is the instrumentation scope of the records.
+const otlpScopeName = "github.com/coder/coder/v2/agent"
+
+// OTLPSink exports records to an OpenTelemetry collector with OTLP/HTTP
+// using the binary protobuf encoding.
+type OTLPSink struct {
+	endpoint string
+	header   http.Header
+	client   *http.Client
+}
+
+var _ Sink = &OTLPSink{}
+
+// NewOTLPSink creates a sink for the logs endpoint of a collector, e.g.
+// https://otel.example.com:4318/v1/logs. The header is sent with every
+// request, e.g. for authentication.
+func NewOTLPSink(endpoint string, header http.Header) (*OTLPSink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, xerrors.Errorf("parse OTLP endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, xerrors.Errorf("unsupported OTLP endpoint scheme %q, must be http or https", u.Scheme)
+	}
+	return &OTLPSink{
+		endpoint: u.String(),
+		header:   header.Clone(),
+		client:   &http.Client{},
+	}, nil
+}
+
+func (*OTLPSink) Name() string {
+	return "otlp"
+}
+
+func (o *OTLPSink) Write(ctx context.Context, records []Record) error {
+	body, err := protobuf.Marshal(otlpRequest(records))
+	if err != nil {
+		return Permanent(xerrors.Errorf("marshal logs: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(xerrors.Errorf("create request: %w", err))
+	}
+	for k, v := range o.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "coder-agent/"+buildinfo.Version())
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("export logs: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	// These are the retryable status codes of the OTLP/HTTP specification.
+	case res.StatusCode == http.StatusTooManyRequests,
+		res.StatusCode == http.StatusBadGateway,
+		res.StatusCode == http.StatusServiceUnavailable,
+		res.StatusCode == http.StatusGatewayTimeout:
+		return xerrors.Errorf("export logs: unexpected status code %d", res.StatusCode)
+	default:
+		return Permanent(xerrors.Errorf("export logs: unexpected status code %d", res.StatusCode))
+	}
+}
+
+func (o *OTLPSink) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpRequest groups the records by resource, records of a batch have a
+// different resource only if it changed while they were queued.
+func otlpRequest(records []Record) *collectorlogspb.ExportLogsServiceRequest {
+	req := &collectorlogspb.ExportLogsServiceRequest{}
+	var (
+		current      *logspb.ScopeLogs
+		lastResource map[string]string
+	)
+	for i, r := range records {
+		if i == 0 || !maps.Equal(r.Resource, lastResource) {
+			current = &logspb.ScopeLogs{
+				Scope: &commonpb.InstrumentationScope{
+					Name:    otlpScopeName,
+					Version: buildinfo.Version(),
+				},
+			}
+			req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+				Resource: &resourcepb.Resource{
+					Attributes: otlpAttributes(r.Resource),
+				},
+				ScopeLogs: []*logspb.ScopeLogs{current},
+			})
+			lastResource = r.Resource
+		}
+
+		attrs := otlpAttributes(r.Attributes)
+		attrs = append(attrs, otlpAttribute("coder.log.source", string(r.Source)))
+		current.LogRecords = append(current.LogRecords, &logspb.LogRecord{
+			TimeUnixNano:         uint64(r.Time.UnixNano()), //nolint:gosec // Timestamps are after 1970.
+			ObservedTimeUnixNano: uint64(r.Time.UnixNano()), //nolint:gosec // Timestamps are after 1970.
+			SeverityNumber:       otlpSeverity(r.Level),
+			SeverityText:         string(r.Level),
+			Body: &commonpb.AnyValue{
+				Value: &commonpb.AnyValue_StringValue{StringValue: r.Message},
+			},
+			Attributes: attrs,
+		})
+	}
+	return req
+}
+
+func otlpAttributes(attrs map[string]string) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs)+1)
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		kvs = append(kvs, otlpAttribute(k, attrs[k]))
+	}
+	return kvs
+}
+
+func otlpAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: value},
+		},
+	}
+}
+
+func otlpSeverity(level codersdk.LogLevel) logspb.SeverityNumber {
+	switch level {
+	case codersdk.LogLevelTrace:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	case codersdk.LogLevelDebug:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case codersdk.LogLevelInfo:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case codersdk.LogLevelWarn:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case codersdk.LogLevelError:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
@@ -0,0 +1,232 @@
+package agentlogsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	syslogAppName = "coder-agent"
+	// syslogFacility is the "user-level messages" facility.
+	syslogFacility = 1
+	// syslogSDID is the ID of the structured data element that holds the
+	// attributes of a record. 32473 is the private enterprise number
+	// reserved for documentation by RFC 5612, as Coder has none.
+	syslogSDID = "coder@32473"
+	// defaultSyslogPort is the port of syslog over UDP, and over TCP on most
+	// servers.
+	defaultSyslogPort = "514"
+)
+
+// SyslogSink sends records to a syslog server in the RFC 5424 format, over
+// UDP or over TCP with octet-counting framing (RFC 6587).
+type SyslogSink struct {
+	network  string
+	address  string
+	hostname string
+	procID   string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+var _ Sink = &SyslogSink{}
+
+// NewSyslogSink creates a sink for a server URL such as
+// udp://logs.example.com:514 or tcp://logs.example.com:601.
+func NewSyslogSink(rawURL string) (*SyslogSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse syslog URL: %w", err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+	default:
+		return nil, xerrors.Errorf("unsupported syslog URL scheme %q, must be udp or tcp", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, xerrors.Errorf("syslog URL %q has no host", rawURL)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultSyslogPort
+	}
+	hostname, _ := os.Hostname()
+	return &SyslogSink{
+		network:  u.Scheme,
+		address:  net.JoinHostPort(u.Hostname(), port),
+		hostname: syslogHeaderField(hostname, 255),
+		procID:   strconv.Itoa(os.Getpid()),
+	}, nil
+}
+
+func (*SyslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *SyslogSink) Write(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return xerrors.Errorf("dial syslog server: %w", err)
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		msg := s.format(r)
+		if s.network == "tcp" {
+			buf.WriteString(strconv.Itoa(len(msg)))
+			buf.WriteByte(' ')
+			buf.Write(msg)
+			continue
+		}
+		// Every UDP datagram holds one message.
+		_, err := s.conn.Write(msg)
+		if err != nil {
+			_ = s.closeLocked()
+			return xerrors.Errorf("write to syslog server: %w", err)
+		}
+	}
+	if buf.Len() > 0 {
+		_, err := s.conn.Write(buf.Bytes())
+		if err != nil {
+			// The connection is re-established on the next write, the
+			// server may receive a partial batch twice.
+			_ = s.closeLocked()
+			return xerrors.Errorf("write to syslog server: %w", err)
+		}
+	}
+	return nil
+}
+
+// format formats the record as a RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *SyslogSink) format(r Record) []byte {
+	var b bytes.Buffer
+	_, _ = fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ",
+		syslogFacility*8+syslogSeverity(r.Level),
+		r.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		s.hostname,
+		syslogAppName,
+		s.procID,
+		syslogHeaderField(string(r.Source), 32),
+	)
+	writeStructuredData(&b, r)
+	b.WriteByte(' ')
+	b.WriteString(strings.TrimRight(r.Message, "\r\n"))
+	return b.Bytes()
+}
+
+func writeStructuredData(b *bytes.Buffer, r Record) {
+	params := make(map[string]string, len(r.Resource)+len(r.Attributes))
+	for k, v := range r.Resource {
+		params[k] = v
+	}
+	for k, v := range r.Attributes {
+		params[k] = v
+	}
+	if len(params) == 0 {
+		b.WriteByte('-')
+		return
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	b.WriteString("[" + syslogSDID)
+	for _, name := range names {
+		b.WriteByte(' ')
+		b.WriteString(syslogSDName(name))
+		b.WriteString(`="`)
+		b.WriteString(syslogSDValueEscaper.Replace(params[name]))
+		b.WriteByte('"')
+	}
+	b.WriteByte(']')
+}
+
+var syslogSDValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogSDName returns a valid PARAM-NAME: at most 32 printable ASCII
+// characters except '=', ' ', ']' and '"'.
+func syslogSDName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
+
+// syslogHeaderField returns a valid header field: at most maxLen printable
+// ASCII characters, or the NILVALUE if it is empty.
+func syslogHeaderField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func syslogSeverity(level codersdk.LogLevel) int {
+	switch level {
+	case codersdk.LogLevelError:
+		return 3
+	case codersdk.LogLevelWarn:
+		return 4
+	case codersdk.LogLevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeLocked()
+}
+
+func (s *SyslogSink) closeLocked() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
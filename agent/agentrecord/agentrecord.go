@@ -0,0 +1,341 @@
+// Package agentrecord records the output of interactive sessions in the
+// asciicast v2 format and uploads it to coderd in chunks.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/ for the format.
+package agentrecord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/quartz"
+)
+
+const (
+	// chunkSize is the size at which buffered output is flushed.
+	chunkSize = 64 << 10
+	// flushInterval is the maximum time output is buffered before it is
+	// flushed, so that recordings of idle sessions are not lost if the
+	// agent dies.
+	flushInterval = 5 * time.Second
+	// maxQueuedBytes limits the amount of recorded output buffered while
+	// the agent is disconnected from coderd. Chunks are dropped once it is
+	// reached.
+	maxQueuedBytes = 16 << 20
+)
+
+// Uploader uploads chunks of recordings, it is implemented by the agent API
+// client.
+type Uploader interface {
+	UploadSessionRecording(ctx context.Context, req *proto.UploadSessionRecordingRequest) (*proto.UploadSessionRecordingResponse, error)
+}
+
+// Manager creates recorders for sessions and queues their chunks until they
+// are uploaded by SendLoop.
+type Manager struct {
+	logger  slog.Logger
+	clock   quartz.Clock
+	enabled atomic.Bool
+	notify  chan struct{}
+
+	mu          sync.Mutex
+	queue       []*proto.UploadSessionRecordingRequest
+	queuedBytes int
+	// rejected holds the recordings that coderd refused to accept more
+	// chunks for.
+	rejected map[uuid.UUID]struct{}
+}
+
+func NewManager(logger slog.Logger, clock quartz.Clock) *Manager {
+	return &Manager{
+		logger:   logger,
+		clock:    clock,
+		notify:   make(chan struct{}, 1),
+		rejected: make(map[uuid.UUID]struct{}),
+	}
+}
+
+// SetEnabled sets whether new sessions are recorded. Sessions that are
+// already being recorded are not affected.
+func (m *Manager) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Start starts recording a session with the given initial terminal size. It
+// returns nil if sessions are not being recorded. The recorder must be closed
+// when the session ends.
+func (m *Manager) Start(connectionType proto.Connection_Type, command string, height, width uint16) *Recorder {
+	if !m.enabled.Load() {
+		return nil
+	}
+	r := &Recorder{
+		manager:        m,
+		id:             uuid.New(),
+		connectionType: connectionType,
+		start:          m.clock.Now(),
+		height:         int32(height),
+		width:          int32(width),
+	}
+	header := struct {
+		Version   int    `json:"version"`
+		Width     int32  `json:"width"`
+		Height    int32  `json:"height"`
+		Timestamp int64  `json:"timestamp"`
+		Command   string `json:"command,omitempty"`
+	}{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Command:   command,
+	}
+	enc := json.NewEncoder(&r.buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(header)
+	m.logger.Debug(context.Background(), "started recording session",
+		slog.F("recording_id", r.id),
+		slog.F("connection_type", connectionType),
+	)
+	return r
+}
+
+func (m *Manager) enqueue(req *proto.UploadSessionRecordingRequest) {
+	id, _ := uuid.FromBytes(req.Id)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rejected[id]; ok {
+		if req.Final {
+			delete(m.rejected, id)
+		}
+		return
+	}
+	if m.queuedBytes+len(req.Data) > maxQueuedBytes {
+		m.logger.Warn(context.Background(), "session recording buffer limit reached, dropping chunk",
+			slog.F("limit", maxQueuedBytes),
+			slog.F("recording_id", id),
+			slog.F("sequence", req.Sequence),
+		)
+		return
+	}
+	m.queue = append(m.queue, req)
+	m.queuedBytes += len(req.Data)
+
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// SendLoop uploads queued chunks until it hits an error or the context is
+// canceled. It does not retry as it is expected that a higher layer retries
+// establishing connection to the agent API and calls SendLoop again.
+func (m *Manager) SendLoop(ctx context.Context, dest Uploader) error {
+	for {
+		for {
+			m.mu.Lock()
+			if len(m.queue) == 0 {
+				m.mu.Unlock()
+				break
+			}
+			req := m.queue[0]
+			// Release lock while we upload the chunk, this is safe since
+			// we only append to the queue.
+			m.mu.Unlock()
+
+			resp, err := dest.UploadSessionRecording(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("upload session recording: %w", err)
+			}
+
+			m.mu.Lock()
+			m.queue[0] = nil // Release the pointer from the underlying array.
+			m.queue = m.queue[1:]
+			m.queuedBytes -= len(req.Data)
+			if resp.Rejected && !req.Final {
+				id, _ := uuid.FromBytes(req.Id)
+				m.logger.Warn(ctx, "coderd rejected session recording, recording truncated", slog.F("recording_id", id))
+				m.rejected[id] = struct{}{}
+				m.dropQueuedLocked(id)
+			}
+			m.mu.Unlock()
+		}
+
+		select {
+		case <-m.notify:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// dropQueuedLocked removes the queued chunks of a recording, except for the
+// final chunk which marks the recording as ended.
+func (m *Manager) dropQueuedLocked(id uuid.UUID) {
+	queue := m.queue[:0]
+	for _, req := range m.queue {
+		if !req.Final && bytes.Equal(req.Id, id[:]) {
+			m.queuedBytes -= len(req.Data)
+			continue
+		}
+		queue = append(queue, req)
+	}
+	clear(m.queue[len(queue):])
+	m.queue = queue
+}
+
+// Recorder records the output of a single session. A nil *Recorder discards
+// everything, so callers need not check whether the session is recorded.
+type Recorder struct {
+	manager        *Manager
+	id             uuid.UUID
+	connectionType proto.Connection_Type
+	start          time.Time
+	height, width  int32
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	partial  []byte
+	sequence int32
+	timer    *quartz.Timer
+	closed   bool
+}
+
+// ID returns the ID of the recording.
+func (r *Recorder) ID() uuid.UUID {
+	if r == nil {
+		return uuid.Nil
+	}
+	return r.id
+}
+
+// Write records output of the session. It never fails, so that it can be
+// used with io.MultiWriter.
+func (r *Recorder) Write(p []byte) (int, error) {
+	if r == nil {
+		return len(p), nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return len(p), nil
+	}
+
+	// Output is recorded as JSON strings, which must be valid UTF-8, so a
+	// multi-byte character split across writes is held back until it is
+	// complete.
+	data := append(r.partial, p...)
+	data, r.partial = splitIncompleteRune(data)
+	if len(r.partial) > 0 {
+		r.partial = bytes.Clone(r.partial)
+	}
+	if len(data) > 0 {
+		r.writeEventLocked("o", string(data))
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(height, width uint16) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.writeEventLocked("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close flushes any buffered output and marks the recording as ended.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	if len(r.partial) > 0 {
+		r.writeEventLocked("o", string(r.partial))
+		r.partial = nil
+	}
+	r.closed = true
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.flushLocked(true)
+	return nil
+}
+
+func (r *Recorder) writeEventLocked(code, data string) {
+	elapsed := r.manager.clock.Since(r.start).Seconds()
+	enc := json.NewEncoder(&r.buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode([]any{math.Round(elapsed*1e6) / 1e6, code, data})
+
+	if r.buf.Len() >= chunkSize {
+		r.flushLocked(false)
+		return
+	}
+	if r.timer == nil {
+		r.timer = r.manager.clock.AfterFunc(flushInterval, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.timer = nil
+			if !r.closed {
+				r.flushLocked(false)
+			}
+		}, "agentrecord", "flush")
+	}
+}
+
+func (r *Recorder) flushLocked(final bool) {
+	if r.buf.Len() == 0 && !final {
+		return
+	}
+	r.manager.enqueue(&proto.UploadSessionRecordingRequest{
+		Id:        r.id[:],
+		Type:      r.connectionType,
+		StartedAt: timestamppb.New(r.start),
+		Width:     r.width,
+		Height:    r.height,
+		Sequence:  r.sequence,
+		Data:      bytes.Clone(r.buf.Bytes()),
+		Final:     final,
+	})
+	r.sequence++
+	r.buf.Reset()
+}
+
+// splitIncompleteRune splits an incomplete UTF-8 encoded character off the
+// end of b.
+func splitIncompleteRune(b []byte) (complete, rest []byte) {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if !utf8.RuneStart(b[len(b)-i]) {
+			continue
+		}
+		if !utf8.FullRune(b[len(b)-i:]) {
+			return b[:len(b)-i], b[len(b)-i:]
+		}
+		break
+	}
+	return b, nil
+}
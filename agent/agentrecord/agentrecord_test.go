@@ -0,0 +1,168 @@
+package agentrecord_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/agent/agentrecord"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+type fakeUploader struct {
+	mu       sync.Mutex
+	reqs     []*proto.UploadSessionRecordingRequest
+	rejected bool
+	uploaded chan struct{}
+}
+
+func newFakeUploader() *fakeUploader {
+	return &fakeUploader{uploaded: make(chan struct{}, 100)}
+}
+
+func (f *fakeUploader) UploadSessionRecording(_ context.Context, req *proto.UploadSessionRecordingRequest) (*proto.UploadSessionRecordingResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reqs = append(f.reqs, req)
+	f.uploaded <- struct{}{}
+	return &proto.UploadSessionRecordingResponse{Rejected: f.rejected}, nil
+}
+
+func (f *fakeUploader) requests() []*proto.UploadSessionRecordingRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.UploadSessionRecordingRequest(nil), f.reqs...)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		m := agentrecord.NewManager(slogtest.Make(t, nil), quartz.NewMock(t))
+		rec := m.Start(proto.Connection_SSH, "", 24, 80)
+		require.Nil(t, rec)
+
+		// A nil recorder discards everything.
+		n, err := rec.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+		rec.Resize(30, 100)
+		require.NoError(t, rec.Close())
+	})
+
+	t.Run("Cast", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clock := quartz.NewMock(t)
+		clock.Set(time.Unix(1735787045, 0))
+		m := agentrecord.NewManager(slogtest.Make(t, nil), clock)
+		m.SetEnabled(true)
+
+		rec := m.Start(proto.Connection_SSH, "vim", 24, 80)
+		require.NotNil(t, rec)
+		clock.Advance(500 * time.Millisecond)
+		_, _ = rec.Write([]byte("hello "))
+		clock.Advance(100 * time.Millisecond)
+		rec.Resize(30, 100)
+		// A character split across writes is held back until it is
+		// complete.
+		euro := []byte("€")
+		_, _ = rec.Write(euro[:1])
+		clock.Advance(100 * time.Millisecond)
+		_, _ = rec.Write(euro[1:])
+		require.NoError(t, rec.Close())
+
+		uploader := newFakeUploader()
+		go func() {
+			_ = m.SendLoop(ctx, uploader)
+		}()
+		testutil.TryReceive(ctx, t, uploader.uploaded)
+
+		reqs := uploader.requests()
+		require.Len(t, reqs, 1)
+		require.True(t, reqs[0].Final)
+		require.EqualValues(t, 0, reqs[0].Sequence)
+		id, err := uuid.FromBytes(reqs[0].Id)
+		require.NoError(t, err)
+		require.Equal(t, rec.ID(), id)
+		require.EqualValues(t, 80, reqs[0].Width)
+		require.EqualValues(t, 24, reqs[0].Height)
+		require.Equal(t, strings.Join([]string{
+			`{"version":2,"width":80,"height":24,"timestamp":1735787045,"command":"vim"}`,
+			`[0.5,"o","hello "]`,
+			`[0.6,"r","100x30"]`,
+			`[0.7,"o","€"]`,
+			``,
+		}, "\n"), string(reqs[0].Data))
+	})
+
+	t.Run("FlushInterval", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clock := quartz.NewMock(t)
+		m := agentrecord.NewManager(slogtest.Make(t, nil), clock)
+		m.SetEnabled(true)
+		uploader := newFakeUploader()
+		go func() {
+			_ = m.SendLoop(ctx, uploader)
+		}()
+
+		rec := m.Start(proto.Connection_VSCODE, "", 24, 80)
+		_, _ = rec.Write([]byte("hello"))
+		_, w := clock.AdvanceNext()
+		w.MustWait(ctx)
+		testutil.TryReceive(ctx, t, uploader.uploaded)
+
+		require.NoError(t, rec.Close())
+		testutil.TryReceive(ctx, t, uploader.uploaded)
+
+		reqs := uploader.requests()
+		require.Len(t, reqs, 2)
+		require.False(t, reqs[0].Final)
+		require.Contains(t, string(reqs[0].Data), `"hello"`)
+		require.True(t, reqs[1].Final)
+		require.EqualValues(t, 1, reqs[1].Sequence)
+		require.Empty(t, reqs[1].Data)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		m := agentrecord.NewManager(slogtest.Make(t, nil), quartz.NewMock(t))
+		m.SetEnabled(true)
+
+		// Enough output to fill several chunks.
+		rec := m.Start(proto.Connection_JETBRAINS, "", 24, 80)
+		line := bytes.Repeat([]byte("a"), 1024)
+		for i := 0; i < 200; i++ {
+			_, _ = rec.Write(line)
+		}
+		require.NoError(t, rec.Close())
+
+		uploader := newFakeUploader()
+		uploader.rejected = true
+		go func() {
+			_ = m.SendLoop(ctx, uploader)
+		}()
+		testutil.TryReceive(ctx, t, uploader.uploaded)
+		testutil.TryReceive(ctx, t, uploader.uploaded)
+
+		// Only the first chunk and the final chunk are uploaded once the
+		// recording is rejected.
+		reqs := uploader.requests()
+		require.Len(t, reqs, 2)
+		require.EqualValues(t, 0, reqs[0].Sequence)
+		require.True(t, reqs[1].Final)
+	})
+}
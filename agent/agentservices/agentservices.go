@@ -0,0 +1,560 @@
+// Package agentservices supervises the long-running services defined by the
+// template. Services are started after the startup scripts finish, restarted
+// according to their restart policy and, if they have a readiness probe,
+// checked like the healthcheck of workspace apps.
+package agentservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// minRestartDelay prevents a service that exits immediately from being
+	// restarted in a busy loop.
+	minRestartDelay = time.Second
+	// defaultProbeInterval is used if the readiness probe of a service does
+	// not set an interval.
+	defaultProbeInterval = 5 * time.Second
+	// waitDelay is the time a service is given to exit after it is sent
+	// SIGHUP, before it is killed.
+	waitDelay = 10 * time.Second
+)
+
+var (
+	// ErrNotFound is returned when a service does not exist.
+	ErrNotFound = xerrors.New("service not found")
+	// ErrNotStarted is returned when a service is restarted before the
+	// services were started.
+	ErrNotStarted = xerrors.New("services have not been started")
+)
+
+// StatusUpdater reports the status of services, it is implemented by the
+// agent API client.
+type StatusUpdater interface {
+	BatchUpdateServices(ctx context.Context, req *proto.BatchUpdateServicesRequest) (*proto.BatchUpdateServicesResponse, error)
+}
+
+// Options are a set of options for the supervisor.
+type Options struct {
+	LogDir          string
+	Logger          slog.Logger
+	SSHServer       *agentssh.Server
+	Filesystem      afero.Fs
+	GetScriptLogger func(logSourceID uuid.UUID) agentscripts.ScriptLogger
+	// Clock is used for restart delays and readiness probes, it defaults
+	// to the real clock.
+	Clock quartz.Clock
+}
+
+// Supervisor runs the services of the agent.
+type Supervisor struct {
+	Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// changed is notified when the status of a service changes.
+	changed chan struct{}
+
+	mu          sync.Mutex
+	services    []*service
+	initialized bool
+	started     bool
+	closed      bool
+}
+
+type service struct {
+	codersdk.WorkspaceAgentService
+	// restart is notified when a restart of the service is requested.
+	restart chan struct{}
+}
+
+// New creates a supervisor, services are added with Init.
+func New(opts Options) *Supervisor {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Supervisor{
+		Options: opts,
+		ctx:     ctx,
+		cancel:  cancel,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Init sets the services to supervise. It can only be called once.
+func (s *Supervisor) Init(services []codersdk.WorkspaceAgentService) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return xerrors.New("supervisor is closed")
+	}
+	if s.initialized {
+		return xerrors.New("services are already initialized")
+	}
+	s.initialized = true
+	for _, svc := range services {
+		svc.Status = codersdk.WorkspaceAgentServiceStatusPending
+		svc.Health = codersdk.WorkspaceAppHealthDisabled
+		if svc.ReadinessProbe.URL != "" {
+			svc.Health = codersdk.WorkspaceAppHealthInitializing
+		}
+		svc.Restarts = 0
+		svc.ExitCode = nil
+		svc.UpdatedAt = s.Clock.Now()
+		s.services = append(s.services, &service{
+			WorkspaceAgentService: svc,
+			restart:               make(chan struct{}, 1),
+		})
+	}
+	s.notifyLocked()
+	return nil
+}
+
+// Start starts all services. It is called after the startup scripts finish.
+func (s *Supervisor) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.started {
+		return
+	}
+	s.started = true
+	for _, svc := range s.services {
+		s.wg.Add(1)
+		go s.supervise(svc)
+	}
+}
+
+// Services returns the services and their current status.
+func (s *Supervisor) Services() []codersdk.WorkspaceAgentService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	services := make([]codersdk.WorkspaceAgentService, 0, len(s.services))
+	for _, svc := range s.services {
+		services = append(services, svc.snapshot())
+	}
+	return services
+}
+
+// Restart restarts the service with the given name, a service that exited
+// is started again.
+func (s *Supervisor) Restart(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, svc := range s.services {
+		if svc.Name != name {
+			continue
+		}
+		if !s.started || s.closed {
+			return ErrNotStarted
+		}
+		select {
+		case svc.restart <- struct{}{}:
+		default:
+			// A restart is already pending.
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+// SendLoop reports changes to the status of services until it hits an error
+// or the context is canceled. It does not retry as it is expected that a
+// higher layer retries establishing connection to the agent API and calls
+// SendLoop again, the status of all services is reported again then.
+func (s *Supervisor) SendLoop(ctx context.Context, dest StatusUpdater) error {
+	reported := make(map[uuid.UUID]codersdk.WorkspaceAgentService)
+	for {
+		var (
+			updates []*proto.BatchUpdateServicesRequest_Update
+			pending []codersdk.WorkspaceAgentService
+		)
+		s.mu.Lock()
+		for _, svc := range s.services {
+			current := svc.snapshot()
+			if last, ok := reported[svc.ID]; ok && !statusChanged(last, current) {
+				continue
+			}
+			update, err := protoFromServiceStatus(current)
+			if err != nil {
+				s.mu.Unlock()
+				return xerrors.Errorf("convert service %q: %w", current.Name, err)
+			}
+			updates = append(updates, update)
+			pending = append(pending, current)
+		}
+		s.mu.Unlock()
+
+		if len(updates) > 0 {
+			_, err := dest.BatchUpdateServices(ctx, &proto.BatchUpdateServicesRequest{Updates: updates})
+			if err != nil {
+				return xerrors.Errorf("update services: %w", err)
+			}
+			for _, svc := range pending {
+				reported[svc.ID] = svc
+			}
+		}
+
+		select {
+		case <-s.changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close stops all services and waits for them to exit.
+func (s *Supervisor) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// supervise runs the service until the supervisor is closed.
+func (s *Supervisor) supervise(svc *service) {
+	defer s.wg.Done()
+
+	logger := s.Logger.With(slog.F("service", svc.Name), slog.F("log_source_id", svc.LogSourceID))
+	for {
+		exitCode, restartRequested, err := s.run(svc, logger)
+		if s.ctx.Err() != nil {
+			s.setStatus(svc, func(svc *service) {
+				svc.Status = codersdk.WorkspaceAgentServiceStatusStopped
+				svc.ExitCode = exitCode
+			})
+			return
+		}
+		if restartRequested {
+			logger.Info(s.ctx, "restarting service on request")
+			s.setStatus(svc, func(svc *service) {
+				svc.Restarts++
+				svc.ExitCode = exitCode
+			})
+			continue
+		}
+
+		failed := err != nil
+		if failed {
+			logger.Warn(s.ctx, "service exited", slog.F("exit_code", exitCode), slog.Error(err))
+		} else {
+			logger.Info(s.ctx, "service exited", slog.F("exit_code", exitCode))
+		}
+
+		restart := false
+		switch svc.RestartPolicy {
+		case codersdk.WorkspaceAgentServiceRestartPolicyAlways:
+			restart = true
+		case codersdk.WorkspaceAgentServiceRestartPolicyOnFailure:
+			restart = failed
+		}
+		if restart {
+			s.setStatus(svc, func(svc *service) {
+				svc.Status = codersdk.WorkspaceAgentServiceStatusRestarting
+				svc.ExitCode = exitCode
+			})
+			delay := max(svc.RestartDelay, minRestartDelay)
+			timer := s.Clock.NewTimer(delay, "service", "restart", svc.Name)
+			select {
+			case <-timer.C:
+			case <-svc.restart:
+				timer.Stop()
+			case <-s.ctx.Done():
+				timer.Stop()
+				s.setStatus(svc, func(svc *service) {
+					svc.Status = codersdk.WorkspaceAgentServiceStatusStopped
+				})
+				return
+			}
+			s.setStatus(svc, func(svc *service) {
+				svc.Restarts++
+			})
+			continue
+		}
+
+		s.setStatus(svc, func(svc *service) {
+			svc.Status = codersdk.WorkspaceAgentServiceStatusStopped
+			if failed {
+				svc.Status = codersdk.WorkspaceAgentServiceStatusFailed
+			}
+			if svc.Health != codersdk.WorkspaceAppHealthDisabled {
+				svc.Health = codersdk.WorkspaceAppHealthUnhealthy
+			}
+			svc.ExitCode = exitCode
+		})
+		// Wait for a manual restart.
+		select {
+		case <-svc.restart:
+			logger.Info(s.ctx, "starting service on request")
+			s.setStatus(svc, func(svc *service) {
+				svc.Restarts++
+			})
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// run starts the service and waits for it to exit or for a restart to be
+// requested. The returned error is non-nil if the service failed.
+func (s *Supervisor) run(svc *service, logger slog.Logger) (exitCode *int32, restartRequested bool, err error) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	hasProbe := svc.ReadinessProbe.URL != ""
+	s.setStatus(svc, func(svc *service) {
+		svc.Status = codersdk.WorkspaceAgentServiceStatusRunning
+		if hasProbe {
+			svc.Status = codersdk.WorkspaceAgentServiceStatusStarting
+			svc.Health = codersdk.WorkspaceAppHealthInitializing
+		}
+	})
+
+	logPath, err := s.logPath(svc)
+	if err != nil {
+		return nil, false, err
+	}
+	fileWriter, err := s.Filesystem.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, false, xerrors.Errorf("open %s service log file: %w", logPath, err)
+	}
+	defer func() {
+		err := fileWriter.Close()
+		if err != nil {
+			logger.Warn(s.ctx, fmt.Sprintf("close %s service log file", logPath), slog.Error(err))
+		}
+	}()
+
+	scriptLogger := s.GetScriptLogger(svc.LogSourceID)
+	// The output of the service is sent with the context of the supervisor
+	// rather than the one of this run, so output written while the service
+	// is stopped for a restart is not discarded.
+	defer func() {
+		if err := scriptLogger.Flush(s.ctx); err != nil {
+			logger.Warn(s.ctx, "flush service logs failed", slog.Error(err))
+		}
+	}()
+	infoW := agentsdk.LogsWriter(s.ctx, scriptLogger.Send, svc.LogSourceID, codersdk.LogLevelInfo)
+	defer infoW.Close()
+	errW := agentsdk.LogsWriter(s.ctx, scriptLogger.Send, svc.LogSourceID, codersdk.LogLevelError)
+	defer errW.Close()
+
+	cmd, err := s.SSHServer.CreateNonInteractiveCommand(ctx, logger, svc.Command, []string{
+		"CODER_SERVICE_NAME=" + svc.Name,
+	})
+	if err != nil {
+		return nil, false, xerrors.Errorf("%s service: create command: %w", svc.Name, err)
+	}
+	cmd.WaitDelay = waitDelay
+	cmd.Stdout = io.MultiWriter(fileWriter, infoW)
+	cmd.Stderr = io.MultiWriter(fileWriter, errW)
+
+	logger.Info(s.ctx, "starting service", slog.F("command", svc.Command), slog.F("log_path", logPath))
+	err = cmd.Start()
+	if err != nil {
+		return nil, false, xerrors.Errorf("%s service: start command: %w", svc.Name, err)
+	}
+
+	var probeWg sync.WaitGroup
+	if hasProbe {
+		probeWg.Add(1)
+		go func() {
+			defer probeWg.Done()
+			s.probe(ctx, svc, logger)
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-svc.restart:
+		restartRequested = true
+		cancel()
+		err = <-done
+	}
+	cancel()
+	probeWg.Wait()
+
+	code := int32(0)
+	if err != nil {
+		code = 255 // Unknown status.
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			code = int32(exitError.ExitCode()) //nolint:gosec // Exit codes fit in an int32.
+		}
+	}
+	return &code, restartRequested, err
+}
+
+// probe checks the readiness probe of the service until ctx is canceled,
+// with the same semantics as the healthcheck of workspace apps.
+func (s *Supervisor) probe(ctx context.Context, svc *service, logger slog.Logger) {
+	interval := time.Duration(svc.ReadinessProbe.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	failures := 0
+	ticker := s.Clock.TickerFunc(ctx, interval, func() error {
+		// Time out at the probe interval, see the app healthcheck for why
+		// this is not a context.WithTimeout.
+		reqCtx, reqCancel := context.WithCancel(ctx)
+		defer reqCancel()
+		timeout := s.Clock.AfterFunc(interval-time.Millisecond, reqCancel, "service", "probe", "timeout", svc.Name)
+		defer timeout.Stop()
+
+		err := checkReadiness(reqCtx, svc.ReadinessProbe.URL)
+		if err != nil {
+			nowUnhealthy := false
+			if failures < int(svc.ReadinessProbe.Threshold) {
+				failures++
+			} else {
+				nowUnhealthy = true
+				s.setStatus(svc, func(svc *service) {
+					svc.Health = codersdk.WorkspaceAppHealthUnhealthy
+				})
+			}
+			logger.Debug(ctx, "service readiness probe failed", slog.F("now_unhealthy", nowUnhealthy), slog.Error(err))
+			return nil
+		}
+		failures = 0
+		s.setStatus(svc, func(svc *service) {
+			svc.Health = codersdk.WorkspaceAppHealthHealthy
+			if svc.Status == codersdk.WorkspaceAgentServiceStatusStarting {
+				svc.Status = codersdk.WorkspaceAgentServiceStatusRunning
+			}
+		})
+		return nil
+	}, "service", "probe", svc.Name)
+	_ = ticker.Wait()
+}
+
+func checkReadiness(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	// A service is ready if the probe returns a non-5XX status code.
+	_ = res.Body.Close()
+	if res.StatusCode >= http.StatusInternalServerError {
+		return xerrors.Errorf("error status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (s *Supervisor) logPath(svc *service) (string, error) {
+	logPath := svc.LogPath
+	if logPath == "" {
+		logPath = fmt.Sprintf("coder-service-%s.log", svc.Name)
+	}
+	if logPath[0] == '~' {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			u, err := user.Current()
+			if err != nil {
+				return "", xerrors.Errorf("current user: %w", err)
+			}
+			homeDir = u.HomeDir
+		}
+		logPath = filepath.Join(homeDir, logPath[1:])
+	}
+	logPath = os.ExpandEnv(logPath)
+	if !filepath.IsAbs(logPath) {
+		logPath = filepath.Join(s.LogDir, logPath)
+	}
+	return logPath, nil
+}
+
+// setStatus applies fn to the service and notifies SendLoop.
+func (s *Supervisor) setStatus(svc *service, fn func(svc *service)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := svc.snapshot()
+	fn(svc)
+	if !statusChanged(before, svc.snapshot()) {
+		return
+	}
+	svc.UpdatedAt = s.Clock.Now()
+	s.notifyLocked()
+}
+
+func (s *Supervisor) notifyLocked() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (svc *service) snapshot() codersdk.WorkspaceAgentService {
+	cpy := svc.WorkspaceAgentService
+	if svc.ExitCode != nil {
+		exitCode := *svc.ExitCode
+		cpy.ExitCode = &exitCode
+	}
+	return cpy
+}
+
+func statusChanged(a, b codersdk.WorkspaceAgentService) bool {
+	if a.Status != b.Status || a.Health != b.Health || a.Restarts != b.Restarts {
+		return true
+	}
+	if (a.ExitCode == nil) != (b.ExitCode == nil) {
+		return true
+	}
+	return a.ExitCode != nil && *a.ExitCode != *b.ExitCode
+}
+
+func protoFromServiceStatus(svc codersdk.WorkspaceAgentService) (*proto.BatchUpdateServicesRequest_Update, error) {
+	status, err := agentsdk.ProtoFromServiceStatus(svc.Status)
+	if err != nil {
+		return nil, err
+	}
+	health, err := agentsdk.ProtoFromAppHealth(svc.Health)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.BatchUpdateServicesRequest_Update{
+		Id:       svc.ID[:],
+		Status:   status,
+		Health:   health,
+		Restarts: svc.Restarts,
+		ExitCode: svc.ExitCode,
+	}, nil
+}
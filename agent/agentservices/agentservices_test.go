@@ -0,0 +1,262 @@
+package agentservices_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentservices"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("services use POSIX shell commands in this test")
+	}
+
+	t.Run("RestartOnFailure", func(t *testing.T) {
+		t.Parallel()
+
+		logs := &testScriptLogger{}
+		s := setup(t, logs)
+		svc := newService("worker", "echo hello; exit 3", codersdk.WorkspaceAgentServiceRestartPolicyOnFailure)
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+		s.Start()
+
+		require.Eventually(t, func() bool {
+			got := s.Services()[0]
+			return got.Restarts >= 1 && got.ExitCode != nil && *got.ExitCode == 3
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Contains(t, logs.outputs(), "hello")
+	})
+
+	t.Run("ExitNever", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t, &testScriptLogger{})
+		svc := newService("once", "exit 0", codersdk.WorkspaceAgentServiceRestartPolicyNever)
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+		s.Start()
+
+		require.Eventually(t, func() bool {
+			return s.Services()[0].Status == codersdk.WorkspaceAgentServiceStatusStopped
+		}, testutil.WaitLong, testutil.IntervalFast)
+		got := s.Services()[0]
+		require.NotNil(t, got.ExitCode)
+		require.EqualValues(t, 0, *got.ExitCode)
+		require.EqualValues(t, 0, got.Restarts)
+
+		// A stopped service is started again on request.
+		require.NoError(t, s.Restart("once"))
+		require.Eventually(t, func() bool {
+			got := s.Services()[0]
+			return got.Restarts == 1 && got.Status == codersdk.WorkspaceAgentServiceStatusStopped
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t, &testScriptLogger{})
+		svc := newService("broken", "exit 1", codersdk.WorkspaceAgentServiceRestartPolicyNever)
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+		s.Start()
+
+		require.Eventually(t, func() bool {
+			return s.Services()[0].Status == codersdk.WorkspaceAgentServiceStatusFailed
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.EqualValues(t, 1, *s.Services()[0].ExitCode)
+	})
+
+	t.Run("RestartRunning", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t, &testScriptLogger{})
+		svc := newService("sleeper", "sleep 300", codersdk.WorkspaceAgentServiceRestartPolicyAlways)
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+
+		require.ErrorIs(t, s.Restart("sleeper"), agentservices.ErrNotStarted)
+		s.Start()
+		require.Eventually(t, func() bool {
+			return s.Services()[0].Status == codersdk.WorkspaceAgentServiceStatusRunning
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		require.ErrorIs(t, s.Restart("unknown"), agentservices.ErrNotFound)
+		require.NoError(t, s.Restart("sleeper"))
+		require.Eventually(t, func() bool {
+			got := s.Services()[0]
+			return got.Restarts == 1 && got.Status == codersdk.WorkspaceAgentServiceStatusRunning
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		require.NoError(t, s.Close())
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStopped, s.Services()[0].Status)
+	})
+
+	t.Run("ReadinessProbe", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu    sync.Mutex
+			ready bool
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !ready {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+
+		s := setup(t, &testScriptLogger{})
+		svc := newService("web", "sleep 300", codersdk.WorkspaceAgentServiceRestartPolicyAlways)
+		svc.ReadinessProbe = codersdk.Healthcheck{URL: srv.URL, Interval: 1, Threshold: 100}
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+		require.Equal(t, codersdk.WorkspaceAppHealthInitializing, s.Services()[0].Health)
+		s.Start()
+
+		require.Eventually(t, func() bool {
+			return s.Services()[0].Status == codersdk.WorkspaceAgentServiceStatusStarting
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		mu.Lock()
+		ready = true
+		mu.Unlock()
+		require.Eventually(t, func() bool {
+			got := s.Services()[0]
+			return got.Status == codersdk.WorkspaceAgentServiceStatusRunning && got.Health == codersdk.WorkspaceAppHealthHealthy
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+
+	t.Run("SendLoop", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		s := setup(t, &testScriptLogger{})
+		svc := newService("once", "exit 0", codersdk.WorkspaceAgentServiceRestartPolicyNever)
+		require.NoError(t, s.Init([]codersdk.WorkspaceAgentService{svc}))
+
+		updater := &fakeUpdater{updates: make(chan *proto.BatchUpdateServicesRequest_Update, 10)}
+		loopCtx, cancel := context.WithCancel(ctx)
+		loopDone := make(chan error, 1)
+		go func() {
+			loopDone <- s.SendLoop(loopCtx, updater)
+		}()
+
+		// The initial status is reported right away.
+		update := testutil.TryReceive(ctx, t, updater.updates)
+		require.Equal(t, svc.ID[:], update.Id)
+		require.Equal(t, proto.ServiceStatus_PENDING, update.Status)
+
+		s.Start()
+		for update.Status != proto.ServiceStatus_STOPPED {
+			update = testutil.TryReceive(ctx, t, updater.updates)
+		}
+		require.NotNil(t, update.ExitCode)
+		require.EqualValues(t, 0, update.GetExitCode())
+		require.Equal(t, proto.AppHealth_DISABLED, update.Health)
+
+		cancel()
+		require.ErrorIs(t, testutil.TryReceive(ctx, t, loopDone), context.Canceled)
+	})
+}
+
+func setup(t *testing.T, logger agentscripts.ScriptLogger) *agentservices.Supervisor {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	log := testutil.Logger(t)
+	sshServer, err := agentssh.NewServer(context.Background(), log, prometheus.NewRegistry(), fs, agentexec.DefaultExecer, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = sshServer.Close()
+	})
+	s := agentservices.New(agentservices.Options{
+		LogDir:     t.TempDir(),
+		Logger:     log,
+		SSHServer:  sshServer,
+		Filesystem: fs,
+		GetScriptLogger: func(uuid.UUID) agentscripts.ScriptLogger {
+			return logger
+		},
+	})
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+	return s
+}
+
+func newService(name, command string, policy codersdk.WorkspaceAgentServiceRestartPolicy) codersdk.WorkspaceAgentService {
+	return codersdk.WorkspaceAgentService{
+		ID:            uuid.New(),
+		LogSourceID:   uuid.New(),
+		Name:          name,
+		Command:       command,
+		RestartPolicy: policy,
+		RestartDelay:  time.Millisecond,
+	}
+}
+
+type testScriptLogger struct {
+	mu   sync.Mutex
+	logs []agentsdk.Log
+}
+
+func (l *testScriptLogger) Send(_ context.Context, logs ...agentsdk.Log) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logs = append(l.logs, logs...)
+	return nil
+}
+
+func (*testScriptLogger) Flush(context.Context) error {
+	return nil
+}
+
+func (l *testScriptLogger) outputs() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var outputs []string
+	for _, log := range l.logs {
+		outputs = append(outputs, log.Output)
+	}
+	return outputs
+}
+
+type fakeUpdater struct {
+	updates chan *proto.BatchUpdateServicesRequest_Update
+}
+
+func (f *fakeUpdater) BatchUpdateServices(ctx context.Context, req *proto.BatchUpdateServicesRequest) (*proto.BatchUpdateServicesResponse, error) {
+	for _, update := range req.Updates {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case f.updates <- update:
+		}
+	}
+	return &proto.BatchUpdateServicesResponse{}, nil
+}
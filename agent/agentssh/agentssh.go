@@ -31,6 +31,7 @@ import (
 
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/agentrsa"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
@@ -113,6 +114,12 @@ type Config struct {
 	BlockFileTransfer bool
 	// ReportConnection.
 	ReportConnection reportConnectionFunc
+	// RecordSession starts recording the output of a PTY session. It may
+	// return nil if the session should not be recorded.
+	RecordSession func(sessionType MagicSessionType, command string, height, width uint16) *agentrecord.Recorder
+	// ContainerRuntime returns the container runtime used to run commands
+	// in containers. Default is docker.
+	ContainerRuntime func() agentcontainers.Runtime
 	// Experimental: allow connecting to running containers if
 	// CODER_AGENT_DEVCONTAINERS_ENABLE=true.
 	ExperimentalDevContainersEnabled bool
@@ -171,6 +178,12 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 	if config.ReportConnection == nil {
 		config.ReportConnection = func(uuid.UUID, MagicSessionType, string) func(int, string) { return func(int, string) {} }
 	}
+	if config.RecordSession == nil {
+		config.RecordSession = func(MagicSessionType, string, uint16, uint16) *agentrecord.Recorder { return nil }
+	}
+	if config.ContainerRuntime == nil {
+		config.ContainerRuntime = func() agentcontainers.Runtime { return agentcontainers.RuntimeDocker }
+	}
 
 	forwardHandler := &ssh.ForwardedTCPHandler{}
 	unixForwardHandler := newForwardedUnixHandler(logger)
@@ -435,12 +448,12 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	switch ss := session.Subsystem(); ss {
 	case "":
 	case "sftp":
+		var err error
 		if s.config.ExperimentalDevContainersEnabled && container != "" {
-			closeCause("sftp not yet supported with containers")
-			_ = session.Exit(1)
-			return
+			err = s.containerSFTPHandler(logger, session, container, containerUser)
+		} else {
+			err = s.sftpHandler(logger, session)
 		}
-		err := s.sftpHandler(logger, session)
 		if err != nil {
 			closeCause(err.Error())
 		}
@@ -548,13 +561,17 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, env []str
 	}
 
 	var ei usershell.EnvInfoer
-	var err error
 	if s.config.ExperimentalDevContainersEnabled && container != "" {
-		ei, err = agentcontainers.EnvInfo(ctx, s.Execer, container, containerUser)
+		dei, err := agentcontainers.EnvInfo(ctx, s.Execer, s.config.ContainerRuntime(), container, containerUser)
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, ptyLabel, "container_env_info").Add(1)
 			return err
 		}
+		if !isPty {
+			// File transfers with scp or rsync are run without a PTY.
+			dei = dei.WithoutTTY()
+		}
+		ei = dei
 	}
 	cmd, err := s.CreateCommand(ctx, session.RawCommand(), env, ei)
 	if err != nil {
@@ -574,7 +591,7 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, env []str
 	}
 
 	if isPty {
-		return s.startPTYSession(logger, session, magicTypeLabel, cmd, sshPty, windowSize)
+		return s.startPTYSession(logger, session, magicType, cmd, sshPty, windowSize)
 	}
 	return s.startNonPTYSession(logger, session, magicTypeLabel, cmd.AsExec())
 }
@@ -633,7 +650,8 @@ type ptySession interface {
 	Signals(chan<- ssh.Signal)
 }
 
-func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTypeLabel string, cmd *pty.Cmd, sshPty ssh.Pty, windowSize <-chan ssh.Window) (retErr error) {
+func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicType MagicSessionType, cmd *pty.Cmd, sshPty ssh.Pty, windowSize <-chan ssh.Window) (retErr error) {
+	magicTypeLabel := magicTypeMetricLabel(magicType)
 	s.metrics.sessionsTotal.WithLabelValues(magicTypeLabel, "yes").Add(1)
 
 	ctx := session.Context()
@@ -684,6 +702,9 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 			}
 		}
 	}()
+	// #nosec G115 - Safe conversions for terminal dimensions which are expected to be within uint16 range
+	rec := s.config.RecordSession(magicType, session.RawCommand(), uint16(sshPty.Window.Height), uint16(sshPty.Window.Width))
+	defer rec.Close()
 	sigs := make(chan ssh.Signal, 1)
 	session.Signals(sigs)
 	defer func() {
@@ -710,6 +731,8 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 				}
 				// #nosec G115 - Safe conversions for terminal dimensions which are expected to be within uint16 range
 				resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
+				// #nosec G115 - Safe conversions for terminal dimensions which are expected to be within uint16 range
+				rec.Resize(uint16(win.Height), uint16(win.Width))
 				// If the pty is closed, then command has exited, no need to log.
 				if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
 					logger.Warn(ctx, "failed to resize tty", slog.Error(resizeErr))
@@ -734,7 +757,11 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	var output io.Writer = session
+	if rec != nil {
+		output = io.MultiWriter(session, rec)
+	}
+	n, err := io.Copy(output, ptty.OutputReader())
 	logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
@@ -816,6 +843,63 @@ func (s *Server) sftpHandler(logger slog.Logger, session ssh.Session) error {
 	return xerrors.Errorf("sftp server closed with error: %w", err)
 }
 
+// containerSFTPHandler serves SFTP by running the SFTP server inside the
+// container, so that files are accessed as the container user.
+func (s *Server) containerSFTPHandler(logger slog.Logger, session ssh.Session, container, containerUser string) error {
+	s.metrics.sftpConnectionsTotal.Add(1)
+
+	ctx := session.Context()
+	logger = logger.With(slog.F("container", container), slog.F("container_user", containerUser))
+
+	// See sftpHandler.
+	session.DisablePTYEmulation()
+
+	ei, err := agentcontainers.EnvInfo(ctx, s.Execer, s.config.ContainerRuntime(), container, containerUser)
+	if err != nil {
+		s.metrics.sftpServerErrors.Add(1)
+		_ = session.Exit(1)
+		return xerrors.Errorf("get container env info: %w", err)
+	}
+	name, args := ei.SFTPCommand()
+	cmd := s.Execer.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = cmdSysProcAttr()
+	cmd.Cancel = cmdCancel(ctx, logger, cmd)
+	cmd.Stdout = session
+	cmd.Stderr = session.Stderr()
+	// See startNonPTYSession.
+	stdinPipe, err := cmd.StdinPipe()
+	if err != nil {
+		s.metrics.sftpServerErrors.Add(1)
+		_ = session.Exit(1)
+		return xerrors.Errorf("create stdin pipe: %w", err)
+	}
+	go func() {
+		_, _ = io.Copy(stdinPipe, session)
+		_ = stdinPipe.Close()
+	}()
+
+	err = cmd.Run()
+	if err == nil {
+		_ = session.Exit(0)
+		return nil
+	}
+	logger.Warn(ctx, "container sftp server exited with error", slog.Error(err))
+	s.metrics.sftpServerErrors.Add(1)
+	code := 1
+	var exitErr *exec.ExitError
+	if xerrors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		code = exitErr.ExitCode()
+	}
+	_ = session.Exit(code)
+	return xerrors.Errorf("container sftp server exited with error: %w", err)
+}
+
+// ContainerRuntime returns the container runtime used to run commands in
+// containers.
+func (s *Server) ContainerRuntime() agentcontainers.Runtime {
+	return s.config.ContainerRuntime()
+}
+
 // CreateCommand processes raw command input with OpenSSH-like behavior.
 // If the script provided is empty, it will default to the users shell.
 // This injects environment variables specified by the user at launch too.
@@ -64,7 +64,7 @@ func Test_sessionStart_orphan(t *testing.T) {
 		// we don't really care what the error is here.  In the larger scenario,
 		// the client has disconnected, so we can't return any error information
 		// to them.
-		_ = s.startPTYSession(logger, sess, "ssh", cmd, ptyInfo, windowSize)
+		_ = s.startPTYSession(logger, sess, MagicSessionTypeSSH, cmd, ptyInfo, windowSize)
 	}()
 
 	readDone := make(chan struct{})
@@ -7,14 +7,19 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"os/exec"
 	"os/user"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/sftp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -27,11 +32,24 @@ import (
 
 	"github.com/coder/coder/v2/agent/agentexec"
 	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/pty"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 )
 
 func TestMain(m *testing.M) {
+	if os.Getenv(fakeSFTPServerEnv) == "1" {
+		// The test binary is run as the SFTP server of a fake container.
+		server, err := sftp.NewServer(struct {
+			io.Reader
+			io.WriteCloser
+		}{os.Stdin, os.Stdout})
+		if err != nil {
+			panic(err)
+		}
+		_ = server.Serve()
+		os.Exit(0)
+	}
 	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
 }
 
@@ -403,6 +421,147 @@ func TestNewServer_Signal(t *testing.T) {
 	})
 }
 
+const fakeSFTPServerEnv = "CODER_TEST_FAKE_SFTP_SERVER"
+
+func TestNewServer_Container(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake docker CLI is a shell script")
+	}
+
+	// A fake docker CLI which runs commands on the host, as a container
+	// whose user is the current user would.
+	dir := t.TempDir()
+	testExe, err := os.Executable()
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "sftp-server"), []byte(fmt.Sprintf("#!/bin/sh\n%s=1 exec %q\n", fakeSFTPServerEnv, testExe)), 0o755)
+	require.NoError(t, err)
+	docker := filepath.Join(dir, "docker")
+	err = os.WriteFile(docker, []byte(`#!/bin/sh
+if [ "$1" = inspect ]; then
+	echo '[{"Id": "abc", "Name": "/fake", "Config": {"Labels": {}}}]'
+	exit 0
+fi
+shift
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--interactive) shift ;;
+	--tty)
+		if [ ! -t 0 ]; then
+			echo "the input device is not a TTY" >&2
+			exit 1
+		fi
+		shift
+		;;
+	--workdir)
+		cd "$2" || exit 1
+		shift 2
+		;;
+	--user | --env) shift 2 ;;
+	*) break ;;
+	esac
+done
+shift
+PATH="`+dir+`:$PATH" exec "$@"
+`), 0o755)
+	require.NoError(t, err)
+
+	u, err := user.Current()
+	require.NoError(t, err)
+	ctx := context.Background()
+	logger := testutil.Logger(t)
+	s, err := agentssh.NewServer(ctx, logger, prometheus.NewRegistry(), afero.NewMemMapFs(), fakeDockerExecer{docker: docker}, &agentssh.Config{
+		ExperimentalDevContainersEnabled: true,
+	})
+	require.NoError(t, err)
+	err = s.UpdateHostSigner(42)
+	assert.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.Serve(ln)
+		assert.Error(t, err) // Server is closed.
+	}()
+	t.Cleanup(func() {
+		err := s.Close()
+		assert.NoError(t, err)
+		<-done
+	})
+
+	c := sshClient(t, ln.Addr().String())
+	newSession := func() *ssh.Session {
+		sess, err := c.NewSession()
+		require.NoError(t, err)
+		require.NoError(t, sess.Setenv(agentssh.ContainerEnvironmentVariable, "fake"))
+		require.NoError(t, sess.Setenv(agentssh.ContainerUserEnvironmentVariable, u.Username))
+		return sess
+	}
+
+	t.Run("Exec", func(t *testing.T) {
+		t.Parallel()
+		// Commands without a PTY, such as scp or rsync, must run
+		// without a TTY in the container.
+		data := []byte("binary\r\n\x00\x03data")
+		sess := newSession()
+		sess.Stdin = bytes.NewReader(data)
+		out, err := sess.Output("cat")
+		require.NoError(t, err)
+		require.Equal(t, data, out)
+	})
+
+	t.Run("SFTP", func(t *testing.T) {
+		t.Parallel()
+		sess := newSession()
+		w, err := sess.StdinPipe()
+		require.NoError(t, err)
+		r, err := sess.StdoutPipe()
+		require.NoError(t, err)
+		require.NoError(t, sess.RequestSubsystem("sftp"))
+		client, err := sftp.NewClientPipe(r, w)
+		require.NoError(t, err)
+		defer client.Close()
+
+		// The SFTP server runs in the home directory of the container
+		// user.
+		wd, err := client.Getwd()
+		require.NoError(t, err)
+		require.Equal(t, u.HomeDir, wd)
+
+		remoteFile := filepath.Join(t.TempDir(), "sftp")
+		file, err := client.Create(remoteFile)
+		require.NoError(t, err)
+		_, err = file.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		got, err := os.ReadFile(remoteFile)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(got))
+	})
+}
+
+// fakeDockerExecer runs the fake docker CLI instead of docker.
+type fakeDockerExecer struct {
+	docker string
+}
+
+func (e fakeDockerExecer) CommandContext(ctx context.Context, cmd string, args ...string) *exec.Cmd {
+	if cmd == "docker" {
+		cmd = e.docker
+	}
+	return agentexec.DefaultExecer.CommandContext(ctx, cmd, args...)
+}
+
+func (e fakeDockerExecer) PTYCommandContext(ctx context.Context, cmd string, args ...string) *pty.Cmd {
+	if cmd == "docker" {
+		cmd = e.docker
+	}
+	return agentexec.DefaultExecer.PTYCommandContext(ctx, cmd, args...)
+}
+
 func sshClient(t *testing.T, addr string) *ssh.Client {
 	conn, err := net.Dial("tcp", addr)
 	require.NoError(t, err)
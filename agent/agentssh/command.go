@@ -0,0 +1,32 @@
+package agentssh
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/gliderlabs/ssh"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// CreateNonInteractiveCommand creates a command for the script that runs
+// without a PTY, as for an SSH exec session. The command runs in its own
+// process group, which is sent SIGHUP when ctx is canceled.
+func (s *Server) CreateNonInteractiveCommand(ctx context.Context, logger slog.Logger, script string, env []string) (*exec.Cmd, error) {
+	ptyCmd, err := s.CreateCommand(ctx, script, env, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create command: %w", err)
+	}
+	cmd := ptyCmd.AsExec()
+	cmd.SysProcAttr = cmdSysProcAttr()
+	cmd.Cancel = cmdCancel(ctx, logger, cmd)
+	return cmd, nil
+}
+
+// OSSignal returns the signal for a name from the SSH protocol, e.g. "INT"
+// or "TERM". As for SSH sessions, unknown names are mapped to SIGKILL.
+func OSSignal(name string) os.Signal {
+	return osSignalFrom(ssh.Signal(name))
+}
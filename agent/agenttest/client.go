@@ -98,8 +98,8 @@ func (c *Client) Close() {
 	c.derpMapOnce.Do(func() { close(c.derpMapUpdates) })
 }
 
-func (c *Client) ConnectRPC26(ctx context.Context) (
-	agentproto.DRPCAgentClient26, proto.DRPCTailnetClient26, error,
+func (c *Client) ConnectRPC210(ctx context.Context) (
+	agentproto.DRPCAgentClient210, proto.DRPCTailnetClient210, error,
 ) {
 	conn, lis := drpcsdk.MemTransportPipe()
 	c.LastWorkspaceAgent = func() {
@@ -163,6 +163,14 @@ func (c *Client) GetConnectionReports() []*agentproto.ReportConnectionRequest {
 	return c.fakeAgentAPI.GetConnectionReports()
 }
 
+func (c *Client) GetSessionRecordingChunks() []*agentproto.UploadSessionRecordingRequest {
+	return c.fakeAgentAPI.GetSessionRecordingChunks()
+}
+
+func (c *Client) GetServiceUpdates() []*agentproto.BatchUpdateServicesRequest_Update {
+	return c.fakeAgentAPI.GetServiceUpdates()
+}
+
 type FakeAgentAPI struct {
 	sync.Mutex
 	t      testing.TB
@@ -177,6 +185,8 @@ type FakeAgentAPI struct {
 	metadata          map[string]agentsdk.Metadata
 	timings           []*agentproto.Timing
 	connectionReports []*agentproto.ReportConnectionRequest
+	recordingChunks   []*agentproto.UploadSessionRecordingRequest
+	serviceUpdates    []*agentproto.BatchUpdateServicesRequest_Update
 
 	getAnnouncementBannersFunc              func() ([]codersdk.BannerConfig, error)
 	getResourcesMonitoringConfigurationFunc func() (*agentproto.GetResourcesMonitoringConfigurationResponse, error)
@@ -365,6 +375,34 @@ func (f *FakeAgentAPI) GetConnectionReports() []*agentproto.ReportConnectionRequ
 	return slices.Clone(f.connectionReports)
 }
 
+func (f *FakeAgentAPI) UploadSessionRecording(_ context.Context, req *agentproto.UploadSessionRecordingRequest) (*agentproto.UploadSessionRecordingResponse, error) {
+	f.Lock()
+	f.recordingChunks = append(f.recordingChunks, req)
+	f.Unlock()
+
+	return &agentproto.UploadSessionRecordingResponse{}, nil
+}
+
+func (f *FakeAgentAPI) GetSessionRecordingChunks() []*agentproto.UploadSessionRecordingRequest {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.recordingChunks)
+}
+
+func (f *FakeAgentAPI) BatchUpdateServices(_ context.Context, req *agentproto.BatchUpdateServicesRequest) (*agentproto.BatchUpdateServicesResponse, error) {
+	f.Lock()
+	f.serviceUpdates = append(f.serviceUpdates, req.Updates...)
+	f.Unlock()
+
+	return &agentproto.BatchUpdateServicesResponse{}, nil
+}
+
+func (f *FakeAgentAPI) GetServiceUpdates() []*agentproto.BatchUpdateServicesRequest_Update {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.serviceUpdates)
+}
+
 func (*FakeAgentAPI) CreateSubAgent(_ context.Context, _ *agentproto.CreateSubAgentRequest) (*agentproto.CreateSubAgentResponse, error) {
 	panic("unimplemented")
 }
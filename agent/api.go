@@ -39,11 +39,16 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 		cacheDuration: cacheDuration,
 	}
 
+	ph := &processesHandler{
+		logger: a.logger.Named("processes"),
+	}
+
 	if a.experimentalDevcontainersEnabled {
 		containerAPIOpts := []agentcontainers.Option{
 			agentcontainers.WithExecer(a.execer),
+			agentcontainers.WithRuntime(a.containerRuntime()),
 			agentcontainers.WithScriptLogger(func(logSourceID uuid.UUID) agentcontainers.ScriptLogger {
-				return a.logSender.GetScriptLogger(logSourceID)
+				return a.scriptLogger(logSourceID)
 			}),
 		}
 		manifest := a.manifest.Load()
@@ -74,6 +79,16 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 	r.Get("/api/v0/listening-ports", lp.handler)
 	r.Get("/api/v0/netcheck", a.HandleNetcheck)
 	r.Post("/api/v0/list-directory", a.HandleLS)
+	r.Get("/api/v0/files/stat", a.HandleStatFile)
+	r.Get("/api/v0/files/download", a.reportFileTransfer(a.HandleDownloadFile))
+	r.Put("/api/v0/files/upload", a.reportFileTransfer(a.HandleUploadFile))
+	r.Get("/api/v0/exec", a.HandleExec)
+	r.Get("/api/v0/services", a.HandleServices)
+	r.Post("/api/v0/services/{service}/restart", a.HandleRestartService)
+	r.Get("/api/v0/processes", ph.handleList)
+	r.Post("/api/v0/processes/{pid}/signal", ph.handleSignal)
+	r.Get("/api/v0/reconnecting-ptys", a.HandleReconnectingPTYs)
+	r.Delete("/api/v0/reconnecting-ptys/{id}", a.HandleKillReconnectingPTY)
 	r.Get("/debug/logs", a.HandleHTTPDebugLogs)
 	r.Get("/debug/magicsock", a.HandleHTTPDebugMagicsock)
 	r.Get("/debug/magicsock/debug-logging/{state}", a.HandleHTTPMagicsockDebugLoggingState)
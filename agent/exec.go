@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleExec runs a command without a PTY over a websocket. The first
+// message is the workspacesdk.ExecRequest, the rest are frames as described
+// by workspacesdk.ExecFrameType.
+//
+// Commands are reported as SSH connections, as they are equivalent to running
+// a command over SSH. The disconnect is reported with the exit code.
+func (a *agent) HandleExec(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Report the connection attempt even if the command is never run.
+	code, reason := 1, ""
+	disconnected := a.reportConnection(uuid.New(), proto.Connection_SSH, r.RemoteAddr)
+	defer func() {
+		disconnected(code, reason)
+	}()
+
+	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		reason = "failed to accept websocket"
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	conn.SetReadLimit(workspacesdk.ExecMessageLimit)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	// The command is terminated if the client disconnects.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	typ, data, err := conn.Read(ctx)
+	if err != nil {
+		reason = "client disconnected before sending the request"
+		return
+	}
+	var req workspacesdk.ExecRequest
+	if typ != websocket.MessageText {
+		err = xerrors.New("the first message must be the request")
+	} else {
+		err = json.Unmarshal(data, &req)
+	}
+	if err != nil {
+		reason = err.Error()
+		writeExecExit(ctx, conn, workspacesdk.ExecExit{ExitCode: -1, Error: err.Error()})
+		return
+	}
+
+	logger := a.logger.Named("exec").With(slog.F("command", req.Command))
+	exitCode, err := a.runExec(ctx, cancel, logger, conn, req)
+	exit := workspacesdk.ExecExit{ExitCode: exitCode}
+	if err != nil {
+		logger.Info(ctx, "exec failed", slog.Error(err))
+		exit.Error = err.Error()
+		reason = err.Error()
+	} else {
+		logger.Debug(ctx, "exec exited", slog.F("exit_code", exitCode))
+		code = exitCode
+	}
+	writeExecExit(ctx, conn, exit)
+}
+
+// runExec runs the command and returns its exit code. An error is returned if
+// the command could not be started. cancel is called if the client
+// disconnects, which terminates the command.
+func (a *agent) runExec(ctx context.Context, cancel context.CancelFunc, logger slog.Logger, conn *websocket.Conn, req workspacesdk.ExecRequest) (int, error) {
+	cmd, err := a.sshServer.CreateNonInteractiveCommand(ctx, logger, req.Command, req.Env)
+	if err != nil {
+		return -1, err
+	}
+	if req.WorkingDirectory != "" {
+		dir := req.WorkingDirectory
+		if !filepath.IsAbs(dir) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return -1, xerrors.Errorf("get user home directory: %w", err)
+			}
+			dir = filepath.Join(home, dir)
+		}
+		cmd.Dir = dir
+	}
+	cmd.Stdout = &execWriter{ctx: ctx, conn: conn, frame: workspacesdk.ExecFrameStdout}
+	cmd.Stderr = &execWriter{ctx: ctx, conn: conn, frame: workspacesdk.ExecFrameStderr}
+	// See startNonPTYSession in agentssh for why a pipe is used.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return -1, xerrors.Errorf("create stdin pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return -1, xerrors.Errorf("start: %w", err)
+	}
+
+	go func() {
+		defer stdin.Close()
+		for {
+			typ, data, err := conn.Read(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					logger.Debug(ctx, "exec client disconnected", slog.Error(err))
+				}
+				// The client is gone, terminate the command.
+				cancel()
+				return
+			}
+			if typ != websocket.MessageBinary || len(data) == 0 {
+				continue
+			}
+			switch workspacesdk.ExecFrameType(data[0]) {
+			case workspacesdk.ExecFrameStdin:
+				if _, err := stdin.Write(data[1:]); err != nil {
+					logger.Debug(ctx, "write exec stdin", slog.Error(err))
+				}
+			case workspacesdk.ExecFrameStdinClose:
+				_ = stdin.Close()
+			case workspacesdk.ExecFrameSignal:
+				sig := agentssh.OSSignal(string(data[1:]))
+				logger.Info(ctx, "received signal from client", slog.F("signal", sig.String()))
+				if err := cmd.Process.Signal(sig); err != nil {
+					logger.Warn(ctx, "signaling the process failed", slog.Error(err))
+				}
+			default:
+			}
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if xerrors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code == -1 {
+			// Terminated by a signal, use the same exit code as SSH
+			// sessions.
+			code = 255
+		}
+		return code, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+func writeExecExit(ctx context.Context, conn *websocket.Conn, exit workspacesdk.ExecExit) {
+	data, err := json.Marshal(exit)
+	if err != nil {
+		return
+	}
+	_ = conn.Write(ctx, websocket.MessageBinary, workspacesdk.ExecFrame(workspacesdk.ExecFrameExit, data))
+}
+
+// execWriter writes output of a command as frames of the type.
+type execWriter struct {
+	ctx   context.Context
+	conn  *websocket.Conn
+	frame workspacesdk.ExecFrameType
+}
+
+func (w *execWriter) Write(p []byte) (int, error) {
+	if err := w.conn.Write(w.ctx, websocket.MessageBinary, workspacesdk.ExecFrame(w.frame, p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+var _ io.Writer = (*execWriter)(nil)
@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleStatFile returns information about the file at the path in the
+// query.
+func (a *agent) HandleStatFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	path, ok := a.filePath(rw, r)
+	if !ok {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, fileInfo(path, info))
+}
+
+// HandleDownloadFile streams the file at the path in the query. Directories
+// are streamed as a tar archive.
+func (a *agent) HandleDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	path, ok := a.filePath(rw, r)
+	if !ok {
+		return
+	}
+	// codeql[go/path-injection] - The intent is to allow the user to read any file in their workspace.
+	f, err := os.Open(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	if info.IsDir() {
+		rw.Header().Set("Content-Type", codersdk.ContentTypeTar)
+		rw.WriteHeader(http.StatusOK)
+		// The status has been written, so errors can only be surfaced to
+		// the client by aborting the stream.
+		if err := agentfiles.Tar(rw, path); err != nil {
+			a.logger.Warn(ctx, "stream directory", slog.F("path", path), slog.Error(err))
+			panic(http.ErrAbortHandler)
+		}
+		return
+	}
+	if !info.Mode().IsRegular() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is not a regular file or directory.", path),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	rw.WriteHeader(http.StatusOK)
+	if _, err := io.CopyN(rw, f, info.Size()); err != nil {
+		a.logger.Debug(ctx, "stream file", slog.F("path", path), slog.Error(err))
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// HandleUploadFile writes the request body to the path in the query. If the
+// body is a tar archive, it is extracted into the directory at the path.
+func (a *agent) HandleUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	path, ok := a.filePath(rw, r)
+	if !ok {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == codersdk.ContentTypeTar {
+		if err := agentfiles.Untar(path, r.Body); err != nil {
+			writeFileError(rw, r, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	mode := os.FileMode(0o644)
+	if raw := r.URL.Query().Get("mode"); raw != "" {
+		m, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil || os.FileMode(m) != os.FileMode(m).Perm() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid file mode.",
+				Detail:  fmt.Sprintf("%q must be an octal permission, e.g. 644.", raw),
+			})
+			return
+		}
+		mode = os.FileMode(m)
+	}
+	if err := writeUploadedFile(path, mode, r.Body); err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// reportFileTransfer reports file transfers as SSH connections, as transfers
+// over SFTP or scp are. Failed transfers are disconnected with status 1.
+func (a *agent) reportFileTransfer(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Transfers that panic are aborted streams.
+		code, reason := 1, "file transfer aborted"
+		disconnected := a.reportConnection(uuid.New(), proto.Connection_SSH, r.RemoteAddr)
+		defer func() {
+			disconnected(code, reason)
+		}()
+
+		sw := &statusWriter{ResponseWriter: rw}
+		next(sw, r)
+		if sw.status >= http.StatusBadRequest {
+			reason = fmt.Sprintf("file transfer failed with status %d", sw.status)
+			return
+		}
+		code, reason = 0, ""
+	}
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// filePath returns the absolute path in the query. Relative paths are
+// relative to the home directory of the user, as with scp.
+func (a *agent) filePath(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	ctx := r.Context()
+
+	if a.blockFileTransfer {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "File transfer has been disabled.",
+		})
+		return "", false
+	}
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The path query parameter is required.",
+		})
+		return "", false
+	}
+	if !filepath.IsAbs(path) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get user home directory.",
+				Detail:  err.Error(),
+			})
+			return "", false
+		}
+		path = filepath.Join(home, path)
+	}
+	return filepath.Clean(path), true
+}
+
+func writeUploadedFile(path string, mode os.FileMode, r io.Reader) error {
+	// codeql[go/path-injection] - The intent is to allow the user to write any file in their workspace.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("write file %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// The mode is only applied when the file is created.
+	return os.Chmod(path, mode)
+}
+
+func writeFileError(rw http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, os.ErrPermission):
+		status = http.StatusForbidden
+	default:
+	}
+	httpapi.Write(r.Context(), rw, status, codersdk.Response{
+		Message: err.Error(),
+	})
+}
+
+func fileInfo(path string, info os.FileInfo) workspacesdk.AgentFileInfo {
+	return workspacesdk.AgentFileInfo{
+		Path:    path,
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		IsDir:   info.IsDir(),
+		ModTime: info.ModTime(),
+	}
+}
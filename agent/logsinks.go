@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/agent/agentlogsink"
+	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// scriptLogger returns the logger for the output of scripts and services
+// with the log source. The output is sent to coderd and, if enabled, to the
+// log sinks.
+func (a *agent) scriptLogger(logSourceID uuid.UUID) agentscripts.ScriptLogger {
+	logger := a.logSender.GetScriptLogger(logSourceID)
+	if !a.logShipper.Enabled(agentlogsink.SourceScript) {
+		return logger
+	}
+	attrs := map[string]string{
+		"coder.log_source.id": logSourceID.String(),
+	}
+	if name := a.logSourceName(logSourceID); name != "" {
+		attrs["coder.log_source.name"] = name
+	}
+	return &shippingScriptLogger{
+		ScriptLogger: logger,
+		shipper:      a.logShipper,
+		attrs:        attrs,
+	}
+}
+
+// logSourceName returns the display name of the script or the name of the
+// service that logs with the log source.
+func (a *agent) logSourceName(logSourceID uuid.UUID) string {
+	manifest := a.manifest.Load()
+	if manifest == nil {
+		return ""
+	}
+	for _, script := range manifest.Scripts {
+		if script.LogSourceID == logSourceID {
+			return script.DisplayName
+		}
+	}
+	for _, service := range manifest.Services {
+		if service.LogSourceID == logSourceID {
+			return service.Name
+		}
+	}
+	return ""
+}
+
+// setLogSinkResource describes the workspace agent in the records shipped to
+// the log sinks.
+func (a *agent) setLogSinkResource(manifest agentsdk.Manifest) {
+	if a.logShipper == nil {
+		return
+	}
+	a.logShipper.SetResource(map[string]string{
+		"coder.agent.id":        manifest.AgentID.String(),
+		"coder.agent.name":      manifest.AgentName,
+		"coder.workspace.id":    manifest.WorkspaceID.String(),
+		"coder.workspace.name":  manifest.WorkspaceName,
+		"coder.workspace.owner": manifest.OwnerName,
+	})
+}
+
+// shipConnection ships a connection event to the log sinks.
+func (a *agent) shipConnection(conn *proto.Connection) {
+	if !a.logShipper.Enabled(agentlogsink.SourceConnection) {
+		return
+	}
+	id, _ := uuid.FromBytes(conn.GetId())
+	attrs := map[string]string{
+		"coder.connection.id":     id.String(),
+		"coder.connection.type":   connectionTypeName(conn.GetType()),
+		"coder.connection.action": connectionActionName(conn.GetAction()),
+		"client.address":          conn.GetIp(),
+	}
+	level := codersdk.LogLevelInfo
+	message := "connection established"
+	if conn.GetAction() == proto.Connection_DISCONNECT {
+		message = "connection closed"
+		attrs["coder.connection.status_code"] = strconv.Itoa(int(conn.GetStatusCode()))
+		if conn.GetReason() != "" {
+			attrs["coder.connection.reason"] = conn.GetReason()
+		}
+		if conn.GetStatusCode() != 0 {
+			level = codersdk.LogLevelWarn
+		}
+	}
+	a.logShipper.Ship(agentlogsink.Record{
+		Time:       conn.GetTimestamp().AsTime(),
+		Source:     agentlogsink.SourceConnection,
+		Level:      level,
+		Message:    message,
+		Attributes: attrs,
+	})
+}
+
+func connectionTypeName(t proto.Connection_Type) string {
+	switch t {
+	case proto.Connection_SSH:
+		return "ssh"
+	case proto.Connection_VSCODE:
+		return "vscode"
+	case proto.Connection_JETBRAINS:
+		return "jetbrains"
+	case proto.Connection_RECONNECTING_PTY:
+		return "reconnecting_pty"
+	default:
+		return "unknown"
+	}
+}
+
+func connectionActionName(action proto.Connection_Action) string {
+	switch action {
+	case proto.Connection_CONNECT:
+		return "connect"
+	case proto.Connection_DISCONNECT:
+		return "disconnect"
+	default:
+		return "unknown"
+	}
+}
+
+// shippingScriptLogger ships the output of a script to the log sinks in
+// addition to sending it to coderd.
+type shippingScriptLogger struct {
+	agentscripts.ScriptLogger
+	shipper *agentlogsink.Shipper
+	attrs   map[string]string
+}
+
+func (l *shippingScriptLogger) Send(ctx context.Context, logs ...agentsdk.Log) error {
+	for _, log := range logs {
+		l.shipper.Ship(agentlogsink.Record{
+			Time:       log.CreatedAt,
+			Source:     agentlogsink.SourceScript,
+			Level:      log.Level,
+			Message:    log.Output,
+			Attributes: l.attrs,
+		})
+	}
+	return l.ScriptLogger.Send(ctx, logs...)
+}
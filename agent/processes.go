@@ -0,0 +1,217 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// processSignals maps the signals that can be sent to processes to their
+// values. These signals exist on all platforms, although only KILL is
+// supported on Windows.
+var processSignals = map[workspacesdk.AgentProcessSignal]syscall.Signal{
+	workspacesdk.AgentProcessSignalHUP:  syscall.SIGHUP,
+	workspacesdk.AgentProcessSignalINT:  syscall.SIGINT,
+	workspacesdk.AgentProcessSignalQUIT: syscall.SIGQUIT,
+	workspacesdk.AgentProcessSignalKILL: syscall.SIGKILL,
+	workspacesdk.AgentProcessSignalTERM: syscall.SIGTERM,
+}
+
+// processesHandler lists the processes in the workspace. The CPU times of
+// the previous listing are kept to report the recent CPU usage, like top.
+type processesHandler struct {
+	logger slog.Logger
+
+	mu          sync.Mutex
+	lastSampled time.Time
+	// lastCPU is the total CPU time in seconds of processes, keyed by PID
+	// and the creation time to tell reused PIDs apart.
+	lastCPU map[processKey]float64
+}
+
+type processKey struct {
+	pid       int32
+	createdAt int64
+}
+
+func (h *processesHandler) handleList(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	processes, err := h.list(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Could not list processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.AgentProcessesResponse{
+		Processes: processes,
+	})
+}
+
+func (h *processesHandler) list(ctx context.Context) ([]workspacesdk.AgentProcess, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("list processes: %w", err)
+	}
+	ports := h.listeningPorts(ctx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(h.lastSampled).Seconds()
+	cpu := make(map[processKey]float64, len(procs))
+	processes := make([]workspacesdk.AgentProcess, 0, len(procs))
+	for _, p := range procs {
+		// Processes may exit while they are inspected, and some details
+		// can't be read without privileges, so every detail is best effort.
+		createdAt, err := p.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		proc := workspacesdk.AgentProcess{
+			PID:            p.Pid,
+			StartedAt:      time.UnixMilli(createdAt),
+			ListeningPorts: ports[p.Pid],
+		}
+		proc.PPID, _ = p.PpidWithContext(ctx)
+		proc.User, _ = p.UsernameWithContext(ctx)
+		proc.Name, _ = p.NameWithContext(ctx)
+		proc.Cmdline, _ = p.CmdlineWithContext(ctx)
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			proc.MemoryRSS = mem.RSS
+		}
+		if times, err := p.TimesWithContext(ctx); err == nil {
+			key := processKey{pid: p.Pid, createdAt: createdAt}
+			total := times.User + times.System
+			cpu[key] = total
+			if last, ok := h.lastCPU[key]; ok && elapsed > 0 {
+				proc.CPUPercent = max(total-last, 0) / elapsed * 100
+			} else if running := now.Sub(proc.StartedAt).Seconds(); running > 0 {
+				proc.CPUPercent = total / running * 100
+			}
+		}
+		processes = append(processes, proc)
+	}
+	h.lastSampled = now
+	h.lastCPU = cpu
+
+	slices.SortFunc(processes, func(a, b workspacesdk.AgentProcess) int {
+		return int(a.PID - b.PID)
+	})
+	return processes, nil
+}
+
+// listeningPorts returns the TCP ports that processes listen on, keyed by
+// PID. Ports are omitted on platforms where they can't be inspected.
+func (h *processesHandler) listeningPorts(ctx context.Context) map[int32][]uint16 {
+	conns, err := psnet.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		h.logger.Debug(ctx, "list listening ports of processes", slog.Error(err))
+		return nil
+	}
+	ports := make(map[int32][]uint16)
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || conn.Pid == 0 {
+			continue
+		}
+		port := uint16(conn.Laddr.Port) //nolint:gosec // Ports are at most 65535.
+		// Sockets that listen on IPv4 and IPv6 are listed twice.
+		if !slices.Contains(ports[conn.Pid], port) {
+			ports[conn.Pid] = append(ports[conn.Pid], port)
+		}
+	}
+	for _, p := range ports {
+		slices.Sort(p)
+	}
+	return ports
+}
+
+func (h *processesHandler) handleSignal(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pid, err := strconv.ParseInt(chi.URLParam(r, "pid"), 10, 32)
+	if err != nil || pid <= 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid process ID.",
+		})
+		return
+	}
+	var req workspacesdk.SignalProcessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	sig, ok := processSignals[req.Signal]
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Unsupported signal %q.", req.Signal),
+		})
+		return
+	}
+	if int(pid) == os.Getpid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The agent process can't be signaled.",
+			Detail:  "Restart the workspace to restart the agent.",
+		})
+		return
+	}
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if errors.Is(err, process.ErrorProcessNotRunning) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Process %d not found.", pid),
+		})
+		return
+	}
+	if err == nil {
+		if sig == syscall.SIGKILL {
+			// SendSignal isn't supported on Windows, but Kill is.
+			err = p.KillWithContext(ctx)
+		} else {
+			err = p.SendSignalWithContext(ctx, sig)
+		}
+	}
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Not permitted to signal process %d.", pid),
+			Detail:  err.Error(),
+		})
+		return
+	case errors.Is(err, os.ErrProcessDone):
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Process %d not found.", pid),
+		})
+		return
+	case err != nil:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Could not signal process %d.", pid),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info(ctx, "signaled process", slog.F("pid", pid), slog.F("signal", req.Signal))
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: fmt.Sprintf("Sent %s to process %d.", req.Signal, pid),
+	})
+}
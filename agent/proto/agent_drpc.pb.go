@@ -55,6 +55,8 @@ type DRPCAgentClient interface {
 	CreateSubAgent(ctx context.Context, in *CreateSubAgentRequest) (*CreateSubAgentResponse, error)
 	DeleteSubAgent(ctx context.Context, in *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(ctx context.Context, in *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
+	UploadSessionRecording(ctx context.Context, in *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error)
+	BatchUpdateServices(ctx context.Context, in *BatchUpdateServicesRequest) (*BatchUpdateServicesResponse, error)
 }
 
 type drpcAgentClient struct {
@@ -211,6 +213,24 @@ func (c *drpcAgentClient) ListSubAgents(ctx context.Context, in *ListSubAgentsRe
 	return out, nil
 }
 
+func (c *drpcAgentClient) UploadSessionRecording(ctx context.Context, in *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error) {
+	out := new(UploadSessionRecordingResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/UploadSessionRecording", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcAgentClient) BatchUpdateServices(ctx context.Context, in *BatchUpdateServicesRequest) (*BatchUpdateServicesResponse, error) {
+	out := new(BatchUpdateServicesResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/BatchUpdateServices", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCAgentServer interface {
 	GetManifest(context.Context, *GetManifestRequest) (*Manifest, error)
 	GetServiceBanner(context.Context, *GetServiceBannerRequest) (*ServiceBanner, error)
@@ -228,6 +248,8 @@ type DRPCAgentServer interface {
 	CreateSubAgent(context.Context, *CreateSubAgentRequest) (*CreateSubAgentResponse, error)
 	DeleteSubAgent(context.Context, *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(context.Context, *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
+	UploadSessionRecording(context.Context, *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error)
+	BatchUpdateServices(context.Context, *BatchUpdateServicesRequest) (*BatchUpdateServicesResponse, error)
 }
 
 type DRPCAgentUnimplementedServer struct{}
@@ -296,9 +318,17 @@ func (s *DRPCAgentUnimplementedServer) ListSubAgents(context.Context, *ListSubAg
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCAgentUnimplementedServer) UploadSessionRecording(context.Context, *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCAgentUnimplementedServer) BatchUpdateServices(context.Context, *BatchUpdateServicesRequest) (*BatchUpdateServicesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCAgentDescription struct{}
 
-func (DRPCAgentDescription) NumMethods() int { return 16 }
+func (DRPCAgentDescription) NumMethods() int { return 18 }
 
 func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -446,6 +476,24 @@ func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*ListSubAgentsRequest),
 					)
 			}, DRPCAgentServer.ListSubAgents, true
+	case 16:
+		return "/coder.agent.v2.Agent/UploadSessionRecording", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					UploadSessionRecording(
+						ctx,
+						in1.(*UploadSessionRecordingRequest),
+					)
+			}, DRPCAgentServer.UploadSessionRecording, true
+	case 17:
+		return "/coder.agent.v2.Agent/BatchUpdateServices", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					BatchUpdateServices(
+						ctx,
+						in1.(*BatchUpdateServicesRequest),
+					)
+			}, DRPCAgentServer.BatchUpdateServices, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -710,3 +758,35 @@ func (x *drpcAgent_ListSubAgentsStream) SendAndClose(m *ListSubAgentsResponse) e
 	}
 	return x.CloseSend()
 }
+
+type DRPCAgent_UploadSessionRecordingStream interface {
+	drpc.Stream
+	SendAndClose(*UploadSessionRecordingResponse) error
+}
+
+type drpcAgent_UploadSessionRecordingStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_UploadSessionRecordingStream) SendAndClose(m *UploadSessionRecordingResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCAgent_BatchUpdateServicesStream interface {
+	drpc.Stream
+	SendAndClose(*BatchUpdateServicesResponse) error
+}
+
+type drpcAgent_BatchUpdateServicesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_BatchUpdateServicesStream) SendAndClose(m *BatchUpdateServicesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
@@ -65,3 +65,30 @@ type DRPCAgentClient26 interface {
 	DeleteSubAgent(ctx context.Context, in *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(ctx context.Context, in *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
 }
+
+// DRPCAgentClient27 is the Agent API at v2.7. It adds the Secrets field to the
+// agent manifest response.
+type DRPCAgentClient27 interface {
+	DRPCAgentClient26
+}
+
+// DRPCAgentClient28 is the Agent API at v2.8. It adds the
+// SessionRecordingEnabled field to the agent manifest response and the
+// UploadSessionRecording RPC.
+type DRPCAgentClient28 interface {
+	DRPCAgentClient27
+	UploadSessionRecording(ctx context.Context, in *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error)
+}
+
+// DRPCAgentClient29 is the Agent API at v2.9. It adds the CPU, load average,
+// inodes and process count monitors to the resources monitoring RPCs.
+type DRPCAgentClient29 interface {
+	DRPCAgentClient28
+}
+
+// DRPCAgentClient210 is the Agent API at v2.10. It adds the Services field to
+// the agent manifest response and the BatchUpdateServices RPC.
+type DRPCAgentClient210 interface {
+	DRPCAgentClient29
+	BatchUpdateServices(ctx context.Context, in *BatchUpdateServicesRequest) (*BatchUpdateServicesResponse, error)
+}
@@ -1,6 +1,11 @@
 package resourcesmonitor
 
 import (
+	"runtime"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/process"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/clistat"
@@ -8,6 +13,8 @@ import (
 
 type Statter interface {
 	IsContainerized() (bool, error)
+	ContainerCPU() (*clistat.Result, error)
+	HostCPU() (*clistat.Result, error)
 	ContainerMemory(p clistat.Prefix) (*clistat.Result, error)
 	HostMemory(p clistat.Prefix) (*clistat.Result, error)
 	Disk(p clistat.Prefix, path string) (*clistat.Result, error)
@@ -16,6 +23,11 @@ type Statter interface {
 type Fetcher interface {
 	FetchMemory() (total int64, used int64, err error)
 	FetchVolume(volume string) (total int64, used int64, err error)
+	// FetchCPU returns the available and used CPU in cores.
+	FetchCPU() (total float64, used float64, err error)
+	FetchLoad() (load1 float64, load5 float64, load15 float64, cores int32, err error)
+	FetchInodes(volume string) (total int64, used int64, err error)
+	FetchProcesses() (count int64, err error)
 }
 
 type fetcher struct {
@@ -79,3 +91,75 @@ func (f *fetcher) FetchVolume(volume string) (total int64, used int64, err error
 
 	return int64(*vol.Total), int64(vol.Used), nil
 }
+
+func (f *fetcher) FetchCPU() (total float64, used float64, err error) {
+	var cpu *clistat.Result
+
+	if f.isContainerized {
+		cpu, err = f.ContainerCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch container cpu: %w", err)
+		}
+
+		// As for memory, a container without a CPU limit can use all
+		// of the host's CPU.
+		if cpu != nil && cpu.Total == nil {
+			hostCPU, err := f.HostCPU()
+			if err != nil {
+				return 0, 0, xerrors.Errorf("fetch host cpu: %w", err)
+			}
+
+			cpu.Total = hostCPU.Total
+		}
+	}
+	if cpu == nil {
+		cpu, err = f.HostCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch host cpu: %w", err)
+		}
+	}
+
+	if cpu.Total == nil {
+		return 0, 0, xerrors.New("cpu total is nil - can not fetch cpu")
+	}
+
+	return *cpu.Total, cpu.Used, nil
+}
+
+// FetchLoad returns the load averages of the host and its number of cores.
+// Load averages are not namespaced, so they are those of the host in
+// containers too.
+func (*fetcher) FetchLoad() (load1 float64, load5 float64, load15 float64, cores int32, err error) {
+	avg, err := load.Avg()
+	if err != nil {
+		return 0, 0, 0, 0, xerrors.Errorf("fetch load average: %w", err)
+	}
+
+	// #nosec G115 - the number of cores fits in an int32.
+	return avg.Load1, avg.Load5, avg.Load15, int32(runtime.NumCPU()), nil
+}
+
+func (*fetcher) FetchInodes(volume string) (total int64, used int64, err error) {
+	usage, err := disk.Usage(volume)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch inodes: %w", err)
+	}
+
+	if usage.InodesTotal == 0 {
+		// Some filesystems, e.g. btrfs, allocate inodes dynamically and
+		// can not run out of them.
+		return 0, 0, xerrors.Errorf("volume %q does not report inodes", volume)
+	}
+
+	// #nosec G115 - inode counts fit in an int64.
+	return int64(usage.InodesTotal), int64(usage.InodesUsed), nil
+}
+
+func (*fetcher) FetchProcesses() (count int64, err error) {
+	pids, err := process.Pids()
+	if err != nil {
+		return 0, xerrors.Errorf("fetch processes: %w", err)
+	}
+
+	return int64(len(pids)), nil
+}
@@ -13,6 +13,8 @@ import (
 
 type mockStatter struct {
 	isContainerized bool
+	containerCPU    clistat.Result
+	hostCPU         clistat.Result
 	containerMemory clistat.Result
 	hostMemory      clistat.Result
 	disk            map[string]clistat.Result
@@ -22,6 +24,14 @@ func (s *mockStatter) IsContainerized() (bool, error) {
 	return s.isContainerized, nil
 }
 
+func (s *mockStatter) ContainerCPU() (*clistat.Result, error) {
+	return &s.containerCPU, nil
+}
+
+func (s *mockStatter) HostCPU() (*clistat.Result, error) {
+	return &s.hostCPU, nil
+}
+
 func (s *mockStatter) ContainerMemory(_ clistat.Prefix) (*clistat.Result, error) {
 	return &s.containerMemory, nil
 }
@@ -107,3 +117,73 @@ func TestFetchMemory(t *testing.T) {
 		require.Equal(t, int64(30), total)
 	})
 }
+
+func TestFetchCPU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IsContainerized", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("WithCPULimit", func(t *testing.T) {
+			t.Parallel()
+
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+				isContainerized: true,
+				containerCPU: clistat.Result{
+					Used:  1.5,
+					Total: ptr.Ref(2.0),
+				},
+				hostCPU: clistat.Result{
+					Used:  3.0,
+					Total: ptr.Ref(8.0),
+				},
+			})
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchCPU()
+			require.NoError(t, err)
+			require.Equal(t, 1.5, used)
+			require.Equal(t, 2.0, total)
+		})
+
+		t.Run("WithoutCPULimit", func(t *testing.T) {
+			t.Parallel()
+
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+				isContainerized: true,
+				containerCPU: clistat.Result{
+					Used:  1.5,
+					Total: nil,
+				},
+				hostCPU: clistat.Result{
+					Used:  3.0,
+					Total: ptr.Ref(8.0),
+				},
+			})
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchCPU()
+			require.NoError(t, err)
+			require.Equal(t, 1.5, used)
+			require.Equal(t, 8.0, total)
+		})
+	})
+
+	t.Run("IsHost", func(t *testing.T) {
+		t.Parallel()
+
+		fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+			isContainerized: false,
+			hostCPU: clistat.Result{
+				Used:  3.0,
+				Total: ptr.Ref(8.0),
+			},
+		})
+		require.NoError(t, err)
+
+		total, used, err := fetcher.FetchCPU()
+		require.NoError(t, err)
+		require.Equal(t, 3.0, used)
+		require.Equal(t, 8.0, total)
+	})
+}
@@ -12,6 +12,10 @@ type Datapoint struct {
 	CollectedAt time.Time
 	Memory      *MemoryDatapoint
 	Volumes     []*VolumeDatapoint
+	CPU         *CPUDatapoint
+	Load        *LoadDatapoint
+	Inodes      []*InodesDatapoint
+	Processes   *ProcessesDatapoint
 }
 
 type MemoryDatapoint struct {
@@ -25,6 +29,29 @@ type VolumeDatapoint struct {
 	Used  int64
 }
 
+// CPUDatapoint is in cores.
+type CPUDatapoint struct {
+	Total float64
+	Used  float64
+}
+
+type LoadDatapoint struct {
+	Load1  float64
+	Load5  float64
+	Load15 float64
+	Cores  int32
+}
+
+type InodesDatapoint struct {
+	Path  string
+	Total int64
+	Used  int64
+}
+
+type ProcessesDatapoint struct {
+	Count int64
+}
+
 // Queue represents a FIFO queue with a fixed size
 type Queue struct {
 	items []Datapoint
@@ -78,6 +105,36 @@ func (q *Queue) ItemsAsProto() []*proto.PushResourcesMonitoringUsageRequest_Data
 			})
 		}
 
+		if item.CPU != nil {
+			protoItem.Cpu = &proto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{
+				Total: item.CPU.Total,
+				Used:  item.CPU.Used,
+			}
+		}
+
+		if item.Load != nil {
+			protoItem.Load = &proto.PushResourcesMonitoringUsageRequest_Datapoint_LoadAverage{
+				Load1:  item.Load.Load1,
+				Load5:  item.Load.Load5,
+				Load15: item.Load.Load15,
+				Cores:  item.Load.Cores,
+			}
+		}
+
+		for _, inodes := range item.Inodes {
+			protoItem.Inodes = append(protoItem.Inodes, &proto.PushResourcesMonitoringUsageRequest_Datapoint_InodesUsage{
+				Volume: inodes.Path,
+				Total:  inodes.Total,
+				Used:   inodes.Used,
+			})
+		}
+
+		if item.Processes != nil {
+			protoItem.Processes = &proto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{
+				Count: item.Processes.Count,
+			}
+		}
+
 		items = append(items, protoItem)
 	}
 
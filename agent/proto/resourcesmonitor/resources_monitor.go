@@ -71,6 +71,61 @@ func (m *monitor) Start(ctx context.Context) error {
 			})
 		}
 
+		if m.config.Cpu != nil && m.config.Cpu.Enabled {
+			cpuTotal, cpuUsed, err := m.resourcesFetcher.FetchCPU()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch cpu", slog.Error(err))
+			} else {
+				datapoint.CPU = &CPUDatapoint{
+					Total: cpuTotal,
+					Used:  cpuUsed,
+				}
+			}
+		}
+
+		if m.config.Load != nil && m.config.Load.Enabled {
+			load1, load5, load15, cores, err := m.resourcesFetcher.FetchLoad()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch load average", slog.Error(err))
+			} else {
+				datapoint.Load = &LoadDatapoint{
+					Load1:  load1,
+					Load5:  load5,
+					Load15: load15,
+					Cores:  cores,
+				}
+			}
+		}
+
+		for _, inodes := range m.config.Inodes {
+			if !inodes.Enabled {
+				continue
+			}
+
+			inodesTotal, inodesUsed, err := m.resourcesFetcher.FetchInodes(inodes.Path)
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch inodes", slog.Error(err))
+				continue
+			}
+
+			datapoint.Inodes = append(datapoint.Inodes, &InodesDatapoint{
+				Path:  inodes.Path,
+				Total: inodesTotal,
+				Used:  inodesUsed,
+			})
+		}
+
+		if m.config.Processes != nil && m.config.Processes.Enabled {
+			count, err := m.resourcesFetcher.FetchProcesses()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch processes", slog.Error(err))
+			} else {
+				datapoint.Processes = &ProcessesDatapoint{
+					Count: count,
+				}
+			}
+		}
+
 		m.queue.Push(datapoint)
 
 		if m.queue.IsFull() {
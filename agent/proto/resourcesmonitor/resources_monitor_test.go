@@ -29,6 +29,14 @@ type fetcher struct {
 	totalVolume int64
 	usedVolume  int64
 
+	totalCPU    float64
+	usedCPU     float64
+	load1       float64
+	cores       int32
+	totalInodes int64
+	usedInodes  int64
+	processes   int64
+
 	errMemory error
 	errVolume error
 }
@@ -41,6 +49,22 @@ func (r *fetcher) FetchVolume(_ string) (total int64, used int64, err error) {
 	return r.totalVolume, r.usedVolume, r.errVolume
 }
 
+func (r *fetcher) FetchCPU() (total float64, used float64, err error) {
+	return r.totalCPU, r.usedCPU, nil
+}
+
+func (r *fetcher) FetchLoad() (load1 float64, load5 float64, load15 float64, cores int32, err error) {
+	return r.load1, r.load1, r.load1, r.cores, nil
+}
+
+func (r *fetcher) FetchInodes(_ string) (total int64, used int64, err error) {
+	return r.totalInodes, r.usedInodes, nil
+}
+
+func (r *fetcher) FetchProcesses() (count int64, err error) {
+	return r.processes, nil
+}
+
 func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -192,6 +216,64 @@ func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 			},
 			numTicks: 20,
 		},
+		{
+			name: "SuccessfulMonitoringAllResources",
+			config: &proto.GetResourcesMonitoringConfigurationResponse{
+				Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
+					NumDatapoints:             20,
+					CollectionIntervalSeconds: 1,
+				},
+				Cpu: &proto.GetResourcesMonitoringConfigurationResponse_CPU{
+					Enabled: true,
+				},
+				Load: &proto.GetResourcesMonitoringConfigurationResponse_Load{
+					Enabled: true,
+				},
+				Inodes: []*proto.GetResourcesMonitoringConfigurationResponse_Inodes{
+					{
+						Enabled: true,
+						Path:    "/",
+					},
+				},
+				Processes: &proto.GetResourcesMonitoringConfigurationResponse_Processes{
+					Enabled: true,
+				},
+			},
+			datapointsPusher: func(_ context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
+				require.Len(t, req.Datapoints, 20)
+				require.Nil(t, req.Datapoints[0].Memory)
+				require.Equal(t, &proto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{
+					Total: 4,
+					Used:  3,
+				}, req.Datapoints[0].Cpu)
+				require.Equal(t, &proto.PushResourcesMonitoringUsageRequest_Datapoint_LoadAverage{
+					Load1:  6,
+					Load5:  6,
+					Load15: 6,
+					Cores:  4,
+				}, req.Datapoints[0].Load)
+				require.Equal(t, []*proto.PushResourcesMonitoringUsageRequest_Datapoint_InodesUsage{{
+					Volume: "/",
+					Total:  1000,
+					Used:   900,
+				}}, req.Datapoints[0].Inodes)
+				require.Equal(t, &proto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{
+					Count: 250,
+				}, req.Datapoints[0].Processes)
+
+				return &proto.PushResourcesMonitoringUsageResponse{}, nil
+			},
+			fetcher: &fetcher{
+				totalCPU:    4,
+				usedCPU:     3,
+				load1:       6,
+				cores:       4,
+				totalInodes: 1000,
+				usedInodes:  900,
+				processes:   250,
+			},
+			numTicks: 20,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleReconnectingPTYs returns the reconnecting PTY sessions.
+func (a *agent) HandleReconnectingPTYs(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, workspacesdk.ReconnectingPTYSessionsResponse{
+		Sessions: a.reconnectingPTYServer.Sessions(),
+	})
+}
+
+// HandleKillReconnectingPTY kills the process of a reconnecting PTY session.
+func (a *agent) HandleKillReconnectingPTY(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid reconnecting PTY ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !a.reconnectingPTYServer.Kill(id) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Reconnecting PTY %s not found.", id),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: fmt.Sprintf("Killed reconnecting PTY %s.", id),
+	})
+}
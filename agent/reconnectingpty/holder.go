@@ -0,0 +1,483 @@
+package reconnectingpty
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/pty"
+)
+
+// HolderCommand is the hidden subcommand of the coder binary that runs the
+// holder of a persistent reconnecting pty.
+const HolderCommand = "agent-pty-holder"
+
+const (
+	// holderSocket is the unix socket the holder listens on.
+	holderSocket = "sock"
+	// holderScrollback is the file the holder writes the pty output to.
+	holderScrollback = "scrollback"
+	// holderSession describes the session, it is read when the pty is
+	// adopted by an agent.
+	holderSession = "session.json"
+	// holderLog is the output of the holder itself.
+	holderLog = "holder.log"
+
+	// scrollbackSize is the amount of output that is replayed on attach.  The
+	// scrollback file is compacted when it grows to twice this size.
+	scrollbackSize = 256 << 10
+	// holderConnBuffer is the number of output chunks queued for an attached
+	// connection.  A connection that falls further behind is closed so it
+	// cannot hold up the output of the others, it can reattach to replay the
+	// scrollback.
+	holderConnBuffer = 256
+)
+
+// Frames sent to the holder are a type byte, a big endian uint32 length and
+// the payload.  The holder only sends the raw pty output.
+const (
+	// frameAttach is the first frame of a connection that attaches to the pty
+	// with the height and width as payload.
+	frameAttach byte = iota + 1
+	// frameWatch is the first frame of the agent connection that is closed
+	// when the holder exits.  It does not keep the pty alive.
+	frameWatch
+	frameInput
+	frameResize
+	frameKill
+)
+
+// holderSpec is the command the holder runs, it is written to the stdin of
+// the holder so the environment is not persisted.
+type holderSpec struct {
+	Path    string        `json:"path"`
+	Args    []string      `json:"args"`
+	Env     []string      `json:"env"`
+	Dir     string        `json:"dir"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+// HolderCLI runs the holder of a persistent reconnecting pty.  It should only
+// be called by the main package.
+func HolderCLI() error {
+	if len(os.Args) != 3 {
+		return xerrors.Errorf("usage: coder %s <dir>", HolderCommand)
+	}
+	dir := os.Args[2]
+
+	var spec holderSpec
+	err := json.NewDecoder(os.Stdin).Decode(&spec)
+	if err != nil {
+		return xerrors.Errorf("read command: %w", err)
+	}
+	logger := slog.Make(sloghuman.Sink(os.Stderr)).Leveled(slog.LevelDebug)
+	return runHolder(context.Background(), logger, dir, spec)
+}
+
+// holder owns the pty of a persistent reconnecting pty so it outlives the
+// agent.  It exits, removing its directory, when the process exits, when it is
+// killed or when nothing is attached for the timeout.
+type holder struct {
+	logger  slog.Logger
+	dir     string
+	timeout time.Duration
+
+	ptty     pty.PTYCmd
+	process  pty.Process
+	listener net.Listener
+
+	mu         sync.Mutex
+	scrollback *scrollback
+	attached   map[net.Conn]*holderConn
+	watchers   map[net.Conn]struct{}
+	// timer closes the holder when nothing is attached for the timeout.
+	timer *time.Timer
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func runHolder(ctx context.Context, logger slog.Logger, dir string, spec holderSpec) error {
+	if len(spec.Args) == 0 {
+		return xerrors.New("no command")
+	}
+	sb, err := openScrollback(filepath.Join(dir, holderScrollback))
+	if err != nil {
+		return err
+	}
+	defer sb.Close()
+
+	socket := filepath.Join(dir, holderSocket)
+	_ = os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return xerrors.Errorf("listen: %w", err)
+	}
+
+	cmd := pty.CommandContext(ctx, spec.Path, spec.Args[1:]...)
+	cmd.Env = spec.Env
+	cmd.Dir = spec.Dir
+	ptty, process, err := pty.Start(cmd)
+	if err != nil {
+		_ = listener.Close()
+		return xerrors.Errorf("start pty: %w", err)
+	}
+	logger.Info(ctx, "started pty", slog.F("path", spec.Path))
+
+	h := &holder{
+		logger:     logger,
+		dir:        dir,
+		timeout:    spec.Timeout,
+		ptty:       ptty,
+		process:    process,
+		listener:   listener,
+		scrollback: sb,
+		attached:   map[net.Conn]*holderConn{},
+		watchers:   map[net.Conn]struct{}{},
+		closed:     make(chan struct{}),
+	}
+	// The agent attaches right after starting the holder, so this only
+	// closes holders that nothing ever attaches to.
+	h.timer = time.AfterFunc(max(spec.Timeout, attachTimeout), func() {
+		logger.Info(ctx, "nothing attached within the timeout")
+		h.close()
+	})
+	go h.copyOutput(ctx)
+	go func() {
+		err := process.Wait()
+		logger.Info(ctx, "process exited", slog.Error(err))
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			break
+		}
+		go h.handleConn(ctx, conn)
+	}
+	h.close()
+	<-h.closed
+	return nil
+}
+
+// copyOutput writes the pty output to the scrollback and the attached
+// connections until the process exits.
+func (h *holder) copyOutput(ctx context.Context) {
+	buffer := make([]byte, 32<<10)
+	for {
+		n, err := h.ptty.OutputReader().Read(buffer)
+		if err != nil {
+			// The output is read until the process exits or it is killed.
+			h.logger.Debug(ctx, "read pty output", slog.Error(err))
+			h.close()
+			return
+		}
+		h.broadcast(ctx, buffer[:n])
+	}
+}
+
+// broadcast writes output to the scrollback and queues it for the attached
+// connections.  It never waits on a connection.
+func (h *holder) broadcast(ctx context.Context, part []byte) {
+	// The buffer is reused by the reader and the connections write
+	// asynchronously, so they need their own copy.
+	part = append([]byte(nil), part...)
+	h.mu.Lock()
+	err := h.scrollback.Write(part)
+	if err != nil {
+		h.logger.Error(ctx, "write scrollback", slog.Error(err))
+	}
+	conns := make([]*holderConn, 0, len(h.attached))
+	for _, c := range h.attached {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		if !c.queue(part) {
+			h.logger.Warn(ctx, "attached connection is too slow, closing it")
+			_ = c.conn.Close()
+		}
+	}
+}
+
+func (h *holder) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	typ, payload, err := readFrame(conn)
+	if err != nil {
+		h.logger.Debug(ctx, "read first frame", slog.Error(err))
+		return
+	}
+	switch typ {
+	case frameAttach:
+		if len(payload) != 4 {
+			return
+		}
+		err = h.attach(ctx, conn)
+		if err != nil {
+			h.logger.Warn(ctx, "attach", slog.Error(err))
+			return
+		}
+		defer h.detach(conn)
+		h.resize(ctx, payload)
+	case frameWatch:
+		h.mu.Lock()
+		h.watchers[conn] = struct{}{}
+		h.mu.Unlock()
+		defer func() {
+			h.mu.Lock()
+			delete(h.watchers, conn)
+			h.mu.Unlock()
+		}()
+	default:
+		return
+	}
+
+	for {
+		typ, payload, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+		switch typ {
+		case frameInput:
+			_, err = h.ptty.InputWriter().Write(payload)
+			if err != nil {
+				h.logger.Warn(ctx, "write pty input", slog.Error(err))
+				return
+			}
+		case frameResize:
+			h.resize(ctx, payload)
+		case frameKill:
+			h.logger.Info(ctx, "killed")
+			h.close()
+			return
+		}
+	}
+}
+
+// attach queues the scrollback for the connection and adds it to the
+// connections that receive the output.
+func (h *holder) attach(ctx context.Context, conn net.Conn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.closed:
+		return xerrors.New("holder closed")
+	default:
+	}
+	prev, err := h.scrollback.Tail()
+	if err != nil {
+		return xerrors.Errorf("read scrollback: %w", err)
+	}
+	c := &holderConn{
+		conn:   conn,
+		output: make(chan []byte, holderConnBuffer),
+		done:   make(chan struct{}),
+	}
+	// Queued while holding the lock so the scrollback is written before any
+	// new output.
+	if len(prev) > 0 {
+		c.queue(prev)
+	}
+	h.attached[conn] = c
+	h.timer.Stop()
+	go c.run(ctx, h.logger)
+	return nil
+}
+
+func (h *holder) detach(conn net.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.attached[conn]; ok {
+		close(c.done)
+		delete(h.attached, conn)
+	}
+	if len(h.attached) == 0 {
+		h.timer.Reset(h.timeout)
+	}
+}
+
+// holderConn writes the output to an attached connection on its own
+// goroutine, so a slow connection only delays itself.
+type holderConn struct {
+	conn   net.Conn
+	output chan []byte
+	// done is closed when the connection is detached.
+	done chan struct{}
+}
+
+// queue adds output for the connection, it returns false if the connection
+// has fallen too far behind.
+func (c *holderConn) queue(part []byte) bool {
+	select {
+	case c.output <- part:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *holderConn) run(ctx context.Context, logger slog.Logger) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case part := <-c.output:
+			_, err := c.conn.Write(part)
+			if err != nil {
+				logger.Debug(ctx, "write to attached connection", slog.Error(err))
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (h *holder) resize(ctx context.Context, payload []byte) {
+	if len(payload) != 4 {
+		return
+	}
+	height, width := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+	if height == 0 || width == 0 {
+		return
+	}
+	err := h.ptty.Resize(height, width)
+	if err != nil {
+		h.logger.Warn(ctx, "resize pty", slog.Error(err))
+	}
+}
+
+// close kills the process, closes all connections and removes the directory
+// of the holder.
+func (h *holder) close() {
+	h.closeOnce.Do(func() {
+		_ = h.listener.Close()
+		_ = h.process.Kill()
+		_ = h.ptty.Close()
+		h.mu.Lock()
+		h.timer.Stop()
+		for conn := range h.attached {
+			_ = conn.Close()
+		}
+		for conn := range h.watchers {
+			_ = conn.Close()
+		}
+		h.mu.Unlock()
+		_ = os.RemoveAll(h.dir)
+		close(h.closed)
+	})
+}
+
+// scrollback is the output of a pty persisted to a file.  Only the most recent
+// output is kept.
+type scrollback struct {
+	path string
+	file *os.File
+	size int64
+}
+
+func openScrollback(path string) (*scrollback, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("open scrollback: %w", err)
+	}
+	return &scrollback{path: path, file: file}, nil
+}
+
+func (s *scrollback) Write(p []byte) error {
+	n, err := s.file.WriteAt(p, s.size)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if s.size < 2*scrollbackSize {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact drops everything but the tail from the file.
+func (s *scrollback) compact() error {
+	tail, err := s.Tail()
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	err = os.WriteFile(tmp, tail, 0o600)
+	if err != nil {
+		return xerrors.Errorf("write compacted scrollback: %w", err)
+	}
+	err = os.Rename(tmp, s.path)
+	if err != nil {
+		return xerrors.Errorf("replace scrollback: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_RDWR, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open compacted scrollback: %w", err)
+	}
+	_ = s.file.Close()
+	s.file = file
+	s.size = int64(len(tail))
+	return nil
+}
+
+// Tail returns the output that is replayed on attach.
+func (s *scrollback) Tail() ([]byte, error) {
+	offset := max(s.size-scrollbackSize, 0)
+	tail := make([]byte, s.size-offset)
+	_, err := s.file.ReadAt(tail, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return tail, nil
+}
+
+func (s *scrollback) Close() error {
+	return s.file.Close()
+}
+
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	frame := make([]byte, 5+len(payload))
+	frame[0] = typ
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload))) //nolint:gosec // Frames are small.
+	copy(frame[5:], payload)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > 1<<20 {
+		return 0, nil, xerrors.Errorf("frame of %d bytes is too large", length)
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func sizePayload(height, width uint16) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload, height)
+	binary.BigEndian.PutUint16(payload[2:], width)
+	return payload
+}
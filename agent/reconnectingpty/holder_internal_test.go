@@ -0,0 +1,114 @@
+package reconnectingpty
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func newTestHolder(t *testing.T) *holder {
+	t.Helper()
+	sb, err := openScrollback(filepath.Join(t.TempDir(), holderScrollback))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sb.Close() })
+	h := &holder{
+		logger:     slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		timeout:    time.Hour,
+		scrollback: sb,
+		attached:   map[net.Conn]*holderConn{},
+		watchers:   map[net.Conn]struct{}{},
+		timer:      time.AfterFunc(time.Hour, func() {}),
+		closed:     make(chan struct{}),
+	}
+	t.Cleanup(func() { h.timer.Stop() })
+	return h
+}
+
+// pipe returns a connection for the holder and the client end of it.  Writes
+// to a pipe block until the client reads, so a client that never reads is
+// stalled.
+func pipe(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	return server, client
+}
+
+func TestHolderSlowClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DoesNotBlockOthers", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		h := newTestHolder(t)
+
+		h.broadcast(ctx, []byte("before"))
+
+		stalled, _ := pipe(t)
+		require.NoError(t, h.attach(ctx, stalled))
+		fast, fastClient := pipe(t)
+		require.NoError(t, h.attach(ctx, fast))
+
+		// The scrollback is replayed first.
+		buf := make([]byte, 64)
+		n, err := fastClient.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, "before", string(buf[:n]))
+
+		for _, part := range []string{"one", "two", "three"} {
+			h.broadcast(ctx, []byte(part))
+			n, err := fastClient.Read(buf)
+			require.NoError(t, err)
+			require.Equal(t, part, string(buf[:n]))
+		}
+
+		// Attaching and detaching do not wait on the stalled client either.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			other, _ := pipe(t)
+			_ = h.attach(ctx, other)
+			h.detach(other)
+			h.detach(fast)
+		}()
+		testutil.TryReceive(ctx, t, done)
+	})
+
+	t.Run("ClosedWhenBehind", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		h := newTestHolder(t)
+
+		stalled, stalledClient := pipe(t)
+		require.NoError(t, h.attach(ctx, stalled))
+
+		// One chunk is taken by the blocked write, the rest fill the queue
+		// and the last one overflows it.
+		for i := 0; i < holderConnBuffer+2; i++ {
+			h.broadcast(ctx, []byte("output"))
+		}
+
+		// The holder closed the connection, so the client reads what was
+		// written and then hits EOF.
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := io.Copy(io.Discard, stalledClient)
+			errCh <- err
+		}()
+		err := testutil.TryReceive(ctx, t, errCh)
+		require.NoError(t, err)
+
+		h.detach(stalled)
+		require.Empty(t, h.attached)
+	})
+}
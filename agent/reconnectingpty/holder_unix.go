@@ -0,0 +1,11 @@
+//go:build !windows
+
+package reconnectingpty
+
+import "syscall"
+
+// holderSysProcAttr starts the holder in a new session, so it is not signaled
+// with the process group of the agent.
+func holderSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,9 @@
+package reconnectingpty
+
+import "syscall"
+
+// holderSysProcAttr starts the holder in a new process group, so it does not
+// receive the console signals of the agent.
+func holderSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
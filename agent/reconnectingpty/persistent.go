@@ -0,0 +1,269 @@
+package reconnectingpty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/pty"
+)
+
+// persistentReconnectingPTY provides a reconnectable PTY by running it in a
+// holder process.  The holder outlives the agent, so a new agent adopts the pty
+// and clients reconnect to it after the agent restarts.
+type persistentReconnectingPTY struct {
+	dir     string
+	metrics *prometheus.CounterVec
+
+	state *ptyState
+	// watch is closed by the holder when it exits.
+	watch   net.Conn
+	watchMu sync.Mutex
+}
+
+// newPersistent starts a holder for the pty.  The holder is not killed if the
+// context ends, the pty is only detached.
+func newPersistent(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *pty.Cmd, options *Options) *persistentReconnectingPTY {
+	rpty := &persistentReconnectingPTY{
+		dir:     options.Dir,
+		metrics: options.Metrics,
+		state:   newState(),
+	}
+
+	holderExited, err := startHolder(ctx, execer, cmd, options)
+	if err != nil {
+		rpty.state.setState(StateDone, xerrors.Errorf("start holder: %w", err))
+		return rpty
+	}
+	go rpty.lifecycle(ctx, logger, attachTimeout, holderExited)
+	return rpty
+}
+
+// adoptPersistent adopts the pty of a holder started by a previous agent.
+func adoptPersistent(ctx context.Context, logger slog.Logger, dir string, metrics *prometheus.CounterVec) *persistentReconnectingPTY {
+	rpty := &persistentReconnectingPTY{
+		dir:     dir,
+		metrics: metrics,
+		state:   newState(),
+	}
+	go rpty.lifecycle(ctx, logger, 0, nil)
+	return rpty
+}
+
+// startHolder starts the holder process in a new session, so it is not killed
+// with the agent.  The returned channel is closed when the holder exits.
+func startHolder(ctx context.Context, execer agentexec.Execer, cmd *pty.Cmd, options *Options) (<-chan struct{}, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, xerrors.Errorf("get executable: %w", err)
+	}
+	// The holder runs the command the way the other backends do, which
+	// includes agent-exec on Linux.  pty.Cmd duplicates Path as the first
+	// argument.
+	wrapped := execer.PTYCommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+	spec, err := json.Marshal(holderSpec{
+		Path:    wrapped.Path,
+		Args:    wrapped.Args,
+		Env:     append(cmd.Env, "TERM=xterm-256color"),
+		Dir:     cmd.Dir,
+		Timeout: options.Timeout,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("marshal command: %w", err)
+	}
+
+	err = os.MkdirAll(options.Dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create dir: %w", err)
+	}
+	logFile, err := os.OpenFile(filepath.Join(options.Dir, holderLog), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("open holder log: %w", err)
+	}
+	defer logFile.Close()
+
+	//nolint:gosec // The agent runs its own binary.
+	holder := exec.Command(executable, HolderCommand, options.Dir)
+	holder.Stdin = bytes.NewReader(spec)
+	holder.Stdout = logFile
+	holder.Stderr = logFile
+	holder.SysProcAttr = holderSysProcAttr()
+	err = holder.Start()
+	if err != nil {
+		return nil, xerrors.Errorf("start holder: %w", err)
+	}
+	// Reap the holder if it exits while the agent runs.
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		_ = holder.Wait()
+	}()
+	return exited, nil
+}
+
+// lifecycle watches the holder until it exits.  If the context ends the pty is
+// detached, the holder keeps running.  holderExited is nil if the holder was
+// started by a previous agent.
+func (rpty *persistentReconnectingPTY) lifecycle(ctx context.Context, logger slog.Logger, startTimeout time.Duration, holderExited <-chan struct{}) {
+	watch, err := rpty.dial(ctx, startTimeout)
+	if err == nil {
+		err = writeFrame(watch, frameWatch, nil)
+	}
+	if err != nil {
+		rpty.metrics.WithLabelValues("holder").Add(1)
+		rpty.state.setState(StateDone, xerrors.Errorf("connect to holder: %w", err))
+		return
+	}
+	rpty.watchMu.Lock()
+	rpty.watch = watch
+	rpty.watchMu.Unlock()
+
+	logger.Debug(ctx, "reconnecting pty ready")
+	rpty.state.setState(StateReady, nil)
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		_, _ = io.Copy(io.Discard, watch)
+	}()
+	select {
+	case <-exited:
+		if holderExited != nil {
+			<-holderExited
+		}
+		logger.Info(ctx, "reconnecting pty holder exited")
+		rpty.state.setState(StateClosing, nil)
+	case <-ctx.Done():
+		logger.Info(ctx, "detached from reconnecting pty holder")
+		rpty.state.setState(StateClosing, ctx.Err())
+		_ = watch.Close()
+		<-exited
+	}
+	rpty.state.setState(StateDone, nil)
+}
+
+// dial connects to the holder, retrying until the timeout while the holder
+// starts.
+func (rpty *persistentReconnectingPTY) dial(ctx context.Context, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	var d net.Dialer
+	for {
+		conn, err := d.DialContext(ctx, "unix", filepath.Join(rpty.dir, holderSocket))
+		if err == nil || !time.Now().Before(deadline) {
+			return conn, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (rpty *persistentReconnectingPTY) Attach(ctx context.Context, _ string, conn net.Conn, height, width uint16, logger slog.Logger) error {
+	logger.Info(ctx, "attach to reconnecting pty")
+
+	state, err := rpty.state.waitForStateOrContext(ctx, StateReady)
+	if state != StateReady {
+		return err
+	}
+
+	holderConn, err := rpty.dial(ctx, 0)
+	if err != nil {
+		rpty.metrics.WithLabelValues("holder").Add(1)
+		return xerrors.Errorf("connect to holder: %w", err)
+	}
+	defer holderConn.Close()
+	err = writeFrame(holderConn, frameAttach, sizePayload(height, width))
+	if err != nil {
+		return xerrors.Errorf("attach to holder: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		// The holder replays the scrollback and sends the output until it
+		// exits.
+		_, _ = io.Copy(conn, holderConn)
+		cancel()
+		_ = conn.Close()
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = holderConn.Close()
+	}()
+
+	readConnLoop(ctx, conn, holderInput{conn: holderConn}, rpty.metrics, logger)
+	return nil
+}
+
+func (rpty *persistentReconnectingPTY) Wait() {
+	_, _ = rpty.state.waitForState(StateClosing)
+}
+
+// Close kills the holder.  The lifecycle moves to the closing state once the
+// holder exits.
+func (rpty *persistentReconnectingPTY) Close(err error) {
+	rpty.watchMu.Lock()
+	watch := rpty.watch
+	rpty.watchMu.Unlock()
+	if watch == nil || writeFrame(watch, frameKill, nil) != nil {
+		rpty.state.setState(StateClosing, err)
+	}
+}
+
+// holderInput writes the input of a connection to the holder.
+type holderInput struct {
+	conn net.Conn
+}
+
+func (h holderInput) InputWriter() io.Writer {
+	return h
+}
+
+func (h holderInput) Write(p []byte) (int, error) {
+	err := writeFrame(h.conn, frameInput, p)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (h holderInput) Resize(height, width uint16) error {
+	return writeFrame(h.conn, frameResize, sizePayload(height, width))
+}
+
+// writeSession describes the session of a persistent pty in its directory.
+func writeSession(dir string, session workspacesdk.ReconnectingPTYSession) error {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return xerrors.Errorf("create dir: %w", err)
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return xerrors.Errorf("marshal session: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, holderSession), data, 0o600)
+}
+
+func readSession(dir string) (workspacesdk.ReconnectingPTYSession, error) {
+	var session workspacesdk.ReconnectingPTYSession
+	data, err := os.ReadFile(filepath.Join(dir, holderSession))
+	if err != nil {
+		return session, err
+	}
+	return session, json.Unmarshal(data, &session)
+}
@@ -34,11 +34,15 @@ type Options struct {
 	Metrics *prometheus.CounterVec
 	// BackendType specifies the ReconnectingPTY backend to use.
 	BackendType string
+	// Dir is the directory the persistent backend keeps the state of the pty
+	// in. The persistent backend is only available if it is set.
+	Dir string
 }
 
 // ReconnectingPTY is a pty that can be reconnected within a timeout and to
 // simultaneous connections.  The reconnecting pty can be backed by screen if
-// installed or a (buggy) buffer replay fallback.
+// installed, a (buggy) buffer replay fallback, or a holder process that
+// outlives the agent.
 type ReconnectingPTY interface {
 	// Attach pipes the connection and pty, spawning it if necessary, replays
 	// history, then blocks until EOF, an error, or the context's end.  The
@@ -62,23 +66,10 @@ func New(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *
 	if options.Timeout == 0 {
 		options.Timeout = 5 * time.Minute
 	}
-	// Screen seems flaky on Darwin.  Locally the tests pass 100% of the time (100
-	// runs) but in CI screen often incorrectly claims the session name does not
-	// exist even though screen -list shows it.  For now, restrict screen to
-	// Linux.
-	autoBackendType := "buffered"
-	if runtime.GOOS == "linux" {
-		_, err := exec.LookPath("screen")
-		if err == nil {
-			autoBackendType = "screen"
-		}
-	}
-	var backendType string
-	switch options.BackendType {
-	case "":
-		backendType = autoBackendType
-	default:
-		backendType = options.BackendType
+	backendType := resolveBackendType(options.BackendType)
+	if backendType == "persistent" && options.Dir == "" {
+		logger.Warn(ctx, "persistent reconnecting ptys are not enabled, falling back to the buffered backend")
+		backendType = "buffered"
 	}
 
 	logger.Info(ctx, "start reconnecting pty", slog.F("backend_type", backendType))
@@ -86,11 +77,36 @@ func New(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *
 	switch backendType {
 	case "screen":
 		return newScreen(ctx, logger, execer, cmd, options)
+	case "persistent":
+		return newPersistent(ctx, logger, execer, cmd, options)
 	default:
 		return newBuffered(ctx, logger, execer, cmd, options)
 	}
 }
 
+// resolveBackendType returns the backend used for the requested backend type,
+// an empty type picks screen if installed.
+func resolveBackendType(backendType string) string {
+	switch backendType {
+	case "screen", "persistent", "buffered":
+		return backendType
+	case "":
+	default:
+		return "buffered"
+	}
+	// Screen seems flaky on Darwin.  Locally the tests pass 100% of the time (100
+	// runs) but in CI screen often incorrectly claims the session name does not
+	// exist even though screen -list shows it.  For now, restrict screen to
+	// Linux.
+	if runtime.GOOS == "linux" {
+		_, err := exec.LookPath("screen")
+		if err == nil {
+			return "screen"
+		}
+	}
+	return "buffered"
+}
+
 // heartbeat resets timer before timeout elapses and blocks until ctx ends.
 func heartbeat(ctx context.Context, timer *time.Timer, timeout time.Duration) {
 	// Reset now in case it is near the end.
@@ -201,9 +217,15 @@ func (s *ptyState) waitForStateOrContext(ctx context.Context, state State) (Stat
 	return s.state, s.error
 }
 
+// ptyInput is the input side of a pty.
+type ptyInput interface {
+	InputWriter() io.Writer
+	Resize(height, width uint16) error
+}
+
 // readConnLoop reads messages from conn and writes to ptty as needed.  Blocks
 // until EOF or an error writing to ptty or reading from conn.
-func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *prometheus.CounterVec, logger slog.Logger) {
+func readConnLoop(ctx context.Context, conn net.Conn, ptty ptyInput, metrics *prometheus.CounterVec, logger slog.Logger) {
 	decoder := json.NewDecoder(conn)
 	for {
 		var req workspacesdk.ReconnectingPTYRequest
@@ -231,5 +253,8 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			logger.Warn(ctx, "reconnecting pty resize failed, but will continue", slog.Error(err))
 			metrics.WithLabelValues("resize").Add(1)
 		}
+		if rc, ok := conn.(*recordingConn); ok {
+			rc.rec.Resize(req.Height, req.Width)
+		}
 	}
 }
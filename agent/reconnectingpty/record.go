@@ -0,0 +1,20 @@
+package reconnectingpty
+
+import (
+	"net"
+
+	"github.com/coder/coder/v2/agent/agentrecord"
+)
+
+// recordingConn records everything written to the connection, which is the
+// output of the pty as seen by the client.
+type recordingConn struct {
+	net.Conn
+	rec *agentrecord.Recorder
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	_, _ = c.rec.Write(p[:n])
+	return n, err
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"net"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +19,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
@@ -30,9 +35,18 @@ type Server struct {
 	reportConnection reportConnectionFunc
 	connCount        atomic.Int64
 	reconnectingPTYs sync.Map
-	timeout          time.Duration
+	// sessions holds the *session of each reconnecting pty by ID.
+	sessions  sync.Map
+	timeout   time.Duration
+	adoptOnce sync.Once
 
 	ExperimentalDevcontainersEnabled bool
+	// PersistentDir is the directory persistent reconnecting ptys keep their
+	// state in.  The persistent backend is disabled if it is empty.
+	PersistentDir string
+	// RecordSession starts recording the output of a connection. It may
+	// return nil if the connection should not be recorded.
+	RecordSession func(command string, height, width uint16) *agentrecord.Recorder
 }
 
 // NewServer returns a new ReconnectingPTY server
@@ -59,7 +73,17 @@ func NewServer(logger slog.Logger, commandCreator *agentssh.Server, reportConnec
 	return s
 }
 
+// session is a reconnecting pty and its description.
+type session struct {
+	info        workspacesdk.ReconnectingPTYSession
+	rpty        ReconnectingPTY
+	connections atomic.Int64
+}
+
 func (s *Server) Serve(ctx, hardCtx context.Context, l net.Listener) (retErr error) {
+	s.adoptOnce.Do(func() {
+		s.adopt(ctx)
+	})
 	var wg sync.WaitGroup
 	for {
 		if ctx.Err() != nil {
@@ -111,6 +135,88 @@ func (s *Server) ConnCount() int64 {
 	return s.connCount.Load()
 }
 
+// Sessions returns the reconnecting pty sessions ordered by creation.
+func (s *Server) Sessions() []workspacesdk.ReconnectingPTYSession {
+	sessions := []workspacesdk.ReconnectingPTYSession{}
+	s.sessions.Range(func(_, value any) bool {
+		sess, ok := value.(*session)
+		if ok {
+			info := sess.info
+			info.Connections = sess.connections.Load()
+			sessions = append(sessions, info)
+		}
+		return true
+	})
+	slices.SortFunc(sessions, func(a, b workspacesdk.ReconnectingPTYSession) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return sessions
+}
+
+// Kill kills the process of a reconnecting pty session, closing its
+// connections.  It returns false if there is no session with the ID.
+func (s *Server) Kill(id uuid.UUID) bool {
+	value, ok := s.sessions.Load(id)
+	if !ok {
+		return false
+	}
+	sess, ok := value.(*session)
+	if !ok {
+		return false
+	}
+	sess.rpty.Close(xerrors.New("killed"))
+	return true
+}
+
+// adopt adopts the persistent reconnecting ptys whose holders outlived the
+// previous agent.  The directories of holders that exited are removed.
+func (s *Server) adopt(ctx context.Context) {
+	if s.PersistentDir == "" {
+		return
+	}
+	entries, err := os.ReadDir(s.PersistentDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn(ctx, "read persistent reconnecting pty dir", slog.Error(err))
+		}
+		return
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(s.PersistentDir, entry.Name())
+		info, err := readSession(dir)
+		if err != nil || info.ID.String() != entry.Name() {
+			s.logger.Warn(ctx, "remove invalid persistent reconnecting pty", slog.F("dir", dir), slog.Error(err))
+			_ = os.RemoveAll(dir)
+			continue
+		}
+		logger := s.logger.With(slog.F("message_id", info.ID))
+		rpty := adoptPersistent(ctx, logger, dir, s.errorsTotal)
+		state, err := rpty.state.waitForState(StateReady)
+		if state != StateReady {
+			logger.Info(ctx, "remove persistent reconnecting pty with exited holder", slog.Error(err))
+			_ = os.RemoveAll(dir)
+			continue
+		}
+		logger.Info(ctx, "adopted persistent reconnecting pty")
+		ready := make(chan ReconnectingPTY, 1)
+		ready <- rpty
+		s.reconnectingPTYs.Store(info.ID, ready)
+		s.track(info, rpty)
+	}
+}
+
+// track adds the session until the reconnecting pty closes.
+func (s *Server) track(info workspacesdk.ReconnectingPTYSession, rpty ReconnectingPTY) *session {
+	sess := &session{info: info, rpty: rpty}
+	s.sessions.Store(info.ID, sess)
+	go func() {
+		rpty.Wait()
+		s.reconnectingPTYs.Delete(info.ID)
+		s.sessions.Delete(info.ID)
+	}()
+	return sess
+}
+
 func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Conn) (retErr error) {
 	defer conn.Close()
 	s.connectionsTotal.Add(1)
@@ -159,7 +265,7 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 		connLogger.Info(ctx, "reconnecting pty connection closed")
 	}()
 
-	var rpty ReconnectingPTY
+	var sess *session
 	sendConnected := make(chan ReconnectingPTY, 1)
 	// On store, reserve this ID to prevent multiple concurrent new connections.
 	waitReady, ok := s.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
@@ -170,11 +276,16 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 		if !ok {
 			return xerrors.Errorf("found invalid type in reconnecting pty map: %T", waitReady)
 		}
-		rpty, ok = <-c
+		rpty, ok := <-c
 		if !ok || rpty == nil {
 			return xerrors.Errorf("reconnecting pty closed before connection")
 		}
 		c <- rpty // Put it back for the next reconnect.
+		value, ok := s.sessions.Load(msg.ID)
+		if !ok {
+			return xerrors.Errorf("reconnecting pty closed before connection")
+		}
+		sess, _ = value.(*session)
 	} else {
 		connLogger.Debug(ctx, "creating new reconnecting pty")
 
@@ -188,7 +299,7 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 
 		var ei usershell.EnvInfoer
 		if s.ExperimentalDevcontainersEnabled && msg.Container != "" {
-			dei, err := agentcontainers.EnvInfo(ctx, s.commandCreator.Execer, msg.Container, msg.ContainerUser)
+			dei, err := agentcontainers.EnvInfo(ctx, s.commandCreator.Execer, s.commandCreator.ContainerRuntime(), msg.Container, msg.ContainerUser)
 			if err != nil {
 				return xerrors.Errorf("get container env info: %w", err)
 			}
@@ -202,33 +313,55 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 			return xerrors.Errorf("create command: %w", err)
 		}
 
-		rpty = New(ctx,
+		info := workspacesdk.ReconnectingPTYSession{
+			ID:          msg.ID,
+			Command:     msg.Command,
+			BackendType: resolveBackendType(msg.BackendType),
+			CreatedAt:   time.Now(),
+		}
+		options := &Options{
+			Timeout:     s.timeout,
+			Metrics:     s.errorsTotal,
+			BackendType: info.BackendType,
+		}
+		if info.BackendType == "persistent" && s.PersistentDir != "" {
+			options.Dir = filepath.Join(s.PersistentDir, msg.ID.String())
+			err = writeSession(options.Dir, info)
+			if err != nil {
+				return xerrors.Errorf("write persistent session: %w", err)
+			}
+		} else if info.BackendType == "persistent" {
+			info.BackendType = "buffered"
+		}
+		rpty := New(ctx,
 			logger.With(slog.F("message_id", msg.ID)),
 			s.commandCreator.Execer,
 			cmd,
-			&Options{
-				Timeout:     s.timeout,
-				Metrics:     s.errorsTotal,
-				BackendType: msg.BackendType,
-			},
+			options,
 		)
 
-		done := make(chan struct{})
-		go func() {
-			select {
-			case <-done:
-			case <-ctx.Done():
+		// Persistent reconnecting ptys detach on their own when the agent
+		// shuts down, so the holder keeps running.
+		if _, ok := rpty.(*persistentReconnectingPTY); !ok {
+			go func() {
+				<-ctx.Done()
 				rpty.Close(ctx.Err())
-			}
-		}()
-
-		go func() {
-			rpty.Wait()
-			s.reconnectingPTYs.Delete(msg.ID)
-		}()
+			}()
+		}
 
+		sess = s.track(info, rpty)
 		connected = true
 		sendConnected <- rpty
 	}
-	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
+	if s.RecordSession != nil {
+		// Each connection is recorded separately, as that is what a user
+		// saw, including the scrollback replayed on reconnect.
+		if rec := s.RecordSession(msg.Command, msg.Height, msg.Width); rec != nil {
+			defer rec.Close()
+			conn = &recordingConn{Conn: conn, rec: rec}
+		}
+	}
+	sess.connections.Add(1)
+	defer sess.connections.Add(-1)
+	return sess.rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
 }
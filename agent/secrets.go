@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+const redactedSecretValue = "[redacted]"
+
+// writeSecretFiles writes the secrets of the workspace owner that have a file
+// path. Failures are logged rather than returned so that one bad path does not
+// prevent the workspace from starting.
+func writeSecretFiles(ctx context.Context, logger slog.Logger, fs afero.Fs, secrets []agentsdk.WorkspaceAgentSecret) {
+	for _, secret := range secrets {
+		if secret.FilePath == "" {
+			continue
+		}
+		logger := logger.With(slog.F("secret", secret.Name), slog.F("path", secret.FilePath))
+		path, err := expandPathToAbs(secret.FilePath)
+		if err != nil {
+			logger.Warn(ctx, "failed to expand secret file path", slog.Error(err))
+			continue
+		}
+		if err := writeSecretFile(fs, path, secret.Value); err != nil {
+			logger.Warn(ctx, "failed to write secret file", slog.Error(err))
+			continue
+		}
+		logger.Debug(ctx, "wrote secret file")
+	}
+}
+
+func writeSecretFile(fs afero.Fs, path string, value string) error {
+	if err := fs.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return xerrors.Errorf("create parent directory: %w", err)
+	}
+	f, err := fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+	// The file may have existed with broader permissions.
+	if err := fs.Chmod(path, 0o600); err != nil {
+		return xerrors.Errorf("chmod file: %w", err)
+	}
+	if _, err := f.WriteString(value); err != nil {
+		return xerrors.Errorf("write file: %w", err)
+	}
+	return f.Close()
+}
+
+// redactProtoManifest returns a copy of the manifest that is safe to log.
+func redactProtoManifest(mp *proto.Manifest) *proto.Manifest {
+	if len(mp.GetSecrets()) == 0 {
+		return mp
+	}
+	//nolint:forcetypeassert // Clone returns the type it is given.
+	redacted := protobuf.Clone(mp).(*proto.Manifest)
+	for _, secret := range redacted.Secrets {
+		secret.Value = redactedSecretValue
+	}
+	return redacted
+}
+
+// redactManifest returns a copy of the manifest that is safe to expose.
+func redactManifest(manifest agentsdk.Manifest) agentsdk.Manifest {
+	if len(manifest.Secrets) == 0 {
+		return manifest
+	}
+	secrets := make([]agentsdk.WorkspaceAgentSecret, len(manifest.Secrets))
+	for i, secret := range manifest.Secrets {
+		secret.Value = redactedSecretValue
+		secrets[i] = secret
+	}
+	manifest.Secrets = secrets
+	return manifest
+}
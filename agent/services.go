@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/agent/agentservices"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// HandleServices returns the services supervised by the agent.
+func (a *agent) HandleServices(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentServicesResponse{
+		Services: a.serviceSupervisor.Services(),
+	})
+}
+
+// HandleRestartService restarts a service, a service that exited is started
+// again.
+func (a *agent) HandleRestartService(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "service")
+
+	err := a.serviceSupervisor.Restart(name)
+	switch {
+	case errors.Is(err, agentservices.ErrNotFound):
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Service %q not found.", name),
+		})
+		return
+	case errors.Is(err, agentservices.ErrNotStarted):
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Services have not been started yet.",
+			Detail:  "Services are started after the startup scripts finish.",
+		})
+		return
+	case err != nil:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Could not restart service.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+		Message: fmt.Sprintf("Service %q is restarting.", name),
+	})
+}
@@ -28,11 +28,14 @@ import (
 	"github.com/coder/serpent"
 
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentlogsink"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/reaper"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/clilog"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
@@ -54,8 +57,16 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 		blockFileTransfer   bool
 		agentHeaderCommand  string
 		agentHeader         []string
+		logSinkFile         string
+		logSinkFileMaxSize  int64
+		logSinkFileBackups  int64
+		logSinkSyslog       string
+		logSinkOTLP         string
+		logSinkOTLPHeader   []string
+		logSinkSources      []string
 
 		experimentalDevcontainersEnabled bool
+		containerRuntime                 string
 	)
 	cmd := &serpent.Command{
 		Use:   "agent",
@@ -172,6 +183,25 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			sinks = append(sinks, sloghuman.Sink(logWriter))
 			logger := inv.Logger.AppendSinks(sinks...).Leveled(slog.LevelDebug)
 
+			// The shipper reports failing sinks with a logger that does not
+			// ship to them, or a failing sink would keep feeding itself.
+			logShipper, err := newAgentLogShipper(logger.Named("logsink"), agentLogSinkConfig{
+				file:           logSinkFile,
+				fileMaxSize:    int(logSinkFileMaxSize),
+				fileMaxBackups: int(logSinkFileBackups),
+				syslog:         logSinkSyslog,
+				otlp:           logSinkOTLP,
+				otlpHeader:     logSinkOTLPHeader,
+				sources:        logSinkSources,
+			})
+			if err != nil {
+				return xerrors.Errorf("configure log sinks: %w", err)
+			}
+			if logShipper != nil {
+				defer logShipper.Close()
+				logger = logger.AppendSinks(logShipper.SlogSink())
+			}
+
 			version := buildinfo.Version()
 			logger.Info(ctx, "agent is starting now",
 				slog.F("url", r.agentURL),
@@ -362,6 +392,8 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 					BlockFileTransfer:                blockFileTransfer,
 					Execer:                           execer,
 					ExperimentalDevcontainersEnabled: experimentalDevcontainersEnabled,
+					ContainerRuntime:                 agentcontainers.Runtime(containerRuntime),
+					LogShipper:                       logShipper,
 				})
 
 				promHandler := agent.PrometheusMetricsHandler(prometheusRegistry, logger)
@@ -507,11 +539,115 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			Description: "Allow the agent to automatically detect running devcontainers.",
 			Value:       serpent.BoolOf(&experimentalDevcontainersEnabled),
 		},
+		{
+			Flag:        "devcontainers-runtime",
+			Env:         "CODER_AGENT_DEVCONTAINERS_RUNTIME",
+			Description: "The container runtime used to detect devcontainers. If unset, the first of docker, podman and nerdctl that is available is used.",
+			Value:       serpent.EnumOf(&containerRuntime, string(agentcontainers.RuntimeDocker), string(agentcontainers.RuntimePodman), string(agentcontainers.RuntimeNerdctl)),
+		},
+		{
+			Flag:        "log-sink-file",
+			Env:         "CODER_AGENT_LOG_SINK_FILE",
+			Description: "Write logs as JSON lines to a file, in addition to sending them to Coder. The file is rotated when it reaches the maximum size.",
+			Value:       serpent.StringOf(&logSinkFile),
+		},
+		{
+			Flag:        "log-sink-file-max-size",
+			Env:         "CODER_AGENT_LOG_SINK_FILE_MAX_SIZE",
+			Default:     "100",
+			Description: "The maximum size in megabytes of the log sink file before it is rotated.",
+			Value:       serpent.Int64Of(&logSinkFileMaxSize),
+		},
+		{
+			Flag:        "log-sink-file-max-backups",
+			Env:         "CODER_AGENT_LOG_SINK_FILE_MAX_BACKUPS",
+			Default:     "5",
+			Description: "The number of rotated log sink files to keep.",
+			Value:       serpent.Int64Of(&logSinkFileBackups),
+		},
+		{
+			Flag:        "log-sink-syslog",
+			Env:         "CODER_AGENT_LOG_SINK_SYSLOG",
+			Description: "Send logs in the RFC 5424 format to a syslog server, e.g. udp://syslog.example.com:514 or tcp://syslog.example.com:601.",
+			Value:       serpent.StringOf(&logSinkSyslog),
+		},
+		{
+			Flag:        "log-sink-otlp",
+			Env:         "CODER_AGENT_LOG_SINK_OTLP",
+			Description: "Export logs with OTLP/HTTP to the logs endpoint of an OpenTelemetry collector, e.g. https://otel.example.com:4318/v1/logs.",
+			Value:       serpent.StringOf(&logSinkOTLP),
+		},
+		{
+			Flag:        "log-sink-otlp-header",
+			Env:         "CODER_AGENT_LOG_SINK_OTLP_HEADER",
+			Description: "Additional HTTP headers added to OTLP export requests, in the format \"Key=Value\".",
+			Value:       serpent.StringArrayOf(&logSinkOTLPHeader),
+		},
+		{
+			Flag:        "log-sink-sources",
+			Env:         "CODER_AGENT_LOG_SINK_SOURCES",
+			Default:     strings.Join(slice.ToStrings(agentlogsink.Sources), ","),
+			Description: "The logs that are shipped to the log sinks: the logs of the agent, the output of scripts and services, and connection events.",
+			Value:       serpent.EnumArrayOf(&logSinkSources, slice.ToStrings(agentlogsink.Sources)...),
+		},
 	}
 
 	return cmd
 }
 
+type agentLogSinkConfig struct {
+	file           string
+	fileMaxSize    int
+	fileMaxBackups int
+	syslog         string
+	otlp           string
+	otlpHeader     []string
+	sources        []string
+}
+
+// newAgentLogShipper creates a shipper for the configured log sinks. It
+// returns nil if no sink is configured.
+func newAgentLogShipper(logger slog.Logger, cfg agentLogSinkConfig) (*agentlogsink.Shipper, error) {
+	var logSinks []agentlogsink.Sink
+	if cfg.file != "" {
+		logSinks = append(logSinks, agentlogsink.NewFileSink(cfg.file, cfg.fileMaxSize, cfg.fileMaxBackups))
+	}
+	if cfg.syslog != "" {
+		sink, err := agentlogsink.NewSyslogSink(cfg.syslog)
+		if err != nil {
+			return nil, err
+		}
+		logSinks = append(logSinks, sink)
+	}
+	if cfg.otlp != "" {
+		header := http.Header{}
+		for _, h := range cfg.otlpHeader {
+			key, value, ok := strings.Cut(h, "=")
+			if !ok {
+				return nil, xerrors.Errorf("OTLP header %q must be in the format \"Key=Value\"", h)
+			}
+			header.Add(key, value)
+		}
+		sink, err := agentlogsink.NewOTLPSink(cfg.otlp, header)
+		if err != nil {
+			return nil, err
+		}
+		logSinks = append(logSinks, sink)
+	}
+	if len(logSinks) == 0 {
+		return nil, nil
+	}
+
+	sources := make([]agentlogsink.Source, 0, len(cfg.sources))
+	for _, source := range cfg.sources {
+		sources = append(sources, agentlogsink.Source(source))
+	}
+	return agentlogsink.New(agentlogsink.Options{
+		Logger:  logger,
+		Sources: sources,
+	}, logSinks...), nil
+}
+
 func ServeHandler(ctx context.Context, logger slog.Logger, handler http.Handler, addr, name string) (closeFunc func()) {
 	// ReadHeaderTimeout is purposefully not enabled. It caused some issues with
 	// websockets over the dev tunnel.
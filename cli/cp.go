@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) cp() *serpent.Command {
+	var (
+		recursive        bool
+		quiet            bool
+		appearanceConfig codersdk.AppearanceConfig
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files to and from a workspace",
+		Long: "Exactly one of the source and destination must be in a workspace, written as " +
+			"<workspace>:<path>. Relative paths in a workspace are relative to the home " +
+			"directory of the workspace user. Files are copied over the workspace " +
+			"connection, an SSH client is not required.\n\n" + FormatExamples(
+			Example{
+				Description: "Copy a file to the home directory of a workspace",
+				Command:     "coder cp ./notes.txt my-workspace:",
+			},
+			Example{
+				Description: "Copy a directory to a workspace",
+				Command:     "coder cp -r ./project my-workspace:/tmp/project",
+			},
+			Example{
+				Description: "Copy a file from the agent of a workspace to the current directory",
+				Command:     "coder cp my-workspace.main:.bashrc .",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			src, dst := parseCopyPath(inv.Args[0]), parseCopyPath(inv.Args[1])
+			if (src.workspace == "") == (dst.workspace == "") {
+				return xerrors.New("exactly one of the source and destination must be in a workspace, e.g. my-workspace:/path")
+			}
+			remote := src
+			if remote.workspace == "" {
+				remote = dst
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, remote.workspace)
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch:   client.WorkspaceAgent,
+				Wait:    false,
+				DocsURL: appearanceConfig.DocsURL,
+			})
+			if err != nil {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			opts := &workspacesdk.DialAgentOptions{}
+			if r.verbose {
+				opts.Logger = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				opts.BlockEndpoints = true
+			}
+			if !r.disableNetworkTelemetry {
+				opts.EnableTelemetry = true
+			}
+			conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+			}
+
+			c := &copier{
+				conn:      conn,
+				recursive: recursive,
+				progress:  !quiet && isTTYErr(inv),
+				stderr:    inv.Stderr,
+			}
+			if src.workspace == "" {
+				return c.upload(ctx, src.path, dst.path)
+			}
+			return c.download(ctx, src.path, dst.path)
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "recursive",
+			FlagShorthand: "r",
+			Description:   "Copy directories recursively.",
+			Value:         serpent.BoolOf(&recursive),
+		},
+		{
+			Flag:          "quiet",
+			FlagShorthand: "q",
+			Description:   "Do not show progress.",
+			Value:         serpent.BoolOf(&quiet),
+		},
+	}
+	return cmd
+}
+
+type copyPath struct {
+	// workspace is empty for local paths.
+	workspace string
+	path      string
+}
+
+// parseCopyPath parses a cp argument. Paths in a workspace are written as
+// <workspace>:<path>, as with scp. Local paths that contain a colon can be
+// written as ./path.
+func parseCopyPath(arg string) copyPath {
+	if filepath.IsAbs(arg) || filepath.VolumeName(arg) != "" || strings.HasPrefix(arg, ".") {
+		return copyPath{path: arg}
+	}
+	workspace, p, ok := strings.Cut(arg, ":")
+	if !ok || workspace == "" {
+		return copyPath{path: arg}
+	}
+	if p == "" {
+		// An empty path is the home directory.
+		p = "."
+	}
+	return copyPath{workspace: workspace, path: p}
+}
+
+type copier struct {
+	conn      *workspacesdk.AgentConn
+	recursive bool
+	progress  bool
+	stderr    io.Writer
+}
+
+func (c *copier) upload(ctx context.Context, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy directories", src)
+	}
+
+	// As with cp, copying into an existing directory places the source
+	// inside of it.
+	target := dst
+	remote, err := c.conn.StatFile(ctx, dst)
+	switch {
+	case err == nil && remote.IsDir:
+		abs, err := filepath.Abs(src)
+		if err != nil {
+			return err
+		}
+		target = path.Join(remote.Path, filepath.Base(abs))
+	case err == nil && info.IsDir():
+		return xerrors.Errorf("%q already exists and is not a directory", dst)
+	case err != nil && !isNotFound(err):
+		return xerrors.Errorf("stat %q: %w", dst, err)
+	}
+
+	if info.IsDir() {
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(agentfiles.Tar(pw, src))
+		}()
+		defer pr.Close()
+		return c.withProgress(filepath.Base(src), -1, pr, func(r io.Reader) error {
+			return c.conn.UploadDirectory(ctx, target, r)
+		})
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.withProgress(filepath.Base(src), info.Size(), f, func(r io.Reader) error {
+		return c.conn.UploadFile(ctx, target, info.Mode().Perm(), r)
+	})
+}
+
+func (c *copier) download(ctx context.Context, src, dst string) error {
+	remote, err := c.conn.StatFile(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("stat %q: %w", src, err)
+	}
+	if remote.IsDir && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy directories", src)
+	}
+
+	target := dst
+	info, err := os.Stat(dst)
+	switch {
+	case err == nil && info.IsDir():
+		target = filepath.Join(dst, remote.Name)
+	case err == nil && remote.IsDir:
+		return xerrors.Errorf("%q already exists and is not a directory", dst)
+	case err != nil && !os.IsNotExist(err):
+		return err
+	}
+
+	rc, err := c.conn.DownloadFile(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("download %q: %w", src, err)
+	}
+	defer rc.Close()
+
+	if remote.IsDir {
+		return c.withProgress(remote.Name, -1, rc, func(r io.Reader) error {
+			return agentfiles.Untar(target, r)
+		})
+	}
+	// #nosec G304 - The user chooses where to write the file.
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, remote.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	err = c.withProgress(remote.Name, remote.Size, rc, func(r io.Reader) error {
+		_, err := io.Copy(f, r)
+		return err
+	})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// withProgress calls fn with a reader that reports the progress of reading
+// r. A total of -1 means the size is unknown.
+func (c *copier) withProgress(name string, total int64, r io.Reader, fn func(io.Reader) error) error {
+	if !c.progress {
+		return fn(r)
+	}
+	pr := &progressReader{r: r}
+	start := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, _ = fmt.Fprintf(c.stderr, "\r%s", formatCopyProgress(name, pr.n.Load(), total, time.Since(start)))
+			}
+		}
+	}()
+	err := fn(pr)
+	close(done)
+	<-stopped
+	_, _ = fmt.Fprintf(c.stderr, "\r%s\n", formatCopyProgress(name, pr.n.Load(), total, time.Since(start)))
+	return err
+}
+
+type progressReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n.Add(int64(n))
+	return n, err
+}
+
+func formatCopyProgress(name string, n, total int64, elapsed time.Duration) string {
+	var rate uint64
+	if elapsed > 0 {
+		// #nosec G115 - Transferred bytes are never negative.
+		rate = uint64(float64(n) / elapsed.Seconds())
+	}
+	// #nosec G115 - Transferred bytes are never negative.
+	transferred := humanize.IBytes(uint64(n))
+	if total < 0 {
+		return fmt.Sprintf("%s  %s  %s/s", name, transferred, humanize.IBytes(rate))
+	}
+	percent := int64(100)
+	if total > 0 {
+		percent = n * 100 / total
+	}
+	// #nosec G115 - Sizes are never negative.
+	return fmt.Sprintf("%s  %s / %s  %3d%%  %s/s", name, transferred, humanize.IBytes(uint64(total)), percent, humanize.IBytes(rate))
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
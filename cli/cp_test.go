@@ -0,0 +1,97 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	run := func(t *testing.T, args ...string) error {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, append([]string{"cp"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		return inv.WithContext(ctx).Run()
+	}
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+
+		local := t.TempDir()
+		remote := t.TempDir()
+		src := filepath.Join(local, "hello.txt")
+		require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+		// Copying into an existing directory keeps the file name.
+		err := run(t, src, workspace.Name+":"+remote)
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(remote, "hello.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(got))
+
+		// Copying to a new path renames the file.
+		err = run(t, src, workspace.Name+":"+filepath.Join(remote, "renamed.txt"))
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(remote, "renamed.txt"))
+
+		dst := filepath.Join(t.TempDir(), "downloaded.txt")
+		err = run(t, workspace.Name+":"+filepath.Join(remote, "renamed.txt"), dst)
+		require.NoError(t, err)
+		got, err = os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(got))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+
+		local := filepath.Join(t.TempDir(), "project")
+		require.NoError(t, os.MkdirAll(filepath.Join(local, "src"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(local, "src", "main.go"), []byte("package main"), 0o600))
+		remote := t.TempDir()
+
+		err := run(t, local, workspace.Name+":"+remote)
+		require.ErrorContains(t, err, "use --recursive")
+
+		err = run(t, "-r", local, workspace.Name+":"+remote)
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(remote, "project", "src", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(got))
+
+		dst := t.TempDir()
+		err = run(t, "--recursive", workspace.Name+":"+filepath.Join(remote, "project"), dst)
+		require.NoError(t, err)
+		got, err = os.ReadFile(filepath.Join(dst, "project", "src", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(got))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		err := run(t, workspace.Name+":"+filepath.Join(t.TempDir(), "missing"), t.TempDir())
+		require.ErrorContains(t, err, "no such file or directory")
+	})
+
+	t.Run("BothLocal", func(t *testing.T) {
+		t.Parallel()
+
+		err := run(t, "./a", "./b")
+		require.ErrorContains(t, err, "exactly one of the source and destination must be in a workspace")
+	})
+}
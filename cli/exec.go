@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) execCmd() *serpent.Command {
+	var (
+		env              []string
+		directory        string
+		noStdin          bool
+		appearanceConfig codersdk.AppearanceConfig
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "exec <workspace> <command> [args...]",
+		Short:       "Run a command in a workspace without a terminal",
+		Long: "Standard output and standard error of the command are kept separate, " +
+			"standard input and stop signals are forwarded to the command and coder " +
+			"exits with the exit code of the command. The command is run with the " +
+			"shell of the workspace user.\n\n" + FormatExamples(
+			Example{
+				Description: "Run a command in a workspace",
+				Command:     "coder exec my-workspace -- make test",
+			},
+			Example{
+				Description: "Run a command in a directory with extra environment variables",
+				Command:     "coder exec --dir project --env GOFLAGS=-v my-workspace -- go build ./...",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			for _, kv := range env {
+				if !strings.Contains(kv, "=") {
+					return xerrors.Errorf("invalid environment variable %q, must be KEY=VALUE", kv)
+				}
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch:   client.WorkspaceAgent,
+				Wait:    false,
+				DocsURL: appearanceConfig.DocsURL,
+			})
+			if err != nil {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			opts := &workspacesdk.DialAgentOptions{}
+			if r.verbose {
+				opts.Logger = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				opts.BlockEndpoints = true
+			}
+			if !r.disableNetworkTelemetry {
+				opts.EnableTelemetry = true
+			}
+			conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+			}
+
+			session, err := conn.Exec(ctx, workspacesdk.ExecRequest{
+				Command:          strings.Join(inv.Args[1:], " "),
+				Env:              env,
+				WorkingDirectory: directory,
+			}, inv.Stdout, inv.Stderr)
+			if err != nil {
+				return xerrors.Errorf("start command: %w", err)
+			}
+			defer session.Close()
+
+			if noStdin {
+				_ = session.CloseStdin()
+			} else {
+				go func() {
+					_, err := io.Copy(session, inv.Stdin)
+					if err == nil || errors.Is(err, io.EOF) {
+						_ = session.CloseStdin()
+					}
+				}()
+			}
+
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, StopSignals...)
+			defer signal.Stop(sigs)
+			go func() {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case sig := <-sigs:
+						if name := execSignalName(sig); name != "" {
+							_ = session.Signal(ctx, name)
+						}
+					}
+				}
+			}()
+
+			code, err := session.Wait()
+			if err != nil {
+				return xerrors.Errorf("run command: %w", err)
+			}
+			if code != 0 {
+				return ExitError(code, nil)
+			}
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "env",
+			FlagShorthand: "e",
+			Description:   "Set environment variables for the command, in KEY=VALUE form.",
+			Value:         serpent.StringArrayOf(&env),
+		},
+		{
+			Flag:        "dir",
+			Description: "The directory to run the command in. Relative paths are relative to the home directory of the workspace user.",
+			Value:       serpent.StringOf(&directory),
+		},
+		{
+			Flag:        "no-stdin",
+			Description: "Do not forward standard input to the command.",
+			Value:       serpent.BoolOf(&noStdin),
+		},
+	}
+	return cmd
+}
+
+// execSignalName returns the SSH protocol name of a stop signal.
+func execSignalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "INT"
+	case syscall.SIGTERM:
+		return "TERM"
+	case syscall.SIGHUP:
+		return "HUP"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The commands are POSIX shell specific")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	run := func(t *testing.T, stdin string, args ...string) (string, string, error) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, append([]string{"exec"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout, stderr bytes.Buffer
+		inv.Stdin = strings.NewReader(stdin)
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+		err := inv.WithContext(ctx).Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("Output", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		stdout, stderr, err := run(t, "", "--dir", dir, "-e", "EXEC_VAR=value", workspace.Name, "--",
+			`echo "$EXEC_VAR"; pwd; echo oops >&2`)
+		require.NoError(t, err)
+		require.Equal(t, "value\n"+dir+"\n", stdout)
+		require.Contains(t, stderr, "oops")
+		require.NotContains(t, stdout, "oops")
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		t.Parallel()
+
+		stdout, _, err := run(t, "hello", workspace.Name, "cat")
+		require.NoError(t, err)
+		require.Equal(t, "hello", stdout)
+
+		stdout, _, err = run(t, "hello", "--no-stdin", workspace.Name, "cat")
+		require.NoError(t, err)
+		require.Empty(t, stdout)
+	})
+
+	t.Run("ExitCode", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := run(t, "", workspace.Name, "exit", "42")
+		require.ErrorContains(t, err, "exit code 42")
+	})
+
+	t.Run("InvalidEnv", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := run(t, "", "--env", "NOVALUE", workspace.Name, "true")
+		require.ErrorContains(t, err, "must be KEY=VALUE")
+	})
+}
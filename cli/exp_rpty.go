@@ -4,15 +4,20 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mattn/go-isatty"
 	"golang.org/x/term"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
@@ -58,6 +63,12 @@ func (r *RootCmd) rptyCommand() *serpent.Command {
 				Default:       "",
 				Value:         serpent.StringOf(&args.ContainerUser),
 			},
+			{
+				Name:        "backend",
+				Description: "The backend of a new session, persistent sessions survive agent restarts. Defaults to screen if installed in the workspace.",
+				Flag:        "backend",
+				Value:       serpent.EnumOf(&args.BackendType, "buffered", "screen", "persistent"),
+			},
 			{
 				Name:          "reconnect",
 				Description:   "The reconnect ID to use.",
@@ -69,12 +80,149 @@ func (r *RootCmd) rptyCommand() *serpent.Command {
 		},
 		Short: "Establish an RPTY session with a workspace/agent.",
 		Use:   "rpty",
+		Children: []*serpent.Command{
+			r.rptyList(),
+			r.rptyAttach(),
+		},
 	}
 
 	return cmd
 }
 
+// rptySessionRow is the type provided to the OutputFormatter.
+type rptySessionRow struct {
+	// For JSON format:
+	workspacesdk.ReconnectingPTYSession `table:"-"`
+
+	// For table format:
+	ID          string `json:"-" table:"id"`
+	Backend     string `json:"-" table:"backend"`
+	Command     string `json:"-" table:"command"`
+	Connections int64  `json:"-" table:"connections"`
+	Created     string `json:"-" table:"created,default_sort"`
+}
+
+func (r *RootCmd) rptyList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]rptySessionRow{}, []string{"id", "backend", "command", "connections", "created"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "list <workspace>",
+		Short: "List the RPTY sessions of a workspace/agent.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			sessions, err := r.rptySessions(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			rows := make([]rptySessionRow, len(sessions))
+			for i, s := range sessions {
+				command := s.Command
+				if command == "" {
+					command = "(shell)"
+				}
+				rows[i] = rptySessionRow{
+					ReconnectingPTYSession: s,
+					ID:                     s.ID.String(),
+					Backend:                s.BackendType,
+					Command:                command,
+					Connections:            s.Connections,
+					Created:                s.CreatedAt.Local().Format(time.DateTime),
+				}
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				cliui.Infof(inv.Stderr, "No RPTY sessions found.")
+				return nil
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	// The shorthand of --column is taken by --container of the parent.
+	for i := range cmd.Options {
+		if cmd.Options[i].Flag == "column" {
+			cmd.Options[i].FlagShorthand = ""
+		}
+	}
+	return cmd
+}
+
+func (r *RootCmd) rptyAttach() *serpent.Command {
+	client := new(codersdk.Client)
+	return &serpent.Command{
+		Use:   "attach <workspace> <id>",
+		Short: "Attach to an existing RPTY session of a workspace/agent.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if r.disableDirect {
+				return xerrors.New("direct connections are disabled, but you can try websocat ;-)")
+			}
+			id, err := uuid.Parse(inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("invalid session ID: %w", err)
+			}
+			// Connecting with an unknown ID would start a new session.
+			sessions, err := r.rptySessions(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			if !slices.ContainsFunc(sessions, func(s workspacesdk.ReconnectingPTYSession) bool {
+				return s.ID == id
+			}) {
+				return xerrors.Errorf("RPTY session %s not found, run \"coder exp rpty list %s\" to list the sessions", id, inv.Args[0])
+			}
+			return handleRPTY(inv, client, handleRPTYArgs{
+				NamedWorkspace: inv.Args[0],
+				ReconnectID:    id.String(),
+			})
+		},
+	}
+}
+
+// rptySessions returns the RPTY sessions of the agent of a workspace.
+func (r *RootCmd) rptySessions(inv *serpent.Invocation, client *codersdk.Client, namedWorkspace string) ([]workspacesdk.ReconnectingPTYSession, error) {
+	ctx := inv.Context()
+	_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, namedWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	opts := &workspacesdk.DialAgentOptions{}
+	if r.verbose {
+		opts.Logger = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+	}
+	if r.disableDirect {
+		opts.BlockEndpoints = true
+	}
+	conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if !conn.AwaitReachable(ctx) {
+		return nil, xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+	}
+	resp, err := conn.ReconnectingPTYSessions(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("list RPTY sessions: %w", err)
+	}
+	return resp.Sessions, nil
+}
+
 type handleRPTYArgs struct {
+	BackendType    string
 	Command        []string
 	Container      string
 	ContainerUser  string
@@ -144,8 +292,8 @@ func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPT
 
 	// If a user does not specify a command, we'll assume they intend to open an
 	// interactive shell.
-	var backend string
-	if isOneShotCommand(args.Command) {
+	backend := args.BackendType
+	if backend == "" && isOneShotCommand(args.Command) {
 		// If the user specified a command, we'll prefer to use the buffered method.
 		// The screen backend is not well suited for one-shot commands.
 		backend = "buffered"
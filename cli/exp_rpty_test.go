@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"bytes"
 	"runtime"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 
@@ -67,6 +69,52 @@ func TestExpRpty(t *testing.T) {
 		<-cmdDone
 	})
 
+	t.Run("ListAndAttach", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The session runs bash")
+		}
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = agenttest.New(t, client.URL, agentToken)
+		resources := coderdtest.NewWorkspaceAgentWaiter(t, client, workspace.ID).Wait()
+
+		conn, err := workspacesdk.New(client).DialAgent(ctx, resources[0].Agents[0].ID, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		id := uuid.New()
+		netConn, err := conn.ReconnectingPTY(ctx, id, 80, 80, "bash --norc",
+			workspacesdk.AgentReconnectingPTYInitWithBackendType("buffered"))
+		require.NoError(t, err)
+		_ = netConn.Close()
+
+		inv, root := clitest.New(t, "exp", "rpty", "list", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		out := new(bytes.Buffer)
+		inv.Stdout = out
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, out.String(), id.String())
+		require.Contains(t, out.String(), "bash --norc")
+
+		inv, root = clitest.New(t, "exp", "rpty", "attach", workspace.Name, id.String())
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		pty.WriteLine("echo attached-$((1+1))")
+		pty.ExpectMatch("attached-2")
+		pty.WriteLine("exit")
+		<-cmdDone
+
+		inv, root = clitest.New(t, "exp", "rpty", "attach", workspace.Name, uuid.NewString())
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "not found")
+	})
+
 	t.Run("NotFound", func(t *testing.T) {
 		t.Parallel()
 
@@ -37,6 +37,7 @@ import (
 	"github.com/coder/coder/v2/scaletest/createworkspaces"
 	"github.com/coder/coder/v2/scaletest/dashboard"
 	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/multiagentconn"
 	"github.com/coder/coder/v2/scaletest/reconnectingpty"
 	"github.com/coder/coder/v2/scaletest/workspacebuild"
 	"github.com/coder/coder/v2/scaletest/workspacetraffic"
@@ -57,6 +58,7 @@ func (r *RootCmd) scaletestCmd() *serpent.Command {
 			r.scaletestDashboard(),
 			r.scaletestCreateWorkspaces(),
 			r.scaletestWorkspaceTraffic(),
+			r.scaletestMultiAgentConn(),
 		},
 	}
 
@@ -1149,6 +1151,205 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 	return cmd
 }
 
+func (r *RootCmd) scaletestMultiAgentConn() *serpent.Command {
+	var (
+		template         string
+		targetWorkspaces string
+		useHostLogin     bool
+		blockEndpoints   bool
+		holdDuration     time.Duration
+
+		client       = &codersdk.Client{}
+		tracingFlags = &scaletestTracingFlags{}
+		strategy     = &scaletestStrategyFlags{}
+		output       = &scaletestOutputFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "multi-agent-conn",
+		Short: "Connect to the agents of scaletest workspaces over a single tailnet connection and measure the memory used per agent",
+		Long:  "All agents are dialed from one connection, like a workspace proxy or a client that connects to many workspaces at once. The memory of this process is measured, so only one connection is opened per invocation.",
+		Middleware: serpent.Chain(
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) (err error) {
+			ctx := inv.Context()
+
+			notifyCtx, stop := signal.NotifyContext(ctx, StopSignals...) // Checked later.
+			defer stop()
+			ctx = notifyCtx
+
+			me, err := requireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if template != "" {
+				_, err := parseTemplate(ctx, client, me.OrganizationIDs, template)
+				if err != nil {
+					return xerrors.Errorf("parse template: %w", err)
+				}
+			}
+			targetWorkspaceStart, targetWorkspaceEnd, err := parseTargetRange("workspaces", targetWorkspaces)
+			if err != nil {
+				return xerrors.Errorf("parse target workspaces: %w", err)
+			}
+
+			var owner string
+			if useHostLogin {
+				owner = codersdk.Me
+			}
+
+			workspaces, numSkipped, err := getScaletestWorkspaces(ctx, client, owner, template)
+			if err != nil {
+				return err
+			}
+			if numSkipped > 0 {
+				cliui.Warnf(inv.Stdout, "CODER_DISABLE_OWNER_WORKSPACE_ACCESS is set on the deployment.\n\t%d workspace(s) were skipped due to ownership mismatch.\n\tSet --use-host-login to only target workspaces you own.", numSkipped)
+			}
+
+			if targetWorkspaceEnd == 0 {
+				targetWorkspaceEnd = len(workspaces)
+			}
+
+			if len(workspaces) == 0 {
+				return xerrors.Errorf("no scaletest workspaces exist")
+			}
+			if targetWorkspaceEnd > len(workspaces) {
+				return xerrors.Errorf("target workspace end %d is greater than the number of workspaces %d", targetWorkspaceEnd, len(workspaces))
+			}
+
+			config := multiagentconn.Config{
+				BlockEndpoints: blockEndpoints,
+				HoldDuration:   httpapi.Duration(holdDuration),
+			}
+			for idx, ws := range workspaces {
+				if idx < targetWorkspaceStart || idx >= targetWorkspaceEnd {
+					continue
+				}
+				var found bool
+				for _, res := range ws.LatestBuild.Resources {
+					for _, agent := range res.Agents {
+						config.AgentIDs = append(config.AgentIDs, agent.ID)
+						found = true
+					}
+				}
+				if !found {
+					_, _ = fmt.Fprintf(inv.Stderr, "WARN: skipping workspace %s: no agent\n", ws.Name)
+				}
+			}
+			if err := config.Validate(); err != nil {
+				return xerrors.Errorf("validate config: %w", err)
+			}
+
+			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+			tracer := tracerProvider.Tracer(scaletestTracerName)
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+
+			// The runner measures the heap of this process, so there is a
+			// single run no matter how many agents are targeted.
+			mr := multiagentconn.NewRunner(client, config)
+			var runner harness.Runnable = mr
+			if tracingEnabled {
+				runner = &runnableTraceWrapper{
+					tracer:   tracer,
+					spanName: "multi-agent-conn/0",
+					runner:   runner,
+				}
+			}
+			th := harness.NewTestHarness(strategy.toStrategy(), harness.LinearExecutionStrategy{})
+			th.AddRun("multi-agent-conn", "0", runner)
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			// If the command was interrupted, skip stats.
+			if notifyCtx.Err() != nil {
+				return notifyCtx.Err()
+			}
+
+			res := th.Results()
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			result := mr.Result()
+			_, _ = fmt.Fprintf(inv.Stderr, "\nConnected to %d agents using %d bytes of heap per agent.\n", result.Peers, result.BytesPerPeer())
+			return nil
+		},
+	}
+
+	cmd.Options = []serpent.Option{
+		{
+			Flag:          "template",
+			FlagShorthand: "t",
+			Env:           "CODER_SCALETEST_TEMPLATE",
+			Description:   "Name or ID of the template. Only agents of workspaces created from this template are targeted.",
+			Value:         serpent.StringOf(&template),
+		},
+		{
+			Flag:        "target-workspaces",
+			Env:         "CODER_SCALETEST_TARGET_WORKSPACES",
+			Description: "Target a specific range of workspaces in the format [START]:[END] (exclusive). Example: 0:10 will target the 10 first alphabetically sorted workspaces (0-9).",
+			Value:       serpent.StringOf(&targetWorkspaces),
+		},
+		{
+			Flag:        "use-host-login",
+			Env:         "CODER_SCALETEST_USE_HOST_LOGIN",
+			Default:     "false",
+			Description: "Connect as the currently logged in user.",
+			Value:       serpent.BoolOf(&useHostLogin),
+		},
+		{
+			Flag:        "block-endpoints",
+			Env:         "CODER_SCALETEST_MULTI_AGENT_CONN_BLOCK_ENDPOINTS",
+			Default:     "false",
+			Description: "Force the connections through DERP.",
+			Value:       serpent.BoolOf(&blockEndpoints),
+		},
+		{
+			Flag:        "hold-duration",
+			Env:         "CODER_SCALETEST_MULTI_AGENT_CONN_HOLD_DURATION",
+			Default:     "0s",
+			Description: "How long to hold the connections open for after all agents are reachable.",
+			Value:       serpent.DurationOf(&holdDuration),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	output.attach(&cmd.Options)
+
+	return cmd
+}
+
 func (r *RootCmd) scaletestDashboard() *serpent.Command {
 	var (
 		interval    time.Duration
@@ -157,6 +157,38 @@ func TestScaleTestWorkspaceTraffic_TargetWorkspaces(t *testing.T) {
 	require.ErrorContains(t, err, "invalid target workspaces \"0:0\": start and end cannot be equal")
 }
 
+// This test just validates that the CLI command accepts its known arguments.
+// A more comprehensive test is performed in multiagentconn/run_test.go
+func TestScaleTestMultiAgentConn(t *testing.T) {
+	t.Parallel()
+
+	if testutil.RaceEnabled() {
+		t.Skip("Skipping due to race detector")
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitMedium)
+	defer cancelFunc()
+
+	log := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	client := coderdtest.New(t, &coderdtest.Options{
+		Logger: &log,
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	inv, root := clitest.New(t, "exp", "scaletest", "multi-agent-conn",
+		"--timeout", "1s",
+		"--block-endpoints",
+		"--hold-duration", "1s",
+	)
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t)
+	inv.Stdout = pty.Output()
+	inv.Stderr = pty.Output()
+
+	err := inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "no scaletest workspaces exist")
+}
+
 // This test just validates that the CLI command accepts its known arguments.
 func TestScaleTestCleanup_Template(t *testing.T) {
 	t.Parallel()
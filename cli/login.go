@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -11,9 +15,11 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/browser"
+	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/pretty"
@@ -21,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/serpent"
 )
 
@@ -105,10 +112,24 @@ func (r *RootCmd) loginWithPassword(
 	client *codersdk.Client,
 	email, password string,
 ) error {
-	resp, err := client.LoginWithPassword(inv.Context(), codersdk.LoginWithPasswordRequest{
+	req := codersdk.LoginWithPasswordRequest{
 		Email:    email,
 		Password: password,
-	})
+	}
+	resp, err := client.LoginWithPassword(inv.Context(), req)
+	if codersdk.IsMFACodeRequired(err) {
+		if !isTTYIn(inv) {
+			return xerrors.New("a second factor is required to login. Run `coder login` interactively")
+		}
+		req.MFACode, err = cliui.Prompt(inv, cliui.PromptOptions{
+			Text:     "Enter the " + pretty.Sprint(cliui.DefaultStyles.Field, "code") + " from your authenticator app, or a recovery code:",
+			Validate: cliui.ValidateNotEmpty,
+		})
+		if err != nil {
+			return xerrors.Errorf("second factor prompt: %w", err)
+		}
+		resp, err = client.LoginWithPassword(inv.Context(), req)
+	}
 	if err != nil {
 		return xerrors.Errorf("login with password: %w", err)
 	}
@@ -122,6 +143,11 @@ func (r *RootCmd) loginWithPassword(
 
 	client.SetSessionToken(sessionToken)
 
+	err = enrollRequiredMFA(inv, client)
+	if err != nil {
+		return err
+	}
+
 	// Nice side-effect: validates the token.
 	u, err := client.User(inv.Context(), "me")
 	if err != nil {
@@ -137,6 +163,52 @@ func (r *RootCmd) loginWithPassword(
 	return nil
 }
 
+// enrollRequiredMFA enrolls a TOTP second factor for the authenticated user
+// if the deployment requires one and the user has yet to enable it. Until
+// then, the session can only be used to enroll.
+func enrollRequiredMFA(inv *serpent.Invocation, client *codersdk.Client) error {
+	ctx := inv.Context()
+	status, err := client.UserMFA(ctx, codersdk.Me)
+	if err != nil {
+		return xerrors.Errorf("get second factor status: %w", err)
+	}
+	if !status.Required || status.TOTPEnabled {
+		return nil
+	}
+	if !isTTYIn(inv) {
+		return xerrors.New("this deployment requires a second factor. Run `coder login` interactively to enroll one")
+	}
+
+	enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+	if err != nil {
+		return xerrors.Errorf("enroll second factor: %w", err)
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, Caret+"This deployment requires a second factor. Add this secret to your authenticator app:\n\n\t%s\n\nOr import this URL:\n\n\t%s\n\n",
+		pretty.Sprint(cliui.DefaultStyles.Code, enrollment.Secret), enrollment.URL)
+
+	var recovery codersdk.MFARecoveryCodes
+	_, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text: "Enter the " + pretty.Sprint(cliui.DefaultStyles.Field, "code") + " shown by your authenticator app:",
+		Validate: func(code string) error {
+			recovery, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: code})
+			if err != nil {
+				return xerrors.New("That code is invalid or expired!")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("verify second factor prompt: %w", err)
+	}
+
+	_, _ = fmt.Fprint(inv.Stdout, "\n"+Caret+"Your second factor is enabled. Store these recovery codes somewhere safe, each can be used once if you lose your authenticator app:\n\n")
+	for _, code := range recovery.RecoveryCodes {
+		_, _ = fmt.Fprintf(inv.Stdout, "\t%s\n", code)
+	}
+	_, _ = fmt.Fprintln(inv.Stdout)
+	return nil
+}
+
 func (r *RootCmd) login() *serpent.Command {
 	const firstUserTrialEnv = "CODER_FIRST_USER_TRIAL"
 
@@ -147,6 +219,10 @@ func (r *RootCmd) login() *serpent.Command {
 		password           string
 		trial              bool
 		useTokenForSession bool
+		passwordLogin      bool
+		passkeyLogin       bool
+		deviceLogin        bool
+		deviceClientID     string
 	)
 	cmd := &serpent.Command{
 		Use:        "login [<url>]",
@@ -341,8 +417,63 @@ func (r *RootCmd) login() *serpent.Command {
 				return nil
 			}
 
+			if passwordLogin {
+				loginEmail, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "What's your " + pretty.Sprint(cliui.DefaultStyles.Field, "email") + "?",
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return err
+				}
+				loginPassword, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "Enter your " + pretty.Sprint(cliui.DefaultStyles.Field, "password") + ":",
+					Secret:   true,
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return err
+				}
+				err = r.loginWithPassword(inv, client, loginEmail, loginPassword)
+				if err != nil {
+					return err
+				}
+				err = r.createConfig().URL().Write(serverURL.String())
+				if err != nil {
+					return xerrors.Errorf("write server url: %w", err)
+				}
+				_, _ = fmt.Fprintln(inv.Stdout)
+				return nil
+			}
+
 			sessionToken, _ := inv.ParsedFlags().GetString(varToken)
-			if sessionToken == "" {
+			if deviceLogin {
+				if passwordLogin || passkeyLogin || sessionToken != "" {
+					return xerrors.New("--device cannot be used with --password-login, --passkey or --token")
+				}
+				if deviceClientID == "" {
+					return xerrors.New("--device-client-id is required with --device")
+				}
+				accessToken, err := loginWithDevice(inv, client, deviceClientID)
+				if err != nil {
+					return err
+				}
+				// The access token expires quickly and belongs to the OAuth2
+				// app, so exchange it for a session key like --token does.
+				client.SetSessionToken(accessToken)
+				key, err := client.CreateAPIKey(ctx, "me")
+				if err != nil {
+					return xerrors.Errorf("create api key: %w", err)
+				}
+				sessionToken = key.Key
+			} else if passkeyLogin {
+				if passwordLogin || sessionToken != "" {
+					return xerrors.New("--passkey cannot be used with --password-login or --token")
+				}
+				sessionToken, err = loginWithPasskey(inv, serverURL)
+				if err != nil {
+					return err
+				}
+			} else if sessionToken == "" {
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
 				// for a url.
@@ -360,6 +491,11 @@ func (r *RootCmd) login() *serpent.Command {
 						client.SetSessionToken(token)
 						_, err := client.User(ctx, codersdk.Me)
 						if err != nil {
+							// Sessions of users that must enroll a second
+							// factor can only access their second factor.
+							if _, mfaErr := client.UserMFA(ctx, codersdk.Me); mfaErr == nil {
+								return nil
+							}
 							return xerrors.New("That's not a valid token!")
 						}
 						return err
@@ -388,6 +524,10 @@ func (r *RootCmd) login() *serpent.Command {
 
 			// Login to get user data - verify it is OK before persisting
 			client.SetSessionToken(sessionToken)
+			err = enrollRequiredMFA(inv, client)
+			if err != nil {
+				return err
+			}
 			resp, err := client.User(ctx, codersdk.Me)
 			if err != nil {
 				return xerrors.Errorf("get user: %w", err)
@@ -438,6 +578,27 @@ func (r *RootCmd) login() *serpent.Command {
 			Description: "Specifies whether a trial license should be provisioned for the Coder deployment or not.",
 			Value:       serpent.BoolOf(&trial),
 		},
+		{
+			Flag:        "password-login",
+			Description: "Authenticate with an email and password instead of a token generated in the browser. Prompts for a second factor if the user has enabled one.",
+			Value:       serpent.BoolOf(&passwordLogin),
+		},
+		{
+			Flag:        "passkey",
+			Description: "Authenticate with a passkey in the browser instead of pasting a token. The browser hands the session to the CLI on a loopback port.",
+			Value:       serpent.BoolOf(&passkeyLogin),
+		},
+		{
+			Flag:        "device",
+			Description: "Authenticate with the OAuth2 device authorization flow (RFC 8628) for machines without a browser. Prints a code to enter on any device that can open the deployment.",
+			Value:       serpent.BoolOf(&deviceLogin),
+		},
+		{
+			Flag:        "device-client-id",
+			Env:         "CODER_LOGIN_DEVICE_CLIENT_ID",
+			Description: "The client ID of a public OAuth2 app of the deployment to authenticate with when using --device.",
+			Value:       serpent.StringOf(&deviceClientID),
+		},
 		{
 			Flag:        "use-token-as-session",
 			Description: "By default, the CLI will generate a new session token when logging in. This flag will instead use the provided token as the session token.",
@@ -447,6 +608,99 @@ func (r *RootCmd) login() *serpent.Command {
 	return cmd
 }
 
+// loginWithPasskey signs in with a passkey in the browser and returns the
+// session token. The browser hands the token to a listener on the loopback
+// interface, so it never leaves the machine.
+func loginWithPasskey(inv *serpent.Invocation, serverURL *url.URL) (string, error) {
+	ctx := inv.Context()
+
+	state, err := cryptorand.String(32)
+	if err != nil {
+		return "", xerrors.Errorf("generate state: %w", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", xerrors.Errorf("listen: %w", err)
+	}
+	defer listener.Close()
+
+	tokens := make(chan string, 1)
+	srv := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			token := query.Get("session_token")
+			if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 || token == "" {
+				http.Error(rw, "Invalid login request.", http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprintln(rw, "You're signed in to the Coder CLI. You can close this window.")
+			select {
+			case tokens <- token:
+			default:
+			}
+		}),
+	}
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	redirectURI := url.URL{Scheme: "http", Host: listener.Addr().String(), Path: "/callback"}
+	authURL := serverURL.JoinPath("/api/v2/users/webauthn/cli-auth")
+	authURL.RawQuery = url.Values{
+		"redirect_uri": {redirectURI.String()},
+		"state":        {state},
+	}.Encode()
+	if err := openURL(inv, authURL.String()); err != nil {
+		_, _ = fmt.Fprintf(inv.Stdout, "Open the following in your browser to sign in with a passkey:\n\n\t%s\n\n", authURL.String())
+	} else {
+		_, _ = fmt.Fprintf(inv.Stdout, "Your browser has been opened to sign in with a passkey:\n\n\t%s\n\n", authURL.String())
+	}
+
+	select {
+	case token := <-tokens:
+		return token, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// loginWithDevice signs in with the OAuth2 device authorization grant and
+// returns the access token. The user approves the code in a browser on any
+// device, so this works on machines without one.
+func loginWithDevice(inv *serpent.Invocation, client *codersdk.Client, clientID string) (string, error) {
+	// Use the client's transport so custom headers and TLS settings apply.
+	ctx := context.WithValue(inv.Context(), oauth2.HTTPClient, client.HTTPClient)
+	cfg := &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: client.URL.JoinPath("/oauth2/device").String(),
+			TokenURL:      client.URL.JoinPath("/oauth2/tokens").String(),
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+	}
+	da, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return "", xerrors.Errorf("start device authorization: %w", err)
+	}
+	verifyURL := da.VerificationURIComplete
+	if verifyURL == "" {
+		verifyURL = da.VerificationURI
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, "Open the following on any device and confirm the code %s:\n\n\t%s\n\n", pretty.Sprint(cliui.DefaultStyles.Code, da.UserCode), verifyURL)
+
+	token, err := cfg.DeviceAccessToken(ctx, da)
+	if err != nil {
+		return "", xerrors.Errorf("wait for device authorization: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
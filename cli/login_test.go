@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,6 +19,8 @@ import (
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/webauthntest"
+	"github.com/coder/coder/v2/coderd/mfa"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
@@ -429,6 +434,117 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("ExistingUserPasswordMFA", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		code, err := mfa.TOTPCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+		recovery, err := client.VerifyTOTP(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: code})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		root, _ := clitest.New(t, "login", "--force-tty", client.URL.String(), "--password-login")
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		matches := []string{
+			"email", coderdtest.FirstUserParams.Email,
+			"password", coderdtest.FirstUserParams.Password,
+			"authenticator app", recovery.RecoveryCodes[0],
+		}
+		for i := 0; i < len(matches); i += 2 {
+			match := matches[i]
+			value := matches[i+1]
+			pty.ExpectMatch(match)
+			pty.WriteLine(value)
+		}
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+
+		status, err := client.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, len(recovery.RecoveryCodes)-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("ExistingUserPasskey", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		authenticator := webauthntest.New(t, (&url.URL{Scheme: client.URL.Scheme, Host: client.URL.Host}).String())
+		options, err := client.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Credential: authenticator.Register(options),
+			Password:   coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		root, _ := clitest.New(t, "login", "--force-tty", client.URL.String(), "--passkey", "--no-open")
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("sign in with a passkey")
+		var authURL *url.URL
+		for authURL == nil {
+			line := strings.TrimSpace(pty.ReadLine(ctx))
+			if strings.HasPrefix(line, "http") {
+				authURL, err = url.Parse(line)
+				require.NoError(t, err)
+			}
+		}
+
+		// Act as the browser: sign in with the passkey and hand the session
+		// token to the CLI.
+		options, err = client.WebAuthnLoginOptions(ctx)
+		require.NoError(t, err)
+		res, err := client.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			Credential: authenticator.Login(options),
+		})
+		require.NoError(t, err)
+		redirect, err := url.Parse(authURL.Query().Get("redirect_uri"))
+		require.NoError(t, err)
+		redirect.RawQuery = url.Values{
+			"state":         {authURL.Query().Get("state")},
+			"session_token": {res.SessionToken},
+		}.Encode()
+
+		// The state must match.
+		invalid := *redirect
+		invalid.RawQuery = url.Values{"state": {"invalid"}, "session_token": {res.SessionToken}}.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, invalid.String(), nil)
+		require.NoError(t, err)
+		callback, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = callback.Body.Close()
+		require.Equal(t, http.StatusBadRequest, callback.StatusCode)
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, redirect.String(), nil)
+		require.NoError(t, err)
+		callback, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = callback.Body.Close()
+		require.Equal(t, http.StatusOK, callback.StatusCode)
+
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+	})
+
 	t.Run("ExistingUserURLSavedInConfig", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -517,6 +633,66 @@ func TestLogin(t *testing.T) {
 		require.NotEqual(t, client.SessionToken(), sessionFile)
 	})
 
+	t.Run("DeviceFlag", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		//nolint:gocritic // OAuth2 app management requires owner permission.
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "coder-cli",
+			CallbackURL: "http://localhost:3000",
+			ClientType:  codersdk.OAuth2ProviderAppClientTypePublic,
+		})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		root, cfg := clitest.New(t, "login", "--force-tty", client.URL.String(), "--device", "--device-client-id", app.ID.String())
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("confirm the code")
+		var rawVerifyURL string
+		for rawVerifyURL == "" {
+			rawVerifyURL = strings.TrimSpace(pty.ReadLine(ctx))
+			if !strings.HasPrefix(rawVerifyURL, "http") {
+				rawVerifyURL = ""
+			}
+		}
+		verifyURL, err := url.Parse(rawVerifyURL)
+		require.NoError(t, err)
+		query := verifyURL.Query()
+		query.Set("action", "allow")
+		verifyURL.RawQuery = query.Encode()
+		// Approve the device as the user, the way the verification page does.
+		res, err := client.Request(ctx, http.MethodGet, verifyURL.String(), nil, func(req *http.Request) {
+			req.Header.Set("Referer", verifyURL.String())
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+		sessionFile, err := cfg.Session().Read()
+		require.NoError(t, err)
+		require.NotEmpty(t, sessionFile)
+		require.NotEqual(t, client.SessionToken(), sessionFile)
+	})
+
+	t.Run("DeviceFlagRequiresClientID", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		root, _ := clitest.New(t, "login", client.URL.String(), "--device")
+		err := root.Run()
+		require.ErrorContains(t, err, "--device-client-id is required")
+	})
+
 	t.Run("KeepOrganizationContext", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -37,7 +37,8 @@ func (r *RootCmd) netcheck() *serpent.Command {
 
 			var derpReport derphealth.Report
 			derpReport.Run(ctx, &derphealth.ReportOptions{
-				DERPMap: connInfo.DERPMap,
+				DERPMap:       connInfo.DERPMap,
+				DERPQUICPorts: connInfo.DERPQUICPorts,
 			})
 
 			ifReport, err := healthsdk.RunInterfacesReport()
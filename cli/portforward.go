@@ -38,6 +38,7 @@ func (r *RootCmd) portForward() *serpent.Command {
 		tcpForwards      []string // <port>:<port>
 		udpForwards      []string // <port>:<port>
 		disableAutostart bool
+		profile          string
 		appearanceConfig codersdk.AppearanceConfig
 	)
 	client := new(codersdk.Client)
@@ -66,13 +67,27 @@ func (r *RootCmd) portForward() *serpent.Command {
 				Description: "Port forward specifying the local address to bind to",
 				Command:     "coder port-forward <workspace> --tcp 1.2.3.4:8080:8080",
 			},
+			Example{
+				Description: "Hold the forwards of a profile, which reconnect when their workspaces restart",
+				Command:     "coder port-forward --profile forwards.yaml",
+			},
 		),
 		Middleware: serpent.Chain(
-			serpent.RequireNArgs(1),
+			serpent.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 			initAppearance(client, &appearanceConfig),
 		),
 		Handler: func(inv *serpent.Invocation) error {
+			if profile != "" {
+				if len(inv.Args) > 0 || len(tcpForwards) > 0 || len(udpForwards) > 0 {
+					return xerrors.New("--profile cannot be combined with a workspace or ports")
+				}
+				return r.portForwardProfile(inv, client, profile)
+			}
+			if len(inv.Args) != 1 {
+				return xerrors.New("a workspace is required")
+			}
+
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
@@ -144,24 +159,11 @@ func (r *RootCmd) portForward() *serpent.Command {
 			defer closeAllListeners()
 
 			for _, spec := range specs {
-				if spec.listenHost == noAddr {
-					// first, opportunistically try to listen on IPv6
-					spec6 := spec
-					spec6.listenHost = ipv6Loopback
-					l6, err6 := listenAndPortForward(ctx, inv, conn, wg, spec6, logger)
-					if err6 != nil {
-						logger.Info(ctx, "failed to opportunistically listen on IPv6", slog.F("spec", spec), slog.Error(err6))
-					} else {
-						listeners = append(listeners, l6)
-					}
-					spec.listenHost = ipv4Loopback
-				}
-				l, err := listenAndPortForward(ctx, inv, conn, wg, spec, logger)
+				ls, err := listenAndPortForwardSpec(ctx, inv, conn, wg, spec, logger)
+				listeners = append(listeners, ls...)
 				if err != nil {
-					logger.Error(ctx, "failed to listen", slog.F("spec", spec), slog.Error(err))
 					return err
 				}
-				listeners = append(listeners, l)
 			}
 
 			stopUpdating := client.UpdateWorkspaceUsageContext(ctx, workspace.ID)
@@ -212,16 +214,59 @@ func (r *RootCmd) portForward() *serpent.Command {
 			Description: "Forward UDP port(s) from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols.",
 			Value:       serpent.StringArrayOf(&udpForwards),
 		},
+		{
+			Flag: "profile",
+			Env:  "CODER_PORT_FORWARD_PROFILE",
+			Description: "Path to a YAML or JSON profile of forwards to any of your workspaces, including unix sockets and " +
+				"reverse forwards. The forwards share one connection and reconnect when their workspaces restart.",
+			Value: serpent.StringOf(&profile),
+		},
 		sshDisableAutostartOption(serpent.BoolOf(&disableAutostart)),
 	}
 
 	return cmd
 }
 
+// portForwardDialer dials an address in a workspace, the host is ignored.
+type portForwardDialer interface {
+	DialContext(ctx context.Context, network string, addr string) (net.Conn, error)
+}
+
+// listenAndPortForwardSpec listens for the spec, on both loopback addresses if
+// the spec has no local address.
+func listenAndPortForwardSpec(
+	ctx context.Context,
+	inv *serpent.Invocation,
+	conn portForwardDialer,
+	wg *sync.WaitGroup,
+	spec portForwardSpec,
+	logger slog.Logger,
+) ([]net.Listener, error) {
+	var listeners []net.Listener
+	if spec.listenHost == noAddr {
+		// first, opportunistically try to listen on IPv6
+		spec6 := spec
+		spec6.listenHost = ipv6Loopback
+		l6, err6 := listenAndPortForward(ctx, inv, conn, wg, spec6, logger)
+		if err6 != nil {
+			logger.Info(ctx, "failed to opportunistically listen on IPv6", slog.F("spec", spec), slog.Error(err6))
+		} else {
+			listeners = append(listeners, l6)
+		}
+		spec.listenHost = ipv4Loopback
+	}
+	l, err := listenAndPortForward(ctx, inv, conn, wg, spec, logger)
+	if err != nil {
+		logger.Error(ctx, "failed to listen", slog.F("spec", spec), slog.Error(err))
+		return listeners, err
+	}
+	return append(listeners, l), nil
+}
+
 func listenAndPortForward(
 	ctx context.Context,
 	inv *serpent.Invocation,
-	conn *workspacesdk.AgentConn,
+	conn portForwardDialer,
 	wg *sync.WaitGroup,
 	spec portForwardSpec,
 	logger slog.Logger,
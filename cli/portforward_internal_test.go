@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/serpent"
 )
 
 func Test_parsePortForwards(t *testing.T) {
@@ -116,3 +121,90 @@ func Test_parsePortForwards(t *testing.T) {
 		})
 	}
 }
+
+func Test_portForwardProfileMappings(t *testing.T) {
+	t.Parallel()
+
+	profile := portForwardProfile{Forwards: []portForwardProfileEntry{{
+		Workspace: "alice/dev",
+		Agent:     "main",
+		TCP:       []string{"8080"},
+		UDP:       []string{"5353:53"},
+		Unix:      []string{"/tmp/docker.sock:/var/run/docker.sock"},
+		Reverse:   []string{"3000:127.0.0.1:3000"},
+	}}}
+	mappings, err := profile.mappings("alice")
+	require.NoError(t, err)
+	got := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		got = append(got, m.String())
+	}
+	require.Equal(t, []string{
+		"tcp localhost:8080 -> dev.main:8080",
+		"udp localhost:5353 -> dev.main:53",
+		"unix /tmp/docker.sock -> dev.main:/var/run/docker.sock",
+		"reverse dev.main:127.0.0.1:3000 -> 127.0.0.1:3000",
+	}, got)
+
+	_, err = profile.mappings("bob")
+	require.ErrorContains(t, err, "not yours")
+	_, err = portForwardProfile{}.mappings("alice")
+	require.ErrorContains(t, err, "no forwards")
+	_, err = portForwardProfile{Forwards: []portForwardProfileEntry{{
+		Workspace: "dev",
+		Unix:      []string{"relative.sock:/remote.sock"},
+	}}}.mappings("alice")
+	require.ErrorContains(t, err, "local_path:remote_path")
+}
+
+func Test_portForwardProfileRunnerUpdate(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+	target := portForwardTarget{workspace: "dev"}
+	runner := &portForwardProfileRunner{
+		inv:        &serpent.Invocation{Stderr: &stderr},
+		mappings:   []*portForwardMapping{{target: target, network: "tcp", spec: portForwardSpec{network: "tcp", listenPort: 8080, dialPort: 8080}}},
+		connected:  make(chan struct{}),
+		workspaces: map[uuid.UUID]*tailnet.Workspace{},
+		agents:     map[uuid.UUID]*tailnet.Agent{},
+		targets:    map[portForwardTarget]*portForwardAgent{},
+		status:     map[*portForwardMapping]string{},
+	}
+	defer runner.close()
+
+	workspace := &tailnet.Workspace{ID: uuid.New(), Name: "dev"}
+	first := &tailnet.Agent{ID: uuid.New(), Name: "main", WorkspaceID: workspace.ID}
+	require.NoError(t, runner.Update(tailnet.WorkspaceUpdate{
+		Kind:               tailnet.Snapshot,
+		UpsertedWorkspaces: []*tailnet.Workspace{workspace},
+	}))
+	require.Nil(t, runner.targets[target])
+	require.NoError(t, runner.Update(tailnet.WorkspaceUpdate{
+		Kind:           tailnet.Diff,
+		UpsertedAgents: []*tailnet.Agent{first},
+	}))
+	firstAgent := runner.targets[target]
+	require.NotNil(t, firstAgent)
+	require.Equal(t, first.ID, firstAgent.id)
+
+	// The workspace restarts with a new agent.
+	second := &tailnet.Agent{ID: uuid.New(), Name: "main", WorkspaceID: workspace.ID}
+	require.NoError(t, runner.Update(tailnet.WorkspaceUpdate{
+		Kind:           tailnet.Diff,
+		DeletedAgents:  []*tailnet.Agent{first},
+		UpsertedAgents: []*tailnet.Agent{second},
+	}))
+	require.Error(t, firstAgent.ctx.Err())
+	require.Equal(t, second.ID, runner.targets[target].id)
+
+	// The workspace stops.
+	require.NoError(t, runner.Update(tailnet.WorkspaceUpdate{
+		Kind:          tailnet.Diff,
+		DeletedAgents: []*tailnet.Agent{second},
+	}))
+	require.Nil(t, runner.targets[target])
+	require.Equal(t, "tcp localhost:8080 -> dev:8080: waiting for agent\n"+
+		"tcp localhost:8080 -> dev:8080: connecting\n"+
+		"tcp localhost:8080 -> dev:8080: waiting for agent\n", stderr.String())
+}
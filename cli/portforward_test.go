@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -380,6 +382,103 @@ func TestPortForward(t *testing.T) {
 	})
 }
 
+func TestPortForwardProfile(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	workspace := runAgent(t, client, memberUser.ID, db)
+
+	// The agent runs in the test process, so local listeners and sockets
+	// are in the "workspace" too.
+	tcpPort := setupTestListener(t, newTCPTestListener(t))
+	dir := tempDirUnixSocket(t)
+	remoteSocket := filepath.Join(dir, "remote.sock")
+	acceptUnix(t, remoteSocket)
+	reverseLocalSocket := filepath.Join(dir, "reverse-local.sock")
+	acceptUnix(t, reverseLocalSocket)
+	reverseRemoteSocket := filepath.Join(dir, "reverse-remote.sock")
+
+	profile := filepath.Join(t.TempDir(), "forwards.yaml")
+	err := os.WriteFile(profile, []byte(fmt.Sprintf(`forwards:
+  - workspace: %s
+    tcp: ["5555:%s"]
+    unix: ["%s:%s"]
+    reverse: ["%s:%s"]
+`, workspace.Name, tcpPort, filepath.Join(dir, "local.sock"), remoteSocket, reverseRemoteSocket, reverseLocalSocket)), 0o600)
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "port-forward", "--profile", profile)
+	clitest.SetupConfig(t, member, root)
+	pty := ptytest.New(t).Attach(inv)
+	iNet := newInProcNet()
+	inv.Net = iNet
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	errC := make(chan error)
+	go func() {
+		errC <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatchContext(ctx, "Ready!")
+	pty.ExpectMatchContext(ctx, "reverse "+workspace.Name+":"+reverseRemoteSocket+" -> "+reverseLocalSocket+": ready")
+
+	c1, err := iNet.dial(ctx, addr{"tcp", "127.0.0.1:5555"})
+	require.NoError(t, err)
+	defer c1.Close()
+	testDial(t, c1)
+
+	c2, err := iNet.dial(ctx, addr{"unix", filepath.Join(dir, "local.sock")})
+	require.NoError(t, err)
+	defer c2.Close()
+	testDial(t, c2)
+
+	var d net.Dialer
+	c3, err := d.DialContext(ctx, "unix", reverseRemoteSocket)
+	require.NoError(t, err)
+	defer c3.Close()
+	testDial(t, c3)
+
+	cancel()
+	require.NoError(t, <-errC)
+}
+
+func newTCPTestListener(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "create TCP listener")
+	return l
+}
+
+// acceptUnix listens on the socket and echoes a single packet per connection.
+func acceptUnix(t *testing.T, path string) {
+	t.Helper()
+
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err, "create unix listener")
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		_ = l.Close()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				testAccept(t, c)
+			}()
+		}
+	}()
+}
+
 // runAgent creates a fake workspace and starts an agent locally for that
 // workspace. The agent will be cleaned up on test completion.
 // nolint:unused
@@ -0,0 +1,580 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/vpn"
+	"github.com/coder/serpent"
+)
+
+// portForwardProfile describes forwards to any number of workspaces, which
+// `coder port-forward --profile` holds over one tailnet connection. Profiles
+// are YAML or JSON:
+//
+//	forwards:
+//	  - workspace: my-workspace
+//	    agent: main
+//	    tcp: ["8080", "127.0.0.1:5432:5432"]
+//	    udp: ["5353:53"]
+//	    unix: ["/tmp/docker.sock:/var/run/docker.sock"]
+//	    reverse: ["3000:127.0.0.1:3000", "/tmp/remote.sock:/tmp/local.sock"]
+type portForwardProfile struct {
+	Forwards []portForwardProfileEntry `yaml:"forwards"`
+}
+
+type portForwardProfileEntry struct {
+	// Workspace is the name of one of your workspaces, optionally prefixed
+	// with your username or "me/".
+	Workspace string `yaml:"workspace"`
+	// Agent is required if the workspace has more than one agent.
+	Agent string `yaml:"agent"`
+	// TCP and UDP use the format of the --tcp and --udp flags.
+	TCP []string `yaml:"tcp"`
+	UDP []string `yaml:"udp"`
+	// Unix forwards local sockets to sockets in the workspace, as
+	// local_path:remote_path.
+	Unix []string `yaml:"unix"`
+	// Reverse forwards ports and sockets in the workspace to the local
+	// machine, in the format of `coder ssh -R`.
+	Reverse []string `yaml:"reverse"`
+}
+
+// portForwardTarget is an agent of a workspace named by a profile.
+type portForwardTarget struct {
+	workspace string
+	agent     string
+}
+
+func (t portForwardTarget) String() string {
+	if t.agent == "" {
+		return t.workspace
+	}
+	return t.workspace + "." + t.agent
+}
+
+// portForwardMapping is a single forward of a profile.
+type portForwardMapping struct {
+	target portForwardTarget
+	// network is tcp, udp, unix or reverse.
+	network string
+	// spec is set for tcp and udp.
+	spec portForwardSpec
+	// local and remote are set for unix and reverse.
+	local, remote net.Addr
+}
+
+func (m *portForwardMapping) String() string {
+	switch m.network {
+	case "tcp", "udp":
+		local := "localhost"
+		if m.spec.listenHost != noAddr {
+			local = m.spec.listenHost.String()
+		}
+		return fmt.Sprintf("%s %s -> %s:%d", m.network,
+			net.JoinHostPort(local, strconv.Itoa(int(m.spec.listenPort))), m.target, m.spec.dialPort)
+	case "unix":
+		return fmt.Sprintf("unix %s -> %s:%s", m.local, m.target, m.remote)
+	default:
+		return fmt.Sprintf("reverse %s:%s -> %s", m.target, m.remote, m.local)
+	}
+}
+
+// readPortForwardProfile reads and validates a profile. The workspaces must be
+// owned by username.
+func readPortForwardProfile(path, username string) ([]*portForwardMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read profile: %w", err)
+	}
+	var profile portForwardProfile
+	// JSON is valid YAML.
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err = dec.Decode(&profile)
+	if err != nil {
+		return nil, xerrors.Errorf("parse profile %q: %w", path, err)
+	}
+	return profile.mappings(username)
+}
+
+func (p portForwardProfile) mappings(username string) ([]*portForwardMapping, error) {
+	if len(p.Forwards) == 0 {
+		return nil, xerrors.New("profile has no forwards")
+	}
+	var mappings []*portForwardMapping
+	for i, entry := range p.Forwards {
+		owner, name, ok := strings.Cut(entry.Workspace, "/")
+		if !ok {
+			owner, name = codersdk.Me, entry.Workspace
+		}
+		if name == "" {
+			return nil, xerrors.Errorf("forward %d: workspace is required", i)
+		}
+		if owner != codersdk.Me && owner != username {
+			return nil, xerrors.Errorf("forward %d: workspace %q is not yours, profiles can only forward to your own workspaces", i, entry.Workspace)
+		}
+		target := portForwardTarget{workspace: name, agent: entry.Agent}
+
+		specs, err := parsePortForwards(entry.TCP, entry.UDP)
+		if err != nil {
+			return nil, xerrors.Errorf("forward %d: %w", i, err)
+		}
+		for _, spec := range specs {
+			mappings = append(mappings, &portForwardMapping{target: target, network: spec.network, spec: spec})
+		}
+		for _, unix := range entry.Unix {
+			matches := remoteForwardRegexUnixSocket.FindStringSubmatch(unix)
+			if len(matches) == 0 {
+				return nil, xerrors.Errorf("forward %d: invalid unix socket forward %q, the format is local_path:remote_path", i, unix)
+			}
+			mappings = append(mappings, &portForwardMapping{
+				target:  target,
+				network: "unix",
+				local:   &net.UnixAddr{Name: matches[1], Net: "unix"},
+				remote:  &net.UnixAddr{Name: matches[2], Net: "unix"},
+			})
+		}
+		for _, reverse := range entry.Reverse {
+			local, remote, err := parseRemoteForward(reverse)
+			if err != nil {
+				return nil, xerrors.Errorf("forward %d: invalid reverse forward %q: %w", i, reverse, err)
+			}
+			mappings = append(mappings, &portForwardMapping{target: target, network: "reverse", local: local, remote: remote})
+		}
+	}
+	if len(mappings) == 0 {
+		return nil, xerrors.New("profile has no forwards")
+	}
+	return mappings, nil
+}
+
+// portForwardProfileRunner holds the mappings of a profile. It follows the
+// workspace updates of the tailnet connection, so the forwards move to the new
+// agents when workspaces restart.
+type portForwardProfileRunner struct {
+	inv      *serpent.Invocation
+	client   *codersdk.Client
+	logger   slog.Logger
+	mappings []*portForwardMapping
+
+	// connected is closed once conn is set.
+	connected chan struct{}
+	conn      vpn.Conn
+
+	mu         sync.Mutex
+	workspaces map[uuid.UUID]*tailnet.Workspace
+	agents     map[uuid.UUID]*tailnet.Agent
+	targets    map[portForwardTarget]*portForwardAgent
+	status     map[*portForwardMapping]string
+}
+
+// portForwardAgent is the current agent of a target. Its context ends when the
+// agent goes away.
+type portForwardAgent struct {
+	id          uuid.UUID
+	workspaceID uuid.UUID
+	ip          netip.Addr
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	sshMu     sync.Mutex
+	sshClient *gossh.Client
+}
+
+var _ tailnet.UpdatesHandler = &portForwardProfileRunner{}
+
+func (r *RootCmd) portForwardProfile(inv *serpent.Invocation, client *codersdk.Client, path string) error {
+	ctx, cancel := inv.SignalNotifyContext(inv.Context(), StopSignals...)
+	defer cancel()
+
+	logger := inv.Logger
+	if r.verbose {
+		logger = logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+	}
+
+	me, err := client.User(ctx, codersdk.Me)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	mappings, err := readPortForwardProfile(path, me.Username)
+	if err != nil {
+		return err
+	}
+
+	runner := &portForwardProfileRunner{
+		inv:        inv,
+		client:     client,
+		logger:     logger,
+		mappings:   mappings,
+		connected:  make(chan struct{}),
+		workspaces: map[uuid.UUID]*tailnet.Workspace{},
+		agents:     map[uuid.UUID]*tailnet.Agent{},
+		targets:    map[portForwardTarget]*portForwardAgent{},
+		status:     map[*portForwardMapping]string{},
+	}
+	defer runner.close()
+
+	// Listen before connecting, so busy local addresses fail early.
+	wg := new(sync.WaitGroup)
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+		wg.Wait()
+	}()
+	for _, m := range mappings {
+		var ls []net.Listener
+		switch m.network {
+		case "tcp", "udp":
+			ls, err = listenAndPortForwardSpec(ctx, inv, runner.dialer(m.target), wg, m.spec, logger)
+		case "unix":
+			var l net.Listener
+			l, err = runner.listenUnix(ctx, wg, m)
+			ls = []net.Listener{l}
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, ls...)
+		runner.setStatus(m, "waiting for workspace")
+	}
+
+	transport, err := r.HeaderTransport(ctx, client.URL)
+	if err != nil {
+		return xerrors.Errorf("create header transport: %w", err)
+	}
+	conn, err := vpn.NewClient().NewConn(ctx, client.URL, client.SessionToken(), &vpn.Options{
+		Headers:       transport.Header,
+		Logger:        logger.Named("tailnet"),
+		UpdateHandler: runner,
+	})
+	if err != nil {
+		return xerrors.Errorf("connect to tailnet: %w", err)
+	}
+	defer conn.Close()
+	runner.conn = conn
+	close(runner.connected)
+
+	_, _ = fmt.Fprintln(inv.Stderr, "Ready! Forwards reconnect when their workspaces restart.")
+	<-ctx.Done()
+	return nil
+}
+
+// Update moves the targets to the agents in the update. It is called with the
+// full state first.
+func (p *portForwardProfileRunner) Update(update tailnet.WorkspaceUpdate) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if update.Kind == tailnet.Snapshot {
+		clear(p.workspaces)
+		clear(p.agents)
+	}
+	for _, w := range update.UpsertedWorkspaces {
+		p.workspaces[w.ID] = w
+	}
+	for _, a := range update.UpsertedAgents {
+		p.agents[a.ID] = a
+	}
+	for _, w := range update.DeletedWorkspaces {
+		delete(p.workspaces, w.ID)
+	}
+	for _, a := range update.DeletedAgents {
+		delete(p.agents, a.ID)
+	}
+
+	for _, m := range p.mappings {
+		target := m.target
+		agent, reason := p.findAgentLocked(target)
+		current := p.targets[target]
+		if current != nil && agent != nil && current.id == agent.ID {
+			continue
+		}
+		if current != nil {
+			current.close()
+			delete(p.targets, target)
+		}
+		if agent == nil {
+			p.setTargetStatusLocked(target, reason)
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		current = &portForwardAgent{
+			id:          agent.ID,
+			workspaceID: agent.WorkspaceID,
+			ip:          tailnet.CoderServicePrefix.AddrFromUUID(agent.ID),
+			ctx:         ctx,
+			cancel:      cancel,
+		}
+		p.targets[target] = current
+		p.setTargetStatusLocked(target, "connecting")
+		go p.connect(target, current)
+	}
+	return nil
+}
+
+// findAgentLocked returns the agent of the target, or why there is none.
+func (p *portForwardProfileRunner) findAgentLocked(target portForwardTarget) (*tailnet.Agent, string) {
+	var workspace *tailnet.Workspace
+	for _, w := range p.workspaces {
+		if w.Name == target.workspace {
+			workspace = w
+			break
+		}
+	}
+	if workspace == nil {
+		return nil, "waiting for workspace"
+	}
+	var agents []*tailnet.Agent
+	for _, a := range p.agents {
+		if a.WorkspaceID == workspace.ID && (target.agent == "" || a.Name == target.agent) {
+			agents = append(agents, a)
+		}
+	}
+	switch len(agents) {
+	case 0:
+		return nil, "waiting for agent"
+	case 1:
+		return agents[0], ""
+	default:
+		return nil, "workspace has multiple agents, set the agent in the profile"
+	}
+}
+
+// connect waits for the agent to be reachable and starts the reverse forwards.
+func (p *portForwardProfileRunner) connect(target portForwardTarget, agent *portForwardAgent) {
+	select {
+	case <-p.connected:
+	case <-agent.ctx.Done():
+		return
+	}
+	if !p.conn.AwaitReachable(agent.ctx, agent.ip) {
+		return
+	}
+	stopUpdating := p.client.UpdateWorkspaceUsageContext(agent.ctx, agent.workspaceID)
+	defer stopUpdating()
+
+	var closers []io.Closer
+	for _, m := range p.mappings {
+		if m.target != target {
+			continue
+		}
+		if m.network != "reverse" {
+			p.setAgentStatus(agent, m, "ready")
+			continue
+		}
+		sshClient, err := p.ssh(agent.ctx, agent)
+		if err == nil {
+			var closer io.Closer
+			closer, err = sshRemoteForward(agent.ctx, p.inv.Stderr, sshClient, m.local, m.remote)
+			if err == nil {
+				closers = append(closers, closer)
+			}
+		}
+		if err != nil {
+			p.setAgentStatus(agent, m, fmt.Sprintf("failed: %v", err))
+			continue
+		}
+		p.setAgentStatus(agent, m, "ready")
+	}
+
+	<-agent.ctx.Done()
+	for _, c := range closers {
+		_ = c.Close()
+	}
+}
+
+// dialer dials the current agent of the target.
+func (p *portForwardProfileRunner) dialer(target portForwardTarget) portForwardDialer {
+	return portForwardDialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		agent, err := p.agent(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		_, rawPort, _ := net.SplitHostPort(addr)
+		port, _ := strconv.ParseUint(rawPort, 10, 16)
+		ipp := netip.AddrPortFrom(agent.ip, uint16(port))
+		switch network {
+		case "tcp":
+			return p.conn.DialContextTCP(ctx, ipp)
+		case "udp":
+			return p.conn.DialContextUDP(ctx, ipp)
+		default:
+			return nil, xerrors.Errorf("unknown network %q", network)
+		}
+	})
+}
+
+type portForwardDialerFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (f portForwardDialerFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// agent returns the current agent of the target once connected.
+func (p *portForwardProfileRunner) agent(ctx context.Context, target portForwardTarget) (*portForwardAgent, error) {
+	select {
+	case <-p.connected:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	agent := p.targets[target]
+	p.mu.Unlock()
+	if agent == nil {
+		return nil, xerrors.Errorf("workspace %s is not running", target)
+	}
+	return agent, nil
+}
+
+// listenUnix forwards a local socket to a socket in the workspace over SSH.
+func (p *portForwardProfileRunner) listenUnix(ctx context.Context, wg *sync.WaitGroup, m *portForwardMapping) (net.Listener, error) {
+	_, _ = fmt.Fprintf(p.inv.Stderr, "Forwarding '%s' locally to '%s' in the workspace\n", m.local, m.remote)
+	l, err := p.inv.Net.Listen("unix", m.local.String())
+	if err != nil {
+		return nil, xerrors.Errorf("listen 'unix://%s': %w", m.local, err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			netConn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer netConn.Close()
+				remoteConn, err := p.dialUnix(ctx, m)
+				if err != nil {
+					_, _ = fmt.Fprintf(p.inv.Stderr, "Failed to dial 'unix://%s' in workspace: %s\n", m.remote, err)
+					return
+				}
+				defer remoteConn.Close()
+				agentssh.Bicopy(ctx, netConn, remoteConn)
+			}()
+		}
+	}()
+	return l, nil
+}
+
+func (p *portForwardProfileRunner) dialUnix(ctx context.Context, m *portForwardMapping) (net.Conn, error) {
+	agent, err := p.agent(ctx, m.target)
+	if err != nil {
+		return nil, err
+	}
+	sshClient, err := p.ssh(ctx, agent)
+	if err != nil {
+		return nil, err
+	}
+	return sshClient.DialContext(ctx, "unix", m.remote.String())
+}
+
+// ssh returns the SSH client of the agent, which is shared by its unix socket
+// and reverse forwards.
+func (p *portForwardProfileRunner) ssh(ctx context.Context, agent *portForwardAgent) (*gossh.Client, error) {
+	agent.sshMu.Lock()
+	defer agent.sshMu.Unlock()
+	if agent.sshClient != nil {
+		return agent.sshClient, nil
+	}
+	netConn, err := p.conn.DialContextTCP(ctx, netip.AddrPortFrom(agent.ip, workspacesdk.AgentSSHPort))
+	if err != nil {
+		return nil, xerrors.Errorf("dial ssh: %w", err)
+	}
+	sshConn, channels, requests, err := gossh.NewClientConn(netConn, "localhost:22", &gossh.ClientConfig{
+		// The tailnet connection already identifies the workspace.
+		// #nosec
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		_ = netConn.Close()
+		return nil, xerrors.Errorf("ssh conn: %w", err)
+	}
+	sshClient := gossh.NewClient(sshConn, channels, requests)
+	agent.sshClient = sshClient
+	go func() {
+		// Reconnect on the next use if the connection breaks.
+		_ = sshClient.Wait()
+		agent.sshMu.Lock()
+		if agent.sshClient == sshClient {
+			agent.sshClient = nil
+		}
+		agent.sshMu.Unlock()
+	}()
+	return sshClient, nil
+}
+
+func (a *portForwardAgent) close() {
+	a.cancel()
+	a.sshMu.Lock()
+	defer a.sshMu.Unlock()
+	if a.sshClient != nil {
+		_ = a.sshClient.Close()
+		a.sshClient = nil
+	}
+}
+
+func (p *portForwardProfileRunner) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for target, agent := range p.targets {
+		agent.close()
+		delete(p.targets, target)
+	}
+}
+
+func (p *portForwardProfileRunner) setTargetStatusLocked(target portForwardTarget, status string) {
+	for _, m := range p.mappings {
+		if m.target == target {
+			p.setStatusLocked(m, status)
+		}
+	}
+}
+
+func (p *portForwardProfileRunner) setStatus(m *portForwardMapping, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setStatusLocked(m, status)
+}
+
+// setAgentStatus sets the status unless the agent was replaced meanwhile.
+func (p *portForwardProfileRunner) setAgentStatus(agent *portForwardAgent, m *portForwardMapping, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.targets[m.target] == agent {
+		p.setStatusLocked(m, status)
+	}
+}
+
+// setStatusLocked reports the status of the mapping if it changed.
+func (p *portForwardProfileRunner) setStatusLocked(m *portForwardMapping, status string) {
+	if p.status[m] == status {
+		return
+	}
+	p.status[m] = status
+	_, _ = fmt.Fprintf(p.inv.Stderr, "%s: %s\n", m, status)
+}
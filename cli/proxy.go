@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/vpn"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) proxy() *serpent.Command {
+	var (
+		address string
+		direct  bool
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "proxy",
+		Short: "Reach all of your workspaces through a local SOCKS5 and HTTP proxy",
+		Long: "The proxy connects to your workspaces without a TUN device, so it " +
+			"needs no root privileges. Workspace agents are reachable with names " +
+			"like <agent>.<workspace>.<owner>.coder.\n\n" + FormatExamples(
+			Example{
+				Description: "Start the proxy on the default address",
+				Command:     "coder proxy",
+			},
+			Example{
+				Description: "Fetch port 8080 of a workspace through the proxy",
+				Command:     "curl -x socks5h://127.0.0.1:1080 http://main.ws.me.coder:8080",
+			},
+			Example{
+				Description: "Also connect to hosts that are not workspaces, for use as a browser proxy",
+				Command:     "coder proxy --direct",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := inv.SignalNotifyContext(inv.Context(), StopSignals...)
+			defer cancel()
+
+			logger := inv.Logger
+			if r.verbose {
+				logger = logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+
+			transport, err := r.HeaderTransport(ctx, client.URL)
+			if err != nil {
+				return xerrors.Errorf("create header transport: %w", err)
+			}
+			connInfo, err := workspacesdk.New(client).AgentConnectionInfoGeneric(ctx)
+			if err != nil {
+				return xerrors.Errorf("get connection info: %w", err)
+			}
+
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				return xerrors.Errorf("listen on %q: %w", address, err)
+			}
+			defer ln.Close()
+
+			// The connection is created after the proxy, which receives
+			// the workspace names from it.  Connections are only dialed
+			// once the proxy serves.
+			var conn vpn.Conn
+			opts := vpn.ProxyOptions{
+				Logger: logger.Named("proxy"),
+				Dial: func(ctx context.Context, ipp netip.AddrPort) (net.Conn, error) {
+					c, err := conn.DialContextTCP(ctx, ipp)
+					if err != nil {
+						return nil, err
+					}
+					return c, nil
+				},
+				Suffix: connInfo.HostnameSuffix,
+			}
+			if direct {
+				var d net.Dialer
+				opts.DirectDial = d.DialContext
+			}
+			proxy := vpn.NewProxy(opts)
+
+			conn, err = vpn.NewClient().NewConn(ctx, client.URL, client.SessionToken(), &vpn.Options{
+				Headers:        transport.Header,
+				Logger:         logger.Named("tailnet"),
+				DNSHostsSetter: proxy,
+			})
+			if err != nil {
+				return xerrors.Errorf("connect to tailnet: %w", err)
+			}
+			defer conn.Close()
+
+			proxyAddr := ln.Addr().String()
+			_, _ = fmt.Fprintf(inv.Stdout, "Proxying your workspaces on %s with SOCKS5 and HTTP.\n", cliui.Code(proxyAddr))
+			_, _ = fmt.Fprintf(inv.Stdout, "Set %s or %s to use it, or run:\n\n  %s\n\n",
+				cliui.Code("ALL_PROXY=socks5h://"+proxyAddr),
+				cliui.Code("HTTPS_PROXY=http://"+proxyAddr),
+				cliui.Code("curl --proxy socks5h://"+proxyAddr+" http://<agent>.<workspace>.<owner>.coder:<port>"),
+			)
+
+			err = proxy.Serve(ctx, ln)
+			if err != nil {
+				return xerrors.Errorf("serve proxy: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "address",
+			Env:         "CODER_PROXY_ADDRESS",
+			Description: "The address to serve the SOCKS5 and HTTP proxies on.",
+			Default:     "127.0.0.1:1080",
+			Value:       serpent.StringOf(&address),
+		},
+		{
+			Flag: "direct",
+			Env:  "CODER_PROXY_DIRECT",
+			Description: "Connect to hosts that are not workspaces directly instead of refusing them, " +
+				"so the proxy can be used for all traffic.",
+			Value: serpent.BoolOf(&direct),
+		},
+	}
+	return cmd
+}
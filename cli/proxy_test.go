@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	// The agent runs in the test process, so a local listener is a port in
+	// the workspace.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = io.WriteString(conn, "hello")
+			_ = conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	inv, root := clitest.New(t, "proxy", "--address", "127.0.0.1:0")
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t).Attach(inv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	cmdDone := tGo(t, func() {
+		err := inv.WithContext(ctx).Run()
+		assert.NoError(t, err)
+	})
+
+	out := pty.ExpectRegexMatch(`127\.0\.0\.1:\d+ with`)
+	proxyAddr := regexp.MustCompile(`127\.0\.0\.1:\d+`).FindString(out)
+	dialer, err := proxy.SOCKS5("tcp", proxyAddr, nil, proxy.Direct)
+	require.NoError(t, err)
+
+	// The names of workspaces arrive after the proxy starts.
+	host := net.JoinHostPort("dev.myworkspace.myuser.coder", strconv.Itoa(port))
+	require.Eventually(t, func() bool {
+		conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", host)
+		if err != nil {
+			t.Logf("dial through proxy: %v", err)
+			return false
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(testutil.WaitShort))
+		data, err := io.ReadAll(conn)
+		return err == nil && string(data) == "hello"
+	}, testutil.WaitLong, testutil.IntervalSlow)
+
+	cancel()
+	<-cmdDone
+}
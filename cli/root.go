@@ -96,8 +96,11 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.notifications(),
 		r.organizations(),
 		r.portForward(),
+		r.proxy(),
 		r.publickey(),
 		r.resetPassword(),
+		r.secrets(),
+		r.sessions(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
@@ -107,8 +110,10 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		// Workspace Commands
 		r.autoupdate(),
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.execCmd(),
 		r.favorite(),
 		r.list(),
 		r.open(),
@@ -116,12 +121,14 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.rename(),
 		r.restart(),
 		r.schedules(),
+		r.services(),
 		r.show(),
 		r.speedtest(),
 		r.ssh(),
 		r.start(),
 		r.stat(),
 		r.stop(),
+		r.top(),
 		r.unfavorite(),
 		r.update(),
 		r.whoami(),
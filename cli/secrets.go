@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) secrets() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "secrets",
+		Short: "Manage secrets delivered to your workspaces",
+		Long: "Secrets are encrypted values, such as API keys, that are exposed to your workspaces as environment variables or files.\n" + FormatExamples(
+			Example{
+				Description: "Expose an API key as an environment variable in your workspaces",
+				Command:     "coder secrets create openai --env OPENAI_API_KEY",
+			},
+			Example{
+				Description: "Write a secret read from stdin to a file in your workspaces",
+				Command:     "cat key.pem | coder secrets create deploy-key --file ~/.ssh/deploy_key",
+			},
+			Example{
+				Description: "List your secrets",
+				Command:     "coder secrets ls",
+			},
+		),
+		Aliases: []string{"secret"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.createSecret(),
+			r.listSecrets(),
+			r.updateSecret(),
+			r.removeSecret(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) createSecret() *serpent.Command {
+	var (
+		description string
+		value       string
+		envName     string
+		filePath    string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <name>",
+		Short: "Create a secret",
+		Long:  "The value of the secret is read from stdin if --value is not specified.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if value == "" {
+				var err error
+				value, err = readSecretValue(inv)
+				if err != nil {
+					return err
+				}
+			}
+
+			secret, err := client.CreateUserSecret(inv.Context(), codersdk.Me, codersdk.CreateUserSecretRequest{
+				Name:        inv.Args[0],
+				Description: description,
+				Value:       value,
+				EnvName:     envName,
+				FilePath:    filePath,
+			})
+			if err != nil {
+				return xerrors.Errorf("create secret: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been created.\n", cliui.Keyword(secret.Name))
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "description",
+			Description: "Specify a description of the secret.",
+			Value:       serpent.StringOf(&description),
+		},
+		{
+			Flag:        "value",
+			Description: "Specify the value of the secret. Prefer stdin, as flags may be recorded in your shell history.",
+			Value:       serpent.StringOf(&value),
+		},
+		{
+			Flag:        "env",
+			Description: "Expose the secret as this environment variable in your workspaces.",
+			Value:       serpent.StringOf(&envName),
+		},
+		{
+			Flag:        "file",
+			Description: `Write the secret to this file in your workspaces. Paths starting with "~/" are relative to the home directory.`,
+			Value:       serpent.StringOf(&filePath),
+		},
+	}
+	return cmd
+}
+
+// secretListRow is the type provided to the OutputFormatter.
+type secretListRow struct {
+	// For JSON format:
+	codersdk.UserSecret `table:"-"`
+
+	// For table format:
+	Name        string    `json:"-" table:"name,default_sort"`
+	Description string    `json:"-" table:"description"`
+	EnvName     string    `json:"-" table:"env"`
+	FilePath    string    `json:"-" table:"file"`
+	UpdatedAt   time.Time `json:"-" table:"updated at"`
+}
+
+func (r *RootCmd) listSecrets() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]secretListRow{}, []string{"name", "description", "env", "file", "updated at"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List secrets",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			secrets, err := client.UserSecrets(inv.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("list secrets: %w", err)
+			}
+
+			if len(secrets) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No secrets found.\n",
+				)
+			}
+
+			rows := make([]secretListRow, len(secrets))
+			for i, secret := range secrets {
+				rows[i] = secretListRow{
+					UserSecret:  secret,
+					Name:        secret.Name,
+					Description: secret.Description,
+					EnvName:     secret.EnvName,
+					FilePath:    secret.FilePath,
+					UpdatedAt:   secret.UpdatedAt,
+				}
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) updateSecret() *serpent.Command {
+	var (
+		description string
+		value       string
+		readValue   bool
+		envName     string
+		filePath    string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "update <name>",
+		Short: "Update a secret",
+		Long:  "Only the specified options are changed. Pass an empty --env or --file to stop delivering the secret that way.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			var req codersdk.UpdateUserSecretRequest
+			if inv.ParsedFlags().Changed("description") {
+				req.Description = &description
+			}
+			if inv.ParsedFlags().Changed("env") {
+				req.EnvName = &envName
+			}
+			if inv.ParsedFlags().Changed("file") {
+				req.FilePath = &filePath
+			}
+			switch {
+			case inv.ParsedFlags().Changed("value") && readValue:
+				return xerrors.New("--value and --stdin cannot be used together")
+			case inv.ParsedFlags().Changed("value"):
+				req.Value = &value
+			case readValue:
+				v, err := readSecretValue(inv)
+				if err != nil {
+					return err
+				}
+				req.Value = &v
+			}
+
+			secret, err := client.UpdateUserSecret(inv.Context(), codersdk.Me, inv.Args[0], req)
+			if err != nil {
+				return xerrors.Errorf("update secret: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been updated.\n", cliui.Keyword(secret.Name))
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "description",
+			Description: "Specify a description of the secret.",
+			Value:       serpent.StringOf(&description),
+		},
+		{
+			Flag:        "value",
+			Description: "Specify the value of the secret. Prefer --stdin, as flags may be recorded in your shell history.",
+			Value:       serpent.StringOf(&value),
+		},
+		{
+			Flag:        "stdin",
+			Description: "Read the value of the secret from stdin.",
+			Value:       serpent.BoolOf(&readValue),
+		},
+		{
+			Flag:        "env",
+			Description: "Expose the secret as this environment variable in your workspaces.",
+			Value:       serpent.StringOf(&envName),
+		},
+		{
+			Flag:        "file",
+			Description: `Write the secret to this file in your workspaces. Paths starting with "~/" are relative to the home directory.`,
+			Value:       serpent.StringOf(&filePath),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) removeSecret() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"delete"},
+		Short:   "Delete a secret",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			err := client.DeleteUserSecret(inv.Context(), codersdk.Me, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete secret: %w", err)
+			}
+
+			cliui.Infof(
+				inv.Stdout,
+				"Secret has been deleted.",
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// readSecretValue prompts for the value of a secret in a terminal, or reads it
+// from stdin otherwise.
+func readSecretValue(inv *serpent.Invocation) (string, error) {
+	if isTTYIn(inv) {
+		value, err := cliui.Prompt(inv, cliui.PromptOptions{
+			Text:   "Value:",
+			Secret: true,
+		})
+		if err != nil {
+			return "", xerrors.Errorf("prompt for value: %w", err)
+		}
+		return value, nil
+	}
+	data, err := io.ReadAll(inv.Stdin)
+	if err != nil {
+		return "", xerrors.Errorf("read value from stdin: %w", err)
+	}
+	// Most tools terminate their output with a newline, which is almost never
+	// part of the secret.
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", xerrors.New("the value of the secret must not be empty")
+	}
+	return value, nil
+}
@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "secrets", "ls")
+	clitest.SetupConfig(t, member, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "No secrets found")
+
+	// The value is read from stdin when --value is not specified.
+	inv, root = clitest.New(t, "secrets", "create", "openai", "--env", "OPENAI_API_KEY", "--description", "OpenAI")
+	clitest.SetupConfig(t, member, root)
+	inv.Stdin = strings.NewReader("sk-123\n")
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "openai")
+
+	inv, root = clitest.New(t, "secrets", "ls")
+	clitest.SetupConfig(t, member, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "openai")
+	require.Contains(t, buf.String(), "OPENAI_API_KEY")
+	require.NotContains(t, buf.String(), "sk-123")
+
+	inv, root = clitest.New(t, "secrets", "update", "openai", "--env", "", "--file", "~/.openai")
+	clitest.SetupConfig(t, member, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "secrets", "ls", "--output", "json")
+	clitest.SetupConfig(t, member, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var secrets []codersdk.UserSecret
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &secrets))
+	require.Len(t, secrets, 1)
+	require.Equal(t, "OpenAI", secrets[0].Description)
+	require.Empty(t, secrets[0].EnvName)
+	require.Equal(t, "~/.openai", secrets[0].FilePath)
+
+	inv, root = clitest.New(t, "secrets", "rm", "openai")
+	clitest.SetupConfig(t, member, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	secrets, err = member.UserSecrets(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Empty(t, secrets)
+}
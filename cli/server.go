@@ -4,9 +4,11 @@ package cli
 
 import (
 	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
@@ -34,12 +36,15 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/coreos/go-systemd/daemon"
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dsig "github.com/russellhaering/goxmldsig"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -88,6 +93,7 @@ import (
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/jobreaper"
+	"github.com/coder/coder/v2/coderd/ldapauth"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
@@ -209,6 +215,118 @@ func createOIDCConfig(ctx context.Context, logger slog.Logger, vals *codersdk.De
 	}, nil
 }
 
+func createLDAPConfig(vals *codersdk.DeploymentValues) (*coderd.LDAPConfig, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		//nolint:gosec // Users are warned in the option description.
+		InsecureSkipVerify: vals.LDAP.InsecureSkipVerify.Value(),
+	}
+	if vals.LDAP.CAFile != "" {
+		caPEM, err := os.ReadFile(vals.LDAP.CAFile.Value())
+		if err != nil {
+			return nil, xerrors.Errorf("read ldap ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, xerrors.Errorf("no certificates found in ldap ca file %q", vals.LDAP.CAFile.Value())
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	cfg := &coderd.LDAPConfig{
+		Config: ldapauth.Config{
+			URL:                vals.LDAP.URL.Value(),
+			StartTLS:           vals.LDAP.StartTLS.Value(),
+			TLSConfig:          tlsConfig,
+			BindDN:             vals.LDAP.BindDN.Value(),
+			BindPassword:       vals.LDAP.BindPassword.Value(),
+			UserSearchBaseDN:   vals.LDAP.UserSearchBaseDN.Value(),
+			UserSearchFilter:   vals.LDAP.UserSearchFilter.Value(),
+			IDAttribute:        vals.LDAP.IDAttribute.Value(),
+			UsernameAttribute:  vals.LDAP.UsernameAttribute.Value(),
+			EmailAttribute:     vals.LDAP.EmailAttribute.Value(),
+			NameAttribute:      vals.LDAP.NameAttribute.Value(),
+			GroupSearchBaseDN:  vals.LDAP.GroupSearchBaseDN.Value(),
+			GroupSearchFilter:  vals.LDAP.GroupSearchFilter.Value(),
+			GroupNameAttribute: vals.LDAP.GroupNameAttribute.Value(),
+		},
+		AllowSignups: vals.LDAP.AllowSignups.Value(),
+		EmailDomain:  vals.LDAP.EmailDomain.Value(),
+		SignInText:   vals.LDAP.SignInText.Value(),
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func createSAMLConfig(ctx context.Context, httpClient *http.Client, vals *codersdk.DeploymentValues) (*coderd.SAMLConfig, error) {
+	var (
+		idpMetadata *saml.EntityDescriptor
+		err         error
+	)
+	if vals.SAML.IDPMetadataURL.String() != "" {
+		idpMetadata, err = samlsp.FetchMetadata(ctx, httpClient, *vals.SAML.IDPMetadataURL.Value())
+		if err != nil {
+			return nil, xerrors.Errorf("fetch saml idp metadata: %w", err)
+		}
+	} else {
+		data, err := os.ReadFile(vals.SAML.IDPMetadataFile.Value())
+		if err != nil {
+			return nil, xerrors.Errorf("read saml idp metadata file: %w", err)
+		}
+		idpMetadata, err = samlsp.ParseMetadata(data)
+		if err != nil {
+			return nil, xerrors.Errorf("parse saml idp metadata file: %w", err)
+		}
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:          vals.SAML.EntityID.Value(),
+		HTTPClient:        httpClient,
+		IDPMetadata:       idpMetadata,
+		AllowIDPInitiated: vals.SAML.AllowIDPInitiated.Value(),
+	}
+	if vals.SAML.CertFile != "" || vals.SAML.KeyFile != "" {
+		keyPair, err := tls.LoadX509KeyPair(vals.SAML.CertFile.Value(), vals.SAML.KeyFile.Value())
+		if err != nil {
+			return nil, xerrors.Errorf("load saml certificate: %w", err)
+		}
+		cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+		if err != nil {
+			return nil, xerrors.Errorf("parse saml certificate: %w", err)
+		}
+		signer, ok := keyPair.PrivateKey.(crypto.Signer)
+		if !ok {
+			return nil, xerrors.Errorf("saml key of type %T cannot be used for signing", keyPair.PrivateKey)
+		}
+		sp.Key = signer
+		sp.Certificate = cert
+	}
+	if vals.SAML.SignRequests {
+		switch sp.Key.(type) {
+		case *rsa.PrivateKey:
+			sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+		case *ecdsa.PrivateKey:
+			sp.SignatureMethod = dsig.ECDSASHA256SignatureMethod
+		default:
+			return nil, xerrors.New("signing saml requests requires an RSA or ECDSA certificate and key")
+		}
+	}
+
+	return &coderd.SAMLConfig{
+		ServiceProvider:   sp,
+		AllowSignups:      vals.SAML.AllowSignups.Value(),
+		EmailDomain:       vals.SAML.EmailDomain.Value(),
+		EmailAttribute:    vals.SAML.EmailAttribute.Value(),
+		UsernameAttribute: vals.SAML.UsernameAttribute.Value(),
+		NameAttribute:     vals.SAML.NameAttribute.Value(),
+		SignInText:        vals.SAML.SignInText.Value(),
+		IconURL:           vals.SAML.IconURL.String(),
+	}, nil
+}
+
 func afterCtx(ctx context.Context, fn func()) {
 	go func() {
 		<-ctx.Done()
@@ -351,6 +469,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			filesRateLimit := 12
 			if vals.RateLimit.DisableAll {
 				vals.RateLimit.API = -1
+				vals.OAuth2.Provider.DynamicRegistrationRateLimit = -1
 				loginRateLimit = -1
 				filesRateLimit = -1
 			}
@@ -689,6 +808,17 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
 
+			if vals.DERP.Server.Enable && vals.DERP.Server.QUICAddress != "" {
+				if httpServers.TLSConfig == nil {
+					return xerrors.New("DERP over QUIC requires TLS to be enabled")
+				}
+				options.DERPQUICListener, err = tailnet.ListenDERPQUIC(vals.DERP.Server.QUICAddress.String(), httpServers.TLSConfig)
+				if err != nil {
+					return xerrors.Errorf("listen for DERP over QUIC: %w", err)
+				}
+				cliui.Infof(inv.Stdout, "Serving DERP over QUIC on udp://%s", options.DERPQUICListener.Addr())
+			}
+
 			if vals.StrictTransportSecurity > 0 {
 				options.StrictTransportSecurityCfg, err = httpmw.HSTSConfigOptions(
 					int(vals.StrictTransportSecurity.Value()), vals.StrictTransportSecurityOptions,
@@ -740,6 +870,25 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			if vals.LDAP.URL != "" {
+				if vals.LDAP.InsecureSkipVerify {
+					logger.Warn(ctx, "coder will not verify the TLS certificate of the LDAP server")
+				}
+				lc, err := createLDAPConfig(vals)
+				if err != nil {
+					return xerrors.Errorf("create ldap config: %w", err)
+				}
+				options.LDAPConfig = lc
+			}
+
+			if vals.SAML.IDPMetadataURL.String() != "" || vals.SAML.IDPMetadataFile != "" {
+				sc, err := createSAMLConfig(ctx, httpClient, vals)
+				if err != nil {
+					return xerrors.Errorf("create saml config: %w", err)
+				}
+				options.SAMLConfig = sc
+			}
+
 			// We'll read from this channel in the select below that tracks shutdown.  If it remains
 			// nil, that case of the select will just never fire, but it's important not to have a
 			// "bare" read on this channel.
@@ -1053,7 +1202,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, quartz.NewReal())
+			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, options.DeploymentValues, quartz.NewReal())
 			defer purger.Close()
 
 			// Updates workspace usage
@@ -586,6 +586,46 @@ func TestServer(t *testing.T) {
 		err := root.WithContext(ctx).Run()
 		require.Error(t, err)
 	})
+	t.Run("DERPQUICWithoutTLS", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		root, _ := clitest.New(t,
+			"server",
+			"--in-memory",
+			"--http-address", ":0",
+			"--access-url", "http://example.com",
+			"--derp-server-quic-address", "127.0.0.1:0",
+			"--cache-dir", t.TempDir(),
+		)
+		err := root.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "DERP over QUIC requires TLS")
+	})
+	t.Run("DERPQUIC", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		certPath, keyPath := generateTLSCertificate(t)
+		root, cfg := clitest.New(t,
+			"server",
+			"--in-memory",
+			"--http-address", "",
+			"--access-url", "https://example.com",
+			"--tls-enable",
+			"--tls-address", ":0",
+			"--tls-cert-file", certPath,
+			"--tls-key-file", keyPath,
+			"--derp-server-quic-address", "127.0.0.1:0",
+			"--cache-dir", t.TempDir(),
+		)
+		pty := ptytest.New(t).Attach(root)
+		clitest.Start(t, root.WithContext(ctx))
+
+		_ = waitAccessURL(t, cfg)
+		pty.ExpectMatch("Serving DERP over QUIC on udp://127.0.0.1:")
+	})
 	t.Run("TLSInvalid", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) services() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "services",
+		Short: "Manage the services of a workspace",
+		Long: "Services are long-running processes defined by the template that are supervised by the workspace agent.\n" + FormatExamples(
+			Example{
+				Description: "List the services of a workspace and their status",
+				Command:     "coder services ls alice/dev",
+			},
+			Example{
+				Description: "Restart a service of a workspace agent",
+				Command:     "coder services restart alice/dev.main web",
+			},
+		),
+		Aliases: []string{"service"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.listServices(),
+			r.restartService(),
+		},
+	}
+	return cmd
+}
+
+// serviceListRow is the type provided to the OutputFormatter.
+type serviceListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceAgentService `table:"-"`
+
+	// For table format:
+	Name     string `json:"-" table:"name,default_sort"`
+	Status   string `json:"-" table:"status"`
+	Health   string `json:"-" table:"health"`
+	Restarts int32  `json:"-" table:"restarts"`
+	ExitCode string `json:"-" table:"exit code"`
+}
+
+func (r *RootCmd) listServices() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]serviceListRow{}, []string{"name", "status", "health", "restarts", "exit code"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the services of a workspace agent",
+		Long:    "Lists the services of a workspace agent with their status as last reported by the agent.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.WorkspaceAgentServices(ctx, workspaceAgent.ID)
+			if err != nil {
+				return xerrors.Errorf("list services: %w", err)
+			}
+
+			if len(resp.Services) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"The workspace agent has no services.\n",
+				)
+			}
+
+			rows := make([]serviceListRow, len(resp.Services))
+			for i, service := range resp.Services {
+				exitCode := ""
+				if service.ExitCode != nil {
+					exitCode = strconv.Itoa(int(*service.ExitCode))
+				}
+				rows[i] = serviceListRow{
+					WorkspaceAgentService: service,
+					Name:                  service.Name,
+					Status:                string(service.Status),
+					Health:                string(service.Health),
+					Restarts:              service.Restarts,
+					ExitCode:              exitCode,
+				}
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) restartService() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "restart <workspace> <service>",
+		Short: "Restart a service of a workspace agent",
+		Long:  "Restarts a running service, or starts a service that has exited. The service is restarted in the background.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			name := inv.Args[1]
+			_, err = client.WorkspaceAgentRestartService(ctx, workspaceAgent.ID, name)
+			if err != nil {
+				return xerrors.Errorf("restart service %q: %w", name, err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Restarting service %s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestServices(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The service command is POSIX shell specific")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Services = []*proto.Service{{
+			Name:          "sleeper",
+			Command:       "sleep 300",
+			RestartPolicy: proto.ServiceRestartPolicy_RESTART_ALWAYS,
+		}}
+		return agents
+	})
+	_ = agenttest.New(t, client.URL, agentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		resp, err := client.WorkspaceAgentServices(ctx, agentID)
+		if !assert.NoError(t, err) || !assert.Len(t, resp.Services, 1) {
+			return false
+		}
+		return resp.Services[0].Status == codersdk.WorkspaceAgentServiceStatusRunning
+	}, testutil.IntervalMedium)
+
+	inv, root := clitest.New(t, "services", "ls", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "sleeper")
+	require.Contains(t, buf.String(), "running")
+
+	inv, root = clitest.New(t, "services", "restart", workspace.Name, "sleeper")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "Restarting service")
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		resp, err := client.WorkspaceAgentServices(ctx, agentID)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return resp.Services[0].Restarts == 1
+	}, testutil.IntervalMedium)
+
+	inv, root = clitest.New(t, "services", "restart", workspace.Name, "unknown")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) sessions() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "sessions",
+		Short: "View recordings of interactive workspace sessions",
+		Long: "Sessions are recorded for templates with session recording enabled. Recordings are stored in the asciicast v2 format.\n" + FormatExamples(
+			Example{
+				Description: "List the recorded sessions of a workspace",
+				Command:     "coder sessions ls alice/dev",
+			},
+			Example{
+				Description: "Replay a recorded session at double speed",
+				Command:     "coder sessions play <id> --speed 2",
+			},
+			Example{
+				Description: "Save a recording for use with asciinema",
+				Command:     "coder sessions play <id> --raw > session.cast",
+			},
+		),
+		Aliases: []string{"session"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.listSessions(),
+			r.playSession(),
+		},
+	}
+	return cmd
+}
+
+// sessionListRow is the type provided to the OutputFormatter.
+type sessionListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceSessionRecording `table:"-"`
+
+	// For table format:
+	ID        string    `json:"-" table:"id"`
+	Workspace string    `json:"-" table:"workspace"`
+	Type      string    `json:"-" table:"type"`
+	StartedAt time.Time `json:"-" table:"started at,default_sort"`
+	Duration  string    `json:"-" table:"duration"`
+	Size      string    `json:"-" table:"size"`
+}
+
+func (r *RootCmd) listSessions() *serpent.Command {
+	var owner string
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]sessionListRow{}, []string{"id", "workspace", "type", "started at", "duration", "size"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list [workspace]",
+		Aliases: []string{"ls"},
+		Short:   "List recorded sessions",
+		Long:    "Lists the recorded sessions you are allowed to view, most recent first.",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			var req codersdk.WorkspaceSessionRecordingsRequest
+			if len(inv.Args) > 0 {
+				workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+				if err != nil {
+					return xerrors.Errorf("get workspace: %w", err)
+				}
+				req.WorkspaceID = workspace.ID
+			}
+			if owner != "" {
+				user, err := client.User(ctx, owner)
+				if err != nil {
+					return xerrors.Errorf("get owner: %w", err)
+				}
+				req.OwnerID = user.ID
+			}
+
+			recordings, err := client.WorkspaceSessionRecordings(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("list sessions: %w", err)
+			}
+
+			if len(recordings) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No recorded sessions found.\n",
+				)
+			}
+
+			rows := make([]sessionListRow, len(recordings))
+			for i, recording := range recordings {
+				duration := "ongoing"
+				if recording.EndedAt != nil {
+					duration = recording.EndedAt.Sub(recording.StartedAt).Round(time.Second).String()
+				}
+				rows[i] = sessionListRow{
+					WorkspaceSessionRecording: recording,
+					ID:                        recording.ID.String(),
+					Workspace:                 recording.OwnerUsername + "/" + recording.WorkspaceName,
+					Type:                      string(recording.Type),
+					StartedAt:                 recording.StartedAt,
+					Duration:                  duration,
+					Size:                      humanize.IBytes(uint64(recording.Size)), // #nosec G115 - Sizes are never negative
+				}
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "owner",
+			Description: "Only list sessions in workspaces of this user.",
+			Value:       serpent.StringOf(&owner),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) playSession() *serpent.Command {
+	var (
+		speed   float64
+		maxIdle time.Duration
+		raw     bool
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "play <id>",
+		Short: "Replay a recorded session in your terminal",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			id, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("parse session id: %w", err)
+			}
+			if speed <= 0 {
+				return xerrors.New("--speed must be greater than zero")
+			}
+
+			cast, err := client.WorkspaceSessionRecordingCast(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("get session recording: %w", err)
+			}
+			defer cast.Close()
+
+			if raw {
+				_, err = io.Copy(inv.Stdout, cast)
+				return err
+			}
+			return playCast(ctx, inv.Stdout, cast, speed, maxIdle)
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "speed",
+			Description: "Playback speed multiplier.",
+			Default:     "1",
+			Value:       serpent.Float64Of(&speed),
+		},
+		{
+			Flag:        "max-idle",
+			Description: "Limit pauses between output to this duration. Zero disables the limit.",
+			Default:     "2s",
+			Value:       serpent.DurationOf(&maxIdle),
+		},
+		{
+			Flag:        "raw",
+			Description: "Write the recording in the asciicast v2 format instead of replaying it.",
+			Value:       serpent.BoolOf(&raw),
+		},
+	}
+	return cmd
+}
+
+// playCast writes the output events of an asciicast v2 recording to w,
+// honoring the time between events.
+func playCast(ctx context.Context, w io.Writer, cast io.Reader, speed float64, maxIdle time.Duration) error {
+	dec := json.NewDecoder(cast)
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := dec.Decode(&header); err != nil {
+		return xerrors.Errorf("decode recording header: %w", err)
+	}
+	if header.Version != 2 {
+		return xerrors.Errorf("unsupported recording version %d", header.Version)
+	}
+
+	var last float64
+	for {
+		var event []any
+		err := dec.Decode(&event)
+		if xerrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("decode recording event: %w", err)
+		}
+		if len(event) != 3 {
+			continue
+		}
+		elapsed, ok1 := event[0].(float64)
+		code, ok2 := event[1].(string)
+		data, ok3 := event[2].(string)
+		if !ok1 || !ok2 || !ok3 || code != "o" {
+			// Only output is replayed, the size of the terminal of the
+			// viewer cannot be changed.
+			continue
+		}
+
+		delay := time.Duration((elapsed - last) / speed * float64(time.Second))
+		if maxIdle > 0 && delay > maxIdle {
+			delay = maxIdle
+		}
+		last = elapsed
+		if delay > 0 {
+			t := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).Do()
+	recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		OrganizationID: owner.OrganizationID,
+		WorkspaceID:    r.Workspace.ID,
+		OwnerID:        member.ID,
+	})
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1735787045}` + "\n" +
+		`[0.5,"o","hello "]` + "\n" +
+		`[0.6,"r","100x30"]` + "\n" +
+		`[0.7,"o","world\r\n"]` + "\n"
+	err := db.InsertWorkspaceSessionRecordingChunk(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.InsertWorkspaceSessionRecordingChunkParams{
+		RecordingID: recording.ID,
+		Sequence:    0,
+		Data:        []byte(cast),
+		CreatedAt:   dbtime.Now(),
+	})
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "ls", "--owner", member.Username)
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), recording.ID.String())
+		require.Contains(t, buf.String(), member.Username+"/"+r.Workspace.Name)
+		require.Contains(t, buf.String(), "ongoing")
+	})
+
+	t.Run("Play", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "play", recording.ID.String(), "--speed", "100")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, "hello world\r\n", buf.String())
+	})
+
+	t.Run("Raw", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "play", recording.ID.String(), "--raw")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, cast, buf.String())
+	})
+}
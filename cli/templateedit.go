@@ -35,6 +35,7 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		sessionRecording               bool
 		orgContext                     = NewOrganizationContext()
 	)
 	client := new(codersdk.Client)
@@ -167,6 +168,11 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				disableEveryoneGroup = disableEveryone
 			}
 
+			var sessionRecordingEnabled *bool
+			if userSetOption(inv, "session-recording") {
+				sessionRecordingEnabled = &sessionRecording
+			}
+
 			req := codersdk.UpdateTemplateMeta{
 				Name:               name,
 				DisplayName:        displayName,
@@ -190,6 +196,7 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				RequireActiveVersion:           requireActiveVersion,
 				DeprecationMessage:             deprecated,
 				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				SessionRecordingEnabled:        sessionRecordingEnabled,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -302,6 +309,13 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Value:   serpent.BoolOf(&disableEveryone),
 			Default: "false",
 		},
+		{
+			Flag: "session-recording",
+			Description: "Record the output of interactive terminal sessions in workspaces of this template. " +
+				"Recordings can be replayed with \"coder sessions play\".",
+			Value:   serpent.BoolOf(&sessionRecording),
+			Default: "false",
+		},
 		cliui.SkipPromptOption(),
 	}
 	orgContext.AttachOptions(cmd)
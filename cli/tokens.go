@@ -23,6 +23,10 @@ func (r *RootCmd) tokens() *serpent.Command {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			Example{
+				Description: "Create a token that can only start and stop the workspaces of a template",
+				Command:     "coder tokens create --scope workspace:read,update,start,stop --scope template:read --allow template:<template-id>",
+			},
 			Example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -50,6 +54,8 @@ func (r *RootCmd) createToken() *serpent.Command {
 		tokenLifetime string
 		name          string
 		user          string
+		scopes        []string
+		allowList     []string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -86,10 +92,31 @@ func (r *RootCmd) createToken() *serpent.Command {
 				}
 			}
 
-			res, err := client.CreateToken(inv.Context(), userID, codersdk.CreateTokenRequest{
+			req := codersdk.CreateTokenRequest{
 				Lifetime:  parsedLifetime,
 				TokenName: name,
-			})
+			}
+			switch {
+			case len(scopes) == 1 && (scopes[0] == string(codersdk.APIKeyScopeAll) || scopes[0] == string(codersdk.APIKeyScopeApplicationConnect)):
+				if len(allowList) > 0 {
+					return xerrors.Errorf("--allow cannot be used with the %q scope", scopes[0])
+				}
+				req.Scope = codersdk.APIKeyScope(scopes[0])
+			case len(scopes) > 0:
+				req.Scope = codersdk.APIKeyScopeCustom
+				req.ScopePermissions, err = codersdk.ParseAPIKeyScopePermissions(scopes)
+				if err != nil {
+					return xerrors.Errorf("parse scope: %w", err)
+				}
+				req.ScopeAllowList, err = codersdk.ParseAPIKeyScopeAllowList(allowList)
+				if err != nil {
+					return xerrors.Errorf("parse allow list: %w", err)
+				}
+			case len(allowList) > 0:
+				return xerrors.New("--allow requires --scope to list permissions")
+			}
+
+			res, err := client.CreateToken(inv.Context(), userID, req)
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
 			}
@@ -121,6 +148,18 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description:   "Specify the user to create the token for (Only works if logged in user is admin).",
 			Value:         serpent.StringOf(&user),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Limit the token to a builtin scope (all, application_connect), or to permissions of the form <resource>:<action>[,<action>...], e.g. workspace:start,stop. Can be specified multiple times.",
+			Value:       serpent.StringArrayOf(&scopes),
+		},
+		{
+			Flag:        "allow",
+			Env:         "CODER_TOKEN_ALLOW",
+			Description: "Limit the permissions of --scope to the given objects, of the form <resource>:<id>. Allowing a template also allows its workspaces, allowing an organization limits the permissions to it. Can be specified multiple times.",
+			Value:       serpent.StringArrayOf(&allowList),
+		},
 	}
 
 	return cmd
@@ -134,6 +173,7 @@ type tokenListRow struct {
 	// For table format:
 	ID        string    `json:"-" table:"id,default_sort"`
 	TokenName string    `json:"token_name" table:"name"`
+	Scope     string    `json:"-" table:"scope"`
 	LastUsed  time.Time `json:"-" table:"last used"`
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
@@ -145,6 +185,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
+		Scope:     tokenScopeString(token.APIKey),
 		LastUsed:  token.LastUsed,
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
@@ -152,9 +193,21 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 	}
 }
 
+// tokenScopeString describes the scope of a token for the table format.
+func tokenScopeString(key codersdk.APIKey) string {
+	if key.Scope != codersdk.APIKeyScopeCustom {
+		return string(key.Scope)
+	}
+	scope := strings.Join(key.ScopePermissions, " ")
+	if len(key.ScopeAllowList) > 0 {
+		scope += " on " + strings.Join(key.ScopeAllowList, " ")
+	}
+	return scope
+}
+
 func (r *RootCmd) listTokens() *serpent.Command {
 	// we only display the 'owner' column if the --all argument is passed in
-	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
+	defaultCols := []string{"id", "name", "scope", "last used", "expires at", "created at"}
 	if slices.Contains(os.Args, "-a") || slices.Contains(os.Args, "--all") {
 		defaultCols = append(defaultCols, "owner")
 	}
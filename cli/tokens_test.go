@@ -157,3 +157,40 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensScope(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "tokens", "create", "--name", "scoped",
+		"--scope", "workspace:start,stop", "--scope", "template:read",
+		"--allow", "organization:"+owner.OrganizationID.String())
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	token, err := client.APIKeyByName(ctx, codersdk.Me, "scoped")
+	require.NoError(t, err)
+	require.Equal(t, codersdk.APIKeyScopeCustom, token.Scope)
+	require.Equal(t, []string{"workspace:start", "workspace:stop", "template:read"}, token.ScopePermissions)
+	require.Equal(t, []string{"organization:" + owner.OrganizationID.String()}, token.ScopeAllowList)
+
+	inv, root = clitest.New(t, "tokens", "ls")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "SCOPE")
+	require.Contains(t, buf.String(), "workspace:start workspace:stop template:read on organization:")
+
+	inv, root = clitest.New(t, "tokens", "create", "--scope", "workspace:fly")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "invalid action")
+}
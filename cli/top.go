@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+// topSampleInterval is the interval over which the CPU usage of processes
+// is measured.
+const topSampleInterval = time.Second
+
+// processRow is the type provided to the OutputFormatter.
+type processRow struct {
+	// For JSON format:
+	workspacesdk.AgentProcess `table:"-"`
+
+	// For table format:
+	PID     int32  `json:"-" table:"pid,nosort"`
+	User    string `json:"-" table:"user"`
+	CPU     string `json:"-" table:"cpu"`
+	Memory  string `json:"-" table:"memory"`
+	Ports   string `json:"-" table:"ports"`
+	Command string `json:"-" table:"command"`
+}
+
+func (r *RootCmd) top() *serpent.Command {
+	var (
+		sortBy string
+		limit  int64
+		kill   int64
+		signal string
+	)
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]processRow{}, []string{"pid", "user", "cpu", "memory", "ports", "command"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "top <workspace>",
+		Short:       "Show the processes running in a workspace",
+		Long: "Lists the processes of a workspace with their CPU and memory usage and the TCP ports they listen on. " +
+			"CPU usage is measured over one second, one fully used core is 100%.\n\n" + FormatExamples(
+			Example{
+				Description: "Show the processes that use the most memory",
+				Command:     "coder top my-workspace --sort-by memory",
+			},
+			Example{
+				Description: "Stop a process",
+				Command:     "coder top my-workspace --kill 1234",
+			},
+			Example{
+				Description: "Kill a process that does not stop",
+				Command:     "coder top my-workspace --kill 1234 --signal KILL",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			opts := &workspacesdk.DialAgentOptions{}
+			if r.verbose {
+				opts.Logger = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				opts.BlockEndpoints = true
+			}
+			conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+			}
+
+			if kill > 0 {
+				err = conn.SignalProcess(ctx, int32(kill), workspacesdk.AgentProcessSignal(signal)) //nolint:gosec // Validated by the flag.
+				if err != nil {
+					return xerrors.Errorf("signal process %d: %w", kill, err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Sent %s to process %s\n", signal, pretty.Sprint(cliui.DefaultStyles.Keyword, strconv.FormatInt(kill, 10)))
+				return nil
+			}
+
+			// The agent reports the CPU usage since the previous listing, so
+			// the first listing only starts the measurement.
+			if _, err := conn.Processes(ctx); err != nil {
+				return xerrors.Errorf("list processes: %w", err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(topSampleInterval):
+			}
+			resp, err := conn.Processes(ctx)
+			if err != nil {
+				return xerrors.Errorf("list processes: %w", err)
+			}
+
+			processes := resp.Processes
+			slices.SortStableFunc(processes, func(a, b workspacesdk.AgentProcess) int {
+				switch sortBy {
+				case "memory":
+					return cmp.Compare(b.MemoryRSS, a.MemoryRSS)
+				case "pid":
+					return cmp.Compare(a.PID, b.PID)
+				default:
+					return cmp.Compare(b.CPUPercent, a.CPUPercent)
+				}
+			})
+			if limit > 0 && int64(len(processes)) > limit {
+				processes = processes[:limit]
+			}
+
+			rows := make([]processRow, len(processes))
+			for i, p := range processes {
+				command := p.Cmdline
+				if command == "" {
+					command = p.Name
+				}
+				ports := make([]string, len(p.ListeningPorts))
+				for j, port := range p.ListeningPorts {
+					ports[j] = strconv.Itoa(int(port))
+				}
+				rows[i] = processRow{
+					AgentProcess: p,
+					PID:          p.PID,
+					User:         p.User,
+					CPU:          fmt.Sprintf("%.1f%%", p.CPUPercent),
+					Memory:       humanize.IBytes(p.MemoryRSS),
+					Ports:        strings.Join(ports, ","),
+					Command:      command,
+				}
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "sort-by",
+			Description: "The column to sort processes by, CPU and memory usage are sorted in descending order.",
+			Default:     "cpu",
+			Value:       serpent.EnumOf(&sortBy, "cpu", "memory", "pid"),
+		},
+		{
+			Flag:          "limit",
+			FlagShorthand: "n",
+			Description:   "The maximum number of processes to show, 0 shows all processes.",
+			Default:       "20",
+			Value:         serpent.Int64Of(&limit),
+		},
+		{
+			Flag:        "kill",
+			Description: "Send a signal to the process with this PID instead of listing processes.",
+			Value:       serpent.Int64Of(&kill),
+		},
+		{
+			Flag:        "signal",
+			Description: "The signal sent with --kill.",
+			Default:     string(workspacesdk.AgentProcessSignalTERM),
+			Value:       serpent.EnumOf(&signal, slice.ToStrings(workspacesdk.AgentProcessSignals)...),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTop(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The test process uses a POSIX command")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	cmd := exec.Command("sleep", "300")
+	require.NoError(t, cmd.Start())
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+	})
+	pid := strconv.Itoa(cmd.Process.Pid)
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, append([]string{"top"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		return stdout.String(), err
+	}
+
+	// The test process runs the agent, so its children are listed.
+	out, err := run(t, workspace.Name, "--limit", "0", "--output", "json")
+	require.NoError(t, err)
+	var processes []workspacesdk.AgentProcess
+	require.NoError(t, json.Unmarshal([]byte(out), &processes))
+	var found bool
+	for _, p := range processes {
+		if strconv.Itoa(int(p.PID)) == pid {
+			found = true
+			require.Equal(t, "sleep 300", p.Cmdline)
+		}
+	}
+	require.True(t, found, "the sleep process is listed")
+
+	out, err = run(t, workspace.Name, "--limit", "0", "--sort-by", "pid")
+	require.NoError(t, err)
+	require.Contains(t, out, "sleep 300")
+
+	out, err = run(t, workspace.Name, "--kill", pid, "--signal", "KILL")
+	require.NoError(t, err)
+	require.Contains(t, out, "Sent KILL to process "+pid)
+	ctx := testutil.Context(t, testutil.WaitShort)
+	require.Error(t, testutil.TryReceive(ctx, t, exited))
+}
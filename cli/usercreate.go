@@ -124,6 +124,10 @@ func (r *RootCmd) userCreate() *serpent.Command {
 				authenticationMethod = `Login is authenticated through GitHub.`
 			case codersdk.LoginTypeOIDC:
 				authenticationMethod = `Login is authenticated through the configured OIDC provider.`
+			case codersdk.LoginTypeLDAP:
+				authenticationMethod = `Login is authenticated through the configured LDAP directory.`
+			case codersdk.LoginTypeSAML:
+				authenticationMethod = `Login is authenticated through the configured SAML identity provider.`
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, `A new user has been created!
@@ -187,7 +191,7 @@ Create a workspace  `+pretty.Sprint(cliui.DefaultStyles.Code, "coder create")+`!
 			Description: fmt.Sprintf("Optionally specify the login type for the user. Valid values are: %s. "+
 				"Using 'none' prevents the user from authenticating and requires an API key/token to be generated by an admin.",
 				strings.Join([]string{
-					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC),
+					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC), string(codersdk.LoginTypeLDAP), string(codersdk.LoginTypeSAML),
 				}, ", ",
 				)),
 			Value: serpent.StringOf(&loginType),
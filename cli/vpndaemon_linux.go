@@ -0,0 +1,84 @@
+//go:build linux
+
+package cli
+
+import (
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/vpn"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) vpnDaemonRun() *serpent.Command {
+	var (
+		rpcReadFd  int64
+		rpcWriteFd int64
+	)
+
+	cmd := &serpent.Command{
+		Use:   "run",
+		Short: "Run the VPN daemon on Linux.",
+		Long: "The daemon speaks the same RPC protocol as on Windows over a pair of " +
+			"inherited pipes. It needs CAP_NET_ADMIN to create the TUN device and " +
+			"configure routes and DNS, unless the manager passes an open TUN device " +
+			"in the start request.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "rpc-read-fd",
+				Env:         "CODER_VPN_DAEMON_RPC_READ_FD",
+				Description: "The file descriptor of the pipe to read from the RPC connection.",
+				Value:       serpent.Int64Of(&rpcReadFd),
+				Required:    true,
+			},
+			{
+				Flag:        "rpc-write-fd",
+				Env:         "CODER_VPN_DAEMON_RPC_WRITE_FD",
+				Description: "The file descriptor of the pipe to write to the RPC connection.",
+				Value:       serpent.Int64Of(&rpcWriteFd),
+				Required:    true,
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			sinks := []slog.Sink{
+				sloghuman.Sink(inv.Stderr),
+			}
+			logger := inv.Logger.AppendSinks(sinks...).Leveled(slog.LevelDebug)
+
+			if rpcReadFd < 0 || rpcWriteFd < 0 {
+				return xerrors.Errorf("rpc-read-fd (%v) and rpc-write-fd (%v) must be positive", rpcReadFd, rpcWriteFd)
+			}
+			if rpcReadFd == rpcWriteFd {
+				return xerrors.Errorf("rpc-read-fd (%v) and rpc-write-fd (%v) must be different", rpcReadFd, rpcWriteFd)
+			}
+
+			logger.Info(ctx, "opening bidirectional RPC pipe", slog.F("rpc_read_fd", rpcReadFd), slog.F("rpc_write_fd", rpcWriteFd))
+			pipe, err := vpn.NewBidirectionalPipe(uintptr(rpcReadFd), uintptr(rpcWriteFd))
+			if err != nil {
+				return xerrors.Errorf("create bidirectional RPC pipe: %w", err)
+			}
+			defer pipe.Close()
+
+			logger.Info(ctx, "starting tunnel")
+			tunnel, err := vpn.NewTunnel(ctx, logger, pipe, vpn.NewClient(),
+				vpn.UseOSNetworkingStack(),
+				vpn.UseAsLogger(),
+				vpn.UseCustomLogSinks(sinks...),
+			)
+			if err != nil {
+				return xerrors.Errorf("create new tunnel for client: %w", err)
+			}
+			defer tunnel.Close()
+
+			<-ctx.Done()
+			return nil
+		},
+	}
+
+	return cmd
+}
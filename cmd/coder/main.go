@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/reconnectingpty"
 	_ "github.com/coder/coder/v2/buildinfo/resources"
 	"github.com/coder/coder/v2/cli"
 )
@@ -18,6 +19,14 @@ func main() {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if len(os.Args) > 1 && os.Args[1] == reconnectingpty.HolderCommand {
+		err := reconnectingpty.HolderCLI()
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	// This preserves backwards compatibility with an init function that is causing grief for
 	// web terminals using agent-exec + screen. See https://github.com/coder/coder/pull/15817
 	tea.InitTerminal()
@@ -46,12 +46,14 @@ type API struct {
 	*StatsAPI
 	*LifecycleAPI
 	*AppsAPI
+	*ServicesAPI
 	*MetadataAPI
 	*ResourcesMonitoringAPI
 	*LogsAPI
 	*ScriptsAPI
 	*AuditAPI
 	*SubAgentAPI
+	*SessionRecordingsAPI
 	*tailnet.DRPCService
 
 	mu sync.Mutex
@@ -73,6 +75,7 @@ type Options struct {
 	Pubsub                            pubsub.Pubsub
 	Auditor                           *atomic.Pointer[audit.Auditor]
 	DerpMapFn                         func() *tailcfg.DERPMap
+	DerpQUICPortsFn                   func() tailnet.DERPQUICPorts
 	TailnetCoordinator                *atomic.Pointer[tailnet.Coordinator]
 	StatsReporter                     *workspacestats.Reporter
 	AppearanceFetcher                 *atomic.Pointer[appearance.Fetcher]
@@ -161,6 +164,13 @@ func New(opts Options) *API {
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
 	}
 
+	api.ServicesAPI = &ServicesAPI{
+		AgentFn:                  api.agent,
+		Database:                 opts.Database,
+		Log:                      opts.Log,
+		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
+	}
+
 	api.MetadataAPI = &MetadataAPI{
 		AgentFn:  api.agent,
 		Database: opts.Database,
@@ -192,6 +202,7 @@ func New(opts Options) *API {
 		Logger:                  opts.Log,
 		DerpMapUpdateFrequency:  opts.DerpMapUpdateFrequency,
 		DerpMapFn:               opts.DerpMapFn,
+		DerpQUICPortsFn:         opts.DerpQUICPortsFn,
 		NetworkTelemetryHandler: opts.NetworkTelemetryHandler,
 	}
 
@@ -205,6 +216,17 @@ func New(opts Options) *API {
 		Database:       opts.Database,
 	}
 
+	api.SessionRecordingsAPI = &SessionRecordingsAPI{
+		AgentID:          opts.AgentID,
+		WorkspaceID:      opts.WorkspaceID,
+		OwnerID:          opts.OwnerID,
+		OrganizationID:   opts.OrganizationID,
+		Log:              opts.Log,
+		Clock:            opts.Clock,
+		Database:         opts.Database,
+		MaxRecordingSize: 64 << 20, // 64MiB
+	}
+
 	return api
 }
 
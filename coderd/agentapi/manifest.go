@@ -45,6 +45,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 	var (
 		dbApps        []database.WorkspaceApp
 		scripts       []database.WorkspaceAgentScript
+		services      []database.WorkspaceAgentService
 		metadata      []database.WorkspaceAgentMetadatum
 		workspace     database.Workspace
 		devcontainers []database.WorkspaceAgentDevcontainer
@@ -63,6 +64,11 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		scripts, err = a.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
 		return err
 	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // This is necessary to fetch agent services!
+		services, err = a.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+		return err
+	})
 	eg.Go(func() (err error) {
 		metadata, err = a.Database.GetWorkspaceAgentMetadata(ctx, database.GetWorkspaceAgentMetadataParams{
 			WorkspaceAgentID: workspaceAgent.ID,
@@ -89,6 +95,20 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		return nil, xerrors.Errorf("fetching workspace agent data: %w", err)
 	}
 
+	// The agent cannot read the secrets of the workspace owner, but is
+	// trusted to deliver them to the workspace.
+	// nolint:gocritic // This is necessary to fetch user secrets!
+	secrets, err := a.Database.GetUserSecretsByUserID(dbauthz.AsSystemRestricted(ctx), workspace.OwnerID)
+	if err != nil {
+		return nil, xerrors.Errorf("fetching user secrets: %w", err)
+	}
+
+	// nolint:gocritic // This is necessary to check whether sessions are recorded.
+	template, err := a.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		return nil, xerrors.Errorf("fetching template: %w", err)
+	}
+
 	appSlug := appurl.ApplicationURL{
 		AppSlugOrPort: "{{port}}",
 		AgentName:     workspaceAgent.Name,
@@ -134,12 +154,15 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
 		ParentId:                 parentID,
+		SessionRecordingEnabled:  template.SessionRecordingEnabled,
 
 		DerpMap:       tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:       dbAgentScriptsToProto(scripts),
 		Apps:          apps,
 		Metadata:      dbAgentMetadataToProtoDescription(metadata),
 		Devcontainers: dbAgentDevcontainersToProto(devcontainers),
+		Secrets:       dbUserSecretsToProto(secrets),
+		Services:      dbAgentServicesToProto(services),
 	}, nil
 }
 
@@ -196,6 +219,44 @@ func dbAgentScriptToProto(script database.WorkspaceAgentScript) *agentproto.Work
 	}
 }
 
+func dbAgentServicesToProto(services []database.WorkspaceAgentService) []*agentproto.WorkspaceAgentService {
+	ret := make([]*agentproto.WorkspaceAgentService, len(services))
+	for i, service := range services {
+		ret[i] = dbAgentServiceToProto(service)
+	}
+	return ret
+}
+
+func dbAgentServiceToProto(service database.WorkspaceAgentService) *agentproto.WorkspaceAgentService {
+	var restartPolicy agentproto.WorkspaceAgentService_RestartPolicy
+	switch service.RestartPolicy {
+	case database.WorkspaceAgentServiceRestartPolicyOnFailure:
+		restartPolicy = agentproto.WorkspaceAgentService_ON_FAILURE
+	case database.WorkspaceAgentServiceRestartPolicyNever:
+		restartPolicy = agentproto.WorkspaceAgentService_NEVER
+	default:
+		restartPolicy = agentproto.WorkspaceAgentService_ALWAYS
+	}
+	ret := &agentproto.WorkspaceAgentService{
+		Id:            service.ID[:],
+		LogSourceId:   service.LogSourceID[:],
+		LogPath:       service.LogPath,
+		Name:          service.Name,
+		DisplayName:   service.DisplayName,
+		Command:       service.Command,
+		RestartPolicy: restartPolicy,
+		RestartDelay:  durationpb.New(time.Duration(service.RestartDelaySeconds) * time.Second),
+	}
+	if service.ReadinessURL != "" {
+		ret.ReadinessProbe = &agentproto.WorkspaceAgentService_ReadinessProbe{
+			Url:       service.ReadinessURL,
+			Interval:  durationpb.New(time.Duration(service.ReadinessIntervalSeconds) * time.Second),
+			Threshold: service.ReadinessThreshold,
+		}
+	}
+	return ret
+}
+
 func dbAppsToProto(dbApps []database.WorkspaceApp, agent database.WorkspaceAgent, ownerName string, workspace database.Workspace) ([]*agentproto.WorkspaceApp, error) {
 	ret := make([]*agentproto.WorkspaceApp, len(dbApps))
 	for i, dbApp := range dbApps {
@@ -252,3 +313,21 @@ func dbAgentDevcontainersToProto(devcontainers []database.WorkspaceAgentDevconta
 	}
 	return ret
 }
+
+// dbUserSecretsToProto converts the secrets that are delivered to workspaces,
+// which are the ones exposed as an environment variable or a file.
+func dbUserSecretsToProto(secrets []database.UserSecret) []*agentproto.WorkspaceAgentSecret {
+	ret := make([]*agentproto.WorkspaceAgentSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret.EnvName == "" && secret.FilePath == "" {
+			continue
+		}
+		ret = append(ret, &agentproto.WorkspaceAgentSecret{
+			Name:     secret.Name,
+			EnvName:  secret.EnvName,
+			FilePath: secret.FilePath,
+			Value:    secret.Value,
+		})
+	}
+	return ret
+}
@@ -45,10 +45,15 @@ func TestGetManifest(t *testing.T) {
 			ID:       uuid.New(),
 			Username: "cool-user",
 		}
+		template = database.Template{
+			ID:                      uuid.New(),
+			SessionRecordingEnabled: true,
+		}
 		workspace = database.Workspace{
 			ID:            uuid.New(),
 			OwnerID:       owner.ID,
 			OwnerUsername: owner.Username,
+			TemplateID:    template.ID,
 			Name:          "cool-workspace",
 		}
 		agent = database.WorkspaceAgent{
@@ -164,6 +169,54 @@ func TestGetManifest(t *testing.T) {
 				CollectedAt:      someTime.Add(time.Hour),
 			},
 		}
+		secrets = []database.UserSecret{
+			{
+				ID:      uuid.New(),
+				UserID:  owner.ID,
+				Name:    "openai",
+				Value:   "sk-123",
+				EnvName: "OPENAI_API_KEY",
+			},
+			{
+				// Not delivered to the workspace.
+				ID:     uuid.New(),
+				UserID: owner.ID,
+				Name:   "unused",
+				Value:  "value",
+			},
+			{
+				ID:       uuid.New(),
+				UserID:   owner.ID,
+				Name:     "deploy-key",
+				Value:    "key",
+				FilePath: "~/.ssh/deploy_key",
+			},
+		}
+		services = []database.WorkspaceAgentService{
+			{
+				ID:                       uuid.New(),
+				WorkspaceAgentID:         agent.ID,
+				LogSourceID:              uuid.New(),
+				Name:                     "web",
+				DisplayName:              "Web Server",
+				Command:                  "npm run dev",
+				RestartPolicy:            database.WorkspaceAgentServiceRestartPolicyAlways,
+				RestartDelaySeconds:      5,
+				ReadinessURL:             "http://localhost:3000",
+				ReadinessIntervalSeconds: 5,
+				ReadinessThreshold:       3,
+			},
+			{
+				ID:               uuid.New(),
+				WorkspaceAgentID: agent.ID,
+				LogSourceID:      uuid.New(),
+				LogPath:          "/tmp/worker.log",
+				Name:             "worker",
+				DisplayName:      "worker",
+				Command:          "./worker",
+				RestartPolicy:    database.WorkspaceAgentServiceRestartPolicyNever,
+			},
+		}
 		devcontainers = []database.WorkspaceAgentDevcontainer{
 			{
 				ID:               uuid.New(),
@@ -290,6 +343,44 @@ func TestGetManifest(t *testing.T) {
 				Timeout:     durationpb.New(time.Duration(metadata[1].Timeout)),
 			},
 		}
+		protoSecrets = []*agentproto.WorkspaceAgentSecret{
+			{
+				Name:    secrets[0].Name,
+				EnvName: secrets[0].EnvName,
+				Value:   secrets[0].Value,
+			},
+			{
+				Name:     secrets[2].Name,
+				FilePath: secrets[2].FilePath,
+				Value:    secrets[2].Value,
+			},
+		}
+		protoServices = []*agentproto.WorkspaceAgentService{
+			{
+				Id:            services[0].ID[:],
+				LogSourceId:   services[0].LogSourceID[:],
+				Name:          services[0].Name,
+				DisplayName:   services[0].DisplayName,
+				Command:       services[0].Command,
+				RestartPolicy: agentproto.WorkspaceAgentService_ALWAYS,
+				RestartDelay:  durationpb.New(5 * time.Second),
+				ReadinessProbe: &agentproto.WorkspaceAgentService_ReadinessProbe{
+					Url:       services[0].ReadinessURL,
+					Interval:  durationpb.New(5 * time.Second),
+					Threshold: 3,
+				},
+			},
+			{
+				Id:            services[1].ID[:],
+				LogSourceId:   services[1].LogSourceID[:],
+				LogPath:       services[1].LogPath,
+				Name:          services[1].Name,
+				DisplayName:   services[1].DisplayName,
+				Command:       services[1].Command,
+				RestartPolicy: agentproto.WorkspaceAgentService_NEVER,
+				RestartDelay:  durationpb.New(0),
+			},
+		}
 		protoDevcontainers = []*agentproto.WorkspaceAgentDevcontainer{
 			{
 				Id:              devcontainers[0].ID[:],
@@ -331,12 +422,15 @@ func TestGetManifest(t *testing.T) {
 
 		mDB.EXPECT().GetWorkspaceAppsByAgentID(gomock.Any(), agent.ID).Return(apps, nil)
 		mDB.EXPECT().GetWorkspaceAgentScriptsByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(scripts, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(services, nil)
 		mDB.EXPECT().GetWorkspaceAgentMetadata(gomock.Any(), database.GetWorkspaceAgentMetadataParams{
 			WorkspaceAgentID: agent.ID,
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetUserSecretsByUserID(gomock.Any(), owner.ID).Return(secrets, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -355,6 +449,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecordingEnabled:  true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -363,6 +458,8 @@ func TestGetManifest(t *testing.T) {
 			Apps:          protoApps,
 			Metadata:      protoMetadata,
 			Devcontainers: protoDevcontainers,
+			Secrets:       protoSecrets,
+			Services:      protoServices,
 		}
 
 		// Log got and expected with spew.
@@ -398,12 +495,15 @@ func TestGetManifest(t *testing.T) {
 
 		mDB.EXPECT().GetWorkspaceAppsByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceApp{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentScriptsByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return([]database.WorkspaceAgentScript{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return([]database.WorkspaceAgentService{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentMetadata(gomock.Any(), database.GetWorkspaceAgentMetadataParams{
 			WorkspaceAgentID: childAgent.ID,
 			Keys:             nil, // all
 		}).Return([]database.WorkspaceAgentMetadatum{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceAgentDevcontainer{}, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetUserSecretsByUserID(gomock.Any(), owner.ID).Return(secrets, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -422,6 +522,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 childAgent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecordingEnabled:  true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -430,6 +531,8 @@ func TestGetManifest(t *testing.T) {
 			Apps:          []*agentproto.WorkspaceApp{},
 			Metadata:      []*agentproto.WorkspaceAgentMetadata_Description{},
 			Devcontainers: []*agentproto.WorkspaceAgentDevcontainer{},
+			Secrets:       protoSecrets,
+			Services:      []*agentproto.WorkspaceAgentService{},
 		}
 
 		require.Equal(t, expected, got)
@@ -461,12 +564,15 @@ func TestGetManifest(t *testing.T) {
 
 		mDB.EXPECT().GetWorkspaceAppsByAgentID(gomock.Any(), agent.ID).Return(apps, nil)
 		mDB.EXPECT().GetWorkspaceAgentScriptsByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(scripts, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(services, nil)
 		mDB.EXPECT().GetWorkspaceAgentMetadata(gomock.Any(), database.GetWorkspaceAgentMetadataParams{
 			WorkspaceAgentID: agent.ID,
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetUserSecretsByUserID(gomock.Any(), owner.ID).Return(secrets, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -484,6 +590,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecordingEnabled:  true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -492,6 +599,8 @@ func TestGetManifest(t *testing.T) {
 			Apps:          protoApps,
 			Metadata:      protoMetadata,
 			Devcontainers: protoDevcontainers,
+			Secrets:       protoSecrets,
+			Services:      protoServices,
 		}
 
 		// Log got and expected with spew.
@@ -46,7 +46,12 @@ func (a *ResourcesMonitoringAPI) GetResourcesMonitoringConfiguration(ctx context
 		return nil, xerrors.Errorf("failed to fetch volume resource monitors: %w", err)
 	}
 
-	return &proto.GetResourcesMonitoringConfigurationResponse{
+	resourceMonitors, err := a.Database.FetchResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch resource monitors: %w", err)
+	}
+
+	resp := &proto.GetResourcesMonitoringConfigurationResponse{
 		Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
 			CollectionIntervalSeconds: int32(a.Config.CollectionInterval.Seconds()),
 			NumDatapoints:             a.Config.NumDatapoints,
@@ -71,7 +76,31 @@ func (a *ResourcesMonitoringAPI) GetResourcesMonitoringConfiguration(ctx context
 
 			return volumes
 		}(),
-	}, nil
+	}
+
+	for _, monitor := range resourceMonitors {
+		switch monitor.Type {
+		case database.WorkspaceAgentResourceMonitorTypeCPU:
+			resp.Cpu = &proto.GetResourcesMonitoringConfigurationResponse_CPU{
+				Enabled: monitor.Enabled,
+			}
+		case database.WorkspaceAgentResourceMonitorTypeLoad:
+			resp.Load = &proto.GetResourcesMonitoringConfigurationResponse_Load{
+				Enabled: monitor.Enabled,
+			}
+		case database.WorkspaceAgentResourceMonitorTypeInodes:
+			resp.Inodes = append(resp.Inodes, &proto.GetResourcesMonitoringConfigurationResponse_Inodes{
+				Enabled: monitor.Enabled,
+				Path:    monitor.Path,
+			})
+		case database.WorkspaceAgentResourceMonitorTypeProcesses:
+			resp.Processes = &proto.GetResourcesMonitoringConfigurationResponse_Processes{
+				Enabled: monitor.Enabled,
+			}
+		}
+	}
+
+	return resp, nil
 }
 
 func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
@@ -85,6 +114,10 @@ func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Contex
 		err = errors.Join(err, xerrors.Errorf("monitor volume: %w", volumeErr))
 	}
 
+	if resourcesErr := a.monitorResources(ctx, req.Datapoints); resourcesErr != nil {
+		err = errors.Join(err, xerrors.Errorf("monitor resources: %w", resourcesErr))
+	}
+
 	return &proto.PushResourcesMonitoringUsageResponse{}, err
 }
 
@@ -260,3 +293,100 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 
 	return nil
 }
+
+// monitorResources monitors the resources other than memory and volume space.
+// A single notification lists all the resources that reached their threshold.
+func (a *ResourcesMonitoringAPI) monitorResources(ctx context.Context, datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
+	resourceMonitors, err := a.Database.FetchResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return xerrors.Errorf("fetch resource monitors: %w", err)
+	}
+
+	exhaustedResources := make([]map[string]any, 0)
+
+	for _, monitor := range resourceMonitors {
+		if !monitor.Enabled {
+			continue
+		}
+
+		usageStates := resourcesmonitor.CalculateResourceUsageStates(monitor, datapoints)
+
+		oldState := monitor.State
+		newState := resourcesmonitor.NextState(a.Config, oldState, usageStates)
+
+		debouncedUntil, shouldNotify := monitor.Debounce(a.Debounce, a.Clock.Now(), oldState, newState)
+
+		if shouldNotify {
+			exhaustedResources = append(exhaustedResources, map[string]any{
+				"resource":  resourceMonitorName(monitor),
+				"threshold": resourceMonitorThreshold(monitor),
+			})
+		}
+
+		//nolint:gocritic // We need to be able to update the resource monitor here.
+		if err := a.Database.UpdateResourceMonitor(dbauthz.AsResourceMonitor(ctx), database.UpdateResourceMonitorParams{
+			AgentID:        a.AgentID,
+			Type:           monitor.Type,
+			Path:           monitor.Path,
+			State:          newState,
+			UpdatedAt:      dbtime.Time(a.Clock.Now()),
+			DebouncedUntil: dbtime.Time(debouncedUntil),
+		}); err != nil {
+			return xerrors.Errorf("update workspace monitor: %w", err)
+		}
+	}
+
+	if len(exhaustedResources) == 0 {
+		return nil
+	}
+
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	if _, err := a.NotificationsEnqueuer.EnqueueWithData(
+		// nolint:gocritic // We need to be able to send the notification.
+		dbauthz.AsNotifier(ctx),
+		workspace.OwnerID,
+		notifications.TemplateWorkspaceResourcesExhausted,
+		map[string]string{
+			"workspace": workspace.Name,
+		},
+		map[string]any{
+			"monitors": exhaustedResources,
+			// See monitorMemory for why a timestamp is injected.
+			"timestamp": a.Clock.Now(),
+		},
+		"workspace-monitor-resources",
+		workspace.ID,
+		workspace.OwnerID,
+		workspace.OrganizationID,
+	); err != nil {
+		return xerrors.Errorf("notify workspace resources exhausted: %w", err)
+	}
+
+	return nil
+}
+
+func resourceMonitorName(monitor database.WorkspaceAgentResourceMonitor) string {
+	switch monitor.Type {
+	case database.WorkspaceAgentResourceMonitorTypeCPU:
+		return "CPU usage"
+	case database.WorkspaceAgentResourceMonitorTypeLoad:
+		return "Load average"
+	case database.WorkspaceAgentResourceMonitorTypeInodes:
+		return fmt.Sprintf("Inode usage of %s", monitor.Path)
+	case database.WorkspaceAgentResourceMonitorTypeProcesses:
+		return "Process count"
+	default:
+		return string(monitor.Type)
+	}
+}
+
+func resourceMonitorThreshold(monitor database.WorkspaceAgentResourceMonitor) string {
+	if monitor.Type == database.WorkspaceAgentResourceMonitorTypeProcesses {
+		return fmt.Sprintf("%d", monitor.Threshold)
+	}
+	return fmt.Sprintf("%d%%", monitor.Threshold)
+}
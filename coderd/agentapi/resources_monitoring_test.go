@@ -942,3 +942,204 @@ func requireVolumeData(t *testing.T, notif *notificationstest.FakeNotification)
 
 	return volumesData.([]map[string]any)
 }
+
+func TestResourceMonitor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		monitor       database.WorkspaceAgentResourceMonitor
+		datapoint     *agentproto.PushResourcesMonitoringUsageRequest_Datapoint
+		expectState   database.WorkspaceAgentMonitorState
+		expectMonitor map[string]any
+	}{
+		{
+			name: "CPU/BelowThreshold",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeCPU,
+				Threshold: 90,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Cpu: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{Used: 1, Total: 4},
+			},
+			expectState: database.WorkspaceAgentMonitorStateOK,
+		},
+		{
+			name: "CPU/ExceedsThreshold",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeCPU,
+				Threshold: 90,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Cpu: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{Used: 3.8, Total: 4},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "CPU usage", "threshold": "90%"},
+		},
+		{
+			name: "Load/ExceedsThreshold",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeLoad,
+				Threshold: 150,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Load: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_LoadAverage{Load1: 8, Cores: 4},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "Load average", "threshold": "150%"},
+		},
+		{
+			name: "Inodes/OtherVolume",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeInodes,
+				Path:      "/home/coder",
+				Threshold: 80,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Inodes: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint_InodesUsage{
+					{Volume: "/", Used: 10, Total: 10},
+				},
+			},
+			expectState: database.WorkspaceAgentMonitorStateOK,
+		},
+		{
+			name: "Inodes/ExceedsThreshold",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeInodes,
+				Path:      "/home/coder",
+				Threshold: 80,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Inodes: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint_InodesUsage{
+					{Volume: "/home/coder", Used: 9, Total: 10},
+				},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "Inode usage of /home/coder", "threshold": "80%"},
+		},
+		{
+			name: "Processes/ExceedsThreshold",
+			monitor: database.WorkspaceAgentResourceMonitor{
+				Type:      database.WorkspaceAgentResourceMonitorTypeProcesses,
+				Threshold: 500,
+			},
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Processes: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{Count: 600},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "Process count", "threshold": "500"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			api, user, clock, notifyEnq := resourceMonitorAPI(t)
+			api.Config.Alert.ConsecutiveNOKsPercent = 100
+
+			tt.monitor.AgentID = api.AgentID
+			tt.monitor.State = database.WorkspaceAgentMonitorStateOK
+			dbgen.WorkspaceAgentResourceMonitor(t, api.Database, tt.monitor)
+
+			tt.datapoint.CollectedAt = timestamppb.New(clock.Now())
+			_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+				Datapoints: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint{tt.datapoint},
+			})
+			require.NoError(t, err)
+
+			monitors, err := api.Database.FetchResourceMonitorsByAgentID(context.Background(), api.AgentID)
+			require.NoError(t, err)
+			require.Len(t, monitors, 1)
+			require.Equal(t, tt.expectState, monitors[0].State)
+
+			sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceResourcesExhausted))
+			if tt.expectMonitor == nil {
+				require.Len(t, sent, 0)
+				return
+			}
+			require.Len(t, sent, 1)
+			require.Equal(t, user.ID, sent[0].UserID)
+			require.Equal(t, []map[string]any{tt.expectMonitor}, sent[0].Data["monitors"])
+		})
+	}
+}
+
+func TestResourceMonitorDebounce(t *testing.T) {
+	t.Parallel()
+
+	api, _, clock, notifyEnq := resourceMonitorAPI(t)
+	api.Config.Alert.ConsecutiveNOKsPercent = 100
+
+	dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+		AgentID:   api.AgentID,
+		Type:      database.WorkspaceAgentResourceMonitorTypeProcesses,
+		State:     database.WorkspaceAgentMonitorStateOK,
+		Threshold: 100,
+	})
+
+	push := func(count int64) {
+		t.Helper()
+		_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+			Datapoints: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				{
+					CollectedAt: timestamppb.New(clock.Now()),
+					Processes:   &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{Count: count},
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// When: the monitor moves to a NOK state
+	push(200)
+
+	// Then: a notification is sent
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceResourcesExhausted))
+	require.Len(t, sent, 1)
+
+	// When: the monitor moves back to OK and then NOK within the debounce
+	clock.Advance(api.Debounce / 2)
+	push(50)
+	push(200)
+
+	// Then: no new notification is sent
+	sent = notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceResourcesExhausted))
+	require.Len(t, sent, 1)
+
+	// When: the monitor moves back to OK and then NOK after the debounce
+	clock.Advance(api.Debounce)
+	push(50)
+	push(200)
+
+	// Then: a new notification is sent
+	sent = notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceResourcesExhausted))
+	require.Len(t, sent, 2)
+}
+
+func TestResourceMonitorConfiguration(t *testing.T) {
+	t.Parallel()
+
+	api, _, _, _ := resourceMonitorAPI(t)
+
+	dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+		AgentID: api.AgentID,
+		Type:    database.WorkspaceAgentResourceMonitorTypeCPU,
+	})
+	dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+		AgentID: api.AgentID,
+		Type:    database.WorkspaceAgentResourceMonitorTypeInodes,
+		Path:    "/home/coder",
+	})
+
+	config, err := api.GetResourcesMonitoringConfiguration(context.Background(), &agentproto.GetResourcesMonitoringConfigurationRequest{})
+	require.NoError(t, err)
+	require.True(t, config.GetCpu().GetEnabled())
+	require.Nil(t, config.Load)
+	require.Nil(t, config.Processes)
+	require.Len(t, config.Inodes, 1)
+	require.Equal(t, "/home/coder", config.Inodes[0].Path)
+	require.True(t, config.Inodes[0].Enabled)
+}
@@ -88,6 +88,66 @@ func CalculateVolumeUsageStates(
 	return states
 }
 
+// CalculateResourceUsageStates calculates the states of a monitor of a
+// resource other than memory and volume space. The usage of CPU, load and
+// inodes is a percentage and the usage of processes is a count, as is the
+// threshold of the monitor.
+func CalculateResourceUsageStates(
+	monitor database.WorkspaceAgentResourceMonitor,
+	datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint,
+) []State {
+	states := make([]State, 0, len(datapoints))
+
+	for _, datapoint := range datapoints {
+		state := StateUnknown
+
+		if usage, ok := resourceUsage(monitor, datapoint); ok {
+			if usage < monitor.Threshold {
+				state = StateOK
+			} else {
+				state = StateNOK
+			}
+		}
+
+		states = append(states, state)
+	}
+
+	return states
+}
+
+func resourceUsage(monitor database.WorkspaceAgentResourceMonitor, datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint) (int32, bool) {
+	switch monitor.Type {
+	case database.WorkspaceAgentResourceMonitorTypeCPU:
+		cpu := datapoint.GetCpu()
+		if cpu == nil || cpu.Total <= 0 {
+			return 0, false
+		}
+		return int32(cpu.Used / cpu.Total * 100), true
+	case database.WorkspaceAgentResourceMonitorTypeLoad:
+		load := datapoint.GetLoad()
+		if load == nil || load.Cores <= 0 {
+			return 0, false
+		}
+		return int32(load.Load1 / float64(load.Cores) * 100), true
+	case database.WorkspaceAgentResourceMonitorTypeInodes:
+		for _, inodes := range datapoint.GetInodes() {
+			if inodes.Volume == monitor.Path && inodes.Total > 0 {
+				return int32(float64(inodes.Used) / float64(inodes.Total) * 100), true
+			}
+		}
+		return 0, false
+	case database.WorkspaceAgentResourceMonitorTypeProcesses:
+		processes := datapoint.GetProcesses()
+		if processes == nil {
+			return 0, false
+		}
+		// #nosec G115 - clamped to the range of int32.
+		return int32(min(processes.Count, math.MaxInt32)), true
+	default:
+		return 0, false
+	}
+}
+
 func NextState(c Config, oldState database.WorkspaceAgentMonitorState, states []State) database.WorkspaceAgentMonitorState {
 	// If there are enough consecutive NOK states, we should be in an
 	// alert state.
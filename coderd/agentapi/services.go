@@ -0,0 +1,155 @@
+package agentapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+)
+
+type ServicesAPI struct {
+	AgentFn                  func(context.Context) (database.WorkspaceAgent, error)
+	Database                 database.Store
+	Log                      slog.Logger
+	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent, wspubsub.WorkspaceEventKind) error
+}
+
+func (a *ServicesAPI) BatchUpdateServices(ctx context.Context, req *agentproto.BatchUpdateServicesRequest) (*agentproto.BatchUpdateServicesResponse, error) {
+	workspaceAgent, err := a.AgentFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Log.Debug(ctx, "got batch service update",
+		slog.F("agent_id", workspaceAgent.ID.String()),
+		slog.F("updates", req.Updates),
+	)
+
+	if len(req.Updates) == 0 {
+		return &agentproto.BatchUpdateServicesResponse{}, nil
+	}
+
+	// nolint:gocritic // This is necessary to fetch agent services!
+	services, err := a.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace agent services by agent ID %q: %w", workspaceAgent.ID, err)
+	}
+
+	var (
+		updated       []database.UpdateWorkspaceAgentServiceStatusByIDParams
+		healthChanged bool
+	)
+	for _, update := range req.Updates {
+		updateID, err := uuid.FromBytes(update.Id)
+		if err != nil {
+			return nil, xerrors.Errorf("parse workspace agent service ID %q: %w", update.Id, err)
+		}
+
+		old := func() *database.WorkspaceAgentService {
+			for _, service := range services {
+				if service.ID == updateID {
+					return &service
+				}
+			}
+			return nil
+		}()
+		if old == nil {
+			return nil, xerrors.Errorf("workspace agent service ID %q not found", updateID)
+		}
+
+		status, err := serviceStatusFromProto(update.Status)
+		if err != nil {
+			return nil, xerrors.Errorf("service %q (%q): %w", updateID, old.Name, err)
+		}
+		health, err := appHealthFromProto(update.Health)
+		if err != nil {
+			return nil, xerrors.Errorf("service %q (%q): %w", updateID, old.Name, err)
+		}
+		if old.ReadinessURL == "" && health != database.WorkspaceAppHealthDisabled {
+			return nil, xerrors.Errorf("workspace agent service %q (%q) does not have a readiness probe", updateID, old.Name)
+		}
+		var exitCode sql.NullInt32
+		if update.ExitCode != nil {
+			exitCode = sql.NullInt32{Int32: update.GetExitCode(), Valid: true}
+		}
+
+		// Don't bother updating if the value hasn't changed.
+		if old.Status == status && old.Health == health && old.Restarts == update.Restarts && old.ExitCode == exitCode {
+			continue
+		}
+		if old.Health != health {
+			healthChanged = true
+		}
+		updated = append(updated, database.UpdateWorkspaceAgentServiceStatusByIDParams{
+			ID:               old.ID,
+			WorkspaceAgentID: workspaceAgent.ID,
+			Status:           status,
+			Health:           health,
+			Restarts:         update.Restarts,
+			ExitCode:         exitCode,
+			UpdatedAt:        dbtime.Now(),
+		})
+	}
+
+	for _, params := range updated {
+		err = a.Database.UpdateWorkspaceAgentServiceStatusByID(ctx, params)
+		if err != nil {
+			return nil, xerrors.Errorf("update workspace agent service %q: %w", params.ID, err)
+		}
+	}
+
+	if a.PublishWorkspaceUpdateFn != nil && len(updated) > 0 {
+		kind := wspubsub.WorkspaceEventKindAgentServiceUpdate
+		if healthChanged {
+			// Readiness of services is shown next to the health of apps.
+			kind = wspubsub.WorkspaceEventKindAppHealthUpdate
+		}
+		err = a.PublishWorkspaceUpdateFn(ctx, &workspaceAgent, kind)
+		if err != nil {
+			return nil, xerrors.Errorf("publish workspace update: %w", err)
+		}
+	}
+	return &agentproto.BatchUpdateServicesResponse{}, nil
+}
+
+func serviceStatusFromProto(status agentproto.ServiceStatus) (database.WorkspaceAgentServiceStatus, error) {
+	switch status {
+	case agentproto.ServiceStatus_PENDING:
+		return database.WorkspaceAgentServiceStatusPending, nil
+	case agentproto.ServiceStatus_STARTING:
+		return database.WorkspaceAgentServiceStatusStarting, nil
+	case agentproto.ServiceStatus_RUNNING:
+		return database.WorkspaceAgentServiceStatusRunning, nil
+	case agentproto.ServiceStatus_RESTARTING:
+		return database.WorkspaceAgentServiceStatusRestarting, nil
+	case agentproto.ServiceStatus_STOPPED:
+		return database.WorkspaceAgentServiceStatusStopped, nil
+	case agentproto.ServiceStatus_FAILED:
+		return database.WorkspaceAgentServiceStatusFailed, nil
+	default:
+		return "", xerrors.Errorf("unknown service status %q", status)
+	}
+}
+
+func appHealthFromProto(health agentproto.AppHealth) (database.WorkspaceAppHealth, error) {
+	switch health {
+	case agentproto.AppHealth_DISABLED:
+		return database.WorkspaceAppHealthDisabled, nil
+	case agentproto.AppHealth_INITIALIZING:
+		return database.WorkspaceAppHealthInitializing, nil
+	case agentproto.AppHealth_HEALTHY:
+		return database.WorkspaceAppHealthHealthy, nil
+	case agentproto.AppHealth_UNHEALTHY:
+		return database.WorkspaceAppHealthUnhealthy, nil
+	default:
+		return "", xerrors.Errorf("unknown health status %q", health)
+	}
+}
@@ -0,0 +1,217 @@
+package agentapi_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestBatchUpdateServices(t *testing.T) {
+	t.Parallel()
+
+	var (
+		agent = database.WorkspaceAgent{
+			ID: uuid.New(),
+		}
+		web = database.WorkspaceAgentService{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agent.ID,
+			Name:             "web",
+			Command:          "npm run dev",
+			RestartPolicy:    database.WorkspaceAgentServiceRestartPolicyAlways,
+			ReadinessURL:     "http://localhost:3000",
+			Status:           database.WorkspaceAgentServiceStatusPending,
+			Health:           database.WorkspaceAppHealthInitializing,
+		}
+		worker = database.WorkspaceAgentService{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agent.ID,
+			Name:             "worker",
+			Command:          "./worker",
+			RestartPolicy:    database.WorkspaceAgentServiceRestartPolicyOnFailure,
+			Status:           database.WorkspaceAgentServiceStatusRunning,
+			Health:           database.WorkspaceAppHealthDisabled,
+		}
+	)
+
+	newAPI := func(t *testing.T, dbM *dbmock.MockStore, published *[]wspubsub.WorkspaceEventKind) *agentapi.ServicesAPI {
+		return &agentapi.ServicesAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			Database: dbM,
+			Log:      testutil.Logger(t),
+			PublishWorkspaceUpdateFn: func(_ context.Context, _ *database.WorkspaceAgent, kind wspubsub.WorkspaceEventKind) error {
+				*published = append(*published, kind)
+				return nil
+			},
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return([]database.WorkspaceAgentService{web, worker}, nil)
+		dbM.EXPECT().UpdateWorkspaceAgentServiceStatusByID(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+				require.Equal(t, web.ID, arg.ID)
+				require.Equal(t, agent.ID, arg.WorkspaceAgentID)
+				require.Equal(t, database.WorkspaceAgentServiceStatusRunning, arg.Status)
+				require.Equal(t, database.WorkspaceAppHealthHealthy, arg.Health)
+				require.False(t, arg.ExitCode.Valid)
+				return nil
+			})
+		dbM.EXPECT().UpdateWorkspaceAgentServiceStatusByID(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+				require.Equal(t, worker.ID, arg.ID)
+				require.Equal(t, database.WorkspaceAgentServiceStatusRestarting, arg.Status)
+				require.Equal(t, database.WorkspaceAppHealthDisabled, arg.Health)
+				require.EqualValues(t, 1, arg.Restarts)
+				require.Equal(t, sql.NullInt32{Int32: 2, Valid: true}, arg.ExitCode)
+				return nil
+			})
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		exitCode := int32(2)
+		resp, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{
+			Updates: []*agentproto.BatchUpdateServicesRequest_Update{
+				{
+					Id:     web.ID[:],
+					Status: agentproto.ServiceStatus_RUNNING,
+					Health: agentproto.AppHealth_HEALTHY,
+				},
+				{
+					Id:       worker.ID[:],
+					Status:   agentproto.ServiceStatus_RESTARTING,
+					Health:   agentproto.AppHealth_DISABLED,
+					Restarts: 1,
+					ExitCode: &exitCode,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &agentproto.BatchUpdateServicesResponse{}, resp)
+		// The health of a service changed, so the app health surface is
+		// notified.
+		require.Equal(t, []wspubsub.WorkspaceEventKind{wspubsub.WorkspaceEventKindAppHealthUpdate}, published)
+	})
+
+	t.Run("StatusOnly", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return([]database.WorkspaceAgentService{web, worker}, nil)
+		dbM.EXPECT().UpdateWorkspaceAgentServiceStatusByID(gomock.Any(), gomock.Any()).Return(nil)
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		exitCode := int32(0)
+		_, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{
+			Updates: []*agentproto.BatchUpdateServicesRequest_Update{
+				{
+					Id:       worker.ID[:],
+					Status:   agentproto.ServiceStatus_STOPPED,
+					Health:   agentproto.AppHealth_DISABLED,
+					ExitCode: &exitCode,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []wspubsub.WorkspaceEventKind{wspubsub.WorkspaceEventKindAgentServiceUpdate}, published)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return([]database.WorkspaceAgentService{web, worker}, nil)
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		_, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{
+			Updates: []*agentproto.BatchUpdateServicesRequest_Update{
+				{
+					Id:     worker.ID[:],
+					Status: agentproto.ServiceStatus_RUNNING,
+					Health: agentproto.AppHealth_DISABLED,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, published)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		// No DB calls are made if there are no updates.
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		_, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{})
+		require.NoError(t, err)
+		require.Empty(t, published)
+	})
+
+	t.Run("NoReadinessProbe", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return([]database.WorkspaceAgentService{web, worker}, nil)
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		_, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{
+			Updates: []*agentproto.BatchUpdateServicesRequest_Update{
+				{
+					Id:     worker.ID[:],
+					Status: agentproto.ServiceStatus_RUNNING,
+					Health: agentproto.AppHealth_HEALTHY,
+				},
+			},
+		})
+		require.ErrorContains(t, err, "does not have a readiness probe")
+		require.Empty(t, published)
+	})
+
+	t.Run("UnknownService", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return([]database.WorkspaceAgentService{web, worker}, nil)
+
+		var published []wspubsub.WorkspaceEventKind
+		api := newAPI(t, dbM, &published)
+
+		id := uuid.New()
+		_, err := api.BatchUpdateServices(context.Background(), &agentproto.BatchUpdateServicesRequest{
+			Updates: []*agentproto.BatchUpdateServicesRequest_Update{
+				{
+					Id:     id[:],
+					Status: agentproto.ServiceStatus_RUNNING,
+					Health: agentproto.AppHealth_DISABLED,
+				},
+			},
+		})
+		require.ErrorContains(t, err, "not found")
+	})
+}
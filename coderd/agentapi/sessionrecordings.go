@@ -0,0 +1,158 @@
+package agentapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+type SessionRecordingsAPI struct {
+	AgentID        uuid.UUID
+	WorkspaceID    uuid.UUID
+	OwnerID        uuid.UUID
+	OrganizationID uuid.UUID
+
+	Log      slog.Logger
+	Clock    quartz.Clock
+	Database database.Store
+
+	// MaxRecordingSize is the maximum size in bytes of a single recording.
+	// Chunks that would exceed it are rejected.
+	MaxRecordingSize int64
+}
+
+func (a *SessionRecordingsAPI) UploadSessionRecording(ctx context.Context, req *agentproto.UploadSessionRecordingRequest) (*agentproto.UploadSessionRecordingResponse, error) {
+	recordingID, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, xerrors.Errorf("recording id from bytes: %w", err)
+	}
+	recordingType, err := sessionRecordingTypeFromProto(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetSequence() < 0 {
+		return nil, xerrors.Errorf("invalid sequence %d", req.GetSequence())
+	}
+
+	now := dbtime.Time(a.Clock.Now())
+	startedAt := now
+	if req.GetStartedAt() != nil {
+		startedAt = dbtime.Time(req.GetStartedAt().AsTime())
+	}
+	resp := &agentproto.UploadSessionRecordingResponse{}
+	err = a.Database.InTx(func(tx database.Store) error {
+		// The agent uploads the recordings of its sessions, but must not be
+		// able to read them.
+		//nolint:gocritic // The agent cannot read recordings.
+		ctx := dbauthz.AsSystemRestricted(ctx)
+
+		recording, err := tx.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			workspace, err := tx.GetWorkspaceByID(ctx, a.WorkspaceID)
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			template, err := tx.GetTemplateByID(ctx, workspace.TemplateID)
+			if err != nil {
+				return xerrors.Errorf("get template: %w", err)
+			}
+			if !template.SessionRecordingEnabled {
+				resp.Rejected = true
+				return nil
+			}
+
+			recording, err = tx.InsertWorkspaceSessionRecording(ctx, database.InsertWorkspaceSessionRecordingParams{
+				ID:             recordingID,
+				OrganizationID: a.OrganizationID,
+				WorkspaceID:    a.WorkspaceID,
+				OwnerID:        a.OwnerID,
+				AgentID:        a.AgentID,
+				Type:           recordingType,
+				Width:          req.GetWidth(),
+				Height:         req.GetHeight(),
+				StartedAt:      startedAt,
+				CreatedAt:      now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert recording: %w", err)
+			}
+		} else if err != nil {
+			return xerrors.Errorf("get recording: %w", err)
+		}
+		if recording.AgentID != a.AgentID {
+			return xerrors.Errorf("recording %s belongs to another agent", recordingID)
+		}
+
+		data := req.GetData()
+		if recording.Size+int64(len(data)) > a.MaxRecordingSize {
+			resp.Rejected = true
+			data = nil
+		}
+		if len(data) > 0 {
+			err = tx.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+				RecordingID: recordingID,
+				Sequence:    req.GetSequence(),
+				Data:        data,
+				CreatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert recording chunk: %w", err)
+			}
+		}
+
+		var endedAt sql.NullTime
+		if req.GetFinal() {
+			endedAt = sql.NullTime{Time: now, Valid: true}
+		}
+		if len(data) == 0 && !endedAt.Valid {
+			return nil
+		}
+		err = tx.UpdateWorkspaceSessionRecordingByID(ctx, database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        recordingID,
+			AddedSize: int64(len(data)),
+			EndedAt:   endedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("update recording: %w", err)
+		}
+		return nil
+	}, nil)
+	// The agent retries chunks it did not get a response for, a chunk that
+	// already exists has been stored completely.
+	if database.IsUniqueViolation(err, database.UniqueWorkspaceSessionRecordingChunksPkey) {
+		a.Log.Debug(ctx, "ignoring duplicate session recording chunk",
+			slog.F("recording_id", recordingID),
+			slog.F("sequence", req.GetSequence()),
+		)
+		return &agentproto.UploadSessionRecordingResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func sessionRecordingTypeFromProto(typ agentproto.Connection_Type) (database.SessionRecordingType, error) {
+	switch typ {
+	// Sessions of unknown SSH clients are reported without a type.
+	case agentproto.Connection_SSH, agentproto.Connection_TYPE_UNSPECIFIED:
+		return database.SessionRecordingTypeSSH, nil
+	case agentproto.Connection_VSCODE:
+		return database.SessionRecordingTypeVSCode, nil
+	case agentproto.Connection_JETBRAINS:
+		return database.SessionRecordingTypeJetBrains, nil
+	case agentproto.Connection_RECONNECTING_PTY:
+		return database.SessionRecordingTypeReconnectingPTY, nil
+	default:
+		return "", xerrors.Errorf("unknown session recording type %q", typ)
+	}
+}
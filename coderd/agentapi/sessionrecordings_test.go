@@ -0,0 +1,167 @@
+package agentapi_test
+
+import (
+	"bytes"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestUploadSessionRecording(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, recordingEnabled bool) (database.Store, *agentapi.SessionRecordingsAPI) {
+		db, _ := dbtestutil.NewDB(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		err := db.UpdateTemplateMetaByID(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.UpdateTemplateMetaByIDParams{
+			ID:                      template.ID,
+			UpdatedAt:               dbtime.Now(),
+			Name:                    template.Name,
+			GroupACL:                template.GroupACL,
+			MaxPortSharingLevel:     template.MaxPortSharingLevel,
+			SessionRecordingEnabled: recordingEnabled,
+		})
+		require.NoError(t, err)
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			TemplateID:     template.ID,
+			OwnerID:        user.ID,
+		})
+
+		logger := slogtest.Make(t, nil)
+		auth := rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry())
+		accessControlStore := &atomic.Pointer[dbauthz.AccessControlStore]{}
+		var acs dbauthz.AccessControlStore = dbauthz.AGPLTemplateAccessControlStore{}
+		accessControlStore.Store(&acs)
+
+		return db, &agentapi.SessionRecordingsAPI{
+			AgentID:          uuid.New(),
+			WorkspaceID:      workspace.ID,
+			OwnerID:          user.ID,
+			OrganizationID:   org.ID,
+			Log:              logger,
+			Clock:            quartz.NewMock(t),
+			Database:         dbauthz.New(db, auth, logger, accessControlStore),
+			MaxRecordingSize: 1024,
+		}
+	}
+
+	chunk := func(id uuid.UUID, sequence int32, data []byte, final bool) *agentproto.UploadSessionRecordingRequest {
+		return &agentproto.UploadSessionRecordingRequest{
+			Id:        id[:],
+			Type:      agentproto.Connection_SSH,
+			StartedAt: timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Width:     80,
+			Height:    24,
+			Sequence:  sequence,
+			Data:      data,
+			Final:     final,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, api := setup(t, true)
+		id := uuid.New()
+
+		resp, err := api.UploadSessionRecording(ctx, chunk(id, 0, []byte("header\n"), false))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+		resp, err = api.UploadSessionRecording(ctx, chunk(id, 1, []byte("output\n"), false))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+		// A retried chunk is ignored.
+		resp, err = api.UploadSessionRecording(ctx, chunk(id, 1, []byte("output\n"), false))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+		resp, err = api.UploadSessionRecording(ctx, chunk(id, 2, nil, true))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		recording, err := db.GetWorkspaceSessionRecordingByID(sysCtx, id)
+		require.NoError(t, err)
+		require.Equal(t, api.AgentID, recording.AgentID)
+		require.Equal(t, api.WorkspaceID, recording.WorkspaceID)
+		require.Equal(t, api.OwnerID, recording.OwnerID)
+		require.Equal(t, database.SessionRecordingTypeSSH, recording.Type)
+		require.EqualValues(t, 80, recording.Width)
+		require.EqualValues(t, 24, recording.Height)
+		require.EqualValues(t, 14, recording.Size)
+		require.True(t, recording.EndedAt.Valid)
+
+		chunks, err := db.GetWorkspaceSessionRecordingChunks(sysCtx, database.GetWorkspaceSessionRecordingChunksParams{
+			RecordingID:   id,
+			AfterSequence: -1,
+			LimitOpt:      10,
+		})
+		require.NoError(t, err)
+		require.Len(t, chunks, 2)
+		require.Equal(t, "header\n", string(chunks[0].Data))
+		require.Equal(t, "output\n", string(chunks[1].Data))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, api := setup(t, false)
+		id := uuid.New()
+
+		resp, err := api.UploadSessionRecording(ctx, chunk(id, 0, []byte("header\n"), false))
+		require.NoError(t, err)
+		require.True(t, resp.Rejected)
+
+		_, err = db.GetWorkspaceSessionRecordingByID(dbauthz.AsSystemRestricted(ctx), id)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("SizeLimit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, api := setup(t, true)
+		id := uuid.New()
+
+		resp, err := api.UploadSessionRecording(ctx, chunk(id, 0, bytes.Repeat([]byte("a"), 1000), false))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+		resp, err = api.UploadSessionRecording(ctx, chunk(id, 1, bytes.Repeat([]byte("b"), 100), false))
+		require.NoError(t, err)
+		require.True(t, resp.Rejected)
+		// The final chunk still marks the recording as ended.
+		resp, err = api.UploadSessionRecording(ctx, chunk(id, 2, nil, true))
+		require.NoError(t, err)
+		require.False(t, resp.Rejected)
+
+		recording, err := db.GetWorkspaceSessionRecordingByID(dbauthz.AsSystemRestricted(ctx), id)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, recording.Size)
+		require.True(t, recording.EndedAt.Valid)
+	})
+}
@@ -12259,6 +12259,9 @@ const docTemplate = `{
                 "enable": {
                     "type": "boolean"
                 },
+                "quic_address": {
+                    "type": "string"
+                },
                 "region_code": {
                     "type": "string"
                 },
@@ -18428,6 +18431,9 @@ const docTemplate = `{
                 "stun": {
                     "$ref": "#/definitions/healthsdk.STUNReport"
                 },
+                "uses_quic": {
+                    "type": "boolean"
+                },
                 "uses_websocket": {
                     "type": "boolean"
                 },
@@ -19328,6 +19334,13 @@ const docTemplate = `{
                 "derp_map": {
                     "$ref": "#/definitions/tailcfg.DERPMap"
                 },
+                "derp_quic_ports": {
+                    "description": "DERPQUICPorts are the QUIC ports of the DERP nodes in DERPMap, keyed\nby node name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
                 "disable_direct_connections": {
                     "type": "boolean"
                 },
@@ -19380,6 +19393,10 @@ const docTemplate = `{
                     "description": "DerpOnly indicates whether the proxy should only be included in the DERP\nmap and should not be used for serving apps.",
                     "type": "boolean"
                 },
+                "derp_quic_port": {
+                    "description": "DerpQUICPort is the UDP port the proxy accepts DERP over QUIC\nconnections on, or 0 if it doesn't.",
+                    "type": "integer"
+                },
                 "hostname": {
                     "description": "ReplicaHostname is the OS hostname of the machine that the proxy is running\non.  This is only used for tracking purposes in the replicas table.",
                     "type": "string"
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -56,10 +58,55 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
 
+	var scopePermissions, scopeAllowList []string
+	if scope == database.APIKeyScopeCustom {
+		var err error
+		scopePermissions, err = codersdk.ParseAPIKeyScopePermissions(createToken.ScopePermissions)
+		if err == nil && len(scopePermissions) == 0 {
+			err = xerrors.New("at least one permission is required")
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid scope permissions.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "scope_permissions",
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
+		scopeAllowList, err = codersdk.ParseAPIKeyScopeAllowList(createToken.ScopeAllowList)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid scope allow list.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "scope_allow_list",
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
+	} else if len(createToken.ScopePermissions) > 0 || len(createToken.ScopeAllowList) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Scope permissions and allow list require the %q scope.", codersdk.APIKeyScopeCustom),
+		})
+		return
+	}
+
+	// A token must not be able to create a token that escapes its own
+	// scope.
+	if err := apiKeyScopeWithin(httpmw.APIKey(r), scope, scopePermissions, scopeAllowList); err != nil {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "The requested scope is broader than the scope of your token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	tokenName := namesgenerator.GetRandomName(1)
 
 	if len(createToken.TokenName) != 0 {
@@ -72,6 +119,9 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
 		Scope:           scope,
 		TokenName:       tokenName,
+
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   scopeAllowList,
 	}
 
 	if createToken.Lifetime != 0 {
@@ -123,6 +173,15 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
+	// Session keys are not scoped, so only unscoped keys can create them.
+	if err := apiKeyScopeWithin(httpmw.APIKey(r), database.APIKeyScopeAll, nil, nil); err != nil {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Scoped tokens cannot create session keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	cookie, _, err := api.createAPIKey(ctx, apikey.CreateParams{
 		UserID:          user.ID,
 		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
@@ -389,3 +448,60 @@ func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*
 		HttpOnly: true,
 	}), &newkey, nil
 }
+
+// apiKeyScopeWithin returns an error if a key with the given scope would be
+// allowed anything that the parent key is not. Keys with the all scope can
+// create any key, other built-in scopes can only create keys of the same
+// scope, and custom scopes can only create custom keys with a subset of
+// their permissions that are limited to a subset of their allow list.
+func apiKeyScopeWithin(parent database.APIKey, scope database.APIKeyScope, permissions, allowList []string) error {
+	switch parent.Scope {
+	case database.APIKeyScopeAll:
+		return nil
+	case database.APIKeyScopeCustom:
+	default:
+		if scope != parent.Scope {
+			return xerrors.Errorf("tokens with the %q scope can only create tokens with the same scope", parent.Scope)
+		}
+		return nil
+	}
+
+	if scope != database.APIKeyScopeCustom {
+		return xerrors.Errorf("tokens with the %q scope can only create tokens with the %q scope", parent.Scope, database.APIKeyScopeCustom)
+	}
+	for _, perm := range permissions {
+		resource, _, _ := strings.Cut(perm, ":")
+		if !slices.Contains(parent.ScopePermissions, perm) &&
+			!slices.Contains(parent.ScopePermissions, resource+":"+policy.WildcardSymbol) &&
+			!slices.Contains(parent.ScopePermissions, policy.WildcardSymbol+":"+policy.WildcardSymbol) {
+			return xerrors.Errorf("permission %q is not in the scope of your token", perm)
+		}
+	}
+
+	// Allow lists only limit the types they list, so every type the parent
+	// limits must be limited to a subset of its IDs.
+	parentIDs := map[string][]string{}
+	for _, elem := range parent.ScopeAllowList {
+		resource, id, _ := strings.Cut(elem, ":")
+		parentIDs[resource] = append(parentIDs[resource], id)
+	}
+	ids := map[string][]string{}
+	for _, elem := range allowList {
+		resource, id, _ := strings.Cut(elem, ":")
+		ids[resource] = append(ids[resource], id)
+	}
+	for resource, allowed := range parentIDs {
+		if slices.Contains(allowed, policy.WildcardSymbol) {
+			continue
+		}
+		if len(ids[resource]) == 0 {
+			return xerrors.Errorf("the allow list must limit %q to the IDs allowed by your token", resource)
+		}
+		for _, id := range ids[resource] {
+			if !slices.Contains(allowed, id) {
+				return xerrors.Errorf("%s:%s is not in the allow list of your token", resource, id)
+			}
+		}
+	}
+	return nil
+}
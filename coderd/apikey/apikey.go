@@ -26,8 +26,12 @@ type CreateParams struct {
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
 	Scope           database.APIKeyScope
-	TokenName       string
-	RemoteAddr      string
+	// ScopePermissions and ScopeAllowList are only used with the custom
+	// scope. See codersdk.ParseAPIKeyScopePermission for the format.
+	ScopePermissions []string
+	ScopeAllowList   []string
+	TokenName        string
+	RemoteAddr       string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -68,6 +72,13 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		if len(params.ScopePermissions) > 0 || len(params.ScopeAllowList) > 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("scope permissions can only be set with the %q scope", database.APIKeyScopeCustom)
+		}
+	case database.APIKeyScopeCustom:
+		if len(params.ScopePermissions) == 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.New("custom scope requires at least one permission")
+		}
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
@@ -94,6 +105,9 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		LoginType:    params.LoginType,
 		Scope:        scope,
 		TokenName:    params.TokenName,
+		// The columns are NOT NULL, so never insert nil arrays.
+		ScopePermissions: append([]string{}, params.ScopePermissions...),
+		ScopeAllowList:   append([]string{}, params.ScopeAllowList...),
 	}, token, nil
 }
 
@@ -7,12 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
@@ -85,6 +87,237 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenCustomScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Permissions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeCustom,
+			ScopePermissions: []string{"user:read"},
+		})
+		require.NoError(t, err)
+
+		keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, codersdk.APIKeyScopeCustom, keys[0].Scope)
+		require.Equal(t, []string{"user:read"}, keys[0].ScopePermissions)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		_, err = scoped.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = scoped.Organization(ctx, owner.OrganizationID)
+		require.Error(t, err)
+
+		// The token cannot be used to escape its scope.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("TemplateAllowList", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		allowed := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).Do()
+		other := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).Do()
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeCustom,
+			ScopePermissions: []string{"workspace:read", "template:read", "user:read", "organization:read"},
+			ScopeAllowList:   []string{"template:" + allowed.Workspace.TemplateID.String()},
+		})
+		require.NoError(t, err)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		_, err = scoped.Workspace(ctx, allowed.Workspace.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, other.Workspace.ID)
+		require.Error(t, err)
+
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.Equal(t, allowed.Workspace.ID, workspaces.Workspaces[0].ID)
+	})
+
+	t.Run("WorkspaceBuilds", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeCustom,
+			ScopePermissions: []string{"workspace:read,update,start,stop", "template:read"},
+			ScopeAllowList:   []string{"template:" + template.ID.String()},
+		})
+		require.NoError(t, err)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		build, err := scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		build, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("CreateWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		other := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeCustom,
+			ScopePermissions: []string{"workspace:create,read,update,start", "template:read,use", "user:read", "organization:read"},
+			ScopeAllowList:   []string{"template:" + template.ID.String()},
+		})
+		require.NoError(t, err)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		// A workspace created with the token is allowed by its template, so
+		// the token can use it without being reissued.
+		workspace, err := scoped.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "scoped",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		_, err = scoped.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+
+		_, err = scoped.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: other.ID,
+			Name:       "other",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("CannotEscalate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		templateID := uuid.NewString()
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeCustom,
+			ScopePermissions: []string{"api_key:create", "user:read", "template:read"},
+			ScopeAllowList:   []string{"template:" + templateID},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		for name, req := range map[string]codersdk.CreateTokenRequest{
+			"All":                {Scope: codersdk.APIKeyScopeAll},
+			"Default":            {},
+			"ApplicationConnect": {Scope: codersdk.APIKeyScopeApplicationConnect},
+			"OtherPermission":    {Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"workspace:read"}},
+			"WildcardAction":     {Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"user:*"}, ScopeAllowList: []string{"template:" + templateID}},
+			"NoAllowList":        {Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"user:read"}},
+			"OtherTemplate":      {Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"user:read"}, ScopeAllowList: []string{"template:" + uuid.NewString()}},
+		} {
+			_, err := scoped.CreateToken(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, name)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode(), "%s: %v", name, err)
+		}
+
+		// Session keys are not scoped.
+		_, err = scoped.CreateAPIKey(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Equal and narrower tokens can be created.
+		for _, req := range []codersdk.CreateTokenRequest{
+			{
+				Scope:            codersdk.APIKeyScopeCustom,
+				ScopePermissions: []string{"api_key:create", "user:read", "template:read"},
+				ScopeAllowList:   []string{"template:" + templateID},
+			},
+			{
+				Scope:            codersdk.APIKeyScopeCustom,
+				ScopePermissions: []string{"template:read"},
+				ScopeAllowList:   []string{"template:" + templateID, "workspace:" + uuid.NewString()},
+			},
+		} {
+			_, err := scoped.CreateToken(ctx, codersdk.Me, req)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		for _, req := range []codersdk.CreateTokenRequest{
+			{Scope: codersdk.APIKeyScopeCustom},
+			{Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"workspace:fly"}},
+			{Scope: codersdk.APIKeyScopeCustom, ScopePermissions: []string{"workspace:read"}, ScopeAllowList: []string{"workspace:not-a-uuid"}},
+			{Scope: codersdk.APIKeyScopeAll, ScopePermissions: []string{"workspace:read"}},
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
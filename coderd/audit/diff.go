@@ -32,7 +32,10 @@ type Auditable interface {
 		idpsync.GroupSyncSettings |
 		idpsync.RoleSyncSettings |
 		database.WorkspaceAgent |
-		database.WorkspaceApp
+		database.WorkspaceApp |
+		database.WebAuthnCredential |
+		database.UserSecret |
+		database.WorkspaceSessionRecording
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
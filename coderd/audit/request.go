@@ -135,6 +135,12 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.WorkspaceApp:
 		return typed.Slug
+	case database.WebAuthnCredential:
+		return typed.Name
+	case database.UserSecret:
+		return typed.Name
+	case database.WorkspaceSessionRecording:
+		return typed.ID.String()
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceTarget", tgt))
 	}
@@ -198,6 +204,12 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.WorkspaceApp:
 		return typed.ID
+	case database.WebAuthnCredential:
+		return typed.ID
+	case database.UserSecret:
+		return typed.ID
+	case database.WorkspaceSessionRecording:
+		return typed.ID
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceID", tgt))
 	}
@@ -253,6 +265,12 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeWorkspaceAgent
 	case database.WorkspaceApp:
 		return database.ResourceTypeWorkspaceApp
+	case database.WebAuthnCredential:
+		return database.ResourceTypeWebAuthnCredential
+	case database.UserSecret:
+		return database.ResourceTypeUserSecret
+	case database.WorkspaceSessionRecording:
+		return database.ResourceTypeWorkspaceSessionRecording
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceType", typed))
 	}
@@ -310,6 +328,12 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 		return true
 	case database.WorkspaceApp:
 		return true
+	case database.WebAuthnCredential:
+		return false
+	case database.UserSecret:
+		return false
+	case database.WorkspaceSessionRecording:
+		return true
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceRequiresOrgID", tgt))
 	}
@@ -2,12 +2,19 @@ package audit_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/propagation"
 
+	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/tracing"
 )
 
 func TestBaggage(t *testing.T) {
@@ -31,3 +38,43 @@ func TestBaggage(t *testing.T) {
 
 	require.Equal(t, expected, got)
 }
+
+// TestInitRequestUser checks that requests are only audited when they are
+// attributed to a user.
+func TestInitRequestUser(t *testing.T) {
+	t.Parallel()
+
+	audited := func(t *testing.T, userID uuid.UUID) []database.AuditLog {
+		t.Helper()
+		auditor := audit.NewMock()
+		handler := httpmw.AttachRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &tracing.StatusWriter{ResponseWriter: w}
+			aReq, commitAudit := audit.InitRequest[database.AuditableGroup](rw, &audit.RequestParams{
+				Audit:          auditor,
+				Log:            slogtest.Make(t, nil),
+				Request:        r,
+				Action:         database.AuditActionCreate,
+				OrganizationID: uuid.New(),
+			})
+			defer commitAudit()
+			aReq.UserID = userID
+			aReq.New = database.AuditableGroup{Group: database.Group{ID: uuid.New(), Name: "group"}}
+			rw.WriteHeader(http.StatusCreated)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+		return auditor.AuditLogs()
+	}
+
+	t.Run("NoUser", func(t *testing.T) {
+		t.Parallel()
+		require.Empty(t, audited(t, uuid.Nil))
+	})
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		userID := uuid.New()
+		logs := audited(t, userID)
+		require.Len(t, logs, 1)
+		require.Equal(t, userID, logs[0].UserID)
+	})
+}
@@ -24,9 +24,11 @@ import (
 	"github.com/andybalholm/brotli"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/klauspost/compress/zstd"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quic-go/quic-go"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
@@ -79,11 +81,13 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/coderd/samlauth"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webauthnauth"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/codersdk"
@@ -161,6 +165,8 @@ type Options struct {
 	LanguageModels                 ai.LanguageModels
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	LDAPConfig                     *LDAPConfig
+	SAMLConfig                     *SAMLConfig
 	PrometheusRegistry             *prometheus.Registry
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
@@ -184,6 +190,9 @@ type Options struct {
 	TLSCertificates    []tls.Certificate
 	TailnetCoordinator tailnet.Coordinator
 	DERPServer         *derp.Server
+	// DERPQUICListener is optional, and accepts DERP over QUIC connections
+	// for DERPServer. The API closes it.
+	DERPQUICListener *quic.Listener
 	// BaseDERPMap is used as the base DERP map for all clients and agents.
 	// Proxies are added to this list.
 	BaseDERPMap                    *tailcfg.DERPMap
@@ -660,7 +669,8 @@ func New(options *Options) *API {
 					AccessURL: options.AccessURL,
 				},
 				DerpHealth: derphealth.ReportOptions{
-					DERPMap: api.DERPMap(),
+					DERPMap:       api.DERPMap(),
+					DERPQUICPorts: api.DERPQUICPorts(),
 				},
 				WorkspaceProxy: healthcheck.WorkspaceProxyReportOptions{
 					WorkspaceProxiesFetchUpdater: *(options.WorkspaceProxiesFetchUpdater).Load(),
@@ -687,12 +697,30 @@ func New(options *Options) *API {
 	if options.OIDCConfig != nil {
 		oidcAuthURLParams = options.OIDCConfig.AuthURLParams
 	}
+	if options.SAMLConfig != nil {
+		// The service provider endpoints are always served from the
+		// access URL.
+		samlauth.SetURLs(options.SAMLConfig.ServiceProvider, options.AccessURL)
+	}
+	api.WebAuthn, err = webauthnauth.New(options.AccessURL)
+	if err != nil {
+		api.Logger.Warn(ctx, "passkeys are disabled", slog.Error(err))
+	}
 
 	api.Auditor.Store(&options.Auditor)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
+	if options.DERPQUICListener != nil {
+		if options.DERPServer != nil {
+			api.derpQUICServer = tailnet.NewDERPQUICServer(options.Logger.Named("derpquic"), options.DERPServer, options.DERPQUICListener)
+			options.PrometheusRegistry.MustRegister(api.derpQUICServer)
+		} else {
+			_ = options.DERPQUICListener.Close()
+		}
+	}
 	dialer := &InmemTailnetDialer{
 		CoordPtr:            &api.TailnetCoordinator,
 		DERPFn:              api.DERPMap,
+		DERPQUICPortsFn:     api.DERPQUICPorts,
 		Logger:              options.Logger,
 		ClientID:            uuid.New(),
 		DatabaseHealthCheck: api.Database,
@@ -726,6 +754,7 @@ func New(options *Options) *API {
 		CoordPtr:                 &api.TailnetCoordinator,
 		DERPMapUpdateFrequency:   api.Options.DERPMapUpdateFrequency,
 		DERPMapFn:                api.DERPMap,
+		DERPQUICPortsFn:          api.DERPQUICPorts,
 		NetworkTelemetryHandler:  api.NetworkTelemetryBatcher.Handler,
 		ResumeTokenProvider:      api.Options.CoordinatorResumeTokenProvider,
 		WorkspaceUpdatesProvider: api.UpdatesProvider,
@@ -771,6 +800,12 @@ func New(options *Options) *API {
 		APIKeyEncryptionKeycache: options.AppEncryptionKeyCache,
 	}
 
+	// Password users that must enroll a second factor can only use the
+	// routes authenticated by apiKeyMiddlewareMFAEnrollment.
+	var mfaEnrollmentRequired func(context.Context, uuid.UUID) (bool, error)
+	if options.DeploymentValues.RequireMFA.Value() {
+		mfaEnrollmentRequired = api.mfaEnrollmentRequired
+	}
 	apiKeyMiddleware := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
 		DB:                            options.Database,
 		ActivateDormantUser:           ActivateDormantUser(options.Logger, &api.Auditor, options.Database),
@@ -780,6 +815,19 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		MFAEnrollmentRequired:         mfaEnrollmentRequired,
+	})
+	// Same as above but it allows sessions that have yet to enroll a
+	// required second factor.
+	apiKeyMiddlewareMFAEnrollment := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+		DB:                            options.Database,
+		ActivateDormantUser:           ActivateDormantUser(options.Logger, &api.Auditor, options.Database),
+		OAuth2Configs:                 oauthConfigs,
+		RedirectToLogin:               false,
+		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		Optional:                      false,
+		SessionTokenFunc:              nil, // Default behavior
+		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
 	})
 	// Same as above but it redirects to the login page.
 	apiKeyMiddlewareRedirect := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -790,6 +838,7 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		MFAEnrollmentRequired:         mfaEnrollmentRequired,
 	})
 	// Same as the first but it's optional.
 	apiKeyMiddlewareOptional := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -800,6 +849,7 @@ func New(options *Options) *API {
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		MFAEnrollmentRequired:         mfaEnrollmentRequired,
 	})
 
 	workspaceAgentInfo := httpmw.ExtractWorkspaceAgentAndLatestBuild(httpmw.ExtractWorkspaceAgentAndLatestBuildConfig{
@@ -912,29 +962,64 @@ func New(options *Options) *API {
 	// for an external application to use Coder as an OAuth2 provider, not for
 	// logging into Coder with an external OAuth2 provider.
 	r.Route("/oauth2", func(r chi.Router) {
-		r.Use(
-			api.oAuth2ProviderMiddleware,
-			// Fetch the app as system because in the /tokens route there will be no
-			// authenticated user.
-			httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database)),
-		)
-		r.Route("/authorize", func(r chi.Router) {
+		r.Use(api.oAuth2ProviderMiddleware)
+		// Fetch the app as system because in the /tokens route there will be no
+		// authenticated user.
+		extractApp := httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database))
+		r.Group(func(r chi.Router) {
+			r.Use(extractApp)
+			r.Route("/authorize", func(r chi.Router) {
+				r.Use(apiKeyMiddlewareRedirect)
+				r.Get("/", api.getOAuth2ProviderAppAuthorize())
+			})
+			// DELETE on /tokens is not part of the OAuth2 spec.  It is our own
+			// route used to revoke permissions from an application.  It is here for
+			// parity with POST on /tokens.
+			r.With(apiKeyMiddleware).Delete("/tokens", api.deleteOAuth2ProviderAppTokens())
+		})
+		r.Group(func(r chi.Router) {
+			// These routes are called by the app itself, so we cannot require an
+			// API key. They look up the app and compare its secret with pbkdf2,
+			// so they use the tight limit of password login.
+			//
+			// This value is intentionally increased during tests.
+			r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute), extractApp)
+			r.Post("/tokens", api.postOAuth2ProviderAppToken())
+			r.Post("/device", api.postOAuth2ProviderDeviceAuthorization())
+			r.Post("/introspect", api.postOAuth2ProviderTokenIntrospection())
+		})
+		// The user enters the code on a different device than the one that
+		// requested it, so the app comes from the code instead of the client ID.
+		r.Route("/device/verify", func(r chi.Router) {
 			r.Use(apiKeyMiddlewareRedirect)
-			r.Get("/", api.getOAuth2ProviderAppAuthorize())
+			r.Get("/", api.getOAuth2ProviderDeviceVerify())
 		})
-		r.Route("/tokens", func(r chi.Router) {
-			r.Group(func(r chi.Router) {
-				r.Use(apiKeyMiddleware)
-				// DELETE on /tokens is not part of the OAuth2 spec.  It is our own
-				// route used to revoke permissions from an application.  It is here for
-				// parity with POST on /tokens.
-				r.Delete("/", api.deleteOAuth2ProviderAppTokens())
-			})
-			// The POST /tokens endpoint will be called from an unauthorized client so
-			// we cannot require an API key.
-			r.Post("/", api.postOAuth2ProviderAppToken())
+		r.Route("/register", func(r chi.Router) {
+			// RFC 7591 sends the initial access token as a bearer token, which is
+			// how "authenticated" registration receives a session token.
+			r.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+				DB:                          options.Database,
+				OAuth2Configs:               oauthConfigs,
+				DisableSessionExpiryRefresh: options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+				Optional:                    true,
+				MFAEnrollmentRequired:       mfaEnrollmentRequired,
+				SessionTokenFunc: func(r *http.Request) string {
+					if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+						return token
+					}
+					return httpmw.APITokenFromRequest(r)
+				},
+			}))
+			// Open registration allows anyone to create apps, so limit it.
+			// Apps that never complete a grant are purged by dbpurge.
+			r.Use(httpmw.RateLimit(int(options.DeploymentValues.OAuth2.Provider.DynamicRegistrationRateLimit.Value()), time.Hour))
+			r.Post("/", api.postOAuth2ClientRegistration)
 		})
 	})
+	r.Route("/.well-known/oauth-authorization-server", func(r chi.Router) {
+		r.Use(api.oAuth2ProviderMiddleware)
+		r.Get("/", api.getOAuth2AuthorizationServerMetadata())
+	})
 
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
@@ -1163,6 +1248,7 @@ func New(options *Options) *API {
 			r.Get("/first", api.firstUser)
 			r.Post("/first", api.postFirstUser)
 			r.Get("/authmethods", api.userAuthMethods)
+			r.Get("/saml/metadata", api.samlMetadata)
 
 			r.Group(func(r chi.Router) {
 				// We use a tight limit for password login to protect against
@@ -1184,6 +1270,16 @@ func New(options *Options) *API {
 						r.Get("/callback", api.userOAuth2Github)
 					})
 				})
+				r.Post("/ldap/login", api.postLoginLDAP)
+				r.Get("/saml/login", api.userSAMLLogin)
+				r.Post("/saml/acs", api.userSAMLACS)
+				r.Get("/saml/slo", api.userSAMLSLO)
+				r.Post("/saml/slo", api.postUserSAMLSLO)
+				r.Route("/webauthn", func(r chi.Router) {
+					r.Post("/options", api.postWebAuthnLoginOptions)
+					r.Post("/login", api.postLoginWebAuthn)
+					r.Get("/cli-auth", api.webAuthnCLIAuth)
+				})
 				r.Route("/oidc/callback", func(r chi.Router) {
 					r.Use(
 						httpmw.ExtractOAuth2(options.OIDCConfig, options.HTTPClient, options.DeploymentValues.HTTPCookies, oidcAuthURLParams),
@@ -1191,13 +1287,40 @@ func New(options *Options) *API {
 					r.Get("/", api.userOIDC)
 				})
 			})
+			r.Group(func(r chi.Router) {
+				r.Use(
+					apiKeyMiddlewareMFAEnrollment,
+				)
+				r.Post("/logout", api.postLogout)
+				r.Route("/{user}/mfa", func(r chi.Router) {
+					r.Use(httpmw.ExtractUserParam(options.Database))
+					r.Get("/", api.userMFA)
+					r.Post("/totp", api.postUserTOTP)
+					r.Group(func(r chi.Router) {
+						// Codes are rate limited like passwords.
+						r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
+						r.Delete("/", api.deleteUserMFA)
+						r.Post("/totp/verify", api.postUserTOTPVerify)
+						r.Post("/recovery-codes", api.postUserMFARecoveryCodes)
+					})
+				})
+				r.Route("/{user}/webauthn", func(r chi.Router) {
+					r.Use(httpmw.ExtractUserParam(options.Database))
+					r.Post("/registration", api.postUserWebAuthnRegistration)
+					r.Route("/credentials", func(r chi.Router) {
+						r.Get("/", api.userWebAuthnCredentials)
+						r.Post("/", api.postUserWebAuthnCredential)
+						r.Delete("/{credential}", api.deleteUserWebAuthnCredential)
+					})
+				})
+			})
 			r.Group(func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
 				)
 				r.Post("/", api.postUser)
 				r.Get("/", api.users)
-				r.Post("/logout", api.postLogout)
+				r.Get("/saml/logout", api.userSAMLLogout)
 				// These routes query information about site wide roles.
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.AssignableSiteRoles)
@@ -1272,6 +1395,15 @@ func New(options *Options) *API {
 							r.Delete("/subscription", api.deleteUserWebpushSubscription)
 							r.Post("/test", api.postUserPushNotificationTest)
 						})
+						r.Route("/secrets", func(r chi.Router) {
+							r.Get("/", api.userSecrets)
+							r.Post("/", api.postUserSecret)
+							r.Route("/{secretname}", func(r chi.Router) {
+								r.Get("/", api.userSecretByName)
+								r.Patch("/", api.patchUserSecret)
+								r.Delete("/", api.deleteUserSecret)
+							})
+						})
 					})
 				})
 			})
@@ -1319,9 +1451,13 @@ func New(options *Options) *API {
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/resources-monitoring", api.workspaceAgentResourceMonitors)
+				r.Get("/exec", api.workspaceAgentExec)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/containers", api.workspaceAgentListContainers)
 				r.Post("/containers/devcontainers/container/{container}/recreate", api.workspaceAgentRecreateDevcontainer)
+				r.Get("/services", api.workspaceAgentServices)
+				r.Post("/services/{service}/restart", api.workspaceAgentRestartService)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
 				// PTY is part of workspaceAppServer.
@@ -1365,6 +1501,11 @@ func New(options *Options) *API {
 				r.Get("/timings", api.workspaceTimings)
 			})
 		})
+		r.Route("/workspacesessionrecordings", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.workspaceSessionRecordings)
+			r.Get("/{recording}/cast", api.workspaceSessionRecordingCast)
+		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1591,6 +1732,9 @@ type API struct {
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	// DERPMapper mutates the DERPMap to include workspace proxies.
 	DERPMapper atomic.Pointer[func(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap]
+	// DERPQUICPortsMapper adds the QUIC ports of workspace proxies in the
+	// DERP map.
+	DERPQUICPortsMapper atomic.Pointer[func(derpMap *tailcfg.DERPMap, ports tailnet.DERPQUICPorts) tailnet.DERPQUICPorts]
 	// AccessControlStore is a pointer to an atomic pointer since it is
 	// passed to dbauthz.
 	AccessControlStore  *atomic.Pointer[dbauthz.AccessControlStore]
@@ -1603,6 +1747,10 @@ type API struct {
 
 	HTTPAuth *HTTPAuthorizer
 
+	// WebAuthn is the relying party used to register and verify passkeys. It
+	// is nil if the access URL is not usable for WebAuthn.
+	WebAuthn *webauthn.WebAuthn
+
 	// APIHandler serves "/api/v2"
 	APIHandler chi.Router
 	// RootHandler serves "/"
@@ -1614,6 +1762,7 @@ type API struct {
 	WebsocketWaitMutex sync.Mutex
 	WebsocketWaitGroup sync.WaitGroup
 	derpCloseFunc      func()
+	derpQUICServer     *tailnet.DERPQUICServer
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
@@ -1673,6 +1822,9 @@ func (api *API) Close() error {
 	if api.derpCloseFunc != nil {
 		api.derpCloseFunc()
 	}
+	if api.derpQUICServer != nil {
+		_ = api.derpQUICServer.Close()
+	}
 	// The coordinator should be closed after the agent provider, and the DERP
 	// handler.
 	coordinator := api.TailnetCoordinator.Load()
@@ -1872,6 +2024,30 @@ func (api *API) DERPMap() *tailcfg.DERPMap {
 	return api.Options.BaseDERPMap
 }
 
+// DERPQUICPorts returns the QUIC ports of the DERP nodes in the map returned
+// by DERPMap.
+func (api *API) DERPQUICPorts() tailnet.DERPQUICPorts {
+	derpMap := api.DERPMap()
+	ports := tailnet.DERPQUICPorts{}
+	if api.derpQUICServer != nil && derpMap != nil {
+		for _, region := range derpMap.Regions {
+			if !region.EmbeddedRelay {
+				continue
+			}
+			for _, node := range region.Nodes {
+				if !node.STUNOnly {
+					ports[node.Name] = api.derpQUICServer.Port()
+				}
+			}
+		}
+	}
+	fn := api.DERPQUICPortsMapper.Load()
+	if fn != nil && derpMap != nil {
+		return (*fn)(derpMap, ports)
+	}
+	return ports
+}
+
 // nolint:revive
 func ReadExperiments(log slog.Logger, raw []string) codersdk.Experiments {
 	exps := make([]codersdk.Experiment, 0, len(raw))
@@ -0,0 +1,421 @@
+// Package samltest runs a fake SAML 2.0 identity provider for tests.
+package samltest
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// User is the identity returned by the fake identity provider.
+type User struct {
+	NameID string
+	// NameIDFormat defaults to the email address format.
+	NameIDFormat string
+	Attributes   map[string][]string
+}
+
+// FakeIDP is a SAML identity provider that authenticates every request as the
+// user passed to Login.
+type FakeIDP struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+	idp  *saml.IdentityProvider
+	srv  *httptest.Server
+
+	mu sync.Mutex
+	sp *saml.ServiceProvider
+	// user is returned for the in-progress login.
+	user *User
+	// logouts are the NameIDs of logout requests received from the service
+	// provider.
+	logouts []string
+}
+
+// NewFakeIDP starts an identity provider that is stopped when the test
+// completes.
+func NewFakeIDP(t testing.TB) *FakeIDP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "samltest"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	f := &FakeIDP{
+		key:  key,
+		cert: cert,
+	}
+	mux := http.NewServeMux()
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+
+	base, err := url.Parse(f.srv.URL)
+	require.NoError(t, err)
+	f.idp = &saml.IdentityProvider{
+		Key:                     key,
+		Certificate:             cert,
+		Logger:                  log.New(os.Stderr, "samltest: ", log.LstdFlags),
+		MetadataURL:             *base.JoinPath("/metadata"),
+		SSOURL:                  *base.JoinPath("/sso"),
+		LogoutURL:               *base.JoinPath("/slo"),
+		ServiceProviderProvider: f,
+		SessionProvider:         f,
+	}
+	mux.HandleFunc("/metadata", f.idp.ServeMetadata)
+	mux.HandleFunc("/sso", f.idp.ServeSSO)
+	mux.HandleFunc("/slo", f.serveSLO)
+	return f
+}
+
+// Metadata returns the identity provider metadata.
+func (f *FakeIDP) Metadata() *saml.EntityDescriptor {
+	return f.idp.Metadata()
+}
+
+// EntityID is the entity ID of the identity provider.
+func (f *FakeIDP) EntityID() string {
+	return f.idp.Metadata().EntityID
+}
+
+// SAMLConfig returns a config for a service provider that trusts the
+// identity provider. The service provider endpoints are set by coderd.
+func (f *FakeIDP) SAMLConfig(t testing.TB, modify func(cfg *coderd.SAMLConfig)) *coderd.SAMLConfig {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.Nil(t, f.sp, "a fake idp supports a single service provider")
+	f.sp = &saml.ServiceProvider{
+		IDPMetadata: f.idp.Metadata(),
+	}
+	cfg := &coderd.SAMLConfig{
+		ServiceProvider:   f.sp,
+		EmailAttribute:    "email",
+		UsernameAttribute: "username",
+		NameAttribute:     "name",
+		SignInText:        "SAML",
+	}
+	if modify != nil {
+		modify(cfg)
+	}
+	return cfg
+}
+
+// GetServiceProvider implements saml.ServiceProviderProvider.
+func (f *FakeIDP) GetServiceProvider(_ *http.Request, serviceProviderID string) (*saml.EntityDescriptor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sp == nil {
+		return nil, os.ErrNotExist
+	}
+	md := f.sp.Metadata()
+	if md.EntityID != serviceProviderID {
+		return nil, os.ErrNotExist
+	}
+	return md, nil
+}
+
+// GetSession implements saml.SessionProvider.
+func (f *FakeIDP) GetSession(w http.ResponseWriter, _ *http.Request, _ *saml.IdpAuthnRequest) *saml.Session {
+	f.mu.Lock()
+	user := f.user
+	f.mu.Unlock()
+	if user == nil {
+		http.Error(w, "no user is logging in", http.StatusUnauthorized)
+		return nil
+	}
+
+	format := user.NameIDFormat
+	if format == "" {
+		format = string(saml.EmailAddressNameIDFormat)
+	}
+	attrs := make([]saml.Attribute, 0, len(user.Attributes))
+	for name, values := range user.Attributes {
+		attr := saml.Attribute{
+			Name:       name,
+			NameFormat: "urn:oasis:names:tc:SAML:2.0:attrname-format:basic",
+		}
+		for _, v := range values {
+			attr.Values = append(attr.Values, saml.AttributeValue{
+				Type:  "xs:string",
+				Value: v,
+			})
+		}
+		attrs = append(attrs, attr)
+	}
+	return &saml.Session{
+		ID:               user.NameID,
+		CreateTime:       saml.TimeNow(),
+		ExpireTime:       saml.TimeNow().Add(time.Hour),
+		Index:            user.NameID,
+		NameID:           user.NameID,
+		NameIDFormat:     format,
+		CustomAttributes: attrs,
+	}
+}
+
+// Login logs in as the user and asserts the login succeeded.
+func (f *FakeIDP) Login(t testing.TB, client *codersdk.Client, user User) (*codersdk.Client, *http.Response) {
+	t.Helper()
+
+	userClient, resp := f.AttemptLogin(t, client, user)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "client failed to login")
+	require.NotNil(t, userClient, "no session token was set")
+	return userClient, resp
+}
+
+// AttemptLogin runs the SP initiated login flow with a new unauthenticated
+// client. The returned client is nil if no session token was set.
+func (f *FakeIDP) AttemptLogin(t testing.TB, client *codersdk.Client, user User) (*codersdk.Client, *http.Response) {
+	t.Helper()
+
+	// Only one login can be in progress at a time, since the identity
+	// provider authenticates every request as the same user.
+	f.mu.Lock()
+	f.user = &user
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.user = nil
+		f.mu.Unlock()
+	}()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	cli := &http.Client{
+		Jar:       jar,
+		Transport: client.HTTPClient.Transport,
+	}
+
+	// Follow the redirect to the identity provider, which responds with a
+	// form that posts the assertion to coderd.
+	loginURL := client.URL.JoinPath("/api/v2/users/saml/login")
+	res := do(t, cli, http.MethodGet, loginURL.String(), nil)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode, "identity provider response: %s", body)
+	action, values := parseForm(t, body)
+
+	res = do(t, cli, http.MethodPost, action, values)
+	var userClient *codersdk.Client
+	for _, cookie := range jar.Cookies(client.URL) {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			userClient = codersdk.New(client.URL)
+			userClient.SetSessionToken(cookie.Value)
+		}
+	}
+	return userClient, res
+}
+
+// LogoutRequestForm returns the form of a signed logout request for the
+// NameID using the HTTP-POST binding.
+func (f *FakeIDP) LogoutRequestForm(t testing.TB, nameID string) url.Values {
+	t.Helper()
+
+	f.mu.Lock()
+	sp := f.sp
+	f.mu.Unlock()
+	require.NotNil(t, sp, "no service provider is configured")
+
+	req := &saml.LogoutRequest{
+		ID:           fmt.Sprintf("id-%d", time.Now().UnixNano()),
+		Version:      "2.0",
+		IssueInstant: saml.TimeNow(),
+		Destination:  sp.SloURL.String(),
+		Issuer: &saml.Issuer{
+			Format: "urn:oasis:names:tc:SAML:2.0:nameid-format:entity",
+			Value:  f.EntityID(),
+		},
+		NameID: &saml.NameID{
+			Format: string(saml.EmailAddressNameIDFormat),
+			Value:  nameID,
+		},
+	}
+	req.Signature = f.sign(t, req.Element())
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.Element())
+	data, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	return url.Values{
+		"SAMLRequest": {base64.StdEncoding.EncodeToString(data)},
+	}
+}
+
+// Logouts returns the NameIDs of logout requests sent by the service
+// provider.
+func (f *FakeIDP) Logouts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.logouts...)
+}
+
+// serveSLO accepts logout requests from the service provider using the
+// HTTP-Redirect binding and responds with a successful logout response.
+func (f *FakeIDP) serveSLO(w http.ResponseWriter, r *http.Request) {
+	compressed, err := base64.StdEncoding.DecodeString(r.URL.Query().Get("SAMLRequest"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req saml.LogoutRequest
+	err = xml.Unmarshal(data, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	sp := f.sp
+	if req.NameID != nil {
+		f.logouts = append(f.logouts, req.NameID.Value)
+	}
+	f.mu.Unlock()
+
+	resp := &saml.LogoutResponse{
+		ID:           fmt.Sprintf("id-%d", time.Now().UnixNano()),
+		InResponseTo: req.ID,
+		Version:      "2.0",
+		IssueInstant: saml.TimeNow(),
+		Destination:  sp.SloURL.String(),
+		Issuer: &saml.Issuer{
+			Format: "urn:oasis:names:tc:SAML:2.0:nameid-format:entity",
+			Value:  f.EntityID(),
+		},
+		Status: saml.Status{
+			StatusCode: saml.StatusCode{Value: saml.StatusSuccess},
+		},
+	}
+	signed, err := f.signElement(resp.Element())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Signature = signed
+
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	doc := etree.NewDocument()
+	doc.SetRoot(resp.Element())
+	_, _ = doc.WriteTo(fw)
+	_ = fw.Close()
+
+	u := sp.SloURL
+	q := u.Query()
+	q.Set("SAMLResponse", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if rs := r.URL.Query().Get("RelayState"); rs != "" {
+		q.Set("RelayState", rs)
+	}
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func (f *FakeIDP) sign(t testing.TB, el *etree.Element) *etree.Element {
+	t.Helper()
+	sig, err := f.signElement(el)
+	require.NoError(t, err)
+	return sig
+}
+
+// signElement returns the enveloped signature of the element.
+func (f *FakeIDP) signElement(el *etree.Element) (*etree.Element, error) {
+	ctx := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(tls.Certificate{
+		Certificate: [][]byte{f.cert.Raw},
+		PrivateKey:  f.key,
+		Leaf:        f.cert,
+	}))
+	ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	err := ctx.SetSignatureMethod(dsig.RSASHA256SignatureMethod)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := ctx.SignEnveloped(el)
+	if err != nil {
+		return nil, err
+	}
+	return signed.Child[len(signed.Child)-1].(*etree.Element), nil
+}
+
+var (
+	formActionRe = regexp.MustCompile(`<form method="post" action="([^"]*)"`)
+	formInputRe  = regexp.MustCompile(`<input type="hidden" name="([^"]*)" value="([^"]*)" />`)
+)
+
+// parseForm returns the action and values of the auto-submitting form
+// written by the identity provider.
+func parseForm(t testing.TB, body []byte) (string, url.Values) {
+	t.Helper()
+
+	action := formActionRe.FindSubmatch(body)
+	require.NotNil(t, action, "no form in response: %s", body)
+	values := url.Values{}
+	for _, m := range formInputRe.FindAllSubmatch(body, -1) {
+		values.Set(html.UnescapeString(string(m[1])), html.UnescapeString(string(m[2])))
+	}
+	return html.UnescapeString(string(action[1])), values
+}
+
+func do(t testing.TB, cli *http.Client, method, target string, values url.Values) *http.Response {
+	t.Helper()
+
+	var body io.Reader
+	if values != nil {
+		body = strings.NewReader(values.Encode())
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, target, body)
+	require.NoError(t, err)
+	if values != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	res, err := cli.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = res.Body.Close()
+	})
+	return res
+}
@@ -309,6 +309,10 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 		comment.router == "/buildinfo" ||
 		comment.router == "/" ||
 		comment.router == "/users/login" ||
+		comment.router == "/users/ldap/login" ||
+		comment.router == "/users/webauthn/options" ||
+		comment.router == "/users/webauthn/login" ||
+		comment.router == "/users/webauthn/cli-auth" ||
 		comment.router == "/users/otp/request" ||
 		comment.router == "/users/otp/change-password" {
 		return // endpoints do not require authorization
@@ -0,0 +1,183 @@
+// Package webauthntest emulates a passkey authenticator for tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// Authenticator holds a single discoverable credential. The credential is
+// created by Register and used by Login.
+type Authenticator struct {
+	t      testing.TB
+	origin string
+
+	// UserVerified controls whether the authenticator reports that it
+	// verified the user, e.g. with a PIN. It defaults to true.
+	UserVerified bool
+
+	key        *ecdsa.PrivateKey
+	id         []byte
+	userHandle []byte
+	counter    uint32
+}
+
+// New returns an authenticator that responds to ceremonies on behalf of a
+// browser on origin, e.g. the URL of a codersdk.Client.
+func New(t testing.TB, origin string) *Authenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 32)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &Authenticator{
+		t:            t,
+		origin:       origin,
+		UserVerified: true,
+		key:          key,
+		id:           id,
+	}
+}
+
+// ID returns the ID of the credential.
+func (a *Authenticator) ID() []byte {
+	return a.id
+}
+
+// Register creates the credential for the options returned by
+// codersdk.Client.WebAuthnRegistrationOptions and returns the response to
+// pass to codersdk.Client.CreateWebAuthnCredential.
+func (a *Authenticator) Register(options codersdk.WebAuthnOptions) json.RawMessage {
+	a.t.Helper()
+
+	var publicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	require.NoError(a.t, json.Unmarshal(options.PublicKey, &publicKey))
+	userHandle, err := base64.RawURLEncoding.DecodeString(publicKey.User.ID)
+	require.NoError(a.t, err)
+	a.userHandle = userHandle
+
+	coseKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(a.t, err)
+
+	authData := a.authData(publicKey.RP.ID, flagAttestedData)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, coseKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	require.NoError(a.t, err)
+
+	return a.marshal(map[string]any{
+		"clientDataJSON":    encode(a.clientData("webauthn.create", publicKey.Challenge)),
+		"attestationObject": encode(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// Login signs the challenge of options returned by
+// codersdk.Client.WebAuthnLoginOptions and returns the response to pass to
+// codersdk.Client.LoginWithWebAuthn or as the second factor of a password
+// login.
+func (a *Authenticator) Login(options codersdk.WebAuthnOptions) json.RawMessage {
+	a.t.Helper()
+	require.NotNil(a.t, a.userHandle, "the credential must be registered first")
+
+	var publicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+	}
+	require.NoError(a.t, json.Unmarshal(options.PublicKey, &publicKey))
+
+	authData := a.authData(publicKey.RPID, 0)
+	clientData := a.clientData("webauthn.get", publicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+
+	return a.marshal(map[string]any{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+// authData returns the authenticator data up to and including the signature
+// counter, which is incremented on every use.
+func (a *Authenticator) authData(rpID string, flags byte) []byte {
+	a.counter++
+	flags |= flagUserPresent
+	if a.UserVerified {
+		flags |= flagUserVerified
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.counter)
+}
+
+func (a *Authenticator) clientData(typ string, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.origin,
+	})
+	require.NoError(a.t, err)
+	return clientData
+}
+
+func (a *Authenticator) marshal(response map[string]any) json.RawMessage {
+	credential, err := json.Marshal(map[string]any{
+		"id":       encode(a.id),
+		"rawId":    encode(a.id),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(a.t, err)
+	return credential
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -308,11 +308,12 @@ func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.Tem
 }
 
 func OAuth2ProviderApp(accessURL *url.URL, dbApp database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
-	return codersdk.OAuth2ProviderApp{
+	app := codersdk.OAuth2ProviderApp{
 		ID:          dbApp.ID,
 		Name:        dbApp.Name,
 		CallbackURL: dbApp.CallbackURL,
 		Icon:        dbApp.Icon,
+		ClientType:  codersdk.OAuth2ProviderAppClientType(dbApp.ClientType),
 		Endpoints: codersdk.OAuth2AppEndpoints{
 			Authorization: accessURL.ResolveReference(&url.URL{
 				Path: "/oauth2/authorize",
@@ -320,10 +321,15 @@ func OAuth2ProviderApp(accessURL *url.URL, dbApp database.OAuth2ProviderApp) cod
 			Token: accessURL.ResolveReference(&url.URL{
 				Path: "/oauth2/tokens",
 			}).String(),
-			// We do not currently support DeviceAuth.
-			DeviceAuth: "",
+			DeviceAuth: accessURL.ResolveReference(&url.URL{
+				Path: "/oauth2/device",
+			}).String(),
 		},
 	}
+	if dbApp.ServiceAccountID.Valid {
+		app.ServiceAccountID = &dbApp.ServiceAccountID.UUID
+	}
+	return app
 }
 
 func OAuth2ProviderApps(accessURL *url.URL, dbApps []database.OAuth2ProviderApp) []codersdk.OAuth2ProviderApp {
@@ -553,6 +559,37 @@ func WorkspaceAppStatus(status database.WorkspaceAppStatus) codersdk.WorkspaceAp
 	}
 }
 
+func WorkspaceAgentServices(services []database.WorkspaceAgentService) []codersdk.WorkspaceAgentService {
+	return List(services, WorkspaceAgentService)
+}
+
+func WorkspaceAgentService(service database.WorkspaceAgentService) codersdk.WorkspaceAgentService {
+	ret := codersdk.WorkspaceAgentService{
+		ID:            service.ID,
+		LogSourceID:   service.LogSourceID,
+		LogPath:       service.LogPath,
+		Name:          service.Name,
+		DisplayName:   service.DisplayName,
+		Icon:          service.Icon,
+		Command:       service.Command,
+		RestartPolicy: codersdk.WorkspaceAgentServiceRestartPolicy(service.RestartPolicy),
+		RestartDelay:  time.Duration(service.RestartDelaySeconds) * time.Second,
+		ReadinessProbe: codersdk.Healthcheck{
+			URL:       service.ReadinessURL,
+			Interval:  service.ReadinessIntervalSeconds,
+			Threshold: service.ReadinessThreshold,
+		},
+		Status:    codersdk.WorkspaceAgentServiceStatus(service.Status),
+		Health:    codersdk.WorkspaceAppHealth(service.Health),
+		Restarts:  service.Restarts,
+		UpdatedAt: service.UpdatedAt,
+	}
+	if service.ExitCode.Valid {
+		ret.ExitCode = &service.ExitCode.Int32
+	}
+	return ret
+}
+
 func ProvisionerDaemon(dbDaemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
 		ID:             dbDaemon.ID,
@@ -319,6 +319,48 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	subjectOAuth2ClientRegistration = rbac.Subject{
+		Type:         rbac.SubjectTypeOAuth2ClientRegistration,
+		FriendlyName: "OAuth2 Client Registration",
+		ID:           uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Identifier:  rbac.RoleIdentifier{Name: "oauth2clientregistration"},
+				DisplayName: "OAuth2 Client Registration",
+				Site: rbac.Permissions(map[string][]policy.Action{
+					rbac.ResourceOauth2App.Type:       {policy.ActionCreate},
+					rbac.ResourceOauth2AppSecret.Type: {policy.ActionCreate},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
+	// See the SCIM groups endpoints of the enterprise package.
+	subjectSCIMProvisioner = rbac.Subject{
+		Type:         rbac.SubjectTypeSCIMProvisioner,
+		FriendlyName: "SCIM Provisioner",
+		ID:           uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Identifier:  rbac.RoleIdentifier{Name: "scimprovisioner"},
+				DisplayName: "SCIM Provisioner",
+				Site: rbac.Permissions(map[string][]policy.Action{
+					rbac.ResourceGroup.Type:              {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceGroupMember.Type:        {policy.ActionRead},
+					rbac.ResourceOrganization.Type:       {policy.ActionRead},
+					rbac.ResourceOrganizationMember.Type: {policy.ActionRead},
+					rbac.ResourceUser.Type:               {policy.ActionRead},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSubAgentAPI = func(userID uuid.UUID, orgID uuid.UUID) rbac.Subject {
 		return rbac.Subject{
 			Type:         rbac.SubjectTypeSubAgentAPI,
@@ -350,26 +392,28 @@ var (
 				Identifier:  rbac.RoleIdentifier{Name: "system"},
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]policy.Action{
-					rbac.ResourceWildcard.Type:               {policy.ActionRead},
-					rbac.ResourceApiKey.Type:                 rbac.ResourceApiKey.AvailableActions(),
-					rbac.ResourceGroup.Type:                  {policy.ActionCreate, policy.ActionUpdate},
-					rbac.ResourceAssignRole.Type:             rbac.ResourceAssignRole.AvailableActions(),
-					rbac.ResourceAssignOrgRole.Type:          rbac.ResourceAssignOrgRole.AvailableActions(),
-					rbac.ResourceSystem.Type:                 {policy.WildcardSymbol},
-					rbac.ResourceOrganization.Type:           {policy.ActionCreate, policy.ActionRead},
-					rbac.ResourceOrganizationMember.Type:     {policy.ActionCreate, policy.ActionDelete, policy.ActionRead},
-					rbac.ResourceProvisionerDaemon.Type:      {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate},
-					rbac.ResourceUser.Type:                   rbac.ResourceUser.AvailableActions(),
-					rbac.ResourceWorkspaceDormant.Type:       {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStop},
-					rbac.ResourceWorkspace.Type:              {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop, policy.ActionSSH, policy.ActionCreateAgent, policy.ActionDeleteAgent},
-					rbac.ResourceWorkspaceProxy.Type:         {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceDeploymentConfig.Type:       {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceNotificationMessage.Type:    {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceNotificationPreference.Type: {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceNotificationTemplate.Type:   {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceCryptoKey.Type:              {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceFile.Type:                   {policy.ActionCreate, policy.ActionRead},
-					rbac.ResourceProvisionerJobs.Type:        {policy.ActionRead, policy.ActionUpdate, policy.ActionCreate},
+					rbac.ResourceWildcard.Type:                  {policy.ActionRead},
+					rbac.ResourceApiKey.Type:                    rbac.ResourceApiKey.AvailableActions(),
+					rbac.ResourceGroup.Type:                     {policy.ActionCreate, policy.ActionUpdate},
+					rbac.ResourceAssignRole.Type:                rbac.ResourceAssignRole.AvailableActions(),
+					rbac.ResourceAssignOrgRole.Type:             rbac.ResourceAssignOrgRole.AvailableActions(),
+					rbac.ResourceSystem.Type:                    {policy.WildcardSymbol},
+					rbac.ResourceOrganization.Type:              {policy.ActionCreate, policy.ActionRead},
+					rbac.ResourceOrganizationMember.Type:        {policy.ActionCreate, policy.ActionDelete, policy.ActionRead},
+					rbac.ResourceProvisionerDaemon.Type:         {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceUser.Type:                      rbac.ResourceUser.AvailableActions(),
+					rbac.ResourceWorkspaceDormant.Type:          {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStop},
+					rbac.ResourceWorkspace.Type:                 {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop, policy.ActionSSH, policy.ActionCreateAgent, policy.ActionDeleteAgent},
+					rbac.ResourceWorkspaceProxy.Type:            {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceDeploymentConfig.Type:          {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceNotificationMessage.Type:       {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceNotificationPreference.Type:    {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceNotificationTemplate.Type:      {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceCryptoKey.Type:                 {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceFile.Type:                      {policy.ActionCreate, policy.ActionRead},
+					rbac.ResourceProvisionerJobs.Type:           {policy.ActionRead, policy.ActionUpdate, policy.ActionCreate},
+					rbac.ResourceOauth2AppCodeToken.Type:        {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceWorkspaceSessionRecording.Type: {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -453,6 +497,18 @@ func AsNotifier(ctx context.Context) context.Context {
 	return As(ctx, subjectNotifier)
 }
 
+// AsOAuth2ClientRegistration returns a context with an actor that has
+// permissions required for OAuth2 clients to register themselves.
+func AsOAuth2ClientRegistration(ctx context.Context) context.Context {
+	return As(ctx, subjectOAuth2ClientRegistration)
+}
+
+// AsSCIMProvisioner returns a context with an actor that has permissions
+// required for an identity provider to manage groups over SCIM.
+func AsSCIMProvisioner(ctx context.Context) context.Context {
+	return As(ctx, subjectSCIMProvisioner)
+}
+
 // AsResourceMonitor returns a context with an actor that has permissions required for
 // updating resource monitors.
 func AsResourceMonitor(ctx context.Context) context.Context {
@@ -999,6 +1055,17 @@ func (q *querier) authorizeTemplateInsights(ctx context.Context, templateIDs []u
 	return nil
 }
 
+// authorizeUserMFAReset allows users to remove their own second factor, and
+// admins that can update a user to reset it for them.
+func (q *querier) authorizeUserMFAReset(ctx context.Context, userID uuid.UUID) error {
+	err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(userID))
+	if err != nil {
+		// Admins can reset the second factor of other users.
+		return q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceUserObject(userID))
+	}
+	return nil
+}
+
 // customRoleEscalationCheck checks to make sure the caller has every permission they are adding
 // to a custom role. This prevents permission escalation.
 func (q *querier) customRoleEscalationCheck(ctx context.Context, actor rbac.Subject, perm rbac.Permission, object rbac.Object) error {
@@ -1187,7 +1254,7 @@ func (q *querier) ClaimPrebuiltWorkspace(ctx context.Context, arg database.Claim
 		return empty, err
 	}
 
-	workspaceObject := rbac.ResourceWorkspace.WithOwner(arg.NewUserID.String()).InOrg(preset.OrganizationID)
+	workspaceObject := rbac.ResourceWorkspace.WithOwner(arg.NewUserID.String()).InOrg(preset.OrganizationID).InTemplate(preset.TemplateID.UUID)
 	err = q.authorizeContext(ctx, policy.ActionCreate, workspaceObject.RBACObject())
 	if err != nil {
 		return empty, err
@@ -1239,6 +1306,13 @@ func (q *querier) CountUnreadInboxNotificationsByUserID(ctx context.Context, use
 	return q.db.CountUnreadInboxNotificationsByUserID(ctx, userID)
 }
 
+func (q *querier) CountUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return 0, err
+	}
+	return q.db.CountUserMFARecoveryCodes(ctx, userID)
+}
+
 // TODO: Handle org scoped lookups
 func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	roleObject := rbac.ResourceAssignRole
@@ -1326,6 +1400,13 @@ func (q *querier) DeleteCustomRole(ctx context.Context, arg database.DeleteCusto
 	return q.db.DeleteCustomRole(ctx, arg)
 }
 
+func (q *querier) DeleteExpiredWebAuthnChallenges(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredWebAuthnChallenges(ctx, beforeTime)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -1386,6 +1467,13 @@ func (q *querier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context
 	return q.db.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
 }
 
+func (q *querier) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2AppCodeToken); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2AppSecret); err != nil {
 		return err
@@ -1429,6 +1517,13 @@ func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentStats(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWorkspaceSessionRecording); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceSessionRecordings(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
 	return deleteQ[database.OrganizationMember](q.log, q.auth, func(ctx context.Context, arg database.DeleteOrganizationMemberParams) (database.OrganizationMember, error) {
 		member, err := database.ExpectOne(q.OrganizationMembers(ctx, database.OrganizationMembersParams{
@@ -1496,6 +1591,57 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteUnusedDynamicOAuth2ProviderApps(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteUnusedDynamicOAuth2ProviderApps(ctx, beforeTime)
+}
+
+func (q *querier) DeleteUserMFARecoveryCode(ctx context.Context, arg database.DeleteUserMFARecoveryCodeParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteUserMFARecoveryCode(ctx, arg)
+}
+
+func (q *querier) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeUserMFAReset(ctx, userID); err != nil {
+		return err
+	}
+	return q.db.DeleteUserMFARecoveryCodes(ctx, userID)
+}
+
+func (q *querier) DeleteUserSecret(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetUserSecretByID, q.db.DeleteUserSecret)(ctx, id)
+}
+
+func (q *querier) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeUserMFAReset(ctx, userID); err != nil {
+		return err
+	}
+	return q.db.DeleteUserTOTP(ctx, userID)
+}
+
+func (q *querier) DeleteWebAuthnChallenge(ctx context.Context, challenge string) (database.WebAuthnChallenge, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return database.WebAuthnChallenge{}, err
+	}
+	return q.db.DeleteWebAuthnChallenge(ctx, challenge)
+}
+
+func (q *querier) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	credential, err := q.db.GetWebAuthnCredentialByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Admins can remove credentials of other users, like second factors.
+	if err := q.authorizeUserMFAReset(ctx, credential.UserID); err != nil {
+		return err
+	}
+	return q.db.DeleteWebAuthnCredential(ctx, id)
+}
+
 func (q *querier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return err
@@ -1603,6 +1749,20 @@ func (q *querier) FetchNewMessageMetadata(ctx context.Context, arg database.Fetc
 	return q.db.FetchNewMessageMetadata(ctx, arg)
 }
 
+func (q *querier) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.FetchResourceMonitorsByAgentID(ctx, agentID)
+}
+
 func (q *querier) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
 	if err != nil {
@@ -2084,6 +2244,14 @@ func (q *querier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPr
 	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByPrefix)(ctx, secretPrefix)
 }
 
+func (q *querier) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppDeviceCodeByPrefix)(ctx, secretPrefix)
+}
+
+func (q *querier) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppDeviceCodeByUserCode)(ctx, userCode)
+}
+
 func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -2102,6 +2270,22 @@ func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID
 	return q.db.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
 }
 
+func (q *querier) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	// The user ID is on the API key so that has to be fetched.
+	key, err := q.db.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOauth2AppCodeToken.WithOwner(key.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
 func (q *querier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
 	token, err := q.db.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
 	if err != nil {
@@ -2880,6 +3064,18 @@ func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uui
 	return q.db.GetUserNotificationPreferences(ctx, userID)
 }
 
+func (q *querier) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetUserSecretByID)(ctx, id)
+}
+
+func (q *querier) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetUserSecretByUserIDAndName)(ctx, arg)
+}
+
+func (q *querier) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetUserSecretsByUserID)(ctx, userID)
+}
+
 func (q *querier) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUser); err != nil {
 		return nil, err
@@ -2887,6 +3083,10 @@ func (q *querier) GetUserStatusCounts(ctx context.Context, arg database.GetUserS
 	return q.db.GetUserStatusCounts(ctx, arg)
 }
 
+func (q *querier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetUserTOTPByUserID)(ctx, userID)
+}
+
 func (q *querier) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
 	u, err := q.db.GetUserByID(ctx, userID)
 	if err != nil {
@@ -2945,6 +3145,21 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.WebAuthnCredential, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetWebAuthnCredentialByCredentialID)(ctx, credentialID)
+}
+
+func (q *querier) GetWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.WebAuthnCredential, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetWebAuthnCredentialByID)(ctx, id)
+}
+
+func (q *querier) GetWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebAuthnCredential, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebAuthnCredentialsByUserID(ctx, userID)
+}
+
 func (q *querier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWebpushSubscription.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -3063,6 +3278,13 @@ func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []
 	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
 }
 
+func (q *querier) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentServicesByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	return q.db.GetWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -3379,6 +3601,22 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceSessionRecordingByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	// Authorized by reading the recording.
+	if _, err := q.GetWorkspaceSessionRecordingByID(ctx, arg.RecordingID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingChunks(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetWorkspaceSessionRecordings)(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIDs []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3560,6 +3798,14 @@ func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.
 	return q.db.InsertOAuth2ProviderAppCode(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	// The code has no owner until a user approves it.
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceOauth2AppCodeToken); err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppDeviceCode(ctx, arg)
+}
+
 func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -3648,6 +3894,14 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return database.WorkspaceAgentResourceMonitor{}, err
+	}
+
+	return q.db.InsertResourceMonitor(ctx, arg)
+}
+
 func (q *querier) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3751,6 +4005,17 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertUserMFARecoveryCodes(ctx context.Context, arg database.InsertUserMFARecoveryCodesParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.InsertUserMFARecoveryCodes(ctx, arg)
+}
+
+func (q *querier) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceUserSecret.WithOwner(arg.UserID.String()), q.db.InsertUserSecret)(ctx, arg)
+}
+
 func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
 		return database.WorkspaceAgentVolumeResourceMonitor{}, err
@@ -3759,6 +4024,17 @@ func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.
 	return q.db.InsertVolumeResourceMonitor(ctx, arg)
 }
 
+func (q *querier) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWebAuthnChallenge(ctx, arg)
+}
+
+func (q *querier) InsertWebAuthnCredential(ctx context.Context, arg database.InsertWebAuthnCredentialParams) (database.WebAuthnCredential, error) {
+	return insertWithAction(q.log, q.auth, rbac.ResourceUserObject(arg.UserID), policy.ActionUpdatePersonal, q.db.InsertWebAuthnCredential)(ctx, arg)
+}
+
 func (q *querier) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return database.WebpushSubscription{}, err
@@ -3767,7 +4043,7 @@ func (q *querier) InsertWebpushSubscription(ctx context.Context, arg database.In
 }
 
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.WorkspaceTable, error) {
-	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
+	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID).InTemplate(arg.TemplateID)
 	tpl, err := q.GetTemplateByID(ctx, arg.TemplateID)
 	if err != nil {
 		return database.WorkspaceTable{}, xerrors.Errorf("verify template by id: %w", err)
@@ -3842,6 +4118,13 @@ func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.
 	return q.db.InsertWorkspaceAgentScripts(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return []database.WorkspaceAgentService{}, err
+	}
+	return q.db.InsertWorkspaceAgentServices(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3957,6 +4240,23 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	obj := rbac.ResourceWorkspaceSessionRecording.WithID(arg.ID).InOrg(arg.OrganizationID).WithOwner(arg.OwnerID.String())
+	return insert(q.log, q.auth, obj, q.db.InsertWorkspaceSessionRecording)(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) error {
+	// Appending a chunk updates the recording.
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, arg.RecordingID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, recording); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+}
+
 func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
 }
@@ -4139,6 +4439,13 @@ func (q *querier) UpdateCustomRole(ctx context.Context, arg database.UpdateCusto
 	return q.db.UpdateCustomRole(ctx, arg)
 }
 
+func (q *querier) UpdateDBCryptKeyWrappedKey(ctx context.Context, arg database.UpdateDBCryptKeyWrappedKeyParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateDBCryptKeyWrappedKey(ctx, arg)
+}
+
 func (q *querier) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		return q.db.GetExternalAuthLink(ctx, database.GetExternalAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -4240,6 +4547,21 @@ func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.
 	return q.db.UpdateOAuth2ProviderAppByID(ctx, arg)
 }
 
+func (q *querier) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOauth2AppCodeToken); err != nil {
+		return err
+	}
+	return q.db.UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	// Approving or denying a code makes the user its owner.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOauth2AppCodeToken.WithOwner(arg.UserID.UUID.String())); err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+	return q.db.UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -4387,6 +4709,14 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return err
+	}
+
+	return q.db.UpdateResourceMonitor(ctx, arg)
+}
+
 func (q *querier) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -4640,6 +4970,13 @@ func (q *querier) UpdateUserRoles(ctx context.Context, arg database.UpdateUserRo
 	return q.db.UpdateUserRoles(ctx, arg)
 }
 
+func (q *querier) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	fetch := func(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+		return q.db.GetUserSecretByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserSecret)(ctx, arg)
+}
+
 func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 		return q.db.GetUserByID(ctx, arg.ID)
@@ -4647,6 +4984,20 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return 0, err
+	}
+	return q.db.UpdateUserTOTPLastUsedStep(ctx, arg)
+}
+
+func (q *querier) UpdateUserTOTPSecret(ctx context.Context, arg database.UpdateUserTOTPSecretParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.UpdateUserTOTPSecret(ctx, arg)
+}
+
 func (q *querier) UpdateUserTerminalFont(ctx context.Context, arg database.UpdateUserTerminalFontParams) (database.UserConfig, error) {
 	u, err := q.db.GetUserByID(ctx, arg.UserID)
 	if err != nil {
@@ -4677,6 +5028,13 @@ func (q *querier) UpdateVolumeResourceMonitor(ctx context.Context, arg database.
 	return q.db.UpdateVolumeResourceMonitor(ctx, arg)
 }
 
+func (q *querier) UpdateWebAuthnCredentialUsage(ctx context.Context, arg database.UpdateWebAuthnCredentialUsageParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWebAuthnCredentialUsageParams) (database.WebAuthnCredential, error) {
+		return q.db.GetWebAuthnCredentialByID(ctx, arg.ID)
+	}
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, fetch, q.db.UpdateWebAuthnCredentialUsage)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -4740,6 +5098,19 @@ func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentServiceStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionUpdate, workspace.RBACObject())
+	if err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceAgentServiceStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	agent, err := q.db.GetWorkspaceAgentByID(ctx, arg.ID)
 	if err != nil {
@@ -4874,6 +5245,13 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) (database.WorkspaceSessionRecording, error) {
+		return q.db.GetWorkspaceSessionRecordingByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceSessionRecordingByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -5061,6 +5439,10 @@ func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return q.db.UpsertTemplateUsageStats(ctx)
 }
 
+func (q *querier) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	return insertWithAction(q.log, q.auth, rbac.ResourceUserObject(arg.UserID), policy.ActionUpdatePersonal, q.db.UpsertUserTOTP)(ctx, arg)
+}
+
 func (q *querier) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -1704,6 +1704,121 @@ func (s *MethodTestSuite) TestUser() {
 			UpdatedAt: key.UpdatedAt,
 		}).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns(key)
 	}))
+	s.Run("GetUserTOTPByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		totp, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+			UserID:    u.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(totp)
+	}))
+	s.Run("UpsertUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserTOTPParams{
+			UserID:    u.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateUserTOTPLastUsedStep", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+			UserID:    u.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateUserTOTPLastUsedStepParams{
+			UserID:       u.ID,
+			LastUsedStep: 1,
+			UpdatedAt:    dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns(int64(1))
+	}))
+	s.Run("UpdateUserTOTPSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+			UserID:    u.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateUserTOTPSecretParams{
+			UserID: u.ID,
+			Secret: "KRSXG5CTMVRXEZLU",
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("DeleteUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("InsertUserMFARecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserMFARecoveryCodesParams{
+			UserID:      u.ID,
+			HashedCodes: [][]byte{{1}},
+			CreatedAt:   dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("CountUserMFARecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(int64(0))
+	}))
+	s.Run("DeleteUserMFARecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteUserMFARecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: []byte{1},
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns(int64(0))
+	}))
+	s.Run("DeleteUserMFARecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("GetWebAuthnCredentialsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.WebAuthnCredential(s.T(), db, database.WebAuthnCredential{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns([]database.WebAuthnCredential{credential})
+	}))
+	s.Run("GetWebAuthnCredentialByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.WebAuthnCredential(s.T(), db, database.WebAuthnCredential{UserID: u.ID})
+		check.Args(credential.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(credential)
+	}))
+	s.Run("GetWebAuthnCredentialByCredentialID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.WebAuthnCredential(s.T(), db, database.WebAuthnCredential{UserID: u.ID})
+		check.Args(credential.CredentialID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(credential)
+	}))
+	s.Run("InsertWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertWebAuthnCredentialParams{
+			ID:              uuid.New(),
+			UserID:          u.ID,
+			Name:            "key",
+			CredentialID:    []byte{1},
+			PublicKey:       []byte{1},
+			AttestationType: "none",
+			Transports:      []string{},
+			AAGUID:          make([]byte, 16),
+			CreatedAt:       dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateWebAuthnCredentialUsage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.WebAuthnCredential(s.T(), db, database.WebAuthnCredential{UserID: u.ID})
+		check.Args(database.UpdateWebAuthnCredentialUsageParams{
+			ID:         credential.ID,
+			SignCount:  1,
+			LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("DeleteWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.WebAuthnCredential(s.T(), db, database.WebAuthnCredential{UserID: u.ID})
+		check.Args(credential.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
 	s.Run("GetExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
 		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
 		check.Args(database.GetExternalAuthLinkParams{
@@ -2703,7 +2818,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			OrganizationID:   o.ID,
 			AutomaticUpdates: database.AutomaticUpdatesNever,
 			TemplateID:       tpl.ID,
-		}).Asserts(tpl, policy.ActionRead, tpl, policy.ActionUse, rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), policy.ActionCreate)
+		}).Asserts(tpl, policy.ActionRead, tpl, policy.ActionUse, rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID).InTemplate(tpl.ID), policy.ActionCreate)
 	}))
 	s.Run("Start/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -2981,6 +3096,41 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Health: database.WorkspaceAppHealthDisabled,
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentServiceStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		svc := dbgen.WorkspaceAgentService(s.T(), db, database.WorkspaceAgentService{WorkspaceAgentID: agt.ID})
+		check.Args(database.UpdateWorkspaceAgentServiceStatusByIDParams{
+			ID:               svc.ID,
+			WorkspaceAgentID: agt.ID,
+			Status:           database.WorkspaceAgentServiceStatusRunning,
+			Health:           database.WorkspaceAppHealthDisabled,
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAutostart", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3734,6 +3884,20 @@ func (s *MethodTestSuite) TestDBCrypt() {
 			Asserts(rbac.ResourceSystem, policy.ActionUpdate).
 			Returns()
 	}))
+	s.Run("UpdateDBCryptKeyWrappedKey", s.Subtest(func(db database.Store, check *expects) {
+		err := db.InsertDBCryptKey(context.Background(), database.InsertDBCryptKeyParams{
+			Number:          1,
+			ActiveKeyDigest: "rewrap me",
+		})
+		s.NoError(err)
+		check.Args(database.UpdateDBCryptKeyWrappedKeyParams{
+			Number:        1,
+			WrappedKey:    "wrapped",
+			WrappingKeyID: "file:1234567",
+		}).
+			Asserts(rbac.ResourceSystem, policy.ActionUpdate).
+			Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestCryptoKeys() {
@@ -4211,9 +4375,32 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Transition: database.WorkspaceTransitionStart,
 		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("DeleteUnusedDynamicOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("DeleteOldWorkspaceAgentLogs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("InsertWebAuthnChallenge", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebAuthnChallengeParams{
+			Challenge: "challenge",
+			Session:   json.RawMessage("{}"),
+			ExpiresAt: dbtime.Now().Add(time.Minute),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("DeleteWebAuthnChallenge", s.Subtest(func(db database.Store, check *expects) {
+		challenge := database.InsertWebAuthnChallengeParams{
+			Challenge: "challenge",
+			Session:   json.RawMessage("{}"),
+			ExpiresAt: dbtime.Now().Add(time.Minute),
+		}
+		err := db.InsertWebAuthnChallenge(context.Background(), challenge)
+		require.NoError(s.T(), err)
+		check.Args(challenge.Challenge).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteExpiredWebAuthnChallenges", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("InsertWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentStatsParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate).Errors(errMatchAny)
 	}))
@@ -4244,6 +4431,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("InsertWorkspaceAgentScripts", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentScriptsParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentServices", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentServicesParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		check.Args(database.InsertWorkspaceAgentMetadataParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
@@ -4379,6 +4569,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentServicesByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceAgentLogSourcesByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
@@ -4946,7 +5139,7 @@ func (s *MethodTestSuite) TestPrebuilds() {
 			NewName:   "",
 			PresetID:  preset.ID,
 		}).Asserts(
-			rbac.ResourceWorkspace.WithOwner(user.ID.String()).InOrg(org.ID), policy.ActionCreate,
+			rbac.ResourceWorkspace.WithOwner(user.ID.String()).InOrg(org.ID).InTemplate(template.ID), policy.ActionCreate,
 			template, policy.ActionRead,
 			template, policy.ActionUse,
 		).ErrorsWithInMemDB(dbmem.ErrUnimplemented).
@@ -5079,9 +5272,10 @@ func (s *MethodTestSuite) TestOAuth2ProviderApps() {
 		})
 		for i := 0; i < 5; i++ {
 			_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    key.ID,
 				HashPrefix:  []byte(fmt.Sprintf("%d", i)),
+				AppID:       app.ID,
 			})
 		}
 		check.Args(user.ID).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionRead).Returns([]database.GetOAuth2ProviderAppsByUserIDRow{
@@ -5093,13 +5287,16 @@ func (s *MethodTestSuite) TestOAuth2ProviderApps() {
 					Name:        app.Name,
 					CreatedAt:   createdAt,
 					UpdatedAt:   createdAt,
+					ClientType:  app.ClientType,
 				},
 				TokenCount: 5,
 			},
 		})
 	}))
 	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.InsertOAuth2ProviderAppParams{}).Asserts(rbac.ResourceOauth2App, policy.ActionCreate)
+		check.Args(database.InsertOAuth2ProviderAppParams{
+			ClientType: database.OAuth2ProviderAppClientTypeConfidential,
+		}).Asserts(rbac.ResourceOauth2App, policy.ActionCreate)
 	}))
 	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
@@ -5111,6 +5308,7 @@ func (s *MethodTestSuite) TestOAuth2ProviderApps() {
 			Name:        app.Name,
 			CallbackURL: app.CallbackURL,
 			UpdatedAt:   app.UpdatedAt,
+			ClientType:  app.ClientType,
 		}).Asserts(rbac.ResourceOauth2App, policy.ActionUpdate).Returns(app)
 	}))
 	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
@@ -5247,8 +5445,9 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 			AppID: app.ID,
 		})
 		check.Args(database.InsertOAuth2ProviderAppTokenParams{
-			AppSecretID: secret.ID,
+			AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 			APIKeyID:    key.ID,
+			AppID:       app.ID,
 		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionCreate)
 	}))
 	s.Run("GetOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
@@ -5261,11 +5460,26 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 			AppID: app.ID,
 		})
 		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-			AppSecretID: secret.ID,
+			AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 			APIKeyID:    key.ID,
+			AppID:       app.ID,
 		})
 		check.Args(token.HashPrefix).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionRead)
 	}))
+	s.Run("GetOAuth2ProviderAppTokenByAPIKeyID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{
+			ClientType: database.OAuth2ProviderAppClientTypePublic,
+		})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			APIKeyID: key.ID,
+			AppID:    app.ID,
+		})
+		check.Args(key.ID).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionRead).Returns(token)
+	}))
 	s.Run("DeleteOAuth2ProviderAppTokensByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		user := dbgen.User(s.T(), db, database.User{})
@@ -5278,9 +5492,10 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 		})
 		for i := 0; i < 5; i++ {
 			_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    key.ID,
 				HashPrefix:  []byte(fmt.Sprintf("%d", i)),
+				AppID:       app.ID,
 			})
 		}
 		check.Args(database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
@@ -5290,6 +5505,61 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 	}))
 }
 
+func (s *MethodTestSuite) TestOAuth2ProviderAppDeviceCodes() {
+	s.Run("InsertOAuth2ProviderAppDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppDeviceCodeParams{
+			AppID:    app.ID,
+			UserCode: "BCDFGHJK",
+		}).Asserts(rbac.ResourceOauth2AppCodeToken, policy.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppDeviceCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.SecretPrefix).Asserts(code, policy.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppDeviceCodeByUserCode", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.UserCode).Asserts(code, policy.ActionRead).Returns(code)
+	}))
+	s.Run("UpdateOAuth2ProviderAppDeviceCodeStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		code.Status = database.OAuth2ProviderAppDeviceCodeStatusApproved
+		code.UserID = uuid.NullUUID{UUID: user.ID, Valid: true}
+		check.Args(database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams{
+			ID:     code.ID,
+			Status: code.Status,
+			UserID: code.UserID,
+		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionUpdate).Returns(code)
+	}))
+	s.Run("UpdateOAuth2ProviderAppDeviceCodePolledAtByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams{
+			ID:           code.ID,
+			LastPolledAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceOauth2AppCodeToken, policy.ActionUpdate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppDeviceCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.ID).Asserts(rbac.ResourceOauth2AppCodeToken, policy.ActionDelete)
+	}))
+}
+
 func (s *MethodTestSuite) TestResourcesMonitor() {
 	createAgent := func(t *testing.T, db database.Store) (database.WorkspaceAgent, database.WorkspaceTable) {
 		t.Helper()
@@ -5342,6 +5612,16 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionCreate)
 	}))
 
+	s.Run("InsertResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
+		agt, _ := createAgent(s.T(), db)
+
+		check.Args(database.InsertResourceMonitorParams{
+			AgentID: agt.ID,
+			Type:    database.WorkspaceAgentResourceMonitorTypeCPU,
+			State:   database.WorkspaceAgentMonitorStateOK,
+		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionCreate)
+	}))
+
 	s.Run("UpdateMemoryResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
 		agt, _ := createAgent(s.T(), db)
 
@@ -5360,6 +5640,16 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
 	}))
 
+	s.Run("UpdateResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
+		agt, _ := createAgent(s.T(), db)
+
+		check.Args(database.UpdateResourceMonitorParams{
+			AgentID: agt.ID,
+			Type:    database.WorkspaceAgentResourceMonitorTypeCPU,
+			State:   database.WorkspaceAgentMonitorStateOK,
+		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
+	}))
+
 	s.Run("FetchMemoryResourceMonitorsUpdatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionRead)
 	}))
@@ -5400,6 +5690,24 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 
 		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(monitors)
 	}))
+
+	s.Run("FetchResourceMonitorsByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		agt, w := createAgent(s.T(), db)
+
+		dbgen.WorkspaceAgentResourceMonitor(s.T(), db, database.WorkspaceAgentResourceMonitor{
+			AgentID:   agt.ID,
+			Type:      database.WorkspaceAgentResourceMonitorTypeInodes,
+			Path:      "/var/lib",
+			Enabled:   true,
+			Threshold: 80,
+			CreatedAt: dbtime.Now(),
+		})
+
+		monitors, err := db.FetchResourceMonitorsByAgentID(context.Background(), agt.ID)
+		require.NoError(s.T(), err)
+
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(monitors)
+	}))
 }
 
 func (s *MethodTestSuite) TestResourcesProvisionerdserver() {
@@ -5517,3 +5825,132 @@ func (s *MethodTestSuite) TestChat() {
 		}).Asserts(c, policy.ActionUpdate)
 	}))
 }
+
+func (s *MethodTestSuite) TestUserSecrets() {
+	s.Run("DeleteUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(secret.ID).Asserts(secret, policy.ActionDelete)
+	}))
+
+	s.Run("GetUserSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(secret.ID).Asserts(secret, policy.ActionRead).Returns(secret)
+	}))
+
+	s.Run("GetUserSecretByUserIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(database.GetUserSecretByUserIDAndNameParams{
+			UserID: u.ID,
+			Name:   secret.Name,
+		}).Asserts(secret, policy.ActionRead).Returns(secret)
+	}))
+
+	s.Run("GetUserSecretsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		a := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID, Name: "a"})
+		b := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID, Name: "b"})
+		_ = dbgen.UserSecret(s.T(), db, database.UserSecret{}) // other user's secret
+		check.Args(u.ID).Asserts(a, policy.ActionRead, b, policy.ActionRead).Returns([]database.UserSecret{a, b})
+	}))
+
+	s.Run("InsertUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserSecretParams{
+			ID:      uuid.New(),
+			UserID:  u.ID,
+			Name:    "token",
+			Value:   "value",
+			EnvName: "TOKEN",
+		}).Asserts(rbac.ResourceUserSecret.WithOwner(u.ID.String()), policy.ActionCreate)
+	}))
+
+	s.Run("UpdateUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(database.UpdateUserSecretParams{
+			ID:        secret.ID,
+			Value:     "new value",
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(secret, policy.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceSessionRecordings() {
+	createRecording := func(t *testing.T, db database.Store) database.WorkspaceSessionRecording {
+		o := dbgen.Organization(t, db, database.Organization{})
+		u := dbgen.User(t, db, database.User{})
+		tpl := dbgen.Template(t, db, database.Template{OrganizationID: o.ID, CreatedBy: u.ID})
+		ws := dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: o.ID, OwnerID: u.ID, TemplateID: tpl.ID})
+		return dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+			OrganizationID: o.ID,
+			WorkspaceID:    ws.ID,
+			OwnerID:        u.ID,
+		})
+	}
+
+	s.Run("DeleteOldWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceWorkspaceSessionRecording, policy.ActionDelete)
+	}))
+
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		recording := createRecording(s.T(), db)
+		check.Args(recording.ID).Asserts(recording, policy.ActionRead).Returns(recording)
+	}))
+
+	s.Run("GetWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		recording := createRecording(s.T(), db)
+		check.Args(database.GetWorkspaceSessionRecordingChunksParams{
+			RecordingID:   recording.ID,
+			AfterSequence: -1,
+			LimitOpt:      10,
+		}).Asserts(recording, policy.ActionRead)
+	}))
+
+	s.Run("GetWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		recording := createRecording(s.T(), db)
+		check.Args(database.GetWorkspaceSessionRecordingsParams{
+			WorkspaceID: recording.WorkspaceID,
+		}).Asserts(recording, policy.ActionRead)
+	}))
+
+	s.Run("InsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		tpl := dbgen.Template(s.T(), db, database.Template{OrganizationID: o.ID, CreatedBy: u.ID})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{OrganizationID: o.ID, OwnerID: u.ID, TemplateID: tpl.ID})
+		id := uuid.New()
+		check.Args(database.InsertWorkspaceSessionRecordingParams{
+			ID:             id,
+			OrganizationID: o.ID,
+			WorkspaceID:    ws.ID,
+			OwnerID:        u.ID,
+			AgentID:        uuid.New(),
+			Type:           database.SessionRecordingTypeSSH,
+			Width:          80,
+			Height:         24,
+			StartedAt:      dbtime.Now(),
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceWorkspaceSessionRecording.WithID(id).InOrg(o.ID).WithOwner(u.ID.String()), policy.ActionCreate)
+	}))
+
+	s.Run("InsertWorkspaceSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		recording := createRecording(s.T(), db)
+		check.Args(database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			Sequence:    0,
+			Data:        []byte("data"),
+			CreatedAt:   dbtime.Now(),
+		}).Asserts(recording, policy.ActionUpdate)
+	}))
+
+	s.Run("UpdateWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		recording := createRecording(s.T(), db)
+		check.Args(database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        recording.ID,
+			AddedSize: 4,
+		}).Asserts(recording, policy.ActionUpdate)
+	}))
+}
@@ -160,3 +160,57 @@ func TestGroupsAuth(t *testing.T) {
 		})
 	}
 }
+
+// TestSCIMProvisionerGroups checks that only the SCIM provisioner can delete
+// groups, the system subject that is used by other callers cannot.
+func TestSCIMProvisionerGroups(t *testing.T) {
+	t.Parallel()
+
+	authz := rbac.NewAuthorizer(prometheus.NewRegistry())
+	store, _ := dbtestutil.NewDB(t)
+	db := dbauthz.New(store, authz, slogtest.Make(t, &slogtest.Options{
+		IgnoreErrors: true,
+	}), coderdtest.AccessControlStorePointer())
+
+	org := dbgen.Organization(t, store, database.Organization{})
+	user := dbgen.User(t, store, database.User{})
+	dbgen.OrganizationMember(t, store, database.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+	})
+	group := dbgen.Group(t, store, database.Group{
+		OrganizationID: org.ID,
+	})
+
+	//nolint:gocritic // Testing the system subject.
+	sysCtx := dbauthz.AsSystemRestricted(context.Background())
+	err := db.DeleteGroupByID(sysCtx, group.ID)
+	require.True(t, dbauthz.IsNotAuthorizedError(err), "system must not delete groups: %v", err)
+
+	scimCtx := dbauthz.AsSCIMProvisioner(context.Background())
+	err = db.InsertGroupMember(scimCtx, database.InsertGroupMemberParams{
+		UserID:  user.ID,
+		GroupID: group.ID,
+	})
+	require.NoError(t, err)
+	members, err := db.GetGroupMembersByGroupID(scimCtx, database.GetGroupMembersByGroupIDParams{
+		GroupID: group.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	// The SCIM provisioner only manages groups.
+	err = db.DeleteOrganizationMember(scimCtx, database.DeleteOrganizationMemberParams{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+	})
+	require.True(t, dbauthz.IsNotAuthorizedError(err), "scim must not delete organization members: %v", err)
+	_, err = db.UpdateUserStatus(scimCtx, database.UpdateUserStatusParams{
+		ID:     user.ID,
+		Status: database.UserStatusSuspended,
+	})
+	require.True(t, dbauthz.IsNotAuthorizedError(err), "scim must not update users: %v", err)
+
+	err = db.DeleteGroupByID(scimCtx, group.ID)
+	require.NoError(t, err)
+}
@@ -127,17 +127,19 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 	key, err := db.InsertAPIKey(genCtx, database.InsertAPIKeyParams{
 		ID: takeFirst(seed.ID, id),
 		// 0 defaults to 86400 at the db layer
-		LifetimeSeconds: takeFirst(seed.LifetimeSeconds, 0),
-		HashedSecret:    takeFirstSlice(seed.HashedSecret, hashed[:]),
-		IPAddress:       ip,
-		UserID:          takeFirst(seed.UserID, uuid.New()),
-		LastUsed:        takeFirst(seed.LastUsed, dbtime.Now()),
-		ExpiresAt:       takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
-		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
-		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
-		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
-		TokenName:       takeFirst(seed.TokenName),
+		LifetimeSeconds:  takeFirst(seed.LifetimeSeconds, 0),
+		HashedSecret:     takeFirstSlice(seed.HashedSecret, hashed[:]),
+		IPAddress:        ip,
+		UserID:           takeFirst(seed.UserID, uuid.New()),
+		LastUsed:         takeFirst(seed.LastUsed, dbtime.Now()),
+		ExpiresAt:        takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedAt:        takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:        takeFirst(seed.UpdatedAt, dbtime.Now()),
+		LoginType:        takeFirst(seed.LoginType, database.LoginTypePassword),
+		Scope:            takeFirst(seed.Scope, database.APIKeyScopeAll),
+		TokenName:        takeFirst(seed.TokenName),
+		ScopePermissions: takeFirstSlice(seed.ScopePermissions, []string{}),
+		ScopeAllowList:   takeFirstSlice(seed.ScopeAllowList, []string{}),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -154,6 +156,23 @@ func Chat(t testing.TB, db database.Store, seed database.Chat) database.Chat {
 	return chat
 }
 
+func UserSecret(t testing.TB, db database.Store, seed database.UserSecret) database.UserSecret {
+	secret, err := db.InsertUserSecret(genCtx, database.InsertUserSecretParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		UserID:      takeFirst(seed.UserID, uuid.New()),
+		Name:        takeFirst(seed.Name, testutil.GetRandomName(t)),
+		Description: seed.Description,
+		Value:       takeFirst(seed.Value, "secret"),
+		ValueKeyID:  seed.ValueKeyID,
+		EnvName:     seed.EnvName,
+		FilePath:    seed.FilePath,
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(seed.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert user secret")
+	return secret
+}
+
 func ChatMessage(t testing.TB, db database.Store, seed database.ChatMessage) database.ChatMessage {
 	msg, err := db.InsertChatMessages(genCtx, database.InsertChatMessagesParams{
 		CreatedAt: takeFirst(seed.CreatedAt, dbtime.Now()),
@@ -257,6 +276,29 @@ func WorkspaceAgentScripts(t testing.TB, db database.Store, count int, orig data
 	return scripts
 }
 
+func WorkspaceAgentService(t testing.TB, db database.Store, orig database.WorkspaceAgentService) database.WorkspaceAgentService {
+	services, err := db.InsertWorkspaceAgentServices(genCtx, database.InsertWorkspaceAgentServicesParams{
+		WorkspaceAgentID:         takeFirst(orig.WorkspaceAgentID, uuid.New()),
+		CreatedAt:                takeFirst(orig.CreatedAt, dbtime.Now()),
+		ID:                       []uuid.UUID{takeFirst(orig.ID, uuid.New())},
+		LogSourceID:              []uuid.UUID{takeFirst(orig.LogSourceID, uuid.New())},
+		LogPath:                  []string{takeFirst(orig.LogPath, "")},
+		Name:                     []string{takeFirst(orig.Name, testutil.GetRandomName(t))},
+		DisplayName:              []string{takeFirst(orig.DisplayName, "")},
+		Icon:                     []string{takeFirst(orig.Icon, "")},
+		Command:                  []string{takeFirst(orig.Command, "sleep infinity")},
+		RestartPolicy:            []database.WorkspaceAgentServiceRestartPolicy{takeFirst(orig.RestartPolicy, database.WorkspaceAgentServiceRestartPolicyAlways)},
+		RestartDelaySeconds:      []int32{takeFirst(orig.RestartDelaySeconds, 0)},
+		ReadinessURL:             []string{takeFirst(orig.ReadinessURL, "")},
+		ReadinessIntervalSeconds: []int32{takeFirst(orig.ReadinessIntervalSeconds, 0)},
+		ReadinessThreshold:       []int32{takeFirst(orig.ReadinessThreshold, 0)},
+		Health:                   []database.WorkspaceAppHealth{takeFirst(orig.Health, database.WorkspaceAppHealthDisabled)},
+	})
+	require.NoError(t, err, "insert workspace agent service")
+	require.NotEmpty(t, services, "insert workspace agent service returned no services")
+	return services[0]
+}
+
 func WorkspaceAgentScriptTimings(t testing.TB, db database.Store, scripts []database.WorkspaceAgentScript) []database.WorkspaceAgentScriptTiming {
 	timings := make([]database.WorkspaceAgentScriptTiming, len(scripts))
 	for i, script := range scripts {
@@ -306,6 +348,23 @@ func WorkspaceAgentDevcontainer(t testing.TB, db database.Store, orig database.W
 	return devcontainers[0]
 }
 
+func WorkspaceSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceSessionRecording) database.WorkspaceSessionRecording {
+	recording, err := db.InsertWorkspaceSessionRecording(genCtx, database.InsertWorkspaceSessionRecordingParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		WorkspaceID:    takeFirst(orig.WorkspaceID, uuid.New()),
+		OwnerID:        takeFirst(orig.OwnerID, uuid.New()),
+		AgentID:        takeFirst(orig.AgentID, uuid.New()),
+		Type:           takeFirst(orig.Type, database.SessionRecordingTypeSSH),
+		Width:          takeFirst(orig.Width, 80),
+		Height:         takeFirst(orig.Height, 24),
+		StartedAt:      takeFirst(orig.StartedAt, dbtime.Now()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace session recording")
+	return recording
+}
+
 func Workspace(t testing.TB, db database.Store, orig database.WorkspaceTable) database.WorkspaceTable {
 	t.Helper()
 
@@ -475,6 +534,29 @@ func GitSSHKey(t testing.TB, db database.Store, orig database.GitSSHKey) databas
 	return key
 }
 
+func WebAuthnCredential(t testing.TB, db database.Store, seed database.WebAuthnCredential) database.WebAuthnCredential {
+	credentialID := make([]byte, 16)
+	_, err := rand.Read(credentialID)
+	require.NoError(t, err, "generate credential id")
+
+	credential, err := db.InsertWebAuthnCredential(genCtx, database.InsertWebAuthnCredentialParams{
+		ID:              takeFirst(seed.ID, uuid.New()),
+		UserID:          takeFirst(seed.UserID, uuid.New()),
+		Name:            takeFirst(seed.Name, testutil.GetRandomName(t)),
+		CredentialID:    takeFirstSlice(seed.CredentialID, credentialID),
+		PublicKey:       takeFirstSlice(seed.PublicKey, []byte{0xa5}),
+		AttestationType: takeFirst(seed.AttestationType, "none"),
+		Transports:      takeFirstSlice(seed.Transports, []string{"internal"}),
+		AAGUID:          takeFirstSlice(seed.AAGUID, make([]byte, 16)),
+		SignCount:       seed.SignCount,
+		BackupEligible:  seed.BackupEligible,
+		BackupState:     seed.BackupState,
+		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert webauthn credential")
+	return credential
+}
+
 func Organization(t testing.TB, db database.Store, orig database.Organization) database.Organization {
 	org, err := db.InsertOrganization(genCtx, database.InsertOrganizationParams{
 		ID:          takeFirst(orig.ID, uuid.New()),
@@ -1092,12 +1174,15 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 
 func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
 	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
-		ID:          takeFirst(seed.ID, uuid.New()),
-		Name:        takeFirst(seed.Name, testutil.GetRandomName(t)),
-		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:   takeFirst(seed.UpdatedAt, dbtime.Now()),
-		Icon:        takeFirst(seed.Icon, ""),
-		CallbackURL: takeFirst(seed.CallbackURL, "http://localhost"),
+		ID:                    takeFirst(seed.ID, uuid.New()),
+		Name:                  takeFirst(seed.Name, testutil.GetRandomName(t)),
+		CreatedAt:             takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:             takeFirst(seed.UpdatedAt, dbtime.Now()),
+		Icon:                  takeFirst(seed.Icon, ""),
+		CallbackURL:           takeFirst(seed.CallbackURL, "http://localhost"),
+		ClientType:            takeFirst(seed.ClientType, database.OAuth2ProviderAppClientTypeConfidential),
+		ServiceAccountID:      seed.ServiceAccountID,
+		DynamicallyRegistered: seed.DynamicallyRegistered,
 	})
 	require.NoError(t, err, "insert oauth2 app")
 	return app
@@ -1118,13 +1203,15 @@ func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAut
 
 func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
 	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
-		ID:           takeFirst(seed.ID, uuid.New()),
-		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
-		ExpiresAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
-		SecretPrefix: takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
-		HashedSecret: takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
-		AppID:        takeFirst(seed.AppID, uuid.New()),
-		UserID:       takeFirst(seed.UserID, uuid.New()),
+		ID:                  takeFirst(seed.ID, uuid.New()),
+		CreatedAt:           takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:           takeFirst(seed.CreatedAt, dbtime.Now()),
+		SecretPrefix:        takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
+		HashedSecret:        takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		AppID:               takeFirst(seed.AppID, uuid.New()),
+		UserID:              takeFirst(seed.UserID, uuid.New()),
+		CodeChallenge:       seed.CodeChallenge,
+		CodeChallengeMethod: seed.CodeChallengeMethod,
 	})
 	require.NoError(t, err, "insert oauth2 app code")
 	return code
@@ -1137,13 +1224,28 @@ func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth
 		ExpiresAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
 		HashPrefix:  takeFirstSlice(seed.HashPrefix, []byte("prefix")),
 		RefreshHash: takeFirstSlice(seed.RefreshHash, []byte("hashed-secret")),
-		AppSecretID: takeFirst(seed.AppSecretID, uuid.New()),
+		AppSecretID: seed.AppSecretID,
 		APIKeyID:    takeFirst(seed.APIKeyID, uuid.New().String()),
+		AppID:       takeFirst(seed.AppID, uuid.New()),
 	})
 	require.NoError(t, err, "insert oauth2 app token")
 	return token
 }
 
+func OAuth2ProviderAppDeviceCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppDeviceCode) database.OAuth2ProviderAppDeviceCode {
+	code, err := db.InsertOAuth2ProviderAppDeviceCode(genCtx, database.InsertOAuth2ProviderAppDeviceCodeParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:    takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Minute*15)),
+		SecretPrefix: takeFirstSlice(seed.SecretPrefix, []byte(testutil.GetRandomName(t))),
+		HashedSecret: takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		UserCode:     takeFirst(seed.UserCode, testutil.GetRandomName(t)),
+		AppID:        takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app device code")
+	return code
+}
+
 func WorkspaceAgentMemoryResourceMonitor(t testing.TB, db database.Store, seed database.WorkspaceAgentMemoryResourceMonitor) database.WorkspaceAgentMemoryResourceMonitor {
 	monitor, err := db.InsertMemoryResourceMonitor(genCtx, database.InsertMemoryResourceMonitorParams{
 		AgentID:        takeFirst(seed.AgentID, uuid.New()),
@@ -1173,6 +1275,22 @@ func WorkspaceAgentVolumeResourceMonitor(t testing.TB, db database.Store, seed d
 	return monitor
 }
 
+func WorkspaceAgentResourceMonitor(t testing.TB, db database.Store, seed database.WorkspaceAgentResourceMonitor) database.WorkspaceAgentResourceMonitor {
+	monitor, err := db.InsertResourceMonitor(genCtx, database.InsertResourceMonitorParams{
+		AgentID:        takeFirst(seed.AgentID, uuid.New()),
+		Type:           takeFirst(seed.Type, database.WorkspaceAgentResourceMonitorTypeCPU),
+		Path:           seed.Path,
+		Enabled:        takeFirst(seed.Enabled, true),
+		State:          takeFirst(seed.State, database.WorkspaceAgentMonitorStateOK),
+		Threshold:      takeFirst(seed.Threshold, 100),
+		CreatedAt:      takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(seed.UpdatedAt, dbtime.Now()),
+		DebouncedUntil: takeFirst(seed.DebouncedUntil, time.Time{}),
+	})
+	require.NoError(t, err, "insert workspace agent resource monitor")
+	return monitor
+}
+
 func CustomRole(t testing.TB, db database.Store, seed database.CustomRole) database.CustomRole {
 	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
 		Name:            takeFirst(seed.Name, strings.ToLower(testutil.GetRandomName(t))),
@@ -234,6 +234,7 @@ type data struct {
 	oauth2ProviderAppSecrets             []database.OAuth2ProviderAppSecret
 	oauth2ProviderAppCodes               []database.OAuth2ProviderAppCode
 	oauth2ProviderAppTokens              []database.OAuth2ProviderAppToken
+	oauth2ProviderAppDeviceCodes         []database.OAuth2ProviderAppDeviceCode
 	parameterSchemas                     []database.ParameterSchema
 	provisionerDaemons                   []database.ProvisionerDaemon
 	provisionerJobLogs                   []database.ProvisionerJobLog
@@ -248,6 +249,11 @@ type data struct {
 	templates                            []database.TemplateTable
 	templateUsageStats                   []database.TemplateUsageStat
 	userConfigs                          []database.UserConfig
+	userMFARecoveryCodes                 []database.UserMFARecoveryCode
+	userSecrets                          []database.UserSecret
+	userTOTPs                            []database.UserTOTP
+	webAuthnChallenges                   []database.WebAuthnChallenge
+	webAuthnCredentials                  []database.WebAuthnCredential
 	webpushSubscriptions                 []database.WebpushSubscription
 	workspaceAgents                      []database.WorkspaceAgent
 	workspaceAgentMetadata               []database.WorkspaceAgentMetadatum
@@ -256,9 +262,11 @@ type data struct {
 	workspaceAgentPortShares             []database.WorkspaceAgentPortShare
 	workspaceAgentScriptTimings          []database.WorkspaceAgentScriptTiming
 	workspaceAgentScripts                []database.WorkspaceAgentScript
+	workspaceAgentServices               []database.WorkspaceAgentService
 	workspaceAgentStats                  []database.WorkspaceAgentStat
 	workspaceAgentMemoryResourceMonitors []database.WorkspaceAgentMemoryResourceMonitor
 	workspaceAgentVolumeResourceMonitors []database.WorkspaceAgentVolumeResourceMonitor
+	workspaceAgentResourceMonitors       []database.WorkspaceAgentResourceMonitor
 	workspaceAgentDevcontainers          []database.WorkspaceAgentDevcontainer
 	workspaceApps                        []database.WorkspaceApp
 	workspaceAppStatuses                 []database.WorkspaceAppStatus
@@ -270,6 +278,8 @@ type data struct {
 	workspaceResourceMetadata            []database.WorkspaceResourceMetadatum
 	workspaceResources                   []database.WorkspaceResource
 	workspaceModules                     []database.WorkspaceModule
+	workspaceSessionRecordings           []database.WorkspaceSessionRecording
+	workspaceSessionRecordingChunks      []database.WorkspaceSessionRecordingChunk
 	workspaces                           []database.WorkspaceTable
 	workspaceProxies                     []database.WorkspaceProxy
 	customRoles                          []database.CustomRole
@@ -447,6 +457,34 @@ func (q *FakeQuerier) getUserByIDNoLock(id uuid.UUID) (database.User, error) {
 	return database.User{}, sql.ErrNoRows
 }
 
+// userSecretUniqueViolationNoLock emulates the unique constraints of the
+// user_secrets table for a secret that is about to be stored.
+func (q *FakeQuerier) userSecretUniqueViolationNoLock(secret database.UserSecret) error {
+	for _, other := range q.userSecrets {
+		if other.ID == secret.ID || other.UserID != secret.UserID {
+			continue
+		}
+		var constraint database.UniqueConstraint
+		switch {
+		case other.Name == secret.Name:
+			constraint = database.UniqueUserSecretsUserIDNameKey
+		case secret.EnvName != "" && other.EnvName == secret.EnvName:
+			constraint = database.UniqueUserSecretsUserEnvNameIndex
+		case secret.FilePath != "" && other.FilePath == secret.FilePath:
+			constraint = database.UniqueUserSecretsUserFilePathIndex
+		default:
+			continue
+		}
+		return &pq.Error{
+			Code:       "23505",
+			Message:    fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+			Table:      "user_secrets",
+			Constraint: string(constraint),
+		}
+	}
+	return nil
+}
+
 func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 	rows := make([]database.GetUsersRow, len(users))
 	for i, u := range users {
@@ -1791,6 +1829,19 @@ func (q *FakeQuerier) CountUnreadInboxNotificationsByUserID(_ context.Context, u
 	return count, nil
 }
 
+func (q *FakeQuerier) CountUserMFARecoveryCodes(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, code := range q.userMFARecoveryCodes {
+		if code.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (q *FakeQuerier) CustomRoles(_ context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1963,6 +2014,16 @@ func (q *FakeQuerier) DeleteCustomRole(_ context.Context, arg database.DeleteCus
 	return nil
 }
 
+func (q *FakeQuerier) DeleteExpiredWebAuthnChallenges(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.webAuthnChallenges = slices.DeleteFunc(q.webAuthnChallenges, func(challenge database.WebAuthnChallenge) bool {
+		return challenge.ExpiresAt.Before(beforeTime)
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteExternalAuthLink(_ context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2057,19 +2118,19 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUI
 	q.oauth2ProviderApps = q.oauth2ProviderApps[:len(q.oauth2ProviderApps)-1]
 
 	// Cascade delete secrets associated with the deleted app.
-	var deletedSecretIDs []uuid.UUID
 	q.oauth2ProviderAppSecrets = slices.DeleteFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
-		matches := secret.AppID == id
-		if matches {
-			deletedSecretIDs = append(deletedSecretIDs, secret.ID)
-		}
-		return matches
+		return secret.AppID == id
+	})
+
+	// Cascade delete device codes requested by the deleted app.
+	q.oauth2ProviderAppDeviceCodes = slices.DeleteFunc(q.oauth2ProviderAppDeviceCodes, func(code database.OAuth2ProviderAppDeviceCode) bool {
+		return code.AppID == id
 	})
 
-	// Cascade delete tokens through the deleted secrets.
+	// Cascade delete tokens issued by the deleted app.
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		matches := slice.Contains(deletedSecretIDs, token.AppSecretID)
+		matches := token.AppID == id
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
 		}
@@ -2117,6 +2178,20 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(_ context.Conte
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOAuth2ProviderAppDeviceCodeByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppDeviceCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppDeviceCodes[index] = q.oauth2ProviderAppDeviceCodes[len(q.oauth2ProviderAppDeviceCodes)-1]
+			q.oauth2ProviderAppDeviceCodes = q.oauth2ProviderAppDeviceCodes[:len(q.oauth2ProviderAppDeviceCodes)-1]
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2135,7 +2210,7 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uu
 	// Cascade delete tokens created through the deleted secret.
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		matches := token.AppSecretID == id
+		matches := token.AppSecretID.Valid && token.AppSecretID.UUID == id
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
 		}
@@ -2161,15 +2236,11 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Cont
 
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		// Join secrets and keys to see if the token matches.
-		secretIdx := slices.IndexFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
-			return secret.ID == token.AppSecretID
-		})
+		// Join keys to see if the token matches.
 		keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
 			return key.ID == token.APIKeyID
 		})
-		matches := secretIdx != -1 &&
-			q.oauth2ProviderAppSecrets[secretIdx].AppID == arg.AppID &&
+		matches := token.AppID == arg.AppID &&
 			keyIdx != -1 && q.apiKeys[keyIdx].UserID == arg.UserID
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
@@ -2372,6 +2443,25 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deleted := make(map[uuid.UUID]struct{})
+	q.workspaceSessionRecordings = slices.DeleteFunc(q.workspaceSessionRecordings, func(r database.WorkspaceSessionRecording) bool {
+		if r.StartedAt.Before(beforeTime) {
+			deleted[r.ID] = struct{}{}
+			return true
+		}
+		return false
+	})
+	q.workspaceSessionRecordingChunks = slices.DeleteFunc(q.workspaceSessionRecordingChunks, func(c database.WorkspaceSessionRecordingChunk) bool {
+		_, ok := deleted[c.RecordingID]
+		return ok
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2468,6 +2558,104 @@ func (*FakeQuerier) DeleteTailnetTunnel(_ context.Context, arg database.DeleteTa
 	return database.DeleteTailnetTunnelRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteUnusedDynamicOAuth2ProviderApps(ctx context.Context, beforeTime time.Time) error {
+	q.mutex.RLock()
+	var unused []uuid.UUID
+	for _, app := range q.oauth2ProviderApps {
+		if !app.DynamicallyRegistered || !app.CreatedAt.Before(beforeTime) {
+			continue
+		}
+		if slices.ContainsFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
+			return token.AppID == app.ID
+		}) {
+			continue
+		}
+		unused = append(unused, app.ID)
+	}
+	q.mutex.RUnlock()
+
+	for _, id := range unused {
+		if err := q.DeleteOAuth2ProviderAppByID(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserMFARecoveryCode(_ context.Context, arg database.DeleteUserMFARecoveryCodeParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.userMFARecoveryCodes {
+		if code.UserID == arg.UserID && bytes.Equal(code.HashedCode, arg.HashedCode) {
+			q.userMFARecoveryCodes = append(q.userMFARecoveryCodes[:i], q.userMFARecoveryCodes[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *FakeQuerier) DeleteUserMFARecoveryCodes(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userMFARecoveryCodes = slices.DeleteFunc(q.userMFARecoveryCodes, func(code database.UserMFARecoveryCode) bool {
+		return code.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserSecret(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.userSecrets {
+		if secret.ID == id {
+			q.userSecrets = append(q.userSecrets[:i], q.userSecrets[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteUserTOTP(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userTOTPs = slices.DeleteFunc(q.userTOTPs, func(totp database.UserTOTP) bool {
+		return totp.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWebAuthnChallenge(_ context.Context, challenge string) (database.WebAuthnChallenge, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, c := range q.webAuthnChallenges {
+		if c.Challenge == challenge {
+			q.webAuthnChallenges = append(q.webAuthnChallenges[:i], q.webAuthnChallenges[i+1:]...)
+			return c, nil
+		}
+	}
+	return database.WebAuthnChallenge{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteWebAuthnCredential(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.webAuthnCredentials = slices.DeleteFunc(q.webAuthnCredentials, func(credential database.WebAuthnCredential) bool {
+		return credential.ID == id
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteWebpushSubscriptionByUserIDAndEndpoint(_ context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2669,6 +2857,20 @@ func (q *FakeQuerier) FetchNewMessageMetadata(_ context.Context, arg database.Fe
 	}, nil
 }
 
+func (q *FakeQuerier) FetchResourceMonitorsByAgentID(_ context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	monitors := []database.WorkspaceAgentResourceMonitor{}
+	for _, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID == agentID {
+			monitors = append(monitors, monitor)
+		}
+	}
+
+	return monitors, nil
+}
+
 func (q *FakeQuerier) FetchVolumesResourceMonitorsByAgentID(_ context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	monitors := []database.WorkspaceAgentVolumeResourceMonitor{}
 
@@ -4003,6 +4205,30 @@ func (q *FakeQuerier) GetOAuth2ProviderAppCodeByPrefix(_ context.Context, secret
 	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppDeviceCodeByPrefix(_ context.Context, secretPrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppDeviceCodes {
+		if bytes.Equal(code.SecretPrefix, secretPrefix) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppDeviceCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppDeviceCodeByUserCode(_ context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppDeviceCodes {
+		if code.UserCode == userCode {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4055,6 +4281,18 @@ func (q *FakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appI
 	return []database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByAPIKeyID(_ context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.APIKeyID == apiKeyID {
+			return token, nil
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderAppTokenByPrefix(_ context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4084,27 +4322,26 @@ func (q *FakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uu
 	rows := []database.GetOAuth2ProviderAppsByUserIDRow{}
 	for _, app := range q.oauth2ProviderApps {
 		tokens := []database.OAuth2ProviderAppToken{}
-		for _, secret := range q.oauth2ProviderAppSecrets {
-			if secret.AppID == app.ID {
-				for _, token := range q.oauth2ProviderAppTokens {
-					if token.AppSecretID == secret.ID {
-						keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
-							return key.ID == token.APIKeyID
-						})
-						if keyIdx != -1 && q.apiKeys[keyIdx].UserID == userID {
-							tokens = append(tokens, token)
-						}
-					}
+		for _, token := range q.oauth2ProviderAppTokens {
+			if token.AppID == app.ID {
+				keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
+					return key.ID == token.APIKeyID
+				})
+				if keyIdx != -1 && q.apiKeys[keyIdx].UserID == userID {
+					tokens = append(tokens, token)
 				}
 			}
 		}
 		if len(tokens) > 0 {
 			rows = append(rows, database.GetOAuth2ProviderAppsByUserIDRow{
 				OAuth2ProviderApp: database.OAuth2ProviderApp{
-					CallbackURL: app.CallbackURL,
-					ID:          app.ID,
-					Icon:        app.Icon,
-					Name:        app.Name,
+					CallbackURL:           app.CallbackURL,
+					ID:                    app.ID,
+					Icon:                  app.Icon,
+					Name:                  app.Name,
+					ClientType:            app.ClientType,
+					ServiceAccountID:      app.ServiceAccountID,
+					DynamicallyRegistered: app.DynamicallyRegistered,
 				},
 				TokenCount: int64(len(tokens)),
 			})
@@ -6726,6 +6963,51 @@ func (q *FakeQuerier) GetUserNotificationPreferences(_ context.Context, userID u
 	return out, nil
 }
 
+func (q *FakeQuerier) GetUserSecretByID(_ context.Context, id uuid.UUID) (database.UserSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.userSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.UserSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserSecretByUserIDAndName(_ context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.userSecrets {
+		if secret.UserID == arg.UserID && secret.Name == arg.Name {
+			return secret, nil
+		}
+	}
+	return database.UserSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserSecretsByUserID(_ context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.UserSecret, 0)
+	for _, secret := range q.userSecrets {
+		if secret.UserID == userID {
+			secrets = append(secrets, secret)
+		}
+	}
+	slices.SortFunc(secrets, func(a, b database.UserSecret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return secrets, nil
+}
+
 func (q *FakeQuerier) GetUserStatusCounts(_ context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -6762,6 +7044,18 @@ func (q *FakeQuerier) GetUserStatusCounts(_ context.Context, arg database.GetUse
 	return result, nil
 }
 
+func (q *FakeQuerier) GetUserTOTPByUserID(_ context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, totp := range q.userTOTPs {
+		if totp.UserID == userID {
+			return totp, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7030,6 +7324,46 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetWebAuthnCredentialByCredentialID(_ context.Context, credentialID []byte) (database.WebAuthnCredential, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, credential := range q.webAuthnCredentials {
+		if bytes.Equal(credential.CredentialID, credentialID) {
+			return credential, nil
+		}
+	}
+	return database.WebAuthnCredential{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebAuthnCredentialByID(_ context.Context, id uuid.UUID) (database.WebAuthnCredential, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, credential := range q.webAuthnCredentials {
+		if credential.ID == id {
+			return credential, nil
+		}
+	}
+	return database.WebAuthnCredential{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebAuthnCredentialsByUserID(_ context.Context, userID uuid.UUID) ([]database.WebAuthnCredential, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	credentials := make([]database.WebAuthnCredential, 0)
+	for _, credential := range q.webAuthnCredentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, credential)
+		}
+	}
+	slices.SortFunc(credentials, func(a, b database.WebAuthnCredential) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return credentials, nil
+}
+
 func (q *FakeQuerier) GetWebpushSubscriptionsByUserID(_ context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7343,6 +7677,22 @@ func (q *FakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids
 	return q.getWorkspaceAgentScriptsByAgentIDsNoLock(ids)
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentServicesByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	services := make([]database.WorkspaceAgentService, 0)
+	for _, service := range q.workspaceAgentServices {
+		if slices.Contains(ids, service.WorkspaceAgentID) {
+			services = append(services, service)
+		}
+	}
+	slices.SortFunc(services, func(a, b database.WorkspaceAgentService) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return services, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentStats(_ context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8326,43 +8676,130 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
-func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	workspaceOwners := make(map[uuid.UUID]map[uuid.UUID]struct{})
-	for _, workspace := range q.workspaces {
-		if workspace.Deleted {
-			continue
+	for _, r := range q.workspaceSessionRecordings {
+		if r.ID == id {
+			return r, nil
 		}
-		if !slices.Contains(templateIds, workspace.TemplateID) {
-			continue
-		}
-		_, ok := workspaceOwners[workspace.TemplateID]
-		if !ok {
-			workspaceOwners[workspace.TemplateID] = make(map[uuid.UUID]struct{})
-		}
-		workspaceOwners[workspace.TemplateID][workspace.OwnerID] = struct{}{}
 	}
-	resp := make([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, 0)
-	for _, templateID := range templateIds {
-		count := len(workspaceOwners[templateID])
-		resp = append(resp, database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow{
-			TemplateID:      templateID,
-			UniqueOwnersSum: int64(count),
-		})
-	}
-
-	return resp, nil
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
-	if err := validateDatabaseType(arg); err != nil {
+func (q *FakeQuerier) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
 		return nil, err
 	}
 
-	// A nil auth filter means no auth filter.
-	workspaceRows, err := q.GetAuthorizedWorkspaces(ctx, arg, nil)
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.WorkspaceSessionRecordingChunk, 0)
+	for _, c := range q.workspaceSessionRecordingChunks {
+		if c.RecordingID == arg.RecordingID && c.Sequence > arg.AfterSequence {
+			chunks = append(chunks, c)
+		}
+	}
+	slices.SortFunc(chunks, func(a, b database.WorkspaceSessionRecordingChunk) int {
+		return int(a.Sequence - b.Sequence)
+	})
+	if len(chunks) > int(arg.LimitOpt) {
+		chunks = chunks[:arg.LimitOpt]
+	}
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordings(_ context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceSessionRecordingsRow, 0)
+	for _, r := range q.workspaceSessionRecordings {
+		if arg.WorkspaceID != uuid.Nil && r.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if arg.OwnerID != uuid.Nil && r.OwnerID != arg.OwnerID {
+			continue
+		}
+		workspaceIdx := slices.IndexFunc(q.workspaces, func(w database.WorkspaceTable) bool {
+			return w.ID == r.WorkspaceID
+		})
+		if workspaceIdx < 0 {
+			continue
+		}
+		owner, err := q.getUserByIDNoLock(r.OwnerID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceSessionRecordingsRow{
+			WorkspaceSessionRecording: r,
+			WorkspaceName:             q.workspaces[workspaceIdx].Name,
+			OwnerUsername:             owner.Username,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceSessionRecordingsRow) int {
+		if c := b.WorkspaceSessionRecording.StartedAt.Compare(a.WorkspaceSessionRecording.StartedAt); c != 0 {
+			return c
+		}
+		return slice.Descending(a.WorkspaceSessionRecording.ID.String(), b.WorkspaceSessionRecording.ID.String())
+	})
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(rows) {
+			return []database.GetWorkspaceSessionRecordingsRow{}, nil
+		}
+		rows = rows[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && len(rows) > int(arg.LimitOpt) {
+		rows = rows[:arg.LimitOpt]
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaceOwners := make(map[uuid.UUID]map[uuid.UUID]struct{})
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		if !slices.Contains(templateIds, workspace.TemplateID) {
+			continue
+		}
+		_, ok := workspaceOwners[workspace.TemplateID]
+		if !ok {
+			workspaceOwners[workspace.TemplateID] = make(map[uuid.UUID]struct{})
+		}
+		workspaceOwners[workspace.TemplateID][workspace.OwnerID] = struct{}{}
+	}
+	resp := make([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, 0)
+	for _, templateID := range templateIds {
+		count := len(workspaceOwners[templateID])
+		resp = append(resp, database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow{
+			TemplateID:      templateID,
+			UniqueOwnersSum: int64(count),
+		})
+	}
+
+	return resp, nil
+}
+
+func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	// A nil auth filter means no auth filter.
+	workspaceRows, err := q.GetAuthorizedWorkspaces(ctx, arg, nil)
 	return workspaceRows, err
 }
 
@@ -8511,18 +8948,26 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 
 	//nolint:gosimple
 	key := database.APIKey{
-		ID:              arg.ID,
-		LifetimeSeconds: arg.LifetimeSeconds,
-		HashedSecret:    arg.HashedSecret,
-		IPAddress:       arg.IPAddress,
-		UserID:          arg.UserID,
-		ExpiresAt:       arg.ExpiresAt,
-		CreatedAt:       arg.CreatedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		LastUsed:        arg.LastUsed,
-		LoginType:       arg.LoginType,
-		Scope:           arg.Scope,
-		TokenName:       arg.TokenName,
+		ID:               arg.ID,
+		LifetimeSeconds:  arg.LifetimeSeconds,
+		HashedSecret:     arg.HashedSecret,
+		IPAddress:        arg.IPAddress,
+		UserID:           arg.UserID,
+		ExpiresAt:        arg.ExpiresAt,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		LastUsed:         arg.LastUsed,
+		LoginType:        arg.LoginType,
+		Scope:            arg.Scope,
+		TokenName:        arg.TokenName,
+		ScopePermissions: arg.ScopePermissions,
+		ScopeAllowList:   arg.ScopeAllowList,
+	}
+	if key.ScopePermissions == nil {
+		key.ScopePermissions = []string{}
+	}
+	if key.ScopeAllowList == nil {
+		key.ScopeAllowList = []string{}
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -8691,6 +9136,8 @@ func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBC
 		Number:          arg.Number,
 		ActiveKeyDigest: sql.NullString{String: arg.ActiveKeyDigest, Valid: true},
 		Test:            arg.Test,
+		WrappedKey:      arg.WrappedKey,
+		WrappingKeyID:   arg.WrappingKeyID,
 	})
 	return nil
 }
@@ -8950,18 +9397,26 @@ func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.In
 
 	for _, app := range q.oauth2ProviderApps {
 		if app.Name == arg.Name {
-			return database.OAuth2ProviderApp{}, errUniqueConstraint
+			return database.OAuth2ProviderApp{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint \"oauth2_provider_apps_name_key\"",
+				Table:      "oauth2_provider_apps",
+				Constraint: string(database.UniqueOauth2ProviderAppsNameKey),
+			}
 		}
 	}
 
 	//nolint:gosimple // Go wants database.OAuth2ProviderApp(arg), but we cannot be sure the structs will remain identical.
 	app := database.OAuth2ProviderApp{
-		ID:          arg.ID,
-		CreatedAt:   arg.CreatedAt,
-		UpdatedAt:   arg.UpdatedAt,
-		Name:        arg.Name,
-		Icon:        arg.Icon,
-		CallbackURL: arg.CallbackURL,
+		ID:                    arg.ID,
+		CreatedAt:             arg.CreatedAt,
+		UpdatedAt:             arg.UpdatedAt,
+		Name:                  arg.Name,
+		Icon:                  arg.Icon,
+		CallbackURL:           arg.CallbackURL,
+		ClientType:            arg.ClientType,
+		ServiceAccountID:      arg.ServiceAccountID,
+		DynamicallyRegistered: arg.DynamicallyRegistered,
 	}
 	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
 
@@ -8980,20 +9435,59 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg databas
 	for _, app := range q.oauth2ProviderApps {
 		if app.ID == arg.AppID {
 			code := database.OAuth2ProviderAppCode{
+				ID:                  arg.ID,
+				CreatedAt:           arg.CreatedAt,
+				ExpiresAt:           arg.ExpiresAt,
+				SecretPrefix:        arg.SecretPrefix,
+				HashedSecret:        arg.HashedSecret,
+				UserID:              arg.UserID,
+				AppID:               arg.AppID,
+				CodeChallenge:       arg.CodeChallenge,
+				CodeChallengeMethod: arg.CodeChallengeMethod,
+				Scope:               arg.Scope,
+			}
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+			return code, nil
+		}
+	}
+
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppDeviceCode(_ context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppDeviceCodes {
+		if code.UserCode == arg.UserCode || bytes.Equal(code.SecretPrefix, arg.SecretPrefix) {
+			return database.OAuth2ProviderAppDeviceCode{}, errUniqueConstraint
+		}
+	}
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
+			code := database.OAuth2ProviderAppDeviceCode{
 				ID:           arg.ID,
 				CreatedAt:    arg.CreatedAt,
 				ExpiresAt:    arg.ExpiresAt,
 				SecretPrefix: arg.SecretPrefix,
 				HashedSecret: arg.HashedSecret,
-				UserID:       arg.UserID,
+				UserCode:     arg.UserCode,
 				AppID:        arg.AppID,
+				Status:       database.OAuth2ProviderAppDeviceCodeStatusPending,
+				Scope:        arg.Scope,
 			}
-			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+			q.oauth2ProviderAppDeviceCodes = append(q.oauth2ProviderAppDeviceCodes, code)
 			return code, nil
 		}
 	}
 
-	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppDeviceCode{}, sql.ErrNoRows
 }
 
 func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
@@ -9032,8 +9526,13 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for _, secret := range q.oauth2ProviderAppSecrets {
-		if secret.ID == arg.AppSecretID {
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
+			if arg.AppSecretID.Valid && !slices.ContainsFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
+				return secret.ID == arg.AppSecretID.UUID
+			}) {
+				return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+			}
 			//nolint:gosimple // Go wants database.OAuth2ProviderAppToken(arg), but we cannot be sure the structs will remain identical.
 			token := database.OAuth2ProviderAppToken{
 				ID:          arg.ID,
@@ -9043,6 +9542,7 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 				RefreshHash: arg.RefreshHash,
 				APIKeyID:    arg.APIKeyID,
 				AppSecretID: arg.AppSecretID,
+				AppID:       arg.AppID,
 			}
 			q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
 			return token, nil
@@ -9294,6 +9794,37 @@ func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *FakeQuerier) InsertResourceMonitor(_ context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentResourceMonitor{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID == arg.AgentID && monitor.Type == arg.Type && monitor.Path == arg.Path {
+			return database.WorkspaceAgentResourceMonitor{}, errUniqueConstraint
+		}
+	}
+
+	monitor := database.WorkspaceAgentResourceMonitor{
+		AgentID:        arg.AgentID,
+		Type:           arg.Type,
+		Path:           arg.Path,
+		Enabled:        arg.Enabled,
+		State:          arg.State,
+		Threshold:      arg.Threshold,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		DebouncedUntil: arg.DebouncedUntil,
+	}
+
+	q.workspaceAgentResourceMonitors = append(q.workspaceAgentResourceMonitors, monitor)
+	return monitor, nil
+}
+
 func (q *FakeQuerier) InsertTelemetryItemIfNotExists(_ context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9612,6 +10143,58 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertUserMFARecoveryCodes(_ context.Context, arg database.InsertUserMFARecoveryCodesParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, hashed := range arg.HashedCodes {
+		for _, code := range q.userMFARecoveryCodes {
+			if code.UserID == arg.UserID && bytes.Equal(code.HashedCode, hashed) {
+				return errUniqueConstraint
+			}
+		}
+		q.userMFARecoveryCodes = append(q.userMFARecoveryCodes, database.UserMFARecoveryCode{
+			UserID:     arg.UserID,
+			HashedCode: hashed,
+			CreatedAt:  arg.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) InsertUserSecret(_ context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	secret := database.UserSecret{
+		ID:          arg.ID,
+		UserID:      arg.UserID,
+		Name:        arg.Name,
+		Description: arg.Description,
+		Value:       arg.Value,
+		ValueKeyID:  arg.ValueKeyID,
+		EnvName:     arg.EnvName,
+		FilePath:    arg.FilePath,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	if err := q.userSecretUniqueViolationNoLock(secret); err != nil {
+		return database.UserSecret{}, err
+	}
+	q.userSecrets = append(q.userSecrets, secret)
+	return secret, nil
+}
+
 func (q *FakeQuerier) InsertVolumeResourceMonitor(_ context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9636,6 +10219,66 @@ func (q *FakeQuerier) InsertVolumeResourceMonitor(_ context.Context, arg databas
 	return monitor, nil
 }
 
+func (q *FakeQuerier) InsertWebAuthnChallenge(_ context.Context, arg database.InsertWebAuthnChallengeParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, c := range q.webAuthnChallenges {
+		if c.Challenge == arg.Challenge {
+			return errUniqueConstraint
+		}
+	}
+	q.webAuthnChallenges = append(q.webAuthnChallenges, database.WebAuthnChallenge{
+		Challenge: arg.Challenge,
+		Session:   arg.Session,
+		ExpiresAt: arg.ExpiresAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertWebAuthnCredential(_ context.Context, arg database.InsertWebAuthnCredentialParams) (database.WebAuthnCredential, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WebAuthnCredential{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, credential := range q.webAuthnCredentials {
+		if credential.ID == arg.ID {
+			return database.WebAuthnCredential{}, errUniqueConstraint
+		}
+		if bytes.Equal(credential.CredentialID, arg.CredentialID) {
+			return database.WebAuthnCredential{}, newUniqueConstraintError(database.UniqueWebauthnCredentialsCredentialIDKey)
+		}
+	}
+	credential := database.WebAuthnCredential{
+		ID:              arg.ID,
+		UserID:          arg.UserID,
+		Name:            arg.Name,
+		CredentialID:    arg.CredentialID,
+		PublicKey:       arg.PublicKey,
+		AttestationType: arg.AttestationType,
+		Transports:      arg.Transports,
+		AAGUID:          arg.AAGUID,
+		SignCount:       arg.SignCount,
+		BackupEligible:  arg.BackupEligible,
+		BackupState:     arg.BackupState,
+		CreatedAt:       arg.CreatedAt,
+	}
+	if credential.Transports == nil {
+		credential.Transports = []string{}
+	}
+	q.webAuthnCredentials = append(q.webAuthnCredentials, credential)
+	return credential, nil
+}
+
 func (q *FakeQuerier) InsertWebpushSubscription(_ context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9884,6 +10527,47 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 	return scripts, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentServices(_ context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	services := make([]database.WorkspaceAgentService, 0)
+	for index, id := range arg.ID {
+		for _, service := range q.workspaceAgentServices {
+			if service.WorkspaceAgentID == arg.WorkspaceAgentID && service.Name == arg.Name[index] {
+				return nil, errUniqueConstraint
+			}
+		}
+		service := database.WorkspaceAgentService{
+			ID:                       id,
+			WorkspaceAgentID:         arg.WorkspaceAgentID,
+			LogSourceID:              arg.LogSourceID[index],
+			LogPath:                  arg.LogPath[index],
+			CreatedAt:                arg.CreatedAt,
+			Name:                     arg.Name[index],
+			DisplayName:              arg.DisplayName[index],
+			Icon:                     arg.Icon[index],
+			Command:                  arg.Command[index],
+			RestartPolicy:            arg.RestartPolicy[index],
+			RestartDelaySeconds:      arg.RestartDelaySeconds[index],
+			ReadinessURL:             arg.ReadinessURL[index],
+			ReadinessIntervalSeconds: arg.ReadinessIntervalSeconds[index],
+			ReadinessThreshold:       arg.ReadinessThreshold[index],
+			Status:                   database.WorkspaceAgentServiceStatusPending,
+			Health:                   arg.Health[index],
+			UpdatedAt:                arg.CreatedAt,
+		}
+		services = append(services, service)
+	}
+	q.workspaceAgentServices = append(q.workspaceAgentServices, services...)
+	return services, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentStats(_ context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -10178,6 +10862,59 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, r := range q.workspaceSessionRecordings {
+		if r.ID == arg.ID {
+			return database.WorkspaceSessionRecording{}, errUniqueConstraint
+		}
+	}
+	recording := database.WorkspaceSessionRecording{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		WorkspaceID:    arg.WorkspaceID,
+		OwnerID:        arg.OwnerID,
+		AgentID:        arg.AgentID,
+		Type:           arg.Type,
+		Width:          arg.Width,
+		Height:         arg.Height,
+		StartedAt:      arg.StartedAt,
+		CreatedAt:      arg.CreatedAt,
+	}
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, recording)
+	return recording, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, c := range q.workspaceSessionRecordingChunks {
+		if c.RecordingID == arg.RecordingID && c.Sequence == arg.Sequence {
+			return newUniqueConstraintError(database.UniqueWorkspaceSessionRecordingChunksPkey)
+		}
+	}
+	q.workspaceSessionRecordingChunks = append(q.workspaceSessionRecordingChunks, database.WorkspaceSessionRecordingChunk{
+		RecordingID: arg.RecordingID,
+		Sequence:    arg.Sequence,
+		Data:        slices.Clone(arg.Data),
+		CreatedAt:   arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -10428,6 +11165,7 @@ func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.Reg
 			p.WildcardHostname = arg.WildcardHostname
 			p.DerpEnabled = arg.DerpEnabled
 			p.DerpOnly = arg.DerpOnly
+			p.DerpQuicPort = arg.DerpQuicPort
 			p.Version = arg.Version
 			p.UpdatedAt = dbtime.Now()
 			q.workspaceProxies[i] = p
@@ -10649,6 +11387,26 @@ func (q *FakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCus
 	return database.CustomRole{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateDBCryptKeyWrappedKey(_ context.Context, arg database.UpdateDBCryptKeyWrappedKeyParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := range q.dbcryptKeys {
+		if q.dbcryptKeys[i].Number != arg.Number {
+			continue
+		}
+		q.dbcryptKeys[i].WrappedKey = sql.NullString{String: arg.WrappedKey, Valid: true}
+		q.dbcryptKeys[i].WrappingKeyID = sql.NullString{String: arg.WrappingKeyID, Valid: true}
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateExternalAuthLink(_ context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ExternalAuthLink{}, err
@@ -10868,12 +11626,15 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg databas
 	for index, app := range q.oauth2ProviderApps {
 		if app.ID == arg.ID {
 			newApp := database.OAuth2ProviderApp{
-				ID:          arg.ID,
-				CreatedAt:   app.CreatedAt,
-				UpdatedAt:   arg.UpdatedAt,
-				Name:        arg.Name,
-				Icon:        arg.Icon,
-				CallbackURL: arg.CallbackURL,
+				ID:                    arg.ID,
+				CreatedAt:             app.CreatedAt,
+				UpdatedAt:             arg.UpdatedAt,
+				Name:                  arg.Name,
+				Icon:                  arg.Icon,
+				CallbackURL:           arg.CallbackURL,
+				ClientType:            arg.ClientType,
+				ServiceAccountID:      arg.ServiceAccountID,
+				DynamicallyRegistered: app.DynamicallyRegistered,
 			}
 			q.oauth2ProviderApps[index] = newApp
 			return newApp, nil
@@ -10882,6 +11643,45 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg databas
 	return database.OAuth2ProviderApp{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(_ context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppDeviceCodes {
+		if code.ID == arg.ID {
+			code.LastPolledAt = arg.LastPolledAt
+			q.oauth2ProviderAppDeviceCodes[index] = code
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderAppDeviceCodeStatusByID(_ context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppDeviceCodes {
+		if code.ID == arg.ID && code.Status == database.OAuth2ProviderAppDeviceCodeStatusPending {
+			code.Status = arg.Status
+			code.UserID = arg.UserID
+			q.oauth2ProviderAppDeviceCodes[index] = code
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11116,6 +11916,30 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateResourceMonitor(_ context.Context, arg database.UpdateResourceMonitorParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID != arg.AgentID || monitor.Type != arg.Type || monitor.Path != arg.Path {
+			continue
+		}
+
+		monitor.State = arg.State
+		monitor.UpdatedAt = arg.UpdatedAt
+		monitor.DebouncedUntil = arg.DebouncedUntil
+		q.workspaceAgentResourceMonitors[i] = monitor
+		return nil
+	}
+
+	return nil
+}
+
 func (*FakeQuerier) UpdateTailnetPeerStatusByCoordinator(context.Context, database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	return ErrUnimplemented
 }
@@ -11222,6 +12046,7 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.AllowUserCancelWorkspaceJobs = arg.AllowUserCancelWorkspaceJobs
 		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
 		tpl.UseClassicParameterFlow = arg.UseClassicParameterFlow
+		tpl.SessionRecordingEnabled = arg.SessionRecordingEnabled
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -11357,6 +12182,10 @@ func (q *FakeQuerier) UpdateUserDeletedByID(_ context.Context, id uuid.UUID) err
 			q.userLinks = slices.DeleteFunc(q.userLinks, func(u database.UserLink) bool {
 				return id == u.UserID
 			})
+
+			q.userSecrets = slices.DeleteFunc(q.userSecrets, func(s database.UserSecret) bool {
+				return id == s.UserID
+			})
 			return nil
 		}
 	}
@@ -11640,6 +12469,34 @@ func (q *FakeQuerier) UpdateUserRoles(_ context.Context, arg database.UpdateUser
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserSecret(_ context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.userSecrets {
+		if secret.ID != arg.ID {
+			continue
+		}
+		secret.Description = arg.Description
+		secret.Value = arg.Value
+		secret.ValueKeyID = arg.ValueKeyID
+		secret.EnvName = arg.EnvName
+		secret.FilePath = arg.FilePath
+		secret.UpdatedAt = arg.UpdatedAt
+		if err := q.userSecretUniqueViolationNoLock(secret); err != nil {
+			return database.UserSecret{}, err
+		}
+		q.userSecrets[i] = secret
+		return secret, nil
+	}
+	return database.UserSecret{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -11666,6 +12523,51 @@ func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserTOTPLastUsedStep(_ context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || totp.LastUsedStep >= arg.LastUsedStep {
+			continue
+		}
+		totp.LastUsedStep = arg.LastUsedStep
+		if !totp.VerifiedAt.Valid {
+			totp.VerifiedAt = sql.NullTime{Time: arg.UpdatedAt, Valid: true}
+		}
+		totp.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[i] = totp
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (q *FakeQuerier) UpdateUserTOTPSecret(_ context.Context, arg database.UpdateUserTOTPSecretParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID {
+			continue
+		}
+		totp.Secret = arg.Secret
+		totp.SecretKeyID = arg.SecretKeyID
+		q.userTOTPs[i] = totp
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateUserTerminalFont(ctx context.Context, arg database.UpdateUserTerminalFontParams) (database.UserConfig, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11744,6 +12646,27 @@ func (q *FakeQuerier) UpdateVolumeResourceMonitor(_ context.Context, arg databas
 	return nil
 }
 
+func (q *FakeQuerier) UpdateWebAuthnCredentialUsage(_ context.Context, arg database.UpdateWebAuthnCredentialUsageParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, credential := range q.webAuthnCredentials {
+		if credential.ID == arg.ID {
+			credential.SignCount = arg.SignCount
+			credential.BackupState = arg.BackupState
+			credential.LastUsedAt = arg.LastUsedAt
+			q.webAuthnCredentials[i] = credential
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.WorkspaceTable{}, err
@@ -11854,6 +12777,30 @@ func (q *FakeQuerier) UpdateWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentServiceStatusByID(_ context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, service := range q.workspaceAgentServices {
+		if service.ID != arg.ID || service.WorkspaceAgentID != arg.WorkspaceAgentID {
+			continue
+		}
+		service.Status = arg.Status
+		service.Health = arg.Health
+		service.Restarts = arg.Restarts
+		service.ExitCode = arg.ExitCode
+		service.UpdatedAt = arg.UpdatedAt
+		q.workspaceAgentServices[index] = service
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -12159,6 +13106,29 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, r := range q.workspaceSessionRecordings {
+		if r.ID != arg.ID {
+			continue
+		}
+		r.Size += arg.AddedSize
+		if arg.EndedAt.Valid {
+			r.EndedAt = arg.EndedAt
+		}
+		q.workspaceSessionRecordings[i] = r
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -13057,6 +14027,32 @@ TemplateUsageStatsInsertLoop:
 	return nil
 }
 
+func (q *FakeQuerier) UpsertUserTOTP(_ context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	totp := database.UserTOTP{
+		UserID:      arg.UserID,
+		Secret:      arg.Secret,
+		SecretKeyID: arg.SecretKeyID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.CreatedAt,
+	}
+	for i, existing := range q.userTOTPs {
+		if existing.UserID == arg.UserID {
+			q.userTOTPs[i] = totp
+			return totp, nil
+		}
+	}
+	q.userTOTPs = append(q.userTOTPs, totp)
+	return totp, nil
+}
+
 func (q *FakeQuerier) UpsertWebpushVAPIDKeys(_ context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -13327,7 +14323,7 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 
 	if prepared != nil {
 		// Call this to match the same function calls as the SQL implementation.
-		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWorkspaces())
 		if err != nil {
 			return nil, err
 		}
@@ -13649,7 +14645,7 @@ func (q *FakeQuerier) GetAuthorizedWorkspacesAndAgentsByOwnerID(ctx context.Cont
 
 	if prepared != nil {
 		// Call this to match the same function calls as the SQL implementation.
-		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWorkspaces())
 		if err != nil {
 			return nil, err
 		}
@@ -200,6 +200,13 @@ func (m queryMetricsStore) CountUnreadInboxNotificationsByUserID(ctx context.Con
 	return r0, r1
 }
 
+func (m queryMetricsStore) CountUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountUserMFARecoveryCodes(ctx, userID)
+	m.queryLatencies.WithLabelValues("CountUserMFARecoveryCodes").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.CustomRoles(ctx, arg)
@@ -277,6 +284,13 @@ func (m queryMetricsStore) DeleteCustomRole(ctx context.Context, arg database.De
 	return r0
 }
 
+func (m queryMetricsStore) DeleteExpiredWebAuthnChallenges(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteExpiredWebAuthnChallenges(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteExpiredWebAuthnChallenges").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
@@ -333,6 +347,13 @@ func (m queryMetricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx contex
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppDeviceCodeByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
@@ -375,6 +396,13 @@ func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) err
 	return err
 }
 
+func (m queryMetricsStore) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceSessionRecordings(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceSessionRecordings").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteOrganizationMember(ctx, arg)
@@ -438,6 +466,55 @@ func (m queryMetricsStore) DeleteTailnetTunnel(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteUnusedDynamicOAuth2ProviderApps(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteUnusedDynamicOAuth2ProviderApps(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteUnusedDynamicOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUserMFARecoveryCode(ctx context.Context, arg database.DeleteUserMFARecoveryCodeParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteUserMFARecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteUserMFARecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserMFARecoveryCodes(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserMFARecoveryCodes").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUserSecret(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserSecret(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteUserSecret").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserTOTP(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserTOTP").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWebAuthnChallenge(ctx context.Context, challenge string) (database.WebAuthnChallenge, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteWebAuthnChallenge(ctx, challenge)
+	m.queryLatencies.WithLabelValues("DeleteWebAuthnChallenge").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWebAuthnCredential(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebAuthnCredential").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg)
@@ -515,6 +592,13 @@ func (m queryMetricsStore) FetchNewMessageMetadata(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m queryMetricsStore) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	start := time.Now()
+	r0, r1 := m.s.FetchResourceMonitorsByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("FetchResourceMonitorsByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchVolumesResourceMonitorsByAgentID(ctx, agentID)
@@ -1012,6 +1096,20 @@ func (m queryMetricsStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppDeviceCodeByPrefix(ctx, secretPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppDeviceCodeByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppDeviceCodeByUserCode(ctx, userCode)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppDeviceCodeByUserCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
@@ -1033,6 +1131,13 @@ func (m queryMetricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Contex
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByAPIKeyID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
@@ -1607,6 +1712,27 @@ func (m queryMetricsStore) GetUserNotificationPreferences(ctx context.Context, u
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetUserSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSecretByUserIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserSecretByUserIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSecretsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserSecretsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserStatusCounts(ctx, arg)
@@ -1614,6 +1740,13 @@ func (m queryMetricsStore) GetUserStatusCounts(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserTOTPByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserTOTPByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserTerminalFont(ctx, userID)
@@ -1649,6 +1782,27 @@ func (m queryMetricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (
 	return users, err
 }
 
+func (m queryMetricsStore) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.WebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebAuthnCredentialByCredentialID(ctx, credentialID)
+	m.queryLatencies.WithLabelValues("GetWebAuthnCredentialByCredentialID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.WebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebAuthnCredentialByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebAuthnCredentialByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebAuthnCredentialsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetWebAuthnCredentialsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushSubscriptionsByUserID(ctx, userID)
@@ -1740,6 +1894,13 @@ func (m queryMetricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Contex
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentServicesByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentServicesByAgentIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStats(ctx, createdAt)
@@ -2006,6 +2167,27 @@ func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context
 	return resources, err
 }
 
+func (m queryMetricsStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingChunks(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingChunks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordings(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -2188,6 +2370,13 @@ func (m queryMetricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppDeviceCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppDeviceCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
@@ -2265,6 +2454,13 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return replica, err
 }
 
+func (m queryMetricsStore) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertResourceMonitor(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertResourceMonitor").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTelemetryItemIfNotExists(ctx, arg)
@@ -2342,6 +2538,20 @@ func (m queryMetricsStore) InsertUserLink(ctx context.Context, arg database.Inse
 	return link, err
 }
 
+func (m queryMetricsStore) InsertUserMFARecoveryCodes(ctx context.Context, arg database.InsertUserMFARecoveryCodesParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserMFARecoveryCodes(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserMFARecoveryCodes").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertVolumeResourceMonitor(ctx, arg)
@@ -2349,6 +2559,20 @@ func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWebAuthnChallenge(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebAuthnChallenge").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertWebAuthnCredential(ctx context.Context, arg database.InsertWebAuthnCredentialParams) (database.WebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebAuthnCredential(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebAuthnCredential").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWebpushSubscription(ctx, arg)
@@ -2412,6 +2636,13 @@ func (m queryMetricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentServices(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentServices").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAgentStats(ctx, arg)
@@ -2482,6 +2713,20 @@ func (m queryMetricsStore) InsertWorkspaceResourceMetadata(ctx context.Context,
 	return metadata, err
 }
 
+func (m queryMetricsStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecording").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecordingChunk").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListProvisionerKeysByOrganization(ctx, organizationID)
@@ -2622,6 +2867,13 @@ func (m queryMetricsStore) UpdateCustomRole(ctx context.Context, arg database.Up
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateDBCryptKeyWrappedKey(ctx context.Context, arg database.UpdateDBCryptKeyWrappedKeyParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateDBCryptKeyWrappedKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateDBCryptKeyWrappedKey").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateExternalAuthLink(ctx, arg)
@@ -2692,6 +2944,20 @@ func (m queryMetricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppDeviceCodePolledAtByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppDeviceCodeStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
@@ -2762,6 +3028,13 @@ func (m queryMetricsStore) UpdateReplica(ctx context.Context, arg database.Updat
 	return replica, err
 }
 
+func (m queryMetricsStore) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateResourceMonitor(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateResourceMonitor").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
@@ -2923,6 +3196,13 @@ func (m queryMetricsStore) UpdateUserRoles(ctx context.Context, arg database.Upd
 	return user, err
 }
 
+func (m queryMetricsStore) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserStatus(ctx, arg)
@@ -2930,6 +3210,20 @@ func (m queryMetricsStore) UpdateUserStatus(ctx context.Context, arg database.Up
 	return user, err
 }
 
+func (m queryMetricsStore) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserTOTPLastUsedStep(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserTOTPLastUsedStep").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserTOTPSecret(ctx context.Context, arg database.UpdateUserTOTPSecretParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateUserTOTPSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserTOTPSecret").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateUserTerminalFont(ctx context.Context, arg database.UpdateUserTerminalFontParams) (database.UserConfig, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserTerminalFont(ctx, arg)
@@ -2951,6 +3245,13 @@ func (m queryMetricsStore) UpdateVolumeResourceMonitor(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWebAuthnCredentialUsage(ctx context.Context, arg database.UpdateWebAuthnCredentialUsageParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWebAuthnCredentialUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebAuthnCredentialUsage").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
@@ -2986,6 +3287,13 @@ func (m queryMetricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg
 	return err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceAgentServiceStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentServiceStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentServiceStatusByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
@@ -3077,6 +3385,13 @@ func (m queryMetricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceSessionRecordingByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
@@ -3252,6 +3567,13 @@ func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	return r0
 }
 
+func (m queryMetricsStore) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserTOTP(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserTOTP").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertWebpushVAPIDKeys(ctx, arg)
@@ -277,6 +277,21 @@ func (mr *MockStoreMockRecorder) CountUnreadInboxNotificationsByUserID(ctx, user
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).CountUnreadInboxNotificationsByUserID), ctx, userID)
 }
 
+// CountUserMFARecoveryCodes mocks base method.
+func (m *MockStore) CountUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserMFARecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserMFARecoveryCodes indicates an expected call of CountUserMFARecoveryCodes.
+func (mr *MockStoreMockRecorder) CountUserMFARecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserMFARecoveryCodes", reflect.TypeOf((*MockStore)(nil).CountUserMFARecoveryCodes), ctx, userID)
+}
+
 // CustomRoles mocks base method.
 func (m *MockStore) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	m.ctrl.T.Helper()
@@ -433,6 +448,20 @@ func (mr *MockStoreMockRecorder) DeleteCustomRole(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockStore)(nil).DeleteCustomRole), ctx, arg)
 }
 
+// DeleteExpiredWebAuthnChallenges mocks base method.
+func (m *MockStore) DeleteExpiredWebAuthnChallenges(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWebAuthnChallenges", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWebAuthnChallenges indicates an expected call of DeleteExpiredWebAuthnChallenges.
+func (mr *MockStoreMockRecorder) DeleteExpiredWebAuthnChallenges(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWebAuthnChallenges", reflect.TypeOf((*MockStore)(nil).DeleteExpiredWebAuthnChallenges), ctx, beforeTime)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -546,6 +575,20 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppCodesByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppCodesByAppAndUserID), ctx, arg)
 }
 
+// DeleteOAuth2ProviderAppDeviceCodeByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppDeviceCodeByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppDeviceCodeByID indicates an expected call of DeleteOAuth2ProviderAppDeviceCodeByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppDeviceCodeByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppDeviceCodeByID), ctx, id)
+}
+
 // DeleteOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -630,6 +673,20 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(ctx any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), ctx)
 }
 
+// DeleteOldWorkspaceSessionRecordings mocks base method.
+func (m *MockStore) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceSessionRecordings", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceSessionRecordings indicates an expected call of DeleteOldWorkspaceSessionRecordings.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceSessionRecordings(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceSessionRecordings", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceSessionRecordings), ctx, beforeTime)
+}
+
 // DeleteOrganizationMember mocks base method.
 func (m *MockStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
 	m.ctrl.T.Helper()
@@ -760,6 +817,106 @@ func (mr *MockStoreMockRecorder) DeleteTailnetTunnel(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetTunnel", reflect.TypeOf((*MockStore)(nil).DeleteTailnetTunnel), ctx, arg)
 }
 
+// DeleteUnusedDynamicOAuth2ProviderApps mocks base method.
+func (m *MockStore) DeleteUnusedDynamicOAuth2ProviderApps(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnusedDynamicOAuth2ProviderApps", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnusedDynamicOAuth2ProviderApps indicates an expected call of DeleteUnusedDynamicOAuth2ProviderApps.
+func (mr *MockStoreMockRecorder) DeleteUnusedDynamicOAuth2ProviderApps(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnusedDynamicOAuth2ProviderApps", reflect.TypeOf((*MockStore)(nil).DeleteUnusedDynamicOAuth2ProviderApps), ctx, beforeTime)
+}
+
+// DeleteUserMFARecoveryCode mocks base method.
+func (m *MockStore) DeleteUserMFARecoveryCode(ctx context.Context, arg database.DeleteUserMFARecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMFARecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserMFARecoveryCode indicates an expected call of DeleteUserMFARecoveryCode.
+func (mr *MockStoreMockRecorder) DeleteUserMFARecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMFARecoveryCode", reflect.TypeOf((*MockStore)(nil).DeleteUserMFARecoveryCode), ctx, arg)
+}
+
+// DeleteUserMFARecoveryCodes mocks base method.
+func (m *MockStore) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMFARecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserMFARecoveryCodes indicates an expected call of DeleteUserMFARecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteUserMFARecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMFARecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteUserMFARecoveryCodes), ctx, userID)
+}
+
+// DeleteUserSecret mocks base method.
+func (m *MockStore) DeleteUserSecret(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSecret", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSecret indicates an expected call of DeleteUserSecret.
+func (mr *MockStoreMockRecorder) DeleteUserSecret(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSecret", reflect.TypeOf((*MockStore)(nil).DeleteUserSecret), ctx, id)
+}
+
+// DeleteUserTOTP mocks base method.
+func (m *MockStore) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTOTP indicates an expected call of DeleteUserTOTP.
+func (mr *MockStoreMockRecorder) DeleteUserTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTOTP", reflect.TypeOf((*MockStore)(nil).DeleteUserTOTP), ctx, userID)
+}
+
+// DeleteWebAuthnChallenge mocks base method.
+func (m *MockStore) DeleteWebAuthnChallenge(ctx context.Context, challenge string) (database.WebAuthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebAuthnChallenge", ctx, challenge)
+	ret0, _ := ret[0].(database.WebAuthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebAuthnChallenge indicates an expected call of DeleteWebAuthnChallenge.
+func (mr *MockStoreMockRecorder) DeleteWebAuthnChallenge(ctx, challenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebAuthnChallenge", reflect.TypeOf((*MockStore)(nil).DeleteWebAuthnChallenge), ctx, challenge)
+}
+
+// DeleteWebAuthnCredential mocks base method.
+func (m *MockStore) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebAuthnCredential", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebAuthnCredential indicates an expected call of DeleteWebAuthnCredential.
+func (mr *MockStoreMockRecorder) DeleteWebAuthnCredential(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebAuthnCredential", reflect.TypeOf((*MockStore)(nil).DeleteWebAuthnCredential), ctx, id)
+}
+
 // DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
 func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	m.ctrl.T.Helper()
@@ -917,6 +1074,21 @@ func (mr *MockStoreMockRecorder) FetchNewMessageMetadata(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNewMessageMetadata", reflect.TypeOf((*MockStore)(nil).FetchNewMessageMetadata), ctx, arg)
 }
 
+// FetchResourceMonitorsByAgentID mocks base method.
+func (m *MockStore) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchResourceMonitorsByAgentID", ctx, agentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchResourceMonitorsByAgentID indicates an expected call of FetchResourceMonitorsByAgentID.
+func (mr *MockStoreMockRecorder) FetchResourceMonitorsByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchResourceMonitorsByAgentID", reflect.TypeOf((*MockStore)(nil).FetchResourceMonitorsByAgentID), ctx, agentID)
+}
+
 // FetchVolumesResourceMonitorsByAgentID mocks base method.
 func (m *MockStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -2057,6 +2229,36 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByPrefix(ctx, secretPre
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByPrefix), ctx, secretPrefix)
 }
 
+// GetOAuth2ProviderAppDeviceCodeByPrefix mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppDeviceCodeByPrefix", ctx, secretPrefix)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppDeviceCodeByPrefix indicates an expected call of GetOAuth2ProviderAppDeviceCodeByPrefix.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx, secretPrefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppDeviceCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppDeviceCodeByPrefix), ctx, secretPrefix)
+}
+
+// GetOAuth2ProviderAppDeviceCodeByUserCode mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppDeviceCodeByUserCode", ctx, userCode)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppDeviceCodeByUserCode indicates an expected call of GetOAuth2ProviderAppDeviceCodeByUserCode.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx, userCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppDeviceCodeByUserCode", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppDeviceCodeByUserCode), ctx, userCode)
+}
+
 // GetOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -2102,6 +2304,21 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppSecretsByAppID(ctx, appID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppSecretsByAppID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppSecretsByAppID), ctx, appID)
 }
 
+// GetOAuth2ProviderAppTokenByAPIKeyID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppTokenByAPIKeyID", ctx, apiKeyID)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppTokenByAPIKeyID indicates an expected call of GetOAuth2ProviderAppTokenByAPIKeyID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppTokenByAPIKeyID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppTokenByAPIKeyID), ctx, apiKeyID)
+}
+
 // GetOAuth2ProviderAppTokenByPrefix mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
 	m.ctrl.T.Helper()
@@ -3362,6 +3579,51 @@ func (mr *MockStoreMockRecorder) GetUserNotificationPreferences(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationPreferences), ctx, userID)
 }
 
+// GetUserSecretByID mocks base method.
+func (m *MockStore) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSecretByID", ctx, id)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSecretByID indicates an expected call of GetUserSecretByID.
+func (mr *MockStoreMockRecorder) GetUserSecretByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecretByID", reflect.TypeOf((*MockStore)(nil).GetUserSecretByID), ctx, id)
+}
+
+// GetUserSecretByUserIDAndName mocks base method.
+func (m *MockStore) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSecretByUserIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSecretByUserIDAndName indicates an expected call of GetUserSecretByUserIDAndName.
+func (mr *MockStoreMockRecorder) GetUserSecretByUserIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecretByUserIDAndName", reflect.TypeOf((*MockStore)(nil).GetUserSecretByUserIDAndName), ctx, arg)
+}
+
+// GetUserSecretsByUserID mocks base method.
+func (m *MockStore) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSecretsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSecretsByUserID indicates an expected call of GetUserSecretsByUserID.
+func (mr *MockStoreMockRecorder) GetUserSecretsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecretsByUserID", reflect.TypeOf((*MockStore)(nil).GetUserSecretsByUserID), ctx, userID)
+}
+
 // GetUserStatusCounts mocks base method.
 func (m *MockStore) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	m.ctrl.T.Helper()
@@ -3377,6 +3639,21 @@ func (mr *MockStoreMockRecorder) GetUserStatusCounts(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStatusCounts", reflect.TypeOf((*MockStore)(nil).GetUserStatusCounts), ctx, arg)
 }
 
+// GetUserTOTPByUserID mocks base method.
+func (m *MockStore) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTOTPByUserID", ctx, userID)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTOTPByUserID indicates an expected call of GetUserTOTPByUserID.
+func (mr *MockStoreMockRecorder) GetUserTOTPByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTOTPByUserID", reflect.TypeOf((*MockStore)(nil).GetUserTOTPByUserID), ctx, userID)
+}
+
 // GetUserTerminalFont mocks base method.
 func (m *MockStore) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
@@ -3452,6 +3729,51 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), ctx, ids)
 }
 
+// GetWebAuthnCredentialByCredentialID mocks base method.
+func (m *MockStore) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebAuthnCredentialByCredentialID", ctx, credentialID)
+	ret0, _ := ret[0].(database.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebAuthnCredentialByCredentialID indicates an expected call of GetWebAuthnCredentialByCredentialID.
+func (mr *MockStoreMockRecorder) GetWebAuthnCredentialByCredentialID(ctx, credentialID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebAuthnCredentialByCredentialID", reflect.TypeOf((*MockStore)(nil).GetWebAuthnCredentialByCredentialID), ctx, credentialID)
+}
+
+// GetWebAuthnCredentialByID mocks base method.
+func (m *MockStore) GetWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebAuthnCredentialByID", ctx, id)
+	ret0, _ := ret[0].(database.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebAuthnCredentialByID indicates an expected call of GetWebAuthnCredentialByID.
+func (mr *MockStoreMockRecorder) GetWebAuthnCredentialByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebAuthnCredentialByID", reflect.TypeOf((*MockStore)(nil).GetWebAuthnCredentialByID), ctx, id)
+}
+
+// GetWebAuthnCredentialsByUserID mocks base method.
+func (m *MockStore) GetWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebAuthnCredentialsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebAuthnCredentialsByUserID indicates an expected call of GetWebAuthnCredentialsByUserID.
+func (mr *MockStoreMockRecorder) GetWebAuthnCredentialsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebAuthnCredentialsByUserID", reflect.TypeOf((*MockStore)(nil).GetWebAuthnCredentialsByUserID), ctx, userID)
+}
+
 // GetWebpushSubscriptionsByUserID mocks base method.
 func (m *MockStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -3647,6 +3969,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentScriptsByAgentIDs(ctx, ids any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentScriptsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentScriptsByAgentIDs), ctx, ids)
 }
 
+// GetWorkspaceAgentServicesByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentServicesByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentServicesByAgentIDs indicates an expected call of GetWorkspaceAgentServicesByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentServicesByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentServicesByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentServicesByAgentIDs), ctx, ids)
+}
+
 // GetWorkspaceAgentStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -4217,6 +4554,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(ctx, createdA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingByID indicates an expected call of GetWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingByID), ctx, id)
+}
+
+// GetWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingChunks", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingChunks indicates an expected call of GetWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingChunks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingChunks), ctx, arg)
+}
+
+// GetWorkspaceSessionRecordings mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordings", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceSessionRecordingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordings indicates an expected call of GetWorkspaceSessionRecordings.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordings", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordings), ctx, arg)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -4617,6 +4999,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppCode(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppCode), ctx, arg)
 }
 
+// InsertOAuth2ProviderAppDeviceCode mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppDeviceCode", ctx, arg)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderAppDeviceCode indicates an expected call of InsertOAuth2ProviderAppDeviceCode.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppDeviceCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppDeviceCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppDeviceCode), ctx, arg)
+}
+
 // InsertOAuth2ProviderAppSecret mocks base method.
 func (m *MockStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -4782,6 +5179,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertResourceMonitor mocks base method.
+func (m *MockStore) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertResourceMonitor", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertResourceMonitor indicates an expected call of InsertResourceMonitor.
+func (mr *MockStoreMockRecorder) InsertResourceMonitor(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertResourceMonitor", reflect.TypeOf((*MockStore)(nil).InsertResourceMonitor), ctx, arg)
+}
+
 // InsertTelemetryItemIfNotExists mocks base method.
 func (m *MockStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	m.ctrl.T.Helper()
@@ -4942,6 +5354,35 @@ func (mr *MockStoreMockRecorder) InsertUserLink(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), ctx, arg)
 }
 
+// InsertUserMFARecoveryCodes mocks base method.
+func (m *MockStore) InsertUserMFARecoveryCodes(ctx context.Context, arg database.InsertUserMFARecoveryCodesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserMFARecoveryCodes", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserMFARecoveryCodes indicates an expected call of InsertUserMFARecoveryCodes.
+func (mr *MockStoreMockRecorder) InsertUserMFARecoveryCodes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserMFARecoveryCodes", reflect.TypeOf((*MockStore)(nil).InsertUserMFARecoveryCodes), ctx, arg)
+}
+
+// InsertUserSecret mocks base method.
+func (m *MockStore) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserSecret", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserSecret indicates an expected call of InsertUserSecret.
+func (mr *MockStoreMockRecorder) InsertUserSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserSecret", reflect.TypeOf((*MockStore)(nil).InsertUserSecret), ctx, arg)
+}
+
 // InsertVolumeResourceMonitor mocks base method.
 func (m *MockStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -4957,6 +5398,35 @@ func (mr *MockStoreMockRecorder) InsertVolumeResourceMonitor(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVolumeResourceMonitor", reflect.TypeOf((*MockStore)(nil).InsertVolumeResourceMonitor), ctx, arg)
 }
 
+// InsertWebAuthnChallenge mocks base method.
+func (m *MockStore) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebAuthnChallenge", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebAuthnChallenge indicates an expected call of InsertWebAuthnChallenge.
+func (mr *MockStoreMockRecorder) InsertWebAuthnChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebAuthnChallenge", reflect.TypeOf((*MockStore)(nil).InsertWebAuthnChallenge), ctx, arg)
+}
+
+// InsertWebAuthnCredential mocks base method.
+func (m *MockStore) InsertWebAuthnCredential(ctx context.Context, arg database.InsertWebAuthnCredentialParams) (database.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebAuthnCredential", ctx, arg)
+	ret0, _ := ret[0].(database.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebAuthnCredential indicates an expected call of InsertWebAuthnCredential.
+func (mr *MockStoreMockRecorder) InsertWebAuthnCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebAuthnCredential", reflect.TypeOf((*MockStore)(nil).InsertWebAuthnCredential), ctx, arg)
+}
+
 // InsertWebpushSubscription mocks base method.
 func (m *MockStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -5091,6 +5561,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentScripts(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentScripts", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentScripts), ctx, arg)
 }
 
+// InsertWorkspaceAgentServices mocks base method.
+func (m *MockStore) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentServices", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceAgentService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentServices indicates an expected call of InsertWorkspaceAgentServices.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentServices(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentServices", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentServices), ctx, arg)
+}
+
 // InsertWorkspaceAgentStats mocks base method.
 func (m *MockStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	m.ctrl.T.Helper()
@@ -5237,6 +5722,35 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), ctx, arg)
 }
 
+// InsertWorkspaceSessionRecording mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecording", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecording indicates an expected call of InsertWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecording(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecording), ctx, arg)
+}
+
+// InsertWorkspaceSessionRecordingChunk mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecordingChunk", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceSessionRecordingChunk indicates an expected call of InsertWorkspaceSessionRecordingChunk.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecordingChunk(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecordingChunk", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecordingChunk), ctx, arg)
+}
+
 // ListProvisionerKeysByOrganization mocks base method.
 func (m *MockStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	m.ctrl.T.Helper()
@@ -5559,6 +6073,20 @@ func (mr *MockStoreMockRecorder) UpdateCustomRole(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomRole", reflect.TypeOf((*MockStore)(nil).UpdateCustomRole), ctx, arg)
 }
 
+// UpdateDBCryptKeyWrappedKey mocks base method.
+func (m *MockStore) UpdateDBCryptKeyWrappedKey(ctx context.Context, arg database.UpdateDBCryptKeyWrappedKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDBCryptKeyWrappedKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDBCryptKeyWrappedKey indicates an expected call of UpdateDBCryptKeyWrappedKey.
+func (mr *MockStoreMockRecorder) UpdateDBCryptKeyWrappedKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDBCryptKeyWrappedKey", reflect.TypeOf((*MockStore)(nil).UpdateDBCryptKeyWrappedKey), ctx, arg)
+}
+
 // UpdateExternalAuthLink mocks base method.
 func (m *MockStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -5706,6 +6234,35 @@ func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppByID(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppByID), ctx, arg)
 }
 
+// UpdateOAuth2ProviderAppDeviceCodePolledAtByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppDeviceCodePolledAtByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2ProviderAppDeviceCodePolledAtByID indicates an expected call of UpdateOAuth2ProviderAppDeviceCodePolledAtByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppDeviceCodePolledAtByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppDeviceCodePolledAtByID), ctx, arg)
+}
+
+// UpdateOAuth2ProviderAppDeviceCodeStatusByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppDeviceCodeStatusByID", ctx, arg)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOAuth2ProviderAppDeviceCodeStatusByID indicates an expected call of UpdateOAuth2ProviderAppDeviceCodeStatusByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppDeviceCodeStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppDeviceCodeStatusByID), ctx, arg)
+}
+
 // UpdateOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -5849,6 +6406,20 @@ func (mr *MockStoreMockRecorder) UpdateReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), ctx, arg)
 }
 
+// UpdateResourceMonitor mocks base method.
+func (m *MockStore) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResourceMonitor", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateResourceMonitor indicates an expected call of UpdateResourceMonitor.
+func (mr *MockStoreMockRecorder) UpdateResourceMonitor(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceMonitor", reflect.TypeOf((*MockStore)(nil).UpdateResourceMonitor), ctx, arg)
+}
+
 // UpdateTailnetPeerStatusByCoordinator mocks base method.
 func (m *MockStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	m.ctrl.T.Helper()
@@ -6179,6 +6750,21 @@ func (mr *MockStoreMockRecorder) UpdateUserRoles(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRoles", reflect.TypeOf((*MockStore)(nil).UpdateUserRoles), ctx, arg)
 }
 
+// UpdateUserSecret mocks base method.
+func (m *MockStore) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserSecret", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserSecret indicates an expected call of UpdateUserSecret.
+func (mr *MockStoreMockRecorder) UpdateUserSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSecret", reflect.TypeOf((*MockStore)(nil).UpdateUserSecret), ctx, arg)
+}
+
 // UpdateUserStatus mocks base method.
 func (m *MockStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -6194,6 +6780,35 @@ func (mr *MockStoreMockRecorder) UpdateUserStatus(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockStore)(nil).UpdateUserStatus), ctx, arg)
 }
 
+// UpdateUserTOTPLastUsedStep mocks base method.
+func (m *MockStore) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPLastUsedStep", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTOTPLastUsedStep indicates an expected call of UpdateUserTOTPLastUsedStep.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPLastUsedStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPLastUsedStep", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPLastUsedStep), ctx, arg)
+}
+
+// UpdateUserTOTPSecret mocks base method.
+func (m *MockStore) UpdateUserTOTPSecret(ctx context.Context, arg database.UpdateUserTOTPSecretParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPSecret", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserTOTPSecret indicates an expected call of UpdateUserTOTPSecret.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPSecret", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPSecret), ctx, arg)
+}
+
 // UpdateUserTerminalFont mocks base method.
 func (m *MockStore) UpdateUserTerminalFont(ctx context.Context, arg database.UpdateUserTerminalFontParams) (database.UserConfig, error) {
 	m.ctrl.T.Helper()
@@ -6238,6 +6853,20 @@ func (mr *MockStoreMockRecorder) UpdateVolumeResourceMonitor(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumeResourceMonitor", reflect.TypeOf((*MockStore)(nil).UpdateVolumeResourceMonitor), ctx, arg)
 }
 
+// UpdateWebAuthnCredentialUsage mocks base method.
+func (m *MockStore) UpdateWebAuthnCredentialUsage(ctx context.Context, arg database.UpdateWebAuthnCredentialUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebAuthnCredentialUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebAuthnCredentialUsage indicates an expected call of UpdateWebAuthnCredentialUsage.
+func (mr *MockStoreMockRecorder) UpdateWebAuthnCredentialUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebAuthnCredentialUsage", reflect.TypeOf((*MockStore)(nil).UpdateWebAuthnCredentialUsage), ctx, arg)
+}
+
 // UpdateWorkspace mocks base method.
 func (m *MockStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	m.ctrl.T.Helper()
@@ -6309,6 +6938,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentMetadata(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentMetadata), ctx, arg)
 }
 
+// UpdateWorkspaceAgentServiceStatusByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentServiceStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentServiceStatusByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentServiceStatusByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentServiceStatusByID indicates an expected call of UpdateWorkspaceAgentServiceStatusByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentServiceStatusByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentServiceStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentServiceStatusByID), ctx, arg)
+}
+
 // UpdateWorkspaceAgentStartupByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6493,6 +7136,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), ctx, arg)
 }
 
+// UpdateWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSessionRecordingByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceSessionRecordingByID indicates an expected call of UpdateWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSessionRecordingByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSessionRecordingByID), ctx, arg)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -6850,6 +7507,21 @@ func (mr *MockStoreMockRecorder) UpsertTemplateUsageStats(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUsageStats), ctx)
 }
 
+// UpsertUserTOTP mocks base method.
+func (m *MockStore) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserTOTP", ctx, arg)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserTOTP indicates an expected call of UpsertUserTOTP.
+func (mr *MockStoreMockRecorder) UpsertUserTOTP(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserTOTP", reflect.TypeOf((*MockStore)(nil).UpsertUserTOTP), ctx, arg)
+}
+
 // UpsertWebpushVAPIDKeys mocks base method.
 func (m *MockStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	m.ctrl.T.Helper()
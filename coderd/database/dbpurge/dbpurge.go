@@ -12,6 +12,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/quartz"
 )
 
@@ -24,7 +25,7 @@ const (
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, vals *codersdk.DeploymentValues, clk quartz.Clock) io.Closer {
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
@@ -62,6 +63,25 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 			if err := tx.DeleteOldNotificationMessages(ctx); err != nil {
 				return xerrors.Errorf("failed to delete old notification messages: %w", err)
 			}
+			if err := tx.DeleteExpiredWebAuthnChallenges(ctx, start); err != nil {
+				return xerrors.Errorf("failed to delete expired webauthn challenges: %w", err)
+			}
+			// A retention of 0 keeps recordings forever.
+			if retention := vals.SessionRecordingRetention.Value(); retention > 0 {
+				deleteOldWorkspaceSessionRecordingsBefore := start.Add(-retention)
+				if err := tx.DeleteOldWorkspaceSessionRecordings(ctx, deleteOldWorkspaceSessionRecordingsBefore); err != nil {
+					return xerrors.Errorf("failed to delete old workspace session recordings: %w", err)
+				}
+			}
+			// Dynamically registered apps that have not completed a grant
+			// by then are unlikely to ever be used. A lifetime of 0 keeps
+			// them forever.
+			if lifetime := vals.OAuth2.Provider.DynamicRegistrationUnusedClientLifetime.Value(); lifetime > 0 {
+				deleteUnusedDynamicOAuth2AppsBefore := start.Add(-lifetime)
+				if err := tx.DeleteUnusedDynamicOAuth2ProviderApps(ctx, deleteUnusedDynamicOAuth2AppsBefore); err != nil {
+					return xerrors.Errorf("failed to delete unused dynamically registered oauth2 apps: %w", err)
+				}
+			}
 
 			logger.Debug(ctx, "purged old database entries", slog.F("duration", clk.Since(start)))
 
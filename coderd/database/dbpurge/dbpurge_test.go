@@ -31,6 +31,7 @@ import (
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
 	"github.com/coder/quartz"
+	"github.com/coder/serpent"
 )
 
 func TestMain(m *testing.M) {
@@ -47,7 +48,7 @@ func TestPurge(t *testing.T) {
 	// We want to make sure dbpurge is actually started so that this test is meaningful.
 	clk := quartz.NewMock(t)
 	done := awaitDoTick(ctx, t, clk)
-	purger := dbpurge.New(context.Background(), testutil.Logger(t), dbmem.New(), clk)
+	purger := dbpurge.New(context.Background(), testutil.Logger(t), dbmem.New(), &codersdk.DeploymentValues{}, clk)
 	<-done // wait for doTick() to run.
 	require.NoError(t, purger.Close())
 }
@@ -125,7 +126,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	})
 
 	// when
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, clk)
 	defer closer.Close()
 
 	// then
@@ -150,7 +151,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 
 	// Start a new purger to immediately trigger delete after rollup.
 	_ = closer.Close()
-	closer = dbpurge.New(ctx, logger, db, clk)
+	closer = dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, clk)
 	defer closer.Close()
 
 	// then
@@ -241,7 +242,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 	// After dbpurge completes, the ticker is reset. Trap this call.
 
 	done := awaitDoTick(ctx, t, clk)
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, clk)
 	defer closer.Close()
 	<-done // doTick() has now run.
 
@@ -267,6 +268,156 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 	assertWorkspaceAgentLogs(ctx, t, db, agentE1.ID, "agent e1 logs should be retained")
 }
 
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceSessionRecordings(t *testing.T) {
+	//nolint:paralleltest // It uses LockIDDBPurge.
+	t.Run("Retention", func(t *testing.T) {
+		// Given: recordings are kept for 30 days.
+		oldRecording, newRecording, db := purgeWorkspaceSessionRecordings(t, 30*24*time.Hour)
+
+		// Then: only the recording older than that is deleted.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := db.GetWorkspaceSessionRecordingByID(ctx, oldRecording.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = db.GetWorkspaceSessionRecordingByID(ctx, newRecording.ID)
+		require.NoError(t, err)
+	})
+
+	//nolint:paralleltest // It uses LockIDDBPurge.
+	t.Run("KeepForever", func(t *testing.T) {
+		// Given: retention is disabled.
+		oldRecording, newRecording, db := purgeWorkspaceSessionRecordings(t, 0)
+
+		// Then: no recording is deleted.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := db.GetWorkspaceSessionRecordingByID(ctx, oldRecording.ID)
+		require.NoError(t, err)
+		_, err = db.GetWorkspaceSessionRecordingByID(ctx, newRecording.ID)
+		require.NoError(t, err)
+	})
+}
+
+// purgeWorkspaceSessionRecordings creates a recording started 31 days ago and
+// one started 29 days ago, and runs dbpurge with the given retention.
+func purgeWorkspaceSessionRecordings(t *testing.T, retention time.Duration) (database.WorkspaceSessionRecording, database.WorkspaceSessionRecording, database.Store) {
+	t.Helper()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{OwnerID: user.ID, OrganizationID: org.ID, TemplateID: tmpl.ID})
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	oldRecording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		OrganizationID: org.ID,
+		WorkspaceID:    ws.ID,
+		OwnerID:        user.ID,
+		StartedAt:      now.Add(-31 * 24 * time.Hour),
+	})
+	newRecording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		OrganizationID: org.ID,
+		WorkspaceID:    ws.ID,
+		OwnerID:        user.ID,
+		StartedAt:      now.Add(-29 * 24 * time.Hour),
+	})
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{
+		SessionRecordingRetention: serpent.Duration(retention),
+	}, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	return oldRecording, newRecording, db
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteUnusedDynamicOAuth2ProviderApps(t *testing.T) {
+	//nolint:paralleltest // It uses LockIDDBPurge.
+	t.Run("Lifetime", func(t *testing.T) {
+		// Given: unused clients are kept for 2 hours.
+		unused, others, db := purgeDynamicOAuth2ProviderApps(t, 2*time.Hour)
+
+		// Then: only the old, unused registration is deleted.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := db.GetOAuth2ProviderAppByID(ctx, unused.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		for _, app := range others {
+			_, err = db.GetOAuth2ProviderAppByID(ctx, app.ID)
+			require.NoError(t, err, app.Name)
+		}
+	})
+
+	//nolint:paralleltest // It uses LockIDDBPurge.
+	t.Run("KeepForever", func(t *testing.T) {
+		// Given: the purge is disabled.
+		unused, others, db := purgeDynamicOAuth2ProviderApps(t, 0)
+
+		// Then: no app is deleted.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, app := range append(others, unused) {
+			_, err := db.GetOAuth2ProviderAppByID(ctx, app.ID)
+			require.NoError(t, err, app.Name)
+		}
+	})
+}
+
+// purgeDynamicOAuth2ProviderApps creates OAuth2 apps around a threshold 2
+// hours ago, runs dbpurge with the given unused client lifetime and returns
+// the old unused registration and the other apps.
+func purgeDynamicOAuth2ProviderApps(t *testing.T, lifetime time.Duration) (database.OAuth2ProviderApp, []database.OAuth2ProviderApp, database.Store) {
+	t.Helper()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	threshold := now.Add(-2 * time.Hour)
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	user := dbgen.User(t, db, database.User{})
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Given: dynamically registered apps from before the threshold with and
+	// without a token, one from after the threshold, and an app created by an
+	// administrator that was never used.
+	unused := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{
+		CreatedAt:             threshold.Add(-time.Hour),
+		DynamicallyRegistered: true,
+	})
+	used := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{
+		CreatedAt:             threshold.Add(-time.Hour),
+		DynamicallyRegistered: true,
+	})
+	key, _ := dbgen.APIKey(t, db, database.APIKey{UserID: user.ID})
+	_ = dbgen.OAuth2ProviderAppToken(t, db, database.OAuth2ProviderAppToken{
+		AppID:    used.ID,
+		APIKeyID: key.ID,
+	})
+	recent := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{
+		CreatedAt:             threshold.Add(time.Hour),
+		DynamicallyRegistered: true,
+	})
+	manual := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{
+		CreatedAt: threshold.Add(-time.Hour),
+	})
+
+	// When: dbpurge runs.
+	dv := &codersdk.DeploymentValues{}
+	dv.OAuth2.Provider.DynamicRegistrationUnusedClientLifetime = serpent.Duration(lifetime)
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, dv, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	return unused, []database.OAuth2ProviderApp{used, recent, manual}, db
+}
+
 func awaitDoTick(ctx context.Context, t *testing.T, clk *quartz.Mock) chan struct{} {
 	t.Helper()
 	ch := make(chan struct{})
@@ -462,7 +613,7 @@ func TestDeleteOldProvisionerDaemons(t *testing.T) {
 	require.NoError(t, err)
 
 	// when
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, clk)
 	defer closer.Close()
 
 	// then
@@ -12,7 +12,8 @@ CREATE TYPE agent_key_scope_enum AS ENUM (
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'custom'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -96,7 +97,9 @@ CREATE TYPE login_type AS ENUM (
     'oidc',
     'token',
     'none',
-    'oauth2_provider_app'
+    'oauth2_provider_app',
+    'ldap',
+    'saml'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
@@ -126,6 +129,17 @@ CREATE TYPE notification_template_kind AS ENUM (
     'system'
 );
 
+CREATE TYPE oauth2_provider_app_client_type AS ENUM (
+    'confidential',
+    'public'
+);
+
+CREATE TYPE oauth2_provider_app_device_code_status AS ENUM (
+    'pending',
+    'approved',
+    'denied'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -241,7 +255,17 @@ CREATE TYPE resource_type AS ENUM (
     'idp_sync_settings_group',
     'idp_sync_settings_role',
     'workspace_agent',
-    'workspace_app'
+    'workspace_app',
+    'webauthn_credential',
+    'user_secret',
+    'workspace_session_recording'
+);
+
+CREATE TYPE session_recording_type AS ENUM (
+    'ssh',
+    'vscode',
+    'jetbrains',
+    'reconnecting_pty'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -283,6 +307,13 @@ CREATE TYPE workspace_agent_monitor_state AS ENUM (
     'NOK'
 );
 
+CREATE TYPE workspace_agent_resource_monitor_type AS ENUM (
+    'cpu',
+    'load',
+    'inodes',
+    'processes'
+);
+
 CREATE TYPE workspace_agent_script_timing_stage AS ENUM (
     'start',
     'stop',
@@ -300,6 +331,21 @@ CREATE TYPE workspace_agent_script_timing_status AS ENUM (
 
 COMMENT ON TYPE workspace_agent_script_timing_status IS 'What the exit status of the script is.';
 
+CREATE TYPE workspace_agent_service_restart_policy AS ENUM (
+    'always',
+    'on_failure',
+    'never'
+);
+
+CREATE TYPE workspace_agent_service_status AS ENUM (
+    'pending',
+    'starting',
+    'running',
+    'restarting',
+    'stopped',
+    'failed'
+);
+
 CREATE TYPE workspace_agent_subsystem AS ENUM (
     'envbuilder',
     'envbox',
@@ -414,6 +460,10 @@ BEGIN
 		-- email if the account is undeleted. Although that is not a guarantee.
 		DELETE FROM user_links
 		WHERE user_id = OLD.id;
+
+		-- Remove their user_secrets
+		DELETE FROM user_secrets
+		WHERE user_id = OLD.id;
 	END IF;
 	RETURN NEW;
 END;
@@ -796,11 +846,17 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scope_permissions text[] DEFAULT '{}'::text[] NOT NULL,
+    scope_allow_list text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scope_permissions IS 'The permissions granted to a key with the custom scope, in the form "<resource>:<action>".';
+
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'The objects a key with the custom scope is limited to, in the form "<resource>:<id>". Empty means no limit.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -878,7 +934,9 @@ CREATE TABLE dbcrypt_keys (
     revoked_key_digest text,
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
     revoked_at timestamp with time zone,
-    test text NOT NULL
+    test text NOT NULL,
+    wrapped_key text,
+    wrapping_key_id text
 );
 
 COMMENT ON TABLE dbcrypt_keys IS 'A table used to store the keys used to encrypt the database.';
@@ -895,6 +953,10 @@ COMMENT ON COLUMN dbcrypt_keys.revoked_at IS 'The time at which the key was revo
 
 COMMENT ON COLUMN dbcrypt_keys.test IS 'A column used to test the encryption.';
 
+COMMENT ON COLUMN dbcrypt_keys.wrapped_key IS 'If the key is a data key managed by a key provider, the base64-encoded data key wrapped by the key provider.';
+
+COMMENT ON COLUMN dbcrypt_keys.wrapping_key_id IS 'If the key is a data key managed by a key provider, the ID of the key provider key that wrapped it.';
+
 CREATE TABLE external_auth_links (
     provider_id text NOT NULL,
     user_id uuid NOT NULL,
@@ -1127,11 +1189,38 @@ CREATE TABLE oauth2_provider_app_codes (
     secret_prefix bytea NOT NULL,
     hashed_secret bytea NOT NULL,
     user_id uuid NOT NULL,
-    app_id uuid NOT NULL
+    app_id uuid NOT NULL,
+    code_challenge text DEFAULT ''::text NOT NULL,
+    code_challenge_method text DEFAULT ''::text NOT NULL,
+    scope text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON TABLE oauth2_provider_app_codes IS 'Codes are meant to be exchanged for access tokens.';
 
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE code challenge, empty if the client did not use PKCE.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.scope IS 'The space-delimited scope requested with the authorization request, empty for the same access as the user.';
+
+CREATE TABLE oauth2_provider_app_device_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    last_polled_at timestamp with time zone,
+    secret_prefix bytea NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_code text NOT NULL,
+    app_id uuid NOT NULL,
+    user_id uuid,
+    status oauth2_provider_app_device_code_status DEFAULT 'pending'::oauth2_provider_app_device_code_status NOT NULL,
+    scope text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_device_codes IS 'Device codes are exchanged for access tokens once a user approves them with the user code (RFC 8628).';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_id IS 'The user that approved or denied the code.';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.scope IS 'The space-delimited scope requested with the device authorization request, empty for the same access as the user.';
+
 CREATE TABLE oauth2_provider_app_secrets (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1150,8 +1239,9 @@ CREATE TABLE oauth2_provider_app_tokens (
     expires_at timestamp with time zone NOT NULL,
     hash_prefix bytea NOT NULL,
     refresh_hash bytea NOT NULL,
-    app_secret_id uuid NOT NULL,
-    api_key_id text NOT NULL
+    app_secret_id uuid,
+    api_key_id text NOT NULL,
+    app_id uuid NOT NULL
 );
 
 COMMENT ON COLUMN oauth2_provider_app_tokens.refresh_hash IS 'Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.';
@@ -1162,11 +1252,20 @@ CREATE TABLE oauth2_provider_apps (
     updated_at timestamp with time zone NOT NULL,
     name character varying(64) NOT NULL,
     icon character varying(256) NOT NULL,
-    callback_url text NOT NULL
+    callback_url text NOT NULL,
+    client_type oauth2_provider_app_client_type DEFAULT 'confidential'::oauth2_provider_app_client_type NOT NULL,
+    service_account_id uuid,
+    dynamically_registered boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON TABLE oauth2_provider_apps IS 'A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.';
 
+COMMENT ON COLUMN oauth2_provider_apps.client_type IS 'Public clients cannot keep a secret, so they must use PKCE and cannot use the client credentials grant.';
+
+COMMENT ON COLUMN oauth2_provider_apps.service_account_id IS 'The user that tokens issued by the client credentials grant act as. The grant is disabled when null.';
+
+COMMENT ON COLUMN oauth2_provider_apps.dynamically_registered IS 'Apps registered with dynamic client registration (RFC 7591) can be created without an account, so they are deleted if they never complete a grant.';
+
 CREATE TABLE organizations (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1626,7 +1725,8 @@ CREATE TABLE templates (
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
     max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
-    use_classic_parameter_flow boolean DEFAULT false NOT NULL
+    use_classic_parameter_flow boolean DEFAULT false NOT NULL,
+    session_recording_enabled boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -1649,6 +1749,8 @@ COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the tem
 
 COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.';
 
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Record the output of interactive sessions in workspaces created from this template.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -1679,6 +1781,7 @@ CREATE VIEW template_with_names AS
     templates.activity_bump,
     templates.max_port_sharing_level,
     templates.use_classic_parameter_flow,
+    templates.session_recording_enabled,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name,
@@ -1723,6 +1826,37 @@ COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key us
 
 COMMENT ON COLUMN user_links.claims IS 'Claims from the IDP for the linked user. Includes both id_token and userinfo claims. ';
 
+CREATE TABLE user_mfa_recovery_codes (
+    user_id uuid NOT NULL,
+    hashed_code bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use recovery codes that can be used in place of a TOTP code.';
+
+COMMENT ON COLUMN user_mfa_recovery_codes.hashed_code IS 'SHA256 hash of the recovery code.';
+
+CREATE TABLE user_secrets (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    env_name text DEFAULT ''::text NOT NULL,
+    file_path text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_secrets IS 'Secrets owned by users that are delivered to their workspaces by the agent.';
+
+COMMENT ON COLUMN user_secrets.value_key_id IS 'The ID of the key used to encrypt the secret value. If this is NULL, the value is not encrypted';
+
+COMMENT ON COLUMN user_secrets.env_name IS 'Name of the environment variable the secret is exposed as in workspaces. Empty if not exposed as an environment variable.';
+
+COMMENT ON COLUMN user_secrets.file_path IS 'Path of the file the secret is written to in workspaces. Empty if not written to a file.';
+
 CREATE TABLE user_status_changes (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -1732,6 +1866,60 @@ CREATE TABLE user_status_changes (
 
 COMMENT ON TABLE user_status_changes IS 'Tracks the history of user status changes';
 
+CREATE TABLE user_totp (
+    user_id uuid NOT NULL,
+    secret text NOT NULL,
+    secret_key_id text,
+    verified_at timestamp with time zone,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_totp IS 'TOTP second factors of password authenticated users.';
+
+COMMENT ON COLUMN user_totp.secret IS 'Base32 encoded TOTP secret shared with the authenticator app.';
+
+COMMENT ON COLUMN user_totp.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted';
+
+COMMENT ON COLUMN user_totp.verified_at IS 'Time the user confirmed enrollment with a valid code. The second factor is only enforced once verified.';
+
+COMMENT ON COLUMN user_totp.last_used_step IS 'Time step of the last accepted code. Codes for this or an earlier step are rejected to prevent replay.';
+
+CREATE TABLE webauthn_challenges (
+    challenge text NOT NULL,
+    session jsonb NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE webauthn_challenges IS 'Pending WebAuthn registration and login ceremonies. Each challenge can only be used once.';
+
+COMMENT ON COLUMN webauthn_challenges.session IS 'Session data of the ceremony that is needed to validate the response of the authenticator.';
+
+CREATE TABLE webauthn_credentials (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    credential_id bytea NOT NULL,
+    public_key bytea NOT NULL,
+    attestation_type text NOT NULL,
+    transports text[] DEFAULT '{}'::text[] NOT NULL,
+    aaguid bytea NOT NULL,
+    sign_count bigint DEFAULT 0 NOT NULL,
+    backup_eligible boolean DEFAULT false NOT NULL,
+    backup_state boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone
+);
+
+COMMENT ON TABLE webauthn_credentials IS 'WebAuthn credentials (passkeys and security keys) registered by users.';
+
+COMMENT ON COLUMN webauthn_credentials.credential_id IS 'Credential ID chosen by the authenticator.';
+
+COMMENT ON COLUMN webauthn_credentials.public_key IS 'COSE encoded public key of the credential.';
+
+COMMENT ON COLUMN webauthn_credentials.sign_count IS 'Signature counter of the authenticator. A counter that does not increase indicates a cloned authenticator.';
+
 CREATE TABLE webpush_subscriptions (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -1814,6 +2002,24 @@ CREATE TABLE workspace_agent_port_share (
     protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
 );
 
+CREATE TABLE workspace_agent_resource_monitors (
+    agent_id uuid NOT NULL,
+    type workspace_agent_resource_monitor_type NOT NULL,
+    path text DEFAULT ''::text NOT NULL,
+    enabled boolean NOT NULL,
+    threshold integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    state workspace_agent_monitor_state DEFAULT 'OK'::workspace_agent_monitor_state NOT NULL,
+    debounced_until timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_resource_monitors IS 'Monitors of workspace agent resources other than memory and volume space.';
+
+COMMENT ON COLUMN workspace_agent_resource_monitors.path IS 'The volume of inodes monitors, empty for other types.';
+
+COMMENT ON COLUMN workspace_agent_resource_monitors.threshold IS 'The percentage of CPU or inodes used, the 1 minute load average as a percentage of the number of cores, or the number of processes.';
+
 CREATE TABLE workspace_agent_script_timings (
     script_id uuid NOT NULL,
     started_at timestamp with time zone NOT NULL,
@@ -1838,6 +2044,34 @@ CREATE TABLE workspace_agent_scripts (
     id uuid DEFAULT gen_random_uuid() NOT NULL
 );
 
+CREATE TABLE workspace_agent_services (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    log_source_id uuid NOT NULL,
+    log_path text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    icon text NOT NULL,
+    command text NOT NULL,
+    restart_policy workspace_agent_service_restart_policy NOT NULL,
+    restart_delay_seconds integer NOT NULL,
+    readiness_url text NOT NULL,
+    readiness_interval_seconds integer NOT NULL,
+    readiness_threshold integer NOT NULL,
+    status workspace_agent_service_status DEFAULT 'pending'::workspace_agent_service_status NOT NULL,
+    health workspace_app_health DEFAULT 'disabled'::workspace_app_health NOT NULL,
+    restarts integer DEFAULT 0 NOT NULL,
+    exit_code integer,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_services IS 'Long-running processes defined by the template that are supervised by the workspace agent.';
+
+COMMENT ON COLUMN workspace_agent_services.restarts IS 'The number of times the agent restarted the service since the agent started.';
+
+COMMENT ON COLUMN workspace_agent_services.exit_code IS 'The exit code of the last run of the service, null if it has not exited.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -2243,7 +2477,8 @@ CREATE TABLE workspace_proxies (
     region_id integer NOT NULL,
     derp_enabled boolean DEFAULT true NOT NULL,
     derp_only boolean DEFAULT false NOT NULL,
-    version text DEFAULT ''::text NOT NULL
+    version text DEFAULT ''::text NOT NULL,
+    derp_quic_port integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN workspace_proxies.icon IS 'Expects an emoji character. (/emojis/1f1fa-1f1f8.png)';
@@ -2258,6 +2493,8 @@ COMMENT ON COLUMN workspace_proxies.token_hashed_secret IS 'Hashed secret is use
 
 COMMENT ON COLUMN workspace_proxies.derp_only IS 'Disables app/terminal proxying for this proxy and only acts as a DERP relay.';
 
+COMMENT ON COLUMN workspace_proxies.derp_quic_port IS 'UDP port the proxy accepts DERP over QUIC connections on. 0 if DERP over QUIC is disabled.';
+
 CREATE SEQUENCE workspace_proxies_region_id_seq
     AS integer
     START WITH 1
@@ -2285,6 +2522,40 @@ CREATE SEQUENCE workspace_resource_metadata_id_seq
 
 ALTER SEQUENCE workspace_resource_metadata_id_seq OWNED BY workspace_resource_metadata.id;
 
+CREATE TABLE workspace_session_recording_chunks (
+    recording_id uuid NOT NULL,
+    sequence integer NOT NULL,
+    data bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'Chunks of session recordings. Concatenating the chunks of a recording in sequence order yields an asciicast v2 file.';
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    type session_recording_type NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    size bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of the output of interactive sessions in workspaces, in the asciicast v2 format.';
+
+COMMENT ON COLUMN workspace_session_recordings.id IS 'Generated by the agent, so that chunks can be uploaded without a round trip.';
+
+COMMENT ON COLUMN workspace_session_recordings.agent_id IS 'Not a foreign key, so that recordings outlive the builds of their workspace.';
+
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'NULL while the session is in progress, or if the agent never sent the final chunk.';
+
+COMMENT ON COLUMN workspace_session_recordings.size IS 'Total size of the chunks in bytes.';
+
 CREATE VIEW workspaces_expanded AS
  SELECT workspaces.id,
     workspaces.created_at,
@@ -2418,6 +2689,15 @@ ALTER TABLE ONLY oauth2_provider_app_codes
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
 
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_secret_prefix_key UNIQUE (secret_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_user_code_key UNIQUE (user_code);
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
 
@@ -2529,12 +2809,33 @@ ALTER TABLE ONLY user_deleted
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_pkey PRIMARY KEY (user_id, hashed_code);
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_user_id_name_key UNIQUE (user_id, name);
+
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webauthn_challenges
+    ADD CONSTRAINT webauthn_challenges_pkey PRIMARY KEY (challenge);
+
+ALTER TABLE ONLY webauthn_credentials
+    ADD CONSTRAINT webauthn_credentials_credential_id_key UNIQUE (credential_id);
+
+ALTER TABLE ONLY webauthn_credentials
+    ADD CONSTRAINT webauthn_credentials_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
 
@@ -2553,12 +2854,21 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
+ALTER TABLE ONLY workspace_agent_resource_monitors
+    ADD CONSTRAINT workspace_agent_resource_monitors_pkey PRIMARY KEY (agent_id, type, path);
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
 
+ALTER TABLE ONLY workspace_agent_services
+    ADD CONSTRAINT workspace_agent_services_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_services
+    ADD CONSTRAINT workspace_agent_services_workspace_agent_id_name_key UNIQUE (workspace_agent_id, name);
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -2616,6 +2926,12 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (recording_id, sequence);
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
@@ -2701,10 +3017,16 @@ CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree
 
 CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
 
+CREATE UNIQUE INDEX user_secrets_user_env_name_idx ON user_secrets USING btree (user_id, env_name) WHERE (env_name <> ''::text);
+
+CREATE UNIQUE INDEX user_secrets_user_file_path_idx ON user_secrets USING btree (user_id, file_path) WHERE (file_path <> ''::text);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webauthn_credentials_user_id_idx ON webauthn_credentials USING btree (user_id);
+
 CREATE INDEX workspace_agent_devcontainers_workspace_agent_id ON workspace_agent_devcontainers USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_devcontainers_workspace_agent_id IS 'Workspace agent foreign key and query index';
@@ -2737,6 +3059,10 @@ CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
+CREATE INDEX workspace_session_recordings_started_at_idx ON workspace_session_recordings USING btree (started_at);
+
+CREATE INDEX workspace_session_recordings_workspace_id_started_at_idx ON workspace_session_recordings USING btree (workspace_id, started_at DESC);
+
 CREATE INDEX workspace_template_id_idx ON workspaces USING btree (template_id) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
@@ -2895,15 +3221,27 @@ ALTER TABLE ONLY oauth2_provider_app_codes
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_service_account_id_fkey FOREIGN KEY (service_account_id) REFERENCES users(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -2997,9 +3335,27 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webauthn_credentials
+    ADD CONSTRAINT webauthn_credentials_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
@@ -3018,12 +3374,18 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_resource_monitors
+    ADD CONSTRAINT workspace_agent_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_services
+    ADD CONSTRAINT workspace_agent_services_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -3084,6 +3446,18 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
@@ -0,0 +1,25 @@
+CREATE TABLE user_totp (
+    user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    secret_key_id text REFERENCES dbcrypt_keys(active_key_digest),
+    verified_at timestamp with time zone,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_totp IS 'TOTP second factors of password authenticated users.';
+COMMENT ON COLUMN user_totp.secret IS 'Base32 encoded TOTP secret shared with the authenticator app.';
+COMMENT ON COLUMN user_totp.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted';
+COMMENT ON COLUMN user_totp.verified_at IS 'Time the user confirmed enrollment with a valid code. The second factor is only enforced once verified.';
+COMMENT ON COLUMN user_totp.last_used_step IS 'Time step of the last accepted code. Codes for this or an earlier step are rejected to prevent replay.';
+
+CREATE TABLE user_mfa_recovery_codes (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashed_code bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, hashed_code)
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use recovery codes that can be used in place of a TOTP code.';
+COMMENT ON COLUMN user_mfa_recovery_codes.hashed_code IS 'SHA256 hash of the recovery code.';
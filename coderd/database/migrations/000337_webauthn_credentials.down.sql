@@ -0,0 +1,3 @@
+-- Enum values can't be dropped, so resource_type is left unchanged.
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,33 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'webauthn_credential';
+
+CREATE TABLE webauthn_credentials (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    credential_id bytea NOT NULL UNIQUE,
+    public_key bytea NOT NULL,
+    attestation_type text NOT NULL,
+    transports text[] NOT NULL DEFAULT '{}'::text[],
+    aaguid bytea NOT NULL,
+    sign_count bigint NOT NULL DEFAULT 0,
+    backup_eligible boolean NOT NULL DEFAULT false,
+    backup_state boolean NOT NULL DEFAULT false,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone
+);
+
+CREATE INDEX webauthn_credentials_user_id_idx ON webauthn_credentials (user_id);
+
+COMMENT ON TABLE webauthn_credentials IS 'WebAuthn credentials (passkeys and security keys) registered by users.';
+COMMENT ON COLUMN webauthn_credentials.credential_id IS 'Credential ID chosen by the authenticator.';
+COMMENT ON COLUMN webauthn_credentials.public_key IS 'COSE encoded public key of the credential.';
+COMMENT ON COLUMN webauthn_credentials.sign_count IS 'Signature counter of the authenticator. A counter that does not increase indicates a cloned authenticator.';
+
+CREATE TABLE webauthn_challenges (
+    challenge text PRIMARY KEY,
+    session jsonb NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE webauthn_challenges IS 'Pending WebAuthn registration and login ceremonies. Each challenge can only be used once.';
+COMMENT ON COLUMN webauthn_challenges.session IS 'Session data of the ceremony that is needed to validate the response of the authenticator.';
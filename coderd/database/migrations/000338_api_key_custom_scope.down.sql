@@ -0,0 +1,7 @@
+-- The 'custom' value cannot be removed from the enum, but keys using it
+-- would be unusable without the permission columns.
+DELETE FROM api_keys WHERE scope = 'custom';
+
+ALTER TABLE api_keys
+	DROP COLUMN scope_allow_list,
+	DROP COLUMN scope_permissions;
@@ -0,0 +1,8 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'custom';
+
+ALTER TABLE api_keys
+	ADD COLUMN scope_permissions text[] DEFAULT '{}'::text[] NOT NULL,
+	ADD COLUMN scope_allow_list text[] DEFAULT '{}'::text[] NOT NULL;
+
+COMMENT ON COLUMN api_keys.scope_permissions IS 'The permissions granted to a key with the custom scope, in the form "<resource>:<action>".';
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'The objects a key with the custom scope is limited to, in the form "<resource>:<id>". Empty means no limit.';
@@ -0,0 +1,19 @@
+DROP TABLE oauth2_provider_app_device_codes;
+DROP TYPE oauth2_provider_app_device_code_status;
+
+-- Tokens issued to public clients cannot be kept without a secret.
+DELETE FROM oauth2_provider_app_tokens WHERE app_secret_id IS NULL;
+
+ALTER TABLE oauth2_provider_app_tokens
+	ALTER COLUMN app_secret_id SET NOT NULL,
+	DROP COLUMN app_id;
+
+ALTER TABLE oauth2_provider_app_codes
+	DROP COLUMN code_challenge_method,
+	DROP COLUMN code_challenge;
+
+ALTER TABLE oauth2_provider_apps
+	DROP COLUMN service_account_id,
+	DROP COLUMN client_type;
+
+DROP TYPE oauth2_provider_app_client_type;
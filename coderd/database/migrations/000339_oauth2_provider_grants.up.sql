@@ -0,0 +1,56 @@
+CREATE TYPE oauth2_provider_app_client_type AS ENUM (
+	'confidential',
+	'public'
+);
+
+ALTER TABLE oauth2_provider_apps
+	ADD COLUMN client_type oauth2_provider_app_client_type NOT NULL DEFAULT 'confidential',
+	ADD COLUMN service_account_id uuid REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN oauth2_provider_apps.client_type IS 'Public clients cannot keep a secret, so they must use PKCE and cannot use the client credentials grant.';
+COMMENT ON COLUMN oauth2_provider_apps.service_account_id IS 'The user that tokens issued by the client credentials grant act as. The grant is disabled when null.';
+
+ALTER TABLE oauth2_provider_app_codes
+	ADD COLUMN code_challenge text NOT NULL DEFAULT '',
+	ADD COLUMN code_challenge_method text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE code challenge, empty if the client did not use PKCE.';
+
+-- Tokens issued to public clients are not tied to a secret, so the app has to
+-- be stored on the token itself.
+ALTER TABLE oauth2_provider_app_tokens
+	ADD COLUMN app_id uuid REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+UPDATE oauth2_provider_app_tokens
+SET app_id = oauth2_provider_app_secrets.app_id
+FROM oauth2_provider_app_secrets
+WHERE oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id;
+
+ALTER TABLE oauth2_provider_app_tokens
+	ALTER COLUMN app_id SET NOT NULL,
+	ALTER COLUMN app_secret_id DROP NOT NULL;
+
+CREATE TYPE oauth2_provider_app_device_code_status AS ENUM (
+	'pending',
+	'approved',
+	'denied'
+);
+
+CREATE TABLE oauth2_provider_app_device_codes (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	last_polled_at timestamp with time zone,
+	secret_prefix bytea NOT NULL,
+	hashed_secret bytea NOT NULL,
+	user_code text NOT NULL,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE,
+	user_id uuid REFERENCES users(id) ON DELETE CASCADE,
+	status oauth2_provider_app_device_code_status NOT NULL DEFAULT 'pending',
+	PRIMARY KEY (id),
+	UNIQUE (secret_prefix),
+	UNIQUE (user_code)
+);
+
+COMMENT ON TABLE oauth2_provider_app_device_codes IS 'Device codes are exchanged for access tokens once a user approves them with the user code (RFC 8628).';
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_id IS 'The user that approved or denied the code.';
@@ -0,0 +1,2 @@
+ALTER TABLE oauth2_provider_apps
+	DROP COLUMN dynamically_registered;
@@ -0,0 +1,4 @@
+ALTER TABLE oauth2_provider_apps
+	ADD COLUMN dynamically_registered boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN oauth2_provider_apps.dynamically_registered IS 'Apps registered with dynamic client registration (RFC 7591) can be created without an account, so they are deleted if they never complete a grant.';
@@ -0,0 +1,50 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'user_secret';
+
+CREATE TABLE user_secrets (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    value text NOT NULL,
+    value_key_id text REFERENCES dbcrypt_keys(active_key_digest),
+    env_name text NOT NULL DEFAULT '',
+    file_path text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX user_secrets_user_env_name_idx ON user_secrets (user_id, env_name) WHERE env_name <> '';
+CREATE UNIQUE INDEX user_secrets_user_file_path_idx ON user_secrets (user_id, file_path) WHERE file_path <> '';
+
+COMMENT ON TABLE user_secrets IS 'Secrets owned by users that are delivered to their workspaces by the agent.';
+COMMENT ON COLUMN user_secrets.value_key_id IS 'The ID of the key used to encrypt the secret value. If this is NULL, the value is not encrypted';
+COMMENT ON COLUMN user_secrets.env_name IS 'Name of the environment variable the secret is exposed as in workspaces. Empty if not exposed as an environment variable.';
+COMMENT ON COLUMN user_secrets.file_path IS 'Path of the file the secret is written to in workspaces. Empty if not written to a file.';
+
+-- Secrets of soft-deleted users are removed along with their other
+-- credentials.
+CREATE OR REPLACE FUNCTION delete_deleted_user_resources() RETURNS trigger
+	LANGUAGE plpgsql
+AS $$
+DECLARE
+BEGIN
+	IF (NEW.deleted) THEN
+		-- Remove their api_keys
+		DELETE FROM api_keys
+		WHERE user_id = OLD.id;
+
+		-- Remove their user_links
+		-- Their login_type is preserved in the users table.
+		-- Matching this user back to the link can still be done by their
+		-- email if the account is undeleted. Although that is not a guarantee.
+		DELETE FROM user_links
+		WHERE user_id = OLD.id;
+
+		-- Remove their user_secrets
+		DELETE FROM user_secrets
+		WHERE user_id = OLD.id;
+	END IF;
+	RETURN NEW;
+END;
+$$;
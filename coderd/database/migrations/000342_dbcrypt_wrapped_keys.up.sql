@@ -0,0 +1,7 @@
+ALTER TABLE dbcrypt_keys
+	ADD COLUMN wrapped_key text,
+	ADD COLUMN wrapping_key_id text;
+
+COMMENT ON COLUMN dbcrypt_keys.wrapped_key IS 'If the key is a data key managed by a key provider, the base64-encoded data key wrapped by the key provider.';
+
+COMMENT ON COLUMN dbcrypt_keys.wrapping_key_id IS 'If the key is a data key managed by a key provider, the ID of the key provider key that wrapped it.';
@@ -0,0 +1,70 @@
+ALTER TABLE templates ADD COLUMN session_recording_enabled boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Record the output of interactive sessions in workspaces created from this template.';
+
+-- Update the template_with_names view by recreating it.
+DROP VIEW template_with_names;
+CREATE VIEW
+	template_with_names
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username,
+	coalesce(visible_users.name, '') AS created_by_name,
+	coalesce(organizations.name, '') AS organization_name,
+	coalesce(organizations.display_name, '') AS organization_display_name,
+	coalesce(organizations.icon, '') AS organization_icon
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id
+		LEFT JOIN
+	organizations
+	ON templates.organization_id = organizations.id
+;
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TYPE session_recording_type AS ENUM (
+	'ssh',
+	'vscode',
+	'jetbrains',
+	'reconnecting_pty'
+);
+
+CREATE TABLE workspace_session_recordings (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	owner_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL,
+	type session_recording_type NOT NULL,
+	width integer NOT NULL,
+	height integer NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone,
+	size bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX workspace_session_recordings_workspace_id_started_at_idx ON workspace_session_recordings (workspace_id, started_at DESC);
+CREATE INDEX workspace_session_recordings_started_at_idx ON workspace_session_recordings (started_at);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of the output of interactive sessions in workspaces, in the asciicast v2 format.';
+COMMENT ON COLUMN workspace_session_recordings.id IS 'Generated by the agent, so that chunks can be uploaded without a round trip.';
+COMMENT ON COLUMN workspace_session_recordings.agent_id IS 'Not a foreign key, so that recordings outlive the builds of their workspace.';
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'NULL while the session is in progress, or if the agent never sent the final chunk.';
+COMMENT ON COLUMN workspace_session_recordings.size IS 'Total size of the chunks in bytes.';
+
+CREATE TABLE workspace_session_recording_chunks (
+	recording_id uuid NOT NULL REFERENCES workspace_session_recordings(id) ON DELETE CASCADE,
+	sequence integer NOT NULL,
+	data bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (recording_id, sequence)
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'Chunks of session recordings. Concatenating the chunks of a recording in sequence order yields an asciicast v2 file.';
@@ -0,0 +1 @@
+-- Enum values can't be dropped, so resource_type is left unchanged.
@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = 'c3290ef1-ef5d-4db0-b924-3e1c1ad5d594';
+
+DROP TABLE IF EXISTS workspace_agent_resource_monitors;
+
+DROP TYPE IF EXISTS workspace_agent_resource_monitor_type;
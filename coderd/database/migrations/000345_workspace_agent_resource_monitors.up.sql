@@ -0,0 +1,42 @@
+CREATE TYPE workspace_agent_resource_monitor_type AS ENUM (
+	'cpu',
+	'load',
+	'inodes',
+	'processes'
+);
+
+CREATE TABLE workspace_agent_resource_monitors (
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	type workspace_agent_resource_monitor_type NOT NULL,
+	path text NOT NULL DEFAULT '',
+	enabled boolean NOT NULL,
+	threshold integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	state workspace_agent_monitor_state NOT NULL DEFAULT 'OK',
+	debounced_until timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00+00',
+	PRIMARY KEY (agent_id, type, path)
+);
+
+COMMENT ON TABLE workspace_agent_resource_monitors IS 'Monitors of workspace agent resources other than memory and volume space.';
+COMMENT ON COLUMN workspace_agent_resource_monitors.path IS 'The volume of inodes monitors, empty for other types.';
+COMMENT ON COLUMN workspace_agent_resource_monitors.threshold IS 'The percentage of CPU or inodes used, the 1 minute load average as a percentage of the number of cores, or the number of processes.';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'c3290ef1-ef5d-4db0-b924-3e1c1ad5d594',
+	'Workspace Resources Exhausted',
+	E'Your workspace "{{.Labels.workspace}}" is running out of resources',
+	E'The following resources have reached their thresholds in workspace **{{.Labels.workspace}}**, which may make it slow or unresponsive:\n\n'||
+		E'{{ range $monitor := .Data.monitors }}'||
+			E'- **{{$monitor.resource}}** is over {{$monitor.threshold}}\n'||
+		E'{{ end }}',
+	'Workspace Events',
+	'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"
+		}
+	]'::jsonb
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS workspace_agent_services;
+
+DROP TYPE IF EXISTS workspace_agent_service_status;
+
+DROP TYPE IF EXISTS workspace_agent_service_restart_policy;
@@ -0,0 +1,41 @@
+CREATE TYPE workspace_agent_service_restart_policy AS ENUM (
+	'always',
+	'on_failure',
+	'never'
+);
+
+CREATE TYPE workspace_agent_service_status AS ENUM (
+	'pending',
+	'starting',
+	'running',
+	'restarting',
+	'stopped',
+	'failed'
+);
+
+CREATE TABLE workspace_agent_services (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	log_source_id uuid NOT NULL,
+	log_path text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	icon text NOT NULL,
+	command text NOT NULL,
+	restart_policy workspace_agent_service_restart_policy NOT NULL,
+	restart_delay_seconds integer NOT NULL,
+	readiness_url text NOT NULL,
+	readiness_interval_seconds integer NOT NULL,
+	readiness_threshold integer NOT NULL,
+	status workspace_agent_service_status NOT NULL DEFAULT 'pending',
+	health workspace_app_health NOT NULL DEFAULT 'disabled',
+	restarts integer NOT NULL DEFAULT 0,
+	exit_code integer,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (workspace_agent_id, name)
+);
+
+COMMENT ON TABLE workspace_agent_services IS 'Long-running processes defined by the template that are supervised by the workspace agent.';
+COMMENT ON COLUMN workspace_agent_services.restarts IS 'The number of times the agent restarted the service since the agent started.';
+COMMENT ON COLUMN workspace_agent_services.exit_code IS 'The exit code of the last run of the service, null if it has not exited.';
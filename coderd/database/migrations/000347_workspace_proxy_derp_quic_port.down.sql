@@ -0,0 +1 @@
+ALTER TABLE workspace_proxies DROP COLUMN derp_quic_port;
@@ -0,0 +1,4 @@
+ALTER TABLE workspace_proxies
+	ADD COLUMN derp_quic_port integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_proxies.derp_quic_port IS 'UDP port the proxy accepts DERP over QUIC connections on. 0 if DERP over QUIC is disabled.';
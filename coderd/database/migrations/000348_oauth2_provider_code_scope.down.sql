@@ -0,0 +1,5 @@
+ALTER TABLE oauth2_provider_app_device_codes
+	DROP COLUMN scope;
+
+ALTER TABLE oauth2_provider_app_codes
+	DROP COLUMN scope;
@@ -0,0 +1,9 @@
+ALTER TABLE oauth2_provider_app_codes
+	ADD COLUMN scope text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.scope IS 'The space-delimited scope requested with the authorization request, empty for the same access as the user.';
+
+ALTER TABLE oauth2_provider_app_device_codes
+	ADD COLUMN scope text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.scope IS 'The space-delimited scope requested with the device authorization request, empty for the same access as the user.';
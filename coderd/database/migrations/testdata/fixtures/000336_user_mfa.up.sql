@@ -0,0 +1,5 @@
+INSERT INTO user_totp (user_id, secret, verified_at, last_used_step, created_at, updated_at) VALUES
+('0ed9befc-4911-4ccf-a8e2-559bf72daa94', 'JBSWY3DPEHPK3PXP', '2025-01-01 12:00:00+00', 58000000, '2025-01-01 11:59:00+00', '2025-01-01 12:00:00+00');
+
+INSERT INTO user_mfa_recovery_codes (user_id, hashed_code, created_at) VALUES
+('0ed9befc-4911-4ccf-a8e2-559bf72daa94', '\x0102030405060708', '2025-01-01 12:00:00+00');
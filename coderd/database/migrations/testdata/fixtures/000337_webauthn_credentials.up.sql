@@ -0,0 +1,5 @@
+INSERT INTO webauthn_credentials (id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, created_at, last_used_at) VALUES
+('8a0f3b9e-51a3-4f0c-9c39-4d6f1b6f3e21', '0ed9befc-4911-4ccf-a8e2-559bf72daa94', 'YubiKey', '\x0102030405060708', '\xa5010203262001215820', 'none', '{usb}', '\x00000000000000000000000000000000', 4, false, false, '2025-01-01 12:00:00+00', '2025-01-02 12:00:00+00');
+
+INSERT INTO webauthn_challenges (challenge, session, expires_at) VALUES
+('dGVzdC1jaGFsbGVuZ2U', '{"challenge": "dGVzdC1jaGFsbGVuZ2U", "rpId": "coder.example.com"}', '2025-01-01 12:05:00+00');
@@ -0,0 +1,14 @@
+INSERT INTO oauth2_provider_app_device_codes
+	(id, created_at, expires_at, last_polled_at, secret_prefix, hashed_secret, user_code, app_id, user_id, status)
+VALUES (
+	'c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'2025-01-01 12:00:00+00',
+	'2025-01-01 12:10:00+00',
+	'2025-01-01 12:01:00+00',
+	CAST('devprefix' AS bytea),
+	CAST('abcdefg' AS bytea),
+	'BCDFGHJK',
+	'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+	'approved'
+);
@@ -0,0 +1,2 @@
+INSERT INTO user_secrets (id, user_id, name, description, value, env_name, file_path, created_at, updated_at) VALUES
+('d3fd38f7-1d37-4ab4-a87e-5f5e1b4ba2a4', '0ed9befc-4911-4ccf-a8e2-559bf72daa94', 'github-token', 'Token for the GitHub CLI', 'ghp_xxxxxxxxxxxxxxxxxxxx', 'GITHUB_TOKEN', '', '2025-01-01 12:00:00+00', '2025-01-01 12:00:00+00');
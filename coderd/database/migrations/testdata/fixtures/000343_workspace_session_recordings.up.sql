@@ -0,0 +1,5 @@
+INSERT INTO workspace_session_recordings (id, organization_id, workspace_id, owner_id, agent_id, type, width, height, started_at, ended_at, size, created_at) VALUES
+('5d4e3b1a-7c2f-4e8a-9b6d-1f0a2c3e4d5b', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 'b90547be-8870-4d68-8184-e8b2242b7c01', '0ed9befc-4911-4ccf-a8e2-559bf72daa94', '7a1bd8f2-3c4e-4b5a-8d6f-9e0a1b2c3d4e', 'ssh', 80, 24, '2025-01-01 12:00:00+00', '2025-01-01 12:05:00+00', 75, '2025-01-01 12:00:00+00');
+
+INSERT INTO workspace_session_recording_chunks (recording_id, sequence, data, created_at) VALUES
+('5d4e3b1a-7c2f-4e8a-9b6d-1f0a2c3e4d5b', 0, convert_to('{"version":2,"width":80,"height":24,"timestamp":1735732800}' || chr(10) || '[0.1,"o","$ "]' || chr(10), 'UTF8'), '2025-01-01 12:00:00+00');
@@ -0,0 +1,35 @@
+INSERT INTO
+	workspace_agent_services (
+		id,
+		workspace_agent_id,
+		log_source_id,
+		log_path,
+		created_at,
+		name,
+		display_name,
+		icon,
+		command,
+		restart_policy,
+		restart_delay_seconds,
+		readiness_url,
+		readiness_interval_seconds,
+		readiness_threshold,
+		updated_at
+	)
+	VALUES (
+		'6f1b3e4c-6c9a-4d2e-9a51-2d8e0b1f6a73',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'0c1c5a0e-7b8e-4f43-9f4a-3c2b6f1e8d90',
+		'',
+		'2024-01-01 00:00:00',
+		'dev-server',
+		'Dev Server',
+		'',
+		'npm run dev',
+		'always',
+		5,
+		'http://localhost:3000',
+		5,
+		3,
+		'2024-01-01 00:00:00'
+	);
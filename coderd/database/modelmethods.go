@@ -236,14 +236,16 @@ func (w WorkspaceTable) RBACObject() rbac.Object {
 
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		InTemplate(w.TemplateID)
 }
 
 func (w WorkspaceTable) DormantRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceDormant.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		InTemplate(w.TemplateID)
 }
 
 func (m OrganizationMember) RBACObject() rbac.Object {
@@ -320,9 +322,11 @@ func (u GetUsersRow) RBACObject() rbac.Object {
 	return rbac.ResourceUserObject(u.ID)
 }
 
-func (u GitSSHKey) RBACObject() rbac.Object        { return rbac.ResourceUserObject(u.UserID) }
-func (u ExternalAuthLink) RBACObject() rbac.Object { return rbac.ResourceUserObject(u.UserID) }
-func (u UserLink) RBACObject() rbac.Object         { return rbac.ResourceUserObject(u.UserID) }
+func (u GitSSHKey) RBACObject() rbac.Object          { return rbac.ResourceUserObject(u.UserID) }
+func (u ExternalAuthLink) RBACObject() rbac.Object   { return rbac.ResourceUserObject(u.UserID) }
+func (u UserLink) RBACObject() rbac.Object           { return rbac.ResourceUserObject(u.UserID) }
+func (u UserTOTP) RBACObject() rbac.Object           { return rbac.ResourceUserObject(u.UserID) }
+func (c WebAuthnCredential) RBACObject() rbac.Object { return rbac.ResourceUserObject(c.UserID) }
 
 func (u ExternalAuthLink) OAuthToken() *oauth2.Token {
 	return &oauth2.Token{
@@ -340,6 +344,14 @@ func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
 	return rbac.ResourceOauth2AppCodeToken.WithOwner(c.UserID.String())
 }
 
+func (c OAuth2ProviderAppDeviceCode) RBACObject() rbac.Object {
+	obj := rbac.ResourceOauth2AppCodeToken
+	if c.UserID.Valid {
+		obj = obj.WithOwner(c.UserID.UUID.String())
+	}
+	return obj
+}
+
 func (OAuth2ProviderAppSecret) RBACObject() rbac.Object {
 	return rbac.ResourceOauth2AppSecret
 }
@@ -569,7 +581,36 @@ func (m WorkspaceAgentVolumeResourceMonitor) Debounce(
 	return m.DebouncedUntil, false
 }
 
+func (m WorkspaceAgentResourceMonitor) Debounce(
+	by time.Duration,
+	now time.Time,
+	oldState, newState WorkspaceAgentMonitorState,
+) (debouncedUntil time.Time, shouldNotify bool) {
+	if now.After(m.DebouncedUntil) &&
+		oldState == WorkspaceAgentMonitorStateOK &&
+		newState == WorkspaceAgentMonitorStateNOK {
+		return now.Add(by), true
+	}
+
+	return m.DebouncedUntil, false
+}
+
 func (c Chat) RBACObject() rbac.Object {
 	return rbac.ResourceChat.WithID(c.ID).
 		WithOwner(c.OwnerID.String())
 }
+
+func (s UserSecret) RBACObject() rbac.Object {
+	return rbac.ResourceUserSecret.WithID(s.ID).
+		WithOwner(s.UserID.String())
+}
+
+func (r WorkspaceSessionRecording) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspaceSessionRecording.WithID(r.ID).
+		InOrg(r.OrganizationID).
+		WithOwner(r.OwnerID.String())
+}
+
+func (r GetWorkspaceSessionRecordingsRow) RBACObject() rbac.Object {
+	return r.WorkspaceSessionRecording.RBACObject()
+}
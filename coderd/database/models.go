@@ -21,6 +21,7 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeCustom             APIKeyScope = "custom"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +62,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom:
 		return true
 	}
 	return false
@@ -71,6 +73,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom,
 	}
 }
 
@@ -797,6 +800,8 @@ const (
 	LoginTypeToken             LoginType = "token"
 	LoginTypeNone              LoginType = "none"
 	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeLDAP              LoginType = "ldap"
+	LoginTypeSAML              LoginType = "saml"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -841,7 +846,9 @@ func (e LoginType) Valid() bool {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
-		LoginTypeOAuth2ProviderApp:
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
+		LoginTypeSAML:
 		return true
 	}
 	return false
@@ -855,6 +862,8 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeToken,
 		LoginTypeNone,
 		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
+		LoginTypeSAML,
 	}
 }
 
@@ -1047,6 +1056,125 @@ func AllNotificationTemplateKindValues() []NotificationTemplateKind {
 	}
 }
 
+type OAuth2ProviderAppClientType string
+
+const (
+	OAuth2ProviderAppClientTypeConfidential OAuth2ProviderAppClientType = "confidential"
+	OAuth2ProviderAppClientTypePublic       OAuth2ProviderAppClientType = "public"
+)
+
+func (e *OAuth2ProviderAppClientType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OAuth2ProviderAppClientType(s)
+	case string:
+		*e = OAuth2ProviderAppClientType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OAuth2ProviderAppClientType: %T", src)
+	}
+	return nil
+}
+
+type NullOAuth2ProviderAppClientType struct {
+	OAuth2ProviderAppClientType OAuth2ProviderAppClientType `json:"oauth2_provider_app_client_type"`
+	Valid                       bool                        `json:"valid"` // Valid is true if OAuth2ProviderAppClientType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOAuth2ProviderAppClientType) Scan(value interface{}) error {
+	if value == nil {
+		ns.OAuth2ProviderAppClientType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OAuth2ProviderAppClientType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOAuth2ProviderAppClientType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OAuth2ProviderAppClientType), nil
+}
+
+func (e OAuth2ProviderAppClientType) Valid() bool {
+	switch e {
+	case OAuth2ProviderAppClientTypeConfidential,
+		OAuth2ProviderAppClientTypePublic:
+		return true
+	}
+	return false
+}
+
+func AllOAuth2ProviderAppClientTypeValues() []OAuth2ProviderAppClientType {
+	return []OAuth2ProviderAppClientType{
+		OAuth2ProviderAppClientTypeConfidential,
+		OAuth2ProviderAppClientTypePublic,
+	}
+}
+
+type OAuth2ProviderAppDeviceCodeStatus string
+
+const (
+	OAuth2ProviderAppDeviceCodeStatusPending  OAuth2ProviderAppDeviceCodeStatus = "pending"
+	OAuth2ProviderAppDeviceCodeStatusApproved OAuth2ProviderAppDeviceCodeStatus = "approved"
+	OAuth2ProviderAppDeviceCodeStatusDenied   OAuth2ProviderAppDeviceCodeStatus = "denied"
+)
+
+func (e *OAuth2ProviderAppDeviceCodeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OAuth2ProviderAppDeviceCodeStatus(s)
+	case string:
+		*e = OAuth2ProviderAppDeviceCodeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OAuth2ProviderAppDeviceCodeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOAuth2ProviderAppDeviceCodeStatus struct {
+	OAuth2ProviderAppDeviceCodeStatus OAuth2ProviderAppDeviceCodeStatus `json:"oauth2_provider_app_device_code_status"`
+	Valid                             bool                              `json:"valid"` // Valid is true if OAuth2ProviderAppDeviceCodeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOAuth2ProviderAppDeviceCodeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OAuth2ProviderAppDeviceCodeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OAuth2ProviderAppDeviceCodeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOAuth2ProviderAppDeviceCodeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OAuth2ProviderAppDeviceCodeStatus), nil
+}
+
+func (e OAuth2ProviderAppDeviceCodeStatus) Valid() bool {
+	switch e {
+	case OAuth2ProviderAppDeviceCodeStatusPending,
+		OAuth2ProviderAppDeviceCodeStatusApproved,
+		OAuth2ProviderAppDeviceCodeStatusDenied:
+		return true
+	}
+	return false
+}
+
+func AllOAuth2ProviderAppDeviceCodeStatusValues() []OAuth2ProviderAppDeviceCodeStatus {
+	return []OAuth2ProviderAppDeviceCodeStatus{
+		OAuth2ProviderAppDeviceCodeStatusPending,
+		OAuth2ProviderAppDeviceCodeStatusApproved,
+		OAuth2ProviderAppDeviceCodeStatusDenied,
+	}
+}
+
 type ParameterDestinationScheme string
 
 const (
@@ -1891,6 +2019,9 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeWebAuthnCredential          ResourceType = "webauthn_credential"
+	ResourceTypeUserSecret                  ResourceType = "user_secret"
+	ResourceTypeWorkspaceSessionRecording   ResourceType = "workspace_session_recording"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1953,7 +2084,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeIdpSyncSettingsGroup,
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
-		ResourceTypeWorkspaceApp:
+		ResourceTypeWorkspaceApp,
+		ResourceTypeWebAuthnCredential,
+		ResourceTypeUserSecret,
+		ResourceTypeWorkspaceSessionRecording:
 		return true
 	}
 	return false
@@ -1985,6 +2119,73 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
 		ResourceTypeWorkspaceApp,
+		ResourceTypeWebAuthnCredential,
+		ResourceTypeUserSecret,
+		ResourceTypeWorkspaceSessionRecording,
+	}
+}
+
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeVSCode          SessionRecordingType = "vscode"
+	SessionRecordingTypeJetBrains       SessionRecordingType = "jetbrains"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+func (e *SessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SessionRecordingType(s)
+	case string:
+		*e = SessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullSessionRecordingType struct {
+	SessionRecordingType SessionRecordingType `json:"session_recording_type"`
+	Valid                bool                 `json:"valid"` // Valid is true if SessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.SessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SessionRecordingType), nil
+}
+
+func (e SessionRecordingType) Valid() bool {
+	switch e {
+	case SessionRecordingTypeSSH,
+		SessionRecordingTypeVSCode,
+		SessionRecordingTypeJetBrains,
+		SessionRecordingTypeReconnectingPTY:
+		return true
+	}
+	return false
+}
+
+func AllSessionRecordingTypeValues() []SessionRecordingType {
+	return []SessionRecordingType{
+		SessionRecordingTypeSSH,
+		SessionRecordingTypeVSCode,
+		SessionRecordingTypeJetBrains,
+		SessionRecordingTypeReconnectingPTY,
 	}
 }
 
@@ -2303,6 +2504,70 @@ func AllWorkspaceAgentMonitorStateValues() []WorkspaceAgentMonitorState {
 	}
 }
 
+type WorkspaceAgentResourceMonitorType string
+
+const (
+	WorkspaceAgentResourceMonitorTypeCPU       WorkspaceAgentResourceMonitorType = "cpu"
+	WorkspaceAgentResourceMonitorTypeLoad      WorkspaceAgentResourceMonitorType = "load"
+	WorkspaceAgentResourceMonitorTypeInodes    WorkspaceAgentResourceMonitorType = "inodes"
+	WorkspaceAgentResourceMonitorTypeProcesses WorkspaceAgentResourceMonitorType = "processes"
+)
+
+func (e *WorkspaceAgentResourceMonitorType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentResourceMonitorType(s)
+	case string:
+		*e = WorkspaceAgentResourceMonitorType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentResourceMonitorType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentResourceMonitorType struct {
+	WorkspaceAgentResourceMonitorType WorkspaceAgentResourceMonitorType `json:"workspace_agent_resource_monitor_type"`
+	Valid                             bool                              `json:"valid"` // Valid is true if WorkspaceAgentResourceMonitorType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentResourceMonitorType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentResourceMonitorType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentResourceMonitorType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentResourceMonitorType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentResourceMonitorType), nil
+}
+
+func (e WorkspaceAgentResourceMonitorType) Valid() bool {
+	switch e {
+	case WorkspaceAgentResourceMonitorTypeCPU,
+		WorkspaceAgentResourceMonitorTypeLoad,
+		WorkspaceAgentResourceMonitorTypeInodes,
+		WorkspaceAgentResourceMonitorTypeProcesses:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentResourceMonitorTypeValues() []WorkspaceAgentResourceMonitorType {
+	return []WorkspaceAgentResourceMonitorType{
+		WorkspaceAgentResourceMonitorTypeCPU,
+		WorkspaceAgentResourceMonitorTypeLoad,
+		WorkspaceAgentResourceMonitorTypeInodes,
+		WorkspaceAgentResourceMonitorTypeProcesses,
+	}
+}
+
 // What stage the script was ran in.
 type WorkspaceAgentScriptTimingStage string
 
@@ -2430,6 +2695,137 @@ func AllWorkspaceAgentScriptTimingStatusValues() []WorkspaceAgentScriptTimingSta
 	}
 }
 
+type WorkspaceAgentServiceRestartPolicy string
+
+const (
+	WorkspaceAgentServiceRestartPolicyAlways    WorkspaceAgentServiceRestartPolicy = "always"
+	WorkspaceAgentServiceRestartPolicyOnFailure WorkspaceAgentServiceRestartPolicy = "on_failure"
+	WorkspaceAgentServiceRestartPolicyNever     WorkspaceAgentServiceRestartPolicy = "never"
+)
+
+func (e *WorkspaceAgentServiceRestartPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentServiceRestartPolicy(s)
+	case string:
+		*e = WorkspaceAgentServiceRestartPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentServiceRestartPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentServiceRestartPolicy struct {
+	WorkspaceAgentServiceRestartPolicy WorkspaceAgentServiceRestartPolicy `json:"workspace_agent_service_restart_policy"`
+	Valid                              bool                               `json:"valid"` // Valid is true if WorkspaceAgentServiceRestartPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentServiceRestartPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentServiceRestartPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentServiceRestartPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentServiceRestartPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentServiceRestartPolicy), nil
+}
+
+func (e WorkspaceAgentServiceRestartPolicy) Valid() bool {
+	switch e {
+	case WorkspaceAgentServiceRestartPolicyAlways,
+		WorkspaceAgentServiceRestartPolicyOnFailure,
+		WorkspaceAgentServiceRestartPolicyNever:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentServiceRestartPolicyValues() []WorkspaceAgentServiceRestartPolicy {
+	return []WorkspaceAgentServiceRestartPolicy{
+		WorkspaceAgentServiceRestartPolicyAlways,
+		WorkspaceAgentServiceRestartPolicyOnFailure,
+		WorkspaceAgentServiceRestartPolicyNever,
+	}
+}
+
+type WorkspaceAgentServiceStatus string
+
+const (
+	WorkspaceAgentServiceStatusPending    WorkspaceAgentServiceStatus = "pending"
+	WorkspaceAgentServiceStatusStarting   WorkspaceAgentServiceStatus = "starting"
+	WorkspaceAgentServiceStatusRunning    WorkspaceAgentServiceStatus = "running"
+	WorkspaceAgentServiceStatusRestarting WorkspaceAgentServiceStatus = "restarting"
+	WorkspaceAgentServiceStatusStopped    WorkspaceAgentServiceStatus = "stopped"
+	WorkspaceAgentServiceStatusFailed     WorkspaceAgentServiceStatus = "failed"
+)
+
+func (e *WorkspaceAgentServiceStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentServiceStatus(s)
+	case string:
+		*e = WorkspaceAgentServiceStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentServiceStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentServiceStatus struct {
+	WorkspaceAgentServiceStatus WorkspaceAgentServiceStatus `json:"workspace_agent_service_status"`
+	Valid                       bool                        `json:"valid"` // Valid is true if WorkspaceAgentServiceStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentServiceStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentServiceStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentServiceStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentServiceStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentServiceStatus), nil
+}
+
+func (e WorkspaceAgentServiceStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentServiceStatusPending,
+		WorkspaceAgentServiceStatusStarting,
+		WorkspaceAgentServiceStatusRunning,
+		WorkspaceAgentServiceStatusRestarting,
+		WorkspaceAgentServiceStatusStopped,
+		WorkspaceAgentServiceStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentServiceStatusValues() []WorkspaceAgentServiceStatus {
+	return []WorkspaceAgentServiceStatus{
+		WorkspaceAgentServiceStatusPending,
+		WorkspaceAgentServiceStatusStarting,
+		WorkspaceAgentServiceStatusRunning,
+		WorkspaceAgentServiceStatusRestarting,
+		WorkspaceAgentServiceStatusStopped,
+		WorkspaceAgentServiceStatusFailed,
+	}
+}
+
 type WorkspaceAgentSubsystem string
 
 const (
@@ -2755,6 +3151,10 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// The permissions granted to a key with the custom scope, in the form "<resource>:<action>".
+	ScopePermissions []string `db:"scope_permissions" json:"scope_permissions"`
+	// The objects a key with the custom scope is limited to, in the form "<resource>:<id>". Empty means no limit.
+	ScopeAllowList []string `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 type AuditLog struct {
@@ -2830,6 +3230,10 @@ type DBCryptKey struct {
 	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
 	// A column used to test the encryption.
 	Test string `db:"test" json:"test"`
+	// If the key is a data key managed by a key provider, the base64-encoded data key wrapped by the key provider.
+	WrappedKey sql.NullString `db:"wrapped_key" json:"wrapped_key"`
+	// If the key is a data key managed by a key provider, the ID of the key provider key that wrapped it.
+	WrappingKeyID sql.NullString `db:"wrapping_key_id" json:"wrapping_key_id"`
 }
 
 type ExternalAuthLink struct {
@@ -2991,6 +3395,12 @@ type OAuth2ProviderApp struct {
 	Name        string    `db:"name" json:"name"`
 	Icon        string    `db:"icon" json:"icon"`
 	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	// Public clients cannot keep a secret, so they must use PKCE and cannot use the client credentials grant.
+	ClientType OAuth2ProviderAppClientType `db:"client_type" json:"client_type"`
+	// The user that tokens issued by the client credentials grant act as. The grant is disabled when null.
+	ServiceAccountID uuid.NullUUID `db:"service_account_id" json:"service_account_id"`
+	// Apps registered with dynamic client registration (RFC 7591) can be created without an account, so they are deleted if they never complete a grant.
+	DynamicallyRegistered bool `db:"dynamically_registered" json:"dynamically_registered"`
 }
 
 // Codes are meant to be exchanged for access tokens.
@@ -3002,6 +3412,28 @@ type OAuth2ProviderAppCode struct {
 	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
 	UserID       uuid.UUID `db:"user_id" json:"user_id"`
 	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	// The PKCE code challenge, empty if the client did not use PKCE.
+	CodeChallenge       string `db:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `db:"code_challenge_method" json:"code_challenge_method"`
+	// The space-delimited scope requested with the authorization request, empty for the same access as the user.
+	Scope string `db:"scope" json:"scope"`
+}
+
+// Device codes are exchanged for access tokens once a user approves them with the user code (RFC 8628).
+type OAuth2ProviderAppDeviceCode struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time    `db:"expires_at" json:"expires_at"`
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
+	SecretPrefix []byte       `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	UserCode     string       `db:"user_code" json:"user_code"`
+	AppID        uuid.UUID    `db:"app_id" json:"app_id"`
+	// The user that approved or denied the code.
+	UserID uuid.NullUUID                     `db:"user_id" json:"user_id"`
+	Status OAuth2ProviderAppDeviceCodeStatus `db:"status" json:"status"`
+	// The space-delimited scope requested with the device authorization request, empty for the same access as the user.
+	Scope string `db:"scope" json:"scope"`
 }
 
 type OAuth2ProviderAppSecret struct {
@@ -3021,9 +3453,10 @@ type OAuth2ProviderAppToken struct {
 	ExpiresAt  time.Time `db:"expires_at" json:"expires_at"`
 	HashPrefix []byte    `db:"hash_prefix" json:"hash_prefix"`
 	// Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.
-	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
-	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
-	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	RefreshHash []byte        `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.NullUUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string        `db:"api_key_id" json:"api_key_id"`
+	AppID       uuid.UUID     `db:"app_id" json:"app_id"`
 }
 
 type Organization struct {
@@ -3262,6 +3695,7 @@ type Template struct {
 	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	SessionRecordingEnabled       bool            `db:"session_recording_enabled" json:"session_recording_enabled"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 	CreatedByName                 string          `db:"created_by_name" json:"created_by_name"`
@@ -3310,6 +3744,8 @@ type TemplateTable struct {
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	// Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.
 	UseClassicParameterFlow bool `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	// Record the output of interactive sessions in workspaces created from this template.
+	SessionRecordingEnabled bool `db:"session_recording_enabled" json:"session_recording_enabled"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -3521,6 +3957,31 @@ type UserLink struct {
 	Claims UserLinkClaims `db:"claims" json:"claims"`
 }
 
+// Single use recovery codes that can be used in place of a TOTP code.
+type UserMFARecoveryCode struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// SHA256 hash of the recovery code.
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// Secrets owned by users that are delivered to their workspaces by the agent.
+type UserSecret struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Value       string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the secret value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	// Name of the environment variable the secret is exposed as in workspaces. Empty if not exposed as an environment variable.
+	EnvName string `db:"env_name" json:"env_name"`
+	// Path of the file the secret is written to in workspaces. Empty if not written to a file.
+	FilePath  string    `db:"file_path" json:"file_path"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Tracks the history of user status changes
 type UserStatusChange struct {
 	ID        uuid.UUID  `db:"id" json:"id"`
@@ -3529,6 +3990,21 @@ type UserStatusChange struct {
 	ChangedAt time.Time  `db:"changed_at" json:"changed_at"`
 }
 
+// TOTP second factors of password authenticated users.
+type UserTOTP struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// Base32 encoded TOTP secret shared with the authenticator app.
+	Secret string `db:"secret" json:"secret"`
+	// The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	// Time the user confirmed enrollment with a valid code. The second factor is only enforced once verified.
+	VerifiedAt sql.NullTime `db:"verified_at" json:"verified_at"`
+	// Time step of the last accepted code. Codes for this or an earlier step are rejected to prevent replay.
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -3537,6 +4013,34 @@ type VisibleUser struct {
 	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
 }
 
+// Pending WebAuthn registration and login ceremonies. Each challenge can only be used once.
+type WebAuthnChallenge struct {
+	Challenge string `db:"challenge" json:"challenge"`
+	// Session data of the ceremony that is needed to validate the response of the authenticator.
+	Session   json.RawMessage `db:"session" json:"session"`
+	ExpiresAt time.Time       `db:"expires_at" json:"expires_at"`
+}
+
+// WebAuthn credentials (passkeys and security keys) registered by users.
+type WebAuthnCredential struct {
+	ID     uuid.UUID `db:"id" json:"id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+	// Credential ID chosen by the authenticator.
+	CredentialID []byte `db:"credential_id" json:"credential_id"`
+	// COSE encoded public key of the credential.
+	PublicKey       []byte   `db:"public_key" json:"public_key"`
+	AttestationType string   `db:"attestation_type" json:"attestation_type"`
+	Transports      []string `db:"transports" json:"transports"`
+	AAGUID          []byte   `db:"aaguid" json:"aaguid"`
+	// Signature counter of the authenticator. A counter that does not increase indicates a cloned authenticator.
+	SignCount      int64        `db:"sign_count" json:"sign_count"`
+	BackupEligible bool         `db:"backup_eligible" json:"backup_eligible"`
+	BackupState    bool         `db:"backup_state" json:"backup_state"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt     sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
 type WebpushSubscription struct {
 	ID                uuid.UUID `db:"id" json:"id"`
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
@@ -3690,6 +4194,21 @@ type WorkspaceAgentPortShare struct {
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
 }
 
+// Monitors of workspace agent resources other than memory and volume space.
+type WorkspaceAgentResourceMonitor struct {
+	AgentID uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type    WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	// The volume of inodes monitors, empty for other types.
+	Path    string `db:"path" json:"path"`
+	Enabled bool   `db:"enabled" json:"enabled"`
+	// The percentage of CPU or inodes used, the 1 minute load average as a percentage of the number of cores, or the number of processes.
+	Threshold      int32                      `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+}
+
 type WorkspaceAgentScript struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
@@ -3714,6 +4233,31 @@ type WorkspaceAgentScriptTiming struct {
 	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
 }
 
+// Long-running processes defined by the template that are supervised by the workspace agent.
+type WorkspaceAgentService struct {
+	ID                       uuid.UUID                          `db:"id" json:"id"`
+	WorkspaceAgentID         uuid.UUID                          `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LogSourceID              uuid.UUID                          `db:"log_source_id" json:"log_source_id"`
+	LogPath                  string                             `db:"log_path" json:"log_path"`
+	CreatedAt                time.Time                          `db:"created_at" json:"created_at"`
+	Name                     string                             `db:"name" json:"name"`
+	DisplayName              string                             `db:"display_name" json:"display_name"`
+	Icon                     string                             `db:"icon" json:"icon"`
+	Command                  string                             `db:"command" json:"command"`
+	RestartPolicy            WorkspaceAgentServiceRestartPolicy `db:"restart_policy" json:"restart_policy"`
+	RestartDelaySeconds      int32                              `db:"restart_delay_seconds" json:"restart_delay_seconds"`
+	ReadinessURL             string                             `db:"readiness_url" json:"readiness_url"`
+	ReadinessIntervalSeconds int32                              `db:"readiness_interval_seconds" json:"readiness_interval_seconds"`
+	ReadinessThreshold       int32                              `db:"readiness_threshold" json:"readiness_threshold"`
+	Status                   WorkspaceAgentServiceStatus        `db:"status" json:"status"`
+	Health                   WorkspaceAppHealth                 `db:"health" json:"health"`
+	// The number of times the agent restarted the service since the agent started.
+	Restarts int32 `db:"restarts" json:"restarts"`
+	// The exit code of the last run of the service, null if it has not exited.
+	ExitCode  sql.NullInt32 `db:"exit_code" json:"exit_code"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspaceAgentStat struct {
 	ID                          uuid.UUID       `db:"id" json:"id"`
 	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
@@ -3937,6 +4481,8 @@ type WorkspaceProxy struct {
 	// Disables app/terminal proxying for this proxy and only acts as a DERP relay.
 	DerpOnly bool   `db:"derp_only" json:"derp_only"`
 	Version  string `db:"version" json:"version"`
+	// UDP port the proxy accepts DERP over QUIC connections on. 0 if DERP over QUIC is disabled.
+	DerpQuicPort int32 `db:"derp_quic_port" json:"derp_quic_port"`
 }
 
 type WorkspaceResource struct {
@@ -3961,6 +4507,34 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// Recordings of the output of interactive sessions in workspaces, in the asciicast v2 format.
+type WorkspaceSessionRecording struct {
+	// Generated by the agent, so that chunks can be uploaded without a round trip.
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	// Not a foreign key, so that recordings outlive the builds of their workspace.
+	AgentID   uuid.UUID            `db:"agent_id" json:"agent_id"`
+	Type      SessionRecordingType `db:"type" json:"type"`
+	Width     int32                `db:"width" json:"width"`
+	Height    int32                `db:"height" json:"height"`
+	StartedAt time.Time            `db:"started_at" json:"started_at"`
+	// NULL while the session is in progress, or if the agent never sent the final chunk.
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+	// Total size of the chunks in bytes.
+	Size      int64     `db:"size" json:"size"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Chunks of session recordings. Concatenating the chunks of a recording in sequence order yields an asciicast v2 file.
+type WorkspaceSessionRecordingChunk struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -146,7 +146,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -171,13 +171,15 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -210,12 +212,14 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -240,6 +244,8 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +261,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -285,6 +291,8 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +308,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -325,6 +333,8 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +363,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_permissions,
+		scope_allow_list
 	)
 VALUES
 	($1,
@@ -362,22 +374,24 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID               string      `db:"id" json:"id"`
+	LifetimeSeconds  int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret     []byte      `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress        pqtype.Inet `db:"ip_address" json:"ip_address"`
+	UserID           uuid.UUID   `db:"user_id" json:"user_id"`
+	LastUsed         time.Time   `db:"last_used" json:"last_used"`
+	ExpiresAt        time.Time   `db:"expires_at" json:"expires_at"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time   `db:"updated_at" json:"updated_at"`
+	LoginType        LoginType   `db:"login_type" json:"login_type"`
+	Scope            APIKeyScope `db:"scope" json:"scope"`
+	TokenName        string      `db:"token_name" json:"token_name"`
+	ScopePermissions []string    `db:"scope_permissions" json:"scope_permissions"`
+	ScopeAllowList   []string    `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -394,6 +408,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		pq.Array(arg.ScopePermissions),
+		pq.Array(arg.ScopeAllowList),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -409,6 +425,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
@@ -1186,7 +1204,7 @@ func (q *sqlQuerier) UpdateCryptoKeyDeletesAt(ctx context.Context, arg UpdateCry
 }
 
 const getDBCryptKeys = `-- name: GetDBCryptKeys :many
-SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test, wrapped_key, wrapping_key_id FROM dbcrypt_keys ORDER BY number ASC
 `
 
 func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
@@ -1205,6 +1223,8 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.Test,
+			&i.WrappedKey,
+			&i.WrappingKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -1221,18 +1241,26 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 
 const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
 INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+	(number, active_key_digest, created_at, test, wrapped_key, wrapping_key_id)
+VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text, $4::text, $5::text)
 `
 
 type InsertDBCryptKeyParams struct {
-	Number          int32  `db:"number" json:"number"`
-	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
-	Test            string `db:"test" json:"test"`
+	Number          int32          `db:"number" json:"number"`
+	ActiveKeyDigest string         `db:"active_key_digest" json:"active_key_digest"`
+	Test            string         `db:"test" json:"test"`
+	WrappedKey      sql.NullString `db:"wrapped_key" json:"wrapped_key"`
+	WrappingKeyID   sql.NullString `db:"wrapping_key_id" json:"wrapping_key_id"`
 }
 
 func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
-	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
+	_, err := q.db.ExecContext(ctx, insertDBCryptKey,
+		arg.Number,
+		arg.ActiveKeyDigest,
+		arg.Test,
+		arg.WrappedKey,
+		arg.WrappingKeyID,
+	)
 	return err
 }
 
@@ -1253,6 +1281,26 @@ func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest strin
 	return err
 }
 
+const updateDBCryptKeyWrappedKey = `-- name: UpdateDBCryptKeyWrappedKey :exec
+UPDATE dbcrypt_keys
+SET
+	wrapped_key = $1::text,
+	wrapping_key_id = $2::text
+WHERE
+	number = $3::int
+`
+
+type UpdateDBCryptKeyWrappedKeyParams struct {
+	WrappedKey    string `db:"wrapped_key" json:"wrapped_key"`
+	WrappingKeyID string `db:"wrapping_key_id" json:"wrapping_key_id"`
+	Number        int32  `db:"number" json:"number"`
+}
+
+func (q *sqlQuerier) UpdateDBCryptKeyWrappedKey(ctx context.Context, arg UpdateDBCryptKeyWrappedKeyParams) error {
+	_, err := q.db.ExecContext(ctx, updateDBCryptKeyWrappedKey, arg.WrappedKey, arg.WrappingKeyID, arg.Number)
+	return err
+}
+
 const deleteExternalAuthLink = `-- name: DeleteExternalAuthLink :exec
 DELETE FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
 `
@@ -4878,6 +4926,15 @@ func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Cont
 	return err
 }
 
+const deleteOAuth2ProviderAppDeviceCodeByID = `-- name: DeleteOAuth2ProviderAppDeviceCodeByID :exec
+DELETE FROM oauth2_provider_app_device_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppDeviceCodeByID, id)
+	return err
+}
+
 const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
 DELETE FROM oauth2_provider_app_secrets WHERE id = $1
 `
@@ -4891,11 +4948,10 @@ const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2Provid
 DELETE FROM
   oauth2_provider_app_tokens
 USING
-  oauth2_provider_app_secrets, api_keys
+  api_keys
 WHERE
-  oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
-  AND api_keys.id = oauth2_provider_app_tokens.api_key_id
-  AND oauth2_provider_app_secrets.app_id = $1
+  api_keys.id = oauth2_provider_app_tokens.api_key_id
+  AND oauth2_provider_app_tokens.app_id = $1
 	AND api_keys.user_id = $2
 `
 
@@ -4909,8 +4965,28 @@ func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Con
 	return err
 }
 
+const deleteUnusedDynamicOAuth2ProviderApps = `-- name: DeleteUnusedDynamicOAuth2ProviderApps :exec
+DELETE FROM
+	oauth2_provider_apps
+WHERE
+	dynamically_registered
+	AND created_at < $1 :: timestamptz
+	AND NOT EXISTS (
+		SELECT 1 FROM oauth2_provider_app_tokens
+		WHERE oauth2_provider_app_tokens.app_id = oauth2_provider_apps.id
+	)
+`
+
+// Anyone can register an app when dynamic client registration is open, so
+// apps that were never granted a token are deleted once they are old enough
+// that they are not waiting on a user.
+func (q *sqlQuerier) DeleteUnusedDynamicOAuth2ProviderApps(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteUnusedDynamicOAuth2ProviderApps, beforeTime)
+	return err
+}
+
 const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+SELECT id, created_at, updated_at, name, icon, callback_url, client_type, service_account_id, dynamically_registered FROM oauth2_provider_apps WHERE id = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
@@ -4923,12 +4999,15 @@ func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID)
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.ClientType,
+		&i.ServiceAccountID,
+		&i.DynamicallyRegistered,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE id = $1
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge, code_challenge_method, scope FROM oauth2_provider_app_codes WHERE id = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
@@ -4942,12 +5021,15 @@ func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.U
 		&i.HashedSecret,
 		&i.UserID,
 		&i.AppID,
+		&i.CodeChallenge,
+		&i.CodeChallengeMethod,
+		&i.Scope,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge, code_challenge_method, scope FROM oauth2_provider_app_codes WHERE secret_prefix = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error) {
@@ -4961,6 +5043,55 @@ func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secre
 		&i.HashedSecret,
 		&i.UserID,
 		&i.AppID,
+		&i.CodeChallenge,
+		&i.CodeChallengeMethod,
+		&i.Scope,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppDeviceCodeByPrefix = `-- name: GetOAuth2ProviderAppDeviceCodeByPrefix :one
+SELECT id, created_at, expires_at, last_polled_at, secret_prefix, hashed_secret, user_code, app_id, user_id, status, scope FROM oauth2_provider_app_device_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppDeviceCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastPolledAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.Status,
+		&i.Scope,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppDeviceCodeByUserCode = `-- name: GetOAuth2ProviderAppDeviceCodeByUserCode :one
+SELECT id, created_at, expires_at, last_polled_at, secret_prefix, hashed_secret, user_code, app_id, user_id, status, scope FROM oauth2_provider_app_device_codes WHERE user_code = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppDeviceCodeByUserCode, userCode)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastPolledAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.Status,
+		&i.Scope,
 	)
 	return i, err
 }
@@ -5038,8 +5169,28 @@ func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, app
 	return items, nil
 }
 
+const getOAuth2ProviderAppTokenByAPIKeyID = `-- name: GetOAuth2ProviderAppTokenByAPIKeyID :one
+SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, app_id FROM oauth2_provider_app_tokens WHERE api_key_id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByAPIKeyID, apiKeyID)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
+		&i.AppID,
+	)
+	return i, err
+}
+
 const getOAuth2ProviderAppTokenByPrefix = `-- name: GetOAuth2ProviderAppTokenByPrefix :one
-SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
+SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, app_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error) {
@@ -5053,12 +5204,13 @@ func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hash
 		&i.RefreshHash,
 		&i.AppSecretID,
 		&i.APIKeyID,
+		&i.AppID,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+SELECT id, created_at, updated_at, name, icon, callback_url, client_type, service_account_id, dynamically_registered FROM oauth2_provider_apps ORDER BY (name, id) ASC
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
@@ -5077,6 +5229,9 @@ func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2Provide
 			&i.Name,
 			&i.Icon,
 			&i.CallbackURL,
+			&i.ClientType,
+			&i.ServiceAccountID,
+			&i.DynamicallyRegistered,
 		); err != nil {
 			return nil, err
 		}
@@ -5094,12 +5249,10 @@ func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2Provide
 const getOAuth2ProviderAppsByUserID = `-- name: GetOAuth2ProviderAppsByUserID :many
 SELECT
   COUNT(DISTINCT oauth2_provider_app_tokens.id) as token_count,
-  oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.callback_url
+  oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.callback_url, oauth2_provider_apps.client_type, oauth2_provider_apps.service_account_id, oauth2_provider_apps.dynamically_registered
 FROM oauth2_provider_app_tokens
-  INNER JOIN oauth2_provider_app_secrets
-    ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
   INNER JOIN oauth2_provider_apps
-    ON oauth2_provider_apps.id = oauth2_provider_app_secrets.app_id
+    ON oauth2_provider_apps.id = oauth2_provider_app_tokens.app_id
   INNER JOIN api_keys
     ON api_keys.id = oauth2_provider_app_tokens.api_key_id
 WHERE
@@ -5130,6 +5283,9 @@ func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID u
 			&i.OAuth2ProviderApp.Name,
 			&i.OAuth2ProviderApp.Icon,
 			&i.OAuth2ProviderApp.CallbackURL,
+			&i.OAuth2ProviderApp.ClientType,
+			&i.OAuth2ProviderApp.ServiceAccountID,
+			&i.OAuth2ProviderApp.DynamicallyRegistered,
 		); err != nil {
 			return nil, err
 		}
@@ -5151,24 +5307,33 @@ INSERT INTO oauth2_provider_apps (
     updated_at,
     name,
     icon,
-    callback_url
+    callback_url,
+    client_type,
+    service_account_id,
+    dynamically_registered
 ) VALUES(
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
-) RETURNING id, created_at, updated_at, name, icon, callback_url
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING id, created_at, updated_at, name, icon, callback_url, client_type, service_account_id, dynamically_registered
 `
 
 type InsertOAuth2ProviderAppParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	ID                    uuid.UUID                   `db:"id" json:"id"`
+	CreatedAt             time.Time                   `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time                   `db:"updated_at" json:"updated_at"`
+	Name                  string                      `db:"name" json:"name"`
+	Icon                  string                      `db:"icon" json:"icon"`
+	CallbackURL           string                      `db:"callback_url" json:"callback_url"`
+	ClientType            OAuth2ProviderAppClientType `db:"client_type" json:"client_type"`
+	ServiceAccountID      uuid.NullUUID               `db:"service_account_id" json:"service_account_id"`
+	DynamicallyRegistered bool                        `db:"dynamically_registered" json:"dynamically_registered"`
 }
 
 func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
@@ -5179,6 +5344,9 @@ func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAut
 		arg.Name,
 		arg.Icon,
 		arg.CallbackURL,
+		arg.ClientType,
+		arg.ServiceAccountID,
+		arg.DynamicallyRegistered,
 	)
 	var i OAuth2ProviderApp
 	err := row.Scan(
@@ -5188,6 +5356,9 @@ func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAut
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.ClientType,
+		&i.ServiceAccountID,
+		&i.DynamicallyRegistered,
 	)
 	return i, err
 }
@@ -5200,7 +5371,10 @@ INSERT INTO oauth2_provider_app_codes (
     secret_prefix,
     hashed_secret,
     app_id,
-    user_id
+    user_id,
+    code_challenge,
+    code_challenge_method,
+    scope
 ) VALUES(
     $1,
     $2,
@@ -5208,39 +5382,112 @@ INSERT INTO oauth2_provider_app_codes (
     $4,
     $5,
     $6,
-    $7
-) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id
+    $7,
+    $8,
+    $9,
+    $10
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge, code_challenge_method, scope
 `
 
 type InsertOAuth2ProviderAppCodeParams struct {
+	ID                  uuid.UUID `db:"id" json:"id"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt           time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix        []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret        []byte    `db:"hashed_secret" json:"hashed_secret"`
+	AppID               uuid.UUID `db:"app_id" json:"app_id"`
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	CodeChallenge       string    `db:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string    `db:"code_challenge_method" json:"code_challenge_method"`
+	Scope               string    `db:"scope" json:"scope"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.AppID,
+		arg.UserID,
+		arg.CodeChallenge,
+		arg.CodeChallengeMethod,
+		arg.Scope,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.CodeChallenge,
+		&i.CodeChallengeMethod,
+		&i.Scope,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppDeviceCode = `-- name: InsertOAuth2ProviderAppDeviceCode :one
+INSERT INTO oauth2_provider_app_device_codes (
+    id,
+    created_at,
+    expires_at,
+    secret_prefix,
+    hashed_secret,
+    user_code,
+    app_id,
+    scope
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING id, created_at, expires_at, last_polled_at, secret_prefix, hashed_secret, user_code, app_id, user_id, status, scope
+`
+
+type InsertOAuth2ProviderAppDeviceCodeParams struct {
 	ID           uuid.UUID `db:"id" json:"id"`
 	CreatedAt    time.Time `db:"created_at" json:"created_at"`
 	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
 	SecretPrefix []byte    `db:"secret_prefix" json:"secret_prefix"`
 	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserCode     string    `db:"user_code" json:"user_code"`
 	AppID        uuid.UUID `db:"app_id" json:"app_id"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Scope        string    `db:"scope" json:"scope"`
 }
 
-func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
-	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+func (q *sqlQuerier) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg InsertOAuth2ProviderAppDeviceCodeParams) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppDeviceCode,
 		arg.ID,
 		arg.CreatedAt,
 		arg.ExpiresAt,
 		arg.SecretPrefix,
 		arg.HashedSecret,
+		arg.UserCode,
 		arg.AppID,
-		arg.UserID,
+		arg.Scope,
 	)
-	var i OAuth2ProviderAppCode
+	var i OAuth2ProviderAppDeviceCode
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.ExpiresAt,
+		&i.LastPolledAt,
 		&i.SecretPrefix,
 		&i.HashedSecret,
-		&i.UserID,
+		&i.UserCode,
 		&i.AppID,
+		&i.UserID,
+		&i.Status,
+		&i.Scope,
 	)
 	return i, err
 }
@@ -5302,7 +5549,8 @@ INSERT INTO oauth2_provider_app_tokens (
     hash_prefix,
     refresh_hash,
     app_secret_id,
-    api_key_id
+    api_key_id,
+    app_id
 ) VALUES(
     $1,
     $2,
@@ -5310,18 +5558,20 @@ INSERT INTO oauth2_provider_app_tokens (
     $4,
     $5,
     $6,
-    $7
-) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id
+    $7,
+    $8
+) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, app_id
 `
 
 type InsertOAuth2ProviderAppTokenParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
-	HashPrefix  []byte    `db:"hash_prefix" json:"hash_prefix"`
-	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
-	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
-	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	ID          uuid.UUID     `db:"id" json:"id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time     `db:"expires_at" json:"expires_at"`
+	HashPrefix  []byte        `db:"hash_prefix" json:"hash_prefix"`
+	RefreshHash []byte        `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.NullUUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string        `db:"api_key_id" json:"api_key_id"`
+	AppID       uuid.UUID     `db:"app_id" json:"app_id"`
 }
 
 func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
@@ -5333,6 +5583,7 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg Inser
 		arg.RefreshHash,
 		arg.AppSecretID,
 		arg.APIKeyID,
+		arg.AppID,
 	)
 	var i OAuth2ProviderAppToken
 	err := row.Scan(
@@ -5343,6 +5594,7 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg Inser
 		&i.RefreshHash,
 		&i.AppSecretID,
 		&i.APIKeyID,
+		&i.AppID,
 	)
 	return i, err
 }
@@ -5352,16 +5604,20 @@ UPDATE oauth2_provider_apps SET
     updated_at = $2,
     name = $3,
     icon = $4,
-    callback_url = $5
-WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url
+    callback_url = $5,
+    client_type = $6,
+    service_account_id = $7
+WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url, client_type, service_account_id, dynamically_registered
 `
 
 type UpdateOAuth2ProviderAppByIDParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	ID               uuid.UUID                   `db:"id" json:"id"`
+	UpdatedAt        time.Time                   `db:"updated_at" json:"updated_at"`
+	Name             string                      `db:"name" json:"name"`
+	Icon             string                      `db:"icon" json:"icon"`
+	CallbackURL      string                      `db:"callback_url" json:"callback_url"`
+	ClientType       OAuth2ProviderAppClientType `db:"client_type" json:"client_type"`
+	ServiceAccountID uuid.NullUUID               `db:"service_account_id" json:"service_account_id"`
 }
 
 func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
@@ -5371,6 +5627,8 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg Update
 		arg.Name,
 		arg.Icon,
 		arg.CallbackURL,
+		arg.ClientType,
+		arg.ServiceAccountID,
 	)
 	var i OAuth2ProviderApp
 	err := row.Scan(
@@ -5380,6 +5638,57 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg Update
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.ClientType,
+		&i.ServiceAccountID,
+		&i.DynamicallyRegistered,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppDeviceCodePolledAtByID = `-- name: UpdateOAuth2ProviderAppDeviceCodePolledAtByID :exec
+UPDATE oauth2_provider_app_device_codes SET
+    last_polled_at = $2
+WHERE id = $1
+`
+
+type UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppDeviceCodePolledAtByID(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateOAuth2ProviderAppDeviceCodePolledAtByID, arg.ID, arg.LastPolledAt)
+	return err
+}
+
+const updateOAuth2ProviderAppDeviceCodeStatusByID = `-- name: UpdateOAuth2ProviderAppDeviceCodeStatusByID :one
+UPDATE oauth2_provider_app_device_codes SET
+    status = $2,
+    user_id = $3
+WHERE id = $1 AND status = 'pending' RETURNING id, created_at, expires_at, last_polled_at, secret_prefix, hashed_secret, user_code, app_id, user_id, status, scope
+`
+
+type UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams struct {
+	ID     uuid.UUID                         `db:"id" json:"id"`
+	Status OAuth2ProviderAppDeviceCodeStatus `db:"status" json:"status"`
+	UserID uuid.NullUUID                     `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppDeviceCodeStatusByID, arg.ID, arg.Status, arg.UserID)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastPolledAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.Status,
+		&i.Scope,
 	)
 	return i, err
 }
@@ -8539,7 +8848,7 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganizationExcludeReserved(ctx contex
 
 const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 FROM
 	workspace_proxies
 WHERE
@@ -8570,6 +8879,7 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 			&i.DerpEnabled,
 			&i.DerpOnly,
 			&i.Version,
+			&i.DerpQuicPort,
 		); err != nil {
 			return nil, err
 		}
@@ -8586,7 +8896,7 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 
 const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 FROM
 	workspace_proxies
 WHERE
@@ -8646,13 +8956,14 @@ func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWor
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
 
 const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 FROM
 	workspace_proxies
 WHERE
@@ -8679,13 +8990,14 @@ func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (W
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
 
 const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 FROM
 	workspace_proxies
 WHERE
@@ -8713,6 +9025,7 @@ func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
@@ -8734,7 +9047,7 @@ INSERT INTO
 		deleted
 	)
 VALUES
-	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 `
 
 type InsertWorkspaceProxyParams struct {
@@ -8777,6 +9090,7 @@ func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspa
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
@@ -8789,11 +9103,12 @@ SET
 	wildcard_hostname = $2 :: text,
 	derp_enabled = $3 :: boolean,
 	derp_only = $4 :: boolean,
-	version = $5 :: text,
+	derp_quic_port = $5 :: integer,
+	version = $6 :: text,
 	updated_at = Now()
 WHERE
-	id = $6
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id = $7
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 `
 
 type RegisterWorkspaceProxyParams struct {
@@ -8801,6 +9116,7 @@ type RegisterWorkspaceProxyParams struct {
 	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
 	DerpEnabled      bool      `db:"derp_enabled" json:"derp_enabled"`
 	DerpOnly         bool      `db:"derp_only" json:"derp_only"`
+	DerpQuicPort     int32     `db:"derp_quic_port" json:"derp_quic_port"`
 	Version          string    `db:"version" json:"version"`
 	ID               uuid.UUID `db:"id" json:"id"`
 }
@@ -8811,6 +9127,7 @@ func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWor
 		arg.WildcardHostname,
 		arg.DerpEnabled,
 		arg.DerpOnly,
+		arg.DerpQuicPort,
 		arg.Version,
 		arg.ID,
 	)
@@ -8830,6 +9147,7 @@ func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWor
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
@@ -8852,7 +9170,7 @@ SET
 	updated_at = Now()
 WHERE
 	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version, derp_quic_port
 `
 
 type UpdateWorkspaceProxyParams struct {
@@ -8888,6 +9206,7 @@ func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspa
 		&i.DerpEnabled,
 		&i.DerpOnly,
 		&i.Version,
+		&i.DerpQuicPort,
 	)
 	return i, err
 }
@@ -10632,7 +10951,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording_enabled, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names
 WHERE
@@ -10674,6 +10993,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.SessionRecordingEnabled,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -10686,7 +11006,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording_enabled, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -10736,6 +11056,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.SessionRecordingEnabled,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -10747,7 +11068,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording_enabled, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -10790,6 +11111,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.SessionRecordingEnabled,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -10812,7 +11134,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording_enabled, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -10913,6 +11235,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.SessionRecordingEnabled,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -11091,7 +11414,8 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	session_recording_enabled = $11
 WHERE
 	id = $1
 `
@@ -11107,6 +11431,7 @@ type UpdateTemplateMetaByIDParams struct {
 	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
 	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	SessionRecordingEnabled      bool            `db:"session_recording_enabled" json:"session_recording_enabled"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -11121,6 +11446,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.GroupACL,
 		arg.MaxPortSharingLevel,
 		arg.UseClassicParameterFlow,
+		arg.SessionRecordingEnabled,
 	)
 	return err
 }
@@ -12513,90 +12839,532 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 	return i, err
 }
 
-const allUserIDs = `-- name: AllUserIDs :many
-SELECT DISTINCT id FROM USERS
-	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
-`
-
-// AllUserIDs returns all UserIDs regardless of user status or deletion.
-func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []uuid.UUID
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getActiveUserCount = `-- name: GetActiveUserCount :one
+const countUserMFARecoveryCodes = `-- name: CountUserMFARecoveryCodes :one
 SELECT
 	COUNT(*)
 FROM
-	users
+	user_mfa_recovery_codes
 WHERE
-	status = 'active'::user_status AND deleted = false
-	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+	user_id = $1
 `
 
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
+func (q *sqlQuerier) CountUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserMFARecoveryCodes, userID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
-SELECT
-	-- username and email are returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status, email,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				-- The roles are returned as a flat array, org scoped and site side.
-				-- Concatenating the organization id scopes the organization roles.
-				array_agg(org_roles || ':' || organization_members.organization_id::text)
-			FROM
-				organization_members,
-				-- All org_members get the organization-member role for their orgs
-				unnest(
-					array_append(roles, 'organization-member')
-				) AS org_roles
-			WHERE
-				user_id = users.id
-		)
-	) :: text[] AS roles,
-	-- All groups the user is in.
-	(
-		SELECT
-			array_agg(
-				group_members.group_id :: text
-			)
-		FROM
-			group_members
-		WHERE
-			user_id = users.id
-	) :: text[] AS groups
-FROM
-	users
+const deleteUserMFARecoveryCode = `-- name: DeleteUserMFARecoveryCode :execrows
+DELETE FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = $1
+	AND hashed_code = $2
+`
+
+type DeleteUserMFARecoveryCodeParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+}
+
+// Consumes a recovery code. No rows are deleted if the code is invalid or was
+// already used.
+func (q *sqlQuerier) DeleteUserMFARecoveryCode(ctx context.Context, arg DeleteUserMFARecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserMFARecoveryCode, arg.UserID, arg.HashedCode)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserMFARecoveryCodes = `-- name: DeleteUserMFARecoveryCodes :exec
+DELETE FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserMFARecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const getUserTOTPByUserID = `-- name: GetUserTOTPByUserID :one
+SELECT
+	user_id, secret, secret_key_id, verified_at, last_used_step, created_at, updated_at
+FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTPByUserID, userID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.SecretKeyID,
+		&i.VerifiedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertUserMFARecoveryCodes = `-- name: InsertUserMFARecoveryCodes :exec
+INSERT INTO
+	user_mfa_recovery_codes (
+		user_id,
+		hashed_code,
+		created_at
+	)
+SELECT
+	$1,
+	unnest($2 :: bytea[]),
+	$3
+`
+
+type InsertUserMFARecoveryCodesParams struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCodes [][]byte  `db:"hashed_codes" json:"hashed_codes"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertUserMFARecoveryCodes(ctx context.Context, arg InsertUserMFARecoveryCodesParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserMFARecoveryCodes, arg.UserID, pq.Array(arg.HashedCodes), arg.CreatedAt)
+	return err
+}
+
+const updateUserTOTPLastUsedStep = `-- name: UpdateUserTOTPLastUsedStep :execrows
+UPDATE
+	user_totp
+SET
+	last_used_step = $1,
+	verified_at = COALESCE(verified_at, $2),
+	updated_at = $2
+WHERE
+	user_id = $3
+	AND last_used_step < $1
+`
+
+type UpdateUserTOTPLastUsedStepParams struct {
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Records the time step of an accepted code and marks the factor as verified.
+// No rows are updated if a code for the same or a later step was already
+// accepted, which callers must treat as a replayed code.
+func (q *sqlQuerier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserTOTPLastUsedStep, arg.LastUsedStep, arg.UpdatedAt, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserTOTPSecret = `-- name: UpdateUserTOTPSecret :exec
+UPDATE
+	user_totp
+SET
+	secret = $1,
+	secret_key_id = $2
+WHERE
+	user_id = $3
+`
+
+type UpdateUserTOTPSecretParams struct {
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+}
+
+// Replaces the stored secret without restarting enrollment, e.g. to encrypt
+// it with a different key.
+func (q *sqlQuerier) UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserTOTPSecret, arg.Secret, arg.SecretKeyID, arg.UserID)
+	return err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO
+	user_totp (
+		user_id,
+		secret,
+		secret_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (user_id)
+DO UPDATE SET
+	secret = $2,
+	secret_key_id = $3,
+	verified_at = NULL,
+	last_used_step = 0,
+	created_at = $4,
+	updated_at = $4
+RETURNING user_id, secret, secret_key_id, verified_at, last_used_step, created_at, updated_at
+`
+
+type UpsertUserTOTPParams struct {
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
+// Starts, or restarts, enrollment of a TOTP second factor. The factor is
+// unverified until the user submits a code generated with the new secret.
+func (q *sqlQuerier) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserTOTP,
+		arg.UserID,
+		arg.Secret,
+		arg.SecretKeyID,
+		arg.CreatedAt,
+	)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.SecretKeyID,
+		&i.VerifiedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteUserSecret = `-- name: DeleteUserSecret :exec
+DELETE FROM
+	user_secrets
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteUserSecret(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSecret, id)
+	return err
+}
+
+const getUserSecretByID = `-- name: GetUserSecretByID :one
+SELECT
+	id, user_id, name, description, value, value_key_id, env_name, file_path, created_at, updated_at
+FROM
+	user_secrets
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetUserSecretByID(ctx context.Context, id uuid.UUID) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretByID, id)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserSecretByUserIDAndName = `-- name: GetUserSecretByUserIDAndName :one
+SELECT
+	id, user_id, name, description, value, value_key_id, env_name, file_path, created_at, updated_at
+FROM
+	user_secrets
+WHERE
+	user_id = $1 AND name = $2
+`
+
+type GetUserSecretByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserSecretsByUserID = `-- name: GetUserSecretsByUserID :many
+SELECT
+	id, user_id, name, description, value, value_key_id, env_name, file_path, created_at, updated_at
+FROM
+	user_secrets
+WHERE
+	user_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]UserSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSecretsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserSecret
+	for rows.Next() {
+		var i UserSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.EnvName,
+			&i.FilePath,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserSecret = `-- name: InsertUserSecret :one
+INSERT INTO
+	user_secrets (
+		id,
+		user_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		file_path,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_id, name, description, value, value_key_id, env_name, file_path, created_at, updated_at
+`
+
+type InsertUserSecretParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	FilePath    string         `db:"file_path" json:"file_path"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertUserSecret(ctx context.Context, arg InsertUserSecretParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertUserSecret,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.FilePath,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserSecret = `-- name: UpdateUserSecret :one
+UPDATE
+	user_secrets
+SET
+	description = $1,
+	value = $2,
+	value_key_id = $3,
+	env_name = $4,
+	file_path = $5,
+	updated_at = $6
+WHERE
+	id = $7
+RETURNING id, user_id, name, description, value, value_key_id, env_name, file_path, created_at, updated_at
+`
+
+type UpdateUserSecretParams struct {
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	FilePath    string         `db:"file_path" json:"file_path"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID      `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateUserSecret(ctx context.Context, arg UpdateUserSecretParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSecret,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.FilePath,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const allUserIDs = `-- name: AllUserIDs :many
+SELECT DISTINCT id FROM USERS
+	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+// AllUserIDs returns all UserIDs regardless of user status or deletion.
+func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username and email are returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status, email,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				-- The roles are returned as a flat array, org scoped and site side.
+				-- Concatenating the organization id scopes the organization roles.
+				array_agg(org_roles || ':' || organization_members.organization_id::text)
+			FROM
+				organization_members,
+				-- All org_members get the organization-member role for their orgs
+				unnest(
+					array_append(roles, 'organization-member')
+				) AS org_roles
+			WHERE
+				user_id = users.id
+		)
+	) :: text[] AS roles,
+	-- All groups the user is in.
+	(
+		SELECT
+			array_agg(
+				group_members.group_id :: text
+			)
+		FROM
+			group_members
+		WHERE
+			user_id = users.id
+	) :: text[] AS groups
+FROM
+	users
 WHERE
 	id = $1
 `
@@ -13443,90 +14211,358 @@ WHERE
 	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'terminal_font', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'terminal_font'
+RETURNING user_id, key, value
+`
+
+type UpdateUserTerminalFontParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
+}
+
+func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_preference', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_preference'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemePreferenceParams struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+}
+
+func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const deleteExpiredWebAuthnChallenges = `-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM
+	webauthn_challenges
+WHERE
+	expires_at < $1
+`
+
+func (q *sqlQuerier) DeleteExpiredWebAuthnChallenges(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredWebAuthnChallenges, beforeTime)
+	return err
+}
+
+const deleteWebAuthnChallenge = `-- name: DeleteWebAuthnChallenge :one
+DELETE FROM
+	webauthn_challenges
+WHERE
+	challenge = $1
+RETURNING challenge, session, expires_at
+`
+
+// Consumes a challenge, so every ceremony can only be completed once.
+func (q *sqlQuerier) DeleteWebAuthnChallenge(ctx context.Context, challenge string) (WebAuthnChallenge, error) {
+	row := q.db.QueryRowContext(ctx, deleteWebAuthnChallenge, challenge)
+	var i WebAuthnChallenge
+	err := row.Scan(&i.Challenge, &i.Session, &i.ExpiresAt)
+	return i, err
+}
+
+const deleteWebAuthnCredential = `-- name: DeleteWebAuthnCredential :exec
+DELETE FROM
+	webauthn_credentials
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebAuthnCredential, id)
+	return err
+}
+
+const getWebAuthnCredentialByCredentialID = `-- name: GetWebAuthnCredentialByCredentialID :one
+SELECT
+	id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, created_at, last_used_at
+FROM
+	webauthn_credentials
+WHERE
+	credential_id = $1
+`
+
+func (q *sqlQuerier) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getWebAuthnCredentialByCredentialID, credentialID)
+	var i WebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.AttestationType,
+		pq.Array(&i.Transports),
+		&i.AAGUID,
+		&i.SignCount,
+		&i.BackupEligible,
+		&i.BackupState,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getWebAuthnCredentialByID = `-- name: GetWebAuthnCredentialByID :one
+SELECT
+	id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, created_at, last_used_at
+FROM
+	webauthn_credentials
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (WebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getWebAuthnCredentialByID, id)
+	var i WebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.AttestationType,
+		pq.Array(&i.Transports),
+		&i.AAGUID,
+		&i.SignCount,
+		&i.BackupEligible,
+		&i.BackupState,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getWebAuthnCredentialsByUserID = `-- name: GetWebAuthnCredentialsByUserID :many
+SELECT
+	id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, created_at, last_used_at
+FROM
+	webauthn_credentials
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]WebAuthnCredential, error) {
+	rows, err := q.db.QueryContext(ctx, getWebAuthnCredentialsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebAuthnCredential
+	for rows.Next() {
+		var i WebAuthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.AttestationType,
+			pq.Array(&i.Transports),
+			&i.AAGUID,
+			&i.SignCount,
+			&i.BackupEligible,
+			&i.BackupState,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWebAuthnChallenge = `-- name: InsertWebAuthnChallenge :exec
+INSERT INTO
+	webauthn_challenges (
+		challenge,
+		session,
+		expires_at
+	)
+VALUES
+	($1, $2, $3)
+`
+
+type InsertWebAuthnChallengeParams struct {
+	Challenge string          `db:"challenge" json:"challenge"`
+	Session   json.RawMessage `db:"session" json:"session"`
+	ExpiresAt time.Time       `db:"expires_at" json:"expires_at"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-	)
-	return i, err
+func (q *sqlQuerier) InsertWebAuthnChallenge(ctx context.Context, arg InsertWebAuthnChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, insertWebAuthnChallenge, arg.Challenge, arg.Session, arg.ExpiresAt)
+	return err
 }
 
-const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
+const insertWebAuthnCredential = `-- name: InsertWebAuthnCredential :one
 INSERT INTO
-	user_configs (user_id, key, value)
+	webauthn_credentials (
+		id,
+		user_id,
+		name,
+		credential_id,
+		public_key,
+		attestation_type,
+		transports,
+		aaguid,
+		sign_count,
+		backup_eligible,
+		backup_state,
+		created_at
+	)
 VALUES
-	($1, 'terminal_font', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'terminal_font'
-RETURNING user_id, key, value
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, created_at, last_used_at
 `
 
-type UpdateUserTerminalFontParams struct {
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
+type InsertWebAuthnCredentialParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	Name            string    `db:"name" json:"name"`
+	CredentialID    []byte    `db:"credential_id" json:"credential_id"`
+	PublicKey       []byte    `db:"public_key" json:"public_key"`
+	AttestationType string    `db:"attestation_type" json:"attestation_type"`
+	Transports      []string  `db:"transports" json:"transports"`
+	AAGUID          []byte    `db:"aaguid" json:"aaguid"`
+	SignCount       int64     `db:"sign_count" json:"sign_count"`
+	BackupEligible  bool      `db:"backup_eligible" json:"backup_eligible"`
+	BackupState     bool      `db:"backup_state" json:"backup_state"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+func (q *sqlQuerier) InsertWebAuthnCredential(ctx context.Context, arg InsertWebAuthnCredentialParams) (WebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, insertWebAuthnCredential,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.AttestationType,
+		pq.Array(arg.Transports),
+		arg.AAGUID,
+		arg.SignCount,
+		arg.BackupEligible,
+		arg.BackupState,
+		arg.CreatedAt,
+	)
+	var i WebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.AttestationType,
+		pq.Array(&i.Transports),
+		&i.AAGUID,
+		&i.SignCount,
+		&i.BackupEligible,
+		&i.BackupState,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
 	return i, err
 }
 
-const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_preference', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
+const updateWebAuthnCredentialUsage = `-- name: UpdateWebAuthnCredentialUsage :exec
+UPDATE
+	webauthn_credentials
 SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_preference'
-RETURNING user_id, key, value
+	sign_count = $1,
+	backup_state = $2,
+	last_used_at = $3
+WHERE
+	id = $4
 `
 
-type UpdateUserThemePreferenceParams struct {
-	UserID          uuid.UUID `db:"user_id" json:"user_id"`
-	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+type UpdateWebAuthnCredentialUsageParams struct {
+	SignCount   int64        `db:"sign_count" json:"sign_count"`
+	BackupState bool         `db:"backup_state" json:"backup_state"`
+	LastUsedAt  sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	ID          uuid.UUID    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
-	return i, err
+func (q *sqlQuerier) UpdateWebAuthnCredentialUsage(ctx context.Context, arg UpdateWebAuthnCredentialUsageParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebAuthnCredentialUsage,
+		arg.SignCount,
+		arg.BackupState,
+		arg.LastUsedAt,
+		arg.ID,
+	)
+	return err
 }
 
 const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
@@ -13876,6 +14912,48 @@ func (q *sqlQuerier) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context
 	return items, nil
 }
 
+const fetchResourceMonitorsByAgentID = `-- name: FetchResourceMonitorsByAgentID :many
+SELECT
+	agent_id, type, path, enabled, threshold, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_resource_monitors
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchResourceMonitorsByAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Type,
+			&i.Path,
+			&i.Enabled,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchVolumesResourceMonitorsByAgentID = `-- name: FetchVolumesResourceMonitorsByAgentID :many
 SELECT
 	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
@@ -14006,6 +15084,62 @@ func (q *sqlQuerier) InsertMemoryResourceMonitor(ctx context.Context, arg Insert
 	return i, err
 }
 
+const insertResourceMonitor = `-- name: InsertResourceMonitor :one
+INSERT INTO
+	workspace_agent_resource_monitors (
+		agent_id,
+		type,
+		path,
+		enabled,
+		state,
+		threshold,
+		created_at,
+		updated_at,
+		debounced_until
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING agent_id, type, path, enabled, threshold, created_at, updated_at, state, debounced_until
+`
+
+type InsertResourceMonitorParams struct {
+	AgentID        uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type           WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	Path           string                            `db:"path" json:"path"`
+	Enabled        bool                              `db:"enabled" json:"enabled"`
+	State          WorkspaceAgentMonitorState        `db:"state" json:"state"`
+	Threshold      int32                             `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                         `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                         `db:"updated_at" json:"updated_at"`
+	DebouncedUntil time.Time                         `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) InsertResourceMonitor(ctx context.Context, arg InsertResourceMonitorParams) (WorkspaceAgentResourceMonitor, error) {
+	row := q.db.QueryRowContext(ctx, insertResourceMonitor,
+		arg.AgentID,
+		arg.Type,
+		arg.Path,
+		arg.Enabled,
+		arg.State,
+		arg.Threshold,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DebouncedUntil,
+	)
+	var i WorkspaceAgentResourceMonitor
+	err := row.Scan(
+		&i.AgentID,
+		&i.Type,
+		&i.Path,
+		&i.Enabled,
+		&i.Threshold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.State,
+		&i.DebouncedUntil,
+	)
+	return i, err
+}
+
 const insertVolumeResourceMonitor = `-- name: InsertVolumeResourceMonitor :one
 INSERT INTO
 	workspace_agent_volume_resource_monitors (
@@ -14085,6 +15219,37 @@ func (q *sqlQuerier) UpdateMemoryResourceMonitor(ctx context.Context, arg Update
 	return err
 }
 
+const updateResourceMonitor = `-- name: UpdateResourceMonitor :exec
+UPDATE workspace_agent_resource_monitors
+SET
+	updated_at = $4,
+	state = $5,
+	debounced_until = $6
+WHERE
+	agent_id = $1 AND type = $2 AND path = $3
+`
+
+type UpdateResourceMonitorParams struct {
+	AgentID        uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type           WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	Path           string                            `db:"path" json:"path"`
+	UpdatedAt      time.Time                         `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState        `db:"state" json:"state"`
+	DebouncedUntil time.Time                         `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) UpdateResourceMonitor(ctx context.Context, arg UpdateResourceMonitorParams) error {
+	_, err := q.db.ExecContext(ctx, updateResourceMonitor,
+		arg.AgentID,
+		arg.Type,
+		arg.Path,
+		arg.UpdatedAt,
+		arg.State,
+		arg.DebouncedUntil,
+	)
+	return err
+}
+
 const updateVolumeResourceMonitor = `-- name: UpdateVolumeResourceMonitor :exec
 UPDATE workspace_agent_volume_resource_monitors
 SET
@@ -15371,44 +16536,228 @@ type UpdateWorkspaceAgentMetadataParams struct {
 	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentMetadata,
+func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		pq.Array(arg.Key),
+		pq.Array(arg.Value),
+		pq.Array(arg.Error),
+		pq.Array(arg.CollectedAt),
+	)
+	return err
+}
+
+const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+UPDATE
+	workspace_agents
+SET
+	version = $2,
+	expanded_directory = $3,
+	subsystems = $4,
+	api_version = $5
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentStartupByIDParams struct {
+	ID                uuid.UUID                 `db:"id" json:"id"`
+	Version           string                    `db:"version" json:"version"`
+	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
+	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+	APIVersion        string                    `db:"api_version" json:"api_version"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+		arg.ID,
+		arg.Version,
+		arg.ExpandedDirectory,
+		pq.Array(arg.Subsystems),
+		arg.APIVersion,
+	)
+	return err
+}
+
+const getWorkspaceAgentServicesByAgentIDs = `-- name: GetWorkspaceAgentServicesByAgentIDs :many
+SELECT id, workspace_agent_id, log_source_id, log_path, created_at, name, display_name, icon, command, restart_policy, restart_delay_seconds, readiness_url, readiness_interval_seconds, readiness_threshold, status, health, restarts, exit_code, updated_at FROM workspace_agent_services WHERE workspace_agent_id = ANY($1 :: uuid [ ]) ORDER BY name ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentService, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentServicesByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentService
+	for rows.Next() {
+		var i WorkspaceAgentService
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.RestartPolicy,
+			&i.RestartDelaySeconds,
+			&i.ReadinessURL,
+			&i.ReadinessIntervalSeconds,
+			&i.ReadinessThreshold,
+			&i.Status,
+			&i.Health,
+			&i.Restarts,
+			&i.ExitCode,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentServices = `-- name: InsertWorkspaceAgentServices :many
+INSERT INTO
+	workspace_agent_services (workspace_agent_id, created_at, updated_at, id, log_source_id, log_path, name, display_name, icon, command, restart_policy, restart_delay_seconds, readiness_url, readiness_interval_seconds, readiness_threshold, health)
+SELECT
+	$1 :: uuid AS workspace_agent_id,
+	$2 :: timestamptz AS created_at,
+	$2 :: timestamptz AS updated_at,
+	unnest($3 :: uuid [ ]) AS id,
+	unnest($4 :: uuid [ ]) AS log_source_id,
+	unnest($5 :: text [ ]) AS log_path,
+	unnest($6 :: text [ ]) AS name,
+	unnest($7 :: text [ ]) AS display_name,
+	unnest($8 :: text [ ]) AS icon,
+	unnest($9 :: text [ ]) AS command,
+	unnest($10 :: workspace_agent_service_restart_policy [ ]) AS restart_policy,
+	unnest($11 :: integer [ ]) AS restart_delay_seconds,
+	unnest($12 :: text [ ]) AS readiness_url,
+	unnest($13 :: integer [ ]) AS readiness_interval_seconds,
+	unnest($14 :: integer [ ]) AS readiness_threshold,
+	unnest($15 :: workspace_app_health [ ]) AS health
+RETURNING workspace_agent_services.id, workspace_agent_services.workspace_agent_id, workspace_agent_services.log_source_id, workspace_agent_services.log_path, workspace_agent_services.created_at, workspace_agent_services.name, workspace_agent_services.display_name, workspace_agent_services.icon, workspace_agent_services.command, workspace_agent_services.restart_policy, workspace_agent_services.restart_delay_seconds, workspace_agent_services.readiness_url, workspace_agent_services.readiness_interval_seconds, workspace_agent_services.readiness_threshold, workspace_agent_services.status, workspace_agent_services.health, workspace_agent_services.restarts, workspace_agent_services.exit_code, workspace_agent_services.updated_at
+`
+
+type InsertWorkspaceAgentServicesParams struct {
+	WorkspaceAgentID         uuid.UUID                            `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt                time.Time                            `db:"created_at" json:"created_at"`
+	ID                       []uuid.UUID                          `db:"id" json:"id"`
+	LogSourceID              []uuid.UUID                          `db:"log_source_id" json:"log_source_id"`
+	LogPath                  []string                             `db:"log_path" json:"log_path"`
+	Name                     []string                             `db:"name" json:"name"`
+	DisplayName              []string                             `db:"display_name" json:"display_name"`
+	Icon                     []string                             `db:"icon" json:"icon"`
+	Command                  []string                             `db:"command" json:"command"`
+	RestartPolicy            []WorkspaceAgentServiceRestartPolicy `db:"restart_policy" json:"restart_policy"`
+	RestartDelaySeconds      []int32                              `db:"restart_delay_seconds" json:"restart_delay_seconds"`
+	ReadinessURL             []string                             `db:"readiness_url" json:"readiness_url"`
+	ReadinessIntervalSeconds []int32                              `db:"readiness_interval_seconds" json:"readiness_interval_seconds"`
+	ReadinessThreshold       []int32                              `db:"readiness_threshold" json:"readiness_threshold"`
+	Health                   []WorkspaceAppHealth                 `db:"health" json:"health"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentServices(ctx context.Context, arg InsertWorkspaceAgentServicesParams) ([]WorkspaceAgentService, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentServices,
 		arg.WorkspaceAgentID,
-		pq.Array(arg.Key),
-		pq.Array(arg.Value),
-		pq.Array(arg.Error),
-		pq.Array(arg.CollectedAt),
+		arg.CreatedAt,
+		pq.Array(arg.ID),
+		pq.Array(arg.LogSourceID),
+		pq.Array(arg.LogPath),
+		pq.Array(arg.Name),
+		pq.Array(arg.DisplayName),
+		pq.Array(arg.Icon),
+		pq.Array(arg.Command),
+		pq.Array(arg.RestartPolicy),
+		pq.Array(arg.RestartDelaySeconds),
+		pq.Array(arg.ReadinessURL),
+		pq.Array(arg.ReadinessIntervalSeconds),
+		pq.Array(arg.ReadinessThreshold),
+		pq.Array(arg.Health),
 	)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentService
+	for rows.Next() {
+		var i WorkspaceAgentService
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.RestartPolicy,
+			&i.RestartDelaySeconds,
+			&i.ReadinessURL,
+			&i.ReadinessIntervalSeconds,
+			&i.ReadinessThreshold,
+			&i.Status,
+			&i.Health,
+			&i.Restarts,
+			&i.ExitCode,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+const updateWorkspaceAgentServiceStatusByID = `-- name: UpdateWorkspaceAgentServiceStatusByID :exec
 UPDATE
-	workspace_agents
+	workspace_agent_services
 SET
-	version = $2,
-	expanded_directory = $3,
-	subsystems = $4,
-	api_version = $5
+	status = $1,
+	health = $2,
+	restarts = $3,
+	exit_code = $4,
+	updated_at = $5
 WHERE
-	id = $1
+	id = $6
+	AND workspace_agent_id = $7
 `
 
-type UpdateWorkspaceAgentStartupByIDParams struct {
-	ID                uuid.UUID                 `db:"id" json:"id"`
-	Version           string                    `db:"version" json:"version"`
-	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
-	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
-	APIVersion        string                    `db:"api_version" json:"api_version"`
+type UpdateWorkspaceAgentServiceStatusByIDParams struct {
+	Status           WorkspaceAgentServiceStatus `db:"status" json:"status"`
+	Health           WorkspaceAppHealth          `db:"health" json:"health"`
+	Restarts         int32                       `db:"restarts" json:"restarts"`
+	ExitCode         sql.NullInt32               `db:"exit_code" json:"exit_code"`
+	UpdatedAt        time.Time                   `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID                   `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                   `db:"workspace_agent_id" json:"workspace_agent_id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+func (q *sqlQuerier) UpdateWorkspaceAgentServiceStatusByID(ctx context.Context, arg UpdateWorkspaceAgentServiceStatusByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentServiceStatusByID,
+		arg.Status,
+		arg.Health,
+		arg.Restarts,
+		arg.ExitCode,
+		arg.UpdatedAt,
 		arg.ID,
-		arg.Version,
-		arg.ExpandedDirectory,
-		pq.Array(arg.Subsystems),
-		arg.APIVersion,
+		arg.WorkspaceAgentID,
 	)
 	return err
 }
@@ -18578,7 +19927,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording_enabled
 	FROM
 		templates
 	WHERE
@@ -19783,3 +21132,294 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const deleteOldWorkspaceSessionRecordings = `-- name: DeleteOldWorkspaceSessionRecordings :exec
+DELETE FROM
+	workspace_session_recordings
+WHERE
+	started_at < $1 :: timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceSessionRecordings, beforeTime)
+	return err
+}
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	id, organization_id, workspace_id, owner_id, agent_id, type, width, height, started_at, ended_at, size, created_at
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.WorkspaceID,
+		&i.OwnerID,
+		&i.AgentID,
+		&i.Type,
+		&i.Width,
+		&i.Height,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingChunks = `-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	recording_id, sequence, data, created_at
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+	AND sequence > $2 :: int
+ORDER BY
+	sequence ASC
+LIMIT
+	$3 :: int
+`
+
+type GetWorkspaceSessionRecordingChunksParams struct {
+	RecordingID   uuid.UUID `db:"recording_id" json:"recording_id"`
+	AfterSequence int32     `db:"after_sequence" json:"after_sequence"`
+	LimitOpt      int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// GetWorkspaceSessionRecordingChunks returns the chunks of a recording after
+// the given sequence number, so that large recordings can be streamed in
+// batches.
+func (q *sqlQuerier) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg GetWorkspaceSessionRecordingChunksParams) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingChunks, arg.RecordingID, arg.AfterSequence, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(
+			&i.RecordingID,
+			&i.Sequence,
+			&i.Data,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordings = `-- name: GetWorkspaceSessionRecordings :many
+SELECT
+	workspace_session_recordings.id, workspace_session_recordings.organization_id, workspace_session_recordings.workspace_id, workspace_session_recordings.owner_id, workspace_session_recordings.agent_id, workspace_session_recordings.type, workspace_session_recordings.width, workspace_session_recordings.height, workspace_session_recordings.started_at, workspace_session_recordings.ended_at, workspace_session_recordings.size, workspace_session_recordings.created_at,
+	workspaces.name AS workspace_name,
+	users.username AS owner_username
+FROM
+	workspace_session_recordings
+INNER JOIN
+	workspaces ON workspace_session_recordings.workspace_id = workspaces.id
+INNER JOIN
+	users ON workspace_session_recordings.owner_id = users.id
+WHERE
+	true
+	AND CASE
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.workspace_id = $1
+		ELSE true
+	END
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.owner_id = $2
+		ELSE true
+	END
+ORDER BY
+	workspace_session_recordings.started_at DESC, workspace_session_recordings.id DESC
+OFFSET $3
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
+`
+
+type GetWorkspaceSessionRecordingsParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID     uuid.UUID `db:"owner_id" json:"owner_id"`
+	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetWorkspaceSessionRecordingsRow struct {
+	WorkspaceSessionRecording WorkspaceSessionRecording `db:"workspace_session_recording" json:"workspace_session_recording"`
+	WorkspaceName             string                    `db:"workspace_name" json:"workspace_name"`
+	OwnerUsername             string                    `db:"owner_username" json:"owner_username"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordings(ctx context.Context, arg GetWorkspaceSessionRecordingsParams) ([]GetWorkspaceSessionRecordingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordings,
+		arg.WorkspaceID,
+		arg.OwnerID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceSessionRecordingsRow
+	for rows.Next() {
+		var i GetWorkspaceSessionRecordingsRow
+		if err := rows.Scan(
+			&i.WorkspaceSessionRecording.ID,
+			&i.WorkspaceSessionRecording.OrganizationID,
+			&i.WorkspaceSessionRecording.WorkspaceID,
+			&i.WorkspaceSessionRecording.OwnerID,
+			&i.WorkspaceSessionRecording.AgentID,
+			&i.WorkspaceSessionRecording.Type,
+			&i.WorkspaceSessionRecording.Width,
+			&i.WorkspaceSessionRecording.Height,
+			&i.WorkspaceSessionRecording.StartedAt,
+			&i.WorkspaceSessionRecording.EndedAt,
+			&i.WorkspaceSessionRecording.Size,
+			&i.WorkspaceSessionRecording.CreatedAt,
+			&i.WorkspaceName,
+			&i.OwnerUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSessionRecording = `-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		organization_id,
+		workspace_id,
+		owner_id,
+		agent_id,
+		type,
+		width,
+		height,
+		started_at,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, organization_id, workspace_id, owner_id, agent_id, type, width, height, started_at, ended_at, size, created_at
+`
+
+type InsertWorkspaceSessionRecordingParams struct {
+	ID             uuid.UUID            `db:"id" json:"id"`
+	OrganizationID uuid.UUID            `db:"organization_id" json:"organization_id"`
+	WorkspaceID    uuid.UUID            `db:"workspace_id" json:"workspace_id"`
+	OwnerID        uuid.UUID            `db:"owner_id" json:"owner_id"`
+	AgentID        uuid.UUID            `db:"agent_id" json:"agent_id"`
+	Type           SessionRecordingType `db:"type" json:"type"`
+	Width          int32                `db:"width" json:"width"`
+	Height         int32                `db:"height" json:"height"`
+	StartedAt      time.Time            `db:"started_at" json:"started_at"`
+	CreatedAt      time.Time            `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSessionRecording,
+		arg.ID,
+		arg.OrganizationID,
+		arg.WorkspaceID,
+		arg.OwnerID,
+		arg.AgentID,
+		arg.Type,
+		arg.Width,
+		arg.Height,
+		arg.StartedAt,
+		arg.CreatedAt,
+	)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.WorkspaceID,
+		&i.OwnerID,
+		&i.AgentID,
+		&i.Type,
+		&i.Width,
+		&i.Height,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceSessionRecordingChunk = `-- name: InsertWorkspaceSessionRecordingChunk :exec
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		sequence,
+		data,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+`
+
+type InsertWorkspaceSessionRecordingChunkParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceSessionRecordingChunk,
+		arg.RecordingID,
+		arg.Sequence,
+		arg.Data,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const updateWorkspaceSessionRecordingByID = `-- name: UpdateWorkspaceSessionRecordingByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	size = size + $1 :: bigint,
+	ended_at = COALESCE($2, ended_at)
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceSessionRecordingByIDParams struct {
+	AddedSize int64        `db:"added_size" json:"added_size"`
+	EndedAt   sql.NullTime `db:"ended_at" json:"ended_at"`
+	ID        uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg UpdateWorkspaceSessionRecordingByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSessionRecordingByID, arg.AddedSize, arg.EndedAt, arg.ID)
+	return err
+}
@@ -14,6 +14,14 @@ AND
 
 -- name: InsertDBCryptKey :exec
 INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES (@number::int, @active_key_digest::text, CURRENT_TIMESTAMP, @test::text);
+	(number, active_key_digest, created_at, test, wrapped_key, wrapping_key_id)
+VALUES (@number::int, @active_key_digest::text, CURRENT_TIMESTAMP, @test::text, sqlc.narg('wrapped_key')::text, sqlc.narg('wrapping_key_id')::text);
+
+-- name: UpdateDBCryptKeyWrappedKey :exec
+UPDATE dbcrypt_keys
+SET
+	wrapped_key = @wrapped_key::text,
+	wrapping_key_id = @wrapping_key_id::text
+WHERE
+	number = @number::int;
 
@@ -11,14 +11,20 @@ INSERT INTO oauth2_provider_apps (
     updated_at,
     name,
     icon,
-    callback_url
+    callback_url,
+    client_type,
+    service_account_id,
+    dynamically_registered
 ) VALUES(
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8,
+    $9
 ) RETURNING *;
 
 -- name: UpdateOAuth2ProviderAppByID :one
@@ -26,7 +32,9 @@ UPDATE oauth2_provider_apps SET
     updated_at = $2,
     name = $3,
     icon = $4,
-    callback_url = $5
+    callback_url = $5,
+    client_type = $6,
+    service_account_id = $7
 WHERE id = $1 RETURNING *;
 
 -- name: DeleteOAuth2ProviderAppByID :exec
@@ -80,7 +88,10 @@ INSERT INTO oauth2_provider_app_codes (
     secret_prefix,
     hashed_secret,
     app_id,
-    user_id
+    user_id,
+    code_challenge,
+    code_challenge_method,
+    scope
 ) VALUES(
     $1,
     $2,
@@ -88,7 +99,10 @@ INSERT INTO oauth2_provider_app_codes (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9,
+    $10
 ) RETURNING *;
 
 -- name: DeleteOAuth2ProviderAppCodeByID :exec
@@ -105,7 +119,8 @@ INSERT INTO oauth2_provider_app_tokens (
     hash_prefix,
     refresh_hash,
     app_secret_id,
-    api_key_id
+    api_key_id,
+    app_id
 ) VALUES(
     $1,
     $2,
@@ -113,21 +128,23 @@ INSERT INTO oauth2_provider_app_tokens (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8
 ) RETURNING *;
 
 -- name: GetOAuth2ProviderAppTokenByPrefix :one
 SELECT * FROM oauth2_provider_app_tokens WHERE hash_prefix = $1;
 
+-- name: GetOAuth2ProviderAppTokenByAPIKeyID :one
+SELECT * FROM oauth2_provider_app_tokens WHERE api_key_id = $1;
+
 -- name: GetOAuth2ProviderAppsByUserID :many
 SELECT
   COUNT(DISTINCT oauth2_provider_app_tokens.id) as token_count,
   sqlc.embed(oauth2_provider_apps)
 FROM oauth2_provider_app_tokens
-  INNER JOIN oauth2_provider_app_secrets
-    ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
   INNER JOIN oauth2_provider_apps
-    ON oauth2_provider_apps.id = oauth2_provider_app_secrets.app_id
+    ON oauth2_provider_apps.id = oauth2_provider_app_tokens.app_id
   INNER JOIN api_keys
     ON api_keys.id = oauth2_provider_app_tokens.api_key_id
 WHERE
@@ -139,9 +156,63 @@ GROUP BY
 DELETE FROM
   oauth2_provider_app_tokens
 USING
-  oauth2_provider_app_secrets, api_keys
+  api_keys
 WHERE
-  oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
-  AND api_keys.id = oauth2_provider_app_tokens.api_key_id
-  AND oauth2_provider_app_secrets.app_id = $1
+  api_keys.id = oauth2_provider_app_tokens.api_key_id
+  AND oauth2_provider_app_tokens.app_id = $1
 	AND api_keys.user_id = $2;
+
+-- name: InsertOAuth2ProviderAppDeviceCode :one
+INSERT INTO oauth2_provider_app_device_codes (
+    id,
+    created_at,
+    expires_at,
+    secret_prefix,
+    hashed_secret,
+    user_code,
+    app_id,
+    scope
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING *;
+
+-- name: GetOAuth2ProviderAppDeviceCodeByPrefix :one
+SELECT * FROM oauth2_provider_app_device_codes WHERE secret_prefix = $1;
+
+-- name: GetOAuth2ProviderAppDeviceCodeByUserCode :one
+SELECT * FROM oauth2_provider_app_device_codes WHERE user_code = $1;
+
+-- name: UpdateOAuth2ProviderAppDeviceCodeStatusByID :one
+UPDATE oauth2_provider_app_device_codes SET
+    status = $2,
+    user_id = $3
+WHERE id = $1 AND status = 'pending' RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppDeviceCodePolledAtByID :exec
+UPDATE oauth2_provider_app_device_codes SET
+    last_polled_at = $2
+WHERE id = $1;
+
+-- name: DeleteOAuth2ProviderAppDeviceCodeByID :exec
+DELETE FROM oauth2_provider_app_device_codes WHERE id = $1;
+
+-- name: DeleteUnusedDynamicOAuth2ProviderApps :exec
+-- Anyone can register an app when dynamic client registration is open, so
+-- apps that were never granted a token are deleted once they are old enough
+-- that they are not waiting on a user.
+DELETE FROM
+	oauth2_provider_apps
+WHERE
+	dynamically_registered
+	AND created_at < @before_time :: timestamptz
+	AND NOT EXISTS (
+		SELECT 1 FROM oauth2_provider_app_tokens
+		WHERE oauth2_provider_app_tokens.app_id = oauth2_provider_apps.id
+	);
@@ -25,6 +25,7 @@ SET
 	wildcard_hostname = @wildcard_hostname :: text,
 	derp_enabled = @derp_enabled :: boolean,
 	derp_only = @derp_only :: boolean,
+	derp_quic_port = @derp_quic_port :: integer,
 	version = @version :: text,
 	updated_at = Now()
 WHERE
@@ -0,0 +1,96 @@
+-- name: GetUserTOTPByUserID :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserTOTP :one
+-- Starts, or restarts, enrollment of a TOTP second factor. The factor is
+-- unverified until the user submits a code generated with the new secret.
+INSERT INTO
+	user_totp (
+		user_id,
+		secret,
+		secret_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (user_id)
+DO UPDATE SET
+	secret = $2,
+	secret_key_id = $3,
+	verified_at = NULL,
+	last_used_step = 0,
+	created_at = $4,
+	updated_at = $4
+RETURNING *;
+
+-- name: UpdateUserTOTPSecret :exec
+-- Replaces the stored secret without restarting enrollment, e.g. to encrypt
+-- it with a different key.
+UPDATE
+	user_totp
+SET
+	secret = @secret,
+	secret_key_id = @secret_key_id
+WHERE
+	user_id = @user_id;
+
+-- name: UpdateUserTOTPLastUsedStep :execrows
+-- Records the time step of an accepted code and marks the factor as verified.
+-- No rows are updated if a code for the same or a later step was already
+-- accepted, which callers must treat as a replayed code.
+UPDATE
+	user_totp
+SET
+	last_used_step = @last_used_step,
+	verified_at = COALESCE(verified_at, @updated_at),
+	updated_at = @updated_at
+WHERE
+	user_id = @user_id
+	AND last_used_step < @last_used_step;
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: InsertUserMFARecoveryCodes :exec
+INSERT INTO
+	user_mfa_recovery_codes (
+		user_id,
+		hashed_code,
+		created_at
+	)
+SELECT
+	@user_id,
+	unnest(@hashed_codes :: bytea[]),
+	@created_at;
+
+-- name: CountUserMFARecoveryCodes :one
+SELECT
+	COUNT(*)
+FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = $1;
+
+-- name: DeleteUserMFARecoveryCode :execrows
+-- Consumes a recovery code. No rows are deleted if the code is invalid or was
+-- already used.
+DELETE FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = $1
+	AND hashed_code = $2;
+
+-- name: DeleteUserMFARecoveryCodes :exec
+DELETE FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = $1;
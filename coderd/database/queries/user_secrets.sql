@@ -0,0 +1,63 @@
+-- name: GetUserSecretsByUserID :many
+SELECT
+	*
+FROM
+	user_secrets
+WHERE
+	user_id = $1
+ORDER BY
+	name ASC;
+
+-- name: GetUserSecretByUserIDAndName :one
+SELECT
+	*
+FROM
+	user_secrets
+WHERE
+	user_id = $1 AND name = $2;
+
+-- name: GetUserSecretByID :one
+SELECT
+	*
+FROM
+	user_secrets
+WHERE
+	id = $1;
+
+-- name: InsertUserSecret :one
+INSERT INTO
+	user_secrets (
+		id,
+		user_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		file_path,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateUserSecret :one
+UPDATE
+	user_secrets
+SET
+	description = @description,
+	value = @value,
+	value_key_id = @value_key_id,
+	env_name = @env_name,
+	file_path = @file_path,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteUserSecret :exec
+DELETE FROM
+	user_secrets
+WHERE
+	id = $1;
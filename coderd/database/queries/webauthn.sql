@@ -0,0 +1,85 @@
+-- name: GetWebAuthnCredentialsByUserID :many
+SELECT
+	*
+FROM
+	webauthn_credentials
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC;
+
+-- name: GetWebAuthnCredentialByID :one
+SELECT
+	*
+FROM
+	webauthn_credentials
+WHERE
+	id = $1;
+
+-- name: GetWebAuthnCredentialByCredentialID :one
+SELECT
+	*
+FROM
+	webauthn_credentials
+WHERE
+	credential_id = $1;
+
+-- name: InsertWebAuthnCredential :one
+INSERT INTO
+	webauthn_credentials (
+		id,
+		user_id,
+		name,
+		credential_id,
+		public_key,
+		attestation_type,
+		transports,
+		aaguid,
+		sign_count,
+		backup_eligible,
+		backup_state,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: UpdateWebAuthnCredentialUsage :exec
+UPDATE
+	webauthn_credentials
+SET
+	sign_count = @sign_count,
+	backup_state = @backup_state,
+	last_used_at = @last_used_at
+WHERE
+	id = @id;
+
+-- name: DeleteWebAuthnCredential :exec
+DELETE FROM
+	webauthn_credentials
+WHERE
+	id = $1;
+
+-- name: InsertWebAuthnChallenge :exec
+INSERT INTO
+	webauthn_challenges (
+		challenge,
+		session,
+		expires_at
+	)
+VALUES
+	($1, $2, $3);
+
+-- name: DeleteWebAuthnChallenge :one
+-- Consumes a challenge, so every ceremony can only be completed once.
+DELETE FROM
+	webauthn_challenges
+WHERE
+	challenge = $1
+RETURNING *;
+
+-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM
+	webauthn_challenges
+WHERE
+	expires_at < @before_time;
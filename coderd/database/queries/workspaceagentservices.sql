@@ -0,0 +1,37 @@
+-- name: InsertWorkspaceAgentServices :many
+INSERT INTO
+	workspace_agent_services (workspace_agent_id, created_at, updated_at, id, log_source_id, log_path, name, display_name, icon, command, restart_policy, restart_delay_seconds, readiness_url, readiness_interval_seconds, readiness_threshold, health)
+SELECT
+	@workspace_agent_id :: uuid AS workspace_agent_id,
+	@created_at :: timestamptz AS created_at,
+	@created_at :: timestamptz AS updated_at,
+	unnest(@id :: uuid [ ]) AS id,
+	unnest(@log_source_id :: uuid [ ]) AS log_source_id,
+	unnest(@log_path :: text [ ]) AS log_path,
+	unnest(@name :: text [ ]) AS name,
+	unnest(@display_name :: text [ ]) AS display_name,
+	unnest(@icon :: text [ ]) AS icon,
+	unnest(@command :: text [ ]) AS command,
+	unnest(@restart_policy :: workspace_agent_service_restart_policy [ ]) AS restart_policy,
+	unnest(@restart_delay_seconds :: integer [ ]) AS restart_delay_seconds,
+	unnest(@readiness_url :: text [ ]) AS readiness_url,
+	unnest(@readiness_interval_seconds :: integer [ ]) AS readiness_interval_seconds,
+	unnest(@readiness_threshold :: integer [ ]) AS readiness_threshold,
+	unnest(@health :: workspace_app_health [ ]) AS health
+RETURNING workspace_agent_services.*;
+
+-- name: GetWorkspaceAgentServicesByAgentIDs :many
+SELECT * FROM workspace_agent_services WHERE workspace_agent_id = ANY(@ids :: uuid [ ]) ORDER BY name ASC;
+
+-- name: UpdateWorkspaceAgentServiceStatusByID :exec
+UPDATE
+	workspace_agent_services
+SET
+	status = @status,
+	health = @health,
+	restarts = @restarts,
+	exit_code = @exit_code,
+	updated_at = @updated_at
+WHERE
+	id = @id
+	AND workspace_agent_id = @workspace_agent_id;
@@ -0,0 +1,96 @@
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceSessionRecordings :many
+SELECT
+	sqlc.embed(workspace_session_recordings),
+	workspaces.name AS workspace_name,
+	users.username AS owner_username
+FROM
+	workspace_session_recordings
+INNER JOIN
+	workspaces ON workspace_session_recordings.workspace_id = workspaces.id
+INNER JOIN
+	users ON workspace_session_recordings.owner_id = users.id
+WHERE
+	true
+	AND CASE
+		WHEN @workspace_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.workspace_id = @workspace_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @owner_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.owner_id = @owner_id
+		ELSE true
+	END
+ORDER BY
+	workspace_session_recordings.started_at DESC, workspace_session_recordings.id DESC
+OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		organization_id,
+		workspace_id,
+		owner_id,
+		agent_id,
+		type,
+		width,
+		height,
+		started_at,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: UpdateWorkspaceSessionRecordingByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	size = size + @added_size :: bigint,
+	ended_at = COALESCE(sqlc.narg('ended_at'), ended_at)
+WHERE
+	id = @id;
+
+-- name: InsertWorkspaceSessionRecordingChunk :exec
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		sequence,
+		data,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4);
+
+-- GetWorkspaceSessionRecordingChunks returns the chunks of a recording after
+-- the given sequence number, so that large recordings can be streamed in
+-- batches.
+-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = @recording_id
+	AND sequence > @after_sequence :: int
+ORDER BY
+	sequence ASC
+LIMIT
+	@limit_opt :: int;
+
+-- name: DeleteOldWorkspaceSessionRecordings :exec
+DELETE FROM
+	workspace_session_recordings
+WHERE
+	started_at < @before_time :: timestamptz;
@@ -26,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
+	"github.com/coder/coder/v2/tailnet"
 )
 
 const (
@@ -40,19 +41,24 @@ type ReportOptions struct {
 	Dismissed bool
 
 	DERPMap *tailcfg.DERPMap
+	// DERPQUICPorts are the QUIC ports of the nodes in DERPMap. Nodes with a
+	// port are tested over QUIC, falling back to TCP.
+	DERPQUICPorts tailnet.DERPQUICPorts
 }
 
 type Report healthsdk.DERPHealthReport
 
 type RegionReport struct {
 	healthsdk.DERPRegionReport
-	mu sync.Mutex
+	mu        sync.Mutex
+	quicPorts tailnet.DERPQUICPorts
 }
 
 type NodeReport struct {
 	healthsdk.DERPNodeReport
 	mu            sync.Mutex
 	clientCounter int
+	quicPort      int
 }
 
 func (r *Report) Run(ctx context.Context, opts *ReportOptions) {
@@ -74,6 +80,7 @@ func (r *Report) Run(ctx context.Context, opts *ReportOptions) {
 				DERPRegionReport: healthsdk.DERPRegionReport{
 					Region: region,
 				},
+				quicPorts: opts.DERPQUICPorts,
 			}
 		)
 		go func() {
@@ -159,6 +166,7 @@ func (r *RegionReport) Run(ctx context.Context) {
 					Healthy: true,
 					Node:    node,
 				},
+				quicPort: r.quicPorts[node.Name],
 			}
 		)
 
@@ -475,6 +483,20 @@ func (r *NodeReport) derpClient(ctx context.Context, derpURL *url.URL) (*derphtt
 		r.writeClientErr(id, err)
 		return nil, id, err
 	}
+	if r.quicPort != 0 {
+		client.SetRegionDialer(func(ctx context.Context, _ *tailcfg.DERPRegion) net.Conn {
+			conn, err := tailnet.DialDERPQUIC(ctx, r.Node, r.quicPort)
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if err != nil {
+				// Returning nil falls back to TCP.
+				r.ClientLogs[id] = append(r.ClientLogs[id], fmt.Sprintf("DERP over QUIC to port %d failed, falling back to TCP: %v", r.quicPort, err))
+				return nil
+			}
+			r.UsesQUIC = true
+			return conn
+		})
+	}
 
 	go func() {
 		<-ctx.Done()
@@ -23,6 +23,7 @@ func StripCoderCookies(header string) string {
 		if name == codersdk.SessionTokenCookie ||
 			name == codersdk.OAuth2StateCookie ||
 			name == codersdk.OAuth2RedirectCookie ||
+			name == codersdk.SAMLRequestCookie ||
 			name == codersdk.PathAppSessionTokenCookie ||
 			name == codersdk.SubdomainAppSessionTokenCookie ||
 			name == codersdk.SignedAppTokenCookie {
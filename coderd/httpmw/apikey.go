@@ -24,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -110,6 +111,12 @@ type ExtractAPIKeyConfig struct {
 	// This is originally implemented to send entitlement warning headers after
 	// a user is authenticated to prevent additional CLI invocations.
 	PostAuthAdditionalHeadersFunc func(a rbac.Subject, header http.Header)
+
+	// MFAEnrollmentRequired is called for sessions created by a password
+	// login. If it returns true, the request is rejected until the user
+	// enrolls a second factor. Routes used to enroll must use a config
+	// without it.
+	MFAEnrollmentRequired func(ctx context.Context, userID uuid.UUID) (bool, error)
 }
 
 // ExtractAPIKeyMW calls ExtractAPIKey with the given config on each request,
@@ -409,7 +416,14 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 	// If the key is valid, we also fetch the user roles and status.
 	// The roles are used for RBAC authorize checks, and the status
 	// is to block 'suspended' users from accessing the platform.
-	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, rbac.ScopeName(key.Scope))
+	scope, err := APIKeyRBACScope(*key)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error building the API key scope. %s", err.Error()),
+		})
+	}
+	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, scope)
 	if err != nil {
 		return write(http.StatusUnauthorized, codersdk.Response{
 			Message: internalErrorMessage,
@@ -439,6 +453,22 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	if cfg.MFAEnrollmentRequired != nil && key.LoginType == database.LoginTypePassword {
+		required, err := cfg.MFAEnrollmentRequired(ctx, key.UserID)
+		if err != nil {
+			return write(http.StatusInternalServerError, codersdk.Response{
+				Message: internalErrorMessage,
+				Detail:  fmt.Sprintf("Internal error checking second factor enrollment. %s", err.Error()),
+			})
+		}
+		if required {
+			return write(http.StatusForbidden, codersdk.Response{
+				Message: "Multi-factor authentication is required. Enroll a second factor before continuing.",
+				Detail:  "Run `coder login` to enroll an authenticator app.",
+			})
+		}
+	}
+
 	if cfg.PostAuthAdditionalHeadersFunc != nil {
 		cfg.PostAuthAdditionalHeadersFunc(actor, rw.Header())
 	}
@@ -446,6 +476,45 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 	return key, &actor, true
 }
 
+// APIKeyRBACScope returns the rbac scope of an API key. Keys with the custom
+// scope are built from their permissions and allow list. An allowed
+// organization limits the permissions to that organization, and an allowed
+// template also allows the workspaces created from it.
+func APIKeyRBACScope(key database.APIKey) (rbac.ExpandableScope, error) {
+	if key.Scope != database.APIKeyScopeCustom {
+		return rbac.ScopeName(key.Scope), nil
+	}
+
+	params := rbac.CustomScopeParams{
+		Permissions: map[string][]policy.Action{},
+	}
+	for _, perm := range key.ScopePermissions {
+		resource, action, ok := strings.Cut(perm, ":")
+		if !ok {
+			return nil, xerrors.Errorf("invalid scope permission %q", perm)
+		}
+		params.Permissions[resource] = append(params.Permissions[resource], policy.Action(action))
+	}
+
+	for _, elem := range key.ScopeAllowList {
+		resource, rawID, ok := strings.Cut(elem, ":")
+		if !ok {
+			return nil, xerrors.Errorf("invalid scope allow list element %q", elem)
+		}
+		params.AllowList = append(params.AllowList, elem)
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			// Wildcards allow every resource of the type.
+			continue
+		}
+		if resource == rbac.ResourceOrganization.Type {
+			params.OrganizationIDs = append(params.OrganizationIDs, id)
+		}
+	}
+
+	return rbac.CustomScope(params), nil
+}
+
 // UserRBACSubject fetches a user's rbac.Subject from the database. It pulls all roles from both
 // site and organization scopes. It also pulls the groups, and the user's status.
 func UserRBACSubject(ctx context.Context, db database.Store, userID uuid.UUID, scope rbac.ExpandableScope) (rbac.Subject, database.UserStatus, error) {
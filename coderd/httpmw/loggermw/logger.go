@@ -134,10 +134,12 @@ var actorLogOrder = []rbac.SubjectType{
 	rbac.SubjectTypeCryptoKeyRotator,
 	rbac.SubjectTypeJobReaper,
 	rbac.SubjectTypeNotifier,
+	rbac.SubjectTypeOAuth2ClientRegistration,
 	rbac.SubjectTypePrebuildsOrchestrator,
 	rbac.SubjectTypeSubAgentAPI,
 	rbac.SubjectTypeProvisionerd,
 	rbac.SubjectTypeResourceMonitor,
+	rbac.SubjectTypeSCIMProvisioner,
 	rbac.SubjectTypeSystemReadProvisionerDaemons,
 	rbac.SubjectTypeSystemRestricted,
 }
@@ -232,6 +232,10 @@ func ExtractOAuth2ProviderApp(db database.Store) func(http.Handler) http.Handler
 						paramAppID = r.Form.Get("client_id")
 					}
 				}
+				if paramAppID == "" {
+					// Clients may also authenticate with HTTP basic auth.
+					paramAppID, _, _ = r.BasicAuth()
+				}
 				if paramAppID == "" {
 					httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 						Message: "Missing OAuth2 client ID.",
@@ -18,25 +18,39 @@ import (
 )
 
 type authorizeParams struct {
-	clientID     string
-	redirectURL  *url.URL
-	responseType codersdk.OAuth2ProviderResponseType
-	scope        []string
-	state        string
+	clientID            string
+	redirectURL         *url.URL
+	responseType        codersdk.OAuth2ProviderResponseType
+	scope               string
+	state               string
+	codeChallenge       string
+	codeChallengeMethod codersdk.OAuth2ProviderCodeChallengeMethod
 }
 
-func extractAuthorizeParams(r *http.Request, callbackURL *url.URL) (authorizeParams, []codersdk.ValidationError, error) {
+func extractAuthorizeParams(r *http.Request, app database.OAuth2ProviderApp, callbackURL *url.URL) (authorizeParams, []codersdk.ValidationError, error) {
 	p := httpapi.NewQueryParamParser()
 	vals := r.URL.Query()
 
 	p.RequiredNotEmpty("state", "response_type", "client_id")
+	// Public clients cannot authenticate with a secret during the exchange, so
+	// PKCE is the only thing binding the code to the client that requested it.
+	if app.ClientType == database.OAuth2ProviderAppClientTypePublic {
+		p.RequiredNotEmpty("code_challenge")
+	}
+	if vals.Get("code_challenge") != "" {
+		// The spec defaults to "plain" when the method is omitted, which we do not
+		// support, so require it to be explicit.
+		p.RequiredNotEmpty("code_challenge_method")
+	}
 
 	params := authorizeParams{
-		clientID:     p.String(vals, "", "client_id"),
-		redirectURL:  p.RedirectURL(vals, callbackURL, "redirect_uri"),
-		responseType: httpapi.ParseCustom(p, vals, "", "response_type", httpapi.ParseEnum[codersdk.OAuth2ProviderResponseType]),
-		scope:        p.Strings(vals, []string{}, "scope"),
-		state:        p.String(vals, "", "state"),
+		clientID:            p.String(vals, "", "client_id"),
+		redirectURL:         p.RedirectURL(vals, callbackURL, "redirect_uri"),
+		responseType:        httpapi.ParseCustom(p, vals, "", "response_type", httpapi.ParseEnum[codersdk.OAuth2ProviderResponseType]),
+		scope:               p.String(vals, "", "scope"),
+		state:               p.String(vals, "", "state"),
+		codeChallenge:       p.String(vals, "", "code_challenge"),
+		codeChallengeMethod: httpapi.ParseCustom(p, vals, "", "code_challenge_method", httpapi.ParseEnum[codersdk.OAuth2ProviderCodeChallengeMethod]),
 	}
 
 	// We add "redirected" when coming from the authorize page.
@@ -68,7 +82,7 @@ func Authorize(db database.Store, accessURL *url.URL) http.HandlerFunc {
 			return
 		}
 
-		params, validationErrs, err := extractAuthorizeParams(r, callbackURL)
+		params, validationErrs, err := extractAuthorizeParams(r, app, callbackURL)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message:     "Invalid query params.",
@@ -78,7 +92,16 @@ func Authorize(db database.Store, accessURL *url.URL) http.HandlerFunc {
 			return
 		}
 
-		// TODO: Ignoring scope for now, but should look into implementing.
+		// The scope is applied to the token once the code is exchanged.
+		var scopeErr scopeError
+		if _, err := parseScope(params.scope); errors.As(err, &scopeErr) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.OAuth2Error{
+				Error:            "invalid_scope",
+				ErrorDescription: scopeErr.description,
+			})
+			return
+		}
+
 		code, err := GenerateSecret()
 		if err != nil {
 			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
@@ -107,11 +130,14 @@ func Authorize(db database.Store, accessURL *url.URL) http.HandlerFunc {
 				// is received.  If the application does wait before exchanging the
 				// token (for example suppose they ask the user to confirm and the user
 				// has left) then they can just retry immediately and get a new code.
-				ExpiresAt:    dbtime.Now().Add(time.Duration(10) * time.Minute),
-				SecretPrefix: []byte(code.Prefix),
-				HashedSecret: []byte(code.Hashed),
-				AppID:        app.ID,
-				UserID:       apiKey.UserID,
+				ExpiresAt:           dbtime.Now().Add(time.Duration(10) * time.Minute),
+				SecretPrefix:        []byte(code.Prefix),
+				HashedSecret:        []byte(code.Hashed),
+				AppID:               app.ID,
+				UserID:              apiKey.UserID,
+				CodeChallenge:       params.codeChallenge,
+				CodeChallengeMethod: string(params.codeChallengeMethod),
+				Scope:               params.scope,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert oauth2 authorization code: %w", err)
@@ -0,0 +1,384 @@
+package identityprovider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// deviceCodeLifetime is how long the user has to enter the code.
+	deviceCodeLifetime = 15 * time.Minute
+	// devicePollInterval is the minimum time clients must wait between polls.
+	devicePollInterval = 5 * time.Second
+	// userCodeCharset avoids vowels so codes cannot spell words, and avoids
+	// characters that are easily confused, as suggested by RFC 8628 section 6.1.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+	// deviceVerifyPath is where the user enters the user code.
+	deviceVerifyPath = "/oauth2/device/verify"
+)
+
+// devicePollError is returned while a device polls the token endpoint. The
+// codes are defined in RFC 8628 section 3.5 and tell the client whether to
+// keep polling.
+type devicePollError struct {
+	code        string
+	description string
+}
+
+func (e devicePollError) Error() string {
+	return e.description
+}
+
+var (
+	errAuthorizationPending = devicePollError{code: "authorization_pending", description: "The user has not yet approved the request."}
+	errSlowDown             = devicePollError{code: "slow_down", description: "The client is polling too quickly."}
+	errAccessDenied         = devicePollError{code: "access_denied", description: "The user denied the request."}
+	errExpiredToken         = devicePollError{code: "expired_token", description: "The device code has expired."}
+)
+
+func generateUserCode() (string, error) {
+	return cryptorand.StringCharset(userCodeCharset, userCodeLength)
+}
+
+// normalizeUserCode strips the formatting users may have typed along with the
+// code, so "bcdf-ghjk" matches the stored "BCDFGHJK".
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+// formatUserCode splits the code in half to make it easier to read and type.
+func formatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// DeviceAuthorization issues a device code and user code as described in RFC
+// 8628 section 3.2. The device shows the user code to the user, who enters it
+// on the verification page from another device, while the device polls the
+// token endpoint with the device code.
+func DeviceAuthorization(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		err := r.ParseForm()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to parse form.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		vals := r.Form
+		clientAuthFromHeader(r, vals)
+
+		p := httpapi.NewQueryParamParser()
+		p.RequiredNotEmpty("client_id")
+		if app.ClientType != database.OAuth2ProviderAppClientTypePublic {
+			p.RequiredNotEmpty("client_secret")
+		}
+		_ = p.String(vals, "", "client_id")
+		clientSecret := p.String(vals, "", "client_secret")
+		scope := p.String(vals, "", "scope")
+		p.ErrorExcessParams(vals)
+		if len(p.Errors) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid query params.",
+				Validations: p.Errors,
+			})
+			return
+		}
+
+		// The scope is applied to the token once the device code is exchanged.
+		var scopeErr scopeError
+		if _, err := parseScope(scope); errors.As(err, &scopeErr) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.OAuth2Error{
+				Error:            "invalid_scope",
+				ErrorDescription: scopeErr.description,
+			})
+			return
+		}
+
+		_, err = authenticateClient(ctx, db, app, clientSecret)
+		if errors.Is(err, errBadSecret) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+
+		deviceCode, err := GenerateSecret()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate OAuth2 device code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		// User codes are short, so retry a few times in the unlikely event that
+		// the code is already in use.
+		var dbCode database.OAuth2ProviderAppDeviceCode
+		for attempt := 0; attempt < 3; attempt++ {
+			var userCode string
+			userCode, err = generateUserCode()
+			if err != nil {
+				break
+			}
+			//nolint:gocritic // There is no user yet so we must use the system.
+			dbCode, err = db.InsertOAuth2ProviderAppDeviceCode(dbauthz.AsSystemRestricted(ctx), database.InsertOAuth2ProviderAppDeviceCodeParams{
+				ID:           uuid.New(),
+				CreatedAt:    dbtime.Now(),
+				ExpiresAt:    dbtime.Now().Add(deviceCodeLifetime),
+				SecretPrefix: []byte(deviceCode.Prefix),
+				HashedSecret: []byte(deviceCode.Hashed),
+				UserCode:     userCode,
+				AppID:        app.ID,
+				Scope:        scope,
+			})
+			if !database.IsUniqueViolation(err) {
+				break
+			}
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate OAuth2 device code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		verifyURL := accessURL.ResolveReference(&url.URL{Path: deviceVerifyPath})
+		completeURL := *verifyURL
+		completeURL.RawQuery = url.Values{"user_code": {formatUserCode(dbCode.UserCode)}}.Encode()
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode.Formatted,
+			UserCode:                formatUserCode(dbCode.UserCode),
+			VerificationURI:         verifyURL.String(),
+			VerificationURIComplete: completeURL.String(),
+			ExpiresIn:               int64(deviceCodeLifetime.Seconds()),
+			Interval:                int64(devicePollInterval.Seconds()),
+		})
+	}
+}
+
+func deviceCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	secretID, err := authenticateClient(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	code, err := parseSecret(params.deviceCode)
+	if err != nil {
+		return oauth2.Token{}, errBadCode
+	}
+	//nolint:gocritic // There is no user yet so we must use the system.
+	dbCode, err := db.GetOAuth2ProviderAppDeviceCodeByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(code.prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return oauth2.Token{}, errBadCode
+	}
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	if dbCode.AppID != app.ID {
+		return oauth2.Token{}, errBadCode
+	}
+	equal, err := userpassword.Compare(string(dbCode.HashedSecret), code.secret)
+	if err != nil {
+		return oauth2.Token{}, xerrors.Errorf("unable to compare code: %w", err)
+	}
+	if !equal {
+		return oauth2.Token{}, errBadCode
+	}
+
+	now := dbtime.Now()
+	if dbCode.ExpiresAt.Before(now) {
+		return oauth2.Token{}, errExpiredToken
+	}
+
+	//nolint:gocritic // There is no user yet so we must use the system.
+	err = db.UpdateOAuth2ProviderAppDeviceCodePolledAtByID(dbauthz.AsSystemRestricted(ctx), database.UpdateOAuth2ProviderAppDeviceCodePolledAtByIDParams{
+		ID:           dbCode.ID,
+		LastPolledAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return oauth2.Token{}, xerrors.Errorf("update device code polled at: %w", err)
+	}
+	if dbCode.LastPolledAt.Valid && now.Sub(dbCode.LastPolledAt.Time) < devicePollInterval {
+		return oauth2.Token{}, errSlowDown
+	}
+
+	switch dbCode.Status {
+	case database.OAuth2ProviderAppDeviceCodeStatusPending:
+		return oauth2.Token{}, errAuthorizationPending
+	case database.OAuth2ProviderAppDeviceCodeStatusDenied:
+		//nolint:gocritic // There is no user yet so we must use the system.
+		err = db.DeleteOAuth2ProviderAppDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return oauth2.Token{}, xerrors.Errorf("delete oauth2 app device code: %w", err)
+		}
+		return oauth2.Token{}, errAccessDenied
+	case database.OAuth2ProviderAppDeviceCodeStatusApproved:
+	default:
+		return oauth2.Token{}, xerrors.Errorf("unknown device code status %q", dbCode.Status)
+	}
+	if !dbCode.UserID.Valid {
+		return oauth2.Token{}, xerrors.New("approved device code has no user")
+	}
+
+	granted, err := parseScope(dbCode.Scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	scope, err := narrowScope(granted, params.scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	return issueToken(ctx, db, app, lifetimes, dbCode.UserID.UUID, secretID, scope, true, func(ctx context.Context, tx database.Store) error {
+		//nolint:gocritic // Device codes can only be deleted by the system.
+		err := tx.DeleteOAuth2ProviderAppDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+		if err != nil {
+			return xerrors.Errorf("delete oauth2 app device code: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeviceVerify lets the user enter a user code and then approve or deny the
+// device that requested it. Like Authorize, it relies on the referer header to
+// detect that the user clicked a button on the page it rendered.
+func DeviceVerify(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		apiKey := httpmw.APIKey(r)
+		ua := httpmw.UserAuthorization(r)
+
+		rawUserCode := r.URL.Query().Get("user_code")
+		if rawUserCode == "" {
+			site.RenderOAuthDevicePage(rw, r, site.RenderOAuthDeviceData{})
+			return
+		}
+
+		//nolint:gocritic // The code has no owner until the user approves it.
+		dbCode, err := db.GetOAuth2ProviderAppDeviceCodeByUserCode(dbauthz.AsSystemRestricted(ctx), normalizeUserCode(rawUserCode))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusInternalServerError,
+				Title:        "Internal Server Error",
+				Description:  err.Error(),
+				DashboardURL: accessURL.String(),
+			})
+			return
+		}
+		if err != nil || dbCode.ExpiresAt.Before(dbtime.Now()) || dbCode.Status != database.OAuth2ProviderAppDeviceCodeStatusPending {
+			site.RenderOAuthDevicePage(rw, r, site.RenderOAuthDeviceData{
+				UserCode: rawUserCode,
+				Error:    "The code is invalid or has expired.",
+			})
+			return
+		}
+
+		//nolint:gocritic // Users cannot read apps so we must use the system.
+		app, err := db.GetOAuth2ProviderAppByID(dbauthz.AsSystemRestricted(ctx), dbCode.AppID)
+		if err != nil {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusInternalServerError,
+				Title:        "Internal Server Error",
+				Description:  err.Error(),
+				DashboardURL: accessURL.String(),
+			})
+			return
+		}
+
+		action := r.URL.Query().Get("action")
+		if action != "" && cameFromPage(r, accessURL, deviceVerifyPath) {
+			status := database.OAuth2ProviderAppDeviceCodeStatusDenied
+			title := "Request denied"
+			description := app.Name + " will not be able to access your account."
+			if action == "allow" {
+				status = database.OAuth2ProviderAppDeviceCodeStatusApproved
+				title = "Device authorized"
+				description = "Return to your device to continue. You can close this window."
+			}
+			_, err = db.UpdateOAuth2ProviderAppDeviceCodeStatusByID(ctx, database.UpdateOAuth2ProviderAppDeviceCodeStatusByIDParams{
+				ID:     dbCode.ID,
+				Status: status,
+				UserID: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				// Someone else approved or denied the code in the meantime.
+				site.RenderOAuthDevicePage(rw, r, site.RenderOAuthDeviceData{
+					UserCode: rawUserCode,
+					Error:    "The code is invalid or has expired.",
+				})
+				return
+			}
+			if err != nil {
+				site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+					Status:       http.StatusInternalServerError,
+					Title:        "Internal Server Error",
+					Description:  err.Error(),
+					DashboardURL: accessURL.String(),
+				})
+				return
+			}
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusOK,
+				HideStatus:   true,
+				Title:        title,
+				Description:  description,
+				DashboardURL: accessURL.String(),
+			})
+			return
+		}
+
+		actionURL := func(action string) string {
+			u := accessURL.ResolveReference(&url.URL{Path: deviceVerifyPath})
+			u.RawQuery = url.Values{
+				"user_code": {formatUserCode(dbCode.UserCode)},
+				"action":    {action},
+			}.Encode()
+			return u.String()
+		}
+		site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+			AppIcon:     app.Icon,
+			AppName:     app.Name,
+			CancelURI:   actionURL("deny"),
+			RedirectURI: actionURL("allow"),
+			Username:    ua.FriendlyName,
+		})
+	}
+}
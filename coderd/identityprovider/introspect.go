@@ -0,0 +1,186 @@
+package identityprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Introspect lets an app check whether a token it was issued is still active
+// as described in RFC 7662. Tokens issued to other apps are reported as
+// inactive so apps cannot learn anything about them.
+func Introspect(db database.Store) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		err := r.ParseForm()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to parse form.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		vals := r.Form
+		clientAuthFromHeader(r, vals)
+
+		p := httpapi.NewQueryParamParser()
+		p.RequiredNotEmpty("token", "client_id", "client_secret")
+		token := p.String(vals, "", "token")
+		// The hint is optional and we can tell the token types apart anyway.
+		_ = p.String(vals, "", "token_type_hint")
+		_ = p.String(vals, "", "client_id")
+		clientSecret := p.String(vals, "", "client_secret")
+		p.ErrorExcessParams(vals)
+		if len(p.Errors) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid query params.",
+				Validations: p.Errors,
+			})
+			return
+		}
+
+		// Public clients cannot authenticate, so they cannot be trusted with
+		// information about tokens.
+		if app.ClientType == database.OAuth2ProviderAppClientTypePublic {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: errUnauthorizedClient.Error(),
+			})
+			return
+		}
+		_, err = validateClientSecret(ctx, db, app, clientSecret)
+		if errors.Is(err, errBadSecret) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+
+		//nolint:gocritic // The app is not a user, so we must use the system.
+		introspection, err := introspectToken(dbauthz.AsSystemRestricted(ctx), db, app, token)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, introspection)
+	}
+}
+
+func introspectToken(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, token string) (codersdk.OAuth2TokenIntrospection, error) {
+	inactive := codersdk.OAuth2TokenIntrospection{Active: false}
+
+	var (
+		dbToken   database.OAuth2ProviderAppToken
+		tokenType string
+		err       error
+	)
+	// Refresh tokens use the same format as our other secrets, whereas access
+	// tokens are regular API keys.
+	if refresh, parseErr := parseSecret(token); parseErr == nil {
+		dbToken, err = db.GetOAuth2ProviderAppTokenByPrefix(ctx, []byte(refresh.prefix))
+		if errors.Is(err, sql.ErrNoRows) {
+			return inactive, nil
+		}
+		if err != nil {
+			return inactive, xerrors.Errorf("get oauth2 app token: %w", err)
+		}
+		equal, err := userpassword.Compare(string(dbToken.RefreshHash), refresh.secret)
+		if err != nil {
+			return inactive, xerrors.Errorf("unable to compare token: %w", err)
+		}
+		if !equal {
+			return inactive, nil
+		}
+		tokenType = "refresh_token"
+	} else {
+		keyID, keySecret, err := httpmw.SplitAPIToken(token)
+		if err != nil {
+			return inactive, nil
+		}
+		key, err := db.GetAPIKeyByID(ctx, keyID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return inactive, nil
+		}
+		if err != nil {
+			return inactive, xerrors.Errorf("get api key: %w", err)
+		}
+		hashedSecret := sha256.Sum256([]byte(keySecret))
+		if subtle.ConstantTimeCompare(key.HashedSecret, hashedSecret[:]) != 1 {
+			return inactive, nil
+		}
+		dbToken, err = db.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, key.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return inactive, nil
+		}
+		if err != nil {
+			return inactive, xerrors.Errorf("get oauth2 app token: %w", err)
+		}
+		tokenType = "Bearer"
+	}
+
+	if dbToken.AppID != app.ID || dbToken.ExpiresAt.Before(dbtime.Now()) {
+		return inactive, nil
+	}
+
+	key, err := db.GetAPIKeyByID(ctx, dbToken.APIKeyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return inactive, nil
+	}
+	if err != nil {
+		return inactive, xerrors.Errorf("get api key: %w", err)
+	}
+	// Refresh tokens outlive their access tokens, which is what allows the
+	// refresh in the first place.
+	expiresAt := dbToken.ExpiresAt
+	if tokenType == "Bearer" {
+		if key.ExpiresAt.Before(dbtime.Now()) {
+			return inactive, nil
+		}
+		expiresAt = key.ExpiresAt
+	}
+
+	user, err := db.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return inactive, xerrors.Errorf("get user: %w", err)
+	}
+	if user.Deleted || user.Status == database.UserStatusSuspended {
+		return inactive, nil
+	}
+
+	// Custom scopes are reported in the same format they are requested in.
+	scope := string(key.Scope)
+	if key.Scope == database.APIKeyScopeCustom {
+		scope = strings.Join(key.ScopePermissions, " ")
+	}
+
+	return codersdk.OAuth2TokenIntrospection{
+		Active:    true,
+		Scope:     scope,
+		ClientID:  app.ID.String(),
+		Username:  user.Username,
+		TokenType: tokenType,
+		Exp:       expiresAt.Unix(),
+		Iat:       dbToken.CreatedAt.Unix(),
+		Sub:       user.ID.String(),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package identityprovider
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Metadata describes the provider as defined in RFC 8414 so clients can
+// discover the endpoints instead of hardcoding them.
+func Metadata(accessURL *url.URL, registration bool) http.HandlerFunc {
+	endpoint := func(path string) string {
+		return accessURL.ResolveReference(&url.URL{Path: path}).String()
+	}
+	metadata := codersdk.OAuth2AuthorizationServerMetadata{
+		// The issuer must match the URL the metadata was fetched from, minus
+		// the well-known suffix.
+		Issuer:                      accessURL.String(),
+		AuthorizationEndpoint:       endpoint("/oauth2/authorize"),
+		TokenEndpoint:               endpoint("/oauth2/tokens"),
+		DeviceAuthorizationEndpoint: endpoint("/oauth2/device"),
+		IntrospectionEndpoint:       endpoint("/oauth2/introspect"),
+		ResponseTypesSupported: []codersdk.OAuth2ProviderResponseType{
+			codersdk.OAuth2ProviderResponseTypeCode,
+		},
+		GrantTypesSupported: []codersdk.OAuth2ProviderGrantType{
+			codersdk.OAuth2ProviderGrantTypeAuthorizationCode,
+			codersdk.OAuth2ProviderGrantTypeRefreshToken,
+			codersdk.OAuth2ProviderGrantTypeClientCredentials,
+			codersdk.OAuth2ProviderGrantTypeDeviceCode,
+		},
+		CodeChallengeMethodsSupported: []codersdk.OAuth2ProviderCodeChallengeMethod{
+			codersdk.OAuth2ProviderCodeChallengeMethodS256,
+		},
+		TokenEndpointAuthMethodsSupported: []codersdk.OAuth2TokenEndpointAuthMethod{
+			codersdk.OAuth2TokenEndpointAuthMethodClientSecretBasic,
+			codersdk.OAuth2TokenEndpointAuthMethodClientSecretPost,
+			codersdk.OAuth2TokenEndpointAuthMethodNone,
+		},
+		// Public clients cannot introspect tokens.
+		IntrospectionEndpointAuthMethodsSupported: []codersdk.OAuth2TokenEndpointAuthMethod{
+			codersdk.OAuth2TokenEndpointAuthMethodClientSecretBasic,
+			codersdk.OAuth2TokenEndpointAuthMethodClientSecretPost,
+		},
+	}
+	if registration {
+		metadata.RegistrationEndpoint = endpoint("/oauth2/register")
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, metadata)
+	}
+}
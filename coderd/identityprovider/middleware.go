@@ -10,13 +10,31 @@ import (
 	"github.com/coder/coder/v2/site"
 )
 
+// cameFromPage reports whether the request was sent from the page at path on
+// this deployment, which is how we detect that the user clicked a button on a
+// page we rendered.
+func cameFromPage(r *http.Request, accessURL *url.URL, path string) bool {
+	origin := r.Header.Get(httpmw.OriginHeader)
+	originU, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	refererU, err := url.Parse(r.Referer())
+	if err != nil {
+		return false
+	}
+	return (origin == "" || originU.Hostname() == accessURL.Hostname()) &&
+		refererU.Hostname() == accessURL.Hostname() &&
+		refererU.Path == path
+}
+
 // authorizeMW serves to remove some code from the primary authorize handler.
 // It decides when to show the html allow page, and when to just continue.
 func authorizeMW(accessURL *url.URL) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get(httpmw.OriginHeader)
-			originU, err := url.Parse(origin)
+			_, err := url.Parse(origin)
 			if err != nil {
 				httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
 					Message: "Invalid origin header.",
@@ -26,7 +44,7 @@ func authorizeMW(accessURL *url.URL) func(next http.Handler) http.Handler {
 			}
 
 			referer := r.Referer()
-			refererU, err := url.Parse(referer)
+			_, err = url.Parse(referer)
 			if err != nil {
 				httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
 					Message: "Invalid referer header.",
@@ -42,9 +60,7 @@ func authorizeMW(accessURL *url.URL) func(next http.Handler) http.Handler {
 			// always set by browsers (or other tools like cURL).  If the origin does
 			// exist, we will make sure it matches.  We require `referer` to be set at
 			// a minimum in order to detect whether "allow" has been pressed, however.
-			cameFromSelf := (origin == "" || originU.Hostname() == accessURL.Hostname()) &&
-				refererU.Hostname() == accessURL.Hostname() &&
-				refererU.Path == "/oauth2/authorize"
+			cameFromSelf := cameFromPage(r, accessURL, "/oauth2/authorize")
 
 			// If we were redirected here from this same page it means the user
 			// pressed the allow button so defer to the authorize handler which
@@ -110,7 +126,7 @@ func authorizeMW(accessURL *url.URL) func(next http.Handler) http.Handler {
 			// 2. Since validation will run once the user clicks "allow", it is
 			//    better to validate now to avoid wasting the user's time clicking a
 			//    button that will just error anyway.
-			params, validationErrs, err := extractAuthorizeParams(r, callbackURL)
+			params, validationErrs, err := extractAuthorizeParams(r, app, callbackURL)
 			if err != nil {
 				errStr := make([]string, len(validationErrs))
 				for i, err := range validationErrs {
@@ -0,0 +1,133 @@
+package identityprovider
+
+import (
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// RegistrationError is returned for client metadata that cannot be registered.
+// The codes are defined in RFC 7591 section 3.2.2.
+type RegistrationError struct {
+	Code        string
+	Description string
+}
+
+func (e RegistrationError) Error() string {
+	return e.Description
+}
+
+func invalidClientMetadata(description string) RegistrationError {
+	return RegistrationError{Code: "invalid_client_metadata", Description: description}
+}
+
+func invalidRedirectURI(description string) RegistrationError {
+	return RegistrationError{Code: "invalid_redirect_uri", Description: description}
+}
+
+// ValidateRegistration checks the metadata a client sent to the registration
+// endpoint and fills in the defaults from RFC 7591 section 2. Clients cannot
+// register for the client credentials grant since that requires an
+// administrator to pick a service account.
+func ValidateRegistration(req codersdk.OAuth2ClientRegistrationRequest, allowedRedirectHosts []string) (codersdk.OAuth2ClientRegistrationRequest, error) {
+	// Apps have a single callback URL, which also covers any path below it.
+	if len(req.RedirectURIs) != 1 {
+		return req, invalidRedirectURI("Exactly one redirect URI is required.")
+	}
+	redirectURI, err := url.Parse(req.RedirectURIs[0])
+	if err != nil || !redirectURI.IsAbs() || redirectURI.Host == "" {
+		return req, invalidRedirectURI("The redirect URI must be an absolute URL.")
+	}
+	if redirectURI.Fragment != "" {
+		return req, invalidRedirectURI("The redirect URI must not contain a fragment.")
+	}
+	switch redirectURI.Scheme {
+	case "https":
+	case "http":
+		// Anyone can watch plain HTTP traffic, so only allow it for native apps
+		// listening on the loopback interface as described in RFC 8252.
+		if !isLoopback(redirectURI.Hostname()) {
+			return req, invalidRedirectURI("The redirect URI must use https unless it points to a loopback address.")
+		}
+	default:
+		return req, invalidRedirectURI("The redirect URI must use http or https.")
+	}
+	if !redirectHostAllowed(redirectURI.Hostname(), allowedRedirectHosts) {
+		return req, invalidRedirectURI("The redirect URI host is not allowed by the deployment.")
+	}
+
+	if req.LogoURI != "" {
+		logoURI, err := url.Parse(req.LogoURI)
+		if err != nil || (logoURI.Scheme != "http" && logoURI.Scheme != "https") {
+			return req, invalidClientMetadata("The logo URI must be an http or https URL.")
+		}
+	}
+
+	if len(req.GrantTypes) == 0 {
+		req.GrantTypes = []codersdk.OAuth2ProviderGrantType{codersdk.OAuth2ProviderGrantTypeAuthorizationCode}
+	}
+	for _, grantType := range req.GrantTypes {
+		switch grantType {
+		case codersdk.OAuth2ProviderGrantTypeAuthorizationCode, codersdk.OAuth2ProviderGrantTypeRefreshToken,
+			codersdk.OAuth2ProviderGrantTypeDeviceCode:
+		case codersdk.OAuth2ProviderGrantTypeClientCredentials:
+			return req, invalidClientMetadata("The client_credentials grant requires an administrator to create the client.")
+		default:
+			return req, invalidClientMetadata("Unsupported grant type " + string(grantType) + ".")
+		}
+	}
+
+	if len(req.ResponseTypes) == 0 {
+		req.ResponseTypes = []codersdk.OAuth2ProviderResponseType{codersdk.OAuth2ProviderResponseTypeCode}
+	}
+	for _, responseType := range req.ResponseTypes {
+		if !responseType.Valid() {
+			return req, invalidClientMetadata("Unsupported response type " + string(responseType) + ".")
+		}
+	}
+
+	if req.TokenEndpointAuthMethod == "" {
+		req.TokenEndpointAuthMethod = codersdk.OAuth2TokenEndpointAuthMethodClientSecretBasic
+	}
+	if !req.TokenEndpointAuthMethod.Valid() {
+		return req, invalidClientMetadata("Unsupported token endpoint auth method " + string(req.TokenEndpointAuthMethod) + ".")
+	}
+
+	return req, nil
+}
+
+// RegistrationClientType returns the client type for a registered client.
+// Clients that do not authenticate at the token endpoint are public.
+func RegistrationClientType(req codersdk.OAuth2ClientRegistrationRequest) codersdk.OAuth2ProviderAppClientType {
+	if req.TokenEndpointAuthMethod == codersdk.OAuth2TokenEndpointAuthMethodNone {
+		return codersdk.OAuth2ProviderAppClientTypePublic
+	}
+	return codersdk.OAuth2ProviderAppClientTypeConfidential
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// redirectHostAllowed matches the host against the allowed hosts, where a
+// leading "*." matches any subdomain. An empty list allows any host.
+func redirectHostAllowed(host string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	return slices.ContainsFunc(allowed, func(pattern string) bool {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			return strings.HasPrefix(suffix, ".") && strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+		}
+		return host == pattern
+	})
+}
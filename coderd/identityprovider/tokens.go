@@ -2,11 +2,16 @@ package identityprovider
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/oauth2"
@@ -30,18 +35,117 @@ var (
 	errBadCode = xerrors.New("Invalid code")
 	// errBadToken means the user provided a bad token.
 	errBadToken = xerrors.New("Invalid token")
+	// errBadCodeVerifier means the PKCE code verifier does not match the
+	// challenge sent with the authorization request.
+	errBadCodeVerifier = xerrors.New("Invalid code verifier")
+	// errUnauthorizedClient means the app is not allowed to use the grant type.
+	errUnauthorizedClient = xerrors.New("Client is not authorized to use this grant type")
 )
 
 type tokenParams struct {
 	clientID     string
 	clientSecret string
 	code         string
+	codeVerifier string
+	deviceCode   string
 	grantType    codersdk.OAuth2ProviderGrantType
 	redirectURL  *url.URL
 	refreshToken string
+	scope        string
 }
 
-func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []codersdk.ValidationError, error) {
+// scopeError means the requested scope is unknown or exceeds the scope of the
+// grant. It is reported as invalid_scope as defined in RFC 6749 section 5.2.
+type scopeError struct {
+	description string
+}
+
+func (e scopeError) Error() string {
+	return e.description
+}
+
+// tokenScope is the API key scope a token is issued with.
+type tokenScope struct {
+	scope       database.APIKeyScope
+	permissions []string
+	allowList   []string
+}
+
+// parseScope maps the space-delimited scope parameter onto an API key scope.
+// An empty scope or "all" grants the same access as the user. Otherwise the
+// scope is either "application_connect" or a list of custom scope permissions
+// such as "workspace:read,start template:read".
+func parseScope(raw string) (tokenScope, error) {
+	values := strings.Fields(raw)
+	if len(values) == 0 {
+		return tokenScope{scope: database.APIKeyScopeAll}, nil
+	}
+	for _, scope := range []database.APIKeyScope{database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect} {
+		if !slices.Contains(values, string(scope)) {
+			continue
+		}
+		if len(values) > 1 {
+			return tokenScope{}, scopeError{description: fmt.Sprintf("Scope %q cannot be combined with other scopes.", scope)}
+		}
+		return tokenScope{scope: scope}, nil
+	}
+	perms, err := codersdk.ParseAPIKeyScopePermissions(values)
+	if err != nil {
+		return tokenScope{}, scopeError{description: fmt.Sprintf("Invalid scope: %s.", err.Error())}
+	}
+	return tokenScope{scope: database.APIKeyScopeCustom, permissions: perms}, nil
+}
+
+// narrowScope returns the scope to issue for a grant that was already given a
+// scope, such as an authorization code or a refresh token. Without a requested
+// scope the granted scope is kept, and a requested scope may not grant more than
+// the granted one as required by RFC 6749 section 6.
+func narrowScope(granted tokenScope, raw string) (tokenScope, error) {
+	if strings.TrimSpace(raw) == "" {
+		return granted, nil
+	}
+	requested, err := parseScope(raw)
+	if err != nil {
+		return tokenScope{}, err
+	}
+	exceeds := scopeError{description: "The requested scope exceeds the scope of the grant."}
+	switch granted.scope {
+	case database.APIKeyScopeAll:
+		return requested, nil
+	case database.APIKeyScopeApplicationConnect:
+		if requested.scope != database.APIKeyScopeApplicationConnect {
+			return tokenScope{}, exceeds
+		}
+		return requested, nil
+	default:
+		if requested.scope != database.APIKeyScopeCustom {
+			return tokenScope{}, exceeds
+		}
+		for _, perm := range requested.permissions {
+			if !permissionGranted(granted.permissions, perm) {
+				return tokenScope{}, exceeds
+			}
+		}
+		// The allow list is not part of the scope parameter, so it carries over.
+		requested.allowList = granted.allowList
+		return requested, nil
+	}
+}
+
+// permissionGranted reports whether perm, of the form "<resource>:<action>", is
+// covered by one of the granted permissions, taking wildcards into account.
+func permissionGranted(granted []string, perm string) bool {
+	resource, action, _ := strings.Cut(perm, ":")
+	for _, g := range granted {
+		gResource, gAction, _ := strings.Cut(g, ":")
+		if (gResource == "*" || gResource == resource) && (gAction == "*" || gAction == action) {
+			return true
+		}
+	}
+	return false
+}
+
+func extractTokenParams(r *http.Request, app database.OAuth2ProviderApp, callbackURL *url.URL) (tokenParams, []codersdk.ValidationError, error) {
 	p := httpapi.NewQueryParamParser()
 	err := r.ParseForm()
 	if err != nil {
@@ -49,22 +153,38 @@ func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []c
 	}
 
 	vals := r.Form
+	clientAuthFromHeader(r, vals)
+
+	confidential := app.ClientType != database.OAuth2ProviderAppClientTypePublic
 	p.RequiredNotEmpty("grant_type")
 	grantType := httpapi.ParseCustom(p, vals, "", "grant_type", httpapi.ParseEnum[codersdk.OAuth2ProviderGrantType])
 	switch grantType {
 	case codersdk.OAuth2ProviderGrantTypeRefreshToken:
 		p.RequiredNotEmpty("refresh_token")
 	case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
-		p.RequiredNotEmpty("client_secret", "client_id", "code")
+		p.RequiredNotEmpty("client_id", "code")
+		if confidential {
+			p.RequiredNotEmpty("client_secret")
+		}
+	case codersdk.OAuth2ProviderGrantTypeClientCredentials:
+		p.RequiredNotEmpty("client_secret", "client_id")
+	case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+		p.RequiredNotEmpty("client_id", "device_code")
+		if confidential {
+			p.RequiredNotEmpty("client_secret")
+		}
 	}
 
 	params := tokenParams{
 		clientID:     p.String(vals, "", "client_id"),
 		clientSecret: p.String(vals, "", "client_secret"),
 		code:         p.String(vals, "", "code"),
+		codeVerifier: p.String(vals, "", "code_verifier"),
+		deviceCode:   p.String(vals, "", "device_code"),
 		grantType:    grantType,
 		redirectURL:  p.RedirectURL(vals, callbackURL, "redirect_uri"),
 		refreshToken: p.String(vals, "", "refresh_token"),
+		scope:        p.String(vals, "", "scope"),
 	}
 
 	p.ErrorExcessParams(vals)
@@ -92,7 +212,7 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			return
 		}
 
-		params, validationErrs, err := extractTokenParams(r, callbackURL)
+		params, validationErrs, err := extractTokenParams(r, app, callbackURL)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message:     "Invalid query params.",
@@ -103,13 +223,15 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 		}
 
 		var token oauth2.Token
-		//nolint:gocritic,revive // More cases will be added later.
 		switch params.grantType {
-		// TODO: Client creds, device code.
 		case codersdk.OAuth2ProviderGrantTypeRefreshToken:
 			token, err = refreshTokenGrant(ctx, db, app, lifetimes, params)
 		case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
 			token, err = authorizationCodeGrant(ctx, db, app, lifetimes, params)
+		case codersdk.OAuth2ProviderGrantTypeClientCredentials:
+			token, err = clientCredentialsGrant(ctx, db, app, lifetimes, params)
+		case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+			token, err = deviceCodeGrant(ctx, db, app, lifetimes, params)
 		default:
 			// Grant types are validated by the parser, so getting through here means
 			// the developer added a type but forgot to add a case here.
@@ -120,12 +242,36 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			return
 		}
 
-		if errors.Is(err, errBadCode) || errors.Is(err, errBadSecret) {
+		if errors.Is(err, errBadCode) || errors.Is(err, errBadSecret) || errors.Is(err, errBadCodeVerifier) {
 			httpapi.Write(r.Context(), rw, http.StatusUnauthorized, codersdk.Response{
 				Message: err.Error(),
 			})
 			return
 		}
+		if errors.Is(err, errUnauthorizedClient) {
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+				Message: err.Error(),
+			})
+			return
+		}
+		var scopeErr scopeError
+		if errors.As(err, &scopeErr) {
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.OAuth2Error{
+				Error:            "invalid_scope",
+				ErrorDescription: scopeErr.description,
+			})
+			return
+		}
+		var pollErr devicePollError
+		if errors.As(err, &pollErr) {
+			// These must be in the format from RFC 8628 so clients know whether to
+			// keep polling.
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.OAuth2Error{
+				Error:            pollErr.code,
+				ErrorDescription: pollErr.description,
+			})
+			return
+		}
 		if err != nil {
 			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Failed to exchange token",
@@ -140,26 +286,89 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 	}
 }
 
-func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
-	// Validate the client secret.
-	secret, err := parseSecret(params.clientSecret)
+// clientAuthFromHeader copies client credentials sent with HTTP basic
+// authentication into the form values, since RFC 6749 section 2.3.1 allows
+// clients to authenticate either way.
+func clientAuthFromHeader(r *http.Request, vals url.Values) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		return
+	}
+	if vals.Get("client_id") == "" {
+		vals.Set("client_id", clientID)
+	}
+	if vals.Get("client_secret") == "" {
+		vals.Set("client_secret", clientSecret)
+	}
+}
+
+// validateClientSecret checks that the raw secret belongs to the app and
+// returns it.
+func validateClientSecret(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, rawSecret string) (database.OAuth2ProviderAppSecret, error) {
+	secret, err := parseSecret(rawSecret)
 	if err != nil {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	//nolint:gocritic // Users cannot read secrets so we must use the system.
 	dbSecret, err := db.GetOAuth2ProviderAppSecretByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(secret.prefix))
 	if errors.Is(err, sql.ErrNoRows) {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	if err != nil {
-		return oauth2.Token{}, err
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+	if dbSecret.AppID != app.ID {
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	equal, err := userpassword.Compare(string(dbSecret.HashedSecret), secret.secret)
 	if err != nil {
-		return oauth2.Token{}, xerrors.Errorf("unable to compare secret: %w", err)
+		return database.OAuth2ProviderAppSecret{}, xerrors.Errorf("unable to compare secret: %w", err)
 	}
 	if !equal {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
+	}
+	return dbSecret, nil
+}
+
+// authenticateClient validates the client secret for confidential apps. Public
+// apps have no secret, so the returned ID is null for them.
+func authenticateClient(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, rawSecret string) (uuid.NullUUID, error) {
+	if app.ClientType == database.OAuth2ProviderAppClientTypePublic {
+		return uuid.NullUUID{}, nil
+	}
+	dbSecret, err := validateClientSecret(ctx, db, app, rawSecret)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: dbSecret.ID, Valid: true}, nil
+}
+
+// verifyCodeChallenge checks the PKCE code verifier against the challenge
+// stored with the code as described in RFC 7636 section 4.6.
+func verifyCodeChallenge(dbCode database.OAuth2ProviderAppCode, verifier string) error {
+	if dbCode.CodeChallenge == "" {
+		// A verifier without a challenge means the request was tampered with.
+		if verifier != "" {
+			return errBadCodeVerifier
+		}
+		return nil
+	}
+	if codersdk.OAuth2ProviderCodeChallengeMethod(dbCode.CodeChallengeMethod) != codersdk.OAuth2ProviderCodeChallengeMethodS256 {
+		return errBadCodeVerifier
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(dbCode.CodeChallenge)) != 1 {
+		return errBadCodeVerifier
+	}
+	return nil
+}
+
+func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	// Validate the client secret.
+	secretID, err := authenticateClient(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
 	}
 
 	// Validate the authorization code.
@@ -175,7 +384,10 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	if err != nil {
 		return oauth2.Token{}, err
 	}
-	equal, err = userpassword.Compare(string(dbCode.HashedSecret), code.secret)
+	if dbCode.AppID != app.ID {
+		return oauth2.Token{}, errBadCode
+	}
+	equal, err := userpassword.Compare(string(dbCode.HashedSecret), code.secret)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("unable to compare code: %w", err)
 	}
@@ -188,19 +400,69 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 		return oauth2.Token{}, errBadCode
 	}
 
+	err = verifyCodeChallenge(dbCode, params.codeVerifier)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	granted, err := parseScope(dbCode.Scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	scope, err := narrowScope(granted, params.scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	return issueToken(ctx, db, app, lifetimes, dbCode.UserID, secretID, scope, true, func(ctx context.Context, tx database.Store) error {
+		err := tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
+		if err != nil {
+			return xerrors.Errorf("delete oauth2 app code: %w", err)
+		}
+		return nil
+	})
+}
+
+func clientCredentialsGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	if app.ClientType == database.OAuth2ProviderAppClientTypePublic || !app.ServiceAccountID.Valid {
+		return oauth2.Token{}, errUnauthorizedClient
+	}
+
+	dbSecret, err := validateClientSecret(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	scope, err := parseScope(params.scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	// The client can request a new token with its credentials at any time, so
+	// RFC 6749 section 4.4.3 recommends against handing out a refresh token.
+	return issueToken(ctx, db, app, lifetimes, app.ServiceAccountID.UUID, uuid.NullUUID{UUID: dbSecret.ID, Valid: true}, scope, false, nil)
+}
+
+// issueToken generates an API key for the user with the given scope and stores
+// it along with a refresh token, replacing any previous token the user had for
+// the app. consume runs in the same transaction as the user and is used to
+// invalidate whatever grant was exchanged.
+func issueToken(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, userID uuid.UUID, secretID uuid.NullUUID, scope tokenScope, withRefresh bool, consume func(context.Context, database.Store) error) (oauth2.Token, error) {
 	// Generate a refresh token.
 	refreshToken, err := GenerateSecret()
 	if err != nil {
 		return oauth2.Token{}, err
 	}
 
-	// Generate the API key we will swap for the code.
-	// TODO: We are ignoring scopes for now.
-	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", dbCode.UserID, app.ID)
+	// Generate the API key we will swap for the grant.
+	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", userID, app.ID)
 	key, sessionToken, err := apikey.Generate(apikey.CreateParams{
-		UserID:          dbCode.UserID,
-		LoginType:       database.LoginTypeOAuth2ProviderApp,
-		DefaultLifetime: lifetimes.DefaultDuration.Value(),
+		UserID:           userID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DefaultLifetime:  lifetimes.DefaultDuration.Value(),
+		Scope:            scope.scope,
+		ScopePermissions: scope.permissions,
+		ScopeAllowList:   scope.allowList,
 		// For now, we allow only one token per app and user at a time.
 		TokenName: tokenName,
 	})
@@ -209,22 +471,27 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	}
 
 	// Grab the user roles so we can perform the exchange as the user.
-	actor, _, err := httpmw.UserRBACSubject(ctx, db, dbCode.UserID, rbac.ScopeAll)
+	actor, status, err := httpmw.UserRBACSubject(ctx, db, userID, rbac.ScopeAll)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("fetch user actor: %w", err)
 	}
+	if status == database.UserStatusSuspended {
+		return oauth2.Token{}, errUnauthorizedClient
+	}
 
 	// Do the actual token exchange in the database.
 	err = db.InTx(func(tx database.Store) error {
 		ctx := dbauthz.As(ctx, actor)
-		err = tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
-		if err != nil {
-			return xerrors.Errorf("delete oauth2 app code: %w", err)
+		if consume != nil {
+			err := consume(ctx, tx)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Delete the previous key, if any.
 		prevKey, err := tx.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
-			UserID:    dbCode.UserID,
+			UserID:    userID,
 			TokenName: tokenName,
 		})
 		if err == nil {
@@ -239,14 +506,17 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 			return xerrors.Errorf("insert oauth2 access token: %w", err)
 		}
 
+		// The token row is inserted even without a refresh token because it ties
+		// the key to the app for introspection and revocation.
 		_, err = tx.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
 			ID:          uuid.New(),
 			CreatedAt:   dbtime.Now(),
 			ExpiresAt:   key.ExpiresAt,
 			HashPrefix:  []byte(refreshToken.Prefix),
 			RefreshHash: []byte(refreshToken.Hashed),
-			AppSecretID: dbSecret.ID,
+			AppSecretID: secretID,
 			APIKeyID:    newKey.ID,
+			AppID:       app.ID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert oauth2 refresh token: %w", err)
@@ -257,12 +527,15 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 		return oauth2.Token{}, err
 	}
 
-	return oauth2.Token{
-		AccessToken:  sessionToken,
-		TokenType:    "Bearer",
-		RefreshToken: refreshToken.Formatted,
-		Expiry:       key.ExpiresAt,
-	}, nil
+	token := oauth2.Token{
+		AccessToken: sessionToken,
+		TokenType:   "Bearer",
+		Expiry:      key.ExpiresAt,
+	}
+	if withRefresh {
+		token.RefreshToken = refreshToken.Formatted
+	}
+	return token, nil
 }
 
 func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
@@ -286,6 +559,9 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 	if !equal {
 		return oauth2.Token{}, errBadToken
 	}
+	if dbToken.AppID != app.ID {
+		return oauth2.Token{}, errBadToken
+	}
 
 	// Ensure the token has not expired.
 	if dbToken.ExpiresAt.Before(dbtime.Now()) {
@@ -304,6 +580,15 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 		return oauth2.Token{}, xerrors.Errorf("fetch user actor: %w", err)
 	}
 
+	scope, err := narrowScope(tokenScope{
+		scope:       prevKey.Scope,
+		permissions: prevKey.ScopePermissions,
+		allowList:   prevKey.ScopeAllowList,
+	}, params.scope)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
 	// Generate a new refresh token.
 	refreshToken, err := GenerateSecret()
 	if err != nil {
@@ -311,12 +596,14 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 	}
 
 	// Generate the new API key.
-	// TODO: We are ignoring scopes for now.
 	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", prevKey.UserID, app.ID)
 	key, sessionToken, err := apikey.Generate(apikey.CreateParams{
-		UserID:          prevKey.UserID,
-		LoginType:       database.LoginTypeOAuth2ProviderApp,
-		DefaultLifetime: lifetimes.DefaultDuration.Value(),
+		UserID:           prevKey.UserID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DefaultLifetime:  lifetimes.DefaultDuration.Value(),
+		Scope:            scope.scope,
+		ScopePermissions: scope.permissions,
+		ScopeAllowList:   scope.allowList,
 		// For now, we allow only one token per app and user at a time.
 		TokenName: tokenName,
 	})
@@ -345,6 +632,7 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 			RefreshHash: []byte(refreshToken.Hashed),
 			AppSecretID: dbToken.AppSecretID,
 			APIKeyID:    newKey.ID,
+			AppID:       dbToken.AppID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert oauth2 refresh token: %w", err)
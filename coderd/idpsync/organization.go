@@ -258,7 +258,7 @@ func (s *OrganizationSyncSettings) ParseClaims(ctx context.Context, db database.
 
 	parsedOrganizations, err := ParseStringSliceClaim(organizationRaw)
 	if err != nil {
-		return userOrganizations, xerrors.Errorf("failed to parese organizations OIDC claims: %w", err)
+		return userOrganizations, xerrors.Errorf("failed to parse organizations claims: %w", err)
 	}
 
 	// add any mapped organizations
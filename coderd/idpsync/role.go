@@ -235,7 +235,7 @@ func (s AGPLIDPSync) syncSiteWideRoles(ctx context.Context, tx database.Store, u
 		}
 	}
 	if len(ignored) > 0 {
-		s.Logger.Debug(ctx, "OIDC roles ignored in assignment",
+		s.Logger.Debug(ctx, "IdP roles ignored in assignment",
 			slog.F("ignored", ignored),
 			slog.F("assigned", filtered),
 			slog.F("user_id", user.ID),
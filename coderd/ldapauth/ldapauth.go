@@ -0,0 +1,343 @@
+// Package ldapauth authenticates users against an LDAP or Active Directory
+// server using the bind/search/bind flow.
+//
+// A service account is bound first and used to search for the user entry that
+// matches the supplied username. The user's password is then verified by
+// binding as the returned entry. Group membership is resolved either from a
+// group search or from attributes on the user entry, so it can be fed into the
+// same claim based sync used for OIDC.
+package ldapauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+// GroupsClaim is the claim name that resolved group names are stored under
+// when a group search is configured.
+const GroupsClaim = "groups"
+
+var (
+	// ErrInvalidCredentials is returned when the username does not match
+	// exactly one entry or the password is rejected by the directory. The two
+	// cases are intentionally indistinguishable to the caller.
+	ErrInvalidCredentials = xerrors.New("invalid username or password")
+)
+
+// Config configures how to connect to and search an LDAP directory.
+type Config struct {
+	// URL is the address of the directory, e.g. "ldaps://ldap.example.com:636".
+	URL string
+	// StartTLS upgrades a plaintext "ldap://" connection with StartTLS before
+	// any credentials are sent.
+	StartTLS bool
+	// TLSConfig is used for "ldaps://" URLs and StartTLS.
+	TLSConfig *tls.Config
+	// Timeout bounds dialing and every request to the directory. Defaults to
+	// 10 seconds.
+	Timeout time.Duration
+
+	// BindDN and BindPassword are the credentials of the service account used
+	// to search the directory. If both are empty, an anonymous bind is used.
+	BindDN       string
+	BindPassword string
+
+	// UserSearchBaseDN is where user entries are searched for.
+	UserSearchBaseDN string
+	// UserSearchFilter selects the user entry. Every "%s" is replaced by the
+	// escaped username, e.g. "(&(objectClass=person)(uid=%s))".
+	UserSearchFilter string
+
+	// IDAttribute holds a stable identifier for the entry, such as
+	// "entryUUID" or "objectGUID". The raw attribute value is hex encoded so
+	// binary identifiers survive intact. If empty, the entry DN is used.
+	IDAttribute string
+	// UsernameAttribute, EmailAttribute and NameAttribute select the entry
+	// attributes used for the Coder username, email and display name.
+	UsernameAttribute string
+	EmailAttribute    string
+	NameAttribute     string
+
+	// GroupSearchBaseDN enables a group search when set. Group entries that
+	// match GroupSearchFilter are returned in the "groups" claim using the value
+	// of GroupNameAttribute.
+	GroupSearchBaseDN string
+	// GroupSearchFilter selects the groups of a user. Every "%s" is replaced by
+	// the escaped DN of the user entry, e.g. "(member=%s)".
+	GroupSearchFilter  string
+	GroupNameAttribute string
+}
+
+// Entry is an authenticated directory entry.
+type Entry struct {
+	DN       string
+	ID       string
+	Username string
+	Email    string
+	Name     string
+	// Groups is only populated when a group search is configured.
+	Groups []string
+	// Attributes holds every attribute returned for the user entry.
+	Attributes map[string][]string
+}
+
+// Claims converts the entry into a claims map compatible with the IdP sync
+// parsers. Single valued attributes are stored as strings, multi-valued
+// attributes as string slices. Resolved groups are stored under GroupsClaim.
+func (e Entry) Claims() map[string]interface{} {
+	claims := make(map[string]interface{}, len(e.Attributes)+1)
+	for name, values := range e.Attributes {
+		if len(values) == 1 {
+			claims[name] = values[0]
+			continue
+		}
+		// Copy the values into an []interface{} to match the shape of decoded
+		// JSON claims.
+		vals := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			vals = append(vals, v)
+		}
+		claims[name] = vals
+	}
+	if e.Groups != nil {
+		groups := make([]interface{}, 0, len(e.Groups))
+		for _, g := range e.Groups {
+			groups = append(groups, g)
+		}
+		claims[GroupsClaim] = groups
+	}
+	return claims
+}
+
+// Validate returns an error if the config is missing required fields.
+func (c *Config) Validate() error {
+	if c.URL == "" {
+		return xerrors.New("ldap url must be set")
+	}
+	if c.UserSearchBaseDN == "" {
+		return xerrors.New("ldap user search base dn must be set")
+	}
+	if !strings.Contains(c.UserSearchFilter, "%s") {
+		return xerrors.Errorf("ldap user search filter %q must contain %%s to substitute the username", c.UserSearchFilter)
+	}
+	if c.GroupSearchBaseDN != "" && !strings.Contains(c.GroupSearchFilter, "%s") {
+		return xerrors.Errorf("ldap group search filter %q must contain %%s to substitute the user dn", c.GroupSearchFilter)
+	}
+	if c.EmailAttribute == "" {
+		return xerrors.New("ldap email attribute must be set")
+	}
+	return nil
+}
+
+// Authenticate verifies the username and password against the directory and
+// returns the matching entry. ErrInvalidCredentials is returned if either is
+// wrong.
+func (c *Config) Authenticate(ctx context.Context, username, password string) (Entry, error) {
+	// An empty password would result in an unauthenticated bind, which most
+	// servers accept as a success. Reject it up front.
+	if username == "" || password == "" {
+		return Entry{}, ErrInvalidCredentials
+	}
+
+	conn, closeConn, err := c.dial(ctx)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer closeConn()
+
+	err = c.bindServiceAccount(conn)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry, err := c.searchUser(conn, username)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	err = conn.Bind(entry.DN, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return Entry{}, ErrInvalidCredentials
+		}
+		return Entry{}, xerrors.Errorf("bind as user: %w", err)
+	}
+
+	if c.GroupSearchBaseDN != "" {
+		// Searching is done as the service account, which is more likely to
+		// have read access to group entries than the user.
+		err = c.bindServiceAccount(conn)
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Groups, err = c.searchGroups(conn, entry.DN)
+		if err != nil {
+			return Entry{}, err
+		}
+	}
+
+	return entry, nil
+}
+
+func (c *Config) timeout() time.Duration {
+	if c.Timeout == 0 {
+		return 10 * time.Second
+	}
+	return c.Timeout
+}
+
+// dial connects to the directory. The returned function closes the
+// connection.
+func (c *Config) dial(ctx context.Context) (*ldap.Conn, func(), error) {
+	dialer := &net.Dialer{Timeout: c.timeout()}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+	conn, err := ldap.DialURL(c.URL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(c.TLSConfig))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("dial ldap server: %w", err)
+	}
+	conn.SetTimeout(c.timeout())
+
+	// The ldap client does not support contexts, so close the connection if
+	// the context is canceled to unblock any pending requests.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	closeConn := func() {
+		stop()
+		_ = conn.Close()
+	}
+
+	if c.StartTLS {
+		err = conn.StartTLS(c.TLSConfig)
+		if err != nil {
+			closeConn()
+			return nil, nil, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	return conn, closeConn, nil
+}
+
+func (c *Config) bindServiceAccount(conn *ldap.Conn) error {
+	var err error
+	if c.BindDN == "" && c.BindPassword == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	}
+	if err != nil {
+		return xerrors.Errorf("bind service account: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) searchUser(conn *ldap.Conn, username string) (Entry, error) {
+	escaped := ldap.EscapeFilter(username)
+	filter := strings.ReplaceAll(c.UserSearchFilter, "%s", escaped)
+	res, err := conn.Search(ldap.NewSearchRequest(
+		c.UserSearchBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		// Ask for two entries so ambiguous filters are detected.
+		2,
+		int(c.timeout().Seconds()),
+		false,
+		filter,
+		// Request all user attributes so they are available as claims.
+		[]string{"*"},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return Entry{}, ErrInvalidCredentials
+		}
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return Entry{}, xerrors.Errorf("ldap user search filter %q matched more than one entry", filter)
+		}
+		return Entry{}, xerrors.Errorf("search for user: %w", err)
+	}
+	switch len(res.Entries) {
+	case 0:
+		return Entry{}, ErrInvalidCredentials
+	case 1:
+	default:
+		return Entry{}, xerrors.Errorf("ldap user search filter %q matched %d entries", filter, len(res.Entries))
+	}
+
+	raw := res.Entries[0]
+	entry := Entry{
+		DN:         raw.DN,
+		ID:         raw.DN,
+		Username:   username,
+		Email:      raw.GetAttributeValue(c.EmailAttribute),
+		Attributes: make(map[string][]string, len(raw.Attributes)),
+	}
+	for _, attr := range raw.Attributes {
+		// Never expose password material as claims, even if the directory is
+		// configured to return it.
+		if strings.EqualFold(attr.Name, "userPassword") || strings.EqualFold(attr.Name, "password") {
+			continue
+		}
+		entry.Attributes[attr.Name] = attr.Values
+	}
+	if c.IDAttribute != "" {
+		// objectGUID and similar attributes are binary, so they must not be
+		// read as strings.
+		entry.ID = hex.EncodeToString(raw.GetRawAttributeValue(c.IDAttribute))
+		if entry.ID == "" {
+			return Entry{}, xerrors.Errorf("user entry %q is missing id attribute %q", raw.DN, c.IDAttribute)
+		}
+	}
+	if c.UsernameAttribute != "" {
+		if v := raw.GetAttributeValue(c.UsernameAttribute); v != "" {
+			entry.Username = v
+		}
+	}
+	if c.NameAttribute != "" {
+		entry.Name = raw.GetAttributeValue(c.NameAttribute)
+	}
+	return entry, nil
+}
+
+func (c *Config) searchGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	filter := strings.ReplaceAll(c.GroupSearchFilter, "%s", ldap.EscapeFilter(userDN))
+	nameAttr := c.GroupNameAttribute
+	if nameAttr == "" {
+		nameAttr = "cn"
+	}
+	res, err := conn.Search(ldap.NewSearchRequest(
+		c.GroupSearchBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		int(c.timeout().Seconds()),
+		false,
+		filter,
+		[]string{nameAttr},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return []string{}, nil
+		}
+		return nil, xerrors.Errorf("search for groups: %w", err)
+	}
+	groups := make([]string, 0, len(res.Entries))
+	for _, g := range res.Entries {
+		name := g.GetAttributeValue(nameAttr)
+		if name == "" {
+			// Fall back to the DN so the group is not silently dropped.
+			name = g.DN
+		}
+		groups = append(groups, name)
+	}
+	return groups, nil
+}
@@ -0,0 +1,166 @@
+package ldapauth_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/ldapauth"
+	"github.com/coder/coder/v2/coderd/ldapauth/ldapauthtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	alice := ldapauthtest.User{
+		Username: "alice",
+		Password: "alice-password",
+		Email:    "alice@example.org",
+		Name:     "Alice Liddell",
+		Groups:   []string{"developers", "admins"},
+	}
+	bob := ldapauthtest.User{
+		Username: "bob",
+		Password: "bob-password",
+		Email:    "bob@example.org",
+	}
+	// objectGUID is a 16 byte binary value that is not valid UTF-8.
+	carolGUID := []byte{0x8f, 0x00, 0xfe, 0x12, 0xc3, 0x28, 0xa0, 0xa1, 0xe2, 0x28, 0xa1, 0xff, 0x00, 0x80, 0x7f, 0x01}
+	carol := ldapauthtest.User{
+		Username:   "carol",
+		Password:   "carol-password",
+		Email:      "carol@example.org",
+		Attributes: map[string][]string{"objectGUID": {string(carolGUID)}},
+	}
+	dir := ldapauthtest.Start(t, alice, bob, carol)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+
+		entry, err := cfg.Authenticate(ctx, alice.Username, alice.Password)
+		require.NoError(t, err)
+		require.Equal(t, alice.DN(), entry.DN)
+		require.Equal(t, alice.DN(), entry.ID)
+		require.Equal(t, alice.Username, entry.Username)
+		require.Equal(t, alice.Email, entry.Email)
+		require.Equal(t, alice.Name, entry.Name)
+		require.ElementsMatch(t, alice.Groups, entry.Groups)
+		require.NotContains(t, entry.Attributes, "password")
+
+		claims := entry.Claims()
+		require.Equal(t, alice.Email, claims["mail"])
+		require.ElementsMatch(t, []interface{}{"developers", "admins"}, claims[ldapauth.GroupsClaim])
+		require.Len(t, claims["memberOf"], 2)
+	})
+
+	t.Run("NoGroups", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+
+		entry, err := cfg.Authenticate(ctx, bob.Username, bob.Password)
+		require.NoError(t, err)
+		require.Empty(t, entry.Groups)
+	})
+
+	t.Run("GroupSearchDisabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+		cfg.GroupSearchBaseDN = ""
+
+		entry, err := cfg.Authenticate(ctx, alice.Username, alice.Password)
+		require.NoError(t, err)
+		require.Nil(t, entry.Groups)
+		require.NotContains(t, entry.Claims(), ldapauth.GroupsClaim)
+	})
+
+	t.Run("BinaryID", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+		cfg.IDAttribute = "objectGUID"
+
+		entry, err := cfg.Authenticate(ctx, carol.Username, carol.Password)
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(carolGUID), entry.ID)
+	})
+
+	t.Run("MissingID", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+		cfg.IDAttribute = "objectGUID"
+
+		_, err := cfg.Authenticate(ctx, alice.Username, alice.Password)
+		require.ErrorContains(t, err, "missing id attribute")
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+
+		_, err := cfg.Authenticate(ctx, alice.Username, "wrong")
+		require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+	})
+
+	t.Run("EmptyPassword", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+
+		_, err := cfg.Authenticate(ctx, alice.Username, "")
+		require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+
+		_, err := cfg.Authenticate(ctx, "mallory", "password")
+		require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+	})
+
+	t.Run("BadServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cfg := dir.Config()
+		cfg.BindPassword = "wrong"
+
+		_, err := cfg.Authenticate(ctx, alice.Username, alice.Password)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+		require.ErrorContains(t, err, "bind service account")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := ldapauth.Config{
+		URL:              "ldaps://ldap.example.org",
+		UserSearchBaseDN: "ou=people,dc=example,dc=org",
+		UserSearchFilter: "(uid=%s)",
+		EmailAttribute:   "mail",
+	}
+	require.NoError(t, valid.Validate())
+
+	noFilterVar := valid
+	noFilterVar.UserSearchFilter = "(uid=alice)"
+	require.ErrorContains(t, noFilterVar.Validate(), "must contain %s")
+
+	noGroupFilterVar := valid
+	noGroupFilterVar.GroupSearchBaseDN = "ou=groups,dc=example,dc=org"
+	noGroupFilterVar.GroupSearchFilter = "(member=x)"
+	require.ErrorContains(t, noGroupFilterVar.Validate(), "group search filter")
+
+	noURL := valid
+	noURL.URL = ""
+	require.ErrorContains(t, noURL.Validate(), "url")
+}
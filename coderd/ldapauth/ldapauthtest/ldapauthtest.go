@@ -0,0 +1,140 @@
+// Package ldapauthtest runs an in-process LDAP directory for tests.
+package ldapauthtest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/ldapauth"
+)
+
+const (
+	UserBaseDN  = "ou=people,dc=example,dc=org"
+	GroupBaseDN = "ou=groups,dc=example,dc=org"
+
+	// ServiceDN and ServicePassword are the credentials of the service
+	// account that is always present in the directory.
+	ServiceDN       = "uid=coder-svc," + UserBaseDN
+	ServicePassword = "service-password"
+)
+
+// User describes a user entry in the test directory.
+type User struct {
+	Username string
+	Password string
+	Email    string
+	Name     string
+	// Groups are the names of the groups the user is a member of. Group
+	// entries are created for every name.
+	Groups []string
+	// Attributes are added to the user entry as is. Values may hold binary
+	// data, e.g. an objectGUID.
+	Attributes map[string][]string
+}
+
+// DN returns the distinguished name of the user entry.
+func (u User) DN() string {
+	return fmt.Sprintf("uid=%s,%s", u.Username, UserBaseDN)
+}
+
+// Directory is a running test directory.
+type Directory struct {
+	*testdirectory.Directory
+	tls *tls.Config
+}
+
+// Start runs an LDAPS directory containing the given users. The directory is
+// stopped when the test completes.
+func Start(t testing.TB, users ...User) *Directory {
+	t.Helper()
+
+	entries := []*gldap.Entry{
+		gldap.NewEntry(ServiceDN, map[string][]string{
+			"uid":      {"coder-svc"},
+			"password": {ServicePassword},
+		}),
+	}
+	groupMembers := map[string][]string{}
+	var groupOrder []string
+	for _, u := range users {
+		attrs := map[string][]string{
+			"uid":      {u.Username},
+			"mail":     {u.Email},
+			"password": {u.Password},
+		}
+		if u.Name != "" {
+			attrs["cn"] = []string{u.Name}
+		}
+		for k, v := range u.Attributes {
+			attrs[k] = v
+		}
+		if len(u.Groups) > 0 {
+			memberOf := make([]string, 0, len(u.Groups))
+			for _, g := range u.Groups {
+				memberOf = append(memberOf, groupDN(g))
+				if _, ok := groupMembers[g]; !ok {
+					groupOrder = append(groupOrder, g)
+				}
+				groupMembers[g] = append(groupMembers[g], u.DN())
+			}
+			attrs["memberOf"] = memberOf
+		}
+		entries = append(entries, gldap.NewEntry(u.DN(), attrs))
+	}
+
+	groups := make([]*gldap.Entry, 0, len(groupOrder))
+	for _, g := range groupOrder {
+		groups = append(groups, gldap.NewEntry(groupDN(g), map[string][]string{
+			"cn":     {g},
+			"member": groupMembers[g],
+		}))
+	}
+
+	dir := testdirectory.Start(t,
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{
+			Users:   entries,
+			Groups:  groups,
+			UserDN:  UserBaseDN,
+			GroupDN: GroupBaseDN,
+		}),
+	)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM([]byte(dir.Cert())), "append directory ca")
+	return &Directory{
+		Directory: dir,
+		tls: &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+}
+
+// Config returns an ldapauth.Config that authenticates against the directory
+// with group search enabled.
+func (d *Directory) Config() ldapauth.Config {
+	return ldapauth.Config{
+		URL:                fmt.Sprintf("ldaps://%s:%d", d.Host(), d.Port()),
+		TLSConfig:          d.tls.Clone(),
+		BindDN:             ServiceDN,
+		BindPassword:       ServicePassword,
+		UserSearchBaseDN:   UserBaseDN,
+		UserSearchFilter:   "(uid=%s)",
+		UsernameAttribute:  "uid",
+		EmailAttribute:     "mail",
+		NameAttribute:      "cn",
+		GroupSearchBaseDN:  GroupBaseDN,
+		GroupSearchFilter:  "(member=%s)",
+		GroupNameAttribute: "cn",
+	}
+}
+
+func groupDN(name string) string {
+	return fmt.Sprintf("cn=%s,%s", name, GroupBaseDN)
+}
@@ -113,7 +113,7 @@ func (api *API) deleteOrganizationMember(rw http.ResponseWriter, r *http.Request
 	aReq.Old = member.OrganizationMember.Auditable(member.Username)
 	defer commitAudit()
 
-	// Note: we disallow adding OIDC users if organization sync is enabled.
+	// Note: we disallow adding IdP synced users if organization sync is enabled.
 	// For removing members, do not have this same enforcement. As long as a user
 	// does not re-login, they will not be immediately removed from the organization.
 	// There might be an urgent need to revoke access.
@@ -332,7 +332,7 @@ func (api *API) allowChangingMemberRoles(ctx context.Context, rw http.ResponseWr
 		return false
 	}
 
-	if user.LoginType == database.LoginTypeOIDC {
+	if idpSyncedLoginType(user.LoginType) {
 		// nolint:gocritic // fetching settings
 		orgSync, err := api.IDPSync.OrganizationRoleSyncEnabled(dbauthz.AsSystemRestricted(ctx), api.Database, organization.ID)
 		if err != nil {
@@ -341,7 +341,7 @@ func (api *API) allowChangingMemberRoles(ctx context.Context, rw http.ResponseWr
 		}
 		if orgSync {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Cannot modify roles for OIDC users when role sync is enabled. This organization member's roles are managed by the identity provider. Have the user re-login to refresh their roles.",
+				Message: fmt.Sprintf("Cannot modify roles for %s users when role sync is enabled. This organization member's roles are managed by the identity provider. Have the user re-login to refresh their roles.", idpSyncedLoginTypeName(user.LoginType)),
 				Detail:  "'User Role Field' is set in the organization settings. Ask an administrator to adjust or disable these settings.",
 			})
 			return false
@@ -442,14 +442,14 @@ func convertOrganizationMembersWithUserData(ctx context.Context, db database.Sto
 	return converted, nil
 }
 
-// manualOrganizationMembership checks if the user is an OIDC user and if organization sync is enabled.
+// manualOrganizationMembership checks if the user is an IdP synced user and if organization sync is enabled.
 // If organization sync is enabled, manual organization assignment is not allowed,
 // since all organization membership is controlled by the external IDP.
 func (api *API) manualOrganizationMembership(ctx context.Context, rw http.ResponseWriter, user database.User) bool {
-	if user.LoginType == database.LoginTypeOIDC && api.IDPSync.OrganizationSyncEnabled(ctx, api.Database) {
+	if idpSyncedLoginType(user.LoginType) && api.IDPSync.OrganizationSyncEnabled(ctx, api.Database) {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Organization sync is enabled for OIDC users, meaning manual organization assignment is not allowed for this user. Have the user re-login to refresh their organizations.",
-			Detail:  fmt.Sprintf("User %s is an OIDC user and organization sync is enabled. Ask an administrator to resolve the membership in your external IDP.", user.Username),
+			Message: fmt.Sprintf("Organization sync is enabled for %s users, meaning manual organization assignment is not allowed for this user. Have the user re-login to refresh their organizations.", idpSyncedLoginTypeName(user.LoginType)),
+			Detail:  fmt.Sprintf("User %s logs in with %s and organization sync is enabled. Ask an administrator to resolve the membership in your external IDP.", user.Username, idpSyncedLoginTypeName(user.LoginType)),
 		})
 		return false
 	}
@@ -0,0 +1,172 @@
+// Package mfa implements the second factors offered to password
+// authenticated users: time-based one-time passwords (RFC 6238) generated by
+// an authenticator app, and single use recovery codes.
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //#nosec // SHA1 is the algorithm authenticator apps support.
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	// TOTPPeriod is the lifetime of a code.
+	TOTPPeriod = 30 * time.Second
+	// TOTPDigits is the length of a code.
+	TOTPDigits = 6
+	// totpSkew is the number of periods before and after the current one
+	// that are accepted to allow for clock drift.
+	totpSkew = 1
+	// totpSecretSize is the size of a generated secret in bytes. RFC 4226
+	// recommends 160 bits.
+	totpSecretSize = 20
+
+	// RecoveryCodeCount is the number of recovery codes generated at once.
+	RecoveryCodeCount = 10
+	// recoveryCodeCharset omits characters that are easily confused.
+	recoveryCodeCharset = "abcdefghjkmnpqrstuvwxyz23456789"
+	// recoveryCodeLength is the number of characters on either side of the
+	// dash in a recovery code.
+	recoveryCodeLength = 5
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 encoded secret.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", xerrors.Errorf("read random bytes: %w", err)
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURL returns the otpauth:// URL that authenticator apps import, usually
+// by scanning it as a QR code.
+func TOTPURL(issuer, account, secret string) string {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + issuer + ":" + account,
+		RawQuery: url.Values{
+			"secret":    {secret},
+			"issuer":    {issuer},
+			"algorithm": {"SHA1"},
+			"digits":    {fmt.Sprint(TOTPDigits)},
+			"period":    {fmt.Sprint(int(TOTPPeriod.Seconds()))},
+		}.Encode(),
+	}
+	return u.String()
+}
+
+// TOTPStep returns the time step that t falls into.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode returns the code for the time step that t falls into.
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, TOTPStep(t)), nil
+}
+
+// ValidateTOTP reports whether code is valid for the secret at the given time
+// and returns the time step it was generated for. Callers must reject codes
+// for a step that was already used to prevent replay.
+func ValidateTOTP(secret, code string, now time.Time) (int64, bool, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false, nil
+	}
+	current := TOTPStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// IsTOTPCode reports whether code has the format of a TOTP code rather than
+// a recovery code.
+func IsTOTPCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateRecoveryCodes returns RecoveryCodeCount random recovery codes in
+// the form "xxxxx-xxxxx".
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		code, err := cryptorand.StringCharset(recoveryCodeCharset, recoveryCodeLength*2)
+		if err != nil {
+			return nil, xerrors.Errorf("generate recovery code: %w", err)
+		}
+		codes = append(codes, code[:recoveryCodeLength]+"-"+code[recoveryCodeLength:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hash a recovery code is stored as. Codes are
+// normalized so they match regardless of case and dashes. Recovery codes have
+// enough entropy that a fast hash is sufficient, like API key secrets.
+func HashRecoveryCode(code string) []byte {
+	code = strings.ToLower(strings.Join(strings.Fields(code), ""))
+	code = strings.ReplaceAll(code, "-", "")
+	hashed := sha256.Sum256([]byte(code))
+	return hashed[:]
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, xerrors.Errorf("decode totp secret: %w", err)
+	}
+	return key, nil
+}
+
+// hotp implements the HMAC-based one-time password algorithm of RFC 4226.
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for range TOTPDigits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod)
+}
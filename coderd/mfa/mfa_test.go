@@ -0,0 +1,99 @@
+package mfa_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/mfa"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6238 appendix B, truncated to six digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		code, err := mfa.TOTPCode(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, "time %d", tc.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+
+	secret, err := mfa.GenerateTOTPSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	code, err := mfa.TOTPCode(secret, now)
+	require.NoError(t, err)
+	step, ok, err := mfa.ValidateTOTP(secret, code, now)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, mfa.TOTPStep(now), step)
+
+	// Codes from the previous period are accepted to allow for clock drift.
+	_, ok, err = mfa.ValidateTOTP(secret, code, now.Add(mfa.TOTPPeriod))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Codes expire after that.
+	_, ok, err = mfa.ValidateTOTP(secret, code, now.Add(3*mfa.TOTPPeriod))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = mfa.ValidateTOTP(secret, "12345", now)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = mfa.ValidateTOTP("not base32!", code, now)
+	require.Error(t, err)
+}
+
+func TestTOTPURL(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse(mfa.TOTPURL("Coder", "alice", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Coder:alice", u.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	require.Equal(t, "Coder", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := mfa.GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, mfa.RecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		require.Len(t, code, 11)
+		require.False(t, mfa.IsTOTPCode(code))
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	// Hashes ignore case, dashes and whitespace.
+	require.Equal(t, mfa.HashRecoveryCode("abcde-fghjk"), mfa.HashRecoveryCode(" ABCDE FGHJK "))
+	require.NotEqual(t, mfa.HashRecoveryCode("abcde-fghjk"), mfa.HashRecoveryCode("abcde-fghjm"))
+	require.True(t, mfa.IsTOTPCode("012345"))
+}
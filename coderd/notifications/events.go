@@ -8,16 +8,17 @@ import "github.com/google/uuid"
 
 // Workspace-related events.
 var (
-	TemplateWorkspaceCreated           = uuid.MustParse("281fdf73-c6d6-4cbb-8ff5-888baf8a2fff")
-	TemplateWorkspaceManuallyUpdated   = uuid.MustParse("d089fe7b-d5c5-4c0c-aaf5-689859f7d392")
-	TemplateWorkspaceDeleted           = uuid.MustParse("f517da0b-cdc9-410f-ab89-a86107c420ed")
-	TemplateWorkspaceAutobuildFailed   = uuid.MustParse("381df2a9-c0c0-4749-420f-80a9280c66f9")
-	TemplateWorkspaceDormant           = uuid.MustParse("0ea69165-ec14-4314-91f1-69566ac3c5a0")
-	TemplateWorkspaceAutoUpdated       = uuid.MustParse("c34a0c09-0704-4cac-bd1c-0c0146811c2b")
-	TemplateWorkspaceMarkedForDeletion = uuid.MustParse("51ce2fdf-c9ca-4be1-8d70-628674f9bc42")
-	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
-	TemplateWorkspaceOutOfMemory       = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
-	TemplateWorkspaceOutOfDisk         = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceCreated            = uuid.MustParse("281fdf73-c6d6-4cbb-8ff5-888baf8a2fff")
+	TemplateWorkspaceManuallyUpdated    = uuid.MustParse("d089fe7b-d5c5-4c0c-aaf5-689859f7d392")
+	TemplateWorkspaceDeleted            = uuid.MustParse("f517da0b-cdc9-410f-ab89-a86107c420ed")
+	TemplateWorkspaceAutobuildFailed    = uuid.MustParse("381df2a9-c0c0-4749-420f-80a9280c66f9")
+	TemplateWorkspaceDormant            = uuid.MustParse("0ea69165-ec14-4314-91f1-69566ac3c5a0")
+	TemplateWorkspaceAutoUpdated        = uuid.MustParse("c34a0c09-0704-4cac-bd1c-0c0146811c2b")
+	TemplateWorkspaceMarkedForDeletion  = uuid.MustParse("51ce2fdf-c9ca-4be1-8d70-628674f9bc42")
+	TemplateWorkspaceManualBuildFailed  = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
+	TemplateWorkspaceOutOfMemory        = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
+	TemplateWorkspaceOutOfDisk          = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceResourcesExhausted = uuid.MustParse("c3290ef1-ef5d-4db0-b924-3e1c1ad5d594")
 )
 
 // Account-related events.
@@ -1217,6 +1217,34 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceResourcesExhausted",
+			id:   notifications.TemplateWorkspaceResourcesExhausted,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace": "bobby-workspace",
+				},
+				Data: map[string]any{
+					"monitors": []map[string]any{
+						{
+							"resource":  "CPU usage",
+							"threshold": "90%",
+						},
+						{
+							"resource":  "Inode usage of /home/coder",
+							"threshold": "80%",
+						},
+						{
+							"resource":  "Process count",
+							"threshold": "500",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "TemplateTestNotification",
 			id:   notifications.TemplateTestNotification,
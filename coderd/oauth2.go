@@ -1,15 +1,20 @@
 package coderd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -113,13 +118,19 @@ func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	clientType, serviceAccountID, ok := api.oAuth2ProviderAppClient(ctx, rw, req.ClientType, req.ServiceAccountID)
+	if !ok {
+		return
+	}
 	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
-		ID:          uuid.New(),
-		CreatedAt:   dbtime.Now(),
-		UpdatedAt:   dbtime.Now(),
-		Name:        req.Name,
-		Icon:        req.Icon,
-		CallbackURL: req.CallbackURL,
+		ID:               uuid.New(),
+		CreatedAt:        dbtime.Now(),
+		UpdatedAt:        dbtime.Now(),
+		Name:             req.Name,
+		Icon:             req.Icon,
+		CallbackURL:      req.CallbackURL,
+		ClientType:       clientType,
+		ServiceAccountID: serviceAccountID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -160,12 +171,18 @@ func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	clientType, serviceAccountID, ok := api.oAuth2ProviderAppClient(ctx, rw, req.ClientType, req.ServiceAccountID)
+	if !ok {
+		return
+	}
 	app, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
-		ID:          app.ID,
-		UpdatedAt:   dbtime.Now(),
-		Name:        req.Name,
-		Icon:        req.Icon,
-		CallbackURL: req.CallbackURL,
+		ID:               app.ID,
+		UpdatedAt:        dbtime.Now(),
+		Name:             req.Name,
+		Icon:             req.Icon,
+		CallbackURL:      req.CallbackURL,
+		ClientType:       clientType,
+		ServiceAccountID: serviceAccountID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -178,6 +195,52 @@ func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.OAuth2ProviderApp(api.AccessURL, app))
 }
 
+// oAuth2ProviderAppClient validates the client type and service account of an
+// app. Only confidential apps can have a service account, since the client
+// credentials grant authenticates with the client secret alone. Service
+// accounts must not be able to log in any other way.
+func (api *API) oAuth2ProviderAppClient(ctx context.Context, rw http.ResponseWriter, clientType codersdk.OAuth2ProviderAppClientType, serviceAccountID *uuid.UUID) (database.OAuth2ProviderAppClientType, uuid.NullUUID, bool) {
+	if clientType == "" {
+		clientType = codersdk.OAuth2ProviderAppClientTypeConfidential
+	}
+	if serviceAccountID == nil {
+		return database.OAuth2ProviderAppClientType(clientType), uuid.NullUUID{}, true
+	}
+	if clientType == codersdk.OAuth2ProviderAppClientTypePublic {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Public OAuth2 applications cannot have a service account.",
+			Validations: []codersdk.ValidationError{
+				{Field: "service_account_id", Detail: "Only confidential applications can use the client credentials grant."},
+			},
+		})
+		return "", uuid.NullUUID{}, false
+	}
+	user, err := api.Database.GetUserByID(ctx, *serviceAccountID)
+	if httpapi.Is404Error(err) || (err == nil && user.Deleted) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Service account not found.",
+			Validations: []codersdk.ValidationError{
+				{Field: "service_account_id", Detail: fmt.Sprintf("User %q does not exist.", serviceAccountID.String())},
+			},
+		})
+		return "", uuid.NullUUID{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return "", uuid.NullUUID{}, false
+	}
+	if user.LoginType != database.LoginTypeNone {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Service accounts must have the login type \"none\".",
+			Validations: []codersdk.ValidationError{
+				{Field: "service_account_id", Detail: fmt.Sprintf("User %q has login type %q.", user.Username, user.LoginType)},
+			},
+		})
+		return "", uuid.NullUUID{}, false
+	}
+	return database.OAuth2ProviderAppClientType(clientType), uuid.NullUUID{UUID: user.ID, Valid: true}, true
+}
+
 // @Summary Delete OAuth2 application.
 // @ID delete-oauth2-application
 // @Security CoderSessionToken
@@ -335,7 +398,9 @@ func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Re
 // @Param state query string true "A random unguessable string"
 // @Param response_type query codersdk.OAuth2ProviderResponseType true "Response type"
 // @Param redirect_uri query string false "Redirect here after authorization"
-// @Param scope query string false "Token scopes (currently ignored)"
+// @Param scope query string false "Space-delimited token scopes, either all, application_connect, or custom scope permissions such as workspace:read"
+// @Param code_challenge query string false "PKCE code challenge, required for public apps"
+// @Param code_challenge_method query string false "PKCE code challenge method, must be S256"
 // @Success 302
 // @Router /oauth2/authorize [post]
 func (api *API) getOAuth2ProviderAppAuthorize() http.HandlerFunc {
@@ -346,10 +411,13 @@ func (api *API) getOAuth2ProviderAppAuthorize() http.HandlerFunc {
 // @ID oauth2-token-exchange
 // @Produce json
 // @Tags Enterprise
-// @Param client_id formData string false "Client ID, required if grant_type=authorization_code"
-// @Param client_secret formData string false "Client secret, required if grant_type=authorization_code"
+// @Param client_id formData string false "Client ID, required unless grant_type=refresh_token"
+// @Param client_secret formData string false "Client secret, required for confidential apps unless grant_type=refresh_token"
 // @Param code formData string false "Authorization code, required if grant_type=authorization_code"
+// @Param code_verifier formData string false "PKCE code verifier, required if a code challenge was sent with the authorization request"
+// @Param device_code formData string false "Device code, required if grant_type=urn:ietf:params:oauth:grant-type:device_code"
 // @Param refresh_token formData string false "Refresh token, required if grant_type=refresh_token"
+// @Param scope formData string false "Space-delimited token scopes, which may only narrow the scope of the grant"
 // @Param grant_type formData codersdk.OAuth2ProviderGrantType true "Grant type"
 // @Success 200 {object} oauth2.Token
 // @Router /oauth2/tokens [post]
@@ -367,3 +435,199 @@ func (api *API) postOAuth2ProviderAppToken() http.HandlerFunc {
 func (api *API) deleteOAuth2ProviderAppTokens() http.HandlerFunc {
 	return identityprovider.RevokeApp(api.Database)
 }
+
+// @Summary OAuth2 device authorization.
+// @ID oauth2-device-authorization
+// @Produce json
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string false "Client secret, required for confidential apps"
+// @Param scope formData string false "Space-delimited token scopes, either all, application_connect, or custom scope permissions such as workspace:read"
+// @Success 200 {object} codersdk.OAuth2DeviceAuthorizationResponse
+// @Router /oauth2/device [post]
+func (api *API) postOAuth2ProviderDeviceAuthorization() http.HandlerFunc {
+	return identityprovider.DeviceAuthorization(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 device verification.
+// @ID oauth2-device-verification
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param user_code query string false "The code shown on the device"
+// @Success 200
+// @Router /oauth2/device/verify [get]
+func (api *API) getOAuth2ProviderDeviceVerify() http.HandlerFunc {
+	return identityprovider.DeviceVerify(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 token introspection.
+// @ID oauth2-token-introspection
+// @Produce json
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string true "Client secret"
+// @Param token formData string true "Access or refresh token issued to the app"
+// @Success 200 {object} codersdk.OAuth2TokenIntrospection
+// @Router /oauth2/introspect [post]
+func (api *API) postOAuth2ProviderTokenIntrospection() http.HandlerFunc {
+	return identityprovider.Introspect(api.Database)
+}
+
+// @Summary OAuth2 authorization server metadata.
+// @ID oauth2-authorization-server-metadata
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} codersdk.OAuth2AuthorizationServerMetadata
+// @Router /.well-known/oauth-authorization-server [get]
+func (api *API) getOAuth2AuthorizationServerMetadata() http.HandlerFunc {
+	registration := codersdk.OAuth2ProviderDynamicRegistration(api.DeploymentValues.OAuth2.Provider.DynamicRegistration) != codersdk.OAuth2ProviderDynamicRegistrationDisabled
+	return identityprovider.Metadata(api.AccessURL, registration)
+}
+
+// @Summary OAuth2 dynamic client registration.
+// @ID oauth2-dynamic-client-registration
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.OAuth2ClientRegistrationRequest true "Client metadata"
+// @Success 201 {object} codersdk.OAuth2ClientRegistrationResponse
+// @Router /oauth2/register [post]
+func (api *API) postOAuth2ClientRegistration(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	switch codersdk.OAuth2ProviderDynamicRegistration(api.DeploymentValues.OAuth2.Provider.DynamicRegistration) {
+	case codersdk.OAuth2ProviderDynamicRegistrationOpen:
+	case codersdk.OAuth2ProviderDynamicRegistrationAuthenticated:
+		if _, ok := httpmw.APIKeyOptional(r); !ok {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "A session token is required to register OAuth2 clients.",
+			})
+			return
+		}
+	default:
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Dynamic OAuth2 client registration is disabled.",
+		})
+		return
+	}
+
+	var req codersdk.OAuth2ClientRegistrationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	req, err := identityprovider.ValidateRegistration(req, api.DeploymentValues.OAuth2.Provider.DynamicRegistrationAllowedRedirectHosts.Value())
+	var registrationErr identityprovider.RegistrationError
+	if errors.As(err, &registrationErr) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.OAuth2Error{
+			Error:            registrationErr.Code,
+			ErrorDescription: registrationErr.Description,
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	clientType := identityprovider.RegistrationClientType(req)
+
+	var (
+		app    database.OAuth2ProviderApp
+		secret identityprovider.OAuth2ProviderAppSecret
+		// Client names are picked by the client, so they often collide. Add a
+		// random suffix rather than failing the registration.
+		baseName = codersdk.UsernameFrom(req.ClientName)
+		name     = baseName
+	)
+	for attempt := 0; ; attempt++ {
+		// Registering clients is open to users that cannot manage apps, so the
+		// deployment policy checked above takes the place of RBAC.
+		app, secret, err = api.registerOAuth2Client(dbauthz.AsOAuth2ClientRegistration(ctx), name, clientType, req)
+		if !database.IsUniqueViolation(err, database.UniqueOauth2ProviderAppsNameKey) || attempt >= 10 {
+			break
+		}
+		name = codersdk.UsernameFrom(fmt.Sprintf("%s-%s", baseName, namesgenerator.GetRandomName(1)))
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error registering OAuth2 client.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Open registration may have no user to attribute the app to, so it is
+	// audited in the background.
+	userID := uuid.Nil
+	if key, ok := httpmw.APIKeyOptional(r); ok {
+		userID = key.UserID
+	}
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.OAuth2ProviderApp]{
+		Audit:     *api.Auditor.Load(),
+		Log:       api.Logger,
+		UserID:    userID,
+		RequestID: httpmw.RequestID(r),
+		Status:    http.StatusCreated,
+		Action:    database.AuditActionCreate,
+		IP:        r.RemoteAddr,
+		UserAgent: r.UserAgent(),
+		New:       app,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ClientRegistrationResponse{
+		ClientID:                app.ID.String(),
+		ClientSecret:            secret.Formatted,
+		ClientIDIssuedAt:        app.CreatedAt.Unix(),
+		ClientSecretExpiresAt:   0,
+		RedirectURIs:            []string{app.CallbackURL},
+		ClientName:              app.Name,
+		LogoURI:                 app.Icon,
+		GrantTypes:              req.GrantTypes,
+		ResponseTypes:           req.ResponseTypes,
+		TokenEndpointAuthMethod: req.TokenEndpointAuthMethod,
+	})
+}
+
+// registerOAuth2Client inserts a dynamically registered app along with a secret
+// for confidential clients.
+func (api *API) registerOAuth2Client(ctx context.Context, name string, clientType codersdk.OAuth2ProviderAppClientType, req codersdk.OAuth2ClientRegistrationRequest) (database.OAuth2ProviderApp, identityprovider.OAuth2ProviderAppSecret, error) {
+	var (
+		app    database.OAuth2ProviderApp
+		secret identityprovider.OAuth2ProviderAppSecret
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		app, err = tx.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+			ID:          uuid.New(),
+			CreatedAt:   dbtime.Now(),
+			UpdatedAt:   dbtime.Now(),
+			Name:        name,
+			Icon:        req.LogoURI,
+			CallbackURL: req.RedirectURIs[0],
+			ClientType:  database.OAuth2ProviderAppClientType(clientType),
+			// Unused registrations are deleted by dbpurge.
+			DynamicallyRegistered: true,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert oauth2 app: %w", err)
+		}
+		if clientType == codersdk.OAuth2ProviderAppClientTypePublic {
+			return nil
+		}
+
+		secret, err = identityprovider.GenerateSecret()
+		if err != nil {
+			return xerrors.Errorf("generate secret: %w", err)
+		}
+		_, err = tx.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+			ID:            uuid.New(),
+			CreatedAt:     dbtime.Now(),
+			SecretPrefix:  []byte(secret.Prefix),
+			HashedSecret:  []byte(secret.Hashed),
+			DisplaySecret: secret.Formatted[len(secret.Formatted)-6:],
+			AppID:         app.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert oauth2 app secret: %w", err)
+		}
+		return nil
+	}, nil)
+	return app, secret, err
+}
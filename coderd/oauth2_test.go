@@ -2,16 +2,20 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -836,8 +840,9 @@ func TestOAuth2ProviderTokenRefresh(t *testing.T) {
 				ExpiresAt:   expires,
 				HashPrefix:  []byte(token.Prefix),
 				RefreshHash: []byte(token.Hashed),
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    newKey.ID,
+				AppID:       test.app.ID,
 			})
 			require.NoError(t, err)
 
@@ -1053,6 +1058,940 @@ func TestOAuth2ProviderRevoke(t *testing.T) {
 	}
 }
 
+func TestOAuth2ProviderPKCE(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	topCtx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	app, err := ownerClient.PostOAuth2ProviderApp(topCtx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:        "pkce-public",
+		CallbackURL: "http://localhost:3000",
+		ClientType:  codersdk.OAuth2ProviderAppClientTypePublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.OAuth2ProviderAppClientTypePublic, app.ClientType)
+
+	tests := []struct {
+		name       string
+		authOpts   func(verifier string) []oauth2.AuthCodeOption
+		tokenOpts  func(verifier string) []oauth2.AuthCodeOption
+		authError  string
+		tokenError string
+	}{
+		{
+			name: "MissingChallenge",
+			authOpts: func(string) []oauth2.AuthCodeOption {
+				return nil
+			},
+			authError: "Invalid query params",
+		},
+		{
+			name: "PlainChallenge",
+			authOpts: func(verifier string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{
+					oauth2.SetAuthURLParam("code_challenge", verifier),
+					oauth2.SetAuthURLParam("code_challenge_method", "plain"),
+				}
+			},
+			authError: "Invalid query params",
+		},
+		{
+			name: "MissingVerifier",
+			authOpts: func(verifier string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(verifier)}
+			},
+			tokenOpts: func(string) []oauth2.AuthCodeOption {
+				return nil
+			},
+			tokenError: "Invalid code verifier",
+		},
+		{
+			name: "WrongVerifier",
+			authOpts: func(verifier string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(verifier)}
+			},
+			tokenOpts: func(string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{oauth2.VerifierOption(oauth2.GenerateVerifier())}
+			},
+			tokenError: "Invalid code verifier",
+		},
+		{
+			name: "OK",
+			authOpts: func(verifier string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(verifier)}
+			},
+			tokenOpts: func(verifier string) []oauth2.AuthCodeOption {
+				return []oauth2.AuthCodeOption{oauth2.VerifierOption(verifier)}
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			userClient, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+			// Public clients have no secret.
+			cfg := &oauth2.Config{
+				ClientID: app.ID.String(),
+				Endpoint: oauth2.Endpoint{
+					AuthURL:   app.Endpoints.Authorization,
+					TokenURL:  app.Endpoints.Token,
+					AuthStyle: oauth2.AuthStyleInParams,
+				},
+				RedirectURL: app.CallbackURL,
+			}
+
+			verifier := oauth2.GenerateVerifier()
+			code, err := authorizationFlow(ctx, userClient, cfg, test.authOpts(verifier)...)
+			if test.authError != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, test.authError)
+				return
+			}
+			require.NoError(t, err)
+
+			token, err := cfg.Exchange(ctx, code, test.tokenOpts(verifier)...)
+			if test.tokenError != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, test.tokenError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, token.AccessToken)
+			require.NotEmpty(t, token.RefreshToken)
+
+			newClient := codersdk.New(userClient.URL)
+			newClient.SetSessionToken(token.AccessToken)
+			gotUser, err := newClient.User(ctx, codersdk.Me)
+			require.NoError(t, err)
+			require.Equal(t, user.ID, gotUser.ID)
+		})
+	}
+}
+
+func TestOAuth2ProviderClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:gocritic // Creating users requires owner permission.
+	serviceAccount, err := ownerClient.CreateUserWithOrgs(ctx, codersdk.CreateUserRequestWithOrgs{
+		Email:           "service-account@coder.com",
+		Username:        "service-account",
+		UserLoginType:   codersdk.LoginTypeNone,
+		OrganizationIDs: []uuid.UUID{owner.OrganizationID},
+	})
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Public clients cannot keep a secret, so they cannot act on their own.
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		_, err := ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:             "client-credentials-public",
+			CallbackURL:      "http://localhost:3000",
+			ClientType:       codersdk.OAuth2ProviderAppClientTypePublic,
+			ServiceAccountID: &serviceAccount.ID,
+		})
+		require.Error(t, err)
+
+		// Only users that cannot log in can be service accounts.
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		_, err = ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:             "client-credentials-owner",
+			CallbackURL:      "http://localhost:3000",
+			ServiceAccountID: &owner.UserID,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("NoServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		app, err := ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "client-credentials-none",
+			CallbackURL: "http://localhost:3000",
+		})
+		require.NoError(t, err)
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		secret, err := ownerClient.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		cfg := clientcredentials.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			TokenURL:     app.Endpoints.Token,
+		}
+		_, err = cfg.Token(ctx)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "not authorized to use this grant type")
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		app, err := ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:             "client-credentials-ok",
+			CallbackURL:      "http://localhost:3000",
+			ServiceAccountID: &serviceAccount.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &serviceAccount.ID, app.ServiceAccountID)
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		secret, err := ownerClient.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		cfg := clientcredentials.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			TokenURL:     app.Endpoints.Token,
+		}
+		token, err := cfg.Token(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+		// There is no user to come back for a refresh, the app can simply ask
+		// again.
+		require.Empty(t, token.RefreshToken)
+
+		newClient := codersdk.New(ownerClient.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		gotUser, err := newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, serviceAccount.ID, gotUser.ID)
+	})
+
+	t.Run("Scope", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		app, err := ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:             "client-credentials-scope",
+			CallbackURL:      "http://localhost:3000",
+			ServiceAccountID: &serviceAccount.ID,
+		})
+		require.NoError(t, err)
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		secret, err := ownerClient.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		cfg := clientcredentials.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			TokenURL:     app.Endpoints.Token,
+			Scopes:       []string{"nope:read"},
+		}
+		_, err = cfg.Token(ctx)
+		require.ErrorContains(t, err, "invalid_scope")
+
+		cfg.Scopes = []string{"all", "user:read"}
+		_, err = cfg.Token(ctx)
+		require.ErrorContains(t, err, "invalid_scope")
+
+		cfg.Scopes = []string{"user:read"}
+		token, err := cfg.Token(ctx)
+		require.NoError(t, err)
+
+		keyID, _, _ := strings.Cut(token.AccessToken, "-")
+		key, err := ownerClient.APIKeyByID(ctx, serviceAccount.ID.String(), keyID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+		require.Equal(t, []string{"user:read"}, key.ScopePermissions)
+
+		newClient := codersdk.New(ownerClient.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		_, err = newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = newClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+}
+
+func TestOAuth2ProviderScopes(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	topCtx := testutil.Context(t, testutil.WaitLong)
+	apps := generateApps(topCtx, t, ownerClient, "scopes")
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	secret, err := ownerClient.PostOAuth2ProviderAppSecret(topCtx, apps.Default.ID)
+	require.NoError(t, err)
+
+	newConfig := func(scopes ...string) *oauth2.Config {
+		return &oauth2.Config{
+			ClientID:     apps.Default.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   apps.Default.Endpoints.Authorization,
+				TokenURL:  apps.Default.Endpoints.Token,
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+			RedirectURL: apps.Default.CallbackURL,
+			Scopes:      scopes,
+		}
+	}
+
+	// Tokens are replaced per user and app, so each test uses its own user.
+	exchange := func(ctx context.Context, t *testing.T, cfg *oauth2.Config) (*oauth2.Token, codersdk.User) {
+		t.Helper()
+		userClient, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+		code, err := authorizationFlow(ctx, userClient, cfg)
+		require.NoError(t, err)
+		token, err := cfg.Exchange(ctx, code)
+		require.NoError(t, err)
+		return token, user
+	}
+
+	apiKey := func(ctx context.Context, t *testing.T, user codersdk.User, token string) *codersdk.APIKey {
+		t.Helper()
+		keyID, _, _ := strings.Cut(token, "-")
+		key, err := ownerClient.APIKeyByID(ctx, user.ID.String(), keyID)
+		require.NoError(t, err)
+		return key
+	}
+
+	// The client library does not send a scope when refreshing, so post the
+	// form directly.
+	refresh := func(ctx context.Context, t *testing.T, refreshToken, scope string) *http.Response {
+		t.Helper()
+		form := url.Values{
+			"client_id":     {apps.Default.ID.String()},
+			"client_secret": {secret.ClientSecretFull},
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {refreshToken},
+			"scope":         {scope},
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apps.Default.Endpoints.Token, strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, user := exchange(ctx, t, newConfig())
+		key := apiKey(ctx, t, user, token.AccessToken)
+		require.Equal(t, codersdk.APIKeyScopeAll, key.Scope)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, user := exchange(ctx, t, newConfig("user:read", "workspace:read,start"))
+		key := apiKey(ctx, t, user, token.AccessToken)
+		require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+		require.Equal(t, []string{"user:read", "workspace:read", "workspace:start"}, key.ScopePermissions)
+
+		newClient := codersdk.New(ownerClient.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		_, err := newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = newClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		userClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+		_, err := authorizationFlow(ctx, userClient, newConfig("openid"))
+		require.ErrorContains(t, err, "invalid_scope")
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, user := exchange(ctx, t, newConfig("workspace:read,start"))
+
+		// Asking for more than the grant is rejected.
+		res := refresh(ctx, t, token.RefreshToken, "workspace:read,update")
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		var oauthErr codersdk.OAuth2Error
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&oauthErr))
+		require.Equal(t, "invalid_scope", oauthErr.Error)
+
+		// Narrowing the scope is allowed.
+		res = refresh(ctx, t, token.RefreshToken, "workspace:read")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var narrowed oauth2.Token
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&narrowed))
+		key := apiKey(ctx, t, user, narrowed.AccessToken)
+		require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+		require.Equal(t, []string{"workspace:read"}, key.ScopePermissions)
+
+		// Without a scope the previous one is kept.
+		res = refresh(ctx, t, narrowed.RefreshToken, "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var kept oauth2.Token
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&kept))
+		key = apiKey(ctx, t, user, kept.AccessToken)
+		require.Equal(t, []string{"workspace:read"}, key.ScopePermissions)
+	})
+}
+
+func TestOAuth2ProviderDeviceAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	topCtx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	app, err := ownerClient.PostOAuth2ProviderApp(topCtx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:        "device-public",
+		CallbackURL: "http://localhost:3000",
+		ClientType:  codersdk.OAuth2ProviderAppClientTypePublic,
+	})
+	require.NoError(t, err)
+
+	newConfig := func(scopes ...string) *oauth2.Config {
+		return &oauth2.Config{
+			ClientID: app.ID.String(),
+			Endpoint: oauth2.Endpoint{
+				AuthURL:       app.Endpoints.Authorization,
+				DeviceAuthURL: app.Endpoints.DeviceAuth,
+				TokenURL:      app.Endpoints.Token,
+				AuthStyle:     oauth2.AuthStyleInParams,
+			},
+			Scopes: scopes,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		action     string
+		scopes     []string
+		tokenError string
+	}{
+		{
+			name:       "Denied",
+			action:     "deny",
+			tokenError: "access_denied",
+		},
+		{
+			name:   "OK",
+			action: "allow",
+		},
+		{
+			name:   "Scope",
+			action: "allow",
+			scopes: []string{"user:read"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			userClient, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+			cfg := newConfig(test.scopes...)
+
+			da, err := cfg.DeviceAuth(ctx)
+			require.NoError(t, err)
+			require.NotEmpty(t, da.DeviceCode)
+			require.NotEmpty(t, da.UserCode)
+			require.NotEmpty(t, da.VerificationURIComplete)
+
+			// The device is still waiting on the user.
+			_, err = cfg.Exchange(ctx, "",
+				oauth2.SetAuthURLParam("grant_type", string(codersdk.OAuth2ProviderGrantTypeDeviceCode)),
+				oauth2.SetAuthURLParam("device_code", da.DeviceCode),
+			)
+			require.Error(t, err)
+			require.ErrorContains(t, err, "authorization_pending")
+
+			// Approve or deny the device as the user. The referer is how we know
+			// the user clicked the button on the page.
+			verifyURL, err := url.Parse(da.VerificationURIComplete)
+			require.NoError(t, err)
+			query := verifyURL.Query()
+			query.Set("action", test.action)
+			verifyURL.RawQuery = query.Encode()
+			res, err := userClient.Request(ctx, http.MethodGet, verifyURL.String(), nil, func(req *http.Request) {
+				req.Header.Set("Referer", verifyURL.String())
+			})
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			token, err := cfg.DeviceAccessToken(ctx, da)
+			if test.tokenError != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, test.tokenError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, token.AccessToken)
+
+			newClient := codersdk.New(userClient.URL)
+			newClient.SetSessionToken(token.AccessToken)
+			gotUser, err := newClient.User(ctx, codersdk.Me)
+			require.NoError(t, err)
+			require.Equal(t, user.ID, gotUser.ID)
+
+			// The scope requested by the device applies to the token.
+			if len(test.scopes) > 0 {
+				keyID, _, _ := strings.Cut(token.AccessToken, "-")
+				key, err := ownerClient.APIKeyByID(ctx, user.ID.String(), keyID)
+				require.NoError(t, err)
+				require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+				require.Equal(t, test.scopes, key.ScopePermissions)
+			}
+
+			// The device code can only be used once.
+			_, err = cfg.Exchange(ctx, "",
+				oauth2.SetAuthURLParam("grant_type", string(codersdk.OAuth2ProviderGrantTypeDeviceCode)),
+				oauth2.SetAuthURLParam("device_code", da.DeviceCode),
+			)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := newConfig("nope:read").DeviceAuth(ctx)
+		require.ErrorContains(t, err, "invalid_scope")
+	})
+
+	t.Run("InvalidUserCode", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		userClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+		verifyURL := userClient.URL.ResolveReference(&url.URL{
+			Path:     "/oauth2/device/verify",
+			RawQuery: url.Values{"user_code": {"BCDF-GHJK"}, "action": {"allow"}}.Encode(),
+		})
+		res, err := userClient.Request(ctx, http.MethodGet, verifyURL.String(), nil, func(req *http.Request) {
+			req.Header.Set("Referer", verifyURL.String())
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), "The code is invalid or has expired.")
+	})
+}
+
+func TestOAuth2ProviderTokenIntrospection(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	topCtx := testutil.Context(t, testutil.WaitLong)
+	apps := generateApps(topCtx, t, ownerClient, "introspection")
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	secret, err := ownerClient.PostOAuth2ProviderAppSecret(topCtx, apps.Default.ID)
+	require.NoError(t, err)
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	otherSecret, err := ownerClient.PostOAuth2ProviderAppSecret(topCtx, apps.NoPort.ID)
+	require.NoError(t, err)
+
+	userClient, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	cfg := &oauth2.Config{
+		ClientID:     apps.Default.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   apps.Default.Endpoints.Authorization,
+			TokenURL:  apps.Default.Endpoints.Token,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: apps.Default.CallbackURL,
+	}
+	code, err := authorizationFlow(topCtx, userClient, cfg)
+	require.NoError(t, err)
+	token, err := cfg.Exchange(topCtx, code)
+	require.NoError(t, err)
+
+	introspect := func(ctx context.Context, t *testing.T, clientID uuid.UUID, clientSecret, token string) codersdk.OAuth2TokenIntrospection {
+		t.Helper()
+		res, err := ownerClient.Request(ctx, http.MethodPost, "/oauth2/introspect", nil, func(req *http.Request) {
+			req.SetBasicAuth(clientID.String(), clientSecret)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Body = io.NopCloser(strings.NewReader(url.Values{"token": {token}}.Encode()))
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var introspection codersdk.OAuth2TokenIntrospection
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&introspection))
+		return introspection
+	}
+
+	t.Run("AccessToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		got := introspect(ctx, t, apps.Default.ID, secret.ClientSecretFull, token.AccessToken)
+		require.True(t, got.Active)
+		require.Equal(t, "Bearer", got.TokenType)
+		require.Equal(t, apps.Default.ID.String(), got.ClientID)
+		require.Equal(t, user.ID.String(), got.Sub)
+		require.Equal(t, user.Username, got.Username)
+	})
+
+	t.Run("RefreshToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		got := introspect(ctx, t, apps.Default.ID, secret.ClientSecretFull, token.RefreshToken)
+		require.True(t, got.Active)
+		require.Equal(t, "refresh_token", got.TokenType)
+	})
+
+	t.Run("OtherApp", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		got := introspect(ctx, t, apps.NoPort.ID, otherSecret.ClientSecretFull, token.AccessToken)
+		require.False(t, got.Active)
+		require.Empty(t, got.Sub)
+	})
+
+	t.Run("Garbage", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		got := introspect(ctx, t, apps.Default.ID, secret.ClientSecretFull, "not-a-token")
+		require.False(t, got.Active)
+	})
+
+	t.Run("BadSecret", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := ownerClient.Request(ctx, http.MethodPost, "/oauth2/introspect", nil, func(req *http.Request) {
+			req.SetBasicAuth(apps.Default.ID.String(), otherSecret.ClientSecretFull)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Body = io.NopCloser(strings.NewReader(url.Values{"token": {token.AccessToken}}.Encode()))
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+}
+
+func TestOAuth2ProviderRateLimit(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{LoginRateLimit: 2})
+	_ = coderdtest.CreateFirstUser(t, client)
+	// Apps call these routes without a session.
+	anonymous := codersdk.New(client.URL)
+
+	for _, path := range []string{"/oauth2/tokens", "/oauth2/device", "/oauth2/introspect"} {
+		t.Run(strings.TrimPrefix(path, "/oauth2/"), func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			post := func() int {
+				res, err := anonymous.Request(ctx, http.MethodPost, path, nil, func(req *http.Request) {
+					req.SetBasicAuth(uuid.NewString(), "not-a-secret")
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+					req.Body = io.NopCloser(strings.NewReader(url.Values{"token": {"not-a-token"}}.Encode()))
+				})
+				require.NoError(t, err)
+				_ = res.Body.Close()
+				return res.StatusCode
+			}
+			require.NotEqual(t, http.StatusTooManyRequests, post())
+			require.NotEqual(t, http.StatusTooManyRequests, post())
+			require.Equal(t, http.StatusTooManyRequests, post())
+		})
+	}
+}
+
+func TestOAuth2AuthorizationServerMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RegistrationDisabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		client := coderdtest.New(t, nil)
+		metadata, err := codersdk.New(client.URL).OAuth2AuthorizationServerMetadata(ctx)
+		require.NoError(t, err)
+		require.Equal(t, client.URL.String(), metadata.Issuer)
+		require.Equal(t, client.URL.String()+"/oauth2/authorize", metadata.AuthorizationEndpoint)
+		require.Equal(t, client.URL.String()+"/oauth2/tokens", metadata.TokenEndpoint)
+		require.Equal(t, client.URL.String()+"/oauth2/device", metadata.DeviceAuthorizationEndpoint)
+		require.Equal(t, client.URL.String()+"/oauth2/introspect", metadata.IntrospectionEndpoint)
+		require.Empty(t, metadata.RegistrationEndpoint)
+		require.Contains(t, metadata.CodeChallengeMethodsSupported, codersdk.OAuth2ProviderCodeChallengeMethodS256)
+		require.Contains(t, metadata.GrantTypesSupported, codersdk.OAuth2ProviderGrantTypeDeviceCode)
+	})
+
+	t.Run("RegistrationEnabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.OAuth2.Provider.DynamicRegistration = string(codersdk.OAuth2ProviderDynamicRegistrationOpen)
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		metadata, err := codersdk.New(client.URL).OAuth2AuthorizationServerMetadata(ctx)
+		require.NoError(t, err)
+		require.Equal(t, client.URL.String()+"/oauth2/register", metadata.RegistrationEndpoint)
+	})
+}
+
+func TestOAuth2ClientRegistration(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T, mode codersdk.OAuth2ProviderDynamicRegistration, allowedHosts ...string) *codersdk.Client {
+		dv := coderdtest.DeploymentValues(t)
+		dv.OAuth2.Provider.DynamicRegistration = string(mode)
+		dv.OAuth2.Provider.DynamicRegistrationAllowedRedirectHosts = allowedHosts
+		return coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		client := newClient(t, codersdk.OAuth2ProviderDynamicRegistrationDisabled)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err := client.PostOAuth2ClientRegistration(ctx, codersdk.OAuth2ClientRegistrationRequest{
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+
+	t.Run("Authenticated", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		ownerClient := newClient(t, codersdk.OAuth2ProviderDynamicRegistrationAuthenticated)
+		owner := coderdtest.CreateFirstUser(t, ownerClient)
+		userClient, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+		// Without an initial access token the registration is rejected.
+		_, err := codersdk.New(ownerClient.URL).PostOAuth2ClientRegistration(ctx, codersdk.OAuth2ClientRegistrationRequest{
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+
+		// Members cannot manage apps, but can register them. The session token
+		// is sent as a bearer token as described in RFC 7591.
+		res, err := codersdk.New(ownerClient.URL).Request(ctx, http.MethodPost, "/oauth2/register", codersdk.OAuth2ClientRegistrationRequest{
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+			ClientName:   "My Tool",
+		}, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+userClient.SessionToken())
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var registration codersdk.OAuth2ClientRegistrationResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&registration))
+		require.Equal(t, "MyTool", registration.ClientName)
+		require.NotEmpty(t, registration.ClientSecret)
+		require.Equal(t, codersdk.OAuth2TokenEndpointAuthMethodClientSecretBasic, registration.TokenEndpointAuthMethod)
+		require.Equal(t, []codersdk.OAuth2ProviderGrantType{codersdk.OAuth2ProviderGrantTypeAuthorizationCode}, registration.GrantTypes)
+
+		// The registered client can go through the usual flow.
+		cfg := &oauth2.Config{
+			ClientID:     registration.ClientID,
+			ClientSecret: registration.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   ownerClient.URL.String() + "/oauth2/authorize",
+				TokenURL:  ownerClient.URL.String() + "/oauth2/tokens",
+				AuthStyle: oauth2.AuthStyleInHeader,
+			},
+			RedirectURL: registration.RedirectURIs[0],
+		}
+		code, err := authorizationFlow(ctx, userClient, cfg)
+		require.NoError(t, err)
+		token, err := cfg.Exchange(ctx, code)
+		require.NoError(t, err)
+
+		tokenClient := codersdk.New(ownerClient.URL)
+		tokenClient.SetSessionToken(token.AccessToken)
+		gotUser, err := tokenClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, gotUser.ID)
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.OAuth2.Provider.DynamicRegistration = string(codersdk.OAuth2ProviderDynamicRegistrationOpen)
+		dv.OAuth2.Provider.DynamicRegistrationRateLimit = 2
+		ownerClient := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		_ = coderdtest.CreateFirstUser(t, ownerClient)
+		anonClient := codersdk.New(ownerClient.URL)
+
+		req := codersdk.OAuth2ClientRegistrationRequest{
+			RedirectURIs: []string{"https://example.com/callback"},
+			ClientName:   "limited",
+		}
+		for range 2 {
+			_, err := anonClient.PostOAuth2ClientRegistration(ctx, req)
+			require.NoError(t, err)
+		}
+		_, err := anonClient.PostOAuth2ClientRegistration(ctx, req)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusTooManyRequests, sdkErr.StatusCode())
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		t.Parallel()
+
+		ownerClient := newClient(t, codersdk.OAuth2ProviderDynamicRegistrationOpen)
+		_ = coderdtest.CreateFirstUser(t, ownerClient)
+		anonClient := codersdk.New(ownerClient.URL)
+
+		t.Run("Public", func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			registration, err := anonClient.PostOAuth2ClientRegistration(ctx, codersdk.OAuth2ClientRegistrationRequest{
+				RedirectURIs: []string{"http://127.0.0.1:8080/callback"},
+				ClientName:   "public-client",
+				GrantTypes: []codersdk.OAuth2ProviderGrantType{
+					codersdk.OAuth2ProviderGrantTypeAuthorizationCode,
+					codersdk.OAuth2ProviderGrantTypeRefreshToken,
+				},
+				TokenEndpointAuthMethod: codersdk.OAuth2TokenEndpointAuthMethodNone,
+			})
+			require.NoError(t, err)
+			require.Empty(t, registration.ClientSecret)
+
+			//nolint:gocritic // OAauth2 app management requires owner permission.
+			app, err := ownerClient.OAuth2ProviderApp(ctx, uuid.MustParse(registration.ClientID))
+			require.NoError(t, err)
+			require.Equal(t, codersdk.OAuth2ProviderAppClientTypePublic, app.ClientType)
+			require.Equal(t, "http://127.0.0.1:8080/callback", app.CallbackURL)
+		})
+
+		t.Run("DuplicateName", func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			req := codersdk.OAuth2ClientRegistrationRequest{
+				RedirectURIs: []string{"https://example.com/callback"},
+				ClientName:   "duplicate",
+			}
+			first, err := anonClient.PostOAuth2ClientRegistration(ctx, req)
+			require.NoError(t, err)
+			second, err := anonClient.PostOAuth2ClientRegistration(ctx, req)
+			require.NoError(t, err)
+			require.Equal(t, "duplicate", first.ClientName)
+			require.NotEqual(t, first.ClientName, second.ClientName)
+			require.NotEqual(t, first.ClientID, second.ClientID)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			t.Parallel()
+
+			tests := []struct {
+				name  string
+				req   codersdk.OAuth2ClientRegistrationRequest
+				error string
+			}{
+				{
+					name:  "NoRedirectURI",
+					req:   codersdk.OAuth2ClientRegistrationRequest{},
+					error: "invalid_redirect_uri",
+				},
+				{
+					name: "InsecureRedirectURI",
+					req: codersdk.OAuth2ClientRegistrationRequest{
+						RedirectURIs: []string{"http://example.com/callback"},
+					},
+					error: "invalid_redirect_uri",
+				},
+				{
+					name: "ClientCredentials",
+					req: codersdk.OAuth2ClientRegistrationRequest{
+						RedirectURIs: []string{"https://example.com/callback"},
+						GrantTypes:   []codersdk.OAuth2ProviderGrantType{codersdk.OAuth2ProviderGrantTypeClientCredentials},
+					},
+					error: "invalid_client_metadata",
+				},
+				{
+					name: "AuthMethod",
+					req: codersdk.OAuth2ClientRegistrationRequest{
+						RedirectURIs:            []string{"https://example.com/callback"},
+						TokenEndpointAuthMethod: "private_key_jwt",
+					},
+					error: "invalid_client_metadata",
+				},
+			}
+			for _, test := range tests {
+				test := test
+				t.Run(test.name, func(t *testing.T) {
+					t.Parallel()
+					ctx := testutil.Context(t, testutil.WaitLong)
+
+					_, err := anonClient.PostOAuth2ClientRegistration(ctx, test.req)
+					var sdkErr *codersdk.Error
+					require.ErrorAs(t, err, &sdkErr)
+					require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+					require.Contains(t, sdkErr.Detail, test.error)
+				})
+			}
+		})
+	})
+
+	t.Run("AllowedRedirectHosts", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		client := newClient(t, codersdk.OAuth2ProviderDynamicRegistrationOpen, "*.example.com", "localhost")
+		_ = coderdtest.CreateFirstUser(t, client)
+		anonClient := codersdk.New(client.URL)
+
+		for _, redirectURI := range []string{"https://tools.example.com/callback", "http://localhost:1234"} {
+			_, err := anonClient.PostOAuth2ClientRegistration(ctx, codersdk.OAuth2ClientRegistrationRequest{
+				RedirectURIs: []string{redirectURI},
+			})
+			require.NoError(t, err, redirectURI)
+		}
+		for _, redirectURI := range []string{"https://example.com/callback", "https://example.org/callback", "http://127.0.0.1:1234"} {
+			_, err := anonClient.PostOAuth2ClientRegistration(ctx, codersdk.OAuth2ClientRegistrationRequest{
+				RedirectURIs: []string{redirectURI},
+			})
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr, redirectURI)
+			require.Contains(t, sdkErr.Detail, "invalid_redirect_uri", redirectURI)
+		}
+	})
+}
+
 type provisionedApps struct {
 	Default   codersdk.OAuth2ProviderApp
 	NoPort    codersdk.OAuth2ProviderApp
@@ -1087,10 +2026,10 @@ func generateApps(ctx context.Context, t *testing.T, client *codersdk.Client, su
 	}
 }
 
-func authorizationFlow(ctx context.Context, client *codersdk.Client, cfg *oauth2.Config) (string, error) {
+func authorizationFlow(ctx context.Context, client *codersdk.Client, cfg *oauth2.Config, opts ...oauth2.AuthCodeOption) (string, error) {
 	state := uuid.NewString()
 	return oidctest.OAuth2GetCode(
-		cfg.AuthCodeURL(state),
+		cfg.AuthCodeURL(state, opts...),
 		func(req *http.Request) (*http.Response, error) {
 			// TODO: Would be better if client had a .Do() method.
 			// TODO: Is this the best way to handle redirects?
@@ -2299,6 +2299,12 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 					return xerrors.Errorf("failed to insert agent volume resource monitor into db: %w", err)
 				}
 			}
+			for _, monitor := range resourceMonitorParams(agentID, prAgent.ResourcesMonitoring) {
+				_, err = db.InsertResourceMonitor(ctx, monitor)
+				if err != nil {
+					return xerrors.Errorf("failed to insert agent %s resource monitor into db: %w", monitor.Type, err)
+				}
+			}
 		}
 
 		logSourceIDs := make([]uuid.UUID, 0, len(prAgent.Scripts))
@@ -2407,6 +2413,101 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			return xerrors.Errorf("insert agent scripts: %w", err)
 		}
 
+		if len(prAgent.Services) > 0 {
+			var (
+				serviceIDs                 = make([]uuid.UUID, 0, len(prAgent.Services))
+				serviceLogSourceIDs        = make([]uuid.UUID, 0, len(prAgent.Services))
+				serviceLogSourceNames      = make([]string, 0, len(prAgent.Services))
+				serviceLogPaths            = make([]string, 0, len(prAgent.Services))
+				serviceNames               = make([]string, 0, len(prAgent.Services))
+				serviceDisplayNames        = make([]string, 0, len(prAgent.Services))
+				serviceIcons               = make([]string, 0, len(prAgent.Services))
+				serviceCommands            = make([]string, 0, len(prAgent.Services))
+				serviceRestartPolicies     = make([]database.WorkspaceAgentServiceRestartPolicy, 0, len(prAgent.Services))
+				serviceRestartDelays       = make([]int32, 0, len(prAgent.Services))
+				serviceReadinessURLs       = make([]string, 0, len(prAgent.Services))
+				serviceReadinessIntervals  = make([]int32, 0, len(prAgent.Services))
+				serviceReadinessThresholds = make([]int32, 0, len(prAgent.Services))
+				serviceHealths             = make([]database.WorkspaceAppHealth, 0, len(prAgent.Services))
+			)
+			for _, service := range prAgent.Services {
+				if !provisioner.AgentNameRegex.MatchString(service.Name) {
+					return xerrors.Errorf("service name %q does not match regex %q", service.Name, provisioner.AgentNameRegex.String())
+				}
+				if slices.Contains(serviceNames, service.Name) {
+					return xerrors.Errorf("duplicate service name %q, must be unique per agent", service.Name)
+				}
+				displayName := service.DisplayName
+				if displayName == "" {
+					displayName = service.Name
+				}
+				var restartPolicy database.WorkspaceAgentServiceRestartPolicy
+				switch service.RestartPolicy {
+				case sdkproto.ServiceRestartPolicy_RESTART_ON_FAILURE:
+					restartPolicy = database.WorkspaceAgentServiceRestartPolicyOnFailure
+				case sdkproto.ServiceRestartPolicy_RESTART_NEVER:
+					restartPolicy = database.WorkspaceAgentServiceRestartPolicyNever
+				default:
+					restartPolicy = database.WorkspaceAgentServiceRestartPolicyAlways
+				}
+				readiness := service.ReadinessProbe
+				if readiness == nil {
+					readiness = &sdkproto.Healthcheck{}
+				}
+				health := database.WorkspaceAppHealthDisabled
+				if readiness.Url != "" {
+					health = database.WorkspaceAppHealthInitializing
+				}
+
+				serviceIDs = append(serviceIDs, uuid.New())
+				serviceLogSourceIDs = append(serviceLogSourceIDs, uuid.New())
+				serviceLogSourceNames = append(serviceLogSourceNames, displayName)
+				serviceLogPaths = append(serviceLogPaths, service.LogPath)
+				serviceNames = append(serviceNames, service.Name)
+				serviceDisplayNames = append(serviceDisplayNames, displayName)
+				serviceIcons = append(serviceIcons, service.Icon)
+				serviceCommands = append(serviceCommands, service.Command)
+				serviceRestartPolicies = append(serviceRestartPolicies, restartPolicy)
+				serviceRestartDelays = append(serviceRestartDelays, service.RestartDelaySeconds)
+				serviceReadinessURLs = append(serviceReadinessURLs, readiness.Url)
+				serviceReadinessIntervals = append(serviceReadinessIntervals, readiness.Interval)
+				serviceReadinessThresholds = append(serviceReadinessThresholds, readiness.Threshold)
+				serviceHealths = append(serviceHealths, health)
+			}
+
+			_, err = db.InsertWorkspaceAgentLogSources(ctx, database.InsertWorkspaceAgentLogSourcesParams{
+				WorkspaceAgentID: agentID,
+				ID:               serviceLogSourceIDs,
+				CreatedAt:        dbtime.Now(),
+				DisplayName:      serviceLogSourceNames,
+				Icon:             serviceIcons,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent service log sources: %w", err)
+			}
+
+			_, err = db.InsertWorkspaceAgentServices(ctx, database.InsertWorkspaceAgentServicesParams{
+				WorkspaceAgentID:         agentID,
+				CreatedAt:                dbtime.Now(),
+				ID:                       serviceIDs,
+				LogSourceID:              serviceLogSourceIDs,
+				LogPath:                  serviceLogPaths,
+				Name:                     serviceNames,
+				DisplayName:              serviceDisplayNames,
+				Icon:                     serviceIcons,
+				Command:                  serviceCommands,
+				RestartPolicy:            serviceRestartPolicies,
+				RestartDelaySeconds:      serviceRestartDelays,
+				ReadinessURL:             serviceReadinessURLs,
+				ReadinessIntervalSeconds: serviceReadinessIntervals,
+				ReadinessThreshold:       serviceReadinessThresholds,
+				Health:                   serviceHealths,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent services: %w", err)
+			}
+		}
+
 		for _, app := range prAgent.Apps {
 			// Similar logic is duplicated in terraform/resources.go.
 			slug := app.Slug
@@ -2773,3 +2874,35 @@ func convertDisplayApps(apps *sdkproto.DisplayApps) []database.DisplayApp {
 	}
 	return dapps
 }
+
+// resourceMonitorParams returns the monitors of resources other than memory
+// and volume space declared for an agent.
+func resourceMonitorParams(agentID uuid.UUID, monitoring *sdkproto.ResourcesMonitoring) []database.InsertResourceMonitorParams {
+	var params []database.InsertResourceMonitorParams
+	add := func(typ database.WorkspaceAgentResourceMonitorType, path string, enabled bool, threshold int32) {
+		params = append(params, database.InsertResourceMonitorParams{
+			AgentID:        agentID,
+			Type:           typ,
+			Path:           path,
+			Enabled:        enabled,
+			Threshold:      threshold,
+			State:          database.WorkspaceAgentMonitorStateOK,
+			CreatedAt:      dbtime.Now(),
+			UpdatedAt:      dbtime.Now(),
+			DebouncedUntil: time.Time{},
+		})
+	}
+	if cpu := monitoring.GetCpu(); cpu != nil {
+		add(database.WorkspaceAgentResourceMonitorTypeCPU, "", cpu.Enabled, cpu.Threshold)
+	}
+	if load := monitoring.GetLoad(); load != nil {
+		add(database.WorkspaceAgentResourceMonitorTypeLoad, "", load.Enabled, load.Threshold)
+	}
+	for _, inodes := range monitoring.GetInodes() {
+		add(database.WorkspaceAgentResourceMonitorTypeInodes, inodes.Path, inodes.Enabled, inodes.Threshold)
+	}
+	if processes := monitoring.GetProcesses(); processes != nil {
+		add(database.WorkspaceAgentResourceMonitorTypeProcesses, "", processes.Enabled, processes.Threshold)
+	}
+	return params
+}
@@ -2842,6 +2842,21 @@ func TestInsertWorkspaceResource(t *testing.T) {
 							Threshold: 50,
 						},
 					},
+					Cpu: &sdkproto.CPUResourceMonitor{
+						Enabled:   true,
+						Threshold: 95,
+					},
+					Inodes: []*sdkproto.InodesResourceMonitor{
+						{
+							Path:      "/volume1",
+							Enabled:   true,
+							Threshold: 70,
+						},
+					},
+					Processes: &sdkproto.ProcessesResourceMonitor{
+						Enabled:   false,
+						Threshold: 1000,
+					},
 				},
 			}},
 		})
@@ -2865,6 +2880,17 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "/volume1", volMonitors[0].Path)
 		require.Equal(t, int32(50), volMonitors[1].Threshold)
 		require.Equal(t, "/volume2", volMonitors[1].Path)
+
+		monitors, err := db.FetchResourceMonitorsByAgentID(ctx, agent.ID)
+		require.NoError(t, err)
+		require.Len(t, monitors, 3)
+		require.Equal(t, database.WorkspaceAgentResourceMonitorTypeCPU, monitors[0].Type)
+		require.Equal(t, int32(95), monitors[0].Threshold)
+		require.Equal(t, database.WorkspaceAgentResourceMonitorTypeInodes, monitors[1].Type)
+		require.Equal(t, "/volume1", monitors[1].Path)
+		require.Equal(t, int32(70), monitors[1].Threshold)
+		require.Equal(t, database.WorkspaceAgentResourceMonitorTypeProcesses, monitors[2].Type)
+		require.False(t, monitors[2].Enabled)
 	})
 
 	t.Run("Devcontainers", func(t *testing.T) {
@@ -2900,6 +2926,93 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "/workspace2", devcontainers[1].WorkspaceFolder)
 		require.Equal(t, "/workspace2/.devcontainer/devcontainer.json", devcontainers[1].ConfigPath)
 	})
+
+	t.Run("Services", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Startup",
+					Script:      "echo hello",
+					RunOnStart:  true,
+				}},
+				Services: []*sdkproto.Service{
+					{
+						Name:                "web",
+						DisplayName:         "Web Server",
+						Command:             "npm run dev",
+						RestartDelaySeconds: 5,
+						ReadinessProbe: &sdkproto.Healthcheck{
+							Url:       "http://localhost:3000",
+							Interval:  5,
+							Threshold: 3,
+						},
+					},
+					{
+						Name:          "worker",
+						Command:       "./worker",
+						RestartPolicy: sdkproto.ServiceRestartPolicy_RESTART_NEVER,
+					},
+				},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		agent := agents[0]
+		services, err := db.GetWorkspaceAgentServicesByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		require.Len(t, services, 2)
+		require.Equal(t, "web", services[0].Name)
+		require.Equal(t, "Web Server", services[0].DisplayName)
+		require.Equal(t, database.WorkspaceAgentServiceRestartPolicyAlways, services[0].RestartPolicy)
+		require.EqualValues(t, 5, services[0].RestartDelaySeconds)
+		require.Equal(t, "http://localhost:3000", services[0].ReadinessURL)
+		require.Equal(t, database.WorkspaceAppHealthInitializing, services[0].Health)
+		require.Equal(t, database.WorkspaceAgentServiceStatusPending, services[0].Status)
+		require.Equal(t, "worker", services[1].Name)
+		require.Equal(t, "worker", services[1].DisplayName)
+		require.Equal(t, database.WorkspaceAgentServiceRestartPolicyNever, services[1].RestartPolicy)
+		require.Equal(t, database.WorkspaceAppHealthDisabled, services[1].Health)
+
+		// Every service gets its own log source, separate from scripts.
+		logSources, err := db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		require.Len(t, logSources, 3)
+		logSourceIDs := make([]uuid.UUID, 0, len(logSources))
+		for _, source := range logSources {
+			logSourceIDs = append(logSourceIDs, source.ID)
+		}
+		require.Contains(t, logSourceIDs, services[0].LogSourceID)
+		require.Contains(t, logSourceIDs, services[1].LogSourceID)
+	})
+
+	t.Run("DuplicateServices", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Services: []*sdkproto.Service{
+					{Name: "web", Command: "true"},
+					{Name: "web", Command: "true"},
+				},
+			}},
+		})
+		require.ErrorContains(t, err, "duplicate service name")
+	})
 }
 
 func TestNotifications(t *testing.T) {
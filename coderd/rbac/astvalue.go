@@ -140,6 +140,10 @@ func (z Object) regoValue() ast.Value {
 			ast.StringTerm("acl_group_list"),
 			ast.NewTerm(grpACL),
 		},
+		[2]*ast.Term{
+			ast.StringTerm("template_id"),
+			ast.StringTerm(z.TemplateID),
+		},
 	)
 }
 
@@ -74,6 +74,8 @@ const (
 	SubjectTypeSystemRestricted             SubjectType = "system_restricted"
 	SubjectTypeNotifier                     SubjectType = "notifier"
 	SubjectTypeSubAgentAPI                  SubjectType = "sub_agent_api"
+	SubjectTypeOAuth2ClientRegistration     SubjectType = "oauth2_client_registration"
+	SubjectTypeSCIMProvisioner              SubjectType = "scim_provisioner"
 )
 
 // Subject is a struct that contains all the elements of a subject in an rbac
@@ -302,6 +304,7 @@ func NewAuthorizer(registry prometheus.Registerer) *RegoAuthorizer {
 				"input.object.org_owner",
 				"input.object.acl_user_list",
 				"input.object.acl_group_list",
+				"input.object.template_id",
 			}),
 			rego.Query("data.authz.allow = true"),
 			rego.Module("policy.rego", regoPolicy),
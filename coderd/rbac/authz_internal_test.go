@@ -1111,6 +1111,212 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceOrganization.WithID(defOrg)},
 		}),
 	)
+
+	templateID := uuid.New()
+	user = Subject{
+		ID: meID.String(),
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+		},
+		Scope: CustomScope(CustomScopeParams{
+			Permissions: map[string][]policy.Action{
+				ResourceWorkspace.Type: {policy.ActionRead, policy.ActionWorkspaceStart},
+				ResourceTemplate.Type:  {policy.ActionRead},
+			},
+			AllowList: []string{"workspace:" + workspaceID.String()},
+		}),
+	}
+
+	testAuthorize(t, "CustomScope_TypedAllowList", user,
+		// Only the allowed workspace can be used.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionRead, policy.ActionWorkspaceStart}
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), allow: true},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner("not-me"), allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), allow: false},
+		}),
+		// Other actions are not allowed by the scope.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStop}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID)},
+		}),
+		// Other types are not limited by the allow list.
+		[]authTestCase{
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceUser.WithID(meID).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	user.Scope = CustomScope(CustomScopeParams{
+		Permissions: map[string][]policy.Action{
+			ResourceWorkspace.Type: {policy.ActionRead},
+			ResourceTemplate.Type:  {policy.ActionRead},
+		},
+		AllowList: []string{"template:" + templateID.String()},
+	})
+
+	testAuthorize(t, "CustomScope_TemplateAllowList", user,
+		// Workspaces are allowed by the template they were created from.
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID).InTemplate(templateID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID).InTemplate(templateID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID).InTemplate(uuid.New()), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	user.Scope = CustomScope(CustomScopeParams{
+		Permissions: map[string][]policy.Action{
+			ResourceTemplate.Type: {policy.ActionUpdate},
+		},
+		OrganizationIDs: []uuid.UUID{defOrg},
+	})
+
+	testAuthorize(t, "CustomScope_Organization", user,
+		[]authTestCase{
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: true},
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: true},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []policy.Action{policy.ActionRead, policy.ActionDelete}, allow: false},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(unusedID), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceTemplate.WithID(templateID), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+		},
+	)
+}
+
+// TestAuthorizeBuiltinScopes guards the built-in scopes against the policy
+// reading the org and user permissions of input.subject.scope. The built-in
+// scopes only have site permissions, so org and user level decisions must
+// still come from the roles of the subject alone.
+func TestAuthorizeBuiltinScopes(t *testing.T) {
+	t.Parallel()
+
+	for name, scope := range builtinScopes {
+		require.Empty(t, scope.Org, "scope %q must not have org permissions", name)
+		require.Empty(t, scope.User, "scope %q must not have user permissions", name)
+	}
+
+	defOrg := uuid.New()
+	otherOrg := uuid.New()
+	meID := uuid.New()
+	member := func(scope ScopeName) Subject {
+		return Subject{
+			ID: meID.String(),
+			Roles: Roles{
+				must(RoleByName(RoleMember())),
+				must(RoleByName(ScopedRoleOrgMember(defOrg))),
+			},
+			Scope: must(scope.Expand()),
+		}
+	}
+
+	testAuthorize(t, "Member_ScopeAll", member(ScopeAll),
+		[]authTestCase{
+			// User level permissions of the member role.
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceStart}, allow: true},
+			{resource: ResourceUser.WithID(meID).WithOwner(meID.String()), actions: []policy.Action{policy.ActionReadPersonal}, allow: true},
+			// Org level permissions of the org member role.
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(otherOrg).InOrg(otherOrg), actions: []policy.Action{policy.ActionRead}, allow: false},
+			// Workspaces of other users and orgs.
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceWorkspace.InOrg(otherOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	testAuthorize(t, "Member_ScopeApplicationConnect", member(ScopeApplicationConnect),
+		[]authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionApplicationConnect}, allow: true},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionApplicationConnect}, allow: false},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceUser.WithID(meID).WithOwner(meID.String()), actions: []policy.Action{policy.ActionReadPersonal}, allow: false},
+		},
+	)
+
+	testAuthorize(t, "Member_ScopeNoUserData", member(ScopeNoUserData),
+		[]authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead, policy.ActionWorkspaceStart}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceUser.WithID(meID).WithOwner(meID.String()), actions: []policy.Action{policy.ActionReadPersonal}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+}
+
+// TestAuthorizeScopeOrgAndUserPermissions checks that the org and user
+// permissions of a scope limit what a subject can do, the same as its site
+// permissions.
+func TestAuthorizeScopeOrgAndUserPermissions(t *testing.T) {
+	t.Parallel()
+
+	defOrg := uuid.New()
+	otherOrg := uuid.New()
+	meID := uuid.New()
+	orgAdmin := func(scope Role) Subject {
+		return Subject{
+			ID: meID.String(),
+			Roles: Roles{
+				must(RoleByName(RoleMember())),
+				must(RoleByName(ScopedRoleOrgMember(defOrg))),
+				must(RoleByName(ScopedRoleOrgAdmin(defOrg))),
+				must(RoleByName(ScopedRoleOrgMember(otherOrg))),
+				must(RoleByName(ScopedRoleOrgAdmin(otherOrg))),
+			},
+			Scope: Scope{
+				Role:        scope,
+				AllowIDList: []string{policy.WildcardSymbol},
+			},
+		}
+	}
+
+	testAuthorize(t, "OrgScope", orgAdmin(Role{
+		Identifier: RoleIdentifier{Name: "org_templates"},
+		Site:       []Permission{},
+		Org: map[string][]Permission{
+			defOrg.String(): Permissions(map[string][]policy.Action{
+				ResourceTemplate.Type: {policy.ActionRead, policy.ActionUpdate},
+			}),
+		},
+		User: []Permission{},
+	}),
+		[]authTestCase{
+			// Allowed by the org permissions of the scope.
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate}, allow: true},
+			// The roles allow these, the scope does not.
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionDelete}, allow: false},
+			{resource: ResourceTemplate.InOrg(otherOrg), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	testAuthorize(t, "UserScope", orgAdmin(Role{
+		Identifier: RoleIdentifier{Name: "own_workspaces"},
+		Site:       []Permission{},
+		Org:        map[string][]Permission{},
+		User: Permissions(map[string][]policy.Action{
+			ResourceWorkspace.Type: {policy.ActionRead},
+		}),
+	}),
+		[]authTestCase{
+			// Allowed by the user permissions of the scope.
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionRead}, allow: true},
+			// The roles allow these, the scope does not.
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(meID.String()), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
@@ -1183,7 +1389,11 @@ func testAuthorize(t *testing.T, name string, subject Subject, sets ...[]authTes
 
 					// Ensure the partial can compile to a SQL clause.
 					// This does not guarantee that the clause is valid SQL.
-					_, err = Compile(ConfigWithACL(), partialAuthz)
+					cfg := ConfigWithACL()
+					if c.resource.Type == ResourceWorkspace.Type {
+						cfg = ConfigWorkspaces()
+					}
+					_, err = Compile(cfg, partialAuthz)
 					require.NoError(t, err, "compile prepared authorizer")
 
 					// Also check the rego policy can form a valid partial query result.
@@ -39,6 +39,10 @@ type Object struct {
 
 	ACLUserList  map[string][]policy.Action ` json:"acl_user_list"`
 	ACLGroupList map[string][]policy.Action ` json:"acl_group_list"`
+
+	// TemplateID is the template a workspace was created from. Scopes that
+	// allow a template also allow the workspaces created from it.
+	TemplateID string `json:"template_id"`
 }
 
 // String is not perfect, but decent enough for human display
@@ -111,6 +115,10 @@ func (z Object) Equal(b Object) bool {
 		return false
 	}
 
+	if z.TemplateID != b.TemplateID {
+		return false
+	}
+
 	return true
 }
 
@@ -157,6 +165,7 @@ func (z Object) WithIDString(id string) Object {
 		ACLUserList:  z.ACLUserList,
 		ACLGroupList: z.ACLGroupList,
 		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   z.TemplateID,
 	}
 }
 
@@ -169,6 +178,7 @@ func (z Object) WithID(id uuid.UUID) Object {
 		ACLUserList:  z.ACLUserList,
 		ACLGroupList: z.ACLGroupList,
 		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   z.TemplateID,
 	}
 }
 
@@ -183,6 +193,7 @@ func (z Object) InOrg(orgID uuid.UUID) Object {
 		ACLGroupList: z.ACLGroupList,
 		// InOrg implies AnyOrgOwner is false
 		AnyOrgOwner: false,
+		TemplateID:  z.TemplateID,
 	}
 }
 
@@ -196,6 +207,7 @@ func (z Object) AnyOrganization() Object {
 		ACLUserList:  z.ACLUserList,
 		ACLGroupList: z.ACLGroupList,
 		AnyOrgOwner:  true,
+		TemplateID:   z.TemplateID,
 	}
 }
 
@@ -209,6 +221,7 @@ func (z Object) WithOwner(ownerID string) Object {
 		ACLUserList:  z.ACLUserList,
 		ACLGroupList: z.ACLGroupList,
 		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   z.TemplateID,
 	}
 }
 
@@ -222,6 +235,21 @@ func (z Object) WithACLUserList(acl map[string][]policy.Action) Object {
 		ACLUserList:  acl,
 		ACLGroupList: z.ACLGroupList,
 		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   z.TemplateID,
+	}
+}
+
+// InTemplate adds the template a workspace was created from to the resource.
+func (z Object) InTemplate(templateID uuid.UUID) Object {
+	return Object{
+		ID:           z.ID,
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLUserList:  z.ACLUserList,
+		ACLGroupList: z.ACLGroupList,
+		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   templateID.String(),
 	}
 }
 
@@ -234,5 +262,6 @@ func (z Object) WithGroupACL(groups map[string][]policy.Action) Object {
 		ACLUserList:  z.ACLUserList,
 		ACLGroupList: groups,
 		AnyOrgOwner:  z.AnyOrgOwner,
+		TemplateID:   z.TemplateID,
 	}
 }
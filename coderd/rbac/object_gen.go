@@ -188,6 +188,7 @@ var (
 	//  - "ActionCreate" :: create an OAuth2 app code token
 	//  - "ActionDelete" :: delete an OAuth2 app code token
 	//  - "ActionRead" :: read an OAuth2 app code token
+	//  - "ActionUpdate" :: update an OAuth2 app code token
 	ResourceOauth2AppCodeToken = Object{
 		Type: "oauth2_app_code_token",
 	}
@@ -295,6 +296,16 @@ var (
 		Type: "user",
 	}
 
+	// ResourceUserSecret
+	// Valid Actions
+	//  - "ActionCreate" :: create a user secret
+	//  - "ActionDelete" :: delete a user secret
+	//  - "ActionRead" :: read a user secret
+	//  - "ActionUpdate" :: update a user secret
+	ResourceUserSecret = Object{
+		Type: "user_secret",
+	}
+
 	// ResourceWebpushSubscription
 	// Valid Actions
 	//  - "ActionCreate" :: create webpush subscriptions
@@ -361,6 +372,16 @@ var (
 	ResourceWorkspaceProxy = Object{
 		Type: "workspace_proxy",
 	}
+
+	// ResourceWorkspaceSessionRecording
+	// Valid Actions
+	//  - "ActionCreate" :: create a recording of a workspace session
+	//  - "ActionDelete" :: delete a recording of a workspace session
+	//  - "ActionRead" :: view a recording of a workspace session
+	//  - "ActionUpdate" :: append to a recording of a workspace session
+	ResourceWorkspaceSessionRecording = Object{
+		Type: "workspace_session_recording",
+	}
 )
 
 func AllResources() []Objecter {
@@ -396,12 +417,14 @@ func AllResources() []Objecter {
 		ResourceTailnetCoordinator,
 		ResourceTemplate,
 		ResourceUser,
+		ResourceUserSecret,
 		ResourceWebpushSubscription,
 		ResourceWorkspace,
 		ResourceWorkspaceAgentDevcontainers,
 		ResourceWorkspaceAgentResourceMonitor,
 		ResourceWorkspaceDormant,
 		ResourceWorkspaceProxy,
+		ResourceWorkspaceSessionRecording,
 	}
 }
 
@@ -99,7 +99,7 @@ org := org_allow(input.subject.roles)
 
 default scope_org := 0
 
-scope_org := org_allow([input.scope])
+scope_org := org_allow([input.subject.scope])
 
 # org_allow_set is a helper function that iterates over all orgs that the actor
 # is a member of. For each organization it sets the numerical allow value
@@ -199,7 +199,7 @@ user := user_allow(input.subject.roles)
 
 default user_scope := 0
 
-scope_user := user_allow([input.scope])
+scope_user := user_allow([input.subject.scope])
 
 user_allow(roles) := num if {
 	input.object.owner != ""
@@ -215,6 +215,9 @@ user_allow(roles) := num if {
 
 # Scope allow_list is a list of resource IDs explicitly allowed by the scope.
 # If the list is '*', then all resources are allowed.
+# Elements can also be typed as '<type>:<id>', which only applies to resources
+# of that type. Resources of a type without typed elements are checked against
+# the untyped elements, and are allowed if there are none.
 scope_allow_list if {
 	"*" in input.subject.scope.allow_list
 }
@@ -224,9 +227,62 @@ scope_allow_list if {
 	# object.id. This line is included to prevent partial compilations from
 	# ever needing to include the object.id.
 	not "*" in input.subject.scope.allow_list
-	input.object.id in input.subject.scope.allow_list
+	count(scope_typed_allow_list) == 0
+	input.object.id in scope_untyped_allow_list
 }
 
+scope_allow_list if {
+	"*" in scope_typed_allow_list
+}
+
+scope_allow_list if {
+	not "*" in scope_typed_allow_list
+	input.object.id in scope_typed_allow_list
+}
+
+scope_allow_list if {
+	count(input.subject.scope.allow_list) > 0
+	count(scope_typed_allow_list) == 0
+	count(scope_untyped_allow_list) == 0
+	not scope_template_limited
+}
+
+# Workspaces are also allowed by the template they were created from.
+scope_allow_list if {
+	scope_template_limited
+	input.object.template_id in scope_template_allow_list
+}
+
+# Allowing a template limits workspaces to the ones created from the allowed
+# templates, even when no workspaces are listed.
+scope_template_limited if {
+	input.object.type == "workspace"
+	count(scope_template_allow_list) > 0
+	not "*" in scope_template_allow_list
+}
+
+# scope_typed_allow_list is the list of IDs allowed for the object's type.
+scope_typed_allow_list := [id |
+	element := input.subject.scope.allow_list[_]
+	parts := split(element, ":")
+	count(parts) == 2
+	parts[0] == input.object.type
+	id := parts[1]
+]
+
+scope_template_allow_list := [id |
+	element := input.subject.scope.allow_list[_]
+	parts := split(element, ":")
+	count(parts) == 2
+	parts[0] == "template"
+	id := parts[1]
+]
+
+scope_untyped_allow_list := [element |
+	element := input.subject.scope.allow_list[_]
+	not contains(element, ":")
+]
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
@@ -95,6 +95,14 @@ var RBACPermissions = map[string]PermissionDefinition{
 			ActionUpdatePersonal: actDef("update personal data"),
 		},
 	},
+	"user_secret": {
+		Actions: map[Action]ActionDefinition{
+			ActionCreate: actDef("create a user secret"),
+			ActionRead:   actDef("read a user secret"),
+			ActionUpdate: actDef("update a user secret"),
+			ActionDelete: actDef("delete a user secret"),
+		},
+	},
 	"workspace": {
 		Actions: workspaceActions,
 	},
@@ -279,6 +287,7 @@ var RBACPermissions = map[string]PermissionDefinition{
 		Actions: map[Action]ActionDefinition{
 			ActionCreate: actDef("create an OAuth2 app code token"),
 			ActionRead:   actDef("read an OAuth2 app code token"),
+			ActionUpdate: actDef("update an OAuth2 app code token"),
 			ActionDelete: actDef("delete an OAuth2 app code token"),
 		},
 	},
@@ -343,4 +352,12 @@ var RBACPermissions = map[string]PermissionDefinition{
 			ActionCreate: actDef("create workspace agent devcontainers"),
 		},
 	},
+	"workspace_session_recording": {
+		Actions: map[Action]ActionDefinition{
+			ActionCreate: actDef("create a recording of a workspace session"),
+			ActionRead:   actDef("view a recording of a workspace session"),
+			ActionUpdate: actDef("append to a recording of a workspace session"),
+			ActionDelete: actDef("delete a recording of a workspace session"),
+		},
+	},
 }
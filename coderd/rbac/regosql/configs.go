@@ -72,6 +72,7 @@ func WorkspaceConverter() *sqltypes.VariableConverter {
 		resourceIDMatcher(),
 		sqltypes.StringVarMatcher("workspaces.organization_id :: text", []string{"input", "object", "org_owner"}),
 		userOwnerMatcher(),
+		sqltypes.StringVarMatcher("workspaces.template_id :: text", []string{"input", "object", "template_id"}),
 	)
 	matcher.RegisterMatcher(
 		sqltypes.AlwaysFalse(groupACLMatcher(matcher)),
@@ -97,7 +97,7 @@ var builtinScopes = map[ScopeName]Scope{
 		Role: Role{
 			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", ScopeNoUserData)},
 			DisplayName: "Scope without access to user data",
-			Site:        allPermsExcept(ResourceUser),
+			Site:        allPermsExcept(ResourceUser, ResourceUserSecret),
 			Org:         map[string][]Permission{},
 			User:        []Permission{},
 		},
@@ -105,6 +105,46 @@ var builtinScopes = map[ScopeName]Scope{
 	},
 }
 
+// CustomScopeParams are the permissions of a scope built by CustomScope.
+type CustomScopeParams struct {
+	// Permissions are the actions allowed for each resource type.
+	Permissions map[string][]policy.Action
+	// OrganizationIDs limits the permissions to resources in the
+	// organizations. If empty, the permissions apply to the whole site.
+	OrganizationIDs []uuid.UUID
+	// AllowList limits the resources of a type to the listed IDs. Elements
+	// are formatted as "<type>:<id>". Types that are not listed are not
+	// limited.
+	AllowList []string
+}
+
+// CustomScope returns a scope that only allows the given permissions. Like
+// any scope, it cannot grant anything the roles of the subject do not.
+func CustomScope(params CustomScopeParams) Scope {
+	role := Role{
+		Identifier:  RoleIdentifier{Name: "Scope_custom"},
+		DisplayName: "Custom scope",
+		Site:        []Permission{},
+		Org:         map[string][]Permission{},
+		User:        []Permission{},
+	}
+	if len(params.OrganizationIDs) == 0 {
+		role.Site = Permissions(params.Permissions)
+	}
+	for _, orgID := range params.OrganizationIDs {
+		role.Org[orgID.String()] = Permissions(params.Permissions)
+	}
+
+	allowList := []string{policy.WildcardSymbol}
+	if len(params.AllowList) > 0 {
+		allowList = params.AllowList
+	}
+	return Scope{
+		Role:        role,
+		AllowIDList: allowList,
+	}
+}
+
 type ExpandableScope interface {
 	Expand() (Scope, error)
 	// Name is for logging and tracing purposes, we want to know the human
@@ -124,7 +164,8 @@ func (name ScopeName) Name() RoleIdentifier {
 
 // Scope acts the exact same as a Role with the addition that is can also
 // apply an AllowIDList. Any resource being checked against a Scope will
-// reject any resource that is not in the AllowIDList.
+// reject any resource that is not in the AllowIDList. Elements of the
+// AllowIDList formatted as "<type>:<id>" only apply to resources of that type.
 // To not use an AllowIDList to reject authorization, use a wildcard for the
 // AllowIDList. Eg: 'AllowIDList: []string{WildcardSymbol}'
 type Scope struct {
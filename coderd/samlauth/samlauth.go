@@ -0,0 +1,236 @@
+// Package samlauth contains the parts of a SAML 2.0 service provider that are
+// not provided by github.com/crewjam/saml: endpoint wiring, converting
+// assertions into claims for IdP sync, and validating logout requests sent by
+// the identity provider.
+package samlauth
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MetadataPath serves the service provider metadata.
+	MetadataPath = "/api/v2/users/saml/metadata"
+	// ACSPath is the assertion consumer service that receives assertions
+	// using the HTTP-POST binding.
+	ACSPath = "/api/v2/users/saml/acs"
+	// SLOPath is the single logout service. It accepts logout requests and
+	// responses using the HTTP-Redirect and HTTP-POST bindings.
+	SLOPath = "/api/v2/users/saml/slo"
+
+	// maxMessageSize bounds the size of an inflated logout message.
+	maxMessageSize = 1 << 20
+)
+
+// SetURLs points the service provider endpoints at the given access URL.
+func SetURLs(sp *saml.ServiceProvider, accessURL *url.URL) {
+	sp.MetadataURL = *accessURL.ResolveReference(&url.URL{Path: MetadataPath})
+	sp.AcsURL = *accessURL.ResolveReference(&url.URL{Path: ACSPath})
+	sp.SloURL = *accessURL.ResolveReference(&url.URL{Path: SLOPath})
+	sp.LogoutBindings = []string{saml.HTTPRedirectBinding, saml.HTTPPostBinding}
+}
+
+// NameID returns the subject NameID of the assertion.
+func NameID(assertion *saml.Assertion) string {
+	if assertion.Subject == nil || assertion.Subject.NameID == nil {
+		return ""
+	}
+	return assertion.Subject.NameID.Value
+}
+
+// Issuer returns the entity ID of the identity provider that issued the
+// assertion.
+func Issuer(assertion *saml.Assertion) string {
+	return assertion.Issuer.Value
+}
+
+// Claims converts the attributes of an assertion into a claims map compatible
+// with the IdP sync parsers. Attributes are stored under their name and, if
+// it does not collide, their friendly name. Single valued attributes are
+// stored as strings, multi-valued attributes as slices.
+func Claims(assertion *saml.Assertion) map[string]interface{} {
+	claims := map[string]interface{}{}
+	friendly := map[string]interface{}{}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]interface{}, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+			var value interface{} = values
+			if len(values) == 1 {
+				value = values[0]
+			}
+			claims[attr.Name] = value
+			if attr.FriendlyName != "" {
+				friendly[attr.FriendlyName] = value
+			}
+		}
+	}
+	for name, value := range friendly {
+		if _, ok := claims[name]; !ok {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+// ClaimString returns the first string value of the named claim.
+func ClaimString(claims map[string]interface{}, name string) string {
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// ParseLogoutRequest reads a logout request sent by the identity provider
+// with the HTTP-Redirect or HTTP-POST binding. The request must carry an
+// enveloped signature from one of the identity provider's signing
+// certificates.
+func ParseLogoutRequest(sp *saml.ServiceProvider, r *http.Request) (*saml.LogoutRequest, error) {
+	var (
+		raw []byte
+		err error
+	)
+	if encoded := r.URL.Query().Get("SAMLRequest"); encoded != "" {
+		raw, err = decodeRedirect(encoded)
+	} else {
+		err = r.ParseForm()
+		if err != nil {
+			return nil, xerrors.Errorf("parse form: %w", err)
+		}
+		raw, err = base64.StdEncoding.DecodeString(r.PostForm.Get("SAMLRequest"))
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("decode logout request: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, xerrors.New("logout request is empty")
+	}
+
+	err = xrv.Validate(bytes.NewReader(raw))
+	if err != nil {
+		return nil, xerrors.Errorf("logout request contains invalid xml: %w", err)
+	}
+	doc := etree.NewDocument()
+	err = doc.ReadFromBytes(raw)
+	if err != nil {
+		return nil, xerrors.Errorf("read logout request: %w", err)
+	}
+	if doc.Root() == nil {
+		return nil, xerrors.New("logout request has no root element")
+	}
+	err = verifySignature(sp.IDPMetadata, doc.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	var req saml.LogoutRequest
+	err = xml.Unmarshal(raw, &req)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal logout request: %w", err)
+	}
+
+	now := saml.TimeNow()
+	if req.Issuer == nil || req.Issuer.Value != sp.IDPMetadata.EntityID {
+		return nil, xerrors.Errorf("logout request issuer does not match the identity provider %q", sp.IDPMetadata.EntityID)
+	}
+	if req.Destination != "" && req.Destination != sp.SloURL.String() {
+		return nil, xerrors.Errorf("logout request destination %q does not match %q", req.Destination, sp.SloURL.String())
+	}
+	if req.IssueInstant.Add(saml.MaxIssueDelay).Before(now) {
+		return nil, xerrors.Errorf("logout request expired at %s", req.IssueInstant.Add(saml.MaxIssueDelay).Format(time.RFC3339))
+	}
+	if req.NotOnOrAfter != nil && !now.Before(*req.NotOnOrAfter) {
+		return nil, xerrors.Errorf("logout request expired at %s", req.NotOnOrAfter.Format(time.RFC3339))
+	}
+	if req.NameID == nil || req.NameID.Value == "" {
+		return nil, xerrors.New("logout request does not contain a NameID")
+	}
+	return &req, nil
+}
+
+// decodeRedirect decodes a message sent with the HTTP-Redirect binding.
+func decodeRedirect(encoded string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+	raw, err := io.ReadAll(io.LimitReader(r, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxMessageSize {
+		return nil, xerrors.New("message is too large")
+	}
+	return raw, nil
+}
+
+func verifySignature(idp *saml.EntityDescriptor, el *etree.Element) error {
+	certs, err := signingCertificates(idp)
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return xerrors.New("identity provider metadata does not contain a signing certificate")
+	}
+	vctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: certs,
+	})
+	vctx.IdAttribute = "ID"
+	_, err = vctx.Validate(el)
+	if err != nil {
+		return xerrors.Errorf("validate signature: %w", err)
+	}
+	return nil
+}
+
+// signingCertificates returns the certificates the identity provider signs
+// messages with.
+func signingCertificates(idp *saml.EntityDescriptor) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, desc := range idp.IDPSSODescriptors {
+		for _, kd := range desc.KeyDescriptors {
+			if kd.Use != "" && kd.Use != "signing" {
+				continue
+			}
+			for _, xc := range kd.KeyInfo.X509Data.X509Certificates {
+				data := strings.Join(strings.Fields(xc.Data), "")
+				der, err := base64.StdEncoding.DecodeString(data)
+				if err != nil {
+					return nil, xerrors.Errorf("decode identity provider certificate: %w", err)
+				}
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					return nil, xerrors.Errorf("parse identity provider certificate: %w", err)
+				}
+				certs = append(certs, cert)
+			}
+		}
+	}
+	return certs, nil
+}
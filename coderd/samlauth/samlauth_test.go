@@ -0,0 +1,95 @@
+package samlauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest/samltest"
+	"github.com/coder/coder/v2/coderd/samlauth"
+)
+
+func TestClaims(t *testing.T) {
+	t.Parallel()
+
+	assertion := &saml.Assertion{
+		AttributeStatements: []saml.AttributeStatement{{
+			Attributes: []saml.Attribute{
+				{
+					Name:         "urn:oid:0.9.2342.19200300.100.1.3",
+					FriendlyName: "mail",
+					Values:       []saml.AttributeValue{{Value: "alice@coder.com"}},
+				},
+				{
+					Name:   "groups",
+					Values: []saml.AttributeValue{{Value: "engineering"}, {Value: "admins"}},
+				},
+				{
+					Name:         "username",
+					FriendlyName: "groups",
+					Values:       []saml.AttributeValue{{Value: "alice"}},
+				},
+			},
+		}},
+	}
+
+	claims := samlauth.Claims(assertion)
+	require.Equal(t, map[string]interface{}{
+		"urn:oid:0.9.2342.19200300.100.1.3": "alice@coder.com",
+		"mail":                              "alice@coder.com",
+		"groups":                            []interface{}{"engineering", "admins"},
+		"username":                          "alice",
+	}, claims)
+	require.Equal(t, "alice@coder.com", samlauth.ClaimString(claims, "mail"))
+	require.Equal(t, "engineering", samlauth.ClaimString(claims, "groups"))
+	require.Empty(t, samlauth.ClaimString(claims, "missing"))
+}
+
+func TestParseLogoutRequest(t *testing.T) {
+	t.Parallel()
+
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	idp := samltest.NewFakeIDP(t)
+	sp := idp.SAMLConfig(t, nil).ServiceProvider
+	samlauth.SetURLs(sp, accessURL)
+
+	post := func(form url.Values) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, samlauth.SLOPath, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := samlauth.ParseLogoutRequest(sp, post(idp.LogoutRequestForm(t, "alice@coder.com")))
+		require.NoError(t, err)
+		require.Equal(t, "alice@coder.com", req.NameID.Value)
+		require.Equal(t, idp.EntityID(), req.Issuer.Value)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := samlauth.ParseLogoutRequest(sp, post(url.Values{}))
+		require.Error(t, err)
+	})
+
+	t.Run("OtherIdentityProvider", func(t *testing.T) {
+		t.Parallel()
+
+		other := samltest.NewFakeIDP(t)
+		otherSP := other.SAMLConfig(t, nil).ServiceProvider
+		samlauth.SetURLs(otherSP, accessURL)
+
+		_, err := samlauth.ParseLogoutRequest(sp, post(other.LogoutRequestForm(t, "alice@coder.com")))
+		require.ErrorContains(t, err, "validate signature")
+	})
+}
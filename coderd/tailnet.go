@@ -545,8 +545,11 @@ type Pinger interface {
 type InmemTailnetDialer struct {
 	CoordPtr *atomic.Pointer[tailnet.Coordinator]
 	DERPFn   func() *tailcfg.DERPMap
-	Logger   slog.Logger
-	ClientID uuid.UUID
+	// DERPQUICPortsFn is optional, and returns the QUIC ports of the DERP
+	// nodes in the map returned by DERPFn.
+	DERPQUICPortsFn func() tailnet.DERPQUICPorts
+	Logger          slog.Logger
+	ClientID        uuid.UUID
 	// DatabaseHealthCheck is used to validate that the store is reachable.
 	DatabaseHealthCheck Pinger
 }
@@ -564,7 +567,7 @@ func (a *InmemTailnetDialer) Dial(ctx context.Context, _ tailnet.ResumeTokenCont
 	}
 	coordClient := tailnet.NewInMemoryCoordinatorClient(
 		a.Logger, a.ClientID, tailnet.SingleTailnetCoordinateeAuth{}, *coord)
-	derpClient := newPollingDERPClient(a.DERPFn, a.Logger)
+	derpClient := newPollingDERPClient(a.DERPFn, a.DERPQUICPortsFn, a.Logger)
 	return tailnet.ControlProtocolClients{
 		Closer:      closeAll{coord: coordClient, derp: derpClient},
 		Coordinator: coordClient,
@@ -572,14 +575,15 @@ func (a *InmemTailnetDialer) Dial(ctx context.Context, _ tailnet.ResumeTokenCont
 	}, nil
 }
 
-func newPollingDERPClient(derpFn func() *tailcfg.DERPMap, logger slog.Logger) tailnet.DERPClient {
+func newPollingDERPClient(derpFn func() *tailcfg.DERPMap, portsFn func() tailnet.DERPQUICPorts, logger slog.Logger) tailnet.DERPClient {
 	ctx, cancel := context.WithCancel(context.Background())
 	a := &pollingDERPClient{
 		fn:       derpFn,
+		portsFn:  portsFn,
 		ctx:      ctx,
 		cancel:   cancel,
 		logger:   logger,
-		ch:       make(chan *tailcfg.DERPMap),
+		ch:       make(chan pollingDERPUpdate),
 		loopDone: make(chan struct{}),
 	}
 	go a.pollDERP()
@@ -590,12 +594,19 @@ func newPollingDERPClient(derpFn func() *tailcfg.DERPMap, logger slog.Logger) ta
 // interval
 type pollingDERPClient struct {
 	fn          func() *tailcfg.DERPMap
+	portsFn     func() tailnet.DERPQUICPorts
 	logger      slog.Logger
 	ctx         context.Context
 	cancel      context.CancelFunc
 	loopDone    chan struct{}
 	lastDERPMap *tailcfg.DERPMap
-	ch          chan *tailcfg.DERPMap
+	lastPorts   tailnet.DERPQUICPorts
+	ch          chan pollingDERPUpdate
+}
+
+type pollingDERPUpdate struct {
+	derpMap *tailcfg.DERPMap
+	ports   tailnet.DERPQUICPorts
 }
 
 // Close the DERP client
@@ -605,12 +616,12 @@ func (a *pollingDERPClient) Close() error {
 	return nil
 }
 
-func (a *pollingDERPClient) Recv() (*tailcfg.DERPMap, error) {
+func (a *pollingDERPClient) Recv() (*tailcfg.DERPMap, tailnet.DERPQUICPorts, error) {
 	select {
 	case <-a.ctx.Done():
-		return nil, a.ctx.Err()
-	case dm := <-a.ch:
-		return dm, nil
+		return nil, nil, a.ctx.Err()
+	case update := <-a.ch:
+		return update.derpMap, update.ports, nil
 	}
 }
 
@@ -629,12 +640,17 @@ func (a *pollingDERPClient) pollDERP() {
 		}
 
 		newDerpMap := a.fn()
-		if !tailnet.CompareDERPMaps(a.lastDERPMap, newDerpMap) {
+		var newPorts tailnet.DERPQUICPorts
+		if a.portsFn != nil {
+			newPorts = a.portsFn()
+		}
+		if !tailnet.CompareDERPMaps(a.lastDERPMap, newDerpMap) || !a.lastPorts.Equal(newPorts) {
 			select {
 			case <-a.ctx.Done():
 				return
-			case a.ch <- newDerpMap:
+			case a.ch <- pollingDERPUpdate{derpMap: newDerpMap, ports: newPorts}:
 			}
+			a.lastPorts = newPorts
 		}
 	}
 }
@@ -733,6 +733,10 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.UseClassicParameterFlow != nil {
 		classicTemplateFlow = *req.UseClassicParameterFlow
 	}
+	sessionRecordingEnabled := template.SessionRecordingEnabled
+	if req.SessionRecordingEnabled != nil {
+		sessionRecordingEnabled = *req.SessionRecordingEnabled
+	}
 
 	var updated database.Template
 	err = api.Database.InTx(func(tx database.Store) error {
@@ -754,6 +758,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
 			(classicTemplateFlow == template.UseClassicParameterFlow) &&
+			(sessionRecordingEnabled == template.SessionRecordingEnabled) &&
 			maxPortShareLevel == template.MaxPortSharingLevel {
 			return nil
 		}
@@ -796,6 +801,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			GroupACL:                     groupACL,
 			MaxPortSharingLevel:          maxPortShareLevel,
 			UseClassicParameterFlow:      classicTemplateFlow,
+			SessionRecordingEnabled:      sessionRecordingEnabled,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -1079,6 +1085,7 @@ func (api *API) convertTemplate(
 		DeprecationMessage:      templateAccessControl.Deprecated,
 		MaxPortShareLevel:       maxPortShareLevel,
 		UseClassicParameterFlow: template.UseClassicParameterFlow,
+		SessionRecordingEnabled: template.SessionRecordingEnabled,
 	}
 }
 
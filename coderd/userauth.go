@@ -3,10 +3,12 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/mail"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +17,7 @@ import (
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/crewjam/saml"
 	"github.com/go-jose/go-jose/v4"
 	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/google/go-github/v43/github"
@@ -28,6 +31,8 @@ import (
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/coderd/ldapauth"
+	"github.com/coder/coder/v2/coderd/samlauth"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 
@@ -529,6 +534,9 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		// user failed to login
 		return
 	}
+	if !api.loginMFA(ctx, rw, user, loginWithPassword.MFACode, loginWithPassword.WebAuthnCredential) {
+		return
+	}
 
 	//nolint:gocritic // Creating the API key as the user instead of as system.
 	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
@@ -803,6 +811,15 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 	if api.OIDCConfig != nil {
 		iconURL = api.OIDCConfig.IconURL
 	}
+	var ldapSignInText string
+	if api.LDAPConfig != nil {
+		ldapSignInText = api.LDAPConfig.SignInText
+	}
+	var samlSignInText, samlIconURL string
+	if api.SAMLConfig != nil {
+		samlSignInText = api.SAMLConfig.SignInText
+		samlIconURL = api.SAMLConfig.IconURL
+	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		TermsOfServiceURL: api.DeploymentValues.TermsOfServiceURL.Value(),
@@ -818,6 +835,18 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		LDAP: codersdk.LDAPAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: api.LDAPConfig != nil},
+			SignInText: ldapSignInText,
+		},
+		SAML: codersdk.SAMLAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: api.SAMLConfig != nil},
+			SignInText: samlSignInText,
+			IconURL:    samlIconURL,
+		},
+		Passkey: codersdk.AuthMethod{
+			Enabled: api.WebAuthn != nil && !api.DeploymentValues.DisablePasswordAuth.Value(),
+		},
 	})
 }
 
@@ -1354,30 +1383,11 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		username = codersdk.UsernameFrom(username)
 	}
 
-	if len(api.OIDCConfig.EmailDomain) > 0 {
-		ok = false
-		emailSp := strings.Split(email, "@")
-		if len(emailSp) == 1 {
-			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
-			})
-			return
-		}
-		userEmailDomain := emailSp[len(emailSp)-1]
-		for _, domain := range api.OIDCConfig.EmailDomain {
-			// Folks sometimes enter EmailDomain with a leading '@'.
-			domain = strings.TrimPrefix(domain, "@")
-			if strings.EqualFold(userEmailDomain, domain) {
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
-			})
-			return
-		}
+	if !emailDomainAllowed(email, api.OIDCConfig.EmailDomain) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
+		})
+		return
 	}
 
 	// The 'name' is an optional property in Coder. If not specified,
@@ -1481,6 +1491,745 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
+// LDAPConfig configures login with an LDAP or Active Directory server.
+type LDAPConfig struct {
+	ldapauth.Config
+
+	AllowSignups bool
+	// EmailDomain are the domains to enforce when a user authenticates.
+	EmailDomain []string
+	// SignInText is the text to display on the LDAP login button.
+	SignInText string
+}
+
+// Authenticates the user with a username and password verified against the
+// configured LDAP directory. Users are created on their first login.
+//
+// @Summary Log in user with LDAP
+// @ID log-in-user-with-ldap
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithLDAPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/ldap/login [post]
+func (api *API) postLoginLDAP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// postLoginLDAP is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.LDAPConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "LDAP authentication is not enabled.",
+		})
+		return
+	}
+
+	var req codersdk.LoginWithLDAPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	entry, err := api.LDAPConfig.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, ldapauth.ErrInvalidCredentials) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Incorrect username or password.",
+			})
+			return
+		}
+		logger.Error(ctx, "ldap: unable to authenticate", slog.F("username", req.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authenticate with LDAP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	email := entry.Email
+	if _, err := mail.ParseAddress(email); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("No valid email found in the %q attribute of your LDAP entry. Please contact your administrator.", api.LDAPConfig.EmailAttribute),
+		})
+		return
+	}
+
+	if !emailDomainAllowed(email, api.LDAPConfig.EmailDomain) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
+		})
+		return
+	}
+
+	username := entry.Username
+	if codersdk.NameValid(username) != nil {
+		username = codersdk.UsernameFrom(username)
+	}
+	name := codersdk.NormalizeRealUsername(entry.Name)
+	claims := entry.Claims()
+
+	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username), slog.F("name", name))
+	logger.Debug(ctx, "got ldap entry",
+		slog.F("dn", entry.DN),
+		slog.F("claim_fields", claimFields(claims)),
+	)
+
+	linkedID := ldapLinkedID(api.LDAPConfig, entry)
+	user, link, err := findLinkedUser(ctx, api.Database, linkedID, email)
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to find linked user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// LDAP attributes and resolved groups are synced with the same claim
+	// based settings used for OIDC.
+	orgSync, orgSyncErr := api.IDPSync.ParseOrganizationClaims(ctx, claims)
+	if orgSyncErr != nil {
+		writeJSONHTTPError(rw, r, orgSyncErr)
+		return
+	}
+
+	groupSync, groupSyncErr := api.IDPSync.ParseGroupClaims(ctx, claims)
+	if groupSyncErr != nil {
+		writeJSONHTTPError(rw, r, groupSyncErr)
+		return
+	}
+
+	roleSync, roleSyncErr := api.IDPSync.ParseRoleClaims(ctx, claims)
+	if roleSyncErr != nil {
+		writeJSONHTTPError(rw, r, roleSyncErr)
+		return
+	}
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		// LDAP has no upstream tokens to store on the user link.
+		State:            httpmw.OAuth2State{Token: &oauth2.Token{}},
+		LinkedID:         linkedID,
+		LoginType:        database.LoginTypeLDAP,
+		AllowSignups:     api.LDAPConfig.AllowSignups,
+		Email:            email,
+		Username:         username,
+		Name:             name,
+		AvatarURL:        user.AvatarURL,
+		OrganizationSync: orgSync,
+		GroupSync:        groupSync,
+		RoleSync:         roleSync,
+		UserClaims: database.UserLinkClaims{
+			MergedClaims: claims,
+		},
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, user, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	if err != nil {
+		if hErr := idpsync.IsHTTPError(err); hErr != nil {
+			writeJSONHTTPError(rw, r, hErr)
+			return
+		}
+		logger.Error(ctx, "ldap: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	var sessionToken string
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			sessionToken = cookie.Value
+		}
+		http.SetCookie(rw, cookie)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: sessionToken,
+	})
+}
+
+// SAMLConfig configures login with a SAML 2.0 identity provider.
+type SAMLConfig struct {
+	*saml.ServiceProvider
+
+	AllowSignups bool
+	// EmailDomain are the domains to enforce when a user authenticates.
+	EmailDomain []string
+	// EmailAttribute, UsernameAttribute and NameAttribute select the
+	// assertion attributes used for the Coder email, username and name.
+	EmailAttribute    string
+	UsernameAttribute string
+	NameAttribute     string
+	// SignInText is the text to display on the SAML login button.
+	SignInText string
+	// IconURL points to a user-facing icon to display on the login page.
+	IconURL string
+}
+
+// Returns the SAML service provider metadata for configuring the identity
+// provider.
+//
+// @Summary Get SAML service provider metadata
+// @ID get-saml-service-provider-metadata
+// @Security CoderSessionToken
+// @Tags Users
+// @Success 200
+// @Router /users/saml/metadata [get]
+func (api *API) samlMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "SAML authentication is not enabled.",
+		})
+		return
+	}
+
+	data, err := xml.MarshalIndent(api.SAMLConfig.Metadata(), "", "  ")
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.Header().Set("Content-Type", "application/samlmetadata+xml")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(data)
+}
+
+// Redirects the user to the identity provider to authenticate. The redirect
+// query parameter is where the user is sent after logging in.
+//
+// @Summary SAML login
+// @ID saml-login
+// @Security CoderSessionToken
+// @Tags Users
+// @Param redirect query string false "Path to redirect to after login"
+// @Success 307
+// @Router /users/saml/login [get]
+func (api *API) userSAMLLogin(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML authentication is not enabled.",
+		})
+		return
+	}
+
+	sp := api.SAMLConfig.ServiceProvider
+	authReq, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating SAML authentication request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The relay state is returned by the identity provider and is used as
+	// the redirect after login. It's sanitized again in the ACS.
+	redirect := uriFromURL(r.URL.Query().Get("redirect"))
+	redirectURL, err := authReq.Redirect(redirect, sp)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating SAML authentication request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	http.SetCookie(rw, api.samlRequestCookie(authReq.ID, 0))
+	http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+}
+
+// samlRequestCookie tracks the ID of a pending authentication request. The
+// assertion is posted cross-site by the identity provider, so the cookie must
+// be sent with SameSite=None whenever the browser allows it.
+func (api *API) samlRequestCookie(requestID string, maxAge int) *http.Cookie {
+	cookie := api.DeploymentValues.HTTPCookies.Apply(&http.Cookie{
+		Name:     codersdk.SAMLRequestCookie,
+		Value:    requestID,
+		Path:     path.Dir(samlauth.ACSPath),
+		MaxAge:   maxAge,
+		HttpOnly: true,
+	})
+	if cookie.Secure {
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	return cookie
+}
+
+// Receives the assertion from the identity provider and logs the user in.
+// Users are created on their first login.
+//
+// @Summary SAML assertion consumer service
+// @ID saml-assertion-consumer-service
+// @Security CoderSessionToken
+// @Tags Users
+// @Param SAMLResponse formData string true "SAML response"
+// @Param RelayState formData string false "Relay state"
+// @Success 303
+// @Router /users/saml/acs [post]
+func (api *API) userSAMLACS(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// userSAMLACS is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML authentication is not enabled.",
+		})
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid form.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var possibleRequestIDs []string
+	if cookie, err := r.Cookie(codersdk.SAMLRequestCookie); err == nil && cookie.Value != "" {
+		possibleRequestIDs = append(possibleRequestIDs, cookie.Value)
+	}
+	// The request ID can only be used once.
+	http.SetCookie(rw, api.samlRequestCookie("", -1))
+
+	assertion, err := api.SAMLConfig.ParseResponse(r, possibleRequestIDs)
+	if err != nil {
+		detail := err.Error()
+		var invalidErr *saml.InvalidResponseError
+		if errors.As(err, &invalidErr) && invalidErr.PrivateErr != nil {
+			detail = invalidErr.PrivateErr.Error()
+		}
+		logger.Warn(ctx, "saml: invalid response", slog.F("detail", detail))
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Failed to validate the SAML response.",
+			Detail:  detail,
+		})
+		return
+	}
+
+	nameID := samlauth.NameID(assertion)
+	if nameID == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The SAML assertion does not contain a subject NameID.",
+		})
+		return
+	}
+	claims := samlauth.Claims(assertion)
+
+	email := samlauth.ClaimString(claims, api.SAMLConfig.EmailAttribute)
+	if email == "" && assertion.Subject.NameID.Format == string(saml.EmailAddressNameIDFormat) {
+		email = nameID
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("No valid email found in the %q attribute of your SAML assertion. Please contact your administrator.", api.SAMLConfig.EmailAttribute),
+		})
+		return
+	}
+
+	if !emailDomainAllowed(email, api.SAMLConfig.EmailDomain) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
+		})
+		return
+	}
+
+	username := samlauth.ClaimString(claims, api.SAMLConfig.UsernameAttribute)
+	if username == "" {
+		username, _, _ = strings.Cut(email, "@")
+	}
+	if codersdk.NameValid(username) != nil {
+		username = codersdk.UsernameFrom(username)
+	}
+	name := codersdk.NormalizeRealUsername(samlauth.ClaimString(claims, api.SAMLConfig.NameAttribute))
+
+	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username), slog.F("name", name))
+	logger.Debug(ctx, "got saml assertion",
+		slog.F("issuer", samlauth.Issuer(assertion)),
+		slog.F("claim_fields", claimFields(claims)),
+	)
+
+	linkedID := samlLinkedID(assertion)
+	user, link, err := findLinkedUser(ctx, api.Database, linkedID, email)
+	if err != nil {
+		logger.Error(ctx, "saml: unable to find linked user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Assertion attributes are synced with the same claim based settings
+	// used for OIDC.
+	orgSync, orgSyncErr := api.IDPSync.ParseOrganizationClaims(ctx, claims)
+	if orgSyncErr != nil {
+		orgSyncErr.Write(rw, r)
+		return
+	}
+
+	groupSync, groupSyncErr := api.IDPSync.ParseGroupClaims(ctx, claims)
+	if groupSyncErr != nil {
+		groupSyncErr.Write(rw, r)
+		return
+	}
+
+	roleSync, roleSyncErr := api.IDPSync.ParseRoleClaims(ctx, claims)
+	if roleSyncErr != nil {
+		roleSyncErr.Write(rw, r)
+		return
+	}
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		// SAML has no upstream tokens to store on the user link.
+		State:            httpmw.OAuth2State{Token: &oauth2.Token{}},
+		LinkedID:         linkedID,
+		LoginType:        database.LoginTypeSAML,
+		AllowSignups:     api.SAMLConfig.AllowSignups,
+		Email:            email,
+		Username:         username,
+		Name:             name,
+		AvatarURL:        user.AvatarURL,
+		OrganizationSync: orgSync,
+		GroupSync:        groupSync,
+		RoleSync:         roleSync,
+		UserClaims: database.UserLinkClaims{
+			MergedClaims: claims,
+		},
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, user, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	if err != nil {
+		if hErr := idpsync.IsHTTPError(err); hErr != nil {
+			hErr.Write(rw, r)
+			return
+		}
+		logger.Error(ctx, "saml: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process SAML login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	for i := range cookies {
+		http.SetCookie(rw, cookies[i])
+	}
+
+	// Strip the host if it exists on the relay state to prevent any
+	// nefarious redirects. The assertion was posted, so redirect with a GET.
+	redirect := uriFromURL(r.PostForm.Get("RelayState"))
+	http.Redirect(rw, r, redirect, http.StatusSeeOther)
+}
+
+// Handles single logout messages from the identity provider. Logout requests
+// revoke the SAML sessions of the user, logout responses complete a logout
+// started by Coder.
+//
+// @Summary SAML single logout service
+// @ID saml-single-logout-service
+// @Security CoderSessionToken
+// @Tags Users
+// @Param SAMLRequest query string false "SAML logout request"
+// @Param SAMLResponse query string false "SAML logout response"
+// @Param RelayState query string false "Relay state"
+// @Success 303
+// @Router /users/saml/slo [get]
+func (api *API) userSAMLSLO(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// userSAMLSLO is a system function.
+		//nolint:gocritic
+		ctx    = dbauthz.AsSystemRestricted(r.Context())
+		logger = api.Logger.Named(userAuthLoggerName)
+	)
+
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML authentication is not enabled.",
+		})
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid form.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	sp := api.SAMLConfig.ServiceProvider
+
+	switch {
+	case r.Form.Get("SAMLResponse") != "":
+		err := sp.ValidateLogoutResponseRequest(r)
+		if err != nil {
+			logger.Warn(ctx, "saml: invalid logout response", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to validate the SAML logout response.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		http.Redirect(rw, r, "/login", http.StatusSeeOther)
+
+	case r.Form.Get("SAMLRequest") != "":
+		req, err := samlauth.ParseLogoutRequest(sp, r)
+		if err != nil {
+			logger.Warn(ctx, "saml: invalid logout request", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to validate the SAML logout request.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		link, err := api.Database.GetUserLinkByLinkedID(ctx, strings.Join([]string{req.Issuer.Value, req.NameID.Value}, "||"))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// The user never logged in, so there is nothing to revoke.
+		case err != nil:
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user link.",
+				Detail:  err.Error(),
+			})
+			return
+		default:
+			err = api.revokeLoginTypeAPIKeys(ctx, link.UserID, database.LoginTypeSAML)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error revoking sessions.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+
+		relayState := r.Form.Get("RelayState")
+		if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) != "" {
+			u, err := sp.MakeRedirectLogoutResponse(req.ID, relayState)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error creating SAML logout response.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			http.Redirect(rw, r, u.String(), http.StatusSeeOther)
+			return
+		}
+		form, err := sp.MakePostLogoutResponse(req.ID, relayState)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating SAML logout response.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		writeSAMLForm(rw, form)
+
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Either a SAMLRequest or SAMLResponse must be provided.",
+		})
+	}
+}
+
+// @Summary SAML single logout service with POST binding
+// @ID saml-single-logout-service-with-post-binding
+// @Security CoderSessionToken
+// @Tags Users
+// @Param SAMLRequest formData string false "SAML logout request"
+// @Param SAMLResponse formData string false "SAML logout response"
+// @Param RelayState formData string false "Relay state"
+// @Success 303
+// @Router /users/saml/slo [post]
+func (api *API) postUserSAMLSLO(rw http.ResponseWriter, r *http.Request) {
+	api.userSAMLSLO(rw, r)
+}
+
+// Logs out the current session and, if the identity provider supports single
+// logout, sends the user to the identity provider to end their session there
+// too.
+//
+// @Summary SAML logout
+// @ID saml-logout
+// @Security CoderSessionToken
+// @Tags Users
+// @Success 303
+// @Router /users/saml/logout [get]
+func (api *API) userSAMLLogout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogout,
+		})
+	)
+	aReq.Old = apiKey
+	defer commitAudit()
+
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML authentication is not enabled.",
+		})
+		return
+	}
+
+	//nolint:gocritic // The user link is read by the system.
+	link, err := api.Database.GetUserLinkByUserIDLoginType(dbauthz.AsSystemRestricted(ctx), database.GetUserLinkByUserIDLoginTypeParams{
+		UserID:    apiKey.UserID,
+		LoginType: database.LoginTypeSAML,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user link.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		MaxAge: -1,
+		Name:   codersdk.SessionTokenCookie,
+		Path:   "/",
+	})
+	err = api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to delete API key", slog.F("api_key", apiKey.ID), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = database.APIKey{}
+
+	sp := api.SAMLConfig.ServiceProvider
+	issuer, nameID, ok := strings.Cut(link.LinkedID, "||")
+	if !ok || issuer != sp.IDPMetadata.EntityID {
+		// The user didn't log in with this identity provider, so there is
+		// no session to end.
+		http.Redirect(rw, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) != "" {
+		u, err := sp.MakeRedirectLogoutRequest(nameID, "")
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating SAML logout request.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		http.Redirect(rw, r, u.String(), http.StatusSeeOther)
+		return
+	}
+	if sp.GetSLOBindingLocation(saml.HTTPPostBinding) != "" {
+		form, err := sp.MakePostLogoutRequest(nameID, "")
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating SAML logout request.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		writeSAMLForm(rw, form)
+		return
+	}
+	http.Redirect(rw, r, "/login", http.StatusSeeOther)
+}
+
+// revokeLoginTypeAPIKeys deletes the API keys of a user that were created by
+// logging in with the given login type.
+func (api *API) revokeLoginTypeAPIKeys(ctx context.Context, userID uuid.UUID, loginType database.LoginType) error {
+	keys, err := api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+		LoginType: loginType,
+		UserID:    userID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get api keys: %w", err)
+	}
+	for _, key := range keys {
+		err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+		if err != nil {
+			return xerrors.Errorf("delete api key %q: %w", key.ID, err)
+		}
+	}
+	return nil
+}
+
+// writeSAMLForm writes an auto-submitting form that sends a SAML message with
+// the HTTP-POST binding.
+func writeSAMLForm(rw http.ResponseWriter, form []byte) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The form submits itself with an inline script.
+	rw.Header().Set("Content-Security-Policy", "default-src 'none'; script-src 'unsafe-inline'; form-action *")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write([]byte("<!DOCTYPE html><html><body>"))
+	_, _ = rw.Write(form)
+	_, _ = rw.Write([]byte("</body></html>"))
+}
+
 func (api *API) accessTokenClaims(ctx context.Context, rw http.ResponseWriter, state httpmw.OAuth2State, logger slog.Logger) (accessTokenClaims map[string]interface{}, ok bool) {
 	// Assume the access token is a jwt, and signed by the provider.
 	accessToken, err := api.OIDCConfig.Verifier.Verify(ctx, state.Token.AccessToken)
@@ -2050,6 +2799,71 @@ func oidcLinkedID(tok *oidc.IDToken) string {
 	return strings.Join([]string{tok.Issuer, tok.Subject}, "||")
 }
 
+// ldapLinkedID returns the unique ID for an LDAP user. The server URL is
+// included so entries from different directories never collide.
+func ldapLinkedID(cfg *LDAPConfig, entry ldapauth.Entry) string {
+	return strings.Join([]string{cfg.URL, entry.ID}, "||")
+}
+
+// samlLinkedID returns the unique ID for a SAML user.
+func samlLinkedID(assertion *saml.Assertion) string {
+	return strings.Join([]string{samlauth.Issuer(assertion), samlauth.NameID(assertion)}, "||")
+}
+
+// idpSyncedLoginType returns true if organizations, groups and roles of users
+// with the login type are managed by IdP sync.
+func idpSyncedLoginType(loginType database.LoginType) bool {
+	switch loginType {
+	case database.LoginTypeOIDC, database.LoginTypeLDAP, database.LoginTypeSAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// idpSyncedLoginTypeName is the name of an IdP synced login type used in
+// error messages.
+func idpSyncedLoginTypeName(loginType database.LoginType) string {
+	switch loginType {
+	case database.LoginTypeLDAP:
+		return "LDAP"
+	case database.LoginTypeSAML:
+		return "SAML"
+	default:
+		return "OIDC"
+	}
+}
+
+// emailDomainAllowed returns true if the domain of the email is one of the
+// allowed domains. All domains are allowed if none are provided.
+func emailDomainAllowed(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	emailSp := strings.Split(email, "@")
+	if len(emailSp) == 1 {
+		return false
+	}
+	userEmailDomain := emailSp[len(emailSp)-1]
+	for _, domain := range domains {
+		// Folks sometimes enter EmailDomain with a leading '@'.
+		domain = strings.TrimPrefix(domain, "@")
+		if strings.EqualFold(userEmailDomain, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSONHTTPError writes an IdP sync error as JSON. Endpoints called by API
+// clients rather than browser redirects must never render the static error
+// page.
+func writeJSONHTTPError(rw http.ResponseWriter, r *http.Request, err *idpsync.HTTPError) {
+	jsonErr := *err
+	jsonErr.RenderStaticPage = false
+	jsonErr.Write(rw, r)
+}
+
 // findLinkedUser tries to find a user by their unique OAuth-linked ID.
 // If it doesn't not find it, it returns the user by their email.
 func findLinkedUser(ctx context.Context, db database.Store, linkedID string, emails ...string) (database.User, database.UserLink, error) {
@@ -34,6 +34,7 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
+	"github.com/coder/coder/v2/coderd/coderdtest/samltest"
 	"github.com/coder/coder/v2/coderd/coderdtest/testjar"
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/database"
@@ -41,6 +42,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/coderd/ldapauth/ldapauthtest"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/promoauth"
@@ -1898,6 +1900,372 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	alice := ldapauthtest.User{
+		Username: "alice",
+		Password: "alice-password",
+		Email:    "alice@coder.com",
+		Name:     "Alice Liddell",
+		Groups:   []string{"engineering"},
+	}
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+
+		dir := ldapauthtest.Start(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Config:       dir.Config(),
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.LDAP.Enabled)
+
+		anon := codersdk.New(client.URL)
+		res, err := anon.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: alice.Password,
+		})
+		require.NoError(t, err)
+		anon.SetSessionToken(res.SessionToken)
+
+		user, err := anon.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, alice.Email, user.Email)
+		require.Equal(t, alice.Name, user.Name)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+
+		// Logging in again must link to the same user.
+		res, err = anon.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: alice.Password,
+		})
+		require.NoError(t, err)
+		anon.SetSessionToken(res.SessionToken)
+		again, err := anon.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+
+		dir := ldapauthtest.Start(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Config:       dir.Config(),
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: "wrong",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("SignupsDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		dir := ldapauthtest.Start(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Config: dir.Config(),
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: alice.Password,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("EmailDomain", func(t *testing.T) {
+		t.Parallel()
+
+		dir := ldapauthtest.Start(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Config:       dir.Config(),
+				AllowSignups: true,
+				EmailDomain:  []string{"example.com"},
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: alice.Password,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.False(t, methods.LDAP.Enabled)
+
+		_, err = codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: alice.Username,
+			Password: alice.Password,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestUserSAML(t *testing.T) {
+	t.Parallel()
+
+	alice := samltest.User{
+		NameID: "alice@coder.com",
+		Attributes: map[string][]string{
+			"email":    {"alice@coder.com"},
+			"username": {"alice"},
+			"name":     {"Alice Liddell"},
+		},
+	}
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+			}),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.SAML.Enabled)
+		require.Equal(t, "SAML", methods.SAML.SignInText)
+
+		userClient, _ := idp.Login(t, client, alice)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@coder.com", user.Email)
+		require.Equal(t, "Alice Liddell", user.Name)
+		require.Equal(t, codersdk.LoginTypeSAML, user.LoginType)
+
+		// Logging in again must link to the same user.
+		userClient, _ = idp.Login(t, client, alice)
+		again, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("NameIDFallback", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+			}),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		userClient, _ := idp.Login(t, client, samltest.User{
+			NameID: "bob@coder.com",
+		})
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "bob", user.Username)
+		require.Equal(t, "bob@coder.com", user.Email)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, nil),
+		})
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/users/saml/metadata", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), client.URL.JoinPath("/api/v2/users/saml/acs").String())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.False(t, methods.SAML.Enabled)
+
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/users/saml/metadata", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("SignupsDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, nil),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		userClient, res := idp.AttemptLogin(t, client, alice)
+		require.Nil(t, userClient)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("EmailDomain", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+				cfg.EmailDomain = []string{"example.com"}
+			}),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		userClient, res := idp.AttemptLogin(t, client, alice)
+		require.Nil(t, userClient)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("IDPInitiatedLogout", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+			}),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		userClient, _ := idp.Login(t, client, alice)
+		_, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		form := idp.LogoutRequestForm(t, alice.NameID)
+		cli := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		res, err := cli.PostForm(client.URL.JoinPath("/api/v2/users/saml/slo").String(), form)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+		location, err := res.Location()
+		require.NoError(t, err)
+		require.NotEmpty(t, location.Query().Get("SAMLResponse"))
+
+		_, err = userClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidLogoutRequest", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, nil),
+		})
+
+		// A request signed by another identity provider must be rejected.
+		other := samltest.NewFakeIDP(t)
+		_ = other.SAMLConfig(t, nil)
+		form := other.LogoutRequestForm(t, alice.NameID)
+		res, err := http.PostForm(client.URL.JoinPath("/api/v2/users/saml/slo").String(), form)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		t.Parallel()
+
+		idp := samltest.NewFakeIDP(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+			}),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		userClient, _ := idp.Login(t, client, alice)
+
+		// The logout redirects to the identity provider, which responds
+		// to coderd with a logout response.
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		cli := &http.Client{
+			Jar: jar,
+			CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+				if req.URL.Path == "/login" {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL.JoinPath("/api/v2/users/saml/logout").String(), nil)
+		require.NoError(t, err)
+		req.Header.Set(codersdk.SessionTokenHeader, userClient.SessionToken())
+		res, err := cli.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+		require.Equal(t, []string{alice.NameID}, idp.Logouts())
+
+		_, err = userClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+}
+
 func TestUserLogout(t *testing.T) {
 	t.Parallel()
 
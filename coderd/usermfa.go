@@ -0,0 +1,564 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/mfa"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user second factor status
+// @ID get-user-second-factor-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserMFA
+// @Router /users/{user}/mfa [get]
+func (api *API) userMFA(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's second factor.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	remaining, err := api.Database.CountUserMFARecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error counting recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	status := codersdk.UserMFA{
+		Required:               api.DeploymentValues.RequireMFA.Value() && user.LoginType == database.LoginTypePassword,
+		TOTPEnabled:            totp.VerifiedAt.Valid,
+		RecoveryCodesRemaining: remaining,
+		WebAuthnCredentials:    int64(len(credentials)),
+	}
+	if totp.VerifiedAt.Valid {
+		status.TOTPEnabledAt = &totp.VerifiedAt.Time
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, status)
+}
+
+// @Summary Enroll user TOTP second factor
+// @ID enroll-user-totp-second-factor
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/{user}/mfa/totp [post]
+func (api *API) postUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if !requireOwnMFA(ctx, rw, apiKey, user) {
+		return
+	}
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("A second factor can only be enrolled by users with login type %q.", database.LoginTypePassword),
+		})
+		return
+	}
+
+	existing, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's second factor.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if existing.VerifiedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A TOTP second factor is already enabled. Disable it before enrolling a new one.",
+		})
+		return
+	}
+
+	secret, err := mfa.GenerateTOTPSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating TOTP secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error storing TOTP secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.TOTPEnrollment{
+		Secret: secret,
+		URL:    mfa.TOTPURL(fmt.Sprintf("Coder (%s)", api.AccessURL.Host), user.Username, secret),
+	})
+}
+
+// @Summary Verify user TOTP second factor
+// @ID verify-user-totp-second-factor
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.MFACodeRequest true "TOTP code"
+// @Success 200 {object} codersdk.MFARecoveryCodes
+// @Router /users/{user}/mfa/totp/verify [post]
+func (api *API) postUserTOTPVerify(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+		logger = api.Logger.Named(userAuthLoggerName)
+	)
+
+	if !requireOwnMFA(ctx, rw, apiKey, user) {
+		return
+	}
+	var req codersdk.MFACodeRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Enroll a TOTP second factor before verifying it.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's second factor.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if totp.VerifiedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The TOTP second factor is already enabled.",
+		})
+		return
+	}
+
+	ok, err := api.verifyTOTPCode(ctx, totp, req.Code)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying TOTP code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !ok {
+		writeInvalidMFACode(ctx, rw)
+		return
+	}
+
+	codes, err := api.replaceMFARecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	logger.Info(ctx, "user enabled totp second factor", slog.F("user_id", user.ID))
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFARecoveryCodes{
+		RecoveryCodes: codes,
+	})
+}
+
+// @Summary Regenerate user recovery codes
+// @ID regenerate-user-recovery-codes
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} codersdk.MFARecoveryCodes
+// @Router /users/{user}/mfa/recovery-codes [post]
+func (api *API) postUserMFARecoveryCodes(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if !requireOwnMFA(ctx, rw, apiKey, user) {
+		return
+	}
+	var req codersdk.MFACodeRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	totp, ok := api.enabledTOTP(ctx, rw, user.ID)
+	if !ok {
+		return
+	}
+	ok, err := api.verifyMFACode(ctx, totp, req.Code)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !ok {
+		writeInvalidMFACode(ctx, rw)
+		return
+	}
+
+	codes, err := api.replaceMFARecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFARecoveryCodes{
+		RecoveryCodes: codes,
+	})
+}
+
+// @Summary Disable user second factor
+// @ID disable-user-second-factor
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.MFACodeRequest true "TOTP or recovery code, unless resetting another user"
+// @Success 204
+// @Router /users/{user}/mfa [delete]
+func (api *API) deleteUserMFA(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		logger            = api.Logger.Named(userAuthLoggerName)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+			AdditionalFields: map[string]string{
+				"second_factor": "disabled",
+			},
+		})
+	)
+	defer commitAudit()
+	aReq.Old = user
+
+	// Users must prove possession of the second factor to remove it, so a
+	// stolen session cannot be used to downgrade the account. Admins can
+	// reset the second factor of users that lost it.
+	if apiKey.UserID == user.ID {
+		var req codersdk.MFACodeRequest
+		if !httpapi.Read(ctx, rw, r, &req) {
+			return
+		}
+		totp, ok := api.enabledTOTP(ctx, rw, user.ID)
+		if !ok {
+			return
+		}
+		ok, err := api.verifyMFACode(ctx, totp, req.Code)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !ok {
+			writeInvalidMFACode(ctx, rw)
+			return
+		}
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserTOTP(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete user totp: %w", err)
+		}
+		err = tx.DeleteUserMFARecoveryCodes(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete user recovery codes: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error disabling second factor.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = user
+	logger.Info(ctx, "user second factor disabled", slog.F("user_id", user.ID), slog.F("by_user_id", apiKey.UserID))
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// loginMFA checks the second factor of a password login. The second factor is
+// either a TOTP or recovery code, or the response of a registered passkey. If
+// false is returned, the appropriate error was written to the ResponseWriter.
+func (api *API) loginMFA(ctx context.Context, rw http.ResponseWriter, user database.User, code string, webAuthnCredential json.RawMessage) bool {
+	//nolint:gocritic // The user is not authenticated until the second
+	// factor is verified.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's second factor.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's passkeys.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if !totp.VerifiedAt.Valid && len(credentials) == 0 {
+		// No second factor was ever enabled.
+		return true
+	}
+
+	if len(webAuthnCredential) > 0 {
+		_, _, err := api.verifyWebAuthnAssertion(ctx, webAuthnCredential, user.ID)
+		if errors.Is(err, errInvalidWebAuthnResponse) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Passkey verification failed.",
+			})
+			return false
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying passkey.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		return true
+	}
+	if code == "" || !totp.VerifiedAt.Valid {
+		detail := "Enter a code from your authenticator app or a recovery code."
+		if !totp.VerifiedAt.Valid {
+			detail = "Use one of your passkeys."
+		} else if len(credentials) > 0 {
+			detail = "Enter a code from your authenticator app or a recovery code, or use one of your passkeys."
+		}
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "A second factor is required.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "mfa_code",
+				Detail: detail,
+			}},
+		})
+		return false
+	}
+	ok, err := api.verifyMFACode(ctx, totp, code)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying second factor.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect second factor code.",
+		})
+		return false
+	}
+	return true
+}
+
+// mfaEnrollmentRequired reports whether a password user has yet to enable a
+// second factor. It is only used when the deployment requires one.
+func (api *API) mfaEnrollmentRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	//nolint:gocritic // Checked before the request is authorized.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if totp.VerifiedAt.Valid {
+		return false, nil
+	}
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(credentials) == 0, nil
+}
+
+// enabledTOTP returns the verified TOTP second factor of a user. If false is
+// returned, the appropriate error was written to the ResponseWriter.
+func (api *API) enabledTOTP(ctx context.Context, rw http.ResponseWriter, userID uuid.UUID) (database.UserTOTP, bool) {
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's second factor.",
+			Detail:  err.Error(),
+		})
+		return database.UserTOTP{}, false
+	}
+	if !totp.VerifiedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A second factor is not enabled.",
+		})
+		return database.UserTOTP{}, false
+	}
+	return totp, true
+}
+
+// verifyMFACode checks a TOTP code or recovery code. Accepted codes are
+// consumed so they cannot be used again.
+func (api *API) verifyMFACode(ctx context.Context, totp database.UserTOTP, code string) (bool, error) {
+	if mfa.IsTOTPCode(code) {
+		return api.verifyTOTPCode(ctx, totp, code)
+	}
+	deleted, err := api.Database.DeleteUserMFARecoveryCode(ctx, database.DeleteUserMFARecoveryCodeParams{
+		UserID:     totp.UserID,
+		HashedCode: mfa.HashRecoveryCode(code),
+	})
+	if err != nil {
+		return false, xerrors.Errorf("delete recovery code: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// verifyTOTPCode checks a TOTP code and records its time step so that it, or
+// an earlier code, cannot be replayed.
+func (api *API) verifyTOTPCode(ctx context.Context, totp database.UserTOTP, code string) (bool, error) {
+	now := dbtime.Now()
+	step, ok, err := mfa.ValidateTOTP(totp.Secret, code, now)
+	if err != nil {
+		return false, xerrors.Errorf("validate totp code: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	updated, err := api.Database.UpdateUserTOTPLastUsedStep(ctx, database.UpdateUserTOTPLastUsedStepParams{
+		UserID:       totp.UserID,
+		LastUsedStep: step,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("update last used step: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// replaceMFARecoveryCodes generates new recovery codes for a user,
+// invalidating any existing ones.
+func (api *API) replaceMFARecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashed := make([][]byte, 0, len(codes))
+	for _, code := range codes {
+		hashed = append(hashed, mfa.HashRecoveryCode(code))
+	}
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserMFARecoveryCodes(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		err = tx.InsertUserMFARecoveryCodes(ctx, database.InsertUserMFARecoveryCodesParams{
+			UserID:      userID,
+			HashedCodes: hashed,
+			CreatedAt:   dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("insert recovery codes: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// requireOwnMFA rejects requests to manage the second factor of another
+// user. Only the user can see the secret or recovery codes.
+func requireOwnMFA(ctx context.Context, rw http.ResponseWriter, apiKey database.APIKey, user database.User) bool {
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only manage your own second factor.",
+		})
+		return false
+	}
+	return true
+}
+
+func writeInvalidMFACode(ctx context.Context, rw http.ResponseWriter) {
+	httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+		Message: "Invalid or expired code.",
+		Validations: []codersdk.ValidationError{{
+			Field:  "code",
+			Detail: "The code is invalid, expired or was already used.",
+		}},
+	})
+}
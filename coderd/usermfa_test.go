@@ -0,0 +1,271 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/mfa"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserMFA(t *testing.T) {
+	t.Parallel()
+
+	// enroll enables a TOTP second factor for the user and returns the secret
+	// and recovery codes.
+	enroll := func(t *testing.T, client *codersdk.Client) (string, []string) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, enrollment.URL, "otpauth://totp/")
+
+		code, err := mfa.TOTPCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+		codes, err := client.VerifyTOTP(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: code})
+		require.NoError(t, err)
+		require.Len(t, codes.RecoveryCodes, mfa.RecoveryCodeCount)
+		return enrollment.Secret, codes.RecoveryCodes
+	}
+
+	// nextCode returns a code for the next time step, as codes can only be
+	// used once.
+	nextCode := func(t *testing.T, secret string) string {
+		t.Helper()
+		code, err := mfa.TOTPCode(secret, time.Now().Add(mfa.TOTPPeriod))
+		require.NoError(t, err)
+		return code
+	}
+
+	t.Run("Login", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		status, err := member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Required)
+		require.False(t, status.TOTPEnabled)
+
+		secret, recoveryCodes := enroll(t, member)
+
+		status, err = member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.TOTPEnabled)
+		require.NotNil(t, status.TOTPEnabledAt)
+		require.EqualValues(t, mfa.RecoveryCodeCount, status.RecoveryCodesRemaining)
+
+		login := codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		}
+		_, err = client.LoginWithPassword(ctx, login)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected a second factor to be required: %v", err)
+
+		login.MFACode = "000000"
+		_, err = client.LoginWithPassword(ctx, login)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.False(t, codersdk.IsMFACodeRequired(err))
+
+		login.MFACode = nextCode(t, secret)
+		_, err = client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+
+		// Codes cannot be replayed.
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		// Recovery codes are consumed on use.
+		login.MFACode = recoveryCodes[0]
+		_, err = client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		status, err = member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, mfa.RecoveryCodeCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("AlreadyEnabled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		enroll(t, member)
+
+		_, err := member.EnrollTOTP(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidVerifyCode", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := member.VerifyTOTP(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: "123456"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = member.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.VerifyTOTP(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: "not-a-code"})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		status, err := member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.TOTPEnabled)
+	})
+
+	t.Run("RegenerateRecoveryCodes", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		secret, oldCodes := enroll(t, member)
+
+		codes, err := member.RegenerateMFARecoveryCodes(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: nextCode(t, secret)})
+		require.NoError(t, err)
+		require.Len(t, codes.RecoveryCodes, mfa.RecoveryCodeCount)
+
+		// The previous codes no longer work.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+			MFACode:  oldCodes[0],
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+			MFACode:  codes.RecoveryCodes[0],
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, recoveryCodes := enroll(t, member)
+
+		err := member.DisableMFA(ctx, codersdk.Me, codersdk.MFACodeRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = member.DisableMFA(ctx, codersdk.Me, codersdk.MFACodeRequest{Code: recoveryCodes[0]})
+		require.NoError(t, err)
+
+		status, err := member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.TOTPEnabled)
+		require.Zero(t, status.RecoveryCodesRemaining)
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("AdminReset", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		enroll(t, member)
+
+		// Members cannot manage the second factor of other users.
+		_, err := other.EnrollTOTP(ctx, user.ID.String())
+		require.Error(t, err)
+		err = other.DisableMFA(ctx, user.ID.String(), codersdk.MFACodeRequest{})
+		require.Error(t, err)
+
+		// Admins cannot enroll a second factor for other users.
+		_, err = client.EnrollTOTP(ctx, user.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.DisableMFA(ctx, user.ID.String(), codersdk.MFACodeRequest{})
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:           database.AuditActionWrite,
+			ResourceType:     database.ResourceTypeUser,
+			ResourceID:       user.ID,
+			UserID:           owner.UserID,
+			AdditionalFields: json.RawMessage(`{"second_factor":"disabled"}`),
+		}))
+
+		status, err := client.UserMFA(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.False(t, status.TOTPEnabled)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireMFA = true
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := client.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		status, err := client.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Required)
+		require.False(t, status.TOTPEnabled)
+
+		enroll(t, client)
+
+		_, err = client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+}
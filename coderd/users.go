@@ -465,6 +465,22 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		loginType = database.LoginTypeOIDC
+	case codersdk.LoginTypeLDAP:
+		if api.LDAPConfig == nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "You must configure LDAP before creating LDAP users.",
+			})
+			return
+		}
+		loginType = database.LoginTypeLDAP
+	case codersdk.LoginTypeSAML:
+		if api.SAMLConfig == nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "You must configure SAML before creating SAML users.",
+			})
+			return
+		}
+		loginType = database.LoginTypeSAML
 	case codersdk.LoginTypeGithub:
 		loginType = database.LoginTypeGithub
 	default:
@@ -1276,10 +1292,10 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 	defer commitAudit()
 	aReq.Old = user
 
-	if user.LoginType == database.LoginTypeOIDC && api.IDPSync.SiteRoleSyncEnabled() {
+	if idpSyncedLoginType(user.LoginType) && api.IDPSync.SiteRoleSyncEnabled() {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Cannot modify roles for OIDC users when role sync is enabled.",
-			Detail:  "'User Role Field' is set in the OIDC configuration. All role changes must come from the oidc identity provider.",
+			Message: fmt.Sprintf("Cannot modify roles for %s users when role sync is enabled.", idpSyncedLoginTypeName(user.LoginType)),
+			Detail:  fmt.Sprintf("'User Role Field' is set in the OIDC configuration. All role changes must come from the %s identity provider.", idpSyncedLoginTypeName(user.LoginType)),
 		})
 		return
 	}
@@ -1578,5 +1594,8 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		Scope:           codersdk.APIKeyScope(k.Scope),
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
+
+		ScopePermissions: k.ScopePermissions,
+		ScopeAllowList:   k.ScopeAllowList,
 	}
 }
@@ -0,0 +1,372 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var userSecretEnvNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// @Summary Get user secrets
+// @ID get-user-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.UserSecret
+// @Router /users/{user}/secrets [get]
+func (api *API) userSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	secrets, err := api.Database.GetUserSecretsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.UserSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		converted = append(converted, convertUserSecret(secret))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get user secret by name
+// @ID get-user-secret-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param secretname path string true "Secret name"
+// @Success 200 {object} codersdk.UserSecret
+// @Router /users/{user}/secrets/{secretname} [get]
+func (api *API) userSecretByName(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	secret, err := api.Database.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+		UserID: user.ID,
+		Name:   chi.URLParam(r, "secretname"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserSecret(secret))
+}
+
+// @Summary Create user secret
+// @ID create-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateUserSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.UserSecret
+// @Router /users/{user}/secrets [post]
+func (api *API) postUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateUserSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validations := validateUserSecretTarget(req.EnvName, req.FilePath); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid user secret.",
+			Validations: validations,
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	secret, err := api.Database.InsertUserSecret(ctx, database.InsertUserSecretParams{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       req.Value,
+		EnvName:     req.EnvName,
+		FilePath:    req.FilePath,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		writeUserSecretConflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+	httpapi.Write(ctx, rw, http.StatusCreated, convertUserSecret(secret))
+}
+
+// @Summary Update user secret
+// @ID update-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param secretname path string true "Secret name"
+// @Param request body codersdk.UpdateUserSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.UserSecret
+// @Router /users/{user}/secrets/{secretname} [patch]
+func (api *API) patchUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateUserSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	secret, err := api.Database.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+		UserID: user.ID,
+		Name:   chi.URLParam(r, "secretname"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = secret
+
+	params := database.UpdateUserSecretParams{
+		ID:          secret.ID,
+		Description: secret.Description,
+		Value:       secret.Value,
+		EnvName:     secret.EnvName,
+		FilePath:    secret.FilePath,
+		UpdatedAt:   dbtime.Now(),
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+	if req.Value != nil {
+		params.Value = *req.Value
+	}
+	if req.EnvName != nil {
+		params.EnvName = *req.EnvName
+	}
+	if req.FilePath != nil {
+		params.FilePath = *req.FilePath
+	}
+	if params.Value == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid user secret.",
+			Validations: []codersdk.ValidationError{
+				{Field: "value", Detail: "Value must not be empty."},
+			},
+		})
+		return
+	}
+	if validations := validateUserSecretTarget(params.EnvName, params.FilePath); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid user secret.",
+			Validations: validations,
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateUserSecret(ctx, params)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		writeUserSecretConflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserSecret(updated))
+}
+
+// @Summary Delete user secret
+// @ID delete-user-secret
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param secretname path string true "Secret name"
+// @Success 204
+// @Router /users/{user}/secrets/{secretname} [delete]
+func (api *API) deleteUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	secret, err := api.Database.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+		UserID: user.ID,
+		Name:   chi.URLParam(r, "secretname"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = secret
+
+	err = api.Database.DeleteUserSecret(ctx, secret.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// validateUserSecretTarget validates where a secret is delivered to in
+// workspaces.
+func validateUserSecretTarget(envName, filePath string) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if envName != "" {
+		switch {
+		case !userSecretEnvNameRegex.MatchString(envName):
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "env_name",
+				Detail: "Must start with a letter or underscore and contain only letters, digits and underscores.",
+			})
+		case strings.HasPrefix(strings.ToUpper(envName), "CODER_"):
+			// The agent sets these itself, and overriding them could break
+			// the connection to the workspace.
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "env_name",
+				Detail: `Must not start with "CODER_".`,
+			})
+		}
+	}
+	if filePath != "" {
+		rel, home := strings.CutPrefix(filePath, "~/")
+		switch {
+		case !home && !path.IsAbs(filePath):
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "file_path",
+				Detail: `Must be an absolute path or start with "~/".`,
+			})
+		case home && (rel == "" || path.Clean(rel) != rel || strings.HasPrefix(rel, "../")),
+			!home && path.Clean(filePath) != filePath,
+			filePath == "/":
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "file_path",
+				Detail: "Must be a clean path to a file.",
+			})
+		}
+	}
+	return validations
+}
+
+func writeUserSecretConflict(rw http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	field, detail := "name", "A secret with this name already exists."
+	switch {
+	case database.IsUniqueViolation(err, database.UniqueUserSecretsUserEnvNameIndex):
+		field, detail = "env_name", "Another secret is already exposed as this environment variable."
+	case database.IsUniqueViolation(err, database.UniqueUserSecretsUserFilePathIndex):
+		field, detail = "file_path", "Another secret is already written to this file."
+	}
+	httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+		Message:     fmt.Sprintf("Conflicting %s.", strings.ReplaceAll(field, "_", " ")),
+		Validations: []codersdk.ValidationError{{Field: field, Detail: detail}},
+	})
+}
+
+func convertUserSecret(secret database.UserSecret) codersdk.UserSecret {
+	return codersdk.UserSecret{
+		ID:          secret.ID,
+		UserID:      secret.UserID,
+		Name:        secret.Name,
+		Description: secret.Description,
+		EnvName:     secret.EnvName,
+		FilePath:    secret.FilePath,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		secret, err := member.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:        "openai",
+			Description: "OpenAI API key",
+			Value:       "sk-123",
+			EnvName:     "OPENAI_API_KEY",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "openai", secret.Name)
+		require.Equal(t, "OPENAI_API_KEY", secret.EnvName)
+		require.Empty(t, secret.FilePath)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeUserSecret,
+			ResourceID:   secret.ID,
+		}))
+
+		secrets, err := member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ID, secrets[0].ID)
+
+		updated, err := member.UpdateUserSecret(ctx, codersdk.Me, "openai", codersdk.UpdateUserSecretRequest{
+			Value:    ptr.Ref("sk-456"),
+			EnvName:  ptr.Ref(""),
+			FilePath: ptr.Ref("~/.config/openai/key"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "OpenAI API key", updated.Description)
+		require.Empty(t, updated.EnvName)
+		require.Equal(t, "~/.config/openai/key", updated.FilePath)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionWrite,
+			ResourceType: database.ResourceTypeUserSecret,
+			ResourceID:   secret.ID,
+		}))
+
+		fetched, err := member.UserSecretByName(ctx, codersdk.Me, "openai")
+		require.NoError(t, err)
+		require.Equal(t, updated, fetched)
+
+		err = member.DeleteUserSecret(ctx, codersdk.Me, "openai")
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeUserSecret,
+			ResourceID:   secret.ID,
+		}))
+
+		_, err = member.UserSecretByName(ctx, codersdk.Me, "openai")
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:     "token",
+			Value:    "value",
+			EnvName:  "TOKEN",
+			FilePath: "/tmp/token",
+		})
+		require.NoError(t, err)
+
+		for _, req := range []codersdk.CreateUserSecretRequest{
+			{Name: "token", Value: "value"},
+			{Name: "other-env", Value: "value", EnvName: "TOKEN"},
+			{Name: "other-file", Value: "value", FilePath: "/tmp/token"},
+		} {
+			_, err = client.CreateUserSecret(ctx, codersdk.Me, req)
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, http.StatusConflict, sdkErr.StatusCode(), req.Name)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		for _, req := range []codersdk.CreateUserSecretRequest{
+			{Name: "no value"},
+			{Name: "-invalid-", Value: "value"},
+			{Name: "env", Value: "value", EnvName: "1FOO"},
+			{Name: "env", Value: "value", EnvName: "CODER_AGENT_TOKEN"},
+			{Name: "file", Value: "value", FilePath: "relative/path"},
+			{Name: "file", Value: "value", FilePath: "~/../escape"},
+			{Name: "file", Value: "value", FilePath: "/tmp/../etc/passwd"},
+		} {
+			_, err := client.CreateUserSecret(ctx, codersdk.Me, req)
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode(), req)
+		}
+	})
+
+	t.Run("OwnerCannotRead", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := member.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:  "token",
+			Value: "value",
+		})
+		require.NoError(t, err)
+
+		secrets, err := client.UserSecrets(ctx, user.Username)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+
+		_, err = client.UserSecretByName(ctx, user.Username, "token")
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+		_, err = client.CreateUserSecret(ctx, user.Username, codersdk.CreateUserSecretRequest{
+			Name:  "injected",
+			Value: "value",
+		})
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
@@ -0,0 +1,746 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/webauthnauth"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/site"
+)
+
+// errInvalidWebAuthnResponse is returned when the response of an
+// authenticator cannot be verified.
+var errInvalidWebAuthnResponse = xerrors.New("invalid webauthn response")
+
+// @Summary Get user WebAuthn credentials
+// @ID get-user-webauthn-credentials
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.WebAuthnCredential
+// @Router /users/{user}/webauthn/credentials [get]
+func (api *API) userWebAuthnCredentials(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credentials.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.WebAuthnCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		converted = append(converted, convertWebAuthnCredential(credential))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Start user WebAuthn credential registration
+// @ID start-user-webauthn-credential-registration
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.WebAuthnOptions
+// @Router /users/{user}/webauthn/registration [post]
+func (api *API) postUserWebAuthnRegistration(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if !api.requireWebAuthn(ctx, rw) || !requireOwnWebAuthn(ctx, rw, apiKey, user) {
+		return
+	}
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credentials.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	waUser := webauthnauth.User{User: user, Credentials: credentials}
+	creation, session, err := api.WebAuthn.BeginRegistration(waUser, webauthn.WithExclusions(waUser.Descriptors()))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting WebAuthn registration.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	options, err := api.saveWebAuthnSession(ctx, session, creation.Response)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting WebAuthn registration.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, options)
+}
+
+// @Summary Create user WebAuthn credential
+// @ID create-user-webauthn-credential
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateWebAuthnCredentialRequest true "Registration response"
+// @Success 201 {object} codersdk.WebAuthnCredential
+// @Router /users/{user}/webauthn/credentials [post]
+func (api *API) postUserWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WebAuthnCredential](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	if !api.requireWebAuthn(ctx, rw) || !requireOwnWebAuthn(ctx, rw, apiKey, user) {
+		return
+	}
+	var req codersdk.CreateWebAuthnCredentialRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// A passkey signs in without a password or second factor, so a stolen
+	// session must not be enough to add one.
+	if !api.verifyUserPresence(ctx, rw, user, userPresence{
+		webAuthnCredential: req.WebAuthnCredential,
+		code:               req.Code,
+		password:           req.Password,
+	}) {
+		return
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		writeInvalidWebAuthnResponse(ctx, rw, err)
+		return
+	}
+	session, err := api.consumeWebAuthnSession(ctx, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		writeInvalidWebAuthnResponse(ctx, rw, err)
+		return
+	}
+	credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credentials.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	credential, err := api.WebAuthn.CreateCredential(webauthnauth.User{User: user, Credentials: credentials}, session, parsed)
+	if err != nil {
+		writeInvalidWebAuthnResponse(ctx, rw, err)
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = "Passkey"
+	}
+	inserted, err := api.Database.InsertWebAuthnCredential(ctx, database.InsertWebAuthnCredentialParams{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      webauthnauth.Transports(credential),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		CreatedAt:       dbtime.Now(),
+	})
+	if database.IsUniqueViolation(err, database.UniqueWebauthnCredentialsCredentialIDKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "This credential is already registered.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error storing WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = inserted
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWebAuthnCredential(inserted))
+}
+
+// @Summary Delete user WebAuthn credential
+// @ID delete-user-webauthn-credential
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param credential path string true "Credential ID" format(uuid)
+// @Param request body codersdk.DeleteWebAuthnCredentialRequest true "Proof of presence"
+// @Success 204
+// @Router /users/{user}/webauthn/credentials/{credential} [delete]
+func (api *API) deleteUserWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WebAuthnCredential](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "credential")
+	if !ok {
+		return
+	}
+	credential, err := api.Database.GetWebAuthnCredentialByID(ctx, id)
+	if httpapi.Is404Error(err) || (err == nil && credential.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = credential
+
+	// Like the second factor, users must prove that they are present to
+	// remove a passkey, so a stolen session cannot be used to downgrade the
+	// account. Admins can remove the passkeys of other users.
+	if apiKey.UserID == user.ID {
+		var req codersdk.DeleteWebAuthnCredentialRequest
+		if !httpapi.Read(ctx, rw, r, &req) {
+			return
+		}
+		if !api.verifyUserPresence(ctx, rw, user, userPresence{
+			webAuthnCredential: req.WebAuthnCredential,
+			code:               req.Code,
+			password:           req.Password,
+		}) {
+			return
+		}
+	}
+
+	err = api.Database.DeleteWebAuthnCredential(ctx, id)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Start passkey login
+// @ID start-passkey-login
+// @Produce json
+// @Tags Authorization
+// @Success 200 {object} codersdk.WebAuthnOptions
+// @Router /users/webauthn/options [post]
+func (api *API) postWebAuthnLoginOptions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.requireWebAuthn(ctx, rw) {
+		return
+	}
+	// The credential is discovered by the authenticator, so the user does
+	// not need to be known yet.
+	assertion, session, err := api.WebAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting passkey login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	options, err := api.saveWebAuthnSession(ctx, session, assertion.Response)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting passkey login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, options)
+}
+
+// @Summary Log in user with a passkey
+// @ID log-in-user-with-a-passkey
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithWebAuthnRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/webauthn/login [post]
+func (api *API) postLoginWebAuthn(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if !api.requireWebAuthn(ctx, rw) {
+		return
+	}
+	// A passkey replaces the password of a password user, so it is disabled
+	// together with password authentication.
+	if api.DeploymentValues.DisablePasswordAuth {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Password authentication is disabled.",
+		})
+		return
+	}
+	var req codersdk.LoginWithWebAuthnRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	user, verified, err := api.verifyWebAuthnAssertion(ctx, req.Credential, uuid.Nil)
+	aReq.UserID = user.ID
+	if errors.Is(err, errInvalidWebAuthnResponse) {
+		logger.Debug(ctx, "passkey login failed", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Passkey login failed.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Signing in without a password requires the authenticator to verify
+	// the user, e.g. with a PIN or biometrics.
+	if !verified {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Passkey login requires user verification.",
+			Detail:  "Use an authenticator that is protected by a PIN or biometrics.",
+		})
+		return
+	}
+
+	// Users of other login types must sign in through their identity
+	// provider, which deprovisions them and syncs their organizations, groups
+	// and roles.
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Incorrect login type, attempting to use a passkey but user is of login type %q", user.LoginType),
+		})
+		return
+	}
+
+	user, err = ActivateDormantUser(api.Logger, &api.Auditor, api.Database)(ctx, user)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	actor, userStatus, err := httpmw.UserRBACSubject(ctx, api.Database, user.ID, rbac.ScopeAll)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	if userStatus != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is " + string(userStatus) + ". Contact an admin to reactivate your account.",
+		})
+		return
+	}
+
+	//nolint:gocritic // Creating the API key as the user instead of as system.
+	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
+		UserID:          user.ID,
+		LoginType:       user.LoginType,
+		RemoteAddr:      r.RemoteAddr,
+		DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to create API key", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// @Summary Sign in to the CLI with a passkey
+// @ID sign-in-to-the-cli-with-a-passkey
+// @Tags Authorization
+// @Param redirect_uri query string true "Loopback URL of the CLI"
+// @Param state query string true "State passed back to the CLI"
+// @Success 200
+// @Router /users/webauthn/cli-auth [get]
+func (api *API) webAuthnCLIAuth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.requireWebAuthn(ctx, rw) {
+		return
+	}
+	p := httpapi.NewQueryParamParser().RequiredNotEmpty("redirect_uri", "state")
+	redirectURI := p.String(r.URL.Query(), "", "redirect_uri")
+	state := p.String(r.URL.Query(), "", "state")
+	p.ErrorExcessParams(r.URL.Query())
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query params.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	// The session token is handed to the CLI in the redirect, so it must
+	// never leave the machine of the user.
+	parsed, err := url.Parse(redirectURI)
+	if err != nil || parsed.Scheme != "http" || !isLoopbackHost(parsed.Hostname()) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid redirect_uri.",
+			Detail:  "The redirect URI must be an http URL on a loopback address.",
+		})
+		return
+	}
+
+	site.RenderWebAuthnCLIPage(rw, r, site.RenderWebAuthnCLIData{
+		RedirectURI: parsed.String(),
+		State:       state,
+	})
+}
+
+// verifyWebAuthnAssertion verifies the response of an authenticator to a login
+// challenge and records the use of the credential. If userID is not uuid.Nil,
+// the credential must belong to that user. It returns the owner of the
+// credential and whether the authenticator verified the user.
+func (api *API) verifyWebAuthnAssertion(ctx context.Context, response json.RawMessage, userID uuid.UUID) (database.User, bool, error) {
+	if api.WebAuthn == nil {
+		return database.User{}, false, xerrors.Errorf("passkeys are not available: %w", errInvalidWebAuthnResponse)
+	}
+	//nolint:gocritic // The user is not authenticated until the response is
+	// verified.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return database.User{}, false, xerrors.Errorf("parse response: %s: %w", err.Error(), errInvalidWebAuthnResponse)
+	}
+	session, err := api.consumeWebAuthnSession(ctx, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		return database.User{}, false, err
+	}
+
+	var (
+		user   database.User
+		stored database.WebAuthnCredential
+	)
+	discover := func(rawID, userHandle []byte) (webauthn.User, error) {
+		var err error
+		stored, err = api.Database.GetWebAuthnCredentialByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, xerrors.Errorf("get credential: %w", err)
+		}
+		if !bytes.Equal(userHandle, webauthnauth.UserHandle(stored.UserID)) {
+			return nil, xerrors.New("user handle does not match the credential")
+		}
+		if userID != uuid.Nil && stored.UserID != userID {
+			return nil, xerrors.New("credential belongs to another user")
+		}
+		user, err = api.Database.GetUserByID(ctx, stored.UserID)
+		if err != nil {
+			return nil, xerrors.Errorf("get user: %w", err)
+		}
+		credentials, err := api.Database.GetWebAuthnCredentialsByUserID(ctx, stored.UserID)
+		if err != nil {
+			return nil, xerrors.Errorf("get credentials: %w", err)
+		}
+		return webauthnauth.User{User: user, Credentials: credentials}, nil
+	}
+	credential, err := api.WebAuthn.ValidateDiscoverableLogin(discover, session, parsed)
+	if err != nil {
+		return user, false, xerrors.Errorf("validate login: %s: %w", err.Error(), errInvalidWebAuthnResponse)
+	}
+	if user.Deleted {
+		return user, false, xerrors.Errorf("user is deleted: %w", errInvalidWebAuthnResponse)
+	}
+	if credential.Authenticator.CloneWarning {
+		api.Logger.Warn(ctx, "webauthn sign counter did not increase, the authenticator may be cloned",
+			slog.F("user_id", user.ID), slog.F("credential_id", stored.ID))
+		return user, false, xerrors.Errorf("sign counter did not increase: %w", errInvalidWebAuthnResponse)
+	}
+
+	err = api.Database.UpdateWebAuthnCredentialUsage(ctx, database.UpdateWebAuthnCredentialUsageParams{
+		ID:          stored.ID,
+		SignCount:   int64(credential.Authenticator.SignCount),
+		BackupState: credential.Flags.BackupState,
+		LastUsedAt:  sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		return user, false, xerrors.Errorf("update credential usage: %w", err)
+	}
+	return user, credential.Flags.UserVerified, nil
+}
+
+// userPresence is the proof that a user is present to change their passkeys.
+// At most one of the fields is checked.
+type userPresence struct {
+	webAuthnCredential json.RawMessage
+	code               string
+	password           string
+}
+
+// verifyUserPresence checks the passkey response, second factor code or
+// password of a user that is about to register or remove one of their
+// passkeys. If false is returned, the appropriate error was written to the
+// ResponseWriter.
+func (api *API) verifyUserPresence(ctx context.Context, rw http.ResponseWriter, user database.User, req userPresence) bool {
+	switch {
+	case len(req.webAuthnCredential) > 0:
+		_, _, err := api.verifyWebAuthnAssertion(ctx, req.webAuthnCredential, user.ID)
+		if errors.Is(err, errInvalidWebAuthnResponse) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Passkey verification failed.",
+			})
+			return false
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying passkey.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		return true
+	case req.code != "":
+		totp, ok := api.enabledTOTP(ctx, rw, user.ID)
+		if !ok {
+			return false
+		}
+		ok, err := api.verifyMFACode(ctx, totp, req.code)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying code.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		if !ok {
+			writeInvalidMFACode(ctx, rw)
+			return false
+		}
+		return true
+	case req.password != "":
+		if user.LoginType != database.LoginTypePassword {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Users of login type %q do not have a password.", user.LoginType),
+			})
+			return false
+		}
+		ok, err := userpassword.Compare(string(user.HashedPassword), req.password)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying password.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		if !ok {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Incorrect password.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "password",
+					Detail: "Incorrect password.",
+				}},
+			})
+			return false
+		}
+		return true
+	default:
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Confirm that you are present to change your passkeys.",
+			Detail:  "Use an existing passkey, or enter a code from your authenticator app, a recovery code or your password.",
+		})
+		return false
+	}
+}
+
+// saveWebAuthnSession stores the session of a ceremony until the
+// authenticator responds to its challenge, and returns the options to pass to
+// the browser.
+func (api *API) saveWebAuthnSession(ctx context.Context, session *webauthn.SessionData, publicKey any) (codersdk.WebAuthnOptions, error) {
+	rawSession, err := json.Marshal(session)
+	if err != nil {
+		return codersdk.WebAuthnOptions{}, xerrors.Errorf("marshal session: %w", err)
+	}
+	rawOptions, err := json.Marshal(publicKey)
+	if err != nil {
+		return codersdk.WebAuthnOptions{}, xerrors.Errorf("marshal options: %w", err)
+	}
+	//nolint:gocritic // Challenges are not owned by a user.
+	err = api.Database.InsertWebAuthnChallenge(dbauthz.AsSystemRestricted(ctx), database.InsertWebAuthnChallengeParams{
+		Challenge: session.Challenge,
+		Session:   rawSession,
+		ExpiresAt: dbtime.Now().Add(webauthnauth.ChallengeTimeout),
+	})
+	if err != nil {
+		return codersdk.WebAuthnOptions{}, xerrors.Errorf("insert challenge: %w", err)
+	}
+	return codersdk.WebAuthnOptions{PublicKey: rawOptions}, nil
+}
+
+// consumeWebAuthnSession loads and deletes the session of the challenge the
+// authenticator responded to, so every challenge can only be answered once.
+func (api *API) consumeWebAuthnSession(ctx context.Context, challenge string) (webauthn.SessionData, error) {
+	//nolint:gocritic // Challenges are not owned by a user.
+	row, err := api.Database.DeleteWebAuthnChallenge(dbauthz.AsSystemRestricted(ctx), challenge)
+	if errors.Is(err, sql.ErrNoRows) {
+		return webauthn.SessionData{}, xerrors.Errorf("unknown challenge: %w", errInvalidWebAuthnResponse)
+	}
+	if err != nil {
+		return webauthn.SessionData{}, xerrors.Errorf("delete challenge: %w", err)
+	}
+	if row.ExpiresAt.Before(dbtime.Now()) {
+		return webauthn.SessionData{}, xerrors.Errorf("challenge expired: %w", errInvalidWebAuthnResponse)
+	}
+	var session webauthn.SessionData
+	err = json.Unmarshal(row.Session, &session)
+	if err != nil {
+		return webauthn.SessionData{}, xerrors.Errorf("unmarshal session: %w", err)
+	}
+	return session, nil
+}
+
+// requireWebAuthn writes an error if passkeys are not available.
+func (api *API) requireWebAuthn(ctx context.Context, rw http.ResponseWriter) bool {
+	if api.WebAuthn == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Passkeys are not available on this deployment.",
+			Detail:  "An access URL is required to use passkeys.",
+		})
+		return false
+	}
+	return true
+}
+
+// requireOwnWebAuthn rejects requests to register credentials for another
+// user.
+func requireOwnWebAuthn(ctx context.Context, rw http.ResponseWriter, apiKey database.APIKey, user database.User) bool {
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only register passkeys for your own account.",
+		})
+		return false
+	}
+	return true
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func writeInvalidWebAuthnResponse(ctx context.Context, rw http.ResponseWriter, err error) {
+	detail := err.Error()
+	var protoErr *protocol.Error
+	if errors.As(err, &protoErr) {
+		detail = protoErr.Details
+	}
+	httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+		Message: "Invalid response from the authenticator.",
+		Detail:  detail,
+	})
+}
+
+func convertWebAuthnCredential(credential database.WebAuthnCredential) codersdk.WebAuthnCredential {
+	converted := codersdk.WebAuthnCredential{
+		ID:             credential.ID,
+		Name:           credential.Name,
+		Transports:     credential.Transports,
+		BackupEligible: credential.BackupEligible,
+		CreatedAt:      credential.CreatedAt,
+	}
+	if credential.LastUsedAt.Valid {
+		converted.LastUsedAt = ptr.Ref(credential.LastUsedAt.Time)
+	}
+	return converted
+}
@@ -0,0 +1,479 @@
+package coderd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/webauthntest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestUserWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	// register creates a passkey for the user of the client, which must have
+	// the password of coderdtest.CreateAnotherUser.
+	register := func(t *testing.T, client *codersdk.Client) (*webauthntest.Authenticator, codersdk.WebAuthnCredential) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		authenticator := webauthntest.New(t, origin(client))
+		options, err := client.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		credential, err := client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Name:       "Laptop",
+			Credential: authenticator.Register(options),
+			Password:   "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		return authenticator, credential
+	}
+
+	// login signs in with the passkey without a password.
+	login := func(t *testing.T, client *codersdk.Client, authenticator *webauthntest.Authenticator) (codersdk.LoginWithPasswordResponse, error) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		options, err := client.WebAuthnLoginOptions(ctx)
+		require.NoError(t, err)
+		return client.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			Credential: authenticator.Login(options),
+		})
+	}
+
+	t.Run("RegisterAndLogin", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.Passkey.Enabled)
+
+		authenticator, credential := register(t, member)
+		require.Equal(t, "Laptop", credential.Name)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeWebAuthnCredential,
+			ResourceID:   credential.ID,
+		}))
+
+		credentials, err := member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		require.Equal(t, credential.ID, credentials[0].ID)
+		require.Equal(t, []string{"internal"}, credentials[0].Transports)
+		require.Nil(t, credentials[0].LastUsedAt)
+
+		res, err := login(t, client, authenticator)
+		require.NoError(t, err)
+		loggedIn := codersdk.New(client.URL)
+		loggedIn.SetSessionToken(res.SessionToken)
+		me, err := loggedIn.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionLogin,
+			ResourceType: database.ResourceTypeApiKey,
+			UserID:       user.ID,
+		}))
+
+		credentials, err = member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.NotNil(t, credentials[0].LastUsedAt)
+	})
+
+	t.Run("RegisterTwice", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, _ := register(t, member)
+		options, err := member.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Credential: authenticator.Register(options),
+			Password:   "SomeSecurePassword!",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("RegisterRequiresPresence", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		// A session alone cannot add a passkey, as the passkey could then
+		// be used to sign in without the password.
+		authenticator := webauthntest.New(t, origin(member))
+		options, err := member.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Credential: authenticator.Register(options),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		options, err = member.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Credential: authenticator.Register(options),
+			Password:   "WrongPassword!",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		credentials, err := member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, credentials)
+
+		// An existing passkey proves presence for the next one.
+		existing, _ := register(t, member)
+		options, err = member.WebAuthnRegistrationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			Credential:         authenticator.Register(options),
+			WebAuthnCredential: authenticatorResponse(t, member, existing),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("ChallengeReplay", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, _ := register(t, member)
+		options, err := client.WebAuthnLoginOptions(ctx)
+		require.NoError(t, err)
+		_, err = client.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			Credential: authenticator.Login(options),
+		})
+		require.NoError(t, err)
+
+		// Challenges are consumed on use.
+		_, err = client.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			Credential: authenticator.Login(options),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("UserVerificationRequired", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		authenticator, _ := register(t, member)
+		authenticator.UserVerified = false
+		_, err := login(t, client, authenticator)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "user verification")
+	})
+
+	t.Run("SuspendedUser", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, _ := register(t, member)
+		_, err := client.UpdateUserStatus(ctx, user.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+
+		_, err = login(t, client, authenticator)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("SecondFactor", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, _ := register(t, member)
+		otherAuthenticator, _ := register(t, other)
+
+		status, err := member.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, status.WebAuthnCredentials)
+
+		req := codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		}
+		_, err = client.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected a second factor to be required: %v", err)
+
+		// Codes are not accepted without a TOTP second factor.
+		req.MFACode = "123456"
+		_, err = client.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected a second factor to be required: %v", err)
+		req.MFACode = ""
+
+		// Passkeys of other users are rejected.
+		options, err := client.WebAuthnLoginOptions(ctx)
+		require.NoError(t, err)
+		req.WebAuthnCredential = otherAuthenticator.Login(options)
+		_, err = client.LoginWithPassword(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.False(t, codersdk.IsMFACodeRequired(err))
+
+		// User presence is enough for a second factor.
+		authenticator.UserVerified = false
+		options, err = client.WebAuthnLoginOptions(ctx)
+		require.NoError(t, err)
+		req.WebAuthnCredential = authenticator.Login(options)
+		_, err = client.LoginWithPassword(ctx, req)
+		require.NoError(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, credential := register(t, member)
+		_, passwordCredential := register(t, member)
+		_, adminCredential := register(t, member)
+
+		// Members cannot remove the passkeys of other users.
+		err := other.DeleteWebAuthnCredential(ctx, user.ID.String(), credential.ID, codersdk.DeleteWebAuthnCredentialRequest{})
+		require.Error(t, err)
+		// Credentials are scoped to the user in the path.
+		err = client.DeleteWebAuthnCredential(ctx, codersdk.Me, credential.ID, codersdk.DeleteWebAuthnCredentialRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = member.DeleteWebAuthnCredential(ctx, codersdk.Me, credential.ID, codersdk.DeleteWebAuthnCredentialRequest{
+			WebAuthnCredential: authenticatorResponse(t, member, authenticator),
+		})
+		require.NoError(t, err)
+		_, err = login(t, client, authenticator)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeWebAuthnCredential,
+			ResourceID:   credential.ID,
+		}))
+
+		err = member.DeleteWebAuthnCredential(ctx, codersdk.Me, passwordCredential.ID, codersdk.DeleteWebAuthnCredentialRequest{
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+
+		// Admins can remove the passkeys of other users.
+		err = client.DeleteWebAuthnCredential(ctx, user.ID.String(), adminCredential.ID, codersdk.DeleteWebAuthnCredentialRequest{})
+		require.NoError(t, err)
+		credentials, err := member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, credentials)
+	})
+
+	t.Run("DeleteRequiresPresence", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, credential := register(t, member)
+		otherAuthenticator, _ := register(t, other)
+
+		for name, tc := range map[string]struct {
+			req    codersdk.DeleteWebAuthnCredentialRequest
+			status int
+		}{
+			"NoProof":       {codersdk.DeleteWebAuthnCredentialRequest{}, http.StatusForbidden},
+			"WrongPassword": {codersdk.DeleteWebAuthnCredentialRequest{Password: "WrongPassword!"}, http.StatusBadRequest},
+			// A TOTP second factor is not enabled.
+			"Code": {codersdk.DeleteWebAuthnCredentialRequest{Code: "123456"}, http.StatusBadRequest},
+			// Passkeys of other users are rejected.
+			"OtherPasskey": {codersdk.DeleteWebAuthnCredentialRequest{WebAuthnCredential: authenticatorResponse(t, other, otherAuthenticator)}, http.StatusBadRequest},
+		} {
+			err := member.DeleteWebAuthnCredential(ctx, codersdk.Me, credential.ID, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, name)
+			require.Equal(t, tc.status, apiErr.StatusCode(), name)
+		}
+
+		credentials, err := member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+	})
+
+	t.Run("LoginTypes", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		// Users of other login types must sign in through their identity
+		// provider.
+		for _, loginType := range []database.LoginType{
+			database.LoginTypeOIDC,
+			database.LoginTypeGithub,
+			database.LoginTypeLDAP,
+			database.LoginTypeSAML,
+			database.LoginTypeNone,
+		} {
+			member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			authenticator, _ := register(t, member)
+			//nolint:gocritic // Changing the login type requires the system.
+			_, err := db.UpdateUserLoginType(dbauthz.AsSystemRestricted(context.Background()), database.UpdateUserLoginTypeParams{
+				NewLoginType: loginType,
+				UserID:       user.ID,
+			})
+			require.NoError(t, err)
+
+			_, err = login(t, client, authenticator)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, loginType)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode(), loginType)
+			require.Contains(t, apiErr.Message, string(loginType))
+		}
+	})
+
+	t.Run("PasswordAuthDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		authenticator, _ := register(t, member)
+		dv.DisablePasswordAuth = serpent.Bool(true)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.False(t, methods.Passkey.Enabled)
+		_, err = login(t, client, authenticator)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("RegisterForOtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := client.WebAuthnRegistrationOptions(ctx, user.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("RequiredMFA", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireMFA = true
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := client.User(ctx, codersdk.Me)
+		require.Error(t, err)
+
+		// A passkey satisfies the requirement.
+		register(t, client)
+		_, err = client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+
+	t.Run("CLIAuth", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		for _, tc := range []struct {
+			redirectURI string
+			status      int
+		}{
+			{"http://127.0.0.1:3000/callback", http.StatusOK},
+			{"http://localhost:3000/callback", http.StatusOK},
+			{"http://[::1]:3000/callback", http.StatusOK},
+			{"https://127.0.0.1:3000/callback", http.StatusBadRequest},
+			{"http://example.com/callback", http.StatusBadRequest},
+			{"http://127.0.0.1.example.com/callback", http.StatusBadRequest},
+		} {
+			query := url.Values{"redirect_uri": {tc.redirectURI}, "state": {"state"}}
+			res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/webauthn/cli-auth?%s", query.Encode()), nil)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			require.Equal(t, tc.status, res.StatusCode, tc.redirectURI)
+		}
+	})
+}
+
+func origin(client *codersdk.Client) string {
+	return (&url.URL{Scheme: client.URL.Scheme, Host: client.URL.Host}).String()
+}
+
+// authenticatorResponse answers a new login challenge with the authenticator.
+func authenticatorResponse(t *testing.T, client *codersdk.Client, authenticator *webauthntest.Authenticator) json.RawMessage {
+	t.Helper()
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	options, err := client.WebAuthnLoginOptions(ctx)
+	require.NoError(t, err)
+	return authenticator.Login(options)
+}
@@ -0,0 +1,143 @@
+// Package webauthnauth contains the parts of a WebAuthn relying party that are
+// not provided by github.com/go-webauthn/webauthn: deriving the relying party
+// from the access URL and converting users and stored credentials to the types
+// the library expects.
+//
+// Passkeys are registered as discoverable credentials so they can be used to
+// sign in without a username. The user handle of every credential is the ID of
+// the user that registered it.
+package webauthnauth
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+const (
+	// ChallengeTimeout is how long a registration or login ceremony can take
+	// before its challenge expires.
+	ChallengeTimeout = 5 * time.Minute
+
+	displayName = "Coder"
+)
+
+// New returns a relying party for the deployment. Credentials are scoped to the
+// hostname of the access URL, and responses are only accepted from its origin.
+func New(accessURL *url.URL) (*webauthn.WebAuthn, error) {
+	if accessURL == nil || accessURL.Hostname() == "" {
+		return nil, xerrors.New("an access URL is required")
+	}
+	origin := url.URL{Scheme: accessURL.Scheme, Host: accessURL.Host}
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    ChallengeTimeout,
+		TimeoutUVD: ChallengeTimeout,
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:                  accessURL.Hostname(),
+		RPDisplayName:         displayName,
+		RPOrigins:             []string{origin.String()},
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationPreferred,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+}
+
+// User adapts a user and their registered credentials to webauthn.User.
+type User struct {
+	database.User
+	Credentials []database.WebAuthnCredential
+}
+
+var _ webauthn.User = User{}
+
+func (u User) WebAuthnID() []byte {
+	return UserHandle(u.ID)
+}
+
+func (u User) WebAuthnName() string {
+	return u.Username
+}
+
+func (u User) WebAuthnDisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Username
+}
+
+func (u User) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.Credentials))
+	for _, c := range u.Credentials {
+		credentials = append(credentials, Credential(c))
+	}
+	return credentials
+}
+
+// Descriptors returns the descriptors of the registered credentials, used to
+// stop an authenticator from registering twice.
+func (u User) Descriptors() []protocol.CredentialDescriptor {
+	descriptors := make([]protocol.CredentialDescriptor, 0, len(u.Credentials))
+	for _, c := range u.WebAuthnCredentials() {
+		descriptors = append(descriptors, c.Descriptor())
+	}
+	return descriptors
+}
+
+// UserHandle returns the user handle of credentials registered by the user.
+func UserHandle(userID uuid.UUID) []byte {
+	handle := make([]byte, len(userID))
+	copy(handle, userID[:])
+	return handle
+}
+
+// UserID parses a user handle returned by an authenticator.
+func UserID(handle []byte) (uuid.UUID, error) {
+	return uuid.FromBytes(handle)
+}
+
+// Credential converts a stored credential to a webauthn.Credential.
+func Credential(c database.WebAuthnCredential) webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+	for _, t := range c.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(t))
+	}
+	return webauthn.Credential{
+		ID:              c.CredentialID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID: c.AAGUID,
+			//nolint:gosec // Sign counters are 32 bit, see NewCredential.
+			SignCount: uint32(c.SignCount),
+		},
+	}
+}
+
+// Transports converts the transports of a credential to strings for storage.
+func Transports(c *webauthn.Credential) []string {
+	transports := make([]string, 0, len(c.Transport))
+	for _, t := range c.Transport {
+		transports = append(transports, string(t))
+	}
+	return transports
+}
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -686,6 +687,79 @@ func (api *API) workspaceAgentLogs(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Summary Get resource monitors for workspace agent
+// @ID get-resource-monitors-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentResourceMonitorsResponse
+// @Router /workspaceagents/{workspaceagent}/resources-monitoring [get]
+func (api *API) workspaceAgentResourceMonitors(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	monitors := []codersdk.WorkspaceAgentResourceMonitor{}
+
+	memoryMonitor, err := api.Database.FetchMemoryResourceMonitorsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if err == nil {
+		monitors = append(monitors, codersdk.WorkspaceAgentResourceMonitor{
+			Type:      codersdk.WorkspaceAgentResourceMonitorTypeMemory,
+			Enabled:   memoryMonitor.Enabled,
+			Threshold: memoryMonitor.Threshold,
+			State:     convertWorkspaceAgentMonitorState(memoryMonitor.State),
+			UpdatedAt: memoryMonitor.UpdatedAt,
+		})
+	}
+
+	volumeMonitors, err := api.Database.FetchVolumesResourceMonitorsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for _, monitor := range volumeMonitors {
+		monitors = append(monitors, codersdk.WorkspaceAgentResourceMonitor{
+			Type:      codersdk.WorkspaceAgentResourceMonitorTypeVolume,
+			Path:      monitor.Path,
+			Enabled:   monitor.Enabled,
+			Threshold: monitor.Threshold,
+			State:     convertWorkspaceAgentMonitorState(monitor.State),
+			UpdatedAt: monitor.UpdatedAt,
+		})
+	}
+
+	resourceMonitors, err := api.Database.FetchResourceMonitorsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for _, monitor := range resourceMonitors {
+		monitors = append(monitors, codersdk.WorkspaceAgentResourceMonitor{
+			Type:      codersdk.WorkspaceAgentResourceMonitorType(monitor.Type),
+			Path:      monitor.Path,
+			Enabled:   monitor.Enabled,
+			Threshold: monitor.Threshold,
+			State:     convertWorkspaceAgentMonitorState(monitor.State),
+			UpdatedAt: monitor.UpdatedAt,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentResourceMonitorsResponse{
+		Monitors: monitors,
+	})
+}
+
+func convertWorkspaceAgentMonitorState(state database.WorkspaceAgentMonitorState) codersdk.WorkspaceAgentResourceMonitorState {
+	if state == database.WorkspaceAgentMonitorStateNOK {
+		return codersdk.WorkspaceAgentResourceMonitorStateAlert
+	}
+	return codersdk.WorkspaceAgentResourceMonitorStateOK
+}
+
 // @Summary Get listening ports for workspace agent
 // @ID get-listening-ports-for-workspace-agent
 // @Security CoderSessionToken
@@ -980,6 +1054,243 @@ func (api *API) workspaceAgentRecreateDevcontainer(rw http.ResponseWriter, r *ht
 	httpapi.Write(ctx, rw, http.StatusAccepted, m)
 }
 
+// @Summary Get services for workspace agent
+// @ID get-services-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentServicesResponse
+// @Router /workspaceagents/{workspaceagent}/services [get]
+func (api *API) workspaceAgentServices(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	// The workspace of the agent is authorized by the middleware, services
+	// are only readable by the system.
+	// nolint:gocritic // This is necessary to fetch agent services!
+	services, err := api.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent services.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentServicesResponse{
+		Services: db2sdk.WorkspaceAgentServices(services),
+	})
+}
+
+// @Summary Restart service for workspace agent
+// @ID restart-service-for-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Produce json
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param service path string true "Service name"
+// @Success 202 {object} codersdk.Response
+// @Router /workspaceagents/{workspaceagent}/services/{service}/restart [post]
+func (api *API) workspaceAgentRestartService(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	service := chi.URLParam(r, "service")
+	if service == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Service name is required.",
+			Validations: []codersdk.ValidationError{
+				{Field: "service", Detail: "Service name is required."},
+			},
+		})
+		return
+	}
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		workspaceAgent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(dialCtx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	m, err := agentConn.RestartService(ctx, service)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			httpapi.Write(ctx, rw, http.StatusRequestTimeout, codersdk.Response{
+				Message: "Failed to restart service from agent.",
+				Detail:  "Request timed out.",
+			})
+			return
+		}
+		// If the agent returns a codersdk.Error, we can return that directly.
+		if cerr, ok := codersdk.AsError(err); ok {
+			httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error restarting service.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, m)
+}
+
+// workspaceAgentExec proxies an exec session to the agent for clients that
+// cannot connect to the agent over tailnet. Messages are forwarded unchanged,
+// see workspacesdk.ExecFrameType.
+//
+// @Summary Run command in workspace agent
+// @ID run-command-in-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 101
+// @Router /workspaceagents/{workspaceagent}/exec [get]
+func (api *API) workspaceAgentExec(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Running commands is equivalent to SSH access to the workspace.
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	// Dial the agent before accepting the websocket so that errors can be
+	// returned as responses.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+	agentWS, err := agentConn.DialExec(dialCtx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to start exec session on workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer agentWS.CloseNow()
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	defer api.WebsocketWaitGroup.Done()
+
+	clientWS, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	clientWS.SetReadLimit(workspacesdk.ExecMessageLimit)
+	defer clientWS.CloseNow()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go httpapi.Heartbeat(ctx, clientWS)
+
+	// The session ends when either side closes.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		proxyWebsocketMessages(ctx, agentWS, clientWS)
+	}()
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		proxyWebsocketMessages(ctx, clientWS, agentWS)
+	}()
+	wg.Wait()
+	_ = clientWS.Close(websocket.StatusNormalClosure, "")
+	_ = agentWS.Close(websocket.StatusNormalClosure, "")
+}
+
+// proxyWebsocketMessages copies messages from src to dst until either fails.
+func proxyWebsocketMessages(ctx context.Context, dst, src *websocket.Conn) {
+	for {
+		typ, data, err := src.Read(ctx)
+		if err != nil {
+			return
+		}
+		if err := dst.Write(ctx, typ, data); err != nil {
+			return
+		}
+	}
+}
+
 // @Summary Get connection info for workspace agent
 // @ID get-connection-info-for-workspace-agent
 // @Security CoderSessionToken
@@ -993,6 +1304,7 @@ func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request
 
 	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.AgentConnectionInfo{
 		DERPMap:                  api.DERPMap(),
+		DERPQUICPorts:            api.DERPQUICPorts(),
 		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		HostnameSuffix:           api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
@@ -1015,6 +1327,7 @@ func (api *API) workspaceAgentConnectionGeneric(rw http.ResponseWriter, r *http.
 
 	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.AgentConnectionInfo{
 		DERPMap:                  api.DERPMap(),
+		DERPQUICPorts:            api.DERPQUICPorts(),
 		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		HostnameSuffix:           api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
@@ -40,6 +40,7 @@ import (
 	"github.com/coder/coder/v2/agent/agentcontainers/watcher"
 	"github.com/coder/coder/v2/agent/agenttest"
 	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -621,6 +622,174 @@ func TestWorkspaceAgentTailnet(t *testing.T) {
 	require.Equal(t, "test", strings.TrimSpace(string(output)))
 }
 
+func TestWorkspaceAgentTailnetMultiAgent(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+
+	var agentIDs []uuid.UUID
+	for range 2 {
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+		agentIDs = append(agentIDs, resources[0].Agents[0].ID)
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	multiConn, err := workspacesdk.New(client).DialMultiAgent(ctx, &workspacesdk.DialAgentOptions{
+		Logger: testutil.Logger(t).Named("client"),
+	})
+	require.NoError(t, err)
+	defer multiConn.Close()
+
+	var conns []*workspacesdk.AgentConn
+	for _, agentID := range agentIDs {
+		conn, err := multiConn.DialAgent(ctx, agentID)
+		require.NoError(t, err)
+		conns = append(conns, conn)
+
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		output, err := session.CombinedOutput("echo test")
+		require.NoError(t, err)
+		_ = session.Close()
+		_ = sshClient.Close()
+		require.Equal(t, "test", strings.TrimSpace(string(output)))
+	}
+	require.ElementsMatch(t, agentIDs, multiConn.Agents())
+
+	// Closing one agent leaves the other connected.
+	require.NoError(t, conns[0].Close())
+	require.Equal(t, []uuid.UUID{agentIDs[1]}, multiConn.Agents())
+	_, _, _, err = conns[1].Ping(ctx)
+	require.NoError(t, err)
+
+	// The agent can be dialed again after it was removed.
+	conn, err := multiConn.DialAgent(ctx, agentIDs[0])
+	require.NoError(t, err)
+	_, _, _, err = conn.Ping(ctx)
+	require.NoError(t, err)
+}
+
+func TestWorkspaceAgentExec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The command is POSIX shell specific")
+	}
+
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		var stdout, stderr strings.Builder
+		session, err := workspacesdk.New(client).AgentExec(ctx, agentID, workspacesdk.ExecRequest{
+			Command: "cat; echo err >&2; exit 5",
+		}, &stdout, &stderr)
+		require.NoError(t, err)
+		defer session.Close()
+		_, err = session.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, session.CloseStdin())
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 5, code)
+		require.Equal(t, "hello", stdout.String())
+		require.Equal(t, "err\n", stderr.String())
+
+		// The agent reports the command as a connection with its exit code.
+		require.Eventually(t, func() bool {
+			return auditor.Contains(t, database.AuditLog{
+				Action:       database.AuditActionDisconnect,
+				ResourceType: database.ResourceTypeWorkspaceAgent,
+				ResourceID:   agentID,
+				StatusCode:   5,
+			})
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := workspacesdk.New(otherClient).AgentExec(ctx, agentID, workspacesdk.ExecRequest{
+			Command: "true",
+		}, nil, nil)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
+
+func TestWorkspaceAgentServices(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The command is POSIX shell specific")
+	}
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Services = []*proto.Service{{
+			Name:          "sleeper",
+			Command:       "sleep 300",
+			RestartPolicy: proto.ServiceRestartPolicy_RESTART_ALWAYS,
+		}}
+		return agents
+	}).Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		resp, err := client.WorkspaceAgentServices(ctx, agentID)
+		if !assert.NoError(t, err) || !assert.Len(t, resp.Services, 1) {
+			return false
+		}
+		return resp.Services[0].Status == codersdk.WorkspaceAgentServiceStatusRunning
+	}, testutil.IntervalMedium)
+
+	_, err := client.WorkspaceAgentRestartService(ctx, agentID, "sleeper")
+	require.NoError(t, err)
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		resp, err := client.WorkspaceAgentServices(ctx, agentID)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return resp.Services[0].Restarts == 1 && resp.Services[0].Status == codersdk.WorkspaceAgentServiceStatusRunning
+	}, testutil.IntervalMedium)
+
+	_, err = client.WorkspaceAgentRestartService(ctx, agentID, "unknown")
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+	otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err = otherClient.WorkspaceAgentServices(ctx, agentID)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
 func TestWorkspaceAgentClientCoordinate_BadVersion(t *testing.T) {
 	t.Parallel()
 	client, db := coderdtest.NewWithDatabase(t, nil)
@@ -944,6 +1113,46 @@ func TestWorkspaceAgentTailnetDirectDisabled(t *testing.T) {
 	require.False(t, p2p)
 }
 
+func TestWorkspaceAgentResourceMonitors(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].ResourcesMonitoring = &proto.ResourcesMonitoring{
+			Memory: &proto.MemoryResourceMonitor{Enabled: true, Threshold: 80},
+			Cpu:    &proto.CPUResourceMonitor{Enabled: true, Threshold: 90},
+			Inodes: []*proto.InodesResourceMonitor{
+				{Path: "/home/coder", Enabled: true, Threshold: 70},
+			},
+		}
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	resp, err := client.WorkspaceAgentResourceMonitors(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, resp.Monitors, 3)
+
+	require.Equal(t, codersdk.WorkspaceAgentResourceMonitorTypeMemory, resp.Monitors[0].Type)
+	require.Equal(t, int32(80), resp.Monitors[0].Threshold)
+	require.Equal(t, codersdk.WorkspaceAgentResourceMonitorStateOK, resp.Monitors[0].State)
+
+	require.Equal(t, codersdk.WorkspaceAgentResourceMonitorTypeCPU, resp.Monitors[1].Type)
+	require.Equal(t, int32(90), resp.Monitors[1].Threshold)
+
+	require.Equal(t, codersdk.WorkspaceAgentResourceMonitorTypeInodes, resp.Monitors[2].Type)
+	require.Equal(t, "/home/coder", resp.Monitors[2].Path)
+	require.True(t, resp.Monitors[2].Enabled)
+}
+
 func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	t.Parallel()
 
@@ -2613,7 +2822,7 @@ func requireGetManifest(ctx context.Context, t testing.TB, aAPI agentproto.DRPCA
 }
 
 func postStartup(ctx context.Context, t testing.TB, client agent.Client, startup *agentproto.Startup) error {
-	aAPI, _, err := client.ConnectRPC26(ctx)
+	aAPI, _, err := client.ConnectRPC210(ctx)
 	require.NoError(t, err)
 	defer func() {
 		cErr := aAPI.DRPCConn().Close()
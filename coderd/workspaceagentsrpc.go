@@ -141,6 +141,7 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		Pubsub:                            api.Pubsub,
 		Auditor:                           &api.Auditor,
 		DerpMapFn:                         api.DERPMap,
+		DerpQUICPortsFn:                   api.DERPQUICPorts,
 		TailnetCoordinator:                &api.TailnetCoordinator,
 		AppearanceFetcher:                 &api.AppearanceFetcher,
 		StatsReporter:                     api.statsReporter,
@@ -500,7 +500,7 @@ func createWorkspace(
 	// This is a premature auth check to avoid doing unnecessary work if the user
 	// doesn't have permission to create a workspace.
 	if !api.Authorize(r, policy.ActionCreate,
-		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(owner.ID.String())) {
+		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(owner.ID.String()).InTemplate(template.ID)) {
 		// If this check fails, return a proper unauthorized error to the user to indicate
 		// what is going on.
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
@@ -519,7 +519,7 @@ func createWorkspace(
 	// Do this upfront to save work. If this fails, the rest of the work
 	// would be wasted.
 	if !api.Authorize(r, policy.ActionCreate,
-		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(owner.ID.String())) {
+		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(owner.ID.String()).InTemplate(template.ID)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
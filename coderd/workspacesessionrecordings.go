@@ -0,0 +1,177 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// sessionRecordingChunkBatchSize is the number of chunks fetched at a time
+// when streaming a recording. Agents upload chunks of at most 64KiB.
+const sessionRecordingChunkBatchSize = 16
+
+// @Summary Get workspace session recordings
+// @ID get-workspace-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace_id query string false "Workspace ID" format(uuid)
+// @Param owner_id query string false "Workspace owner ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /workspacesessionrecordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	p := httpapi.NewQueryParamParser()
+	workspaceID := p.UUID(r.URL.Query(), uuid.Nil, "workspace_id")
+	ownerID := p.UUID(r.URL.Query(), uuid.Nil, "owner_id")
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	recordings, err := api.Database.GetWorkspaceSessionRecordings(ctx, database.GetWorkspaceSessionRecordingsParams{
+		WorkspaceID: workspaceID,
+		OwnerID:     ownerID,
+		OffsetOpt:   int32(page.Offset), // #nosec G115 - Pagination offsets are small and fit in int32
+		LimitOpt:    int32(page.Limit),  // #nosec G115 - Pagination limits are small and fit in int32
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.WorkspaceSessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		converted = append(converted, convertWorkspaceSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get workspace session recording in asciicast format
+// @ID get-workspace-session-recording-in-asciicast-format
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200
+// @Router /workspacesessionrecordings/{recording}/cast [get]
+func (api *API) workspaceSessionRecordingCast(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceSessionRecording](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionOpen,
+		})
+	)
+	defer commitAudit()
+
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Replaying a recording is audited even though nothing changes, so
+	// that there is a record of who watched which session.
+	aReq.UpdateOrganizationID(recording.OrganizationID)
+	aReq.Old = recording
+	aReq.New = recording
+
+	// Recordings can be large, so they are streamed in batches of chunks.
+	// Once the first chunk is written errors can no longer be reported to
+	// the client.
+	var (
+		afterSequence int32 = -1
+		wroteHeader   bool
+	)
+	for {
+		chunks, err := api.Database.GetWorkspaceSessionRecordingChunks(ctx, database.GetWorkspaceSessionRecordingChunksParams{
+			RecordingID:   recording.ID,
+			AfterSequence: afterSequence,
+			LimitOpt:      sessionRecordingChunkBatchSize,
+		})
+		if err != nil {
+			if !wroteHeader {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching workspace session recording chunks.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			api.Logger.Warn(ctx, "fetch workspace session recording chunks",
+				slog.F("recording_id", recording.ID),
+				slog.Error(err),
+			)
+			return
+		}
+		if !wroteHeader {
+			rw.Header().Set("Content-Type", "application/x-asciicast")
+			rw.WriteHeader(http.StatusOK)
+			wroteHeader = true
+		}
+		for _, chunk := range chunks {
+			if _, err := rw.Write(chunk.Data); err != nil {
+				return
+			}
+			afterSequence = chunk.Sequence
+		}
+		if len(chunks) < sessionRecordingChunkBatchSize {
+			return
+		}
+	}
+}
+
+func convertWorkspaceSessionRecording(row database.GetWorkspaceSessionRecordingsRow) codersdk.WorkspaceSessionRecording {
+	recording := row.WorkspaceSessionRecording
+	converted := codersdk.WorkspaceSessionRecording{
+		ID:             recording.ID,
+		OrganizationID: recording.OrganizationID,
+		WorkspaceID:    recording.WorkspaceID,
+		WorkspaceName:  row.WorkspaceName,
+		OwnerID:        recording.OwnerID,
+		OwnerUsername:  row.OwnerUsername,
+		AgentID:        recording.AgentID,
+		Type:           codersdk.SessionRecordingType(recording.Type),
+		Width:          recording.Width,
+		Height:         recording.Height,
+		StartedAt:      recording.StartedAt,
+		Size:           recording.Size,
+	}
+	if recording.EndedAt.Valid {
+		converted.EndedAt = ptr.Ref(recording.EndedAt.Time)
+	}
+	return converted
+}
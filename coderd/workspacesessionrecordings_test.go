@@ -0,0 +1,120 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	mockAuditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: mockAuditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	auditor, auditorUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleAuditor())
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).Do()
+
+	recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		OrganizationID: owner.OrganizationID,
+		WorkspaceID:    r.Workspace.ID,
+		OwnerID:        memberUser.ID,
+	})
+	// Enough chunks to require more than one batch when streaming.
+	ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort))
+	var expected []byte
+	for i := int32(0); i < 20; i++ {
+		data := []byte(`[0.1, "o", "hello"]` + "\n")
+		if i == 0 {
+			data = []byte(`{"version": 2, "width": 80, "height": 24}` + "\n")
+		}
+		err := db.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			Sequence:    i,
+			Data:        data,
+			CreatedAt:   dbtime.Now(),
+		})
+		require.NoError(t, err)
+		expected = append(expected, data...)
+	}
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		for _, c := range []*codersdk.Client{client, auditor} {
+			recordings, err := c.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{
+				WorkspaceID: r.Workspace.ID,
+			})
+			require.NoError(t, err)
+			require.Len(t, recordings, 1)
+			require.Equal(t, recording.ID, recordings[0].ID)
+			require.Equal(t, r.Workspace.Name, recordings[0].WorkspaceName)
+			require.Equal(t, memberUser.Username, recordings[0].OwnerUsername)
+			require.Equal(t, codersdk.SessionRecordingTypeSSH, recordings[0].Type)
+		}
+
+		// The workspace owner cannot view recordings of their sessions.
+		recordings, err := member.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{})
+		require.NoError(t, err)
+		require.Empty(t, recordings)
+	})
+
+	t.Run("Cast", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		cast, err := auditor.WorkspaceSessionRecordingCast(ctx, recording.ID)
+		require.NoError(t, err)
+		defer cast.Close()
+		got, err := io.ReadAll(cast)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(got))
+		require.True(t, mockAuditor.Contains(t, database.AuditLog{
+			UserID:         auditorUser.ID,
+			OrganizationID: owner.OrganizationID,
+			ResourceType:   database.ResourceTypeWorkspaceSessionRecording,
+			ResourceID:     recording.ID,
+			Action:         database.AuditActionOpen,
+			StatusCode:     http.StatusOK,
+		}))
+
+		_, err = member.WorkspaceSessionRecordingCast(ctx, recording.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateSetting", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		template, err := client.Template(ctx, r.Workspace.TemplateID)
+		require.NoError(t, err)
+		require.False(t, template.SessionRecordingEnabled)
+
+		template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			SessionRecordingEnabled: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		require.True(t, template.SessionRecordingEnabled)
+	})
+}
@@ -56,6 +56,7 @@ const (
 	WorkspaceEventKindAgentLogsOverflow     WorkspaceEventKind = "agt_logs_overflow"
 	WorkspaceEventKindAgentTimeout          WorkspaceEventKind = "agt_timeout"
 	WorkspaceEventKindAgentAppStatusUpdate  WorkspaceEventKind = "agt_app_status_update"
+	WorkspaceEventKindAgentServiceUpdate    WorkspaceEventKind = "agt_service_update"
 )
 
 func (w *WorkspaceEvent) Validate() error {
@@ -125,6 +125,23 @@ type Manifest struct {
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
 	Devcontainers            []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
+	// Secrets are the secrets of the workspace owner that are delivered to
+	// the workspace as environment variables or files.
+	Secrets []WorkspaceAgentSecret `json:"secrets"`
+	// SessionRecordingEnabled instructs the agent to record the output of
+	// interactive sessions and upload it to coderd.
+	SessionRecordingEnabled bool `json:"session_recording_enabled"`
+	// Services are the long-running processes supervised by the agent.
+	Services []codersdk.WorkspaceAgentService `json:"services"`
+}
+
+// WorkspaceAgentSecret is a secret of the workspace owner. At least one of
+// EnvName and FilePath is set.
+type WorkspaceAgentSecret struct {
+	Name     string `json:"name"`
+	EnvName  string `json:"env_name"`
+	FilePath string `json:"file_path"`
+	Value    string `json:"value"`
 }
 
 type LogSource struct {
@@ -281,6 +298,54 @@ func (c *Client) ConnectRPC26(ctx context.Context) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC27 returns a dRPC client to the Agent API v2.7. It is useful when you want to be
+// maximally compatible with Coderd Release Versions that deliver secrets to agents.
+func (c *Client) ConnectRPC27(ctx context.Context) (
+	proto.DRPCAgentClient27, tailnetproto.DRPCTailnetClient27, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 7))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
+// ConnectRPC28 returns a dRPC client to the Agent API v2.8. It is useful when you want to be
+// maximally compatible with Coderd Release Versions that record sessions.
+func (c *Client) ConnectRPC28(ctx context.Context) (
+	proto.DRPCAgentClient28, tailnetproto.DRPCTailnetClient28, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 8))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
+// ConnectRPC29 returns a dRPC client to the Agent API v2.9. It is useful when you want to be
+// maximally compatible with Coderd Release Versions that monitor CPU, load, inodes and processes.
+func (c *Client) ConnectRPC29(ctx context.Context) (
+	proto.DRPCAgentClient29, tailnetproto.DRPCTailnetClient29, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 9))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
+// ConnectRPC210 returns a dRPC client to the Agent API v2.10. It is useful when you want to be
+// maximally compatible with Coderd Release Versions that supervise services.
+func (c *Client) ConnectRPC210(ctx context.Context) (
+	proto.DRPCAgentClient210, tailnetproto.DRPCTailnetClient210, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 10))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API
 func (c *Client) ConnectRPC(ctx context.Context) (drpc.Conn, error) {
 	return c.connectRPCVersion(ctx, proto.CurrentVersion)
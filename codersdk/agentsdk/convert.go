@@ -35,6 +35,10 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 	if err != nil {
 		return Manifest{}, xerrors.Errorf("error converting workspace agent devcontainers: %w", err)
 	}
+	services, err := AgentServicesFromProto(manifest.Services)
+	if err != nil {
+		return Manifest{}, xerrors.Errorf("error converting workspace agent services: %w", err)
+	}
 	return Manifest{
 		AgentID:                  agentID,
 		AgentName:                manifest.AgentName,
@@ -53,6 +57,9 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
 		Devcontainers:            devcontainers,
+		Secrets:                  SecretsFromProto(manifest.Secrets),
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
+		Services:                 services,
 	}, nil
 }
 
@@ -80,6 +87,9 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		Apps:                     apps,
 		Metadata:                 ProtoFromMetadataDescriptions(manifest.Metadata),
 		Devcontainers:            ProtoFromDevcontainers(manifest.Devcontainers),
+		Secrets:                  ProtoFromSecrets(manifest.Secrets),
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
+		Services:                 ProtoFromServices(manifest.Services),
 	}, nil
 }
 
@@ -204,6 +214,117 @@ func ProtoFromScript(s codersdk.WorkspaceAgentScript) *proto.WorkspaceAgentScrip
 	}
 }
 
+func AgentServicesFromProto(protoServices []*proto.WorkspaceAgentService) ([]codersdk.WorkspaceAgentService, error) {
+	ret := make([]codersdk.WorkspaceAgentService, len(protoServices))
+	for i, protoService := range protoServices {
+		service, err := AgentServiceFromProto(protoService)
+		if err != nil {
+			return nil, xerrors.Errorf("parse service %v: %w", i, err)
+		}
+		ret[i] = service
+	}
+	return ret, nil
+}
+
+func ProtoFromServices(services []codersdk.WorkspaceAgentService) []*proto.WorkspaceAgentService {
+	ret := make([]*proto.WorkspaceAgentService, len(services))
+	for i, service := range services {
+		ret[i] = ProtoFromService(service)
+	}
+	return ret
+}
+
+func AgentServiceFromProto(protoService *proto.WorkspaceAgentService) (codersdk.WorkspaceAgentService, error) {
+	id, err := uuid.FromBytes(protoService.Id)
+	if err != nil {
+		return codersdk.WorkspaceAgentService{}, xerrors.Errorf("parse id: %w", err)
+	}
+
+	logSourceID, err := uuid.FromBytes(protoService.LogSourceId)
+	if err != nil {
+		return codersdk.WorkspaceAgentService{}, xerrors.Errorf("parse log source id: %w", err)
+	}
+
+	var restartPolicy codersdk.WorkspaceAgentServiceRestartPolicy
+	switch protoService.RestartPolicy {
+	case proto.WorkspaceAgentService_ON_FAILURE:
+		restartPolicy = codersdk.WorkspaceAgentServiceRestartPolicyOnFailure
+	case proto.WorkspaceAgentService_NEVER:
+		restartPolicy = codersdk.WorkspaceAgentServiceRestartPolicyNever
+	default:
+		restartPolicy = codersdk.WorkspaceAgentServiceRestartPolicyAlways
+	}
+
+	service := codersdk.WorkspaceAgentService{
+		ID:            id,
+		LogSourceID:   logSourceID,
+		LogPath:       protoService.LogPath,
+		Name:          protoService.Name,
+		DisplayName:   protoService.DisplayName,
+		Command:       protoService.Command,
+		RestartPolicy: restartPolicy,
+		RestartDelay:  protoService.RestartDelay.AsDuration(),
+		Status:        codersdk.WorkspaceAgentServiceStatusPending,
+		Health:        codersdk.WorkspaceAppHealthDisabled,
+	}
+	if probe := protoService.ReadinessProbe; probe != nil {
+		service.ReadinessProbe = codersdk.Healthcheck{
+			URL:       probe.Url,
+			Interval:  int32(probe.Interval.AsDuration().Seconds()),
+			Threshold: probe.Threshold,
+		}
+		service.Health = codersdk.WorkspaceAppHealthInitializing
+	}
+	return service, nil
+}
+
+func ProtoFromService(s codersdk.WorkspaceAgentService) *proto.WorkspaceAgentService {
+	var restartPolicy proto.WorkspaceAgentService_RestartPolicy
+	switch s.RestartPolicy {
+	case codersdk.WorkspaceAgentServiceRestartPolicyOnFailure:
+		restartPolicy = proto.WorkspaceAgentService_ON_FAILURE
+	case codersdk.WorkspaceAgentServiceRestartPolicyNever:
+		restartPolicy = proto.WorkspaceAgentService_NEVER
+	default:
+		restartPolicy = proto.WorkspaceAgentService_ALWAYS
+	}
+
+	service := &proto.WorkspaceAgentService{
+		Id:            s.ID[:],
+		LogSourceId:   s.LogSourceID[:],
+		LogPath:       s.LogPath,
+		Name:          s.Name,
+		DisplayName:   s.DisplayName,
+		Command:       s.Command,
+		RestartPolicy: restartPolicy,
+		RestartDelay:  durationpb.New(s.RestartDelay),
+	}
+	if s.ReadinessProbe.URL != "" {
+		service.ReadinessProbe = &proto.WorkspaceAgentService_ReadinessProbe{
+			Url:       s.ReadinessProbe.URL,
+			Interval:  durationpb.New(time.Duration(s.ReadinessProbe.Interval) * time.Second),
+			Threshold: s.ReadinessProbe.Threshold,
+		}
+	}
+	return service
+}
+
+func ProtoFromServiceStatus(s codersdk.WorkspaceAgentServiceStatus) (proto.ServiceStatus, error) {
+	caps, ok := proto.ServiceStatus_value[strings.ToUpper(string(s))]
+	if !ok {
+		return 0, xerrors.Errorf("unknown service status: %s", s)
+	}
+	return proto.ServiceStatus(caps), nil
+}
+
+func ProtoFromAppHealth(h codersdk.WorkspaceAppHealth) (proto.AppHealth, error) {
+	caps, ok := proto.AppHealth_value[strings.ToUpper(string(h))]
+	if !ok {
+		return 0, xerrors.Errorf("unknown app health: %s", h)
+	}
+	return proto.AppHealth(caps), nil
+}
+
 func AppsFromProto(protoApps []*proto.WorkspaceApp) ([]codersdk.WorkspaceApp, error) {
 	ret := make([]codersdk.WorkspaceApp, len(protoApps))
 	for i, protoApp := range protoApps {
@@ -473,3 +594,29 @@ func ProtoFromDevcontainer(dc codersdk.WorkspaceAgentDevcontainer) *proto.Worksp
 		ConfigPath:      dc.ConfigPath,
 	}
 }
+
+func SecretsFromProto(pss []*proto.WorkspaceAgentSecret) []WorkspaceAgentSecret {
+	ret := make([]WorkspaceAgentSecret, len(pss))
+	for i, ps := range pss {
+		ret[i] = WorkspaceAgentSecret{
+			Name:     ps.Name,
+			EnvName:  ps.EnvName,
+			FilePath: ps.FilePath,
+			Value:    ps.Value,
+		}
+	}
+	return ret
+}
+
+func ProtoFromSecrets(secrets []WorkspaceAgentSecret) []*proto.WorkspaceAgentSecret {
+	ret := make([]*proto.WorkspaceAgentSecret, len(secrets))
+	for i, secret := range secrets {
+		ret[i] = &proto.WorkspaceAgentSecret{
+			Name:     secret.Name,
+			EnvName:  secret.EnvName,
+			FilePath: secret.FilePath,
+			Value:    secret.Value,
+		}
+	}
+	return ret
+}
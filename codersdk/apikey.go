@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 // APIKey: do not ever return the HashedSecret
@@ -18,10 +21,14 @@ type APIKey struct {
 	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,ldap,saml,token"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,custom"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// ScopePermissions and ScopeAllowList are only set for keys with the
+	// custom scope.
+	ScopePermissions []string `json:"scope_permissions,omitempty"`
+	ScopeAllowList   []string `json:"scope_allow_list,omitempty"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -32,6 +39,8 @@ const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeLDAP     LoginType = "ldap"
+	LoginTypeSAML     LoginType = "saml"
 	LoginTypeToken    LoginType = "token"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
@@ -48,12 +57,89 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeCustom is a scope that only allows the permissions listed
+	// in ScopePermissions, optionally limited to the objects in
+	// ScopeAllowList.
+	APIKeyScopeCustom APIKeyScope = "custom"
 )
 
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,custom"`
 	TokenName string        `json:"token_name"`
+	// ScopePermissions is required with the custom scope. Each element is
+	// "<resource>:<action>", e.g. "workspace:start".
+	ScopePermissions []string `json:"scope_permissions,omitempty"`
+	// ScopeAllowList limits a custom scope to the listed objects. Each
+	// element is "<resource>:<id>", e.g. "template:<uuid>". Workspaces
+	// of an allowed template and resources of an allowed organization are
+	// allowed too.
+	ScopeAllowList []string `json:"scope_allow_list,omitempty"`
+}
+
+// ParseAPIKeyScopePermissions parses permissions of the form
+// "<resource>:<action>[,<action>...]" into one "<resource>:<action>" element
+// per action. An element without a resource adds an action to the previous
+// element, so "workspace:start,stop" also parses after being split on commas.
+// Both the resource and the action may be "*".
+func ParseAPIKeyScopePermissions(values []string) ([]string, error) {
+	var (
+		perms    []string
+		resource RBACResource
+	)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		actions := value
+		if res, rest, ok := strings.Cut(value, ":"); ok {
+			resource = RBACResource(res)
+			actions = rest
+			if _, ok := RBACResourceActions[resource]; !ok {
+				return nil, xerrors.Errorf("unknown resource %q", res)
+			}
+			if resource == ResourceWildcard && actions != "*" {
+				return nil, xerrors.Errorf("the %q resource only supports the %q action", ResourceWildcard, "*")
+			}
+		} else if resource == "" {
+			return nil, xerrors.Errorf("permission %q must be of the form <resource>:<action>", value)
+		}
+		for _, action := range strings.Split(actions, ",") {
+			action = strings.TrimSpace(action)
+			if action != "*" && !slices.Contains(RBACResourceActions[resource], RBACAction(action)) {
+				return nil, xerrors.Errorf("invalid action %q for resource %q", action, resource)
+			}
+			perms = append(perms, string(resource)+":"+action)
+		}
+	}
+	return perms, nil
+}
+
+// ParseAPIKeyScopeAllowList validates allow-list elements of the form
+// "<resource>:<id>". The id must be a UUID or "*".
+func ParseAPIKeyScopeAllowList(values []string) ([]string, error) {
+	var list []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		res, id, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, xerrors.Errorf("allow list element %q must be of the form <resource>:<id>", value)
+		}
+		if _, ok := RBACResourceActions[RBACResource(res)]; !ok || RBACResource(res) == ResourceWildcard {
+			return nil, xerrors.Errorf("unknown resource %q", res)
+		}
+		if id != "*" {
+			if _, err := uuid.Parse(id); err != nil {
+				return nil, xerrors.Errorf("invalid id %q for resource %q: %w", id, res, err)
+			}
+		}
+		list = append(list, value)
+	}
+	return list, nil
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -39,6 +39,9 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeWebAuthnCredential          ResourceType = "webauthn_credential"
+	ResourceTypeUserSecret                  ResourceType = "user_secret"
+	ResourceTypeWorkspaceSessionRecording   ResourceType = "workspace_session_recording"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -93,6 +96,12 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace agent"
 	case ResourceTypeWorkspaceApp:
 		return "workspace app"
+	case ResourceTypeWebAuthnCredential:
+		return "passkey"
+	case ResourceTypeUserSecret:
+		return "user secret"
+	case ResourceTypeWorkspaceSessionRecording:
+		return "workspace session recording"
 	default:
 		return "unknown"
 	}
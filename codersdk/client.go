@@ -38,6 +38,9 @@ const (
 	OAuth2StateCookie = "oauth_state"
 	// OAuth2RedirectCookie is the name of the cookie that stores the oauth2 redirect.
 	OAuth2RedirectCookie = "oauth_redirect"
+	// SAMLRequestCookie is the name of the cookie that stores the ID of the
+	// pending SAML authentication request.
+	SAMLRequestCookie = "saml_request"
 
 	// PathAppSessionTokenCookie is the name of the cookie that stores an
 	// application-scoped API token on workspace proxy path app domains.
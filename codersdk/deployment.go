@@ -343,62 +343,67 @@ type DeploymentValues struct {
 	DocsURL             serpent.URL    `json:"docs_url,omitempty"`
 	RedirectToAccessURL serpent.Bool   `json:"redirect_to_access_url,omitempty"`
 	// HTTPAddress is a string because it may be set to zero to disable.
-	HTTPAddress                     serpent.String                       `json:"http_address,omitempty" typescript:",notnull"`
-	AutobuildPollInterval           serpent.Duration                     `json:"autobuild_poll_interval,omitempty"`
-	JobReaperDetectorInterval       serpent.Duration                     `json:"job_hang_detector_interval,omitempty"`
-	DERP                            DERP                                 `json:"derp,omitempty" typescript:",notnull"`
-	Prometheus                      PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
-	Pprof                           PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
-	ProxyTrustedHeaders             serpent.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
-	ProxyTrustedOrigins             serpent.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
-	CacheDir                        serpent.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
-	InMemoryDatabase                serpent.Bool                         `json:"in_memory_database,omitempty" typescript:",notnull"`
-	EphemeralDeployment             serpent.Bool                         `json:"ephemeral_deployment,omitempty" typescript:",notnull"`
-	PostgresURL                     serpent.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
-	PostgresAuth                    string                               `json:"pg_auth,omitempty" typescript:",notnull"`
-	OAuth2                          OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
-	OIDC                            OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
-	Telemetry                       TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
-	TLS                             TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
-	Trace                           TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
-	HTTPCookies                     HTTPCookieConfig                     `json:"http_cookies,omitempty" typescript:",notnull"`
-	StrictTransportSecurity         serpent.Int64                        `json:"strict_transport_security,omitempty" typescript:",notnull"`
-	StrictTransportSecurityOptions  serpent.StringArray                  `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
-	SSHKeygenAlgorithm              serpent.String                       `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
-	MetricsCacheRefreshInterval     serpent.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
-	AgentStatRefreshInterval        serpent.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
-	AgentFallbackTroubleshootingURL serpent.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
-	BrowserOnly                     serpent.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
-	SCIMAPIKey                      serpent.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
-	ExternalTokenEncryptionKeys     serpent.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
-	Provisioner                     ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
-	RateLimit                       RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
-	Experiments                     serpent.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
-	UpdateCheck                     serpent.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
-	Swagger                         SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
-	Logging                         LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
-	Dangerous                       DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
-	DisablePathApps                 serpent.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
-	Sessions                        SessionLifetime                      `json:"session_lifetime,omitempty" typescript:",notnull"`
-	DisablePasswordAuth             serpent.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
-	Support                         SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
-	ExternalAuthConfigs             serpent.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
-	AI                              serpent.Struct[AIConfig]             `json:"ai,omitempty" typescript:",notnull"`
-	SSHConfig                       SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
-	WgtunnelHost                    serpent.String                       `json:"wgtunnel_host,omitempty" typescript:",notnull"`
-	DisableOwnerWorkspaceExec       serpent.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
-	ProxyHealthStatusInterval       serpent.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
-	EnableTerraformDebugMode        serpent.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
-	UserQuietHoursSchedule          UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
-	WebTerminalRenderer             serpent.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
-	AllowWorkspaceRenames           serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
-	Healthcheck                     HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
-	CLIUpgradeMessage               serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
-	TermsOfServiceURL               serpent.String                       `json:"terms_of_service_url,omitempty" typescript:",notnull"`
-	Notifications                   NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
-	AdditionalCSPPolicy             serpent.StringArray                  `json:"additional_csp_policy,omitempty" typescript:",notnull"`
-	WorkspaceHostnameSuffix         serpent.String                       `json:"workspace_hostname_suffix,omitempty" typescript:",notnull"`
-	Prebuilds                       PrebuildsConfig                      `json:"workspace_prebuilds,omitempty" typescript:",notnull"`
+	HTTPAddress                         serpent.String                       `json:"http_address,omitempty" typescript:",notnull"`
+	AutobuildPollInterval               serpent.Duration                     `json:"autobuild_poll_interval,omitempty"`
+	JobReaperDetectorInterval           serpent.Duration                     `json:"job_hang_detector_interval,omitempty"`
+	DERP                                DERP                                 `json:"derp,omitempty" typescript:",notnull"`
+	Prometheus                          PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
+	Pprof                               PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
+	ProxyTrustedHeaders                 serpent.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
+	ProxyTrustedOrigins                 serpent.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
+	CacheDir                            serpent.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
+	InMemoryDatabase                    serpent.Bool                         `json:"in_memory_database,omitempty" typescript:",notnull"`
+	EphemeralDeployment                 serpent.Bool                         `json:"ephemeral_deployment,omitempty" typescript:",notnull"`
+	PostgresURL                         serpent.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
+	PostgresAuth                        string                               `json:"pg_auth,omitempty" typescript:",notnull"`
+	OAuth2                              OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
+	OIDC                                OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
+	LDAP                                LDAPConfig                           `json:"ldap,omitempty" typescript:",notnull"`
+	SAML                                SAMLConfig                           `json:"saml,omitempty" typescript:",notnull"`
+	Telemetry                           TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
+	TLS                                 TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
+	Trace                               TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
+	HTTPCookies                         HTTPCookieConfig                     `json:"http_cookies,omitempty" typescript:",notnull"`
+	StrictTransportSecurity             serpent.Int64                        `json:"strict_transport_security,omitempty" typescript:",notnull"`
+	StrictTransportSecurityOptions      serpent.StringArray                  `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
+	SSHKeygenAlgorithm                  serpent.String                       `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
+	MetricsCacheRefreshInterval         serpent.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
+	AgentStatRefreshInterval            serpent.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
+	AgentFallbackTroubleshootingURL     serpent.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	BrowserOnly                         serpent.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
+	SCIMAPIKey                          serpent.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKeys         serpent.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKeyProviders serpent.StringArray                  `json:"external_token_encryption_key_providers,omitempty" typescript:",notnull"`
+	Provisioner                         ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
+	RateLimit                           RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
+	Experiments                         serpent.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
+	UpdateCheck                         serpent.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
+	Swagger                             SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
+	Logging                             LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
+	Dangerous                           DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
+	DisablePathApps                     serpent.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
+	Sessions                            SessionLifetime                      `json:"session_lifetime,omitempty" typescript:",notnull"`
+	DisablePasswordAuth                 serpent.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	RequireMFA                          serpent.Bool                         `json:"require_mfa,omitempty" typescript:",notnull"`
+	Support                             SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
+	ExternalAuthConfigs                 serpent.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
+	AI                                  serpent.Struct[AIConfig]             `json:"ai,omitempty" typescript:",notnull"`
+	SSHConfig                           SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
+	WgtunnelHost                        serpent.String                       `json:"wgtunnel_host,omitempty" typescript:",notnull"`
+	DisableOwnerWorkspaceExec           serpent.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	ProxyHealthStatusInterval           serpent.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	EnableTerraformDebugMode            serpent.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
+	UserQuietHoursSchedule              UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	WebTerminalRenderer                 serpent.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
+	AllowWorkspaceRenames               serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
+	Healthcheck                         HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
+	CLIUpgradeMessage                   serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
+	TermsOfServiceURL                   serpent.String                       `json:"terms_of_service_url,omitempty" typescript:",notnull"`
+	Notifications                       NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
+	AdditionalCSPPolicy                 serpent.StringArray                  `json:"additional_csp_policy,omitempty" typescript:",notnull"`
+	WorkspaceHostnameSuffix             serpent.String                       `json:"workspace_hostname_suffix,omitempty" typescript:",notnull"`
+	Prebuilds                           PrebuildsConfig                      `json:"workspace_prebuilds,omitempty" typescript:",notnull"`
+	SessionRecordingRetention           serpent.Duration                     `json:"session_recording_retention,omitempty" typescript:",notnull"`
 
 	Config      serpent.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig serpent.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -482,6 +487,7 @@ type DERPServerConfig struct {
 	RegionName    serpent.String      `json:"region_name" typescript:",notnull"`
 	STUNAddresses serpent.StringArray `json:"stun_addresses" typescript:",notnull"`
 	RelayURL      serpent.URL         `json:"relay_url" typescript:",notnull"`
+	QUICAddress   serpent.String      `json:"quic_address" typescript:",notnull"`
 }
 
 type DERPConfig struct {
@@ -505,7 +511,34 @@ type PprofConfig struct {
 }
 
 type OAuth2Config struct {
-	Github OAuth2GithubConfig `json:"github" typescript:",notnull"`
+	Github   OAuth2GithubConfig   `json:"github" typescript:",notnull"`
+	Provider OAuth2ProviderConfig `json:"provider" typescript:",notnull"`
+}
+
+// OAuth2ProviderConfig configures Coder acting as an OAuth2 provider.
+type OAuth2ProviderConfig struct {
+	DynamicRegistration                     string              `json:"dynamic_registration" typescript:",notnull"`
+	DynamicRegistrationAllowedRedirectHosts serpent.StringArray `json:"dynamic_registration_allowed_redirect_hosts" typescript:",notnull"`
+	DynamicRegistrationRateLimit            serpent.Int64       `json:"dynamic_registration_rate_limit" typescript:",notnull"`
+	DynamicRegistrationUnusedClientLifetime serpent.Duration    `json:"dynamic_registration_unused_client_lifetime" typescript:",notnull"`
+}
+
+// OAuth2ProviderDynamicRegistration controls who can register OAuth2 clients
+// without an administrator, as described in RFC 7591.
+type OAuth2ProviderDynamicRegistration string
+
+const (
+	OAuth2ProviderDynamicRegistrationDisabled OAuth2ProviderDynamicRegistration = "disabled"
+	// OAuth2ProviderDynamicRegistrationAuthenticated requires a Coder session
+	// token as the initial access token.
+	OAuth2ProviderDynamicRegistrationAuthenticated OAuth2ProviderDynamicRegistration = "authenticated"
+	OAuth2ProviderDynamicRegistrationOpen          OAuth2ProviderDynamicRegistration = "open"
+)
+
+var OAuth2ProviderDynamicRegistrationModes = []string{
+	string(OAuth2ProviderDynamicRegistrationDisabled),
+	string(OAuth2ProviderDynamicRegistrationAuthenticated),
+	string(OAuth2ProviderDynamicRegistrationOpen),
 }
 
 type OAuth2GithubConfig struct {
@@ -563,6 +596,44 @@ type OIDCConfig struct {
 	SkipIssuerChecks          serpent.Bool                           `json:"skip_issuer_checks" typescript:",notnull"`
 }
 
+type LDAPConfig struct {
+	URL                serpent.String      `json:"url" typescript:",notnull"`
+	StartTLS           serpent.Bool        `json:"start_tls" typescript:",notnull"`
+	InsecureSkipVerify serpent.Bool        `json:"insecure_skip_verify" typescript:",notnull"`
+	CAFile             serpent.String      `json:"ca_file" typescript:",notnull"`
+	BindDN             serpent.String      `json:"bind_dn" typescript:",notnull"`
+	BindPassword       serpent.String      `json:"bind_password" typescript:",notnull"`
+	UserSearchBaseDN   serpent.String      `json:"user_search_base_dn" typescript:",notnull"`
+	UserSearchFilter   serpent.String      `json:"user_search_filter" typescript:",notnull"`
+	IDAttribute        serpent.String      `json:"id_attribute" typescript:",notnull"`
+	UsernameAttribute  serpent.String      `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute     serpent.String      `json:"email_attribute" typescript:",notnull"`
+	NameAttribute      serpent.String      `json:"name_attribute" typescript:",notnull"`
+	GroupSearchBaseDN  serpent.String      `json:"group_search_base_dn" typescript:",notnull"`
+	GroupSearchFilter  serpent.String      `json:"group_search_filter" typescript:",notnull"`
+	GroupNameAttribute serpent.String      `json:"group_name_attribute" typescript:",notnull"`
+	AllowSignups       serpent.Bool        `json:"allow_signups" typescript:",notnull"`
+	EmailDomain        serpent.StringArray `json:"email_domain" typescript:",notnull"`
+	SignInText         serpent.String      `json:"sign_in_text" typescript:",notnull"`
+}
+
+type SAMLConfig struct {
+	IDPMetadataURL    serpent.URL         `json:"idp_metadata_url" typescript:",notnull"`
+	IDPMetadataFile   serpent.String      `json:"idp_metadata_file" typescript:",notnull"`
+	EntityID          serpent.String      `json:"entity_id" typescript:",notnull"`
+	CertFile          serpent.String      `json:"cert_file" typescript:",notnull"`
+	KeyFile           serpent.String      `json:"key_file" typescript:",notnull"`
+	SignRequests      serpent.Bool        `json:"sign_requests" typescript:",notnull"`
+	AllowIDPInitiated serpent.Bool        `json:"allow_idp_initiated" typescript:",notnull"`
+	AllowSignups      serpent.Bool        `json:"allow_signups" typescript:",notnull"`
+	EmailDomain       serpent.StringArray `json:"email_domain" typescript:",notnull"`
+	EmailAttribute    serpent.String      `json:"email_attribute" typescript:",notnull"`
+	UsernameAttribute serpent.String      `json:"username_attribute" typescript:",notnull"`
+	NameAttribute     serpent.String      `json:"name_attribute" typescript:",notnull"`
+	SignInText        serpent.String      `json:"sign_in_text" typescript:",notnull"`
+	IconURL           serpent.URL         `json:"icon_url" typescript:",notnull"`
+}
+
 type TelemetryConfig struct {
 	Enable serpent.Bool `json:"enable" typescript:",notnull"`
 	Trace  serpent.Bool `json:"trace" typescript:",notnull"`
@@ -965,10 +1036,26 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Name:   "GitHub",
 			YAML:   "github",
 		}
+		deploymentGroupOAuth2Provider = serpent.Group{
+			Parent:      &deploymentGroupOAuth2,
+			Name:        "Provider",
+			Description: "Configure Coder as an OAuth2 provider for other applications.",
+			YAML:        "provider",
+		}
 		deploymentGroupOIDC = serpent.Group{
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupLDAP = serpent.Group{
+			Name:        "LDAP",
+			Description: "Configure login and user-provisioning with an LDAP or Active Directory server.",
+			YAML:        "ldap",
+		}
+		deploymentGroupSAML = serpent.Group{
+			Name:        "SAML",
+			Description: "Configure login and user-provisioning with a SAML 2.0 identity provider. Organization, group and role sync use the OIDC sync settings, matched against the names of assertion attributes.",
+			YAML:        "saml",
+		}
 		deploymentGroupTelemetry = serpent.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -1507,6 +1594,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 				Mark(annotationEnterpriseKey, "true").
 				Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "DERP Server QUIC Address",
+			Description: "UDP address to serve DERP over QUIC on, e.g. \":4443\". This is an optional transport: clients that can reach it over UDP relay traffic over QUIC instead of TCP, and clients that cannot, for example because UDP is blocked, keep using TCP or WebSockets. Requires TLS to be enabled. Leave empty to disable.",
+			Flag:        "derp-server-quic-address",
+			Env:         "CODER_DERP_SERVER_QUIC_ADDRESS",
+			Value:       &c.DERP.Server.QUICAddress,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "quicAddress",
+			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
+		},
 		{
 			Name:        "Block Direct Connections",
 			Description: "Block peer-to-peer (aka. direct) workspace connections. All workspace connections from the CLI will be proxied through Coder (or custom configured DERP servers) and will never be peer-to-peer when enabled. Workspaces may still reach out to STUN servers to get their address until they are restarted after this change has been made, but new connections will still be proxied regardless.",
@@ -1715,6 +1812,46 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupOAuth2GitHub,
 			YAML:        "enterpriseBaseURL",
 		},
+		// OAuth2 provider settings.
+		{
+			Name:        "OAuth2 Provider Dynamic Registration",
+			Description: "Whether OAuth2 clients can register themselves using RFC 7591 dynamic client registration. \"authenticated\" requires a Coder session token as the initial access token, \"open\" allows anyone to register. Registration is rate limited, and clients that are never granted a token are deleted after a while.",
+			Flag:        "oauth2-provider-dynamic-registration",
+			Env:         "CODER_OAUTH2_PROVIDER_DYNAMIC_REGISTRATION",
+			Default:     string(OAuth2ProviderDynamicRegistrationDisabled),
+			Value:       serpent.EnumOf(&c.OAuth2.Provider.DynamicRegistration, OAuth2ProviderDynamicRegistrationModes...),
+			Group:       &deploymentGroupOAuth2Provider,
+			YAML:        "dynamicRegistration",
+		},
+		{
+			Name:        "OAuth2 Provider Dynamic Registration Allowed Redirect Hosts",
+			Description: "Hosts that dynamically registered OAuth2 clients may redirect to. Wildcards like \"*.example.com\" are supported. If empty, any host is allowed.",
+			Flag:        "oauth2-provider-dynamic-registration-allowed-redirect-hosts",
+			Env:         "CODER_OAUTH2_PROVIDER_DYNAMIC_REGISTRATION_ALLOWED_REDIRECT_HOSTS",
+			Value:       &c.OAuth2.Provider.DynamicRegistrationAllowedRedirectHosts,
+			Group:       &deploymentGroupOAuth2Provider,
+			YAML:        "dynamicRegistrationAllowedRedirectHosts",
+		},
+		{
+			Name:        "OAuth2 Provider Dynamic Registration Rate Limit",
+			Description: "Maximum number of OAuth2 clients that can be dynamically registered per hour per IP address. Negative values mean no rate limit.",
+			Flag:        "oauth2-provider-dynamic-registration-rate-limit",
+			Env:         "CODER_OAUTH2_PROVIDER_DYNAMIC_REGISTRATION_RATE_LIMIT",
+			Default:     "60",
+			Value:       &c.OAuth2.Provider.DynamicRegistrationRateLimit,
+			Group:       &deploymentGroupOAuth2Provider,
+			YAML:        "dynamicRegistrationRateLimit",
+		},
+		{
+			Name:        "OAuth2 Provider Dynamic Registration Unused Client Lifetime",
+			Description: "How long dynamically registered OAuth2 clients are kept if they are never granted a token. Set to 0 to keep unused clients forever.",
+			Flag:        "oauth2-provider-dynamic-registration-unused-client-lifetime",
+			Env:         "CODER_OAUTH2_PROVIDER_DYNAMIC_REGISTRATION_UNUSED_CLIENT_LIFETIME",
+			Default:     (24 * time.Hour).String(),
+			Value:       &c.OAuth2.Provider.DynamicRegistrationUnusedClientLifetime,
+			Group:       &deploymentGroupOAuth2Provider,
+			YAML:        "dynamicRegistrationUnusedClientLifetime",
+		},
 		// OIDC settings.
 		{
 			Name:        "OIDC Allow Signups",
@@ -2032,6 +2169,314 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group: &deploymentGroupOIDC,
 			YAML:  "dangerousSkipIssuerChecks",
 		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP server, e.g. ldaps://ldap.example.com:636. Setting this enables login with LDAP.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade plaintext ldap:// connections with StartTLS before sending credentials.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Default:     "false",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP Insecure Skip Verify",
+			Description: "Skip verification of the LDAP server's TLS certificate. This is not recommended.",
+			Flag:        "ldap-insecure-skip-verify",
+			Env:         "CODER_LDAP_INSECURE_SKIP_VERIFY",
+			Default:     "false",
+			Value:       &c.LDAP.InsecureSkipVerify,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "insecureSkipVerify",
+		},
+		{
+			Name:        "LDAP CA File",
+			Description: "Path to a PEM encoded CA certificate used to verify the LDAP server's TLS certificate. The system pool is used if unset.",
+			Flag:        "ldap-ca-file",
+			Env:         "CODER_LDAP_CA_FILE",
+			Value:       &c.LDAP.CAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "caFile",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN of the service account used to search for users and groups. An anonymous bind is used if unset.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the service account used to search for users and groups.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP User Search Base DN",
+			Description: "Base DN to search for user entries.",
+			Flag:        "ldap-user-search-base-dn",
+			Env:         "CODER_LDAP_USER_SEARCH_BASE_DN",
+			Value:       &c.LDAP.UserSearchBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userSearchBaseDN",
+		},
+		{
+			Name:        "LDAP User Search Filter",
+			Description: "Filter used to find the user entry. Every %s is replaced with the escaped username. For Active Directory, use (sAMAccountName=%s).",
+			Flag:        "ldap-user-search-filter",
+			Env:         "CODER_LDAP_USER_SEARCH_FILTER",
+			Default:     "(uid=%s)",
+			Value:       &c.LDAP.UserSearchFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userSearchFilter",
+		},
+		{
+			Name:        "LDAP ID Attribute",
+			Description: "Attribute holding a stable, unique identifier for the user entry, such as entryUUID. The entry DN is used if unset.",
+			Flag:        "ldap-id-attribute",
+			Env:         "CODER_LDAP_ID_ATTRIBUTE",
+			Value:       &c.LDAP.IDAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "idAttribute",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "Attribute of the user entry to use as the username.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "Attribute of the user entry to use as the email.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Name Attribute",
+			Description: "Attribute of the user entry to use as the name.",
+			Flag:        "ldap-name-attribute",
+			Env:         "CODER_LDAP_NAME_ATTRIBUTE",
+			Default:     "cn",
+			Value:       &c.LDAP.NameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "nameAttribute",
+		},
+		{
+			Name: "LDAP Group Search Base DN",
+			Description: "Base DN to search for the groups of a user. If set, the names of the matching groups are provided to group and role sync " +
+				"in the 'groups' claim. Attributes of the user entry, such as memberOf, are always available to sync.",
+			Flag:  "ldap-group-search-base-dn",
+			Env:   "CODER_LDAP_GROUP_SEARCH_BASE_DN",
+			Value: &c.LDAP.GroupSearchBaseDN,
+			Group: &deploymentGroupLDAP,
+			YAML:  "groupSearchBaseDN",
+		},
+		{
+			Name:        "LDAP Group Search Filter",
+			Description: "Filter used to find the groups of a user. Every %s is replaced with the escaped DN of the user entry.",
+			Flag:        "ldap-group-search-filter",
+			Env:         "CODER_LDAP_GROUP_SEARCH_FILTER",
+			Default:     "(member=%s)",
+			Value:       &c.LDAP.GroupSearchFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupSearchFilter",
+		},
+		{
+			Name:        "LDAP Group Name Attribute",
+			Description: "Attribute of the group entry to use as the group name.",
+			Flag:        "ldap-group-name-attribute",
+			Env:         "CODER_LDAP_GROUP_NAME_ATTRIBUTE",
+			Default:     "cn",
+			Value:       &c.LDAP.GroupNameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupNameAttribute",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users are created the first time they log in with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP Email Domain",
+			Description: "Email domains that users logging in with LDAP must match.",
+			Flag:        "ldap-email-domain",
+			Env:         "CODER_LDAP_EMAIL_DOMAIN",
+			Value:       &c.LDAP.EmailDomain,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailDomain",
+		},
+		{
+			Name:        "LDAP sign in text",
+			Description: "The text to show on the LDAP sign in button.",
+			Flag:        "ldap-sign-in-text",
+			Env:         "CODER_LDAP_SIGN_IN_TEXT",
+			Default:     "LDAP",
+			Value:       &c.LDAP.SignInText,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "signInText",
+		},
+		// SAML settings.
+		{
+			Name:        "SAML IdP Metadata URL",
+			Description: "URL of the SAML identity provider's metadata. Setting this or the metadata file enables login with SAML.",
+			Flag:        "saml-idp-metadata-url",
+			Env:         "CODER_SAML_IDP_METADATA_URL",
+			Value:       &c.SAML.IDPMetadataURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "idpMetadataURL",
+		},
+		{
+			Name:        "SAML IdP Metadata File",
+			Description: "Path to a file containing the SAML identity provider's metadata. Used if the metadata URL is unset.",
+			Flag:        "saml-idp-metadata-file",
+			Env:         "CODER_SAML_IDP_METADATA_FILE",
+			Value:       &c.SAML.IDPMetadataFile,
+			Group:       &deploymentGroupSAML,
+			YAML:        "idpMetadataFile",
+		},
+		{
+			Name:        "SAML Entity ID",
+			Description: "Entity ID of the Coder service provider. Defaults to the URL of the service provider metadata, {access-url}/api/v2/users/saml/metadata.",
+			Flag:        "saml-entity-id",
+			Env:         "CODER_SAML_ENTITY_ID",
+			Value:       &c.SAML.EntityID,
+			Group:       &deploymentGroupSAML,
+			YAML:        "entityID",
+		},
+		{
+			Name:        "SAML Certificate File",
+			Description: "Path to a PEM encoded certificate published in the service provider metadata. Used with the key file to sign requests and decrypt assertions.",
+			Flag:        "saml-cert-file",
+			Env:         "CODER_SAML_CERT_FILE",
+			Value:       &c.SAML.CertFile,
+			Group:       &deploymentGroupSAML,
+			YAML:        "certFile",
+		},
+		{
+			Name:        "SAML Key File",
+			Description: "Path to the PEM encoded private key of the service provider certificate.",
+			Flag:        "saml-key-file",
+			Env:         "CODER_SAML_KEY_FILE",
+			Value:       &c.SAML.KeyFile,
+			Group:       &deploymentGroupSAML,
+			YAML:        "keyFile",
+		},
+		{
+			Name:        "SAML Sign Requests",
+			Description: "Sign authentication and logout requests sent to the identity provider. Requires the certificate and key files.",
+			Flag:        "saml-sign-requests",
+			Env:         "CODER_SAML_SIGN_REQUESTS",
+			Default:     "false",
+			Value:       &c.SAML.SignRequests,
+			Group:       &deploymentGroupSAML,
+			YAML:        "signRequests",
+		},
+		{
+			Name:        "SAML Allow IdP Initiated",
+			Description: "Accept assertions that were not requested by Coder, such as those sent when a user opens Coder from the identity provider's dashboard.",
+			Flag:        "saml-allow-idp-initiated",
+			Env:         "CODER_SAML_ALLOW_IDP_INITIATED",
+			Default:     "false",
+			Value:       &c.SAML.AllowIDPInitiated,
+			Group:       &deploymentGroupSAML,
+			YAML:        "allowIdPInitiated",
+		},
+		{
+			Name:        "SAML Allow Signups",
+			Description: "Whether new users are created the first time they log in with SAML.",
+			Flag:        "saml-allow-signups",
+			Env:         "CODER_SAML_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.SAML.AllowSignups,
+			Group:       &deploymentGroupSAML,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "SAML Email Domain",
+			Description: "Email domains that users logging in with SAML must match.",
+			Flag:        "saml-email-domain",
+			Env:         "CODER_SAML_EMAIL_DOMAIN",
+			Value:       &c.SAML.EmailDomain,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailDomain",
+		},
+		{
+			Name:        "SAML Email Attribute",
+			Description: "Assertion attribute to use as the email. The subject NameID is used if the attribute is missing and the NameID is an email address.",
+			Flag:        "saml-email-attribute",
+			Env:         "CODER_SAML_EMAIL_ATTRIBUTE",
+			Default:     "email",
+			Value:       &c.SAML.EmailAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "SAML Username Attribute",
+			Description: "Assertion attribute to use as the username. The local part of the email is used if the attribute is missing.",
+			Flag:        "saml-username-attribute",
+			Env:         "CODER_SAML_USERNAME_ATTRIBUTE",
+			Default:     "username",
+			Value:       &c.SAML.UsernameAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "SAML Name Attribute",
+			Description: "Assertion attribute to use as the name.",
+			Flag:        "saml-name-attribute",
+			Env:         "CODER_SAML_NAME_ATTRIBUTE",
+			Default:     "name",
+			Value:       &c.SAML.NameAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "nameAttribute",
+		},
+		{
+			Name:        "SAML sign in text",
+			Description: "The text to show on the SAML sign in button.",
+			Flag:        "saml-sign-in-text",
+			Env:         "CODER_SAML_SIGN_IN_TEXT",
+			Default:     "SAML",
+			Value:       &c.SAML.SignInText,
+			Group:       &deploymentGroupSAML,
+			YAML:        "signInText",
+		},
+		{
+			Name:        "SAML icon URL",
+			Description: "URL pointing to the icon to use on the SAML login button.",
+			Flag:        "saml-icon-url",
+			Env:         "CODER_SAML_ICON_URL",
+			Value:       &c.SAML.IconURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "iconURL",
+		},
 		// Telemetry settings
 		telemetryEnable,
 		{
@@ -2543,6 +2988,15 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
 			Value:       &c.ExternalTokenEncryptionKeys,
 		},
+		{
+			Name:        "External Token Encryption Key Providers",
+			Description: "Encrypt OIDC and Git authentication tokens with AES-256-GCM data keys that are wrapped by a key provider and stored in the database. The value must be a comma-separated list of key providers, either `file:<path>` for a file containing a base64-encoded 32 byte key, the URL of an HTTP KMS, or a `pkcs11:` URI naming a key on a PKCS#11 token. The first key provider is used to wrap data keys, and takes precedence over --external-token-encryption-keys. Subsequent key providers will be used as a fallback when unwrapping. Rotate key providers with the `coder server dbcrypt rotate --new-key-provider` command.",
+			Flag:        "external-token-encryption-key-providers",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KEY_PROVIDERS",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalTokenEncryptionKeyProviders,
+			YAML:        "externalTokenEncryptionKeyProviders",
+		},
 		{
 			Name:        "Disable Path Apps",
 			Description: "Disable workspace apps that are not served from subdomains. Path-based apps can make requests to the Coder API and pose a security risk when the workspace serves malicious JavaScript. This is recommended for security purposes if a --wildcard-access-url is configured.",
@@ -2594,6 +3048,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disablePasswordAuth",
 		},
+		{
+			Name:        "Require Multi-Factor Authentication",
+			Description: "Require users that sign in with a password to enroll a TOTP second factor. Until they do, their sessions can only be used to enroll.",
+			Flag:        "require-mfa",
+			Env:         "CODER_REQUIRE_MFA",
+
+			Value: &c.RequireMFA,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "requireMFA",
+		},
 		{
 			Name:          "Config Path",
 			Description:   `Specify a YAML file to load configuration from.`,
@@ -2695,6 +3159,16 @@ Write out the current server config as YAML to stdout.`,
 			Default:     "", // empty string means pick best server
 			Hidden:      true,
 		},
+		{
+			Name:        "Session Recording Retention",
+			Description: "How long recordings of workspace sessions are kept before they are deleted. Set to 0 to keep recordings forever.",
+			Flag:        "session-recording-retention",
+			Env:         "CODER_SESSION_RECORDING_RETENTION",
+			Default:     (90 * 24 * time.Hour).String(),
+			Value:       &c.SessionRecordingRetention,
+			YAML:        "sessionRecordingRetention",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Proxy Health Check Interval",
 			Description: "The interval in which coderd should be checking the status of workspace proxies.",
@@ -60,6 +60,9 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"OIDC Client Secret": {
 			yaml: true,
 		},
+		"LDAP Bind Password": {
+			yaml: true,
+		},
 		"Postgres Connection URL": {
 			yaml: true,
 		},
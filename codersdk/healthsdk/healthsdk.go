@@ -216,6 +216,7 @@ type DERPNodeReport struct {
 	RoundTripPing       string                 `json:"round_trip_ping"`
 	RoundTripPingMs     int                    `json:"round_trip_ping_ms"`
 	UsesWebsocket       bool                   `json:"uses_websocket"`
+	UsesQUIC            bool                   `json:"uses_quic"`
 	ClientLogs          [][]string             `json:"client_logs"`
 	ClientErrs          [][]string             `json:"client_errs"`
 
@@ -0,0 +1,133 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// UserMFA is the second factor status of a user.
+type UserMFA struct {
+	// Required is true if the deployment requires password users to enroll
+	// a second factor.
+	Required      bool       `json:"required"`
+	TOTPEnabled   bool       `json:"totp_enabled"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at,omitempty" format:"date-time"`
+	// RecoveryCodesRemaining is the number of unused recovery codes.
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+	// WebAuthnCredentials is the number of registered passkeys, which can
+	// be used as a second factor.
+	WebAuthnCredentials int64 `json:"webauthn_credentials"`
+}
+
+// TOTPEnrollment contains the secret to add to an authenticator app. The
+// second factor is enabled once a code generated from it is verified.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is the otpauth:// URL of the secret, usually shown as a QR code.
+	URL string `json:"url"`
+}
+
+// MFACodeRequest proves possession of the second factor with a TOTP code or
+// a recovery code.
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+// MFARecoveryCodes are single use codes that can be used in place of a TOTP
+// code. They are only returned when generated.
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// IsMFACodeRequired reports whether a password login failed because the user
+// has a second factor and no code was provided.
+func IsMFACodeRequired(err error) bool {
+	var sdkErr *Error
+	if !xerrors.As(err, &sdkErr) || sdkErr.StatusCode() != http.StatusUnauthorized {
+		return false
+	}
+	for _, v := range sdkErr.Validations {
+		if v.Field == "mfa_code" {
+			return true
+		}
+	}
+	return false
+}
+
+// UserMFA returns the second factor status of the user.
+func (c *Client) UserMFA(ctx context.Context, user string) (UserMFA, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/mfa", user), nil)
+	if err != nil {
+		return UserMFA{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserMFA{}, ReadBodyAsError(res)
+	}
+	var status UserMFA
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
+// EnrollTOTP starts enrollment of a TOTP second factor. Enrolling again before
+// the code is verified replaces the secret.
+func (c *Client) EnrollTOTP(ctx context.Context, user string) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp", user), nil)
+	if err != nil {
+		return TOTPEnrollment{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// VerifyTOTP enables the enrolled TOTP second factor and returns a new set of
+// recovery codes.
+func (c *Client) VerifyTOTP(ctx context.Context, user string, req MFACodeRequest) (MFARecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp/verify", user), req)
+	if err != nil {
+		return MFARecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return MFARecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes MFARecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// RegenerateMFARecoveryCodes replaces the recovery codes of the user.
+func (c *Client) RegenerateMFARecoveryCodes(ctx context.Context, user string, req MFACodeRequest) (MFARecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/recovery-codes", user), req)
+	if err != nil {
+		return MFARecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return MFARecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes MFARecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// DisableMFA removes the second factor and recovery codes of the user. Users
+// must provide a code to disable their own second factor, admins can reset
+// the second factor of other users without one.
+func (c *Client) DisableMFA(ctx context.Context, user string, req MFACodeRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/mfa", user), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -10,10 +10,14 @@ import (
 )
 
 type OAuth2ProviderApp struct {
-	ID          uuid.UUID `json:"id" format:"uuid"`
-	Name        string    `json:"name"`
-	CallbackURL string    `json:"callback_url"`
-	Icon        string    `json:"icon"`
+	ID          uuid.UUID                   `json:"id" format:"uuid"`
+	Name        string                      `json:"name"`
+	CallbackURL string                      `json:"callback_url"`
+	Icon        string                      `json:"icon"`
+	ClientType  OAuth2ProviderAppClientType `json:"client_type"`
+	// ServiceAccountID is the user that tokens issued by the client credentials
+	// grant act as. The grant is disabled when it is not set.
+	ServiceAccountID *uuid.UUID `json:"service_account_id,omitempty" format:"uuid"`
 
 	// Endpoints are included in the app response for easier discovery. The OAuth2
 	// spec does not have a defined place to find these (for comparison, OIDC has
@@ -28,6 +32,27 @@ type OAuth2AppEndpoints struct {
 	DeviceAuth string `json:"device_authorization"`
 }
 
+// OAuth2ProviderAppClientType is the client type as defined in RFC 6749
+// section 2.1.
+type OAuth2ProviderAppClientType string
+
+const (
+	// OAuth2ProviderAppClientTypeConfidential clients authenticate with a client
+	// secret.
+	OAuth2ProviderAppClientTypeConfidential OAuth2ProviderAppClientType = "confidential"
+	// OAuth2ProviderAppClientTypePublic clients cannot keep a secret, like
+	// desktop or CLI applications. They must use PKCE instead.
+	OAuth2ProviderAppClientTypePublic OAuth2ProviderAppClientType = "public"
+)
+
+func (e OAuth2ProviderAppClientType) Valid() bool {
+	switch e {
+	case OAuth2ProviderAppClientTypeConfidential, OAuth2ProviderAppClientTypePublic:
+		return true
+	}
+	return false
+}
+
 type OAuth2ProviderAppFilter struct {
 	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
 }
@@ -73,6 +98,9 @@ type PostOAuth2ProviderAppRequest struct {
 	Name        string `json:"name" validate:"required,oauth2_app_name"`
 	CallbackURL string `json:"callback_url" validate:"required,http_url"`
 	Icon        string `json:"icon" validate:"omitempty"`
+	// ClientType defaults to confidential.
+	ClientType       OAuth2ProviderAppClientType `json:"client_type,omitempty" validate:"omitempty,oneof=confidential public"`
+	ServiceAccountID *uuid.UUID                  `json:"service_account_id,omitempty" format:"uuid"`
 }
 
 // PostOAuth2ProviderApp adds an application that can authenticate using Coder
@@ -94,6 +122,9 @@ type PutOAuth2ProviderAppRequest struct {
 	Name        string `json:"name" validate:"required,oauth2_app_name"`
 	CallbackURL string `json:"callback_url" validate:"required,http_url"`
 	Icon        string `json:"icon" validate:"omitempty"`
+	// ClientType defaults to confidential.
+	ClientType       OAuth2ProviderAppClientType `json:"client_type,omitempty" validate:"omitempty,oneof=confidential public"`
+	ServiceAccountID *uuid.UUID                  `json:"service_account_id,omitempty" format:"uuid"`
 }
 
 // PutOAuth2ProviderApp updates an application that can authenticate using Coder
@@ -185,11 +216,29 @@ type OAuth2ProviderGrantType string
 const (
 	OAuth2ProviderGrantTypeAuthorizationCode OAuth2ProviderGrantType = "authorization_code"
 	OAuth2ProviderGrantTypeRefreshToken      OAuth2ProviderGrantType = "refresh_token"
+	OAuth2ProviderGrantTypeClientCredentials OAuth2ProviderGrantType = "client_credentials"
+	OAuth2ProviderGrantTypeDeviceCode        OAuth2ProviderGrantType = "urn:ietf:params:oauth:grant-type:device_code"
 )
 
 func (e OAuth2ProviderGrantType) Valid() bool {
 	switch e {
-	case OAuth2ProviderGrantTypeAuthorizationCode, OAuth2ProviderGrantTypeRefreshToken:
+	case OAuth2ProviderGrantTypeAuthorizationCode, OAuth2ProviderGrantTypeRefreshToken,
+		OAuth2ProviderGrantTypeClientCredentials, OAuth2ProviderGrantTypeDeviceCode:
+		return true
+	}
+	return false
+}
+
+type OAuth2ProviderCodeChallengeMethod string
+
+const (
+	OAuth2ProviderCodeChallengeMethodS256 OAuth2ProviderCodeChallengeMethod = "S256"
+)
+
+func (e OAuth2ProviderCodeChallengeMethod) Valid() bool {
+	//nolint:gocritic,revive // "plain" is deliberately not supported.
+	switch e {
+	case OAuth2ProviderCodeChallengeMethodS256:
 		return true
 	}
 	return false
@@ -231,3 +280,138 @@ func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, appID uuid.UUID) e
 type OAuth2DeviceFlowCallbackResponse struct {
 	RedirectURL string `json:"redirect_url"`
 }
+
+// OAuth2DeviceAuthorizationResponse is returned by the device authorization
+// endpoint as defined in RFC 8628 section 3.2.
+type OAuth2DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	// ExpiresIn is the lifetime of the device code in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+	// Interval is the minimum number of seconds the client should wait between
+	// polling requests.
+	Interval int64 `json:"interval"`
+}
+
+// OAuth2TokenIntrospection is returned by the introspection endpoint as defined
+// in RFC 7662 section 2.2. Only Active is set for inactive tokens.
+type OAuth2TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+}
+
+// OAuth2Error is the error response of the token endpoint as defined in RFC
+// 6749 section 5.2. The device flow uses it to tell the client whether to keep
+// polling.
+type OAuth2Error struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type OAuth2TokenEndpointAuthMethod string
+
+const (
+	// OAuth2TokenEndpointAuthMethodNone is used by public clients, which have
+	// no secret.
+	OAuth2TokenEndpointAuthMethodNone              OAuth2TokenEndpointAuthMethod = "none"
+	OAuth2TokenEndpointAuthMethodClientSecretBasic OAuth2TokenEndpointAuthMethod = "client_secret_basic"
+	OAuth2TokenEndpointAuthMethodClientSecretPost  OAuth2TokenEndpointAuthMethod = "client_secret_post"
+)
+
+func (e OAuth2TokenEndpointAuthMethod) Valid() bool {
+	switch e {
+	case OAuth2TokenEndpointAuthMethodNone, OAuth2TokenEndpointAuthMethodClientSecretBasic,
+		OAuth2TokenEndpointAuthMethodClientSecretPost:
+		return true
+	}
+	return false
+}
+
+// OAuth2AuthorizationServerMetadata describes the provider so clients can
+// configure themselves, as defined in RFC 8414 section 2.
+type OAuth2AuthorizationServerMetadata struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	IntrospectionEndpoint       string `json:"introspection_endpoint"`
+	// RegistrationEndpoint is only set when dynamic client registration is
+	// enabled.
+	RegistrationEndpoint                      string                              `json:"registration_endpoint,omitempty"`
+	ResponseTypesSupported                    []OAuth2ProviderResponseType        `json:"response_types_supported"`
+	GrantTypesSupported                       []OAuth2ProviderGrantType           `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported             []OAuth2ProviderCodeChallengeMethod `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported         []OAuth2TokenEndpointAuthMethod     `json:"token_endpoint_auth_methods_supported"`
+	IntrospectionEndpointAuthMethodsSupported []OAuth2TokenEndpointAuthMethod     `json:"introspection_endpoint_auth_methods_supported"`
+}
+
+// OAuth2AuthorizationServerMetadata returns the metadata of the built-in
+// OAuth2 provider.
+func (c *Client) OAuth2AuthorizationServerMetadata(ctx context.Context) (OAuth2AuthorizationServerMetadata, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/.well-known/oauth-authorization-server", nil)
+	if err != nil {
+		return OAuth2AuthorizationServerMetadata{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2AuthorizationServerMetadata{}, ReadBodyAsError(res)
+	}
+	var metadata OAuth2AuthorizationServerMetadata
+	return metadata, json.NewDecoder(res.Body).Decode(&metadata)
+}
+
+// OAuth2ClientRegistrationRequest is the client metadata sent to the
+// registration endpoint as defined in RFC 7591 section 2. Unsupported metadata
+// is ignored.
+type OAuth2ClientRegistrationRequest struct {
+	RedirectURIs []string `json:"redirect_uris"`
+	ClientName   string   `json:"client_name,omitempty"`
+	LogoURI      string   `json:"logo_uri,omitempty"`
+	// GrantTypes defaults to authorization_code.
+	GrantTypes []OAuth2ProviderGrantType `json:"grant_types,omitempty"`
+	// ResponseTypes defaults to code.
+	ResponseTypes []OAuth2ProviderResponseType `json:"response_types,omitempty"`
+	// TokenEndpointAuthMethod defaults to client_secret_basic. Clients using
+	// "none" are registered as public clients and must use PKCE.
+	TokenEndpointAuthMethod OAuth2TokenEndpointAuthMethod `json:"token_endpoint_auth_method,omitempty"`
+}
+
+// OAuth2ClientRegistrationResponse is returned after a client registers itself
+// as defined in RFC 7591 section 3.2.1.
+type OAuth2ClientRegistrationResponse struct {
+	ClientID string `json:"client_id"`
+	// ClientSecret is only set for confidential clients.
+	ClientSecret     string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt int64  `json:"client_id_issued_at"`
+	// ClientSecretExpiresAt is always zero since secrets do not expire.
+	ClientSecretExpiresAt   int64                         `json:"client_secret_expires_at"`
+	RedirectURIs            []string                      `json:"redirect_uris"`
+	ClientName              string                        `json:"client_name"`
+	LogoURI                 string                        `json:"logo_uri,omitempty"`
+	GrantTypes              []OAuth2ProviderGrantType     `json:"grant_types"`
+	ResponseTypes           []OAuth2ProviderResponseType  `json:"response_types"`
+	TokenEndpointAuthMethod OAuth2TokenEndpointAuthMethod `json:"token_endpoint_auth_method"`
+}
+
+// PostOAuth2ClientRegistration registers an OAuth2 client without an
+// administrator. The deployment must allow dynamic client registration.
+func (c *Client) PostOAuth2ClientRegistration(ctx context.Context, req OAuth2ClientRegistrationRequest) (OAuth2ClientRegistrationResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/oauth2/register", req)
+	if err != nil {
+		return OAuth2ClientRegistrationResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ClientRegistrationResponse{}, ReadBodyAsError(res)
+	}
+	var registration OAuth2ClientRegistrationResponse
+	return registration, json.NewDecoder(res.Body).Decode(&registration)
+}
@@ -63,6 +63,9 @@ type Template struct {
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
 
 	UseClassicParameterFlow bool `json:"use_classic_parameter_flow"`
+	// SessionRecordingEnabled records the output of interactive sessions in
+	// workspaces of the template.
+	SessionRecordingEnabled bool `json:"session_recording_enabled"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -258,6 +261,10 @@ type UpdateTemplateMeta struct {
 	// made the default.
 	// An "opt-out" is present in case the new feature breaks some existing templates.
 	UseClassicParameterFlow *bool `json:"use_classic_parameter_flow,omitempty"`
+	// SessionRecordingEnabled records the output of interactive sessions in
+	// workspaces of the template. Sessions that are already running are not
+	// affected until the agent reconnects.
+	SessionRecordingEnabled *bool `json:"session_recording_enabled,omitempty"`
 }
 
 type TemplateExample struct {
@@ -5,14 +5,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 
 	"github.com/google/uuid"
 	"github.com/kylecarbs/aisdk-go"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
 
 func NewDeps(client *codersdk.Client, opts ...func(*Deps)) (Deps, error) {
@@ -162,12 +165,14 @@ var All = []GenericTool{
 	ListTemplates.Generic(),
 	ListTemplateVersionParameters.Generic(),
 	ListWorkspaces.Generic(),
+	ListWorkspaceProcesses.Generic(),
 	GetAuthenticatedUser.Generic(),
 	GetTemplateVersionLogs.Generic(),
 	GetWorkspace.Generic(),
 	GetWorkspaceAgentLogs.Generic(),
 	GetWorkspaceBuildLogs.Generic(),
 	ReportTask.Generic(),
+	SignalWorkspaceProcess.Generic(),
 	UploadTarFile.Generic(),
 	UpdateTemplateActiveVersion.Generic(),
 }
@@ -1312,6 +1317,101 @@ var DeleteTemplate = Tool[DeleteTemplateArgs, codersdk.Response]{
 	},
 }
 
+type ListWorkspaceProcessesArgs struct {
+	WorkspaceAgentID string `json:"workspace_agent_id"`
+}
+
+var ListWorkspaceProcesses = Tool[ListWorkspaceProcessesArgs, []workspacesdk.AgentProcess]{
+	Tool: aisdk.Tool{
+		Name: "coder_list_workspace_processes",
+		Description: `List the processes running in a workspace agent.
+
+Every process includes its owning user, command line, CPU usage in percent of one core, resident memory in bytes, and the TCP ports it listens on. Use this to find out which process serves a port or consumes resources.`,
+		Schema: aisdk.Schema{
+			Properties: map[string]any{
+				"workspace_agent_id": map[string]any{
+					"type": "string",
+				},
+			},
+			Required: []string{"workspace_agent_id"},
+		},
+	},
+	Handler: func(ctx context.Context, deps Deps, args ListWorkspaceProcessesArgs) ([]workspacesdk.AgentProcess, error) {
+		conn, err := dialWorkspaceAgent(ctx, deps, args.WorkspaceAgentID)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		resp, err := conn.Processes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Processes, nil
+	},
+}
+
+type SignalWorkspaceProcessArgs struct {
+	WorkspaceAgentID string `json:"workspace_agent_id"`
+	PID              int32  `json:"pid"`
+	Signal           string `json:"signal"`
+}
+
+var SignalWorkspaceProcess = Tool[SignalWorkspaceProcessArgs, codersdk.Response]{
+	Tool: aisdk.Tool{
+		Name: "coder_signal_workspace_process",
+		Description: `Send a signal to a process running in a workspace agent.
+
+Send TERM to ask a process to stop, and KILL only if it does not stop. This does not wait for the process to exit.`,
+		Schema: aisdk.Schema{
+			Properties: map[string]any{
+				"workspace_agent_id": map[string]any{
+					"type": "string",
+				},
+				"pid": map[string]any{
+					"type": "integer",
+				},
+				"signal": map[string]any{
+					"type": "string",
+					"enum": slice.ToStrings(workspacesdk.AgentProcessSignals),
+				},
+			},
+			Required: []string{"workspace_agent_id", "pid", "signal"},
+		},
+	},
+	Handler: func(ctx context.Context, deps Deps, args SignalWorkspaceProcessArgs) (codersdk.Response, error) {
+		conn, err := dialWorkspaceAgent(ctx, deps, args.WorkspaceAgentID)
+		if err != nil {
+			return codersdk.Response{}, err
+		}
+		defer conn.Close()
+		err = conn.SignalProcess(ctx, args.PID, workspacesdk.AgentProcessSignal(args.Signal))
+		if err != nil {
+			return codersdk.Response{}, err
+		}
+		return codersdk.Response{
+			Message: fmt.Sprintf("Sent %s to process %d.", args.Signal, args.PID),
+		}, nil
+	},
+}
+
+// dialWorkspaceAgent connects to the workspace agent with the ID. The caller
+// must close the connection.
+func dialWorkspaceAgent(ctx context.Context, deps Deps, workspaceAgentID string) (*workspacesdk.AgentConn, error) {
+	agentID, err := uuid.Parse(workspaceAgentID)
+	if err != nil {
+		return nil, xerrors.Errorf("workspace_agent_id must be a valid UUID: %w", err)
+	}
+	conn, err := workspacesdk.New(deps.coderClient).DialAgent(ctx, agentID, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("dial workspace agent: %w", err)
+	}
+	if !conn.AwaitReachable(ctx) {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+	}
+	return conn, nil
+}
+
 type MinimalWorkspace struct {
 	ID                      string    `json:"id"`
 	Name                    string    `json:"name"`
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"os/exec"
+	"runtime"
+	"slices"
 	"sort"
 	"sync"
 	"testing"
@@ -15,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
@@ -22,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/codersdk/toolsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -93,6 +98,43 @@ func TestTools(t *testing.T) {
 		require.Equal(t, r.Workspace.ID, result.ID, "expected the workspace ID to match")
 	})
 
+	t.Run("WorkspaceProcesses", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("The test process uses a POSIX command")
+		}
+		// Given: the workspace agent is running.
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		_ = coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+		cmd := exec.Command("sleep", "300")
+		require.NoError(t, cmd.Start())
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+		}()
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+		})
+
+		tb, err := toolsdk.NewDeps(memberClient)
+		require.NoError(t, err)
+		processes, err := testTool(t, toolsdk.ListWorkspaceProcesses, tb, toolsdk.ListWorkspaceProcessesArgs{
+			WorkspaceAgentID: agentID.String(),
+		})
+		require.NoError(t, err)
+		require.True(t, slices.ContainsFunc(processes, func(p workspacesdk.AgentProcess) bool {
+			return int(p.PID) == cmd.Process.Pid
+		}), "the sleep process is listed")
+
+		_, err = testTool(t, toolsdk.SignalWorkspaceProcess, tb, toolsdk.SignalWorkspaceProcessArgs{
+			WorkspaceAgentID: agentID.String(),
+			PID:              int32(cmd.Process.Pid), //nolint:gosec
+			Signal:           "KILL",
+		})
+		require.NoError(t, err)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.Error(t, testutil.TryReceive(ctx, t, exited))
+	})
+
 	t.Run("ListTemplates", func(t *testing.T) {
 		tb, err := toolsdk.NewDeps(memberClient)
 		require.NoError(t, err)
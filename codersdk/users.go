@@ -272,6 +272,12 @@ type ConvertLoginRequest struct {
 type LoginWithPasswordRequest struct {
 	Email    string `json:"email" validate:"required,email" format:"email"`
 	Password string `json:"password" validate:"required"`
+	// MFACode is a TOTP code or recovery code. It is required if the user has
+	// enabled a second factor.
+	MFACode string `json:"mfa_code,omitempty"`
+	// WebAuthnCredential is the response of a registered passkey to a
+	// challenge from WebAuthnLoginOptions. It can be used in place of MFACode.
+	WebAuthnCredential json.RawMessage `json:"webauthn_credential,omitempty"`
 }
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
@@ -279,6 +285,13 @@ type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
 }
 
+// LoginWithLDAPRequest enables callers to authenticate with a username and
+// password that are verified against the configured LDAP directory.
+type LoginWithLDAPRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
 // RequestOneTimePasscodeRequest enables callers to request a one-time-passcode to change their password.
 type RequestOneTimePasscodeRequest struct {
 	Email string `json:"email" validate:"required,email" format:"email"`
@@ -304,6 +317,9 @@ type AuthMethods struct {
 	Password          AuthMethod       `json:"password"`
 	Github            GithubAuthMethod `json:"github"`
 	OIDC              OIDCAuthMethod   `json:"oidc"`
+	LDAP              LDAPAuthMethod   `json:"ldap"`
+	SAML              SAMLAuthMethod   `json:"saml"`
+	Passkey           AuthMethod       `json:"passkey"`
 }
 
 type AuthMethod struct {
@@ -325,6 +341,17 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type LDAPAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+}
+
+type SAMLAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+	IconURL    string `json:"iconUrl"`
+}
+
 type UserParameter struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
@@ -631,6 +658,26 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginWithLDAP creates a session token authenticating with a username and
+// password verified against the deployment's LDAP directory.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithLDAP(ctx context.Context, req LoginWithLDAPRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/ldap/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	return resp, nil
+}
+
 func (c *Client) RequestOneTimePasscode(ctx context.Context, req RequestOneTimePasscodeRequest) error {
 	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/otp/request", req)
 	if err != nil {
@@ -0,0 +1,154 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WebAuthnCredential is a passkey or security key registered by a user.
+type WebAuthnCredential struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	Name       string    `json:"name"`
+	Transports []string  `json:"transports"`
+	// BackupEligible is true for passkeys that can be synced between
+	// devices.
+	BackupEligible bool       `json:"backup_eligible"`
+	CreatedAt      time.Time  `json:"created_at" format:"date-time"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+}
+
+// WebAuthnOptions are the options of a registration or login ceremony. They
+// are passed to navigator.credentials.create() or navigator.credentials.get()
+// after decoding the base64url encoded binary fields.
+type WebAuthnOptions struct {
+	PublicKey json.RawMessage `json:"publicKey"`
+}
+
+// CreateWebAuthnCredentialRequest completes the registration of a credential.
+// Like DeleteWebAuthnCredentialRequest, one of WebAuthnCredential, Code or
+// Password must prove the presence of the user.
+type CreateWebAuthnCredentialRequest struct {
+	Name string `json:"name" validate:"max=64"`
+	// Credential is the PublicKeyCredential returned by
+	// navigator.credentials.create(), with binary fields base64url encoded.
+	Credential json.RawMessage `json:"credential" validate:"required"`
+	// WebAuthnCredential is the response of an existing passkey of the user
+	// to a challenge from WebAuthnLoginOptions.
+	WebAuthnCredential json.RawMessage `json:"webauthn_credential,omitempty"`
+	// Code is a TOTP code or a recovery code.
+	Code     string `json:"code,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// DeleteWebAuthnCredentialRequest proves the presence of the user to remove
+// one of their own passkeys. One of the fields must be set. Admins can remove
+// the passkeys of other users without one.
+type DeleteWebAuthnCredentialRequest struct {
+	// WebAuthnCredential is the response of any passkey of the user to a
+	// challenge from WebAuthnLoginOptions.
+	WebAuthnCredential json.RawMessage `json:"webauthn_credential,omitempty"`
+	// Code is a TOTP code or a recovery code.
+	Code     string `json:"code,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// LoginWithWebAuthnRequest signs in with a passkey.
+type LoginWithWebAuthnRequest struct {
+	// Credential is the PublicKeyCredential returned by
+	// navigator.credentials.get(), with binary fields base64url encoded.
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+// WebAuthnCredentials returns the credentials registered by the user.
+func (c *Client) WebAuthnCredentials(ctx context.Context, user string) ([]WebAuthnCredential, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var credentials []WebAuthnCredential
+	return credentials, json.NewDecoder(res.Body).Decode(&credentials)
+}
+
+// WebAuthnRegistrationOptions starts the registration of a credential for the
+// user.
+func (c *Client) WebAuthnRegistrationOptions(ctx context.Context, user string) (WebAuthnOptions, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/webauthn/registration", user), nil)
+	if err != nil {
+		return WebAuthnOptions{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WebAuthnOptions{}, ReadBodyAsError(res)
+	}
+	var options WebAuthnOptions
+	return options, json.NewDecoder(res.Body).Decode(&options)
+}
+
+// CreateWebAuthnCredential completes the registration of a credential with the
+// response of the authenticator.
+func (c *Client) CreateWebAuthnCredential(ctx context.Context, user string, req CreateWebAuthnCredentialRequest) (WebAuthnCredential, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials", user), req)
+	if err != nil {
+		return WebAuthnCredential{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WebAuthnCredential{}, ReadBodyAsError(res)
+	}
+	var credential WebAuthnCredential
+	return credential, json.NewDecoder(res.Body).Decode(&credential)
+}
+
+// DeleteWebAuthnCredential removes a credential of the user.
+func (c *Client) DeleteWebAuthnCredential(ctx context.Context, user string, id uuid.UUID, req DeleteWebAuthnCredentialRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials/%s", user, id), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebAuthnLoginOptions starts a passkey login. The response of the
+// authenticator can be used to sign in with LoginWithWebAuthn, or as the
+// second factor of a password login.
+func (c *Client) WebAuthnLoginOptions(ctx context.Context) (WebAuthnOptions, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/webauthn/options", nil)
+	if err != nil {
+		return WebAuthnOptions{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WebAuthnOptions{}, ReadBodyAsError(res)
+	}
+	var options WebAuthnOptions
+	return options, json.NewDecoder(res.Body).Decode(&options)
+}
+
+// LoginWithWebAuthn signs in with a passkey. The session token is not applied
+// to the client.
+func (c *Client) LoginWithWebAuthn(ctx context.Context, req LoginWithWebAuthnRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/webauthn/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -393,6 +393,61 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentResourceMonitorType is the resource watched by a monitor.
+type WorkspaceAgentResourceMonitorType string
+
+const (
+	WorkspaceAgentResourceMonitorTypeMemory    WorkspaceAgentResourceMonitorType = "memory"
+	WorkspaceAgentResourceMonitorTypeVolume    WorkspaceAgentResourceMonitorType = "volume"
+	WorkspaceAgentResourceMonitorTypeCPU       WorkspaceAgentResourceMonitorType = "cpu"
+	WorkspaceAgentResourceMonitorTypeLoad      WorkspaceAgentResourceMonitorType = "load"
+	WorkspaceAgentResourceMonitorTypeInodes    WorkspaceAgentResourceMonitorType = "inodes"
+	WorkspaceAgentResourceMonitorTypeProcesses WorkspaceAgentResourceMonitorType = "processes"
+)
+
+// WorkspaceAgentResourceMonitorState is whether the usage of a resource
+// reached the threshold of its monitor.
+type WorkspaceAgentResourceMonitorState string
+
+const (
+	WorkspaceAgentResourceMonitorStateOK    WorkspaceAgentResourceMonitorState = "ok"
+	WorkspaceAgentResourceMonitorStateAlert WorkspaceAgentResourceMonitorState = "alert"
+)
+
+// WorkspaceAgentResourceMonitor is a resource monitor declared in the
+// template of the workspace.
+type WorkspaceAgentResourceMonitor struct {
+	Type WorkspaceAgentResourceMonitorType `json:"type"`
+	// Path is the volume of volume and inodes monitors.
+	Path    string `json:"path,omitempty"`
+	Enabled bool   `json:"enabled"`
+	// Threshold is the number of processes for processes monitors, the 1
+	// minute load average as a percentage of the number of cores for load
+	// monitors and a percentage of the resource used otherwise.
+	Threshold int32                              `json:"threshold"`
+	State     WorkspaceAgentResourceMonitorState `json:"state"`
+	UpdatedAt time.Time                          `json:"updated_at" format:"date-time"`
+}
+
+type WorkspaceAgentResourceMonitorsResponse struct {
+	Monitors []WorkspaceAgentResourceMonitor `json:"monitors"`
+}
+
+// WorkspaceAgentResourceMonitors returns the resource monitors of the
+// workspace agent and their states.
+func (c *Client) WorkspaceAgentResourceMonitors(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentResourceMonitorsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/resources-monitoring", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentResourceMonitorsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentResourceMonitorsResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentResourceMonitorsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // WorkspaceAgentDevcontainersResponse is the response to the devcontainers
 // request.
 type WorkspaceAgentDevcontainersResponse struct {
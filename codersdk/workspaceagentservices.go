@@ -0,0 +1,123 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspaceAgentServiceRestartPolicy decides whether the agent restarts a
+// service when it exits.
+type WorkspaceAgentServiceRestartPolicy string
+
+const (
+	// WorkspaceAgentServiceRestartPolicyAlways restarts the service whenever
+	// it exits.
+	WorkspaceAgentServiceRestartPolicyAlways WorkspaceAgentServiceRestartPolicy = "always"
+	// WorkspaceAgentServiceRestartPolicyOnFailure restarts the service if it
+	// exits with a non-zero exit code.
+	WorkspaceAgentServiceRestartPolicyOnFailure WorkspaceAgentServiceRestartPolicy = "on_failure"
+	// WorkspaceAgentServiceRestartPolicyNever never restarts the service.
+	WorkspaceAgentServiceRestartPolicyNever WorkspaceAgentServiceRestartPolicy = "never"
+)
+
+// WorkspaceAgentServiceStatus is the state of the process of a service.
+type WorkspaceAgentServiceStatus string
+
+const (
+	// WorkspaceAgentServiceStatusPending is the status of a service that has
+	// not been started yet, services are started after the startup scripts
+	// finish.
+	WorkspaceAgentServiceStatusPending WorkspaceAgentServiceStatus = "pending"
+	// WorkspaceAgentServiceStatusStarting is the status of a service with a
+	// readiness probe that has not passed yet.
+	WorkspaceAgentServiceStatusStarting WorkspaceAgentServiceStatus = "starting"
+	// WorkspaceAgentServiceStatusRunning is the status of a service whose
+	// process is running and, if it has a readiness probe, has been ready.
+	WorkspaceAgentServiceStatusRunning WorkspaceAgentServiceStatus = "running"
+	// WorkspaceAgentServiceStatusRestarting is the status of a service that
+	// exited and waits for the restart delay before it is started again.
+	WorkspaceAgentServiceStatusRestarting WorkspaceAgentServiceStatus = "restarting"
+	// WorkspaceAgentServiceStatusStopped is the status of a service that
+	// exited successfully and is not restarted, or that was stopped by the
+	// agent shutting down.
+	WorkspaceAgentServiceStatusStopped WorkspaceAgentServiceStatus = "stopped"
+	// WorkspaceAgentServiceStatusFailed is the status of a service that
+	// exited with a failure and is not restarted.
+	WorkspaceAgentServiceStatusFailed WorkspaceAgentServiceStatus = "failed"
+)
+
+// WorkspaceAgentService is a long-running process defined by the template
+// that is supervised by the workspace agent.
+type WorkspaceAgentService struct {
+	ID            uuid.UUID                          `json:"id" format:"uuid"`
+	LogSourceID   uuid.UUID                          `json:"log_source_id" format:"uuid"`
+	LogPath       string                             `json:"log_path"`
+	Name          string                             `json:"name"`
+	DisplayName   string                             `json:"display_name"`
+	Icon          string                             `json:"icon"`
+	Command       string                             `json:"command"`
+	RestartPolicy WorkspaceAgentServiceRestartPolicy `json:"restart_policy" enums:"always,on_failure,never"`
+	// RestartDelay is the time the agent waits before restarting the service
+	// after it exits.
+	RestartDelay time.Duration `json:"restart_delay"`
+	// ReadinessProbe is checked like the healthcheck of workspace apps. It
+	// is unset if the service has no readiness probe.
+	ReadinessProbe Healthcheck `json:"readiness_probe"`
+
+	Status WorkspaceAgentServiceStatus `json:"status" enums:"pending,starting,running,restarting,stopped,failed"`
+	// Health is the result of the readiness probe, it is disabled if the
+	// service has no readiness probe.
+	Health WorkspaceAppHealth `json:"health"`
+	// Restarts is the number of times the agent restarted the service since
+	// the agent started.
+	Restarts int32 `json:"restarts"`
+	// ExitCode is the exit code of the last run of the service, it is unset
+	// if the service has not exited.
+	ExitCode  *int32    `json:"exit_code,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// WorkspaceAgentServicesResponse is the response to the services request.
+type WorkspaceAgentServicesResponse struct {
+	Services []WorkspaceAgentService `json:"services"`
+}
+
+// WorkspaceAgentServices returns the services of the workspace agent and
+// their status as last reported by the agent.
+func (c *Client) WorkspaceAgentServices(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentServicesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/services", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentServicesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentServicesResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentServicesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentRestartService restarts the service with the given name. The
+// service is started if it is not running.
+func (c *Client) WorkspaceAgentRestartService(ctx context.Context, agentID uuid.UUID, name string) (Response, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/services/%s/restart", agentID, url.PathEscape(name)), nil)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return Response{}, ReadBodyAsError(res)
+	}
+	var m Response
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return Response{}, xerrors.Errorf("decode response body: %w", err)
+	}
+	return m, nil
+}
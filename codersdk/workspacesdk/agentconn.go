@@ -1,6 +1,7 @@
 package workspacesdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
@@ -9,6 +10,8 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
+	"os"
 	"strconv"
 	"time"
 
@@ -24,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/websocket"
 )
 
 // NewAgentConn creates a new WorkspaceAgentConn. `conn` may be unique
@@ -115,6 +119,15 @@ func AgentReconnectingPTYInitWithContainer(container, containerUser string) Agen
 	}
 }
 
+// AgentReconnectingPTYInitWithBackendType sets the backend of a new reconnecting
+// PTY session, for example "persistent" for a session that survives agent
+// restarts.
+func AgentReconnectingPTYInitWithBackendType(backendType string) AgentReconnectingPTYInitOption {
+	return func(init *AgentReconnectingPTYInit) {
+		init.BackendType = backendType
+	}
+}
+
 // ReconnectingPTYRequest is sent from the client to the server
 // to pipe data to a PTY.
 // @typescript-ignore ReconnectingPTYRequest
@@ -407,8 +420,303 @@ func (c *AgentConn) RecreateDevcontainer(ctx context.Context, containerIDOrName
 	return m, nil
 }
 
+// Services returns the services supervised by the agent and their current
+// status.
+func (c *AgentConn) Services(ctx context.Context) (codersdk.WorkspaceAgentServicesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/services", nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentServicesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentServicesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp codersdk.WorkspaceAgentServicesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// RestartService restarts the service with the given name. The service is
+// started if it is not running. This does not wait for the service to be
+// restarted.
+func (c *AgentConn) RestartService(ctx context.Context, name string) (codersdk.Response, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/services/"+url.PathEscape(name)+"/restart", nil)
+	if err != nil {
+		return codersdk.Response{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return codersdk.Response{}, codersdk.ReadBodyAsError(res)
+	}
+	var m codersdk.Response
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return codersdk.Response{}, xerrors.Errorf("decode response body: %w", err)
+	}
+	return m, nil
+}
+
+// AgentFileInfo describes a file in the workspace.
+type AgentFileInfo struct {
+	// Path is the absolute path of the file.
+	Path    string      `json:"path"`
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Mode    os.FileMode `json:"mode"`
+	IsDir   bool        `json:"is_dir"`
+	ModTime time.Time   `json:"mod_time"`
+}
+
+// StatFile returns information about a file in the workspace. Relative paths
+// are relative to the home directory of the workspace user.
+func (c *AgentConn) StatFile(ctx context.Context, path string) (AgentFileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/stat", nil, codersdk.WithQueryParam("path", path))
+	if err != nil {
+		return AgentFileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AgentFileInfo{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp AgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DownloadFile streams a file from the workspace. Directories are streamed
+// as a tar archive of their contents. Relative paths are relative to the home
+// directory of the workspace user. The caller must close the returned reader.
+func (c *AgentConn) DownloadFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/download", nil, codersdk.WithQueryParam("path", path))
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// UploadFile streams r to a file in the workspace, replacing it if it
+// exists. The parent directory must exist. Relative paths are relative to the
+// home directory of the workspace user.
+func (c *AgentConn) UploadFile(ctx context.Context, path string, mode os.FileMode, r io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPut, "/api/v0/files/upload", r,
+		codersdk.WithQueryParam("path", path),
+		codersdk.WithQueryParam("mode", strconv.FormatUint(uint64(mode.Perm()), 8)),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		},
+	)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UploadDirectory extracts the tar archive read from r into a directory in
+// the workspace, creating the directory if it does not exist. Relative paths
+// are relative to the home directory of the workspace user.
+func (c *AgentConn) UploadDirectory(ctx context.Context, path string, r io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPut, "/api/v0/files/upload", r,
+		codersdk.WithQueryParam("path", path),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", codersdk.ContentTypeTar)
+		},
+	)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// Exec runs a command in the workspace without a PTY. Standard output and
+// standard error are written to stdout and stderr as they arrive, nil writers
+// discard output. The caller must close the returned session.
+func (c *AgentConn) Exec(ctx context.Context, req ExecRequest, stdout, stderr io.Writer) (*ExecSession, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	conn, err := c.DialExec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewExecSession(ctx, conn, req, stdout, stderr)
+}
+
+// DialExec connects to the exec endpoint of the agent. Most callers should
+// use Exec, this is used to proxy exec sessions.
+func (c *AgentConn) DialExec(ctx context.Context) (*websocket.Conn, error) {
+	host := net.JoinHostPort(c.agentAddress().String(), strconv.Itoa(AgentHTTPAPIServerPort))
+	//nolint:bodyclose
+	conn, res, err := websocket.Dial(ctx, fmt.Sprintf("http://%s/api/v0/exec", host), &websocket.DialOptions{
+		HTTPClient:      c.apiClient(),
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, xerrors.Errorf("dial exec: %w", err)
+		}
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	conn.SetReadLimit(ExecMessageLimit)
+	return conn, nil
+}
+
+// AgentProcess is a process running in the workspace.
+type AgentProcess struct {
+	PID     int32  `json:"pid"`
+	PPID    int32  `json:"ppid"`
+	User    string `json:"user"`
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
+	// CPUPercent is the CPU usage since the previous listing, or since the
+	// process started if it was not listed before. One fully used core is
+	// 100 percent.
+	CPUPercent float64 `json:"cpu_percent"`
+	// MemoryRSS is the resident set size in bytes.
+	MemoryRSS uint64    `json:"memory_rss"`
+	StartedAt time.Time `json:"started_at"`
+	// ListeningPorts are the TCP ports the process listens on.
+	ListeningPorts []uint16 `json:"listening_ports"`
+}
+
+// AgentProcessesResponse lists the processes running in the workspace.
+type AgentProcessesResponse struct {
+	Processes []AgentProcess `json:"processes"`
+}
+
+// AgentProcessSignal is a signal that can be sent to a process in the
+// workspace. Signals are named as in the SSH protocol.
+type AgentProcessSignal string
+
+const (
+	AgentProcessSignalHUP  AgentProcessSignal = "HUP"
+	AgentProcessSignalINT  AgentProcessSignal = "INT"
+	AgentProcessSignalQUIT AgentProcessSignal = "QUIT"
+	AgentProcessSignalKILL AgentProcessSignal = "KILL"
+	AgentProcessSignalTERM AgentProcessSignal = "TERM"
+)
+
+// AgentProcessSignals are all signals that can be sent to a process.
+var AgentProcessSignals = []AgentProcessSignal{
+	AgentProcessSignalHUP,
+	AgentProcessSignalINT,
+	AgentProcessSignalQUIT,
+	AgentProcessSignalKILL,
+	AgentProcessSignalTERM,
+}
+
+// SignalProcessRequest is the request to send a signal to a process.
+type SignalProcessRequest struct {
+	Signal AgentProcessSignal `json:"signal"`
+}
+
+// Processes returns the processes running in the workspace.
+func (c *AgentConn) Processes(ctx context.Context) (AgentProcessesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/processes", nil)
+	if err != nil {
+		return AgentProcessesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AgentProcessesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp AgentProcessesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// SignalProcess sends a signal to the process with the PID. This does not
+// wait for the process to exit.
+func (c *AgentConn) SignalProcess(ctx context.Context, pid int32, signal AgentProcessSignal) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	data, err := json.Marshal(SignalProcessRequest{Signal: signal})
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, fmt.Sprintf("/api/v0/processes/%d/signal", pid), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// ReconnectingPTYSession is a reconnecting PTY session on the agent. A
+// session is attached to by connecting to the reconnecting PTY with its ID.
+type ReconnectingPTYSession struct {
+	ID uuid.UUID `json:"id"`
+	// Command is the command the session was started with, the user's shell
+	// if empty.
+	Command     string `json:"command"`
+	BackendType string `json:"backend_type"`
+	// Connections is the number of connections attached to the session.
+	Connections int64     `json:"connections"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReconnectingPTYSessionsResponse lists the reconnecting PTY sessions.
+type ReconnectingPTYSessionsResponse struct {
+	Sessions []ReconnectingPTYSession `json:"sessions"`
+}
+
+// ReconnectingPTYSessions returns the reconnecting PTY sessions on the agent.
+func (c *AgentConn) ReconnectingPTYSessions(ctx context.Context) (ReconnectingPTYSessionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return ReconnectingPTYSessionsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReconnectingPTYSessionsResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp ReconnectingPTYSessionsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// KillReconnectingPTY kills the process of a reconnecting PTY session and
+// closes its connections.
+func (c *AgentConn) KillReconnectingPTY(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodDelete, "/api/v0/reconnecting-ptys/"+id.String(), nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
-func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader, opts ...codersdk.RequestOption) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
@@ -419,6 +727,9 @@ func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io
 	if err != nil {
 		return nil, xerrors.Errorf("new http api request to %q: %w", url, err)
 	}
+	for _, opt := range opts {
+		opt(req)
+	}
 
 	return c.apiClient().Do(req)
 }
@@ -0,0 +1,185 @@
+package workspacesdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/coder/websocket"
+	"golang.org/x/xerrors"
+)
+
+// ExecRequest is the command to run in an exec session. It is sent as the
+// first message of the session.
+type ExecRequest struct {
+	// Command is run with the shell of the workspace user, as for SSH
+	// sessions.
+	Command string `json:"command"`
+	// Env is added to the environment of the command, in KEY=VALUE form.
+	Env []string `json:"env,omitempty"`
+	// WorkingDirectory is the directory the command is run in. Relative
+	// paths are relative to the home directory of the workspace user. If
+	// empty, the agent's default directory is used.
+	WorkingDirectory string `json:"working_directory,omitempty"`
+}
+
+// ExecExit is the result of an exec session.
+type ExecExit struct {
+	ExitCode int `json:"exit_code"`
+	// Error is set if the command could not be run.
+	Error string `json:"error,omitempty"`
+}
+
+// ExecFrameType is the first byte of the binary messages of an exec session,
+// the rest of the message is the payload.
+type ExecFrameType byte
+
+const (
+	// ExecFrameStdin carries data for the standard input of the command.
+	ExecFrameStdin ExecFrameType = iota + 1
+	// ExecFrameStdinClose closes the standard input of the command.
+	ExecFrameStdinClose
+	// ExecFrameSignal carries the name of a signal to send to the command,
+	// as in the SSH protocol, e.g. "INT" or "TERM".
+	ExecFrameSignal
+	// ExecFrameStdout carries data written to standard output.
+	ExecFrameStdout
+	// ExecFrameStderr carries data written to standard error.
+	ExecFrameStderr
+	// ExecFrameExit carries a JSON encoded ExecExit and is the last message
+	// sent by the agent.
+	ExecFrameExit
+)
+
+// ExecMessageLimit is the maximum size of a message in an exec session.
+const ExecMessageLimit = 1 << 20
+
+// ExecFrame returns a binary message of the frame type and payload.
+func ExecFrame(t ExecFrameType, payload []byte) []byte {
+	return append([]byte{byte(t)}, payload...)
+}
+
+// ExecSession is a command running in a workspace. Output is written to the
+// writers passed when the session is started.
+type ExecSession struct {
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	stdout io.Writer
+	stderr io.Writer
+
+	done chan struct{}
+	exit ExecExit
+	err  error
+}
+
+// NewExecSession starts the command over a websocket connected to an exec
+// endpoint. Nil writers discard output.
+func NewExecSession(ctx context.Context, conn *websocket.Conn, req ExecRequest, stdout, stderr io.Writer) (*ExecSession, error) {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	conn.SetReadLimit(ExecMessageLimit)
+	data, err := json.Marshal(req)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, "")
+		return nil, xerrors.Errorf("marshal request: %w", err)
+	}
+	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+		_ = conn.Close(websocket.StatusInternalError, "")
+		return nil, xerrors.Errorf("write request: %w", err)
+	}
+	// The session outlives the context used to start it.
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	s := &ExecSession{
+		conn:   conn,
+		ctx:    sessionCtx,
+		cancel: cancel,
+		stdout: stdout,
+		stderr: stderr,
+		done:   make(chan struct{}),
+	}
+	go s.read()
+	return s, nil
+}
+
+func (s *ExecSession) read() {
+	defer close(s.done)
+	for {
+		_, msg, err := s.conn.Read(s.ctx)
+		if err != nil {
+			s.err = xerrors.Errorf("session closed before the command exited: %w", err)
+			return
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		payload := msg[1:]
+		switch ExecFrameType(msg[0]) {
+		case ExecFrameStdout:
+			_, err = s.stdout.Write(payload)
+		case ExecFrameStderr:
+			_, err = s.stderr.Write(payload)
+		case ExecFrameExit:
+			if err := json.Unmarshal(payload, &s.exit); err != nil {
+				s.err = xerrors.Errorf("decode exit: %w", err)
+			} else if s.exit.Error != "" {
+				s.err = xerrors.New(s.exit.Error)
+			}
+			_ = s.conn.Close(websocket.StatusNormalClosure, "")
+			return
+		default:
+		}
+		if err != nil {
+			s.err = xerrors.Errorf("write output: %w", err)
+			_ = s.conn.Close(websocket.StatusGoingAway, "")
+			return
+		}
+	}
+}
+
+// Write sends p to the standard input of the command.
+func (s *ExecSession) Write(p []byte) (int, error) {
+	// Split large writes so messages fit within the read limit.
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), ExecMessageLimit-1)
+		if err := s.conn.Write(s.ctx, websocket.MessageBinary, ExecFrame(ExecFrameStdin, p[:n])); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// CloseStdin closes the standard input of the command.
+func (s *ExecSession) CloseStdin() error {
+	return s.conn.Write(s.ctx, websocket.MessageBinary, ExecFrame(ExecFrameStdinClose, nil))
+}
+
+// Signal sends a signal to the command. Signals are named as in the SSH
+// protocol, e.g. "INT" or "TERM".
+func (s *ExecSession) Signal(ctx context.Context, sig string) error {
+	return s.conn.Write(ctx, websocket.MessageBinary, ExecFrame(ExecFrameSignal, []byte(sig)))
+}
+
+// Wait waits for the command to exit and returns its exit code. All output
+// has been written when Wait returns. An error is returned if the command
+// could not be run or the session was closed before it exited.
+func (s *ExecSession) Wait() (int, error) {
+	<-s.done
+	return s.exit.ExitCode, s.err
+}
+
+// Close closes the session. The command is terminated if it is still
+// running.
+func (s *ExecSession) Close() error {
+	err := s.conn.Close(websocket.StatusNormalClosure, "")
+	s.cancel()
+	<-s.done
+	return err
+}
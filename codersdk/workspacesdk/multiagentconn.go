@@ -0,0 +1,130 @@
+package workspacesdk
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/tailnet"
+)
+
+// MultiAgentConn connects to any number of workspace agents over a single
+// tailnet connection. Each agent is added as a tunnel destination when it is
+// first dialed, and removed once every AgentConn to it is closed.
+type MultiAgentConn struct {
+	logger slog.Logger
+	tn     *dialedTailnet
+
+	mu     sync.Mutex
+	closed bool
+	// agents counts the open AgentConns of each destination.
+	agents map[uuid.UUID]int
+}
+
+// DialMultiAgent connects to the tailnet coordinator without any agents.
+// Agents are connected with DialAgent on the returned connection.
+func (c *Client) DialMultiAgent(dialCtx context.Context, options *DialAgentOptions) (*MultiAgentConn, error) {
+	if options == nil {
+		options = &DialAgentOptions{}
+	}
+
+	connInfo, err := c.AgentConnectionInfoGeneric(dialCtx)
+	if err != nil {
+		return nil, xerrors.Errorf("get connection info: %w", err)
+	}
+
+	tn, err := c.dialTailnet(dialCtx, "/api/v2/tailnet", connInfo, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiAgentConn{
+		logger: options.Logger,
+		tn:     tn,
+		agents: make(map[uuid.UUID]int),
+	}, nil
+}
+
+// DialAgent connects to an agent over the shared tailnet connection and waits
+// for it to be reachable. Closing the returned AgentConn does not close the
+// shared connection.
+func (m *MultiAgentConn) DialAgent(ctx context.Context, agentID uuid.UUID) (*AgentConn, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, xerrors.New("multi-agent connection is closed")
+	}
+	m.agents[agentID]++
+	if m.agents[agentID] == 1 {
+		m.logger.Debug(ctx, "adding agent destination", slog.F("agent_id", agentID))
+		m.tn.coordCtrl.AddDestination(agentID)
+	}
+	m.mu.Unlock()
+
+	var once sync.Once
+	agentConn := NewAgentConn(m.tn.conn, AgentConnOptions{
+		AgentID: agentID,
+		CloseFunc: func() error {
+			once.Do(func() { m.release(agentID) })
+			return ErrSkipClose
+		},
+	})
+	if !agentConn.AwaitReachable(ctx) {
+		_ = agentConn.Close()
+		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", ctx.Err())
+	}
+	return agentConn, nil
+}
+
+func (m *MultiAgentConn) release(agentID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agents[agentID]--
+	if m.agents[agentID] > 0 {
+		return
+	}
+	delete(m.agents, agentID)
+	if m.closed {
+		return
+	}
+	m.logger.Debug(context.Background(), "removing agent destination", slog.F("agent_id", agentID))
+	m.tn.coordCtrl.RemoveDestination(agentID)
+}
+
+// Agents returns the IDs of the agents with open connections.
+func (m *MultiAgentConn) Agents() []uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	agents := make([]uuid.UUID, 0, len(m.agents))
+	for agentID := range m.agents {
+		agents = append(agents, agentID)
+	}
+	slices.SortFunc(agents, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return agents
+}
+
+// TailnetConn returns the shared tailnet connection.
+func (m *MultiAgentConn) TailnetConn() *tailnet.Conn {
+	return m.tn.conn
+}
+
+// Close disconnects from the coordinator and closes the shared tailnet
+// connection, which ends the connections to all agents.
+func (m *MultiAgentConn) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	return m.tn.close()
+}
@@ -0,0 +1,113 @@
+package workspacesdk_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/tailnet"
+	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/coder/v2/tailnet/tailnettest"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/websocket"
+)
+
+func TestDialMultiAgent(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	logger := slogtest.Make(t, &slogtest.Options{
+		IgnoreErrors: true,
+	}).Leveled(slog.LevelDebug)
+
+	fCoord := tailnettest.NewFakeCoordinator()
+	var coord tailnet.Coordinator = fCoord
+	coordPtr := atomic.Pointer[tailnet.Coordinator]{}
+	coordPtr.Store(&coord)
+	svc, err := tailnet.NewClientService(tailnet.ClientServiceOptions{
+		Logger:                 logger,
+		CoordPtr:               &coordPtr,
+		DERPMapUpdateFrequency: time.Hour,
+		DERPMapFn:              func() *tailcfg.DERPMap { return &tailcfg.DERPMap{} },
+		ResumeTokenProvider:    tailnet.NewInsecureTestResumeTokenProvider(),
+	})
+	require.NoError(t, err)
+
+	clientID := uuid.New()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/workspaceagents/connection", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), w, http.StatusOK, workspacesdk.AgentConnectionInfo{
+			DERPMap:                  &tailcfg.DERPMap{},
+			DisableDirectConnections: true,
+		})
+	})
+	mux.HandleFunc("/api/v2/tailnet", func(w http.ResponseWriter, r *http.Request) {
+		sws, err := websocket.Accept(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		wsCtx, nc := codersdk.WebsocketNetConn(ctx, sws, websocket.MessageBinary)
+		_ = svc.ServeConnV2(wsCtx, nc, tailnet.StreamID{Name: "client", ID: clientID})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	client := workspacesdk.New(codersdk.New(srvURL))
+	multiConn, err := client.DialMultiAgent(ctx, &workspacesdk.DialAgentOptions{Logger: logger})
+	require.NoError(t, err)
+	defer multiConn.Close()
+
+	call := testutil.TryReceive(ctx, t, fCoord.CoordinateCalls)
+	require.Equal(t, clientID, call.ID)
+
+	// Dialing an agent adds it as a tunnel destination.  The agent never
+	// answers, so the dial fails when the context is canceled and the
+	// destination is removed again.
+	agentID := uuid.New()
+	dialCtx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := multiConn.DialAgent(dialCtx, agentID)
+		errCh <- err
+	}()
+	req := awaitTunnelRequest(ctx, t, call.Reqs)
+	require.Equal(t, agentID[:], req.GetAddTunnel().GetId())
+	require.Equal(t, []uuid.UUID{agentID}, multiConn.Agents())
+
+	cancel()
+	err = testutil.TryReceive(ctx, t, errCh)
+	require.ErrorContains(t, err, "timed out waiting for agent to become reachable")
+	req = awaitTunnelRequest(ctx, t, call.Reqs)
+	require.Equal(t, agentID[:], req.GetRemoveTunnel().GetId())
+	require.Empty(t, multiConn.Agents())
+
+	require.NoError(t, multiConn.Close())
+	_, err = multiConn.DialAgent(ctx, agentID)
+	require.ErrorContains(t, err, "multi-agent connection is closed")
+}
+
+// awaitTunnelRequest returns the next request that adds or removes a tunnel.
+func awaitTunnelRequest(ctx context.Context, t *testing.T, reqs <-chan *tailnetproto.CoordinateRequest) *tailnetproto.CoordinateRequest {
+	t.Helper()
+	for {
+		req := testutil.TryReceive(ctx, t, reqs)
+		if req.GetAddTunnel() != nil || req.GetRemoveTunnel() != nil {
+			return req
+		}
+	}
+}
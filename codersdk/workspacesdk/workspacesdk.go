@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
@@ -145,10 +146,13 @@ func New(c *codersdk.Client) *Client {
 // a connection with a workspace.
 // @typescript-ignore AgentConnectionInfo
 type AgentConnectionInfo struct {
-	DERPMap                  *tailcfg.DERPMap `json:"derp_map"`
-	DERPForceWebSockets      bool             `json:"derp_force_websockets"`
-	DisableDirectConnections bool             `json:"disable_direct_connections"`
-	HostnameSuffix           string           `json:"hostname_suffix,omitempty"`
+	DERPMap *tailcfg.DERPMap `json:"derp_map"`
+	// DERPQUICPorts are the QUIC ports of the DERP nodes in DERPMap, keyed
+	// by node name.
+	DERPQUICPorts            tailnet.DERPQUICPorts `json:"derp_quic_ports,omitempty"`
+	DERPForceWebSockets      bool                  `json:"derp_force_websockets"`
+	DisableDirectConnections bool                  `json:"disable_direct_connections"`
+	HostnameSuffix           string                `json:"hostname_suffix,omitempty"`
 }
 
 func (c *Client) AgentConnectionInfoGeneric(ctx context.Context) (AgentConnectionInfo, error) {
@@ -202,6 +206,45 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 	if err != nil {
 		return nil, xerrors.Errorf("get connection info: %w", err)
 	}
+
+	tn, err := c.dialTailnet(dialCtx, fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID), connInfo, options, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	agentConn = NewAgentConn(tn.conn, AgentConnOptions{
+		AgentID:   agentID,
+		CloseFunc: tn.close,
+	})
+
+	if !agentConn.AwaitReachable(dialCtx) {
+		_ = agentConn.Close()
+		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", dialCtx.Err())
+	}
+
+	return agentConn, nil
+}
+
+// dialedTailnet is a tailnet connection that is coordinated over the tailnet
+// API.
+type dialedTailnet struct {
+	conn       *tailnet.Conn
+	coordCtrl  *tailnet.TunnelSrcCoordController
+	controller *tailnet.Controller
+	cancel     context.CancelFunc
+}
+
+// close disconnects from the coordinator and closes the tailnet connection.
+func (d *dialedTailnet) close() error {
+	d.cancel()
+	<-d.controller.Closed()
+	return d.conn.Close()
+}
+
+// dialTailnet creates a tailnet connection that is coordinated over the
+// tailnet API at path, with tunnels to the given destinations.  It returns
+// once the coordinator is connected.
+func (c *Client) dialTailnet(dialCtx context.Context, path string, connInfo AgentConnectionInfo, options *DialAgentOptions, destinations ...uuid.UUID) (_ *dialedTailnet, err error) {
 	if connInfo.DisableDirectConnections {
 		options.BlockEndpoints = true
 	}
@@ -222,7 +265,7 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		}
 	}()
 
-	coordinateURL, err := c.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	coordinateURL, err := c.client.URL.Parse(path)
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
@@ -251,6 +294,7 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses:           []netip.Prefix{netip.PrefixFrom(ip, 128)},
 		DERPMap:             connInfo.DERPMap,
+		DERPQUICPorts:       connInfo.DERPQUICPorts,
 		DERPHeader:          &header,
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
@@ -268,7 +312,9 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		}
 	}()
 	coordCtrl := tailnet.NewTunnelSrcCoordController(options.Logger, conn)
-	coordCtrl.AddDestination(agentID)
+	for _, dest := range destinations {
+		coordCtrl.AddDestination(dest)
+	}
 	controller.CoordCtrl = coordCtrl
 	controller.DERPCtrl = tailnet.NewBasicDERPController(options.Logger, conn)
 	controller.Run(ctx)
@@ -286,21 +332,12 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		options.Logger.Debug(ctx, "connected to tailnet v2+ API")
 	}
 
-	agentConn = NewAgentConn(conn, AgentConnOptions{
-		AgentID: agentID,
-		CloseFunc: func() error {
-			cancel()
-			<-controller.Closed()
-			return conn.Close()
-		},
-	})
-
-	if !agentConn.AwaitReachable(dialCtx) {
-		_ = agentConn.Close()
-		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", dialCtx.Err())
-	}
-
-	return agentConn, nil
+	return &dialedTailnet{
+		conn:       conn,
+		coordCtrl:  coordCtrl,
+		controller: controller,
+		cancel:     cancel,
+	}, nil
 }
 
 // @typescript-ignore:WorkspaceAgentReconnectingPTYOpts
@@ -390,6 +427,39 @@ func (c *Client) AgentReconnectingPTY(ctx context.Context, opts WorkspaceAgentRe
 	return websocket.NetConn(context.Background(), conn, websocket.MessageBinary), nil
 }
 
+// AgentExec runs a command in the workspace through coderd, for clients that
+// cannot connect to the agent over tailnet. See AgentConn.Exec.
+func (c *Client) AgentExec(ctx context.Context, agentID uuid.UUID, req ExecRequest, stdout, stderr io.Writer) (*ExecSession, error) {
+	serverURL, err := c.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/exec", agentID))
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(serverURL, []*http.Cookie{{
+		Name:  codersdk.SessionTokenCookie,
+		Value: c.client.SessionToken(),
+	}})
+	httpClient := &http.Client{
+		Jar:       jar,
+		Transport: c.client.HTTPClient.Transport,
+	}
+	//nolint:bodyclose
+	conn, res, err := websocket.Dial(ctx, serverURL.String(), &websocket.DialOptions{
+		HTTPClient:      httpClient,
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return NewExecSession(ctx, conn, req, stdout, stderr)
+}
+
 func WithTestOnlyCoderContextResolver(ctx context.Context, r Resolver) context.Context {
 	return context.WithValue(ctx, dnsResolverContextKey{}, r)
 }
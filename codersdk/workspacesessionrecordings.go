@@ -0,0 +1,92 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeVSCode          SessionRecordingType = "vscode"
+	SessionRecordingTypeJetBrains       SessionRecordingType = "jetbrains"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceSessionRecording is a recording of the output of an interactive
+// session in a workspace. Sessions are only recorded if enabled on the
+// template of the workspace.
+type WorkspaceSessionRecording struct {
+	ID             uuid.UUID            `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID            `json:"organization_id" format:"uuid"`
+	WorkspaceID    uuid.UUID            `json:"workspace_id" format:"uuid"`
+	WorkspaceName  string               `json:"workspace_name"`
+	OwnerID        uuid.UUID            `json:"owner_id" format:"uuid"`
+	OwnerUsername  string               `json:"owner_username"`
+	AgentID        uuid.UUID            `json:"agent_id" format:"uuid"`
+	Type           SessionRecordingType `json:"type" enums:"ssh,vscode,jetbrains,reconnecting_pty"`
+	// Width and Height are the initial size of the terminal.
+	Width     int32      `json:"width"`
+	Height    int32      `json:"height"`
+	StartedAt time.Time  `json:"started_at" format:"date-time"`
+	EndedAt   *time.Time `json:"ended_at,omitempty" format:"date-time"`
+	// Size is the size of the recording in bytes.
+	Size int64 `json:"size"`
+}
+
+// WorkspaceSessionRecordingsRequest filters the recordings returned by
+// WorkspaceSessionRecordings.
+type WorkspaceSessionRecordingsRequest struct {
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	OwnerID     uuid.UUID `json:"owner_id,omitempty" format:"uuid"`
+	Pagination
+}
+
+// WorkspaceSessionRecordings returns the session recordings the user is
+// allowed to view, most recent first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, req WorkspaceSessionRecordingsRequest) ([]WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workspacesessionrecordings", nil,
+		req.Pagination.asRequestOption(),
+		func(r *http.Request) {
+			q := r.URL.Query()
+			if req.WorkspaceID != uuid.Nil {
+				q.Set("workspace_id", req.WorkspaceID.String())
+			}
+			if req.OwnerID != uuid.Nil {
+				q.Set("owner_id", req.OwnerID.String())
+			}
+			r.URL.RawQuery = q.Encode()
+		},
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecordingCast returns the recording in the asciicast v2
+// format. The caller must close the returned reader.
+func (c *Client) WorkspaceSessionRecordingCast(ctx context.Context, id uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacesessionrecordings/%s/cast", id), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
@@ -18,17 +18,20 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"WorkspaceAgent":  {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
-	"WorkspaceApp":    {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"GitSSHKey":                 {codersdk.AuditActionCreate},
+	"Template":                  {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                      {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":            {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                    {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                   {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"WorkspaceAgent":            {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
+	"WorkspaceApp":              {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"WebAuthnCredential":        {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"UserSecret":                {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceSessionRecording": {codersdk.AuditActionOpen},
 }
 
 type Action string
@@ -117,6 +120,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"max_port_sharing_level":            ActionTrack,
 		"activity_bump":                     ActionTrack,
 		"use_classic_parameter_flow":        ActionTrack,
+		"session_recording_enabled":         ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -205,18 +209,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                ActionIgnore,
+		"hashed_secret":     ActionIgnore,
+		"user_id":           ActionTrack,
+		"last_used":         ActionTrack,
+		"expires_at":        ActionTrack,
+		"created_at":        ActionTrack,
+		"updated_at":        ActionIgnore,
+		"login_type":        ActionIgnore,
+		"lifetime_seconds":  ActionIgnore,
+		"ip_address":        ActionIgnore,
+		"scope":             ActionIgnore,
+		"token_name":        ActionIgnore,
+		"scope_permissions": ActionTrack,
+		"scope_allow_list":  ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -255,16 +261,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"token_hashed_secret": ActionSecret,
 		"derp_enabled":        ActionTrack,
 		"derp_only":           ActionTrack,
+		"derp_quic_port":      ActionTrack,
 		"region_id":           ActionTrack,
 		"version":             ActionTrack,
 	},
 	&database.OAuth2ProviderApp{}: {
-		"id":           ActionIgnore,
-		"created_at":   ActionIgnore,
-		"updated_at":   ActionIgnore,
-		"name":         ActionTrack,
-		"icon":         ActionTrack,
-		"callback_url": ActionTrack,
+		"id":                     ActionIgnore,
+		"created_at":             ActionIgnore,
+		"updated_at":             ActionIgnore,
+		"name":                   ActionTrack,
+		"icon":                   ActionTrack,
+		"callback_url":           ActionTrack,
+		"client_type":            ActionTrack,
+		"service_account_id":     ActionTrack,
+		"dynamically_registered": ActionTrack,
 	},
 	&database.OAuth2ProviderAppSecret{}: {
 		"id":             ActionIgnore,
@@ -370,6 +380,47 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"hidden":                ActionIgnore,
 		"open_in":               ActionIgnore,
 	},
+	&database.WebAuthnCredential{}: {
+		"id":               ActionTrack,
+		"user_id":          ActionTrack,
+		"name":             ActionTrack,
+		"credential_id":    ActionIgnore,
+		"public_key":       ActionIgnore,
+		"attestation_type": ActionTrack,
+		"transports":       ActionTrack,
+		"aaguid":           ActionTrack,
+		"sign_count":       ActionIgnore,
+		"backup_eligible":  ActionTrack,
+		"backup_state":     ActionIgnore,
+		"created_at":       ActionIgnore,
+		"last_used_at":     ActionIgnore,
+	},
+	&database.UserSecret{}: {
+		"id":           ActionTrack,
+		"user_id":      ActionTrack,
+		"name":         ActionTrack,
+		"description":  ActionTrack,
+		"value":        ActionSecret,
+		"value_key_id": ActionIgnore,
+		"env_name":     ActionTrack,
+		"file_path":    ActionTrack,
+		"created_at":   ActionIgnore,
+		"updated_at":   ActionIgnore,
+	},
+	&database.WorkspaceSessionRecording{}: {
+		"id":              ActionIgnore,
+		"organization_id": ActionIgnore,
+		"workspace_id":    ActionIgnore,
+		"owner_id":        ActionIgnore,
+		"agent_id":        ActionIgnore,
+		"type":            ActionIgnore,
+		"width":           ActionIgnore,
+		"height":          ActionIgnore,
+		"started_at":      ActionIgnore,
+		"ended_at":        ActionIgnore,
+		"size":            ActionIgnore,
+		"created_at":      ActionIgnore,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
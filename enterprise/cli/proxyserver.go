@@ -30,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/wsproxy"
+	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/pretty"
 	"github.com/coder/serpent"
 )
@@ -276,6 +277,16 @@ func (r *RootCmd) proxyServer() *serpent.Command {
 			if httpServers.TLSConfig != nil {
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
+			if cfg.DERP.Server.Enable && cfg.DERP.Server.QUICAddress != "" {
+				if httpServers.TLSConfig == nil {
+					return xerrors.New("DERP over QUIC requires TLS to be enabled")
+				}
+				options.DERPQUICListener, err = tailnet.ListenDERPQUIC(cfg.DERP.Server.QUICAddress.String(), httpServers.TLSConfig)
+				if err != nil {
+					return xerrors.Errorf("listen for DERP over QUIC: %w", err)
+				}
+				cliui.Infof(inv.Stdout, "Serving DERP over QUIC on udp://%s", options.DERPQUICListener.Addr())
+			}
 
 			proxy, err := wsproxy.New(ctx, options)
 			if err != nil {
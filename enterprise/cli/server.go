@@ -99,6 +99,20 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			CheckInactiveUsersCancelFunc: dormancy.CheckInactiveUsers(ctx, options.Logger, quartz.NewReal(), options.Database, options.Auditor),
 		}
 
+		// Data keys wrapped by a key provider take precedence over static
+		// keys, which remain available for decryption while rotating.
+		if providerSpecs := options.DeploymentValues.ExternalTokenEncryptionKeyProviders.Value(); len(providerSpecs) != 0 {
+			providers, err := dbcrypt.ParseKeyProviders(providerSpecs...)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("parse external-token-encryption-key-providers: %w", err)
+			}
+			cs, err := dbcrypt.NewEnvelopeCiphers(ctx, options.Database, providers...)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("initialize encryption: %w", err)
+			}
+			o.ExternalTokenEncryption = append(o.ExternalTokenEncryption, cs...)
+		}
+
 		if encKeys := options.DeploymentValues.ExternalTokenEncryptionKeys.Value(); len(encKeys) != 0 {
 			keys := make([][]byte, 0, len(encKeys))
 			for idx, ek := range encKeys {
@@ -112,7 +126,7 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			if err != nil {
 				return nil, nil, xerrors.Errorf("initialize encryption: %w", err)
 			}
-			o.ExternalTokenEncryption = cs
+			o.ExternalTokenEncryption = append(o.ExternalTokenEncryption, cs...)
 		}
 
 		api, err := coderd.New(ctx, o)
@@ -12,6 +12,7 @@ import (
 	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/v2/cli"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/awsiamrds"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
@@ -54,11 +55,13 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 			}
 
 			ks := [][]byte{}
-			dk, err := base64.StdEncoding.DecodeString(flags.New)
-			if err != nil {
-				return xerrors.Errorf("decode new key: %w", err)
+			if flags.New != "" {
+				dk, err := base64.StdEncoding.DecodeString(flags.New)
+				if err != nil {
+					return xerrors.Errorf("decode new key: %w", err)
+				}
+				ks = append(ks, dk)
 			}
-			ks = append(ks, dk)
 
 			for _, k := range flags.Old {
 				dk, err := base64.StdEncoding.DecodeString(k)
@@ -73,19 +76,35 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 				return xerrors.Errorf("create ciphers: %w", err)
 			}
 
-			var act string
-			switch len(flags.Old) {
-			case 0:
-				act = "Data will be encrypted with the new key."
-			default:
-				act = "Data will be decrypted with all available keys and re-encrypted with new key."
+			var providers []dbcrypt.KeyProvider
+			if flags.NewKeyProvider != "" {
+				providers, err = dbcrypt.ParseKeyProviders(append([]string{flags.NewKeyProvider}, flags.OldKeyProviders...)...)
+				if err != nil {
+					return xerrors.Errorf("create key providers: %w", err)
+				}
 			}
 
-			msg := fmt.Sprintf("%s\n\n- New key: %s\n- Old keys: %s\n\nRotate external token encryption keys?\n",
-				act,
-				flags.New,
-				strings.Join(flags.Old, ", "),
-			)
+			var msg string
+			if flags.NewKeyProvider != "" {
+				msg = fmt.Sprintf("Data keys will be re-wrapped with the new key provider. Data encrypted with old keys will be re-encrypted with the current data key.\n\n- New key provider: %s\n- Old key providers: %s\n- Old keys: %s\n\nRotate external token encryption keys?\n",
+					flags.NewKeyProvider,
+					strings.Join(flags.OldKeyProviders, ", "),
+					strings.Join(flags.Old, ", "),
+				)
+			} else {
+				var act string
+				switch len(flags.Old) {
+				case 0:
+					act = "Data will be encrypted with the new key."
+				default:
+					act = "Data will be decrypted with all available keys and re-encrypted with new key."
+				}
+				msg = fmt.Sprintf("%s\n\n- New key: %s\n- Old keys: %s\n\nRotate external token encryption keys?\n",
+					act,
+					flags.New,
+					strings.Join(flags.Old, ", "),
+				)
+			}
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{Text: msg, IsConfirm: true}); err != nil {
 				return err
 			}
@@ -106,6 +125,19 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 				_ = sqlDB.Close()
 			}()
 			logger.Info(ctx, "connected to postgres")
+			if len(providers) > 0 {
+				// Re-wrapping the data keys is enough to stop using the
+				// old key providers, as the data keys themselves are
+				// unchanged.
+				if err := dbcrypt.Rewrap(ctx, logger, sqlDB, providers); err != nil {
+					return xerrors.Errorf("rewrap data keys: %w", err)
+				}
+				envelopeCiphers, err := dbcrypt.NewEnvelopeCiphers(ctx, database.New(sqlDB), providers[0])
+				if err != nil {
+					return xerrors.Errorf("load data keys: %w", err)
+				}
+				ciphers = append(envelopeCiphers, ciphers...)
+			}
 			if err := dbcrypt.Rotate(ctx, logger, sqlDB, ciphers); err != nil {
 				return xerrors.Errorf("rotate ciphers: %w", err)
 			}
@@ -148,6 +180,11 @@ func (*RootCmd) dbcryptDecryptCmd() *serpent.Command {
 				return xerrors.Errorf("create ciphers: %w", err)
 			}
 
+			providers, err := dbcrypt.ParseKeyProviders(flags.KeyProviders...)
+			if err != nil {
+				return xerrors.Errorf("create key providers: %w", err)
+			}
+
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "This will decrypt all encrypted data in the database. Are you sure you want to continue?",
 				IsConfirm: true,
@@ -171,6 +208,13 @@ func (*RootCmd) dbcryptDecryptCmd() *serpent.Command {
 				_ = sqlDB.Close()
 			}()
 			logger.Info(ctx, "connected to postgres")
+			if len(providers) > 0 {
+				envelopeCiphers, err := dbcrypt.LoadCiphers(ctx, database.New(sqlDB), providers...)
+				if err != nil {
+					return xerrors.Errorf("load data keys: %w", err)
+				}
+				ciphers = append(envelopeCiphers, ciphers...)
+			}
 			if err := dbcrypt.Decrypt(ctx, logger, sqlDB, ciphers); err != nil {
 				return xerrors.Errorf("rotate ciphers: %w", err)
 			}
@@ -239,10 +283,12 @@ Are you sure you want to continue?`
 }
 
 type rotateFlags struct {
-	PostgresURL  string
-	PostgresAuth string
-	New          string
-	Old          []string
+	PostgresURL     string
+	PostgresAuth    string
+	New             string
+	Old             []string
+	NewKeyProvider  string
+	OldKeyProviders []string
 }
 
 func (f *rotateFlags) attach(opts *serpent.OptionSet) {
@@ -274,6 +320,18 @@ func (f *rotateFlags) attach(opts *serpent.OptionSet) {
 			Description: "The old external token encryption keys. Must be a comma-separated list of base64-encoded keys.",
 			Value:       serpent.StringArrayOf(&f.Old),
 		},
+		serpent.Option{
+			Flag:        "new-key-provider",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_NEW_KEY_PROVIDER",
+			Description: "The key provider that wraps the data keys. Data keys wrapped by an old key provider are re-wrapped without re-encrypting any data. Cannot be used with --new-key.",
+			Value:       serpent.StringOf(&f.NewKeyProvider),
+		},
+		serpent.Option{
+			Flag:        "old-key-providers",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_OLD_KEY_PROVIDERS",
+			Description: "The old key providers. Must be a comma-separated list of key providers.",
+			Value:       serpent.StringArrayOf(&f.OldKeyProviders),
+		},
 		cliui.SkipPromptOption(),
 	)
 }
@@ -283,14 +341,30 @@ func (f *rotateFlags) valid() error {
 		return xerrors.Errorf("no database configured")
 	}
 
-	if f.New == "" {
-		return xerrors.Errorf("no new key provided")
+	if f.New == "" && f.NewKeyProvider == "" {
+		return xerrors.Errorf("no new key or key provider provided")
+	}
+
+	if f.New != "" && f.NewKeyProvider != "" {
+		return xerrors.Errorf("--new-key and --new-key-provider cannot be used together")
 	}
 
-	if val, err := base64.StdEncoding.DecodeString(f.New); err != nil {
-		return xerrors.Errorf("new key must be base64-encoded")
-	} else if len(val) != 32 {
-		return xerrors.Errorf("new key must be exactly 32 bytes in length")
+	if f.NewKeyProvider == "" && len(f.OldKeyProviders) > 0 {
+		return xerrors.Errorf("--old-key-providers requires --new-key-provider")
+	}
+
+	if f.New != "" {
+		if val, err := base64.StdEncoding.DecodeString(f.New); err != nil {
+			return xerrors.Errorf("new key must be base64-encoded")
+		} else if len(val) != 32 {
+			return xerrors.Errorf("new key must be exactly 32 bytes in length")
+		}
+	}
+
+	for i, p := range f.OldKeyProviders {
+		if p == f.NewKeyProvider {
+			return xerrors.Errorf("old key provider at index %d is the same as the new key provider", i)
+		}
 	}
 
 	for i, k := range f.Old {
@@ -313,6 +387,7 @@ type decryptFlags struct {
 	PostgresURL  string
 	PostgresAuth string
 	Keys         []string
+	KeyProviders []string
 }
 
 func (f *decryptFlags) attach(opts *serpent.OptionSet) {
@@ -338,6 +413,12 @@ func (f *decryptFlags) attach(opts *serpent.OptionSet) {
 			Description: "Keys required to decrypt existing data. Must be a comma-separated list of base64-encoded keys.",
 			Value:       serpent.StringArrayOf(&f.Keys),
 		},
+		serpent.Option{
+			Flag:        "key-providers",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_DECRYPT_KEY_PROVIDERS",
+			Description: "Key providers required to unwrap the data keys of existing data. Must be a comma-separated list of key providers.",
+			Value:       serpent.StringArrayOf(&f.KeyProviders),
+		},
 		cliui.SkipPromptOption(),
 	)
 }
@@ -347,8 +428,8 @@ func (f *decryptFlags) valid() error {
 		return xerrors.Errorf("no database configured")
 	}
 
-	if len(f.Keys) == 0 {
-		return xerrors.Errorf("no keys provided")
+	if len(f.Keys) == 0 && len(f.KeyProviders) == 0 {
+		return xerrors.Errorf("no keys or key providers provided")
 	}
 
 	for i, k := range f.Keys {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
@@ -213,6 +215,112 @@ func TestServerDBCrypt(t *testing.T) {
 	}
 }
 
+// TestServerDBCryptKeyProviders tests migrating from a static key to data
+// keys wrapped by key providers, and rotating key providers.
+func TestServerDBCryptKeyProviders(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test requires a postgres instance")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	connectionURL, err := dbtestutil.Open(t)
+	require.NoError(t, err)
+	t.Cleanup(func() { dbtestutil.DumpOnFailure(t, connectionURL) })
+
+	sqlDB, err := sql.Open("postgres", connectionURL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+	db := database.New(sqlDB)
+
+	// Given: data encrypted with a static key
+	key := testutil.MustRandString(t, 32)
+	staticCiphers, err := dbcrypt.NewCiphers([]byte(key))
+	require.NoError(t, err)
+	cryptdb, err := dbcrypt.New(ctx, db, staticCiphers...)
+	require.NoError(t, err)
+	users := genData(t, cryptdb)
+
+	writeKeyProvider := func() string {
+		path := filepath.Join(t.TempDir(), "kek")
+		kek := base64.StdEncoding.EncodeToString([]byte(testutil.MustRandString(t, 32)))
+		require.NoError(t, os.WriteFile(path, []byte(kek), 0o600))
+		return "file:" + path
+	}
+	providerA := writeKeyProvider()
+	providerB := writeKeyProvider()
+
+	// When: the data is rotated to a data key wrapped by key provider A
+	inv, _ := newCLI(t, "server", "dbcrypt", "rotate",
+		"--postgres-url", connectionURL,
+		"--new-key-provider", providerA,
+		"--old-keys", base64.StdEncoding.EncodeToString([]byte(key)),
+		"--yes",
+	)
+	pty := ptytest.New(t)
+	inv.Stdout = pty.Output()
+	require.NoError(t, inv.Run())
+	require.NoError(t, pty.Close())
+
+	// Then: all data is encrypted with the data key
+	providers, err := dbcrypt.ParseKeyProviders(providerA)
+	require.NoError(t, err)
+	dataKeys, err := dbcrypt.LoadCiphers(ctx, db, providers...)
+	require.NoError(t, err)
+	require.Len(t, dataKeys, 1)
+	for _, usr := range users {
+		requireEncryptedWithCipher(ctx, t, db, dataKeys[0], usr.ID)
+	}
+
+	// When: the key provider is rotated to key provider B
+	inv, _ = newCLI(t, "server", "dbcrypt", "rotate",
+		"--postgres-url", connectionURL,
+		"--new-key-provider", providerB,
+		"--old-key-providers", providerA,
+		"--yes",
+	)
+	pty = ptytest.New(t)
+	inv.Stdout = pty.Output()
+	require.NoError(t, inv.Run())
+	require.NoError(t, pty.Close())
+
+	// Then: the data key is unchanged, but only key provider B can unwrap it
+	providers, err = dbcrypt.ParseKeyProviders(providerB)
+	require.NoError(t, err)
+	rewrapped, err := dbcrypt.LoadCiphers(ctx, db, providers...)
+	require.NoError(t, err)
+	require.Len(t, rewrapped, 1)
+	require.Equal(t, dataKeys[0].HexDigest(), rewrapped[0].HexDigest())
+	for _, usr := range users {
+		requireEncryptedWithCipher(ctx, t, db, rewrapped[0], usr.ID)
+	}
+	providers, err = dbcrypt.ParseKeyProviders(providerA)
+	require.NoError(t, err)
+	stale, err := dbcrypt.LoadCiphers(ctx, db, providers...)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+
+	// When: the data is decrypted with key provider B
+	inv, _ = newCLI(t, "server", "dbcrypt", "decrypt",
+		"--postgres-url", connectionURL,
+		"--key-providers", providerB,
+		"--yes",
+	)
+	pty = ptytest.New(t)
+	inv.Stdout = pty.Output()
+	require.NoError(t, inv.Run())
+	require.NoError(t, pty.Close())
+
+	// Then: all data is decrypted
+	for _, usr := range users {
+		requireEncryptedWithCipher(ctx, t, db, &nullCipher{}, usr.ID)
+	}
+}
+
 func genData(t *testing.T, db database.Store) []database.User {
 	t.Helper()
 	var users []database.User
@@ -497,6 +497,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/{id}", api.scimPatchUser)
 				r.Put("/{id}", api.scimPutUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 			r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 				u := r.URL.String()
 				httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{
@@ -829,8 +837,11 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 			if enabled {
 				fn := derpMapper(api.Logger, api.ProxyHealth)
 				api.AGPL.DERPMapper.Store(&fn)
+				portsFn := derpQUICPortsMapper(api.ProxyHealth)
+				api.AGPL.DERPQUICPortsMapper.Store(&portsFn)
 			} else {
 				api.AGPL.DERPMapper.Store(nil)
+				api.AGPL.DERPQUICPortsMapper.Store(nil)
 			}
 		}
 
@@ -1059,6 +1070,42 @@ func derpMapper(logger slog.Logger, proxyHealth *proxyhealth.ProxyHealth) func(*
 	}
 }
 
+// derpQUICPortsMapper adds the QUIC ports of healthy workspace proxies to the
+// ports of a DERP map produced by derpMapper.
+func derpQUICPortsMapper(proxyHealth *proxyhealth.ProxyHealth) func(*tailcfg.DERPMap, agpltailnet.DERPQUICPorts) agpltailnet.DERPQUICPorts {
+	return func(derpMap *tailcfg.DERPMap, ports agpltailnet.DERPQUICPorts) agpltailnet.DERPQUICPorts {
+		regions := make(map[string]*tailcfg.DERPRegion, len(derpMap.Regions))
+		for _, region := range derpMap.Regions {
+			if !region.EmbeddedRelay {
+				regions[region.RegionCode] = region
+			}
+		}
+
+		ports = ports.Clone()
+		for _, status := range proxyHealth.HealthStatus() {
+			if status.Status != proxyhealth.Healthy || !status.Proxy.DerpEnabled || status.Proxy.DerpQuicPort == 0 {
+				continue
+			}
+			u, err := url.Parse(status.Proxy.Url)
+			if err != nil {
+				continue
+			}
+			region, ok := regions[fmt.Sprintf("coder_%s", strings.ToLower(status.Proxy.Name))]
+			if !ok {
+				continue
+			}
+			for _, node := range region.Nodes {
+				// derpMapper leaves out proxies whose region conflicts with
+				// an existing one, so check the node is really the proxy.
+				if node.HostName == u.Hostname() {
+					ports[node.Name] = int(status.Proxy.DerpQuicPort)
+				}
+			}
+		}
+		return ports
+	}
+}
+
 // @Summary Get entitlements
 // @ID get-entitlements
 // @Security CoderSessionToken
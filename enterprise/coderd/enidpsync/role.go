@@ -54,7 +54,7 @@ func (e EnterpriseIDPSync) ParseRoleClaims(ctx context.Context, mergedClaims jwt
 		claimRoles, err = e.AGPLIDPSync.RolesFromClaim(e.AGPLIDPSync.SiteRoleField, mergedClaims)
 		if err != nil {
 			rawType := mergedClaims[e.AGPLIDPSync.SiteRoleField]
-			e.Logger.Error(ctx, "oidc claims user roles field was an unknown type",
+			e.Logger.Error(ctx, "idp claims user roles field was an unknown type",
 				slog.F("type", fmt.Sprintf("%T", rawType)),
 				slog.F("field", e.AGPLIDPSync.SiteRoleField),
 				slog.F("raw_value", rawType),
@@ -63,7 +63,7 @@ func (e EnterpriseIDPSync) ParseRoleClaims(ctx context.Context, mergedClaims jwt
 			// TODO: Determine a static page or not
 			return idpsync.RoleParams{}, &idpsync.HTTPError{
 				Code:             http.StatusInternalServerError,
-				Msg:              "Login disabled until site wide OIDC config is fixed",
+				Msg:              "Login disabled until site wide role sync config is fixed",
 				Detail:           fmt.Sprintf("Roles claim must be an array of strings, type found: %T. Disabling role sync will allow login to proceed.", rawType),
 				RenderStaticPage: false,
 			}
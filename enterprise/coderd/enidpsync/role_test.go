@@ -7,6 +7,8 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog/sloggers/slogtest"
+
 	"github.com/coder/coder/v2/coderd/entitlements"
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -141,4 +143,23 @@ func TestEnterpriseParseRoleClaims(t *testing.T) {
 			rbac.RoleOwner().Name,
 		}, params.SiteWideRoles)
 	})
+
+	t.Run("InvalidClaimType", func(t *testing.T) {
+		t.Parallel()
+
+		mgr := runtimeconfig.NewManager()
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		s := enidpsync.NewSync(logger, mgr, entitled, idpsync.DeploymentSyncSettings{
+			SiteRoleField: "roles",
+		})
+
+		_, err := s.ParseRoleClaims(context.Background(), jwt.MapClaims{
+			"roles": 42,
+		})
+		require.NotNil(t, err)
+		// LDAP and SAML logins share role sync, so the error must not
+		// blame the OIDC configuration.
+		require.NotContains(t, err.Msg, "OIDC")
+		require.Contains(t, err.Msg, "role sync")
+	})
 }
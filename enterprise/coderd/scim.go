@@ -77,7 +77,8 @@ func (api *API) scimServiceProviderConfig(rw http.ResponseWriter, _ *http.Reques
 			Supported: false,
 		},
 		Filter: scim.FilterSupported{
-			Supported: false,
+			Supported:  true,
+			MaxResults: scimMaxResults,
 		},
 		ChangePassword: scim.Supported{
 			Supported: false,
@@ -0,0 +1,811 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+const (
+	scimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// scimMaxResults is the largest page of groups returned by a list request.
+	scimMaxResults = 100
+)
+
+var (
+	// scimDisplayNameFilter matches the only filter supported when listing
+	// groups. Okta and Azure use it to look up a group before creating it.
+	scimDisplayNameFilter = regexp.MustCompile(`(?i)^\s*displayName\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+	// scimMemberPath matches a patch path selecting a single member, e.g.
+	// 'members[value eq "<user id>"]'.
+	scimMemberPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+	// scimGroupNameReplace matches the characters that are not allowed in a
+	// group name.
+	scimGroupNameReplace = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// SCIMGroup is a SCIM 2.0 group. Groups are provisioned in the default
+// organization. The display name is kept as the group's display name and the
+// group name is derived from it.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members,omitempty"`
+	Meta        SCIMGroupMeta     `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	// Value is the ID of the user returned when it was provisioned.
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+type SCIMGroupMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+type SCIMGroupList struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []SCIMGroup `json:"Resources"`
+}
+
+// SCIMPatchGroupRequest is a SCIM 2.0 PatchOp message. Only the display name
+// and the members of a group can be patched.
+type SCIMPatchGroupRequest struct {
+	Schemas    []string                  `json:"schemas"`
+	Operations []SCIMPatchGroupOperation `json:"Operations"`
+}
+
+type SCIMPatchGroupOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// scimGetGroups lists the groups of the default organization. Only the
+// 'displayName eq' filter is supported.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only 'displayName eq' is supported"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Param excludedAttributes query string false "Attributes to exclude, only 'members' is supported"
+// @Success 200 {object} coderd.SCIMGroupList
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	query := r.URL.Query()
+	var displayName *string
+	if filter := query.Get("filter"); filter != "" {
+		match := scimDisplayNameFilter.FindStringSubmatch(filter)
+		if match == nil {
+			_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, xerrors.Errorf("unsupported filter %q, only 'displayName eq' is supported", filter)))
+			return
+		}
+		value, err := strconv.Unquote(match[1])
+		if err != nil {
+			_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, xerrors.Errorf("invalid filter value: %w", err)))
+			return
+		}
+		displayName = &value
+	}
+	startIndex, err := scimQueryInt(query.Get("startIndex"), 1)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("invalid startIndex: %w", err)))
+		return
+	}
+	count, err := scimQueryInt(query.Get("count"), scimMaxResults)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("invalid count: %w", err)))
+		return
+	}
+	startIndex = max(startIndex, 1)
+	count = min(max(count, 0), scimMaxResults)
+	excludeMembers := false
+	for _, attr := range strings.Split(query.Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			excludeMembers = true
+		}
+	}
+
+	org, ok := api.scimOrganization(rw, r)
+	if !ok {
+		return
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	rows, err := api.Database.GetGroups(dbauthz.AsSCIMProvisioner(ctx), database.GetGroupsParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	groups := make([]database.Group, 0, len(rows))
+	for _, row := range rows {
+		if row.Group.IsEveryone() {
+			continue
+		}
+		if displayName != nil && scimGroupDisplayName(row.Group) != *displayName {
+			continue
+		}
+		groups = append(groups, row.Group)
+	}
+
+	list := SCIMGroupList{
+		Schemas:      []string{scimListResponseSchema},
+		TotalResults: len(groups),
+		StartIndex:   startIndex,
+		Resources:    []SCIMGroup{},
+	}
+	if startIndex <= len(groups) {
+		groups = groups[startIndex-1:]
+		groups = groups[:min(count, len(groups))]
+		for _, group := range groups {
+			var members []database.GroupMember
+			if !excludeMembers {
+				//nolint:gocritic // SCIM operations are a system user
+				members, err = api.Database.GetGroupMembersByGroupID(dbauthz.AsSCIMProvisioner(ctx), database.GetGroupMembersByGroupIDParams{
+					GroupID:       group.ID,
+					IncludeSystem: false,
+				})
+				if err != nil {
+					_ = handlerutil.WriteError(rw, err) // internal error
+					return
+				}
+			}
+			list.Resources = append(list.Resources, api.scimGroup(group, members))
+		}
+	}
+	list.ItemsPerPage = len(list.Resources)
+
+	httpapi.Write(ctx, rw, http.StatusOK, list)
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	group, members, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.scimGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+	name, err := scimGroupName(sGroup.DisplayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err))
+		return
+	}
+	memberIDs, err := scimGroupMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err))
+		return
+	}
+
+	org, ok := api.scimOrganization(rw, r)
+	if !ok {
+		return
+	}
+
+	var (
+		group   database.Group
+		members []database.GroupMember
+	)
+	//nolint:gocritic // SCIM operations are a system user
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, err = tx.InsertGroup(dbauthz.AsSCIMProvisioner(ctx), database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           name,
+			DisplayName:    sGroup.DisplayName,
+			OrganizationID: org.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		members, err = scimUpdateGroupMembers(dbauthz.AsSCIMProvisioner(ctx), tx, group, nil, memberIDs)
+		return err
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name)))
+		return
+	}
+	if err != nil {
+		scimWriteGroupError(rw, err)
+		return
+	}
+	api.scimAuditGroup(r, http.StatusCreated, database.AuditActionCreate, org.ID, database.AuditableGroup{}, group.Auditable(members))
+
+	httpapi.Write(ctx, rw, http.StatusCreated, api.scimGroup(group, members))
+}
+
+// scimPatchGroup supports replacing the display name and adding, removing or
+// replacing the members of a group.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchGroupRequest true "Patch group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var req SCIMPatchGroupRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	group, members, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	displayName := scimGroupDisplayName(group)
+	memberIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+	for _, op := range req.Operations {
+		displayName, memberIDs, err = scimApplyGroupOperation(op, displayName, memberIDs)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	api.scimUpdateGroup(rw, r, group, members, displayName, memberIDs)
+}
+
+// scimPutGroup replaces the display name and the members of a group.
+//
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+	memberIDs, err := scimGroupMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err))
+		return
+	}
+
+	group, members, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+	displayName := sGroup.DisplayName
+	if displayName == "" {
+		displayName = scimGroupDisplayName(group)
+	}
+
+	api.scimUpdateGroup(rw, r, group, members, displayName, memberIDs)
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security Authorization
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	group, members, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	err := api.Database.DeleteGroupByID(dbauthz.AsSCIMProvisioner(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	api.scimAuditGroup(r, http.StatusNoContent, database.AuditActionDelete, group.OrganizationID, group.Auditable(members), database.AuditableGroup{})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimUpdateGroup renames the group and sets its members, writing the updated
+// group to the response.
+func (api *API) scimUpdateGroup(rw http.ResponseWriter, r *http.Request, group database.Group, members []database.GroupMember, displayName string, memberIDs []uuid.UUID) {
+	ctx := r.Context()
+	old := group.Auditable(members)
+
+	name, err := scimGroupName(displayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err))
+		return
+	}
+
+	changed := false
+	//nolint:gocritic // SCIM operations are a system user
+	err = api.Database.InTx(func(tx database.Store) error {
+		if name != group.Name || displayName != group.DisplayName {
+			group, err = tx.UpdateGroupByID(dbauthz.AsSCIMProvisioner(ctx), database.UpdateGroupByIDParams{
+				ID:             group.ID,
+				Name:           name,
+				DisplayName:    displayName,
+				AvatarURL:      group.AvatarURL,
+				QuotaAllowance: group.QuotaAllowance,
+			})
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+			changed = true
+		}
+		newMembers, err := scimUpdateGroupMembers(dbauthz.AsSCIMProvisioner(ctx), tx, group, members, memberIDs)
+		if err != nil {
+			return err
+		}
+		if len(newMembers) != len(members) || !scimSameMembers(members, memberIDs) {
+			changed = true
+		}
+		members = newMembers
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name)))
+		return
+	}
+	if err != nil {
+		scimWriteGroupError(rw, err)
+		return
+	}
+	// Do not push an audit log if there is no change.
+	if changed {
+		api.scimAuditGroup(r, http.StatusOK, database.AuditActionWrite, group.OrganizationID, old, group.Auditable(members))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.scimGroup(group, members))
+}
+
+// scimAuditGroup audits a change to a group. SCIM requests are made by the
+// identity provider rather than a user, so they are audited in the background
+// without one.
+func (api *API) scimAuditGroup(r *http.Request, status int, action database.AuditAction, orgID uuid.UUID, oldGroup, newGroup database.AuditableGroup) {
+	fields, err := json.Marshal(SCIMAuditAdditionalFields)
+	if err != nil {
+		api.Logger.Warn(r.Context(), "marshal additional fields", slog.Error(err))
+	}
+	audit.BackgroundAudit(r.Context(), &audit.BackgroundAuditParams[database.AuditableGroup]{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		UserID:           uuid.Nil,
+		RequestID:        httpmw.RequestID(r),
+		Status:           status,
+		Action:           action,
+		OrganizationID:   orgID,
+		IP:               r.RemoteAddr,
+		UserAgent:        r.UserAgent(),
+		AdditionalFields: fields,
+		Old:              oldGroup,
+		New:              newGroup,
+	})
+}
+
+// scimOrganization returns the organization groups are provisioned in.
+func (api *API) scimOrganization(rw http.ResponseWriter, r *http.Request) (database.Organization, bool) {
+	//nolint:gocritic // SCIM operations are a system user
+	org, err := api.Database.GetDefaultOrganization(dbauthz.AsSCIMProvisioner(r.Context()))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("failed to get default organization: %w", err)))
+		return database.Organization{}, false
+	}
+	return org, true
+}
+
+// scimGroupParam returns the group from the "id" URL parameter and its
+// members. The "Everyone" group and groups of other organizations cannot be
+// managed with SCIM and are not found.
+func (api *API) scimGroupParam(rw http.ResponseWriter, r *http.Request) (database.Group, []database.GroupMember, bool) {
+	ctx := r.Context()
+	notFound := func() {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusNotFound, spec.ErrNotFound.Type, xerrors.New("group not found")))
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		notFound()
+		return database.Group{}, nil, false
+	}
+	org, ok := api.scimOrganization(rw, r)
+	if !ok {
+		return database.Group{}, nil, false
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	group, err := api.Database.GetGroupByID(dbauthz.AsSCIMProvisioner(ctx), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (group.OrganizationID != org.ID || group.IsEveryone())) {
+		notFound()
+		return database.Group{}, nil, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return database.Group{}, nil, false
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	members, err := api.Database.GetGroupMembersByGroupID(dbauthz.AsSCIMProvisioner(ctx), database.GetGroupMembersByGroupIDParams{
+		GroupID:       group.ID,
+		IncludeSystem: false,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return database.Group{}, nil, false
+	}
+	return group, members, true
+}
+
+func (api *API) scimGroup(group database.Group, members []database.GroupMember) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: scimGroupDisplayName(group),
+		Meta: SCIMGroupMeta{
+			ResourceType: "Group",
+		},
+	}
+	if locURL, err := api.AccessURL.Parse("/scim/v2/Groups/" + group.ID.String()); err == nil {
+		sGroup.Meta.Location = locURL.String()
+	}
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.UserID.String(),
+			Display: member.UserUsername,
+		})
+	}
+	return sGroup
+}
+
+// scimGroupDisplayName returns the name the identity provider knows the group
+// by. Groups created in Coder may not have a display name.
+func scimGroupDisplayName(group database.Group) string {
+	if group.DisplayName != "" {
+		return group.DisplayName
+	}
+	return group.Name
+}
+
+// scimGroupName derives a valid group name from the display name of a SCIM
+// group, e.g. "Platform Team" becomes "Platform-Team".
+func scimGroupName(displayName string) (string, error) {
+	if displayName == "" {
+		return "", xerrors.New("displayName is required")
+	}
+	if err := codersdk.DisplayNameValid(displayName); err != nil {
+		return "", xerrors.Errorf("invalid displayName %q: %w", displayName, err)
+	}
+	name := strings.Trim(scimGroupNameReplace.ReplaceAllString(displayName, "-"), "-")
+	if name == database.EveryoneGroup {
+		return "", xerrors.Errorf("%q is a reserved group name", name)
+	}
+	if err := codersdk.GroupNameValid(name); err != nil {
+		return "", xerrors.Errorf("cannot derive a group name from displayName %q: %w", displayName, err)
+	}
+	return name, nil
+}
+
+// scimQueryInt parses an integer query parameter, returning def if it is not
+// set.
+func scimQueryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func scimGroupMemberIDs(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("member value %q must be a user ID: %w", member.Value, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// scimApplyGroupOperation applies a single patch operation to the display
+// name and members of a group. Attribute names and operations are case
+// insensitive, as required by RFC 7644.
+func scimApplyGroupOperation(op SCIMPatchGroupOperation, displayName string, memberIDs []uuid.UUID) (string, []uuid.UUID, error) {
+	invalidValue := func(err error) error {
+		return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err)
+	}
+	readMembers := func() ([]uuid.UUID, error) {
+		var members []SCIMGroupMember
+		if err := json.Unmarshal(op.Value, &members); err != nil {
+			return nil, invalidValue(xerrors.Errorf("members must be a list: %w", err))
+		}
+		ids, err := scimGroupMemberIDs(members)
+		if err != nil {
+			return nil, invalidValue(err)
+		}
+		return ids, nil
+	}
+
+	opName := strings.ToLower(op.Op)
+	path := strings.TrimSpace(op.Path)
+	if match := scimMemberPath.FindStringSubmatch(path); match != nil {
+		if opName != "remove" {
+			return "", nil, invalidValue(xerrors.Errorf("unsupported operation %q for path %q", op.Op, op.Path))
+		}
+		value, err := strconv.Unquote(match[1])
+		if err != nil {
+			return "", nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("invalid path %q: %w", op.Path, err))
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return "", nil, invalidValue(xerrors.Errorf("member value %q must be a user ID: %w", value, err))
+		}
+		return displayName, scimRemoveMembers(memberIDs, []uuid.UUID{id}), nil
+	}
+
+	if strings.Contains(path, "[") {
+		return "", nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, xerrors.Errorf("unsupported filter in path %q, only 'members[value eq]' is supported", op.Path))
+	}
+
+	switch strings.ToLower(path) {
+	case "":
+		if opName != "add" && opName != "replace" {
+			return "", nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrNoTarget.Type, xerrors.Errorf("operation %q requires a path", op.Op))
+		}
+		var value struct {
+			DisplayName *string            `json:"displayName"`
+			Members     *[]SCIMGroupMember `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return "", nil, invalidValue(xerrors.Errorf("value must be an object: %w", err))
+		}
+		if value.DisplayName != nil {
+			displayName = *value.DisplayName
+		}
+		if value.Members != nil {
+			ids, err := scimGroupMemberIDs(*value.Members)
+			if err != nil {
+				return "", nil, invalidValue(err)
+			}
+			if opName == "replace" {
+				memberIDs = ids
+			} else {
+				memberIDs = scimAddMembers(memberIDs, ids)
+			}
+		}
+		return displayName, memberIDs, nil
+	case "displayname":
+		if opName == "remove" {
+			return "", nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrMutability.Type, xerrors.New("displayName is required"))
+		}
+		if err := json.Unmarshal(op.Value, &displayName); err != nil {
+			return "", nil, invalidValue(xerrors.Errorf("displayName must be a string: %w", err))
+		}
+		return displayName, memberIDs, nil
+	case "members":
+		switch opName {
+		case "add":
+			ids, err := readMembers()
+			if err != nil {
+				return "", nil, err
+			}
+			return displayName, scimAddMembers(memberIDs, ids), nil
+		case "replace":
+			ids, err := readMembers()
+			if err != nil {
+				return "", nil, err
+			}
+			return displayName, ids, nil
+		case "remove":
+			// Removing the attribute without a value removes all members.
+			if len(op.Value) == 0 || string(op.Value) == "null" {
+				return displayName, []uuid.UUID{}, nil
+			}
+			ids, err := readMembers()
+			if err != nil {
+				return "", nil, err
+			}
+			return displayName, scimRemoveMembers(memberIDs, ids), nil
+		}
+	case "externalid":
+		// The external ID is not stored, the group is always referenced by
+		// its Coder ID.
+		return displayName, memberIDs, nil
+	default:
+		return "", nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("unsupported path %q", op.Path))
+	}
+	return "", nil, invalidValue(xerrors.Errorf("unsupported operation %q", op.Op))
+}
+
+func scimAddMembers(memberIDs []uuid.UUID, add []uuid.UUID) []uuid.UUID {
+	result := append([]uuid.UUID{}, memberIDs...)
+	for _, id := range add {
+		if !scimContainsMember(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func scimRemoveMembers(memberIDs []uuid.UUID, remove []uuid.UUID) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if !scimContainsMember(remove, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func scimContainsMember(memberIDs []uuid.UUID, id uuid.UUID) bool {
+	for _, memberID := range memberIDs {
+		if memberID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func scimSameMembers(members []database.GroupMember, memberIDs []uuid.UUID) bool {
+	for _, member := range members {
+		if !scimContainsMember(memberIDs, member.UserID) {
+			return false
+		}
+	}
+	return true
+}
+
+// errSCIMGroupMember is returned when a member of a group is not a member of
+// the organization of the group.
+var errSCIMGroupMember = xerrors.New("user is not a member of the organization")
+
+// scimUpdateGroupMembers sets the members of the group to memberIDs and
+// returns the new members.
+func scimUpdateGroupMembers(ctx context.Context, tx database.Store, group database.Group, members []database.GroupMember, memberIDs []uuid.UUID) ([]database.GroupMember, error) {
+	current := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		current = append(current, member.UserID)
+	}
+	for _, id := range scimRemoveMembers(memberIDs, current) {
+		_, err := database.ExpectOne(tx.OrganizationMembers(ctx, database.OrganizationMembersParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         id,
+			IncludeSystem:  false,
+		}))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("user %q: %w", id, errSCIMGroupMember)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get organization member %q: %w", id, err)
+		}
+		err = tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			GroupID: group.ID,
+			UserID:  id,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("insert group member %q: %w", id, err)
+		}
+	}
+	for _, id := range scimRemoveMembers(current, memberIDs) {
+		err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  id,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("delete group member %q: %w", id, err)
+		}
+	}
+	newMembers, err := tx.GetGroupMembersByGroupID(ctx, database.GetGroupMembersByGroupIDParams{
+		GroupID:       group.ID,
+		IncludeSystem: false,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get group members: %w", err)
+	}
+	return newMembers, nil
+}
+
+func scimWriteGroupError(rw http.ResponseWriter, err error) {
+	if errors.Is(err, errSCIMGroupMember) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err))
+		return
+	}
+	_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("failed to update group: %w", err)))
+}
@@ -0,0 +1,324 @@
+package coderd_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	scimAPIKey := []byte("hi")
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, *audit.MockAuditor) {
+		mockAudit := audit.NewMock()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options:      &coderdtest.Options{Auditor: mockAudit},
+			SCIMAPIKey:   scimAPIKey,
+			AuditLogging: true,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureAuditLog:     1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		mockAudit.ResetLogs()
+		return client, owner, mockAudit
+	}
+
+	// request sends a SCIM request and decodes the response into res.
+	request := func(ctx context.Context, t *testing.T, client *codersdk.Client, method, path string, body any, res any) int {
+		t.Helper()
+		resp, err := client.Request(ctx, method, path, body, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if res != nil && resp.StatusCode < 400 {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+		} else {
+			_, _ = io.Copy(io.Discard, resp.Body)
+		}
+		return resp.StatusCode
+	}
+
+	// scimType sends a SCIM request that fails and returns the SCIM error
+	// type of the response.
+	scimType := func(ctx context.Context, t *testing.T, client *codersdk.Client, method, path string, body any) string {
+		t.Helper()
+		resp, err := client.Request(ctx, method, path, body, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var scimErr struct {
+			ScimType string `json:"scimType"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&scimErr))
+		return scimErr.ScimType
+	}
+
+	createUser := func(ctx context.Context, t *testing.T, client *codersdk.Client) coderd.SCIMUser {
+		t.Helper()
+		sUser := makeScimUser(t)
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodPost, "/scim/v2/Users", sUser, &sUser))
+		return sUser
+	}
+
+	memberIDs := func(t *testing.T, members []codersdk.ReducedUser) []string {
+		t.Helper()
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			ids = append(ids, member.ID.String())
+		}
+		return ids
+	}
+
+	t.Run("noAuth", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, _ := setup(t)
+
+		res, err := client.Request(ctx, http.MethodGet, "/scim/v2/Groups", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("CreateAndList", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, owner, mockAudit := setup(t)
+		alice := createUser(ctx, t, client)
+		mockAudit.ResetLogs()
+
+		var group coderd.SCIMGroup
+		status := request(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Platform Team",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID}},
+		}, &group)
+		require.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, "Platform Team", group.DisplayName)
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, alice.ID, group.Members[0].Value)
+
+		sdkGroup, err := client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "Platform-Team", sdkGroup.Name)
+		assert.Equal(t, owner.OrganizationID, sdkGroup.OrganizationID)
+		assert.Equal(t, []string{alice.ID}, memberIDs(t, sdkGroup.Members))
+
+		// The audit log is committed after the response is written, so only
+		// check it after a subsequent request.
+		aLogs := mockAudit.AuditLogs()
+		require.Len(t, aLogs, 1)
+		assert.Equal(t, database.AuditActionCreate, aLogs[0].Action)
+		assert.Equal(t, database.ResourceTypeGroup, aLogs[0].ResourceType)
+		assert.Equal(t, owner.OrganizationID, aLogs[0].OrganizationID)
+		// SCIM requests are not made by a user.
+		assert.Equal(t, uuid.Nil, aLogs[0].UserID)
+		assert.JSONEq(t, `{"automatic_actor":"coder","automatic_subsystem":"scim"}`, string(aLogs[0].AdditionalFields))
+
+		var got coderd.SCIMGroup
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodGet, "/scim/v2/Groups/"+group.ID, nil, &got))
+		assert.Equal(t, group, got)
+
+		// Creating the group twice is a conflict.
+		status = request(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "Platform Team"}, nil)
+		assert.Equal(t, http.StatusConflict, status)
+
+		// The "Everyone" group is not listed.
+		var list coderd.SCIMGroupList
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodGet, "/scim/v2/Groups", nil, &list))
+		assert.Equal(t, 1, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, group, list.Resources[0])
+
+		filter := url.Values{
+			"filter":             {`displayName eq "Platform Team"`},
+			"excludedAttributes": {"members"},
+		}
+		list = coderd.SCIMGroupList{}
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodGet, "/scim/v2/Groups?"+filter.Encode(), nil, &list))
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, group.ID, list.Resources[0].ID)
+		assert.Empty(t, list.Resources[0].Members)
+
+		filter = url.Values{"filter": {`displayName eq "Other"`}}
+		list = coderd.SCIMGroupList{}
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodGet, "/scim/v2/Groups?"+filter.Encode(), nil, &list))
+		assert.Equal(t, 0, list.TotalResults)
+		assert.Empty(t, list.Resources)
+
+		// Other filters are rejected rather than ignored.
+		for _, unsupported := range []string{
+			`externalId eq "123"`,
+			`displayName co "Platform"`,
+			`displayName eq "Platform Team" or displayName eq "Other"`,
+			`displayName pr`,
+		} {
+			filter = url.Values{"filter": {unsupported}}
+			assert.Equal(t, "invalidFilter", scimType(ctx, t, client, http.MethodGet, "/scim/v2/Groups?"+filter.Encode(), nil), unsupported)
+		}
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, mockAudit := setup(t)
+		alice := createUser(ctx, t, client)
+		bob := createUser(ctx, t, client)
+
+		var group coderd.SCIMGroup
+		require.Equal(t, http.StatusCreated, request(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Engineering",
+		}, &group))
+		mockAudit.ResetLogs()
+
+		patch := func(t *testing.T, ops ...coderd.SCIMPatchGroupOperation) (coderd.SCIMGroup, int) {
+			t.Helper()
+			var patched coderd.SCIMGroup
+			status := request(ctx, t, client, http.MethodPatch, "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchGroupRequest{
+				Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				Operations: ops,
+			}, &patched)
+			return patched, status
+		}
+		members := func(t *testing.T, users ...coderd.SCIMUser) json.RawMessage {
+			t.Helper()
+			var members []coderd.SCIMGroupMember
+			for _, user := range users {
+				members = append(members, coderd.SCIMGroupMember{Value: user.ID})
+			}
+			raw, err := json.Marshal(members)
+			require.NoError(t, err)
+			return raw
+		}
+
+		// Okta adds members with a path.
+		patched, status := patch(t, coderd.SCIMPatchGroupOperation{Op: "add", Path: "members", Value: members(t, alice, bob)})
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, patched.Members, 2)
+
+		// Okta removes members with a value filter.
+		_, status = patch(t, coderd.SCIMPatchGroupOperation{Op: "remove", Path: `members[value eq "` + alice.ID + `"]`})
+		require.Equal(t, http.StatusOK, status)
+		sdkGroup, err := client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		assert.Equal(t, []string{bob.ID}, memberIDs(t, sdkGroup.Members))
+
+		// Azure capitalizes operations and removes members by value.
+		_, status = patch(t,
+			coderd.SCIMPatchGroupOperation{Op: "Remove", Path: "members", Value: members(t, bob)},
+			coderd.SCIMPatchGroupOperation{Op: "Replace", Path: "displayName", Value: json.RawMessage(`"Engineering Team"`)},
+		)
+		require.Equal(t, http.StatusOK, status)
+		sdkGroup, err = client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		assert.Empty(t, sdkGroup.Members)
+		assert.Equal(t, "Engineering-Team", sdkGroup.Name)
+		assert.Equal(t, "Engineering Team", sdkGroup.DisplayName)
+
+		// Replace without a path.
+		patched, status = patch(t, coderd.SCIMPatchGroupOperation{Op: "replace", Value: json.RawMessage(`{"displayName":"Eng","members":[{"value":"` + alice.ID + `"}]}`)})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Eng", patched.DisplayName)
+		require.Len(t, patched.Members, 1)
+		assert.Equal(t, alice.ID, patched.Members[0].Value)
+
+		_, err = client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		aLogs := mockAudit.AuditLogs()
+		require.Len(t, aLogs, 4)
+		for _, aLog := range aLogs {
+			assert.Equal(t, database.AuditActionWrite, aLog.Action)
+			assert.Equal(t, database.ResourceTypeGroup, aLog.ResourceType)
+		}
+
+		// A patch that changes nothing is not audited.
+		mockAudit.ResetLogs()
+		_, status = patch(t, coderd.SCIMPatchGroupOperation{Op: "add", Path: "members", Value: members(t, alice)})
+		require.Equal(t, http.StatusOK, status)
+		_, err = client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		assert.Empty(t, mockAudit.AuditLogs())
+
+		// Users must be members of the organization.
+		_, status = patch(t, coderd.SCIMPatchGroupOperation{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + uuid.NewString() + `"}]`)})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		_, status = patch(t, coderd.SCIMPatchGroupOperation{Op: "add", Path: "description", Value: json.RawMessage(`"x"`)})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// Only members can be selected by value.
+		assert.Equal(t, "invalidFilter", scimType(ctx, t, client, http.MethodPatch, "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchGroupRequest{
+			Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+			Operations: []coderd.SCIMPatchGroupOperation{{Op: "remove", Path: `members[display eq "alice"]`}},
+		}))
+	})
+
+	t.Run("PutAndDelete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, mockAudit := setup(t)
+		alice := createUser(ctx, t, client)
+		bob := createUser(ctx, t, client)
+
+		var group coderd.SCIMGroup
+		require.Equal(t, http.StatusCreated, request(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Design",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID}},
+		}, &group))
+
+		var replaced coderd.SCIMGroup
+		require.Equal(t, http.StatusOK, request(ctx, t, client, http.MethodPut, "/scim/v2/Groups/"+group.ID, coderd.SCIMGroup{
+			DisplayName: "Design",
+			Members:     []coderd.SCIMGroupMember{{Value: bob.ID}},
+		}, &replaced))
+		require.Len(t, replaced.Members, 1)
+		assert.Equal(t, bob.ID, replaced.Members[0].Value)
+
+		mockAudit.ResetLogs()
+		require.Equal(t, http.StatusNoContent, request(ctx, t, client, http.MethodDelete, "/scim/v2/Groups/"+group.ID, nil, nil))
+		assert.Equal(t, http.StatusNotFound, request(ctx, t, client, http.MethodGet, "/scim/v2/Groups/"+group.ID, nil, nil))
+
+		aLogs := mockAudit.AuditLogs()
+		require.Len(t, aLogs, 1)
+		assert.Equal(t, database.AuditActionDelete, aLogs[0].Action)
+		assert.Equal(t, database.ResourceTypeGroup, aLogs[0].ResourceType)
+	})
+
+	t.Run("Everyone", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, owner, _ := setup(t)
+
+		// The "Everyone" group has the ID of the organization.
+		path := "/scim/v2/Groups/" + owner.OrganizationID.String()
+		assert.Equal(t, http.StatusNotFound, request(ctx, t, client, http.MethodGet, path, nil, nil))
+		assert.Equal(t, http.StatusNotFound, request(ctx, t, client, http.MethodDelete, path, nil, nil))
+		assert.Equal(t, http.StatusBadRequest, request(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "Everyone"}, nil))
+	})
+}
@@ -14,11 +14,14 @@ import (
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
+	"github.com/coder/coder/v2/coderd/coderdtest/samltest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/ldapauth"
+	"github.com/coder/coder/v2/coderd/ldapauth/ldapauthtest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
@@ -1002,6 +1005,103 @@ func TestGroupSync(t *testing.T) {
 	}
 }
 
+// TestLDAPIDPSync ensures directory groups are fed into the same group and
+// role sync used for OIDC claims.
+func TestLDAPIDPSync(t *testing.T) {
+	t.Parallel()
+
+	dir := ldapauthtest.Start(t, ldapauthtest.User{
+		Username: "alice",
+		Password: "alice-password",
+		Email:    "alice@coder.com",
+		Groups:   []string{"engineering", "admins"},
+	})
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.OIDC.GroupField = ldapauth.GroupsClaim
+	dv.OIDC.GroupAutoCreate = true
+	dv.OIDC.UserRoleField = ldapauth.GroupsClaim
+	dv.OIDC.UserRoleMapping = serpent.Struct[map[string][]string]{
+		Value: map[string][]string{
+			"admins": {rbac.RoleTemplateAdmin().String()},
+		},
+	}
+	owner, _ := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+			LDAPConfig: &coderd.LDAPConfig{
+				Config:       dir.Config(),
+				AllowSignups: true,
+			},
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+				codersdk.FeatureTemplateRBAC:       1,
+			},
+		},
+	})
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	_, err := codersdk.New(owner.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+		Username: "alice",
+		Password: "alice-password",
+	})
+	require.NoError(t, err)
+
+	runner := &oidcTestRunner{AdminClient: owner}
+	runner.AssertGroups(t, "alice", []string{"engineering", "admins"})
+	runner.AssertRoles(t, "alice", []string{rbac.RoleTemplateAdmin().String()})
+
+	// Manual role changes are blocked, and the error names the login type.
+	_, err = owner.UpdateUserRoles(ctx, "alice", codersdk.UpdateRoles{
+		Roles: []string{rbac.RoleUserAdmin().String()},
+	})
+	require.ErrorContains(t, err, "Cannot modify roles for LDAP users when role sync is enabled.")
+	require.NotContains(t, err.Error(), "OIDC users")
+}
+
+func TestSAMLIDPSync(t *testing.T) {
+	t.Parallel()
+
+	idp := samltest.NewFakeIDP(t)
+	dv := coderdtest.DeploymentValues(t)
+	dv.OIDC.GroupField = "groups"
+	dv.OIDC.GroupAutoCreate = true
+	dv.OIDC.UserRoleField = "groups"
+	dv.OIDC.UserRoleMapping = serpent.Struct[map[string][]string]{
+		Value: map[string][]string{
+			"admins": {rbac.RoleTemplateAdmin().String()},
+		},
+	}
+	owner, _ := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+			SAMLConfig: idp.SAMLConfig(t, func(cfg *coderd.SAMLConfig) {
+				cfg.AllowSignups = true
+			}),
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+				codersdk.FeatureTemplateRBAC:       1,
+			},
+		},
+	})
+
+	_, _ = idp.Login(t, owner, samltest.User{
+		NameID: "alice@coder.com",
+		Attributes: map[string][]string{
+			"username": {"alice"},
+			"groups":   {"engineering", "admins"},
+		},
+	})
+
+	runner := &oidcTestRunner{AdminClient: owner}
+	runner.AssertGroups(t, "alice", []string{"engineering", "admins"})
+	runner.AssertRoles(t, "alice", []string{rbac.RoleTemplateAdmin().String()})
+}
+
 func TestEnterpriseUserLogin(t *testing.T) {
 	t.Parallel()
 
@@ -604,6 +604,15 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if req.DerpQUICPort < 0 || req.DerpQUICPort > 65535 || (req.DerpQUICPort != 0 && !req.DerpEnabled) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "DerpQUICPort must be a valid port, and requires DerpEnabled.",
+		})
+		return
+	}
+	// #nosec G115 - Safe conversion as the port was validated above
+	derpQUICPort := int32(req.DerpQUICPort)
+
 	startingRegionID, _ := getProxyDERPStartingRegionID(api.Options.BaseDERPMap)
 	// #nosec G115 - Safe conversion as DERP region IDs are small integers expected to be within int32 range
 	regionID := int32(startingRegionID) + proxy.RegionID
@@ -615,6 +624,7 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 			Url:              req.AccessURL,
 			DerpEnabled:      req.DerpEnabled,
 			DerpOnly:         req.DerpOnly,
+			DerpQuicPort:     derpQUICPort,
 			WildcardHostname: req.WildcardHostname,
 			Version:          req.Version,
 		})
@@ -415,6 +415,43 @@ func TestProxyRegisterDeregister(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("DERPQUICPort", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		createRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "quic",
+		})
+		require.NoError(t, err)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		proxyClient.SetSessionToken(createRes.ProxyToken)
+
+		req := wsproxysdk.RegisterWorkspaceProxyRequest{
+			AccessURL:           "https://proxy.coder.test",
+			WildcardHostname:    "*.proxy.coder.test",
+			DerpEnabled:         false,
+			DerpQUICPort:        4443,
+			ReplicaID:           uuid.New(),
+			ReplicaHostname:     "mars",
+			ReplicaRelayAddress: "http://127.0.0.1:8080",
+			Version:             buildinfo.Version(),
+		}
+		// A QUIC port requires DERP.
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, req)
+		require.Error(t, err)
+
+		req.DerpEnabled = true
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, req)
+		require.NoError(t, err)
+
+		proxy, err := db.GetWorkspaceProxyByID(ctx, createRes.Proxy.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, req.DerpQUICPort, proxy.DerpQuicPort)
+	})
+
 	t.Run("ReregisterUpdateReplica", func(t *testing.T) {
 		t.Parallel()
 
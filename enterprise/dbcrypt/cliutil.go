@@ -73,6 +73,42 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 					return xerrors.Errorf("update external auth link user_id=%s provider_id=%s: %w", externalAuthLink.UserID, externalAuthLink.ProviderID, err)
 				}
 			}
+
+			userSecrets, err := cryptTx.GetUserSecretsByUserID(ctx, uid)
+			if err != nil {
+				return xerrors.Errorf("get user secrets for user: %w", err)
+			}
+			for _, userSecret := range userSecrets {
+				if userSecret.ValueKeyID.String == ciphers[0].HexDigest() {
+					log.Debug(ctx, "skipping user secret", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+					continue
+				}
+				if _, err := cryptTx.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+					ID:          userSecret.ID,
+					Description: userSecret.Description,
+					Value:       userSecret.Value,
+					ValueKeyID:  sql.NullString{}, // dbcrypt will update as required
+					EnvName:     userSecret.EnvName,
+					FilePath:    userSecret.FilePath,
+					UpdatedAt:   userSecret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update user secret user_id=%s name=%s: %w", userSecret.UserID, userSecret.Name, err)
+				}
+			}
+
+			totp, err := cryptTx.GetUserTOTPByUserID(ctx, uid)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get totp for user: %w", err)
+			}
+			if err == nil && totp.SecretKeyID.String != ciphers[0].HexDigest() {
+				if err := cryptTx.UpdateUserTOTPSecret(ctx, database.UpdateUserTOTPSecretParams{
+					UserID:      uid,
+					Secret:      totp.Secret,
+					SecretKeyID: sql.NullString{}, // dbcrypt will update as required
+				}); err != nil {
+					return xerrors.Errorf("update totp user_id=%s: %w", uid, err)
+				}
+			}
 			return nil
 		}, &database.TxOptions{
 			Isolation: sql.LevelRepeatableRead,
@@ -94,6 +130,63 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 	return nil
 }
 
+// Rewrap re-wraps all active data keys with the first key provider. Each data
+// key is unwrapped with the provider that wrapped it, so none of the values
+// encrypted with the data keys need to be re-encrypted.
+func Rewrap(ctx context.Context, log slog.Logger, sqlDB *sql.DB, providers []KeyProvider) error {
+	return rewrap(ctx, log, database.New(sqlDB), providers)
+}
+
+func rewrap(ctx context.Context, log slog.Logger, db database.Store, providers []KeyProvider) error {
+	if len(providers) == 0 {
+		return xerrors.New("at least one key provider is required")
+	}
+	providersByID := make(map[string]KeyProvider, len(providers))
+	for _, p := range providers {
+		providersByID[p.KeyID()] = p
+	}
+	newProvider := providers[0]
+
+	return db.InTx(func(tx database.Store) error {
+		keys, err := tx.GetDBCryptKeys(ctx)
+		if err != nil {
+			return xerrors.Errorf("get db crypt keys: %w", err)
+		}
+		for _, k := range keys {
+			if !k.ActiveKeyDigest.Valid || !k.WrappedKey.Valid {
+				continue
+			}
+			if k.WrappingKeyID.String == newProvider.KeyID() {
+				log.Debug(ctx, "skipping data key", slog.F("number", k.Number), slog.F("digest", k.ActiveKeyDigest.String))
+				continue
+			}
+			oldProvider, ok := providersByID[k.WrappingKeyID.String]
+			if !ok {
+				return xerrors.Errorf("data key %d is wrapped by unknown key provider %q", k.Number, k.WrappingKeyID.String)
+			}
+			dataKey, err := unwrapDataKey(ctx, oldProvider, k)
+			if err != nil {
+				return err
+			}
+			wrapped, err := newProvider.WrapKey(ctx, dataKey)
+			if err != nil {
+				return xerrors.Errorf("wrap data key %d with %q: %w", k.Number, newProvider.KeyID(), err)
+			}
+			if err := tx.UpdateDBCryptKeyWrappedKey(ctx, database.UpdateDBCryptKeyWrappedKeyParams{
+				Number:        k.Number,
+				WrappedKey:    b64encode(wrapped),
+				WrappingKeyID: newProvider.KeyID(),
+			}); err != nil {
+				return xerrors.Errorf("update data key %d: %w", k.Number, err)
+			}
+			log.Info(ctx, "re-wrapped data key", slog.F("number", k.Number), slog.F("digest", k.ActiveKeyDigest.String), slog.F("from", oldProvider.KeyID()), slog.F("to", newProvider.KeyID()))
+		}
+		return nil
+	}, &database.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+}
+
 // Decrypt decrypts all user tokens and revokes all ciphers.
 func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
@@ -163,6 +256,42 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 					return xerrors.Errorf("update external auth link user_id=%s provider_id=%s: %w", externalAuthLink.UserID, externalAuthLink.ProviderID, err)
 				}
 			}
+
+			userSecrets, err := tx.GetUserSecretsByUserID(ctx, uid)
+			if err != nil {
+				return xerrors.Errorf("get user secrets for user: %w", err)
+			}
+			for _, userSecret := range userSecrets {
+				if !userSecret.ValueKeyID.Valid {
+					log.Debug(ctx, "skipping user secret", slog.F("user_id", uid), slog.F("current", idx+1))
+					continue
+				}
+				if _, err := tx.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+					ID:          userSecret.ID,
+					Description: userSecret.Description,
+					Value:       userSecret.Value,
+					ValueKeyID:  sql.NullString{}, // we explicitly want to clear the key id
+					EnvName:     userSecret.EnvName,
+					FilePath:    userSecret.FilePath,
+					UpdatedAt:   userSecret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update user secret user_id=%s name=%s: %w", userSecret.UserID, userSecret.Name, err)
+				}
+			}
+
+			totp, err := tx.GetUserTOTPByUserID(ctx, uid)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get totp for user: %w", err)
+			}
+			if err == nil && totp.SecretKeyID.Valid {
+				if err := tx.UpdateUserTOTPSecret(ctx, database.UpdateUserTOTPSecretParams{
+					UserID:      uid,
+					Secret:      totp.Secret,
+					SecretKeyID: sql.NullString{}, // we explicitly want to clear the key id
+				}); err != nil {
+					return xerrors.Errorf("update totp user_id=%s: %w", uid, err)
+				}
+			}
 			return nil
 		}, &database.TxOptions{
 			Isolation: sql.LevelRepeatableRead,
@@ -193,6 +322,10 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+DELETE FROM user_secrets
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM user_totp
+	WHERE secret_key_id IS NOT NULL;
 COMMIT;
 `
 
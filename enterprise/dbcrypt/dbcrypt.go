@@ -276,6 +276,101 @@ func (db *dbCrypt) UpdateExternalAuthLinkRefreshToken(ctx context.Context, param
 	return db.Store.UpdateExternalAuthLinkRefreshToken(ctx, params)
 }
 
+func (db *dbCrypt) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
+	secret, err := db.Store.GetUserSecretByID(ctx, id)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetUserSecretByUserIDAndName(ctx context.Context, params database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	secret, err := db.Store.GetUserSecretByUserIDAndName(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	secrets, err := db.Store.GetUserSecretsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(&secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) InsertUserSecret(ctx context.Context, params database.InsertUserSecretParams) (database.UserSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	secret, err := db.Store.InsertUserSecret(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) UpdateUserSecret(ctx context.Context, params database.UpdateUserSecretParams) (database.UserSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	secret, err := db.Store.UpdateUserSecret(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	totp, err := db.Store.GetUserTOTPByUserID(ctx, userID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := db.decryptField(&totp.Secret, totp.SecretKeyID); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return totp, nil
+}
+
+func (db *dbCrypt) UpsertUserTOTP(ctx context.Context, params database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return database.UserTOTP{}, err
+	}
+	totp, err := db.Store.UpsertUserTOTP(ctx, params)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := db.decryptField(&totp.Secret, totp.SecretKeyID); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return totp, nil
+}
+
+func (db *dbCrypt) UpdateUserTOTPSecret(ctx context.Context, params database.UpdateUserTOTPSecretParams) error {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateUserTOTPSecret(ctx, params)
+}
+
 func (db *dbCrypt) GetCryptoKeys(ctx context.Context) ([]database.CryptoKey, error) {
 	keys, err := db.Store.GetCryptoKeys(ctx)
 	if err != nil {
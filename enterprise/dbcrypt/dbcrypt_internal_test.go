@@ -553,6 +553,186 @@ func TestCryptoKeys(t *testing.T) {
 	})
 }
 
+func TestUserSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertUserSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.UserSecret(t, crypt, database.UserSecret{
+			UserID: user.ID,
+			Value:  "hunter2",
+		})
+		require.Equal(t, "hunter2", secret.Value)
+
+		secret, err := db.GetUserSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), secret.ValueKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "hunter2")
+	})
+
+	t.Run("UpdateUserSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.UserSecret(t, crypt, database.UserSecret{
+			UserID: user.ID,
+		})
+		updated, err := crypt.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+			ID:        secret.ID,
+			Value:     "hunter2",
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", updated.Value)
+
+		secret, err = db.GetUserSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), secret.ValueKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "hunter2")
+	})
+
+	t.Run("GetUserSecretByUserIDAndName", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.UserSecret(t, crypt, database.UserSecret{
+			UserID: user.ID,
+			Value:  "hunter2",
+		})
+		params := database.GetUserSecretByUserIDAndNameParams{
+			UserID: user.ID,
+			Name:   secret.Name,
+		}
+		secret, err := crypt.GetUserSecretByUserIDAndName(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", secret.Value)
+
+		secret, err = db.GetUserSecretByUserIDAndName(ctx, params)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "hunter2")
+	})
+
+	t.Run("GetUserSecretsByUserID", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		_ = dbgen.UserSecret(t, crypt, database.UserSecret{
+			UserID: user.ID,
+			Value:  "hunter2",
+		})
+		secrets, err := crypt.GetUserSecretsByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "hunter2", secrets[0].Value)
+
+		secrets, err = db.GetUserSecretsByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		requireEncryptedEquals(t, ciphers[0], secrets[0].Value, "hunter2")
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, db, database.User{})
+		secret := dbgen.UserSecret(t, db, database.UserSecret{
+			UserID: user.ID,
+			Value:  fakeBase64RandomData(t, 32),
+			ValueKeyID: sql.NullString{
+				String: ciphers[0].HexDigest(),
+				Valid:  true,
+			},
+		})
+		_, err := crypt.GetUserSecretByID(ctx, secret.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestUserTOTP(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("UpsertUserTOTP", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		totp, err := crypt.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+			UserID:    user.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "JBSWY3DPEHPK3PXP", totp.Secret)
+
+		totp, err = db.GetUserTOTPByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), totp.SecretKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], totp.Secret, "JBSWY3DPEHPK3PXP")
+	})
+
+	t.Run("UpdateUserTOTPSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		_, err := db.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+			UserID:    user.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		err = crypt.UpdateUserTOTPSecret(ctx, database.UpdateUserTOTPSecretParams{
+			UserID: user.ID,
+			Secret: "JBSWY3DPEHPK3PXP",
+		})
+		require.NoError(t, err)
+
+		totp, err := db.GetUserTOTPByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), totp.SecretKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], totp.Secret, "JBSWY3DPEHPK3PXP")
+	})
+
+	t.Run("GetUserTOTPByUserID", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, _ := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		_, err := crypt.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+			UserID:    user.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		totp, err := crypt.GetUserTOTPByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "JBSWY3DPEHPK3PXP", totp.Secret)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, db, database.User{})
+		_, err := db.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+			UserID: user.ID,
+			Secret: fakeBase64RandomData(t, 32),
+			SecretKeyID: sql.NullString{
+				String: ciphers[0].HexDigest(),
+				Valid:  true,
+			},
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		_, err = crypt.GetUserTOTPByUserID(ctx, user.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -26,6 +26,12 @@
 //   - revoked_key_digest: the SHA256 digest of the revoked key. If null, the key has not been revoked.
 //   - revoked_at: the time the key was revoked. If null, the key has not been revoked.
 //   - test: the encrypted value of the string "coder". This is used to ensure that the key is valid.
+//   - wrapped_key: the data key wrapped by a KeyProvider. If null, the key is not managed by a KeyProvider.
+//   - wrapping_key_id: the ID of the KeyProvider key that wrapped the data key.
+//
+// Keys are either passed in directly, or are data keys generated by Coder and
+// stored wrapped by a KeyProvider, such as a KMS. Rotating a KeyProvider only
+// re-wraps the data keys, so encrypted values do not need to be rewritten.
 //
 // Encrypted fields are stored in the database as a base64-encoded string.
 // Each encrypted column MUST have a corresponding _key_id column that is a foreign key
@@ -0,0 +1,252 @@
+package dbcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// KeyProvider wraps and unwraps data keys with a key encryption key that is
+// held outside of the database, such as in a KMS.
+//
+// Data keys are the AES-256 keys used by Ciphers. They are stored wrapped in
+// the dbcrypt_keys table, so rotating the key encryption key only requires
+// re-wrapping the data keys rather than re-encrypting every value.
+type KeyProvider interface {
+	// KeyID uniquely identifies the key encryption key. It is stored
+	// alongside every data key wrapped by the provider, and must not
+	// change for as long as the key encryption key is in use.
+	KeyID() string
+	// WrapKey encrypts the given data key.
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key previously returned by WrapKey.
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// ParseKeyProvider returns the KeyProvider described by spec, which is one of:
+//   - file:<path>: a file containing a base64-encoded 32 byte key.
+//   - http(s)://<url>: an HTTP KMS, see NewHTTPKeyProvider. The optional
+//     token_file query parameter names a file containing a bearer token.
+//   - pkcs11:<uri>: a key on a PKCS#11 token such as an HSM, see
+//     parsePKCS11URI.
+func ParseKeyProvider(spec string) (KeyProvider, error) {
+	switch {
+	case strings.HasPrefix(spec, "file:"):
+		return NewFileKeyProvider(strings.TrimPrefix(spec, "file:"))
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		u, err := url.Parse(spec)
+		if err != nil {
+			return nil, xerrors.Errorf("parse url: %w", err)
+		}
+		var token string
+		query := u.Query()
+		if tokenFile := query.Get("token_file"); tokenFile != "" {
+			data, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return nil, xerrors.Errorf("read token file: %w", err)
+			}
+			token = strings.TrimSpace(string(data))
+			query.Del("token_file")
+			u.RawQuery = query.Encode()
+		}
+		return NewHTTPKeyProvider(u.String(), token, nil)
+	case strings.HasPrefix(spec, "pkcs11:"):
+		cfg, err := parsePKCS11URI(spec)
+		if err != nil {
+			return nil, err
+		}
+		token, err := openPKCS11Token(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewPKCS11KeyProvider(token, cfg.keyLabel), nil
+	default:
+		return nil, xerrors.Errorf("unknown key provider %q, must start with file:, http://, https:// or pkcs11:", spec)
+	}
+}
+
+// ParseKeyProviders is a convenience function for parsing multiple key
+// providers.
+func ParseKeyProviders(specs ...string) ([]KeyProvider, error) {
+	providers := make([]KeyProvider, 0, len(specs))
+	for idx, spec := range specs {
+		p, err := ParseKeyProvider(spec)
+		if err != nil {
+			return nil, xerrors.Errorf("key provider %d: %w", idx, err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// NewEnvelopeCiphers returns the ciphers for all active data keys that can be
+// unwrapped by the given key providers. The first cipher is the newest data
+// key wrapped by the first provider. If there is no such data key, a new one
+// is generated, wrapped, and stored in the database.
+func NewEnvelopeCiphers(ctx context.Context, db database.Store, providers ...KeyProvider) ([]Cipher, error) {
+	if len(providers) == 0 {
+		return nil, xerrors.New("at least one key provider is required")
+	}
+	var err error
+	for i := 0; i < 3; i++ {
+		err = ensureDataKey(ctx, db, providers[0])
+		if err == nil {
+			return LoadCiphers(ctx, db, providers...)
+		}
+		// Another replica may have inserted a data key concurrently, in
+		// which case we retry and use theirs.
+		if !database.IsSerializedError(err) && !database.IsUniqueViolation(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// LoadCiphers returns the ciphers for all active data keys that can be
+// unwrapped by the given key providers, newest first. Data keys wrapped by
+// the first provider are returned before any others.
+func LoadCiphers(ctx context.Context, db database.Store, providers ...KeyProvider) ([]Cipher, error) {
+	// nolint: gocritic // This is allowed.
+	keys, err := db.GetDBCryptKeys(dbauthz.AsSystemRestricted(ctx))
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get dbcrypt keys: %w", err)
+	}
+	providersByID := make(map[string]KeyProvider, len(providers))
+	for _, p := range providers {
+		providersByID[p.KeyID()] = p
+	}
+
+	var primary, secondary []Cipher
+	for i := len(keys) - 1; i >= 0; i-- {
+		p, ok := providersByID[keys[i].WrappingKeyID.String]
+		if !ok || !keys[i].ActiveKeyDigest.Valid || !keys[i].WrappedKey.Valid {
+			continue
+		}
+		dataKey, err := unwrapDataKey(ctx, p, keys[i])
+		if err != nil {
+			return nil, err
+		}
+		c, err := cipherAES256(dataKey)
+		if err != nil {
+			return nil, err
+		}
+		if p.KeyID() == providers[0].KeyID() {
+			primary = append(primary, c)
+		} else {
+			secondary = append(secondary, c)
+		}
+	}
+	return append(primary, secondary...), nil
+}
+
+// ensureDataKey inserts a new data key wrapped by the given provider, unless
+// an active one already exists.
+func ensureDataKey(ctx context.Context, db database.Store, provider KeyProvider) error {
+	// nolint: gocritic // This is allowed.
+	authCtx := dbauthz.AsSystemRestricted(ctx)
+	return db.InTx(func(tx database.Store) error {
+		keys, err := tx.GetDBCryptKeys(authCtx)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		var highestNumber int32
+		for _, k := range keys {
+			if k.ActiveKeyDigest.Valid && k.WrappingKeyID.String == provider.KeyID() {
+				return nil
+			}
+			if k.Number > highestNumber {
+				highestNumber = k.Number
+			}
+		}
+
+		dataKey := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return xerrors.Errorf("generate data key: %w", err)
+		}
+		c, err := cipherAES256(dataKey)
+		if err != nil {
+			return err
+		}
+		wrapped, err := provider.WrapKey(ctx, dataKey)
+		if err != nil {
+			return xerrors.Errorf("wrap data key with %q: %w", provider.KeyID(), err)
+		}
+		test, err := c.Encrypt([]byte(testValue))
+		if err != nil {
+			return err
+		}
+		return tx.InsertDBCryptKey(authCtx, database.InsertDBCryptKeyParams{
+			Number:          highestNumber + 1,
+			ActiveKeyDigest: c.HexDigest(),
+			Test:            b64encode(test),
+			WrappedKey:      sql.NullString{String: b64encode(wrapped), Valid: true},
+			WrappingKeyID:   sql.NullString{String: provider.KeyID(), Valid: true},
+		})
+	}, &database.TxOptions{Isolation: sql.LevelRepeatableRead})
+}
+
+// unwrapDataKey unwraps the data key of the given row and ensures that it
+// matches the digest of the row.
+func unwrapDataKey(ctx context.Context, provider KeyProvider, key database.DBCryptKey) ([]byte, error) {
+	wrapped, err := b64decode(key.WrappedKey.String)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed wrapped data key %d: %w", key.Number, err)
+	}
+	dataKey, err := provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, xerrors.Errorf("unwrap data key %d with %q: %w", key.Number, provider.KeyID(), err)
+	}
+	c, err := cipherAES256(dataKey)
+	if err != nil {
+		return nil, xerrors.Errorf("unwrap data key %d: %w", key.Number, err)
+	}
+	if c.HexDigest() != key.ActiveKeyDigest.String {
+		return nil, xerrors.Errorf("unwrapped data key %d has digest %q, expected %q", key.Number, c.HexDigest(), key.ActiveKeyDigest.String)
+	}
+	return dataKey, nil
+}
+
+// NewFileKeyProvider returns a KeyProvider that wraps data keys with the
+// base64-encoded AES-256 key stored in the file at path.
+func NewFileKeyProvider(path string) (KeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read key file: %w", err)
+	}
+	key, err := b64decode(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, xerrors.Errorf("key file must contain a base64-encoded key: %w", err)
+	}
+	c, err := cipherAES256(key)
+	if err != nil {
+		return nil, err
+	}
+	return &fileKeyProvider{cipher: c}, nil
+}
+
+type fileKeyProvider struct {
+	cipher *aes256
+}
+
+// KeyID is derived from the key rather than the path, so that the file can be
+// moved without re-wrapping.
+func (p *fileKeyProvider) KeyID() string {
+	return "file:" + p.cipher.HexDigest()
+}
+
+func (p *fileKeyProvider) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return p.cipher.Encrypt(dataKey)
+}
+
+func (p *fileKeyProvider) UnwrapKey(_ context.Context, wrappedKey []byte) ([]byte, error) {
+	return p.cipher.Decrypt(wrappedKey)
+}
@@ -0,0 +1,126 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// HTTPWrapRequest is the body of a request to the wrap endpoint of an HTTP
+// KMS.
+type HTTPWrapRequest struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// HTTPWrapResponse is the body of a response from the wrap endpoint of an
+// HTTP KMS.
+type HTTPWrapResponse struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// HTTPUnwrapRequest is the body of a request to the unwrap endpoint of an
+// HTTP KMS.
+type HTTPUnwrapRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// HTTPUnwrapResponse is the body of a response from the unwrap endpoint of an
+// HTTP KMS.
+type HTTPUnwrapResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// NewHTTPKeyProvider returns a KeyProvider that wraps data keys with a key
+// held by a KMS that exposes the following endpoints below rawURL:
+//
+//	POST <url>/wrap   HTTPWrapRequest   -> HTTPWrapResponse
+//	POST <url>/unwrap HTTPUnwrapRequest -> HTTPUnwrapResponse
+//
+// Byte fields are base64-encoded. If token is not empty, it is sent as a
+// bearer token. The API is deliberately small so that a thin proxy in front
+// of a cloud KMS, or a local stub, can satisfy it. If client is nil,
+// http.DefaultClient is used.
+func NewHTTPKeyProvider(rawURL string, token string, client *http.Client) (KeyProvider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, xerrors.Errorf("url must be http or https, got %q", u.Scheme)
+	}
+	if u.User != nil {
+		return nil, xerrors.New("url must not contain credentials, use a token instead")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return &httpKeyProvider{
+		url:    u,
+		token:  token,
+		client: client,
+	}, nil
+}
+
+type httpKeyProvider struct {
+	url    *url.URL
+	token  string
+	client *http.Client
+}
+
+func (p *httpKeyProvider) KeyID() string {
+	return p.url.String()
+}
+
+func (p *httpKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var resp HTTPWrapResponse
+	if err := p.do(ctx, "wrap", HTTPWrapRequest{Plaintext: dataKey}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Ciphertext) == 0 {
+		return nil, xerrors.New("kms returned an empty ciphertext")
+	}
+	return resp.Ciphertext, nil
+}
+
+func (p *httpKeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	var resp HTTPUnwrapResponse
+	if err := p.do(ctx, "unwrap", HTTPUnwrapRequest{Ciphertext: wrappedKey}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (p *httpKeyProvider) do(ctx context.Context, op string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url.JoinPath(op).String(), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("%s request: %w", op, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("%s request: unexpected status %s: %s", op, res.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return xerrors.Errorf("decode %s response: %w", op, err)
+	}
+	return nil
+}
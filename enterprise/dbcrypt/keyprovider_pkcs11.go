@@ -0,0 +1,134 @@
+package dbcrypt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// PKCS11Token is the subset of a PKCS#11 token used to wrap data keys.
+type PKCS11Token interface {
+	// Label returns the label of the token.
+	Label() string
+	// WrapKey wraps key with the secret key object labelled keyLabel.
+	WrapKey(ctx context.Context, keyLabel string, key []byte) ([]byte, error)
+	// UnwrapKey reverses WrapKey.
+	UnwrapKey(ctx context.Context, keyLabel string, wrappedKey []byte) ([]byte, error)
+}
+
+// NewPKCS11KeyProvider returns a KeyProvider that wraps data keys with the
+// secret key object labelled keyLabel on the given token. The key never
+// leaves the token.
+func NewPKCS11KeyProvider(token PKCS11Token, keyLabel string) KeyProvider {
+	return &pkcs11KeyProvider{token: token, keyLabel: keyLabel}
+}
+
+type pkcs11KeyProvider struct {
+	token    PKCS11Token
+	keyLabel string
+}
+
+// KeyID is a PKCS#11 URI as described in RFC 7512. It only names the token and
+// the key, so that the module can be moved or the slot renumbered without
+// re-wrapping.
+func (p *pkcs11KeyProvider) KeyID() string {
+	return fmt.Sprintf("pkcs11:token=%s;object=%s", url.PathEscape(p.token.Label()), url.PathEscape(p.keyLabel))
+}
+
+func (p *pkcs11KeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return p.token.WrapKey(ctx, p.keyLabel, dataKey)
+}
+
+func (p *pkcs11KeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	return p.token.UnwrapKey(ctx, p.keyLabel, wrappedKey)
+}
+
+// pkcs11Config is a parsed PKCS#11 URI.
+type pkcs11Config struct {
+	modulePath string
+	// Either tokenLabel or slotID selects the token.
+	tokenLabel string
+	slotID     *uint
+	keyLabel   string
+	pin        string
+}
+
+// parsePKCS11URI parses a PKCS#11 URI as described in RFC 7512, e.g.
+//
+//	pkcs11:token=coder;object=kek?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=/run/secrets/pin
+//
+// The token is selected by the token or slot-id attribute, and the key by the
+// object attribute. pin-source names a file containing the user PIN.
+func parsePKCS11URI(spec string) (pkcs11Config, error) {
+	rest, ok := strings.CutPrefix(spec, "pkcs11:")
+	if !ok {
+		return pkcs11Config{}, xerrors.Errorf("pkcs11 uri must start with pkcs11:, got %q", spec)
+	}
+	path, rawQuery, _ := strings.Cut(rest, "?")
+
+	var cfg pkcs11Config
+	for _, attr := range strings.Split(path, ";") {
+		if attr == "" {
+			continue
+		}
+		name, rawValue, ok := strings.Cut(attr, "=")
+		if !ok {
+			return pkcs11Config{}, xerrors.Errorf("pkcs11 uri attribute %q must be of the form name=value", attr)
+		}
+		value, err := url.PathUnescape(rawValue)
+		if err != nil {
+			return pkcs11Config{}, xerrors.Errorf("pkcs11 uri attribute %q: %w", name, err)
+		}
+		switch name {
+		case "token":
+			cfg.tokenLabel = value
+		case "object":
+			cfg.keyLabel = value
+		case "slot-id":
+			id, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return pkcs11Config{}, xerrors.Errorf("pkcs11 uri slot-id %q must be a number", value)
+			}
+			slotID := uint(id)
+			cfg.slotID = &slotID
+		default:
+			return pkcs11Config{}, xerrors.Errorf("unsupported pkcs11 uri attribute %q", name)
+		}
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return pkcs11Config{}, xerrors.Errorf("parse pkcs11 uri query: %w", err)
+	}
+	for name, values := range query {
+		switch name {
+		case "module-path":
+			cfg.modulePath = values[0]
+		case "pin-source":
+			// RFC 7512 allows the PIN source to be a file URI.
+			data, err := os.ReadFile(strings.TrimPrefix(values[0], "file:"))
+			if err != nil {
+				return pkcs11Config{}, xerrors.Errorf("read pin file: %w", err)
+			}
+			cfg.pin = strings.TrimSpace(string(data))
+		default:
+			return pkcs11Config{}, xerrors.Errorf("unsupported pkcs11 uri query attribute %q", name)
+		}
+	}
+
+	if cfg.modulePath == "" {
+		return pkcs11Config{}, xerrors.New("pkcs11 uri must set module-path")
+	}
+	if cfg.keyLabel == "" {
+		return pkcs11Config{}, xerrors.New("pkcs11 uri must set object to the label of the key")
+	}
+	if cfg.tokenLabel == "" && cfg.slotID == nil {
+		return pkcs11Config{}, xerrors.New("pkcs11 uri must set token or slot-id")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,168 @@
+//go:build cgo
+
+package dbcrypt
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"golang.org/x/xerrors"
+)
+
+// openPKCS11Token loads the PKCS#11 module and opens a session on the token
+// described by cfg. The session is kept open for the lifetime of the process.
+func openPKCS11Token(cfg pkcs11Config) (PKCS11Token, error) {
+	ctx := pkcs11.New(cfg.modulePath)
+	if ctx == nil {
+		return nil, xerrors.Errorf("load pkcs11 module %q", cfg.modulePath)
+	}
+	err := ctx.Initialize()
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		return nil, xerrors.Errorf("initialize pkcs11 module: %w", err)
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return nil, xerrors.Errorf("list pkcs11 slots: %w", err)
+	}
+	var (
+		slot  uint
+		label string
+		found bool
+	)
+	for _, s := range slots {
+		info, err := ctx.GetTokenInfo(s)
+		if err != nil {
+			return nil, xerrors.Errorf("get pkcs11 token info for slot %d: %w", s, err)
+		}
+		if cfg.slotID != nil && *cfg.slotID != s {
+			continue
+		}
+		if cfg.tokenLabel != "" && cfg.tokenLabel != info.Label {
+			continue
+		}
+		slot, label, found = s, info.Label, true
+		break
+	}
+	if !found {
+		return nil, xerrors.New("pkcs11 token not found")
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, xerrors.Errorf("open pkcs11 session: %w", err)
+	}
+	if cfg.pin != "" {
+		err = ctx.Login(session, pkcs11.CKU_USER, cfg.pin)
+		if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			_ = ctx.CloseSession(session)
+			return nil, xerrors.Errorf("log in to pkcs11 token: %w", err)
+		}
+	}
+	return &pkcs11Token{ctx: ctx, session: session, label: label}, nil
+}
+
+type pkcs11Token struct {
+	// mu serializes use of the session, which PKCS#11 does not allow to be
+	// used concurrently.
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	label   string
+}
+
+func (t *pkcs11Token) Label() string {
+	return t.label
+}
+
+// wrapMechanism is AES key wrap with padding (RFC 5649).
+var wrapMechanism = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_WRAP_PAD, nil)}
+
+// WrapKey imports the data key as a temporary session object so that the token
+// can wrap it.
+func (t *pkcs11Token) WrapKey(_ context.Context, keyLabel string, key []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kek, err := t.findKey(keyLabel)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := t.ctx.CreateObject(t.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, key),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("import data key: %w", err)
+	}
+	defer func() {
+		_ = t.ctx.DestroyObject(t.session, obj)
+	}()
+	wrapped, err := t.ctx.WrapKey(t.session, wrapMechanism, kek, obj)
+	if err != nil {
+		return nil, xerrors.Errorf("wrap data key: %w", err)
+	}
+	return wrapped, nil
+}
+
+// UnwrapKey unwraps the data key into a temporary session object and reads
+// its value back.
+func (t *pkcs11Token) UnwrapKey(_ context.Context, keyLabel string, wrappedKey []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kek, err := t.findKey(keyLabel)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := t.ctx.UnwrapKey(t.session, wrapMechanism, kek, wrappedKey, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("unwrap data key: %w", err)
+	}
+	defer func() {
+		_ = t.ctx.DestroyObject(t.session, obj)
+	}()
+	attrs, err := t.ctx.GetAttributeValue(t.session, obj, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("read unwrapped data key: %w", err)
+	}
+	return attrs[0].Value, nil
+}
+
+// findKey returns the secret key object with the given label. t.mu must be
+// held.
+func (t *pkcs11Token) findKey(label string) (pkcs11.ObjectHandle, error) {
+	err := t.ctx.FindObjectsInit(t.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("find pkcs11 key: %w", err)
+	}
+	objs, _, err := t.ctx.FindObjects(t.session, 2)
+	_ = t.ctx.FindObjectsFinal(t.session)
+	if err != nil {
+		return 0, xerrors.Errorf("find pkcs11 key: %w", err)
+	}
+	switch len(objs) {
+	case 0:
+		return 0, xerrors.Errorf("pkcs11 key %q not found on token %q", label, t.label)
+	case 1:
+		return objs[0], nil
+	default:
+		return 0, xerrors.Errorf("multiple pkcs11 keys labelled %q on token %q", label, t.label)
+	}
+}
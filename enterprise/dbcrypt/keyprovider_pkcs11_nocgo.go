@@ -0,0 +1,10 @@
+//go:build !cgo
+
+package dbcrypt
+
+import "golang.org/x/xerrors"
+
+// openPKCS11Token fails because loading a PKCS#11 module requires cgo.
+func openPKCS11Token(pkcs11Config) (PKCS11Token, error) {
+	return nil, xerrors.New("pkcs11 key providers require a build of Coder with cgo enabled")
+}
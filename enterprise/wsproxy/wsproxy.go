@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -18,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quic-go/quic-go"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/xerrors"
@@ -75,6 +77,9 @@ type Options struct {
 	DisablePathApps        bool
 	DERPEnabled            bool
 	DERPServerRelayAddress string
+	// DERPQUICListener is optional, and accepts DERP over QUIC connections
+	// when DERP is enabled. The server closes it.
+	DERPQUICListener *quic.Listener
 	// DERPOnly determines whether this proxy only provides DERP and does not
 	// provide access to workspace apps/terminal.
 	DERPOnly bool
@@ -140,6 +145,7 @@ type Server struct {
 	// DERP
 	derpMesh                *derpmesh.Mesh
 	derpMeshTLSConfig       *tls.Config
+	derpQUICServer          *tailnet.DERPQUICServer
 	replicaPingSingleflight singleflight.Group
 	replicaErrMut           sync.Mutex
 	replicaErr              string
@@ -199,6 +205,15 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		return nil, xerrors.Errorf("create DERP mesh tls config: %w", err)
 	}
 	derpServer := derp.NewServer(key.NewNode(), tailnet.Logger(opts.Logger.Named("net.derp")))
+	var derpQUICPort int
+	if opts.DERPQUICListener != nil {
+		if !opts.DERPEnabled {
+			_ = opts.DERPQUICListener.Close()
+			opts.DERPQUICListener = nil
+		} else if addr, ok := opts.DERPQUICListener.Addr().(*net.UDPAddr); ok {
+			derpQUICPort = addr.Port
+		}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -252,6 +267,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 			ReplicaHostname:     cliutil.Hostname(),
 			ReplicaError:        "",
 			ReplicaRelayAddress: opts.DERPServerRelayAddress,
+			DerpQUICPort:        derpQUICPort,
 			Version:             buildinfo.Version(),
 		},
 		MutateFn:   s.mutateRegister,
@@ -264,6 +280,10 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	s.registerLoop = registerLoop
 
 	derpServer.SetMeshKey(regResp.DERPMeshKey)
+	if opts.DERPQUICListener != nil {
+		s.derpQUICServer = tailnet.NewDERPQUICServer(opts.Logger.Named("net.derpquic"), derpServer, opts.DERPQUICListener)
+		opts.PrometheusRegistry.MustRegister(s.derpQUICServer)
+	}
 	err = s.handleRegister(regResp)
 	if err != nil {
 		return nil, xerrors.Errorf("handle register: %w", err)
@@ -452,6 +472,9 @@ func (s *Server) Close() error {
 	var err error
 	s.registerLoop.Close()
 	s.derpCloseFunc()
+	if s.derpQUICServer != nil {
+		_ = s.derpQUICServer.Close()
+	}
 	appServerErr := s.AppServer.Close()
 	if appServerErr != nil {
 		err = multierror.Append(err, appServerErr)
@@ -179,6 +179,9 @@ type RegisterWorkspaceProxyRequest struct {
 	// DerpOnly indicates whether the proxy should only be included in the DERP
 	// map and should not be used for serving apps.
 	DerpOnly bool `json:"derp_only"`
+	// DerpQUICPort is the UDP port the proxy accepts DERP over QUIC
+	// connections on, or 0 if it doesn't.
+	DerpQUICPort int `json:"derp_quic_port"`
 
 	// ReplicaID is a unique identifier for the replica of the proxy that is
 	// registering. It should be generated by the client on startup and
@@ -68,8 +68,12 @@ type agentDevcontainerAttributes struct {
 }
 
 type agentResourcesMonitoring struct {
-	Memory  []agentMemoryResourceMonitor `mapstructure:"memory"`
-	Volumes []agentVolumeResourceMonitor `mapstructure:"volume"`
+	Memory    []agentMemoryResourceMonitor    `mapstructure:"memory"`
+	Volumes   []agentVolumeResourceMonitor    `mapstructure:"volume"`
+	CPU       []agentCPUResourceMonitor       `mapstructure:"cpu"`
+	Load      []agentLoadResourceMonitor      `mapstructure:"load"`
+	Inodes    []agentInodesResourceMonitor    `mapstructure:"inodes"`
+	Processes []agentProcessesResourceMonitor `mapstructure:"processes"`
 }
 
 type agentMemoryResourceMonitor struct {
@@ -83,6 +87,27 @@ type agentVolumeResourceMonitor struct {
 	Threshold int32  `mapstructure:"threshold"`
 }
 
+type agentCPUResourceMonitor struct {
+	Enabled   bool  `mapstructure:"enabled"`
+	Threshold int32 `mapstructure:"threshold"`
+}
+
+type agentLoadResourceMonitor struct {
+	Enabled   bool  `mapstructure:"enabled"`
+	Threshold int32 `mapstructure:"threshold"`
+}
+
+type agentInodesResourceMonitor struct {
+	Path      string `mapstructure:"path"`
+	Enabled   bool   `mapstructure:"enabled"`
+	Threshold int32  `mapstructure:"threshold"`
+}
+
+type agentProcessesResourceMonitor struct {
+	Enabled   bool  `mapstructure:"enabled"`
+	Threshold int32 `mapstructure:"threshold"`
+}
+
 type agentDisplayAppsAttributes struct {
 	VSCode               bool `mapstructure:"vscode"`
 	VSCodeInsiders       bool `mapstructure:"vscode_insiders"`
@@ -132,6 +157,18 @@ type agentScriptAttributes struct {
 	TimeoutSeconds   int32  `mapstructure:"timeout"`
 }
 
+// A mapping of attributes on the "coder_service" resource.
+type agentServiceAttributes struct {
+	AgentID             string                     `mapstructure:"agent_id"`
+	DisplayName         string                     `mapstructure:"display_name"`
+	Icon                string                     `mapstructure:"icon"`
+	Command             string                     `mapstructure:"command"`
+	Restart             string                     `mapstructure:"restart"`
+	RestartDelaySeconds int32                      `mapstructure:"restart_delay"`
+	ReadinessProbe      []appHealthcheckAttributes `mapstructure:"readiness_probe"`
+	LogPath             string                     `mapstructure:"log_path"`
+}
+
 // A mapping of attributes on the "healthcheck" resource.
 type appHealthcheckAttributes struct {
 	URL       string `mapstructure:"url"`
@@ -286,6 +323,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 
 			resourcesMonitoring := &proto.ResourcesMonitoring{
 				Volumes: make([]*proto.VolumeResourceMonitor, 0),
+				Inodes:  make([]*proto.InodesResourceMonitor, 0),
 			}
 
 			for _, resource := range attrs.ResourcesMonitoring {
@@ -307,6 +345,34 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 				}
 			}
 
+			for _, resource := range attrs.ResourcesMonitoring {
+				for _, cpu := range resource.CPU {
+					resourcesMonitoring.Cpu = &proto.CPUResourceMonitor{
+						Enabled:   cpu.Enabled,
+						Threshold: cpu.Threshold,
+					}
+				}
+				for _, load := range resource.Load {
+					resourcesMonitoring.Load = &proto.LoadResourceMonitor{
+						Enabled:   load.Enabled,
+						Threshold: load.Threshold,
+					}
+				}
+				for _, inodes := range resource.Inodes {
+					resourcesMonitoring.Inodes = append(resourcesMonitoring.Inodes, &proto.InodesResourceMonitor{
+						Path:      inodes.Path,
+						Enabled:   inodes.Enabled,
+						Threshold: inodes.Threshold,
+					})
+				}
+				for _, processes := range resource.Processes {
+					resourcesMonitoring.Processes = &proto.ProcessesResourceMonitor{
+						Enabled:   processes.Enabled,
+						Threshold: processes.Threshold,
+					}
+				}
+			}
+
 			agent := &proto.Agent{
 				Name:                     tfResource.Name,
 				Id:                       attrs.ID,
@@ -601,6 +667,57 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 		}
 	}
 
+	// Associate services with agents.
+	for _, resources := range tfResourcesByLabel {
+		for _, resource := range resources {
+			if resource.Type != "coder_service" {
+				continue
+			}
+			var attrs agentServiceAttributes
+			err = mapstructure.Decode(resource.AttributeValues, &attrs)
+			if err != nil {
+				return nil, xerrors.Errorf("decode service attributes: %w", err)
+			}
+			var restartPolicy proto.ServiceRestartPolicy
+			switch strings.ToLower(attrs.Restart) {
+			case "", "always":
+				restartPolicy = proto.ServiceRestartPolicy_RESTART_ALWAYS
+			case "on-failure":
+				restartPolicy = proto.ServiceRestartPolicy_RESTART_ON_FAILURE
+			case "never":
+				restartPolicy = proto.ServiceRestartPolicy_RESTART_NEVER
+			default:
+				return nil, xerrors.Errorf("invalid restart policy %q for service %q", attrs.Restart, resource.Name)
+			}
+			var readinessProbe *proto.Healthcheck
+			if len(attrs.ReadinessProbe) != 0 {
+				readinessProbe = &proto.Healthcheck{
+					Url:       attrs.ReadinessProbe[0].URL,
+					Interval:  attrs.ReadinessProbe[0].Interval,
+					Threshold: attrs.ReadinessProbe[0].Threshold,
+				}
+			}
+			for _, agents := range resourceAgents {
+				for _, agent := range agents {
+					// Find agents with the matching ID and associate them!
+					if !dependsOnAgent(graph, agent, attrs.AgentID, resource) {
+						continue
+					}
+					agent.Services = append(agent.Services, &proto.Service{
+						Name:                resource.Name,
+						DisplayName:         attrs.DisplayName,
+						Icon:                attrs.Icon,
+						Command:             attrs.Command,
+						RestartPolicy:       restartPolicy,
+						RestartDelaySeconds: attrs.RestartDelaySeconds,
+						ReadinessProbe:      readinessProbe,
+						LogPath:             attrs.LogPath,
+					})
+				}
+			}
+		}
+	}
+
 	// Associate Dev Containers with agents.
 	for _, resources := range tfResourcesByLabel {
 		for _, resource := range resources {
@@ -707,7 +824,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			if resource.Mode == tfjson.DataResourceMode {
 				continue
 			}
-			if resource.Type == "coder_script" || resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
+			if resource.Type == "coder_script" || resource.Type == "coder_service" || resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
 				continue
 			}
 			label := convertAddressToLabel(resource.Address)
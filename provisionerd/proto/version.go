@@ -29,9 +29,17 @@ import "github.com/coder/coder/v2/apiversion"
 //     `ttl` to define TTL-based expiration for unclaimed prebuilds.
 //   - Add `group` field to `App`
 //   - Add `form_type` field to parameters
+//
+// API v1.7:
+//   - Add `cpu`, `load`, `inodes` and `processes` fields to `resources_monitoring`
+//     in the Agent.
+//
+// API v1.8:
+//   - Add `services` field to the Agent, describing long-running processes
+//     supervised by the agent.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 6
+	CurrentMinor = 8
 )
 
 // CurrentVersion is the current provisionerd API version.
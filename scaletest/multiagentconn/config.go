@@ -0,0 +1,40 @@
+package multiagentconn
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+type Config struct {
+	// AgentIDs are the IDs of the agents to connect to over a single tailnet
+	// connection.
+	AgentIDs []uuid.UUID `json:"agent_ids"`
+	// BlockEndpoints forces the connections through DERP.
+	BlockEndpoints bool `json:"block_endpoints"`
+	// HoldDuration is the duration to hold the connections open for after all
+	// agents are reachable. If set to 0, the connections are closed
+	// immediately.
+	HoldDuration httpapi.Duration `json:"hold_duration"`
+}
+
+func (c Config) Validate() error {
+	if len(c.AgentIDs) == 0 {
+		return xerrors.New("agent_ids must be set")
+	}
+	seen := make(map[uuid.UUID]struct{}, len(c.AgentIDs))
+	for i, id := range c.AgentIDs {
+		if id == uuid.Nil {
+			return xerrors.Errorf("agent_ids[%d] must be set", i)
+		}
+		if _, ok := seen[id]; ok {
+			return xerrors.Errorf("agent_ids[%d] is a duplicate of %s", i, id)
+		}
+		seen[id] = struct{}{}
+	}
+	if c.HoldDuration < 0 {
+		return xerrors.New("hold_duration must be a positive value")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package multiagentconn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/multiagentconn"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	cases := []struct {
+		name        string
+		config      multiagentconn.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: multiagentconn.Config{
+				AgentIDs:     []uuid.UUID{id, uuid.New()},
+				HoldDuration: httpapi.Duration(time.Minute),
+			},
+		},
+		{
+			name:        "NoAgentIDs",
+			config:      multiagentconn.Config{},
+			errContains: "agent_ids must be set",
+		},
+		{
+			name: "NilAgentID",
+			config: multiagentconn.Config{
+				AgentIDs: []uuid.UUID{id, uuid.Nil},
+			},
+			errContains: "agent_ids[1] must be set",
+		},
+		{
+			name: "DuplicateAgentID",
+			config: multiagentconn.Config{
+				AgentIDs: []uuid.UUID{id, id},
+			},
+			errContains: "agent_ids[1] is a duplicate",
+		},
+		{
+			name: "NegativeHoldDuration",
+			config: multiagentconn.Config{
+				AgentIDs:     []uuid.UUID{id},
+				HoldDuration: -1,
+			},
+			errContains: "hold_duration must be a positive value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
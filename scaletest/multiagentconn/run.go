@@ -0,0 +1,140 @@
+package multiagentconn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+const defaultPingTimeout = 5 * time.Second
+
+// Result is the memory used by the connection to the agents.
+type Result struct {
+	// Peers is the number of agents connected over the tailnet connection.
+	Peers int
+	// BaseHeapBytes is the heap in use with no agents connected.
+	BaseHeapBytes uint64
+	// HeapBytes is the heap in use with all agents connected.
+	HeapBytes uint64
+}
+
+// BytesPerPeer is the heap each agent adds to the connection.
+func (r Result) BytesPerPeer() uint64 {
+	if r.Peers == 0 || r.HeapBytes < r.BaseHeapBytes {
+		return 0
+	}
+	return (r.HeapBytes - r.BaseHeapBytes) / uint64(r.Peers)
+}
+
+// Runner connects to many agents over a single tailnet connection and
+// measures the memory used per agent. The heap of the whole process is
+// measured, so runners should not be executed concurrently.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	result Result
+}
+
+var _ harness.Runnable = &Runner{}
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, _ string, w io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs := loadtestutil.NewSyncWriter(w)
+	defer logs.Close()
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	_, _ = fmt.Fprintln(logs, "Opening tailnet connection...")
+	multiConn, err := workspacesdk.New(r.client).
+		DialMultiAgent(ctx, &workspacesdk.DialAgentOptions{
+			Logger:         logger.Named("multiagentconn"),
+			BlockEndpoints: r.cfg.BlockEndpoints,
+		})
+	if err != nil {
+		return xerrors.Errorf("dial tailnet: %w", err)
+	}
+	defer multiConn.Close()
+
+	r.result = Result{BaseHeapBytes: heapInUse()}
+	_, _ = fmt.Fprintf(logs, "\tHeap with no agents: %d bytes\n", r.result.BaseHeapBytes)
+
+	_, _ = fmt.Fprintf(logs, "Connecting to %d agents...\n", len(r.cfg.AgentIDs))
+	conns := make([]*workspacesdk.AgentConn, 0, len(r.cfg.AgentIDs))
+	defer func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	for i, agentID := range r.cfg.AgentIDs {
+		start := time.Now()
+		conn, err := multiConn.DialAgent(ctx, agentID)
+		if err != nil {
+			return xerrors.Errorf("dial agent %s: %w", agentID, err)
+		}
+		conns = append(conns, conn)
+
+		pingCtx, cancel := context.WithTimeout(ctx, defaultPingTimeout)
+		_, p2p, _, err := conn.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			return xerrors.Errorf("ping agent %s: %w", agentID, err)
+		}
+		_, _ = fmt.Fprintf(logs, "\tAgent %d/%d %s reachable after %s, p2p = %v\n", i+1, len(r.cfg.AgentIDs), agentID, time.Since(start), p2p)
+	}
+
+	r.result.Peers = len(conns)
+	r.result.HeapBytes = heapInUse()
+	_, _ = fmt.Fprintf(logs, "\tHeap with %d agents: %d bytes\n", r.result.Peers, r.result.HeapBytes)
+	_, _ = fmt.Fprintf(logs, "\tHeap per agent: %d bytes\n", r.result.BytesPerPeer())
+
+	if r.cfg.HoldDuration > 0 {
+		_, _ = fmt.Fprintf(logs, "Waiting for %s...\n", time.Duration(r.cfg.HoldDuration))
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("hold connections: %w", ctx.Err())
+		case <-time.After(time.Duration(r.cfg.HoldDuration)):
+		}
+	}
+
+	err = multiConn.Close()
+	if err != nil {
+		return xerrors.Errorf("close connection: %w", err)
+	}
+	return nil
+}
+
+// Result returns the memory measured by the last run.
+func (r *Runner) Result() Result {
+	return r.result
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
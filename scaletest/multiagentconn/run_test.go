@@ -0,0 +1,55 @@
+package multiagentconn_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/scaletest/multiagentconn"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+
+	const agents = 3
+	var agentIDs []uuid.UUID
+	for range agents {
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+		agentIDs = append(agentIDs, resources[0].Agents[0].ID)
+	}
+
+	runner := multiagentconn.NewRunner(client, multiagentconn.Config{
+		AgentIDs:       agentIDs,
+		BlockEndpoints: true,
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	logs := bytes.NewBuffer(nil)
+	err := runner.Run(ctx, "1", logs)
+	logStr := logs.String()
+	t.Log("Runner logs:\n\n" + logStr)
+	require.NoError(t, err)
+
+	require.Contains(t, logStr, "Connecting to 3 agents")
+	require.Contains(t, logStr, "Agent 3/3")
+	require.Contains(t, logStr, "Heap per agent")
+	result := runner.Result()
+	require.Equal(t, agents, result.Peers)
+	require.NotZero(t, result.BaseHeapBytes)
+	require.NotZero(t, result.HeapBytes)
+}
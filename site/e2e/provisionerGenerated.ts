@@ -37,6 +37,13 @@ export enum AppSharingLevel {
   UNRECOGNIZED = -1,
 }
 
+export enum ServiceRestartPolicy {
+  RESTART_ALWAYS = 0,
+  RESTART_ON_FAILURE = 1,
+  RESTART_NEVER = 2,
+  UNRECOGNIZED = -1,
+}
+
 export enum AppOpenIn {
   /** @deprecated */
   WINDOW = 0,
@@ -206,6 +213,7 @@ export interface Agent {
   resourcesMonitoring: ResourcesMonitoring | undefined;
   devcontainers: Devcontainer[];
   apiKeyScope: string;
+  services: Service[];
 }
 
 export interface Agent_Metadata {
@@ -225,6 +233,10 @@ export interface Agent_EnvEntry {
 export interface ResourcesMonitoring {
   memory: MemoryResourceMonitor | undefined;
   volumes: VolumeResourceMonitor[];
+  cpu: CPUResourceMonitor | undefined;
+  load: LoadResourceMonitor | undefined;
+  inodes: InodesResourceMonitor[];
+  processes: ProcessesResourceMonitor | undefined;
 }
 
 export interface MemoryResourceMonitor {
@@ -238,6 +250,31 @@ export interface VolumeResourceMonitor {
   threshold: number;
 }
 
+export interface CPUResourceMonitor {
+  enabled: boolean;
+  /** The percentage of available CPU used. */
+  threshold: number;
+}
+
+export interface LoadResourceMonitor {
+  enabled: boolean;
+  /** The 1 minute load average as a percentage of the number of cores. */
+  threshold: number;
+}
+
+export interface InodesResourceMonitor {
+  path: string;
+  enabled: boolean;
+  /** The percentage of inodes used. */
+  threshold: number;
+}
+
+export interface ProcessesResourceMonitor {
+  enabled: boolean;
+  /** The number of processes. */
+  threshold: number;
+}
+
 export interface DisplayApps {
   vscode: boolean;
   vscodeInsiders: boolean;
@@ -264,6 +301,18 @@ export interface Script {
   logPath: string;
 }
 
+/** Service represents a long-running process supervised by the agent. */
+export interface Service {
+  name: string;
+  displayName: string;
+  icon: string;
+  command: string;
+  restartPolicy: ServiceRestartPolicy;
+  restartDelaySeconds: number;
+  readinessProbe: Healthcheck | undefined;
+  logPath: string;
+}
+
 export interface Devcontainer {
   workspaceFolder: string;
   configPath: string;
@@ -756,6 +805,9 @@ export const Agent = {
     if (message.apiKeyScope !== "") {
       writer.uint32(210).string(message.apiKeyScope);
     }
+    for (const v of message.services) {
+      Service.encode(v!, writer.uint32(218).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -804,6 +856,18 @@ export const ResourcesMonitoring = {
     for (const v of message.volumes) {
       VolumeResourceMonitor.encode(v!, writer.uint32(18).fork()).ldelim();
     }
+    if (message.cpu !== undefined) {
+      CPUResourceMonitor.encode(message.cpu, writer.uint32(26).fork()).ldelim();
+    }
+    if (message.load !== undefined) {
+      LoadResourceMonitor.encode(message.load, writer.uint32(34).fork()).ldelim();
+    }
+    for (const v of message.inodes) {
+      InodesResourceMonitor.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
+    if (message.processes !== undefined) {
+      ProcessesResourceMonitor.encode(message.processes, writer.uint32(50).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -835,6 +899,57 @@ export const VolumeResourceMonitor = {
   },
 };
 
+export const CPUResourceMonitor = {
+  encode(message: CPUResourceMonitor, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.threshold !== 0) {
+      writer.uint32(16).int32(message.threshold);
+    }
+    return writer;
+  },
+};
+
+export const LoadResourceMonitor = {
+  encode(message: LoadResourceMonitor, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.threshold !== 0) {
+      writer.uint32(16).int32(message.threshold);
+    }
+    return writer;
+  },
+};
+
+export const InodesResourceMonitor = {
+  encode(message: InodesResourceMonitor, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.path !== "") {
+      writer.uint32(10).string(message.path);
+    }
+    if (message.enabled === true) {
+      writer.uint32(16).bool(message.enabled);
+    }
+    if (message.threshold !== 0) {
+      writer.uint32(24).int32(message.threshold);
+    }
+    return writer;
+  },
+};
+
+export const ProcessesResourceMonitor = {
+  encode(message: ProcessesResourceMonitor, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.threshold !== 0) {
+      writer.uint32(16).int32(message.threshold);
+    }
+    return writer;
+  },
+};
+
 export const DisplayApps = {
   encode(message: DisplayApps, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.vscode === true) {
@@ -901,6 +1016,36 @@ export const Script = {
   },
 };
 
+export const Service = {
+  encode(message: Service, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.displayName !== "") {
+      writer.uint32(18).string(message.displayName);
+    }
+    if (message.icon !== "") {
+      writer.uint32(26).string(message.icon);
+    }
+    if (message.command !== "") {
+      writer.uint32(34).string(message.command);
+    }
+    if (message.restartPolicy !== 0) {
+      writer.uint32(40).int32(message.restartPolicy);
+    }
+    if (message.restartDelaySeconds !== 0) {
+      writer.uint32(48).int32(message.restartDelaySeconds);
+    }
+    if (message.readinessProbe !== undefined) {
+      Healthcheck.encode(message.readinessProbe, writer.uint32(58).fork()).ldelim();
+    }
+    if (message.logPath !== "") {
+      writer.uint32(66).string(message.logPath);
+    }
+    return writer;
+  },
+};
+
 export const Devcontainer = {
   encode(message: Devcontainer, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.workspaceFolder !== "") {
@@ -58,6 +58,16 @@ var (
 	oauthHTML string
 
 	oauthTemplate *htmltemplate.Template
+
+	//go:embed static/webauthncli.html
+	webAuthnCLIHTML string
+
+	webAuthnCLITemplate *htmltemplate.Template
+
+	//go:embed static/oauth2device.html
+	oauthDeviceHTML string
+
+	oauthDeviceTemplate *htmltemplate.Template
 )
 
 func init() {
@@ -71,6 +81,16 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	webAuthnCLITemplate, err = htmltemplate.New("webauthncli").Parse(webAuthnCLIHTML)
+	if err != nil {
+		panic(err)
+	}
+
+	oauthDeviceTemplate, err = htmltemplate.New("oauth2device").Parse(oauthDeviceHTML)
+	if err != nil {
+		panic(err)
+	}
 }
 
 type Options struct {
@@ -1125,3 +1145,45 @@ func RenderOAuthAllowPage(rw http.ResponseWriter, r *http.Request, data RenderOA
 		return
 	}
 }
+
+// RenderOAuthDeviceData contains the variables that are found in
+// site/static/oauth2device.html.
+type RenderOAuthDeviceData struct {
+	UserCode string
+	Error    string
+}
+
+// RenderOAuthDevicePage renders the static page where a user enters the code
+// shown by a device using the OAuth2 device authorization flow.
+func RenderOAuthDevicePage(rw http.ResponseWriter, r *http.Request, data RenderOAuthDeviceData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	err := oauthDeviceTemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.Response{
+			Message: "Failed to render device page: " + err.Error(),
+		})
+		return
+	}
+}
+
+// RenderWebAuthnCLIData contains the variables that are found in
+// site/static/webauthncli.html.
+type RenderWebAuthnCLIData struct {
+	RedirectURI string
+	State       string
+}
+
+// RenderWebAuthnCLIPage renders the static page used by `coder login --passkey`
+// to sign in with a passkey and hand the session token to the CLI.
+func RenderWebAuthnCLIPage(rw http.ResponseWriter, r *http.Request, data RenderWebAuthnCLIData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	err := webAuthnCLITemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.Response{
+			Message: "Failed to render passkey page: " + err.Error(),
+		})
+		return
+	}
+}
@@ -104,6 +104,7 @@ export const RBACResourceActions: Partial<
 		create: "create an OAuth2 app code token",
 		delete: "delete an OAuth2 app code token",
 		read: "read an OAuth2 app code token",
+		update: "update an OAuth2 app code token",
 	},
 	oauth2_app_secret: {
 		create: "create an OAuth2 app secret",
@@ -165,6 +166,12 @@ export const RBACResourceActions: Partial<
 		update: "update an existing user",
 		update_personal: "update personal data",
 	},
+	user_secret: {
+		create: "create a user secret",
+		delete: "delete a user secret",
+		read: "read a user secret",
+		update: "update a user secret",
+	},
 	webpush_subscription: {
 		create: "create webpush subscriptions",
 		delete: "delete webpush subscriptions",
@@ -208,4 +215,10 @@ export const RBACResourceActions: Partial<
 		read: "read and use a workspace proxy",
 		update: "update a workspace proxy",
 	},
+	workspace_session_recording: {
+		create: "create a recording of a workspace session",
+		delete: "delete a recording of a workspace session",
+		read: "view a recording of a workspace session",
+		update: "append to a recording of a workspace session",
+	},
 };
@@ -30,12 +30,18 @@ export interface APIKey {
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
 	readonly lifetime_seconds: number;
+	readonly scope_permissions?: readonly string[];
+	readonly scope_allow_list?: readonly string[];
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
+export type APIKeyScope = "all" | "application_connect" | "custom";
 
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export const APIKeyScopes: APIKeyScope[] = [
+	"all",
+	"application_connect",
+	"custom",
+];
 
 // From codersdk/apikey.go
 export interface APIKeyWithOwner extends APIKey {
@@ -214,6 +220,9 @@ export interface AuthMethods {
 	readonly password: AuthMethod;
 	readonly github: GithubAuthMethod;
 	readonly oidc: OIDCAuthMethod;
+	readonly ldap: LDAPAuthMethod;
+	readonly saml: SAMLAuthMethod;
+	readonly passkey: AuthMethod;
 }
 
 // From codersdk/authorization.go
@@ -467,6 +476,8 @@ export interface CreateTokenRequest {
 	readonly lifetime: number;
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
+	readonly scope_permissions?: readonly string[];
+	readonly scope_allow_list?: readonly string[];
 }
 
 // From codersdk/users.go
@@ -480,6 +491,24 @@ export interface CreateUserRequestWithOrgs {
 	readonly organization_ids: readonly string[];
 }
 
+// From codersdk/usersecrets.go
+export interface CreateUserSecretRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly value: string;
+	readonly env_name?: string;
+	readonly file_path?: string;
+}
+
+// From codersdk/webauthn.go
+export interface CreateWebAuthnCredentialRequest {
+	readonly name: string;
+	readonly credential: Record<string, string>;
+	readonly webauthn_credential?: Record<string, string>;
+	readonly code?: string;
+	readonly password?: string;
+}
+
 // From codersdk/workspaces.go
 export interface CreateWorkspaceBuildRequest {
 	readonly template_version_id?: string;
@@ -597,6 +626,7 @@ export interface DERPNodeReport {
 	readonly round_trip_ping: string;
 	readonly round_trip_ping_ms: number;
 	readonly uses_websocket: boolean;
+	readonly uses_quic: boolean;
 	readonly client_logs: readonly string[][];
 	readonly client_errs: readonly string[][];
 	readonly stun: STUNReport;
@@ -626,6 +656,7 @@ export interface DERPServerConfig {
 	readonly region_name: string;
 	readonly stun_addresses: string;
 	readonly relay_url: string;
+	readonly quic_address: string;
 }
 
 // From codersdk/deployment.go
@@ -647,6 +678,13 @@ export interface DatabaseReport extends BaseReport {
 	readonly threshold_ms: number;
 }
 
+// From codersdk/webauthn.go
+export interface DeleteWebAuthnCredentialRequest {
+	readonly webauthn_credential?: Record<string, string>;
+	readonly code?: string;
+	readonly password?: string;
+}
+
 // From codersdk/notifications.go
 export interface DeleteWebpushSubscription {
 	readonly endpoint: string;
@@ -695,6 +733,8 @@ export interface DeploymentValues {
 	readonly pg_auth?: string;
 	readonly oauth2?: OAuth2Config;
 	readonly oidc?: OIDCConfig;
+	readonly ldap?: LDAPConfig;
+	readonly saml?: SAMLConfig;
 	readonly telemetry?: TelemetryConfig;
 	readonly tls?: TLSConfig;
 	readonly trace?: TraceConfig;
@@ -708,6 +748,7 @@ export interface DeploymentValues {
 	readonly browser_only?: boolean;
 	readonly scim_api_key?: string;
 	readonly external_token_encryption_keys?: string;
+	readonly external_token_encryption_key_providers?: string;
 	readonly provisioner?: ProvisionerConfig;
 	readonly rate_limit?: RateLimitConfig;
 	readonly experiments?: string;
@@ -718,6 +759,7 @@ export interface DeploymentValues {
 	readonly disable_path_apps?: boolean;
 	readonly session_lifetime?: SessionLifetime;
 	readonly disable_password_auth?: boolean;
+	readonly require_mfa?: boolean;
 	readonly support?: SupportConfig;
 	readonly external_auth?: SerpentStruct<ExternalAuthConfig[]>;
 	readonly ai?: SerpentStruct<AIConfig>;
@@ -736,6 +778,7 @@ export interface DeploymentValues {
 	readonly additional_csp_policy?: string;
 	readonly workspace_hostname_suffix?: string;
 	readonly workspace_prebuilds?: PrebuildsConfig;
+	readonly session_recording_retention?: number;
 	readonly config?: string;
 	readonly write_config?: boolean;
 	readonly address?: string;
@@ -1247,6 +1290,33 @@ export type JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES";
 
 export const JobErrorCodes: JobErrorCode[] = ["REQUIRED_TEMPLATE_VARIABLES"];
 
+// From codersdk/users.go
+export interface LDAPAuthMethod extends AuthMethod {
+	readonly signInText: string;
+}
+
+// From codersdk/deployment.go
+export interface LDAPConfig {
+	readonly url: string;
+	readonly start_tls: boolean;
+	readonly insecure_skip_verify: boolean;
+	readonly ca_file: string;
+	readonly bind_dn: string;
+	readonly bind_password: string;
+	readonly user_search_base_dn: string;
+	readonly user_search_filter: string;
+	readonly id_attribute: string;
+	readonly username_attribute: string;
+	readonly email_attribute: string;
+	readonly name_attribute: string;
+	readonly group_search_base_dn: string;
+	readonly group_search_filter: string;
+	readonly group_name_attribute: string;
+	readonly allow_signups: boolean;
+	readonly email_domain: string;
+	readonly sign_in_text: string;
+}
+
 // From codersdk/deployment.go
 export interface LanguageModel {
 	readonly id: string;
@@ -1327,21 +1397,39 @@ export interface LoggingConfig {
 }
 
 // From codersdk/apikey.go
-export type LoginType = "github" | "none" | "oidc" | "password" | "token" | "";
+export type LoginType =
+	| "github"
+	| "ldap"
+	| "none"
+	| "oidc"
+	| "password"
+	| "saml"
+	| "token"
+	| "";
 
 export const LoginTypes: LoginType[] = [
 	"github",
+	"ldap",
 	"none",
 	"oidc",
 	"password",
+	"saml",
 	"token",
 	"",
 ];
 
+// From codersdk/users.go
+export interface LoginWithLDAPRequest {
+	readonly username: string;
+	readonly password: string;
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
 	readonly email: string;
 	readonly password: string;
+	readonly mfa_code?: string;
+	readonly webauthn_credential?: Record<string, string>;
 }
 
 // From codersdk/users.go
@@ -1349,6 +1437,21 @@ export interface LoginWithPasswordResponse {
 	readonly session_token: string;
 }
 
+// From codersdk/webauthn.go
+export interface LoginWithWebAuthnRequest {
+	readonly credential: Record<string, string>;
+}
+
+// From codersdk/mfa.go
+export interface MFACodeRequest {
+	readonly code: string;
+}
+
+// From codersdk/mfa.go
+export interface MFARecoveryCodes {
+	readonly recovery_codes: readonly string[];
+}
+
 // From codersdk/provisionerdaemons.go
 export interface MatchedProvisioners {
 	readonly count: number;
@@ -1492,9 +1595,59 @@ export interface OAuth2AppEndpoints {
 	readonly device_authorization: string;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2AuthorizationServerMetadata {
+	readonly issuer: string;
+	readonly authorization_endpoint: string;
+	readonly token_endpoint: string;
+	readonly device_authorization_endpoint: string;
+	readonly introspection_endpoint: string;
+	readonly registration_endpoint?: string;
+	readonly response_types_supported: readonly OAuth2ProviderResponseType[];
+	readonly grant_types_supported: readonly OAuth2ProviderGrantType[];
+	readonly code_challenge_methods_supported: readonly OAuth2ProviderCodeChallengeMethod[];
+	readonly token_endpoint_auth_methods_supported: readonly OAuth2TokenEndpointAuthMethod[];
+	readonly introspection_endpoint_auth_methods_supported: readonly OAuth2TokenEndpointAuthMethod[];
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ClientRegistrationRequest {
+	readonly redirect_uris: readonly string[];
+	readonly client_name?: string;
+	readonly logo_uri?: string;
+	readonly grant_types?: readonly OAuth2ProviderGrantType[];
+	readonly response_types?: readonly OAuth2ProviderResponseType[];
+	readonly token_endpoint_auth_method?: OAuth2TokenEndpointAuthMethod;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ClientRegistrationResponse {
+	readonly client_id: string;
+	readonly client_secret?: string;
+	readonly client_id_issued_at: number;
+	readonly client_secret_expires_at: number;
+	readonly redirect_uris: readonly string[];
+	readonly client_name: string;
+	readonly logo_uri?: string;
+	readonly grant_types: readonly OAuth2ProviderGrantType[];
+	readonly response_types: readonly OAuth2ProviderResponseType[];
+	readonly token_endpoint_auth_method: OAuth2TokenEndpointAuthMethod;
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
 	readonly github: OAuth2GithubConfig;
+	readonly provider: OAuth2ProviderConfig;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2DeviceAuthorizationResponse {
+	readonly device_code: string;
+	readonly user_code: string;
+	readonly verification_uri: string;
+	readonly verification_uri_complete: string;
+	readonly expires_in: number;
+	readonly interval: number;
 }
 
 // From codersdk/oauth2.go
@@ -1502,6 +1655,12 @@ export interface OAuth2DeviceFlowCallbackResponse {
 	readonly redirect_url: string;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2Error {
+	readonly error: string;
+	readonly error_description?: string;
+}
+
 // From codersdk/deployment.go
 export interface OAuth2GithubConfig {
 	readonly client_id: string;
@@ -1521,9 +1680,19 @@ export interface OAuth2ProviderApp {
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly client_type: OAuth2ProviderAppClientType;
+	readonly service_account_id?: string;
 	readonly endpoints: OAuth2AppEndpoints;
 }
 
+// From codersdk/oauth2.go
+export type OAuth2ProviderAppClientType = "confidential" | "public";
+
+export const OAuth2ProviderAppClientTypes: OAuth2ProviderAppClientType[] = [
+	"confidential",
+	"public",
+];
+
 // From codersdk/oauth2.go
 export interface OAuth2ProviderAppFilter {
 	readonly user_id?: string;
@@ -1543,11 +1712,40 @@ export interface OAuth2ProviderAppSecretFull {
 }
 
 // From codersdk/oauth2.go
-export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token";
+export type OAuth2ProviderCodeChallengeMethod = "S256";
+
+export const OAuth2ProviderCodeChallengeMethods: OAuth2ProviderCodeChallengeMethod[] =
+	["S256"];
+
+// From codersdk/deployment.go
+export interface OAuth2ProviderConfig {
+	readonly dynamic_registration: string;
+	readonly dynamic_registration_allowed_redirect_hosts: string;
+	readonly dynamic_registration_rate_limit: number;
+	readonly dynamic_registration_unused_client_lifetime: number;
+}
+
+// From codersdk/deployment.go
+export type OAuth2ProviderDynamicRegistration =
+	| "authenticated"
+	| "disabled"
+	| "open";
+
+export const OAuth2ProviderDynamicRegistrations: OAuth2ProviderDynamicRegistration[] =
+	["authenticated", "disabled", "open"];
+
+// From codersdk/oauth2.go
+export type OAuth2ProviderGrantType =
+	| "authorization_code"
+	| "client_credentials"
+	| "refresh_token"
+	| "urn:ietf:params:oauth:grant-type:device_code";
 
 export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
 	"authorization_code",
+	"client_credentials",
 	"refresh_token",
+	"urn:ietf:params:oauth:grant-type:device_code",
 ];
 
 // From codersdk/oauth2.go
@@ -1563,6 +1761,30 @@ export const OAuth2RedirectCookie = "oauth_redirect";
 // From codersdk/client.go
 export const OAuth2StateCookie = "oauth_state";
 
+// From codersdk/oauth2.go
+export type OAuth2TokenEndpointAuthMethod =
+	| "client_secret_basic"
+	| "client_secret_post"
+	| "none";
+
+export const OAuth2TokenEndpointAuthMethods: OAuth2TokenEndpointAuthMethod[] = [
+	"client_secret_basic",
+	"client_secret_post",
+	"none",
+];
+
+// From codersdk/oauth2.go
+export interface OAuth2TokenIntrospection {
+	readonly active: boolean;
+	readonly scope?: string;
+	readonly client_id?: string;
+	readonly username?: string;
+	readonly token_type?: string;
+	readonly exp?: number;
+	readonly iat?: number;
+	readonly sub?: string;
+}
+
 // From codersdk/users.go
 export interface OAuthConversionResponse {
 	readonly state_string: string;
@@ -1792,6 +2014,8 @@ export interface PostOAuth2ProviderAppRequest {
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly client_type?: OAuth2ProviderAppClientType;
+	readonly service_account_id?: string;
 }
 
 // From codersdk/workspaces.go
@@ -2127,6 +2351,8 @@ export interface PutOAuth2ProviderAppRequest {
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly client_type?: OAuth2ProviderAppClientType;
+	readonly service_account_id?: string;
 }
 
 // From codersdk/rbacresources_gen.go
@@ -2199,13 +2425,15 @@ export type RBACResource =
 	| "tailnet_coordinator"
 	| "template"
 	| "user"
+	| "user_secret"
 	| "webpush_subscription"
 	| "*"
 	| "workspace"
 	| "workspace_agent_devcontainers"
 	| "workspace_agent_resource_monitor"
 	| "workspace_dormant"
-	| "workspace_proxy";
+	| "workspace_proxy"
+	| "workspace_session_recording";
 
 export const RBACResources: RBACResource[] = [
 	"api_key",
@@ -2238,6 +2466,7 @@ export const RBACResources: RBACResource[] = [
 	"tailnet_coordinator",
 	"template",
 	"user",
+	"user_secret",
 	"webpush_subscription",
 	"*",
 	"workspace",
@@ -2245,6 +2474,7 @@ export const RBACResources: RBACResource[] = [
 	"workspace_agent_resource_monitor",
 	"workspace_dormant",
 	"workspace_proxy",
+	"workspace_session_recording",
 ];
 
 // From codersdk/deployment.go
@@ -2326,11 +2556,14 @@ export type ResourceType =
 	| "template"
 	| "template_version"
 	| "user"
+	| "user_secret"
+	| "webauthn_credential"
 	| "workspace"
 	| "workspace_agent"
 	| "workspace_app"
 	| "workspace_build"
-	| "workspace_proxy";
+	| "workspace_proxy"
+	| "workspace_session_recording";
 
 export const ResourceTypes: ResourceType[] = [
 	"api_key",
@@ -2352,11 +2585,14 @@ export const ResourceTypes: ResourceType[] = [
 	"template",
 	"template_version",
 	"user",
+	"user_secret",
+	"webauthn_credential",
 	"workspace",
 	"workspace_agent",
 	"workspace_app",
 	"workspace_build",
 	"workspace_proxy",
+	"workspace_session_recording",
 ];
 
 // From codersdk/client.go
@@ -2416,6 +2652,33 @@ export const RoleTemplateAdmin = "template-admin";
 // From codersdk/rbacroles.go
 export const RoleUserAdmin = "user-admin";
 
+// From codersdk/users.go
+export interface SAMLAuthMethod extends AuthMethod {
+	readonly signInText: string;
+	readonly iconUrl: string;
+}
+
+// From codersdk/deployment.go
+export interface SAMLConfig {
+	readonly idp_metadata_url: string;
+	readonly idp_metadata_file: string;
+	readonly entity_id: string;
+	readonly cert_file: string;
+	readonly key_file: string;
+	readonly sign_requests: boolean;
+	readonly allow_idp_initiated: boolean;
+	readonly allow_signups: boolean;
+	readonly email_domain: string;
+	readonly email_attribute: string;
+	readonly username_attribute: string;
+	readonly name_attribute: string;
+	readonly sign_in_text: string;
+	readonly icon_url: string;
+}
+
+// From codersdk/client.go
+export const SAMLRequestCookie = "saml_request";
+
 // From codersdk/deployment.go
 export interface SSHConfig {
 	readonly DeploymentName: string;
@@ -2521,6 +2784,20 @@ export interface SessionLifetime {
 	readonly max_token_lifetime?: number;
 }
 
+// From codersdk/workspacesessionrecordings.go
+export type SessionRecordingType =
+	| "jetbrains"
+	| "reconnecting_pty"
+	| "ssh"
+	| "vscode";
+
+export const SessionRecordingTypes: SessionRecordingType[] = [
+	"jetbrains",
+	"reconnecting_pty",
+	"ssh",
+	"vscode",
+];
+
 // From codersdk/client.go
 export const SessionTokenCookie = "coder_session_token";
 
@@ -2570,6 +2847,12 @@ export interface TLSConfig {
 	readonly allow_insecure_ciphers: boolean;
 }
 
+// From codersdk/mfa.go
+export interface TOTPEnrollment {
+	readonly secret: string;
+	readonly url: string;
+}
+
 // From tailcfg/derpmap.go
 export interface TailDERPNode {
 	readonly Name: string;
@@ -2638,6 +2921,7 @@ export interface Template {
 	readonly require_active_version: boolean;
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow: boolean;
+	readonly session_recording_enabled: boolean;
 }
 
 // From codersdk/templates.go
@@ -3010,6 +3294,7 @@ export interface UpdateTemplateMeta {
 	readonly disable_everyone_group_access: boolean;
 	readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow?: boolean;
+	readonly session_recording_enabled?: boolean;
 }
 
 // From codersdk/users.go
@@ -3040,6 +3325,14 @@ export interface UpdateUserQuietHoursScheduleRequest {
 	readonly schedule: string;
 }
 
+// From codersdk/usersecrets.go
+export interface UpdateUserSecretRequest {
+	readonly description?: string;
+	readonly value?: string;
+	readonly env_name?: string;
+	readonly file_path?: string;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
 	readonly automatic_updates: AutomaticUpdates;
@@ -3169,6 +3462,15 @@ export interface UserLoginType {
 	readonly login_type: LoginType;
 }
 
+// From codersdk/mfa.go
+export interface UserMFA {
+	readonly required: boolean;
+	readonly totp_enabled: boolean;
+	readonly totp_enabled_at?: string;
+	readonly recovery_codes_remaining: number;
+	readonly webauthn_credentials: number;
+}
+
 // From codersdk/users.go
 export interface UserParameter {
 	readonly name: string;
@@ -3197,6 +3499,18 @@ export interface UserRoles {
 	readonly organization_roles: Record<string, string[]>;
 }
 
+// From codersdk/usersecrets.go
+export interface UserSecret {
+	readonly id: string;
+	readonly user_id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly env_name: string;
+	readonly file_path: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/users.go
 export type UserStatus = "active" | "dormant" | "suspended";
 
@@ -3244,6 +3558,21 @@ export interface VariableValue {
 	readonly value: string;
 }
 
+// From codersdk/webauthn.go
+export interface WebAuthnCredential {
+	readonly id: string;
+	readonly name: string;
+	readonly transports: readonly string[];
+	readonly backup_eligible: boolean;
+	readonly created_at: string;
+	readonly last_used_at?: string;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnOptions {
+	readonly publicKey: Record<string, string>;
+}
+
 // From codersdk/notifications.go
 export interface WebpushMessage {
 	readonly icon: string;
@@ -3510,6 +3839,39 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentResourceMonitor {
+	readonly type: WorkspaceAgentResourceMonitorType;
+	readonly path?: string;
+	readonly enabled: boolean;
+	readonly threshold: number;
+	readonly state: WorkspaceAgentResourceMonitorState;
+	readonly updated_at: string;
+}
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentResourceMonitorState = "alert" | "ok";
+
+export const WorkspaceAgentResourceMonitorStates: WorkspaceAgentResourceMonitorState[] =
+	["alert", "ok"];
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentResourceMonitorType =
+	| "cpu"
+	| "inodes"
+	| "load"
+	| "memory"
+	| "processes"
+	| "volume";
+
+export const WorkspaceAgentResourceMonitorTypes: WorkspaceAgentResourceMonitorType[] =
+	["cpu", "inodes", "load", "memory", "processes", "volume"];
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentResourceMonitorsResponse {
+	readonly monitors: readonly WorkspaceAgentResourceMonitor[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
 	readonly id: string;
@@ -3524,6 +3886,57 @@ export interface WorkspaceAgentScript {
 	readonly display_name: string;
 }
 
+// From codersdk/workspaceagentservices.go
+export interface WorkspaceAgentService {
+	readonly id: string;
+	readonly log_source_id: string;
+	readonly log_path: string;
+	readonly name: string;
+	readonly display_name: string;
+	readonly icon: string;
+	readonly command: string;
+	readonly restart_policy: WorkspaceAgentServiceRestartPolicy;
+	readonly restart_delay: number;
+	readonly readiness_probe: Healthcheck;
+	readonly status: WorkspaceAgentServiceStatus;
+	readonly health: WorkspaceAppHealth;
+	readonly restarts: number;
+	readonly exit_code?: number;
+	readonly updated_at: string;
+}
+
+// From codersdk/workspaceagentservices.go
+export type WorkspaceAgentServiceRestartPolicy =
+	| "always"
+	| "never"
+	| "on_failure";
+
+export const WorkspaceAgentServiceRestartPolicys: WorkspaceAgentServiceRestartPolicy[] =
+	["always", "never", "on_failure"];
+
+// From codersdk/workspaceagentservices.go
+export type WorkspaceAgentServiceStatus =
+	| "failed"
+	| "pending"
+	| "restarting"
+	| "running"
+	| "starting"
+	| "stopped";
+
+export const WorkspaceAgentServiceStatuses: WorkspaceAgentServiceStatus[] = [
+	"failed",
+	"pending",
+	"restarting",
+	"running",
+	"starting",
+	"stopped",
+];
+
+// From codersdk/workspaceagentservices.go
+export interface WorkspaceAgentServicesResponse {
+	readonly services: readonly WorkspaceAgentService[];
+}
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking";
 
@@ -3752,6 +4165,29 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecording {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_username: string;
+	readonly agent_id: string;
+	readonly type: SessionRecordingType;
+	readonly width: number;
+	readonly height: number;
+	readonly started_at: string;
+	readonly ended_at?: string;
+	readonly size: number;
+}
+
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecordingsRequest extends Pagination {
+	readonly workspace_id?: string;
+	readonly owner_id?: string;
+}
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -145,6 +145,7 @@ const DERPRegionPage: FC = () => {
 									<BooleanPill value={report.uses_websocket}>
 										Websocket
 									</BooleanPill>
+									<BooleanPill value={report.uses_quic}>QUIC</BooleanPill>
 								</div>
 							</header>
 							<Logs lines={logs?.flat() ?? []} css={reportStyles.logs} />
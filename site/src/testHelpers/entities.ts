@@ -1760,6 +1760,9 @@ export const MockAuthMethodsPasswordOnly: TypesGen.AuthMethods = {
 	password: { enabled: true },
 	github: { enabled: false, default_provider_configured: true },
 	oidc: { enabled: false, signInText: "", iconUrl: "" },
+	ldap: { enabled: false, signInText: "" },
+	saml: { enabled: false, signInText: "", iconUrl: "" },
+	passkey: { enabled: false },
 };
 
 export const MockAuthMethodsPasswordTermsOfService: TypesGen.AuthMethods = {
@@ -1767,6 +1770,9 @@ export const MockAuthMethodsPasswordTermsOfService: TypesGen.AuthMethods = {
 	password: { enabled: true },
 	github: { enabled: false, default_provider_configured: true },
 	oidc: { enabled: false, signInText: "", iconUrl: "" },
+	ldap: { enabled: false, signInText: "" },
+	saml: { enabled: false, signInText: "", iconUrl: "" },
+	passkey: { enabled: false },
 };
 
 export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
@@ -1777,6 +1783,9 @@ export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
 		signInText: "Google",
 		iconUrl: "/icon/google.svg",
 	},
+	ldap: { enabled: false, signInText: "" },
+	saml: { enabled: false, signInText: "", iconUrl: "" },
+	passkey: { enabled: false },
 };
 
 export const MockAuthMethodsAll: TypesGen.AuthMethods = {
@@ -1787,6 +1796,9 @@ export const MockAuthMethodsAll: TypesGen.AuthMethods = {
 		signInText: "Google",
 		iconUrl: "/icon/google.svg",
 	},
+	ldap: { enabled: true, signInText: "LDAP" },
+	saml: { enabled: true, signInText: "SAML", iconUrl: "" },
+	passkey: { enabled: true },
 };
 
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
@@ -3255,6 +3267,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "0",
 						round_trip_ping_ms: 0,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [],
 						client_errs: [],
 						stun: {
@@ -3282,6 +3295,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "7674330",
 						round_trip_ping_ms: 7674330,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [
 							[
 								"derphttp.Client.Connect: connecting to https://dev.coder.com/derp",
@@ -3355,6 +3369,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "0",
 						round_trip_ping_ms: 0,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [],
 						client_errs: [],
 						stun: {
@@ -3382,6 +3397,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "170527034",
 						round_trip_ping_ms: 170527034,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [
 							[
 								"derphttp.Client.Connect: connecting to https://sydney.dev.coder.com/derp",
@@ -3445,6 +3461,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "0",
 						round_trip_ping_ms: 0,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [],
 						client_errs: [],
 						stun: {
@@ -3472,6 +3489,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "111329690",
 						round_trip_ping_ms: 111329690,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [
 							[
 								"derphttp.Client.Connect: connecting to https://europe.dev.coder.com/derp",
@@ -3535,6 +3553,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "0",
 						round_trip_ping_ms: 0,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [],
 						client_errs: [],
 						stun: {
@@ -3562,6 +3581,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 						round_trip_ping: "138185506",
 						round_trip_ping_ms: 138185506,
 						uses_websocket: false,
+						uses_quic: false,
 						client_logs: [
 							[
 								"derphttp.Client.Connect: connecting to https://brazil.dev.coder.com/derp",
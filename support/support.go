@@ -189,7 +189,8 @@ func NetworkInfo(ctx context.Context, client *codersdk.Client, log slog.Logger)
 		n.ConnectionInfo = connInfo
 		var rpt derphealth.Report
 		rpt.Run(ctx, &derphealth.ReportOptions{
-			DERPMap: connInfo.DERPMap,
+			DERPMap:       connInfo.DERPMap,
+			DERPQUICPorts: connInfo.DERPQUICPorts,
 		})
 		n.Netcheck = &rpt
 		return nil
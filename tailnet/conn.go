@@ -95,6 +95,9 @@ type Options struct {
 	// falling back. This is useful for misbehaving proxies that prevent
 	// fallback due to odd behavior, like Azure App Proxy.
 	DERPForceWebSockets bool
+	// DERPQUICPorts are the QUIC ports of the DERP nodes in DERPMap. Regions
+	// with a port are connected to over QUIC, falling back to TCP.
+	DERPQUICPorts DERPQUICPorts
 	// BlockEndpoints specifies whether P2P endpoints are blocked.
 	// If so, only DERPs can establish connections.
 	BlockEndpoints bool
@@ -226,6 +229,8 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	magicConn := sys.MagicSock.Get()
 	magicConn.SetDERPForceWebsockets(options.DERPForceWebSockets)
 	magicConn.SetBlockEndpoints(options.BlockEndpoints)
+	derpDialer := newDERPRegionDialer(options.Logger.Named("net.derpdialer"), options.DERPQUICPorts, options.DERPForceWebSockets)
+	magicConn.SetDERPRegionDialer(derpDialer.dial)
 	if options.DERPHeader != nil {
 		magicConn.SetDERPHeader(options.DERPHeader.Clone())
 	}
@@ -308,6 +313,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		closed:           make(chan struct{}),
 		logger:           options.Logger,
 		magicConn:        magicConn,
+		derpDialer:       derpDialer,
 		dialer:           dialer,
 		listeners:        map[listenKey]*listener{},
 		tunDevice:        sys.Tun.Get(),
@@ -435,6 +441,7 @@ type Conn struct {
 	nodeUpdater      *nodeUpdater
 	netStack         *netstack.Impl
 	magicConn        *magicsock.Conn
+	derpDialer       *derpRegionDialer
 	wireguardMonitor *netmon.Monitor
 	wireguardRouter  *router.Config
 	wireguardEngine  wgengine.Engine
@@ -507,9 +514,18 @@ func (c *Conn) SetDERPMap(derpMap *tailcfg.DERPMap) {
 
 func (c *Conn) SetDERPForceWebSockets(v bool) {
 	c.logger.Info(context.Background(), "setting DERP Force Websockets", slog.F("force_derp_websockets", v))
+	c.derpDialer.setForceWebSockets(v)
 	c.magicConn.SetDERPForceWebsockets(v)
 }
 
+// SetDERPQUICPorts updates the QUIC ports of the DERP nodes. They apply to new
+// DERP connections.
+func (c *Conn) SetDERPQUICPorts(ports DERPQUICPorts) {
+	if c.derpDialer.setPorts(ports) {
+		c.logger.Debug(context.Background(), "updated DERP QUIC ports", slog.F("ports", ports))
+	}
+}
+
 // SetBlockEndpoints sets whether to block P2P endpoints. This setting
 // will only apply to new peers.
 func (c *Conn) SetBlockEndpoints(blockEndpoints bool) {
@@ -519,8 +535,9 @@ func (c *Conn) SetBlockEndpoints(blockEndpoints bool) {
 }
 
 // SetDERPRegionDialer updates the dialer to use for connecting to DERP regions.
+// If the dialer returns nil, the region is dialed over QUIC or TCP as usual.
 func (c *Conn) SetDERPRegionDialer(dialer func(ctx context.Context, region *tailcfg.DERPRegion) net.Conn) {
-	c.magicConn.SetDERPRegionDialer(dialer)
+	c.derpDialer.setCustom(dialer)
 }
 
 // UpdatePeers connects with a set of peers. This can be constantly updated,
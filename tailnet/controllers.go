@@ -73,10 +73,11 @@ type CoordinationController interface {
 	New(CoordinatorClient) CloserWaiter
 }
 
-// DERPClient is an abstraction of the stream of DERPMap updates from the control plane.
+// DERPClient is an abstraction of the stream of DERPMap updates from the control plane. Each
+// update carries the QUIC ports of the DERP nodes alongside the map.
 type DERPClient interface {
 	Close() error
-	Recv() (*tailcfg.DERPMap, error)
+	Recv() (*tailcfg.DERPMap, DERPQUICPorts, error)
 }
 
 // A DERPController accepts connections to the control plane, and handles the DERPMap updates
@@ -565,6 +566,7 @@ func NewInMemoryCoordinatorClient(
 
 type DERPMapSetter interface {
 	SetDERPMap(derpMap *tailcfg.DERPMap)
+	SetDERPQUICPorts(ports DERPQUICPorts)
 }
 
 type basicDERPController struct {
@@ -630,7 +632,7 @@ func (l *derpSetLoop) Wait() <-chan error {
 func (l *derpSetLoop) recvLoop() {
 	defer close(l.recvLoopDone)
 	for {
-		dm, err := l.client.Recv()
+		dm, ports, err := l.client.Recv()
 		if err != nil {
 			l.logger.Debug(context.Background(), "failed to receive DERP message", slog.Error(err))
 			select {
@@ -640,6 +642,9 @@ func (l *derpSetLoop) recvLoop() {
 			return
 		}
 		l.logger.Debug(context.Background(), "got new DERP Map", slog.F("derp_map", dm))
+		// Set the ports first, so new DERP connections to regions in the map
+		// can use them.
+		l.setter.SetDERPQUICPorts(ports)
 		l.setter.SetDERPMap(dm)
 	}
 }
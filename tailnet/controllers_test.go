@@ -584,17 +584,21 @@ func (f *fakeCoordinatee) SetNodeCallback(callback func(*tailnet.Node)) {
 
 func TestNewBasicDERPController_Mainline(t *testing.T) {
 	t.Parallel()
-	fs := make(chan *tailcfg.DERPMap)
+	fs := newFakeSetter()
 	logger := testutil.Logger(t)
-	uut := tailnet.NewBasicDERPController(logger, fakeSetter(fs))
+	uut := tailnet.NewBasicDERPController(logger, fs)
+	expectPorts := tailnet.DERPQUICPorts{"1a": 3478}
 	fc := fakeDERPClient{
-		ch: make(chan *tailcfg.DERPMap),
+		ch:    make(chan *tailcfg.DERPMap),
+		ports: expectPorts,
 	}
 	c := uut.New(fc)
 	ctx := testutil.Context(t, testutil.WaitShort)
 	expectDM := &tailcfg.DERPMap{}
 	testutil.RequireSend(ctx, t, fc.ch, expectDM)
-	gotDM := testutil.TryReceive(ctx, t, fs)
+	gotPorts := testutil.TryReceive(ctx, t, fs.ports)
+	require.Equal(t, expectPorts, gotPorts)
+	gotDM := testutil.TryReceive(ctx, t, fs.maps)
 	require.Equal(t, expectDM, gotDM)
 	err := c.Close(ctx)
 	require.NoError(t, err)
@@ -607,9 +611,9 @@ func TestNewBasicDERPController_Mainline(t *testing.T) {
 
 func TestNewBasicDERPController_RecvErr(t *testing.T) {
 	t.Parallel()
-	fs := make(chan *tailcfg.DERPMap)
+	fs := newFakeSetter()
 	logger := testutil.Logger(t)
-	uut := tailnet.NewBasicDERPController(logger, fakeSetter(fs))
+	uut := tailnet.NewBasicDERPController(logger, fs)
 	expectedErr := xerrors.New("a bad thing happened")
 	fc := fakeDERPClient{
 		ch:  make(chan *tailcfg.DERPMap),
@@ -624,15 +628,30 @@ func TestNewBasicDERPController_RecvErr(t *testing.T) {
 	require.NoError(t, err)
 }
 
-type fakeSetter chan *tailcfg.DERPMap
+type fakeSetter struct {
+	maps  chan *tailcfg.DERPMap
+	ports chan tailnet.DERPQUICPorts
+}
+
+func newFakeSetter() fakeSetter {
+	return fakeSetter{
+		maps:  make(chan *tailcfg.DERPMap),
+		ports: make(chan tailnet.DERPQUICPorts),
+	}
+}
 
 func (s fakeSetter) SetDERPMap(derpMap *tailcfg.DERPMap) {
-	s <- derpMap
+	s.maps <- derpMap
+}
+
+func (s fakeSetter) SetDERPQUICPorts(ports tailnet.DERPQUICPorts) {
+	s.ports <- ports
 }
 
 type fakeDERPClient struct {
-	ch  chan *tailcfg.DERPMap
-	err error
+	ch    chan *tailcfg.DERPMap
+	ports tailnet.DERPQUICPorts
+	err   error
 }
 
 func (f fakeDERPClient) Close() error {
@@ -640,15 +659,15 @@ func (f fakeDERPClient) Close() error {
 	return nil
 }
 
-func (f fakeDERPClient) Recv() (*tailcfg.DERPMap, error) {
+func (f fakeDERPClient) Recv() (*tailcfg.DERPMap, tailnet.DERPQUICPorts, error) {
 	if f.err != nil {
-		return nil, f.err
+		return nil, nil, f.err
 	}
 	dm, ok := <-f.ch
 	if ok {
-		return dm, nil
+		return dm, f.ports, nil
 	}
-	return nil, io.EOF
+	return nil, nil, io.EOF
 }
 
 func TestBasicTelemetryController_Success(t *testing.T) {
@@ -1200,6 +1219,8 @@ func (*fakeTailnetConn) SetNodeCallback(func(*tailnet.Node)) {}
 
 func (*fakeTailnetConn) SetDERPMap(*tailcfg.DERPMap) {}
 
+func (*fakeTailnetConn) SetDERPQUICPorts(tailnet.DERPQUICPorts) {}
+
 func (*fakeTailnetConn) SetTunnelDestination(uuid.UUID) {}
 
 type pipeDialer struct {
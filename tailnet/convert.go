@@ -276,6 +276,33 @@ func DERPNodeFromProto(node *proto.DERPMap_Region_Node) *tailcfg.DERPNode {
 	}
 }
 
+// AddDERPQUICPortsToProto sets the QUIC ports of the nodes of a DERP map
+// converted with DERPMapToProto.
+func AddDERPQUICPortsToProto(derpMap *proto.DERPMap, ports DERPQUICPorts) {
+	if derpMap == nil {
+		return
+	}
+	for _, region := range derpMap.Regions {
+		for _, node := range region.GetNodes() {
+			// #nosec G115 - Safe conversion as QUIC port is within int32 range (0-65535)
+			node.QuicPort = int32(ports[node.Name])
+		}
+	}
+}
+
+// DERPQUICPortsFromProto returns the QUIC ports of the nodes of a DERP map.
+func DERPQUICPortsFromProto(derpMap *proto.DERPMap) DERPQUICPorts {
+	ports := DERPQUICPorts{}
+	for _, region := range derpMap.GetRegions() {
+		for _, node := range region.GetNodes() {
+			if node.GetQuicPort() > 0 {
+				ports[node.Name] = int(node.QuicPort)
+			}
+		}
+	}
+	return ports
+}
+
 func WorkspaceStatusToProto(status codersdk.WorkspaceStatus) proto.Workspace_Status {
 	switch status {
 	case codersdk.WorkspaceStatusCanceled:
@@ -311,12 +338,12 @@ func (w *DERPFromDRPCWrapper) Close() error {
 	return w.Client.Close()
 }
 
-func (w *DERPFromDRPCWrapper) Recv() (*tailcfg.DERPMap, error) {
+func (w *DERPFromDRPCWrapper) Recv() (*tailcfg.DERPMap, DERPQUICPorts, error) {
 	p, err := w.Client.Recv()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return DERPMapFromProto(p), nil
+	return DERPMapFromProto(p), DERPQUICPortsFromProto(p), nil
 }
 
 var _ DERPClient = &DERPFromDRPCWrapper{}
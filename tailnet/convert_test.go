@@ -206,4 +206,11 @@ func TestDERPMap(t *testing.T) {
 	require.NotNil(t, derpMap2)
 
 	require.Equal(t, derpMap, derpMap2)
+
+	// QUIC ports travel on the nodes of the proto map. Nodes without a port
+	// don't support QUIC.
+	ports := tailnet.DERPQUICPorts{"zzz1": 4433}
+	tailnet.AddDERPQUICPortsToProto(protoMap, ports)
+	require.Equal(t, ports, tailnet.DERPQUICPortsFromProto(protoMap))
+	require.Equal(t, derpMap, tailnet.DERPMapFromProto(protoMap))
 }
@@ -0,0 +1,594 @@
+package tailnet
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quic-go/quic-go"
+	"golang.org/x/xerrors"
+	"tailscale.com/derp"
+	"tailscale.com/tailcfg"
+	"tailscale.com/util/clientmetric"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+)
+
+// DERPQUICALPN is the TLS application protocol negotiated by DERP over QUIC
+// connections.
+const DERPQUICALPN = "coder-derp"
+
+const (
+	// derpFrameHeaderLen is the length of a DERP frame header: one byte of
+	// frame type followed by a big-endian uint32 payload length.
+	derpFrameHeaderLen = 5
+	// derpFrameMaxLen bounds the payload of a single DERP frame. DERP packets
+	// are at most 64KiB, the rest is headroom for the frame's own fields.
+	derpFrameMaxLen = 1 << 20
+	// derpFrameSendPacket and derpFrameRecvPacket carry WireGuard packets
+	// from the client to the server and from the server to the client. They
+	// are the only frames sent as QUIC datagrams.
+	derpFrameSendPacket = 0x04
+	derpFrameRecvPacket = 0x05
+
+	derpQUICDialTimeout = 3 * time.Second
+	// derpQUICFailureBackoff is how long a client sticks to TCP for a region
+	// after failing to dial it over QUIC, which is usually because UDP is
+	// blocked on the path.
+	derpQUICFailureBackoff = 5 * time.Minute
+)
+
+var (
+	metricDERPQUICConns        = clientmetric.NewGauge("derp_quic_conns")
+	metricDERPQUICDialFailures = clientmetric.NewCounter("derp_quic_dial_failures")
+)
+
+// DERPQUICPorts maps DERP node names to the UDP port the node accepts DERP
+// over QUIC connections on. tailcfg.DERPNode has no field for it, so it is
+// distributed alongside the DERP map.
+type DERPQUICPorts map[string]int
+
+// Clone returns a copy of the ports.
+func (p DERPQUICPorts) Clone() DERPQUICPorts {
+	if p == nil {
+		return nil
+	}
+	return maps.Clone(p)
+}
+
+// Equal returns whether both sets of ports are the same.
+func (p DERPQUICPorts) Equal(other DERPQUICPorts) bool {
+	return maps.Equal(p, other)
+}
+
+// RegionNode returns the node DERP over QUIC connections to the region should
+// use, and its port. It follows derphttp and uses the first node that is not
+// STUN-only. The port is 0 if that node does not support QUIC.
+func (p DERPQUICPorts) RegionNode(region *tailcfg.DERPRegion) (*tailcfg.DERPNode, int) {
+	if region == nil {
+		return nil, 0
+	}
+	for _, node := range region.Nodes {
+		if node.STUNOnly {
+			continue
+		}
+		return node, p[node.Name]
+	}
+	return nil, 0
+}
+
+func derpQUICConfig() *quic.Config {
+	return &quic.Config{
+		EnableDatagrams:      true,
+		HandshakeIdleTimeout: derpQUICDialTimeout,
+		MaxIdleTimeout:       time.Minute,
+		KeepAlivePeriod:      15 * time.Second,
+	}
+}
+
+// ListenDERPQUIC listens for DERP over QUIC connections on the UDP address.
+// The TLS config is cloned and its application protocols are replaced with
+// DERPQUICALPN, so the config of an HTTPS server can be reused.
+func ListenDERPQUIC(addr string, tlsConfig *tls.Config) (*quic.Listener, error) {
+	if tlsConfig == nil {
+		return nil, xerrors.New("DERP over QUIC requires TLS")
+	}
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{DERPQUICALPN}
+	listener, err := quic.ListenAddr(addr, tlsConfig, derpQUICConfig())
+	if err != nil {
+		return nil, xerrors.Errorf("listen on %q: %w", addr, err)
+	}
+	return listener, nil
+}
+
+// DERPQUICServer accepts DERP over QUIC connections and hands them to a DERP
+// server. It implements prometheus.Collector.
+type DERPQUICServer struct {
+	logger   slog.Logger
+	server   *derp.Server
+	listener *quic.Listener
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	connsOpen  prometheus.Gauge
+	connsTotal prometheus.Counter
+}
+
+// NewDERPQUICServer starts serving DERP over QUIC connections accepted by the
+// listener. Closing the server closes the listener.
+func NewDERPQUICServer(logger slog.Logger, server *derp.Server, listener *quic.Listener) *DERPQUICServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &DERPQUICServer{
+		logger:   logger,
+		server:   server,
+		listener: listener,
+		ctx:      ctx,
+		cancel:   cancel,
+		connsOpen: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coder",
+			Subsystem: "derp_quic",
+			Name:      "open_connections",
+			Help:      "Number of open DERP over QUIC connections.",
+		}),
+		connsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "derp_quic",
+			Name:      "connections_total",
+			Help:      "Total number of accepted DERP over QUIC connections.",
+		}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s
+}
+
+// Port returns the UDP port the server listens on.
+func (s *DERPQUICServer) Port() int {
+	addr, ok := s.listener.Addr().(*net.UDPAddr)
+	if !ok {
+		return 0
+	}
+	return addr.Port
+}
+
+// Close stops accepting connections, closes the open ones and waits for them
+// to finish.
+func (s *DERPQUICServer) Close() error {
+	s.cancel()
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *DERPQUICServer) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		qc, err := s.listener.Accept(s.ctx)
+		if err != nil {
+			if s.ctx.Err() == nil && !errors.Is(err, quic.ErrServerClosed) {
+				s.logger.Warn(s.ctx, "failed to accept DERP over QUIC connection", slog.Error(err))
+			}
+			return
+		}
+		s.wg.Add(1)
+		go s.handleConn(qc)
+	}
+}
+
+func (s *DERPQUICServer) handleConn(qc *quic.Conn) {
+	defer s.wg.Done()
+	s.connsTotal.Inc()
+	s.connsOpen.Inc()
+	defer s.connsOpen.Dec()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	go func() {
+		// Unblock the DERP server when the connection goes away or the
+		// server is closed.
+		select {
+		case <-ctx.Done():
+		case <-qc.Context().Done():
+		}
+		cancel()
+		_ = qc.CloseWithError(0, "")
+	}()
+
+	// The DERP server speaks first, so the server opens the stream.
+	stream, err := qc.OpenStreamSync(ctx)
+	if err != nil {
+		s.logger.Debug(ctx, "failed to open DERP over QUIC stream", slog.Error(err))
+		return
+	}
+	nc := newDERPQUICConn(qc, stream, derpFrameRecvPacket)
+	defer nc.Close()
+	brw := bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc))
+	s.server.Accept(ctx, nc, brw, qc.RemoteAddr().String())
+}
+
+func (s *DERPQUICServer) Describe(descs chan<- *prometheus.Desc) {
+	s.connsOpen.Describe(descs)
+	s.connsTotal.Describe(descs)
+}
+
+func (s *DERPQUICServer) Collect(metrics chan<- prometheus.Metric) {
+	s.connsOpen.Collect(metrics)
+	s.connsTotal.Collect(metrics)
+}
+
+// DialDERPQUIC dials a DERP node over QUIC. The returned connection speaks the
+// DERP protocol directly, without an HTTP upgrade.
+func DialDERPQUIC(ctx context.Context, node *tailcfg.DERPNode, port int) (net.Conn, error) {
+	if node == nil || port <= 0 {
+		return nil, xerrors.New("node does not support DERP over QUIC")
+	}
+	host := node.HostName
+	if ip, err := netip.ParseAddr(node.IPv4); err == nil {
+		host = ip.String()
+	}
+	serverName := node.HostName
+	if node.CertName != "" {
+		serverName = node.CertName
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, derpQUICDialTimeout)
+	defer cancel()
+	qc, err := quic.DialAddr(ctx, net.JoinHostPort(host, strconv.Itoa(port)), &tls.Config{
+		ServerName: serverName,
+		NextProtos: []string{DERPQUICALPN},
+		// #nosec G402 - Only set for test DERP maps.
+		InsecureSkipVerify: node.InsecureForTests,
+		MinVersion:         tls.VersionTLS13,
+	}, derpQUICConfig())
+	if err != nil {
+		return nil, xerrors.Errorf("dial: %w", err)
+	}
+	stream, err := qc.AcceptStream(ctx)
+	if err != nil {
+		_ = qc.CloseWithError(0, "")
+		return nil, xerrors.Errorf("accept stream: %w", err)
+	}
+	return newDERPQUICConn(qc, stream, derpFrameSendPacket), nil
+}
+
+// derpQUICConn carries a DERP connection over a QUIC connection. DERP frames
+// are written to a single stream, except for frames carrying WireGuard packets
+// which are sent as unreliable datagrams when they fit. A lost or delayed
+// packet then doesn't hold up the packets behind it, which is what makes DERP
+// over TCP painful on lossy, high-latency links. WireGuard handles loss and
+// reordering itself.
+type derpQUICConn struct {
+	conn   *quic.Conn
+	stream *quic.Stream
+	// datagramFrame is the type of frame this side sends as datagrams.
+	datagramFrame byte
+
+	writeMu  sync.Mutex
+	writeBuf []byte
+
+	// frames receives whole frames from the stream and datagram readers.
+	frames       chan []byte
+	pending      []byte
+	readDone     chan struct{}
+	readDoneOnce sync.Once
+	readErr      error
+
+	mu              sync.Mutex
+	readDeadline    time.Time
+	deadlineChanged chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newDERPQUICConn(qc *quic.Conn, stream *quic.Stream, datagramFrame byte) *derpQUICConn {
+	c := &derpQUICConn{
+		conn:            qc,
+		stream:          stream,
+		datagramFrame:   datagramFrame,
+		frames:          make(chan []byte, 64),
+		readDone:        make(chan struct{}),
+		deadlineChanged: make(chan struct{}),
+		closed:          make(chan struct{}),
+	}
+	metricDERPQUICConns.Add(1)
+	go c.readStream()
+	return c
+}
+
+func (c *derpQUICConn) finishRead(err error) {
+	c.readDoneOnce.Do(func() {
+		c.readErr = err
+		close(c.readDone)
+	})
+}
+
+func (c *derpQUICConn) enqueue(frame []byte) bool {
+	select {
+	case c.frames <- frame:
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+func (c *derpQUICConn) readStream() {
+	datagramsStarted := false
+	header := make([]byte, derpFrameHeaderLen)
+	for {
+		_, err := io.ReadFull(c.stream, header)
+		if err != nil {
+			c.finishRead(err)
+			return
+		}
+		n := binary.BigEndian.Uint32(header[1:])
+		if n > derpFrameMaxLen {
+			c.finishRead(xerrors.Errorf("DERP frame of %d bytes is too large", n))
+			return
+		}
+		frame := make([]byte, derpFrameHeaderLen+int(n))
+		copy(frame, header)
+		_, err = io.ReadFull(c.stream, frame[derpFrameHeaderLen:])
+		if err != nil {
+			c.finishRead(err)
+			return
+		}
+		if !c.enqueue(frame) {
+			return
+		}
+		// Datagrams are only read once the first stream frame is queued.
+		// Otherwise a packet could overtake the DERP handshake.
+		if !datagramsStarted && c.conn.ConnectionState().SupportsDatagrams {
+			datagramsStarted = true
+			go c.readDatagrams()
+		}
+	}
+}
+
+func (c *derpQUICConn) readDatagrams() {
+	for {
+		frame, err := c.conn.ReceiveDatagram(c.conn.Context())
+		if err != nil {
+			return
+		}
+		// Datagrams always hold exactly one frame. Drop anything else, the
+		// same as a lost packet.
+		if len(frame) < derpFrameHeaderLen ||
+			int(binary.BigEndian.Uint32(frame[1:derpFrameHeaderLen])) != len(frame)-derpFrameHeaderLen {
+			continue
+		}
+		if !c.enqueue(frame) {
+			return
+		}
+	}
+}
+
+func (c *derpQUICConn) Read(b []byte) (int, error) {
+	for len(c.pending) == 0 {
+		c.mu.Lock()
+		deadline, changed := c.readDeadline, c.deadlineChanged
+		c.mu.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, os.ErrDeadlineExceeded
+			}
+			timer = time.NewTimer(d)
+			timeout = timer.C
+		}
+
+		var err error
+		select {
+		case frame := <-c.frames:
+			c.pending = frame
+		case <-c.readDone:
+			// Hand out whatever was queued before the stream ended.
+			select {
+			case frame := <-c.frames:
+				c.pending = frame
+			default:
+				err = c.readErr
+			}
+		case <-c.closed:
+			err = net.ErrClosed
+		case <-timeout:
+			err = os.ErrDeadlineExceeded
+		case <-changed:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *derpQUICConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.writeBuf = append(c.writeBuf, b...)
+	buf := c.writeBuf
+	for len(buf) >= derpFrameHeaderLen {
+		n := derpFrameHeaderLen + int(binary.BigEndian.Uint32(buf[1:derpFrameHeaderLen]))
+		if len(buf) < n {
+			break
+		}
+		err := c.writeFrame(buf[:n])
+		if err != nil {
+			return 0, err
+		}
+		buf = buf[n:]
+	}
+	// Keep any partial frame until the rest of it is written.
+	c.writeBuf = append(c.writeBuf[:0], buf...)
+	return len(b), nil
+}
+
+func (c *derpQUICConn) writeFrame(frame []byte) error {
+	if frame[0] == c.datagramFrame && c.conn.ConnectionState().SupportsDatagrams {
+		err := c.conn.SendDatagram(frame)
+		if err == nil {
+			return nil
+		}
+		var tooLarge *quic.DatagramTooLargeError
+		if !errors.As(err, &tooLarge) {
+			return err
+		}
+		// The packet doesn't fit in a datagram on this path, so it goes on
+		// the stream instead.
+	}
+	_, err := c.stream.Write(frame)
+	return err
+}
+
+func (c *derpQUICConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		metricDERPQUICConns.Add(-1)
+		c.stream.CancelRead(0)
+		_ = c.stream.Close()
+		_ = c.conn.CloseWithError(0, "")
+	})
+	return nil
+}
+
+func (c *derpQUICConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *derpQUICConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *derpQUICConn) SetDeadline(t time.Time) error {
+	_ = c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+func (c *derpQUICConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	close(c.deadlineChanged)
+	c.deadlineChanged = make(chan struct{})
+	return nil
+}
+
+func (c *derpQUICConn) SetWriteDeadline(t time.Time) error {
+	return c.stream.SetWriteDeadline(t)
+}
+
+var _ net.Conn = &derpQUICConn{}
+
+// derpRegionDialer is the DERP region dialer of a Conn. It prefers a dialer
+// set with Conn.SetDERPRegionDialer, then DERP over QUIC for regions that
+// support it. Returning nil makes the DERP client dial TCP or WebSockets
+// itself.
+//
+// QUIC is an optional transport, not a fallback: it runs over UDP, so it only
+// helps when UDP reaches the DERP server. When the QUIC dial fails, for
+// example because UDP is blocked, the region is dialed over TCP or WebSockets
+// and QUIC is not tried again for derpQUICFailureBackoff.
+type derpRegionDialer struct {
+	logger slog.Logger
+	clock  quartz.Clock
+	// dialQUIC is DialDERPQUIC, replaced in tests.
+	dialQUIC func(ctx context.Context, node *tailcfg.DERPNode, port int) (net.Conn, error)
+
+	mu              sync.Mutex
+	custom          func(ctx context.Context, region *tailcfg.DERPRegion) net.Conn
+	ports           DERPQUICPorts
+	forceWebSockets bool
+	// failedUntil holds the regions DERP over QUIC recently failed for.
+	failedUntil map[int]time.Time
+}
+
+func newDERPRegionDialer(logger slog.Logger, ports DERPQUICPorts, forceWebSockets bool) *derpRegionDialer {
+	return &derpRegionDialer{
+		logger:          logger,
+		clock:           quartz.NewReal(),
+		dialQUIC:        DialDERPQUIC,
+		ports:           ports.Clone(),
+		forceWebSockets: forceWebSockets,
+		failedUntil:     map[int]time.Time{},
+	}
+}
+
+func (d *derpRegionDialer) setCustom(dialer func(ctx context.Context, region *tailcfg.DERPRegion) net.Conn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.custom = dialer
+}
+
+// setPorts replaces the QUIC ports and reports whether they changed.
+func (d *derpRegionDialer) setPorts(ports DERPQUICPorts) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ports.Equal(ports) {
+		return false
+	}
+	d.ports = ports.Clone()
+	return true
+}
+
+func (d *derpRegionDialer) setForceWebSockets(v bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.forceWebSockets = v
+}
+
+func (d *derpRegionDialer) dial(ctx context.Context, region *tailcfg.DERPRegion) net.Conn {
+	d.mu.Lock()
+	custom := d.custom
+	node, port := d.ports.RegionNode(region)
+	skip := d.forceWebSockets || d.clock.Now().Before(d.failedUntil[region.RegionID])
+	d.mu.Unlock()
+
+	if custom != nil {
+		if conn := custom(ctx, region); conn != nil {
+			return conn
+		}
+	}
+	if port == 0 || skip {
+		return nil
+	}
+	// DialDERPQUIC only returns once the server has started the DERP
+	// handshake, so a node that accepts QUIC but doesn't serve DERP on it
+	// fails here as well and falls back to TCP.
+	conn, err := d.dialQUIC(ctx, node, port)
+	if err != nil {
+		metricDERPQUICDialFailures.Add(1)
+		d.logger.Info(ctx, "failed to dial DERP over QUIC, falling back to TCP",
+			slog.F("region_id", region.RegionID), slog.F("node", node.Name), slog.Error(err))
+		d.mu.Lock()
+		d.failedUntil[region.RegionID] = d.clock.Now().Add(derpQUICFailureBackoff)
+		d.mu.Unlock()
+		return nil
+	}
+	d.logger.Debug(ctx, "connected to DERP over QUIC",
+		slog.F("region_id", region.RegionID), slog.F("node", node.Name))
+	return conn
+}
@@ -0,0 +1,132 @@
+package tailnet
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
+
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestDERPRegionDialer_FallbackOrder(t *testing.T) {
+	t.Parallel()
+
+	region := &tailcfg.DERPRegion{
+		RegionID: 1,
+		Nodes: []*tailcfg.DERPNode{
+			{Name: "1stun", RegionID: 1, STUNOnly: true},
+			{Name: "1a", RegionID: 1},
+		},
+	}
+	ports := DERPQUICPorts{"1a": 4443}
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	newDialer := func(t *testing.T, ports DERPQUICPorts) (*derpRegionDialer, *quartz.Mock, chan dialResult, *int) {
+		mClock := quartz.NewMock(t)
+		results := make(chan dialResult, 1)
+		dials := 0
+		d := newDERPRegionDialer(testutil.Logger(t), ports, false)
+		d.clock = mClock
+		d.dialQUIC = func(_ context.Context, node *tailcfg.DERPNode, port int) (net.Conn, error) {
+			dials++
+			require.Equal(t, "1a", node.Name)
+			require.Equal(t, 4443, port)
+			select {
+			case r := <-results:
+				return r.conn, r.err
+			default:
+				t.Error("unexpected QUIC dial")
+				return nil, xerrors.New("unexpected dial")
+			}
+		}
+		return d, mClock, results, &dials
+	}
+
+	t.Run("CustomFirst", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		d, _, _, dials := newDialer(t, ports)
+
+		custom, _ := net.Pipe()
+		defer custom.Close()
+		d.setCustom(func(context.Context, *tailcfg.DERPRegion) net.Conn { return custom })
+		require.Equal(t, custom, d.dial(ctx, region))
+		require.Zero(t, *dials)
+	})
+
+	t.Run("QUICAfterCustom", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		d, _, results, dials := newDialer(t, ports)
+
+		// A custom dialer that declines the region lets QUIC dial it.
+		d.setCustom(func(context.Context, *tailcfg.DERPRegion) net.Conn { return nil })
+		quicConn, _ := net.Pipe()
+		defer quicConn.Close()
+		results <- dialResult{conn: quicConn}
+		require.Equal(t, quicConn, d.dial(ctx, region))
+		require.Equal(t, 1, *dials)
+	})
+
+	t.Run("NoQUICPort", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		d, _, _, dials := newDialer(t, nil)
+
+		// Without a QUIC port the DERP client dials TCP or WebSockets.
+		require.Nil(t, d.dial(ctx, region))
+		require.Zero(t, *dials)
+	})
+
+	t.Run("ForceWebSockets", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		d, _, _, dials := newDialer(t, ports)
+
+		d.setForceWebSockets(true)
+		require.Nil(t, d.dial(ctx, region))
+		require.Zero(t, *dials)
+	})
+
+	t.Run("TCPAfterFailure", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		d, mClock, results, dials := newDialer(t, ports)
+
+		// A failed QUIC dial falls back to TCP or WebSockets.
+		results <- dialResult{err: xerrors.New("udp blocked")}
+		require.Nil(t, d.dial(ctx, region))
+		require.Equal(t, 1, *dials)
+
+		// During the backoff QUIC is not dialed at all, so reconnects go
+		// straight to TCP or WebSockets.
+		mClock.Advance(derpQUICFailureBackoff - 1)
+		require.Nil(t, d.dial(ctx, region))
+		require.Equal(t, 1, *dials)
+
+		// Other regions are not affected by the backoff.
+		other := &tailcfg.DERPRegion{
+			RegionID: 2,
+			Nodes:    []*tailcfg.DERPNode{{Name: "1a", RegionID: 2}},
+		}
+		quicConn, _ := net.Pipe()
+		defer quicConn.Close()
+		results <- dialResult{conn: quicConn}
+		require.Equal(t, quicConn, d.dial(ctx, other))
+		require.Equal(t, 2, *dials)
+
+		// Once the backoff is over QUIC is tried again.
+		mClock.Advance(1)
+		results <- dialResult{conn: quicConn}
+		require.Equal(t, quicConn, d.dial(ctx, region))
+		require.Equal(t, 3, *dials)
+	})
+}
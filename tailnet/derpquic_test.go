@@ -0,0 +1,207 @@
+package tailnet_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/tailnettest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func runDERPQUIC(t *testing.T) (*tailcfg.DERPMap, tailnet.DERPQUICPorts) {
+	t.Helper()
+	logger := testutil.Logger(t)
+	d := derp.NewServer(key.NewNode(), tailnet.Logger(logger.Named("derp")))
+	listener, err := tailnet.ListenDERPQUIC("127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{testutil.GenerateTLSCertificate(t, "localhost")},
+		MinVersion:   tls.VersionTLS13,
+	})
+	require.NoError(t, err)
+	server := tailnet.NewDERPQUICServer(logger.Named("derpquic"), d, listener)
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = d.Close()
+	})
+
+	// Nothing listens on the DERP port, so DERP only works over QUIC.
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tcpPort := tcpListener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, tcpListener.Close())
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {
+				RegionID:   1,
+				RegionCode: "test",
+				RegionName: "Test",
+				Nodes: []*tailcfg.DERPNode{{
+					Name:             "1a",
+					RegionID:         1,
+					HostName:         "localhost",
+					IPv4:             "127.0.0.1",
+					IPv6:             "none",
+					STUNPort:         -1,
+					DERPPort:         tcpPort,
+					InsecureForTests: true,
+				}},
+			},
+		},
+	}
+	return derpMap, tailnet.DERPQUICPorts{"1a": server.Port()}
+}
+
+func TestDERPQUIC(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Exchange", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		logf := tailnet.Logger(testutil.Logger(t))
+		derpMap, ports := runDERPQUIC(t)
+		region := derpMap.Regions[1]
+
+		packets := func(c *derphttp.Client) <-chan derp.ReceivedPacket {
+			ch := make(chan derp.ReceivedPacket)
+			go func() {
+				defer close(ch)
+				for {
+					msg, err := c.Recv()
+					if err != nil {
+						return
+					}
+					if pkt, ok := msg.(derp.ReceivedPacket); ok {
+						select {
+						case ch <- pkt:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}()
+			return ch
+		}
+		newClient := func() (*derphttp.Client, key.NodePublic) {
+			k := key.NewNode()
+			c := derphttp.NewRegionClient(k, logf, nil, func() *tailcfg.DERPRegion { return region })
+			c.SetRegionDialer(func(ctx context.Context, region *tailcfg.DERPRegion) net.Conn {
+				node, port := ports.RegionNode(region)
+				conn, err := tailnet.DialDERPQUIC(ctx, node, port)
+				if err != nil {
+					t.Logf("dial DERP over QUIC: %v", err)
+					return nil
+				}
+				return conn
+			})
+			t.Cleanup(func() { _ = c.Close() })
+			require.NoError(t, c.Connect(ctx))
+			// The server info is sent once the server registered the
+			// client, so packets sent to it after this are delivered.
+			msg, err := c.Recv()
+			require.NoError(t, err)
+			require.IsType(t, derp.ServerInfoMessage{}, msg)
+			return c, k.Public()
+		}
+		sender, _ := newClient()
+		receiver, receiverKey := newClient()
+		received := packets(receiver)
+
+		// A small packet fits in a QUIC datagram, a large one goes on the
+		// stream.
+		for _, size := range []int{100, 16 << 10} {
+			payload := bytes.Repeat([]byte{byte(size)}, size)
+			require.NoError(t, sender.Send(receiverKey, payload))
+			pkt := testutil.TryReceive(ctx, t, received)
+			require.Equal(t, payload, pkt.Data)
+		}
+	})
+
+	t.Run("Conn", func(t *testing.T) {
+		t.Parallel()
+		logger := testutil.Logger(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		derpMap, ports := runDERPQUIC(t)
+
+		ip1 := tailnet.TailscaleServicePrefix.RandomAddr()
+		conn1, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:      []netip.Prefix{netip.PrefixFrom(ip1, 128)},
+			Logger:         logger.Named("w1"),
+			DERPMap:        derpMap,
+			DERPQUICPorts:  ports,
+			BlockEndpoints: true,
+		})
+		require.NoError(t, err)
+		defer conn1.Close()
+
+		conn2, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:      []netip.Prefix{tailnet.TailscaleServicePrefix.RandomPrefix()},
+			Logger:         logger.Named("w2"),
+			DERPMap:        derpMap,
+			BlockEndpoints: true,
+		})
+		require.NoError(t, err)
+		defer conn2.Close()
+		// Ports set after the fact apply as well.
+		conn2.SetDERPQUICPorts(ports)
+
+		stitch(t, conn2, conn1)
+		stitch(t, conn1, conn2)
+		require.True(t, conn2.AwaitReachable(ctx, ip1))
+		_, p2p, _, err := conn2.Ping(ctx, ip1)
+		require.NoError(t, err)
+		require.False(t, p2p)
+	})
+
+	t.Run("FallbackToTCP", func(t *testing.T) {
+		t.Parallel()
+		logger := testutil.Logger(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		derpMap, _ := tailnettest.RunDERPAndSTUN(t)
+
+		// Nothing listens on the QUIC port, as when UDP is blocked, so the
+		// DERP client has to fall back to TCP.
+		udpListener, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		ports := tailnet.DERPQUICPorts{"t2": udpListener.LocalAddr().(*net.UDPAddr).Port}
+		require.NoError(t, udpListener.Close())
+
+		ip1 := tailnet.TailscaleServicePrefix.RandomAddr()
+		conn1, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:      []netip.Prefix{netip.PrefixFrom(ip1, 128)},
+			Logger:         logger.Named("w1"),
+			DERPMap:        derpMap,
+			DERPQUICPorts:  ports,
+			BlockEndpoints: true,
+		})
+		require.NoError(t, err)
+		defer conn1.Close()
+
+		conn2, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:      []netip.Prefix{tailnet.TailscaleServicePrefix.RandomPrefix()},
+			Logger:         logger.Named("w2"),
+			DERPMap:        derpMap,
+			DERPQUICPorts:  ports,
+			BlockEndpoints: true,
+		})
+		require.NoError(t, err)
+		defer conn2.Close()
+
+		stitch(t, conn2, conn1)
+		stitch(t, conn1, conn2)
+		require.True(t, conn2.AwaitReachable(ctx, ip1))
+		_, p2p, _, err := conn2.Ping(ctx, ip1)
+		require.NoError(t, err)
+		require.False(t, p2p)
+	})
+}
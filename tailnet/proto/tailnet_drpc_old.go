@@ -50,3 +50,23 @@ type DRPCTailnetClient25 interface {
 type DRPCTailnetClient26 interface {
 	DRPCTailnetClient25
 }
+
+// DRPCTailnetClient27 is the Tailnet API at v2.7.
+type DRPCTailnetClient27 interface {
+	DRPCTailnetClient26
+}
+
+// DRPCTailnetClient28 is the Tailnet API at v2.8.
+type DRPCTailnetClient28 interface {
+	DRPCTailnetClient27
+}
+
+// DRPCTailnetClient29 is the Tailnet API at v2.9.
+type DRPCTailnetClient29 interface {
+	DRPCTailnetClient28
+}
+
+// DRPCTailnetClient210 is the Tailnet API at v2.10.
+type DRPCTailnetClient210 interface {
+	DRPCTailnetClient29
+}
@@ -55,9 +55,29 @@ import (
 //   - Added support for CreateSubAgent RPC on the Agent API.
 //   - Added support for DeleteSubAgent RPC on the Agent API.
 //   - Added support for ListSubAgents RPC on the Agent API.
+//
+// API v2.7:
+//   - Added `Secrets` to the agent manifest.
+//
+// API v2.8:
+//   - Added `SessionRecordingEnabled` to the agent manifest.
+//   - Added support for UploadSessionRecording RPC on the Agent API.
+//
+// API v2.9:
+//   - Added CPU, load average, inodes and process count monitors to the
+//     GetResourcesMonitoringConfiguration and PushResourcesMonitoringUsage
+//     RPCs on the Agent API.
+//
+// API v2.10:
+//   - Added `Services` to the agent manifest.
+//   - Added support for BatchUpdateServices RPC on the Agent API.
+//
+// API v2.11:
+//   - Added `quic_port` to DERP nodes in the DERP map on the Tailnet API.
+//   - No changes to the Agent API.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 6
+	CurrentMinor = 11
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
@@ -55,10 +55,13 @@ type TunnelAuthorizer interface {
 }
 
 type ClientServiceOptions struct {
-	Logger                   slog.Logger
-	CoordPtr                 *atomic.Pointer[Coordinator]
-	DERPMapUpdateFrequency   time.Duration
-	DERPMapFn                func() *tailcfg.DERPMap
+	Logger                 slog.Logger
+	CoordPtr               *atomic.Pointer[Coordinator]
+	DERPMapUpdateFrequency time.Duration
+	DERPMapFn              func() *tailcfg.DERPMap
+	// DERPQUICPortsFn is optional, and returns the QUIC ports of the DERP
+	// nodes in the map returned by DERPMapFn.
+	DERPQUICPortsFn          func() DERPQUICPorts
 	NetworkTelemetryHandler  func(batch []*proto.TelemetryEvent)
 	ResumeTokenProvider      ResumeTokenProvider
 	WorkspaceUpdatesProvider WorkspaceUpdatesProvider
@@ -84,6 +87,7 @@ func NewClientService(options ClientServiceOptions) (
 		Logger:                   options.Logger,
 		DerpMapUpdateFrequency:   options.DERPMapUpdateFrequency,
 		DerpMapFn:                options.DERPMapFn,
+		DerpQUICPortsFn:          options.DERPQUICPortsFn,
 		NetworkTelemetryHandler:  options.NetworkTelemetryHandler,
 		ResumeTokenProvider:      options.ResumeTokenProvider,
 		WorkspaceUpdatesProvider: options.WorkspaceUpdatesProvider,
@@ -141,6 +145,7 @@ type DRPCService struct {
 	Logger                   slog.Logger
 	DerpMapUpdateFrequency   time.Duration
 	DerpMapFn                func() *tailcfg.DERPMap
+	DerpQUICPortsFn          func() DERPQUICPorts
 	NetworkTelemetryHandler  func(batch []*proto.TelemetryEvent)
 	ResumeTokenProvider      ResumeTokenProvider
 	WorkspaceUpdatesProvider WorkspaceUpdatesProvider
@@ -159,20 +164,29 @@ func (s *DRPCService) StreamDERPMaps(_ *proto.StreamDERPMapsRequest, stream prot
 	ticker := time.NewTicker(s.DerpMapUpdateFrequency)
 	defer ticker.Stop()
 
-	var lastDERPMap *tailcfg.DERPMap
+	var (
+		lastDERPMap *tailcfg.DERPMap
+		lastPorts   DERPQUICPorts
+	)
 	for {
 		derpMap := s.DerpMapFn()
 		if derpMap == nil {
 			// in testing, we send nil to close the stream.
 			return io.EOF
 		}
-		if lastDERPMap == nil || !CompareDERPMaps(lastDERPMap, derpMap) {
+		var ports DERPQUICPorts
+		if s.DerpQUICPortsFn != nil {
+			ports = s.DerpQUICPortsFn()
+		}
+		if lastDERPMap == nil || !CompareDERPMaps(lastDERPMap, derpMap) || !lastPorts.Equal(ports) {
 			protoDERPMap := DERPMapToProto(derpMap)
+			AddDERPQUICPortsToProto(protoDERPMap, ports)
 			err := stream.Send(protoDERPMap)
 			if err != nil {
 				return xerrors.Errorf("send derp map: %w", err)
 			}
 			lastDERPMap = derpMap
+			lastPorts = ports
 		}
 
 		ticker.Reset(s.DerpMapUpdateFrequency)
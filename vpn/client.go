@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tailscale/wireguard-go/tun"
+	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/codersdk"
@@ -27,6 +28,9 @@ import (
 type Conn interface {
 	CurrentWorkspaceState() (tailnet.WorkspaceUpdate, error)
 	GetPeerDiagnostics(peerID uuid.UUID) tailnet.PeerDiagnostics
+	DialContextTCP(ctx context.Context, ipp netip.AddrPort) (*gonet.TCPConn, error)
+	DialContextUDP(ctx context.Context, ipp netip.AddrPort) (*gonet.UDPConn, error)
+	AwaitReachable(ctx context.Context, ip netip.Addr) bool
 	Close() error
 }
 
@@ -66,6 +70,9 @@ type Options struct {
 	TUNDevice        tun.Device
 	WireguardMonitor *netmon.Monitor
 	UpdateHandler    tailnet.UpdatesHandler
+	// DNSHostsSetter receives the names of workspaces instead of the DNS
+	// configurator, for connections without a TUN device.
+	DNSHostsSetter tailnet.DNSHostsSetter
 }
 
 func (*client) NewConn(initCtx context.Context, serverURL *url.URL, token string, options *Options) (vpnC Conn, err error) {
@@ -129,6 +136,7 @@ func (*client) NewConn(initCtx context.Context, serverURL *url.URL, token string
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses:           []netip.Prefix{netip.PrefixFrom(ip, 128)},
 		DERPMap:             connInfo.DERPMap,
+		DERPQUICPorts:       connInfo.DERPQUICPorts,
 		DERPHeader:          &headers,
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
@@ -148,6 +156,17 @@ func (*client) NewConn(initCtx context.Context, serverURL *url.URL, token string
 		}
 	}()
 
+	updatesOpts := []tailnet.TunnelAllOption{tailnet.WithHandler(options.UpdateHandler)}
+	switch {
+	case options.DNSHostsSetter != nil:
+		updatesOpts = append(updatesOpts, tailnet.WithDNS(options.DNSHostsSetter, me.Username, dnsNameOptions))
+	case options.DNSConfigurator != nil:
+		updatesOpts = append(updatesOpts, tailnet.WithDNS(conn, me.Username, dnsNameOptions))
+	default:
+		// The tailnet cannot set hosts without a DNS configurator, the
+		// workspaces are only reachable by address.
+	}
+
 	clk := quartz.NewReal()
 	controller := tailnet.NewController(options.Logger, dialer)
 	coordCtrl := tailnet.NewTunnelSrcCoordController(options.Logger, conn)
@@ -157,8 +176,7 @@ func (*client) NewConn(initCtx context.Context, serverURL *url.URL, token string
 	updatesCtrl := tailnet.NewTunnelAllWorkspaceUpdatesController(
 		options.Logger,
 		coordCtrl,
-		tailnet.WithDNS(conn, me.Username, dnsNameOptions),
-		tailnet.WithHandler(options.UpdateHandler),
+		updatesOpts...,
 	)
 	controller.WorkspaceUpdatesCtrl = updatesCtrl
 	controller.Run(ctx)
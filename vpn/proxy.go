@@ -0,0 +1,253 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"tailscale.com/net/proxymux"
+	"tailscale.com/net/socks5"
+	"tailscale.com/util/dnsname"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+// ProxyOptions configures a Proxy.
+type ProxyOptions struct {
+	Logger slog.Logger
+	// Dial connects to a workspace agent over the tailnet.
+	Dial func(ctx context.Context, ipp netip.AddrPort) (net.Conn, error)
+	// DirectDial connects to hosts that are not workspaces.  If nil, these
+	// connections are refused.
+	DirectDial func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Suffix is the hostname suffix of workspaces, names with the suffix
+	// that are not known are refused even if DirectDial is set.
+	Suffix string
+}
+
+// Proxy serves a SOCKS5 and an HTTP proxy on the same listener that connect to
+// workspaces over a tailnet connection, so no TUN device or root is required.
+// Workspace names are resolved with the hosts set from the workspace updates,
+// the proxy is the DNSHostsSetter of the connection.
+type Proxy struct {
+	opts ProxyOptions
+
+	mu    sync.RWMutex
+	hosts map[dnsname.FQDN][]netip.Addr
+}
+
+// NewProxy returns a proxy that resolves no names until the hosts are set.
+func NewProxy(opts ProxyOptions) *Proxy {
+	if opts.Suffix == "" {
+		opts.Suffix = tailnet.CoderDNSSuffix
+	}
+	return &Proxy{
+		opts:  opts,
+		hosts: map[dnsname.FQDN][]netip.Addr{},
+	}
+}
+
+var _ tailnet.DNSHostsSetter = &Proxy{}
+
+// SetDNSHosts replaces the names of workspaces the proxy resolves.
+func (p *Proxy) SetDNSHosts(hosts map[dnsname.FQDN][]netip.Addr) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hosts = hosts
+	return nil
+}
+
+// Hosts returns the workspace names the proxy resolves, without the trailing
+// dot.
+func (p *Proxy) Hosts() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	hosts := make([]string, 0, len(p.hosts))
+	for fqdn := range p.hosts {
+		hosts = append(hosts, fqdn.WithoutTrailingDot())
+	}
+	return hosts
+}
+
+// Serve serves the SOCKS5 and HTTP proxies on the listener until the context
+// ends.  The listener is closed when Serve returns.
+func (p *Proxy) Serve(ctx context.Context, ln net.Listener) error {
+	socksListener, httpListener := proxymux.SplitSOCKSAndHTTP(ln)
+	logger := p.opts.Logger
+
+	socksServer := &socks5.Server{
+		Logf: func(format string, args ...any) {
+			logger.Debug(ctx, fmt.Sprintf(format, args...))
+		},
+		Dialer: p.Dial,
+	}
+	httpServer := &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- socksServer.Serve(socksListener)
+	}()
+	go func() {
+		errs <- httpServer.Serve(httpListener)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	_ = ln.Close()
+	_ = httpServer.Close()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Dial connects to the address through the tailnet if the host is a
+// workspace, and directly otherwise if allowed.
+func (p *Proxy) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, xerrors.Errorf("unsupported network %q", network)
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, xerrors.Errorf("parse address %q: %w", addr, err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, xerrors.Errorf("parse port %q: %w", portStr, err)
+	}
+
+	ip, ok := p.resolve(host)
+	if !ok {
+		if p.isWorkspaceName(host) {
+			return nil, xerrors.Errorf("workspace %q not found", host)
+		}
+		if p.opts.DirectDial == nil {
+			return nil, xerrors.Errorf("%q is not a workspace", host)
+		}
+		return p.opts.DirectDial(ctx, network, addr)
+	}
+	conn, err := p.opts.Dial(ctx, netip.AddrPortFrom(ip, uint16(port)))
+	if err != nil {
+		return nil, xerrors.Errorf("dial workspace %q: %w", host, err)
+	}
+	p.opts.Logger.Debug(ctx, "proxying connection to workspace", slog.F("addr", addr))
+	return conn, nil
+}
+
+// resolve returns the tailnet address of a workspace name or address.
+func (p *Proxy) resolve(host string) (netip.Addr, bool) {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return ip, tailnet.CoderServicePrefix.AsNetip().Contains(ip) ||
+			tailnet.TailscaleServicePrefix.AsNetip().Contains(ip)
+	}
+	fqdn, err := dnsname.ToFQDN(strings.ToLower(host))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	addrs := p.hosts[fqdn]
+	if len(addrs) == 0 {
+		return netip.Addr{}, false
+	}
+	return addrs[0], true
+}
+
+func (p *Proxy) isWorkspaceName(host string) bool {
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), "."+p.opts.Suffix)
+}
+
+// ServeHTTP serves CONNECT requests and forwards plain HTTP requests, which
+// clients send to HTTP proxies with the absolute URL.
+func (p *Proxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		if r.URL.Host == "" {
+			http.Error(rw, "This is a proxy, requests must have an absolute URL.", http.StatusBadRequest)
+			return
+		}
+		p.forward(rw, r)
+		return
+	}
+
+	conn, err := p.Dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		p.opts.Logger.Debug(r.Context(), "dial for connect", slog.F("host", r.Host), slog.Error(err))
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer conn.Close()
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		http.Error(rw, "Hijacking is not supported.", http.StatusInternalServerError)
+		return
+	}
+	client, buf, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer client.Close()
+	_, err = io.WriteString(client, "HTTP/1.1 200 Connection established\r\n\r\n")
+	if err != nil {
+		return
+	}
+	// The client may have sent data after the request.
+	if n := buf.Reader.Buffered(); n > 0 {
+		data, _ := buf.Reader.Peek(n)
+		if _, err := conn.Write(data); err != nil {
+			return
+		}
+	}
+	pipe(client, conn)
+}
+
+func (p *Proxy) forward(rw http.ResponseWriter, r *http.Request) {
+	proxy := &httputil.ReverseProxy{
+		// Requests to proxies have the absolute URL, so the outgoing
+		// request needs no changes.
+		Rewrite: func(*httputil.ProxyRequest) {},
+		Transport: &http.Transport{
+			DialContext:       p.Dial,
+			DisableKeepAlives: true,
+		},
+		ErrorHandler: func(rw http.ResponseWriter, r *http.Request, err error) {
+			p.opts.Logger.Debug(r.Context(), "forward request", slog.F("url", r.URL.String()), slog.Error(err))
+			http.Error(rw, err.Error(), http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(rw, r)
+}
+
+// pipe copies between the connections until either side closes.
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,146 @@
+package vpn_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+	"tailscale.com/util/dnsname"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/coder/v2/vpn"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := testutil.Logger(t)
+
+	// The workspace is a local server that the fake tailnet dials.
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(rw, "hello from %s", r.Host)
+	}))
+	t.Cleanup(srv.Close)
+	agentIP := tailnet.CoderServicePrefix.AddrFromUUID(uuid.New())
+
+	p := vpn.NewProxy(vpn.ProxyOptions{
+		Logger: logger,
+		Dial: func(ctx context.Context, ipp netip.AddrPort) (net.Conn, error) {
+			assert.Equal(t, agentIP, ipp.Addr())
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", srv.Listener.Addr().String())
+		},
+	})
+	require.NoError(t, p.SetDNSHosts(map[dnsname.FQDN][]netip.Addr{
+		"agnt.wrk.me.coder.":    {agentIP},
+		"agnt.wrk.owner.coder.": {agentIP},
+	}))
+	require.ElementsMatch(t, []string{"agnt.wrk.me.coder", "agnt.wrk.owner.coder"}, p.Hosts())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveCtx, cancel := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() {
+		served <- p.Serve(serveCtx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-served)
+	})
+	proxyAddr := ln.Addr().String()
+
+	get := func(t *testing.T, client *http.Client, u string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		require.NoError(t, err)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+
+	t.Run("SOCKS5", func(t *testing.T) {
+		t.Parallel()
+		dialer, err := proxy.SOCKS5("tcp", proxyAddr, nil, proxy.Direct)
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: dialer.(proxy.ContextDialer).DialContext,
+		}}
+		status, body := get(t, client, "http://agnt.wrk.owner.coder:8080/")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "hello from agnt.wrk.owner.coder:8080", body)
+
+		// Unknown workspaces are refused.
+		_, err = dialer.Dial("tcp", "other.wrk.me.coder:8080")
+		require.Error(t, err)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		t.Parallel()
+		proxyURL, err := url.Parse("http://" + proxyAddr)
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+		status, body := get(t, client, "http://agnt.wrk.me.coder:3000/")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "hello from agnt.wrk.me.coder:3000", body)
+
+		status, body = get(t, client, "http://other.wrk.me.coder/")
+		require.Equal(t, http.StatusBadGateway, status)
+		require.Contains(t, body, "not found")
+
+		// Hosts that are not workspaces are refused without a direct dialer.
+		status, body = get(t, client, "http://example.com/")
+		require.Equal(t, http.StatusBadGateway, status)
+		require.Contains(t, body, "is not a workspace")
+	})
+
+	t.Run("CONNECT", func(t *testing.T) {
+		t.Parallel()
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = io.WriteString(conn, "CONNECT agnt.wrk.me.coder:5432 HTTP/1.1\r\nHost: agnt.wrk.me.coder:5432\r\n\r\n")
+		require.NoError(t, err)
+		br := bufio.NewReader(conn)
+		res, err := http.ReadResponse(br, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		// The connection is a tunnel to the workspace now.
+		_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: tunneled\r\nConnection: close\r\n\r\n")
+		require.NoError(t, err)
+		res, err = http.ReadResponse(br, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, "hello from tunneled", string(body))
+	})
+
+	t.Run("Address", func(t *testing.T) {
+		t.Parallel()
+		// Tailnet addresses of agents are proxied without a name.
+		dialer, err := proxy.SOCKS5("tcp", proxyAddr, nil, proxy.Direct)
+		require.NoError(t, err)
+		conn, err := dialer.Dial("tcp", net.JoinHostPort(agentIP.String(), "22"))
+		require.NoError(t, err)
+		_ = conn.Close()
+	})
+}
@@ -0,0 +1,144 @@
+//go:build linux
+
+package vpn
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+
+	"github.com/tailscale/netlink"
+	"golang.org/x/xerrors"
+	"tailscale.com/wgengine/router"
+)
+
+// linuxRouter assigns the addresses and routes of the tailnet to the TUN
+// device over netlink.  Unlike the Tailscale router it never touches the
+// firewall, so it does not need iptables or nftables.
+type linuxRouter struct {
+	link netlink.Link
+
+	mu     sync.Mutex
+	addrs  map[netip.Prefix]struct{}
+	routes map[netip.Prefix]struct{}
+}
+
+func newLinuxRouter(devName string) (*linuxRouter, error) {
+	link, err := netlink.LinkByName(devName)
+	if err != nil {
+		return nil, xerrors.Errorf("find link %q: %w", devName, err)
+	}
+	return &linuxRouter{
+		link:   link,
+		addrs:  map[netip.Prefix]struct{}{},
+		routes: map[netip.Prefix]struct{}{},
+	}, nil
+}
+
+func (r *linuxRouter) Up() error {
+	err := netlink.LinkSetUp(r.link)
+	if err != nil {
+		return xerrors.Errorf("set link up: %w", err)
+	}
+	return nil
+}
+
+// Set replaces the addresses and routes of the device with the ones in the
+// config.
+func (r *linuxRouter) Set(cfg *router.Config) error {
+	if cfg == nil {
+		cfg = &router.Config{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	addrs := prefixSet(cfg.LocalAddrs)
+	for p := range r.addrs {
+		if _, ok := addrs[p]; ok {
+			continue
+		}
+		err := netlink.AddrDel(r.link, &netlink.Addr{IPNet: ipNet(p)})
+		if err != nil {
+			return xerrors.Errorf("delete address %s: %w", p, err)
+		}
+		delete(r.addrs, p)
+	}
+	for p := range addrs {
+		if _, ok := r.addrs[p]; ok {
+			continue
+		}
+		err := netlink.AddrReplace(r.link, &netlink.Addr{IPNet: ipNet(p)})
+		if err != nil {
+			return xerrors.Errorf("add address %s: %w", p, err)
+		}
+		r.addrs[p] = struct{}{}
+	}
+
+	routes := prefixSet(cfg.Routes)
+	for p := range r.routes {
+		if _, ok := routes[p]; ok {
+			continue
+		}
+		err := r.delRoute(p)
+		if err != nil {
+			return err
+		}
+		delete(r.routes, p)
+	}
+	for p := range routes {
+		if _, ok := r.routes[p]; ok {
+			continue
+		}
+		err := netlink.RouteReplace(&netlink.Route{
+			LinkIndex: r.link.Attrs().Index,
+			Dst:       ipNet(p),
+		})
+		if err != nil {
+			return xerrors.Errorf("add route %s: %w", p, err)
+		}
+		r.routes[p] = struct{}{}
+	}
+	return nil
+}
+
+// Close removes the routes.  The addresses go away with the device.
+func (r *linuxRouter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for p := range r.routes {
+		err := r.delRoute(p)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		delete(r.routes, p)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *linuxRouter) delRoute(p netip.Prefix) error {
+	err := netlink.RouteDel(&netlink.Route{
+		LinkIndex: r.link.Attrs().Index,
+		Dst:       ipNet(p),
+	})
+	if err != nil {
+		return xerrors.Errorf("delete route %s: %w", p, err)
+	}
+	return nil
+}
+
+func prefixSet(prefixes []netip.Prefix) map[netip.Prefix]struct{} {
+	set := make(map[netip.Prefix]struct{}, len(prefixes))
+	for _, p := range prefixes {
+		set[p] = struct{}{}
+	}
+	return set
+}
+
+func ipNet(p netip.Prefix) *net.IPNet {
+	return &net.IPNet{
+		IP:   p.Addr().AsSlice(),
+		Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen()),
+	}
+}
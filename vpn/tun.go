@@ -1,10 +1,10 @@
-//go:build !darwin && !windows
+//go:build !darwin && !windows && !linux
 
 package vpn
 
 import "cdr.dev/slog"
 
-// This is a no-op on every platform except Darwin and Windows.
+// This is a no-op on every platform except Darwin, Windows and Linux.
 func GetNetworkingStack(_ *Tunnel, _ *StartRequest, _ slog.Logger) (NetworkStack, error) {
 	return NetworkStack{}, nil
 }
@@ -0,0 +1,97 @@
+//go:build linux
+
+package vpn
+
+import (
+	"context"
+	"os"
+
+	"github.com/tailscale/wireguard-go/tun"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/dns"
+	"tailscale.com/net/netmon"
+	"tailscale.com/net/tstun"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+const tunName = "coder0"
+
+// GetNetworkingStack creates a TUN device from /dev/net/tun, which requires
+// CAP_NET_ADMIN.  If the manager passes the file descriptor of a TUN device it
+// opened itself, that device is used instead.  Unlike macOS, where the Desktop
+// app applies the network settings, the routes to the Coder prefix and the DNS
+// settings are configured here.  DNS uses systemd-resolved if it manages the
+// system's resolvers, and falls back to editing /etc/resolv.conf.
+func GetNetworkingStack(_ *Tunnel, req *StartRequest, logger slog.Logger) (NetworkStack, error) {
+	var (
+		tunDev  tun.Device
+		devName string
+		err     error
+	)
+	if fd := int(req.GetTunnelFileDescriptor()); fd > 0 {
+		tunDev, devName, err = tunFromFileDescriptor(fd)
+	} else {
+		tunDev, devName, err = tstun.New(tailnet.Logger(logger.Named("net.tun.device")), tunName)
+	}
+	if err != nil {
+		return NetworkStack{}, xerrors.Errorf("create tun device: %w", err)
+	}
+	logger.Info(context.Background(), "tun created", slog.F("name", devName))
+
+	wireguardMonitor, err := netmon.New(tailnet.Logger(logger.Named("net.wgmonitor")))
+	if err != nil {
+		_ = tunDev.Close()
+		return NetworkStack{}, xerrors.Errorf("create wireguard monitor: %w", err)
+	}
+
+	coderRouter, err := newLinuxRouter(devName)
+	if err != nil {
+		_ = wireguardMonitor.Close()
+		_ = tunDev.Close()
+		return NetworkStack{}, xerrors.Errorf("create router: %w", err)
+	}
+
+	dnsConfigurator, err := dns.NewOSConfigurator(tailnet.Logger(logger.Named("net.dns")), devName)
+	if err != nil {
+		_ = coderRouter.Close()
+		_ = wireguardMonitor.Close()
+		_ = tunDev.Close()
+		return NetworkStack{}, xerrors.Errorf("create dns configurator: %w", err)
+	}
+
+	return NetworkStack{
+		// The router watches the same monitor, the tailnet closes it.
+		WireguardMonitor: wireguardMonitor,
+		TUNDevice:        tunDev,
+		Router:           coderRouter,
+		DNSConfigurator:  dnsConfigurator,
+	}, nil
+}
+
+// tunFromFileDescriptor wraps a TUN device opened by the manager.  The file
+// descriptor is duplicated, so the manager keeps ownership of its copy.
+func tunFromFileDescriptor(fd int) (tun.Device, string, error) {
+	dupTunFd, err := unix.Dup(fd)
+	if err != nil {
+		return nil, "", xerrors.Errorf("dup tun fd: %w", err)
+	}
+	err = unix.SetNonblock(dupTunFd, true)
+	if err != nil {
+		_ = unix.Close(dupTunFd)
+		return nil, "", xerrors.Errorf("set nonblock: %w", err)
+	}
+	fileTun, err := tun.CreateTUNFromFile(os.NewFile(uintptr(dupTunFd), "/dev/net/tun"), int(tstun.DefaultMTU()))
+	if err != nil {
+		_ = unix.Close(dupTunFd)
+		return nil, "", xerrors.Errorf("create TUN from File: %w", err)
+	}
+	name, err := fileTun.Name()
+	if err != nil {
+		_ = fileTun.Close()
+		return nil, "", xerrors.Errorf("get tun name: %w", err)
+	}
+	return fileTun, name, nil
+}
@@ -0,0 +1,112 @@
+//go:build linux
+
+package vpn_test
+
+import (
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	"tailscale.com/net/dns"
+	"tailscale.com/util/dnsname"
+	"tailscale.com/wgengine/router"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/coder/v2/vpn"
+)
+
+// netNSTestEnv is set when the test runs in its own namespaces.
+const netNSTestEnv = "CODER_VPN_TEST_NETNS"
+
+// TestGetNetworkingStackLinux creates the TUN device, routes and DNS settings
+// in new user, network and mount namespaces, so the host is not touched.
+func TestGetNetworkingStackLinux(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(netNSTestEnv) == "" {
+		unshare := []string{"unshare", "--user", "--map-root-user", "--net", "--mount"}
+		//nolint:gosec // The arguments are constant.
+		err := exec.Command(unshare[0], append(unshare[1:], "true")...).Run()
+		if err != nil {
+			t.Skipf("cannot create namespaces: %v", err)
+		}
+		//nolint:gosec // The test runs itself.
+		cmd := exec.Command(unshare[0], append(unshare[1:], os.Args[0], "-test.run=^TestGetNetworkingStackLinux$", "-test.v")...)
+		cmd.Env = append(os.Environ(), netNSTestEnv+"=1")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return
+	}
+
+	// Hide the D-Bus socket and resolv.conf of the host, so DNS falls back to
+	// writing the resolv.conf of the test.
+	for _, dir := range []string{"/run", "/etc"} {
+		err := unix.Mount("tmpfs", dir, "tmpfs", 0, "")
+		if err != nil {
+			t.Skipf("mount tmpfs on %s: %v", dir, err)
+		}
+	}
+	resolvConf := filepath.Join("/etc", "resolv.conf")
+	require.NoError(t, os.WriteFile(resolvConf, []byte("nameserver 192.0.2.1\n"), 0o600))
+	if _, err := os.Stat("/dev/net/tun"); err != nil {
+		t.Skipf("no tun device: %v", err)
+	}
+
+	logger := testutil.Logger(t)
+	stack, err := vpn.GetNetworkingStack(nil, &vpn.StartRequest{}, logger)
+	require.NoError(t, err)
+	defer stack.TUNDevice.Close()
+	defer stack.WireguardMonitor.Close()
+	defer stack.Router.Close()
+	defer stack.DNSConfigurator.Close()
+
+	name, err := stack.TUNDevice.Name()
+	require.NoError(t, err)
+	require.Equal(t, "coder0", name)
+	_, err = net.InterfaceByName(name)
+	require.NoError(t, err)
+
+	// The Coder prefix is routed through the device.
+	ip := tailnet.CoderServicePrefix.RandomAddr()
+	require.NoError(t, stack.Router.Up())
+	require.NoError(t, stack.Router.Set(&router.Config{
+		LocalAddrs: []netip.Prefix{netip.PrefixFrom(ip, 128)},
+		Routes:     []netip.Prefix{tailnet.CoderServicePrefix.AsNetip()},
+	}))
+	out, err := exec.Command("ip", "-6", "route", "show", "table", "all").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), tailnet.CoderServicePrefix.AsNetip().String()+" dev "+name)
+	out, err = exec.Command("ip", "-6", "addr", "show", "dev", name).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), ip.String()+"/128")
+
+	// Routes that are no longer in the config are removed.
+	require.NoError(t, stack.Router.Set(&router.Config{
+		LocalAddrs: []netip.Prefix{netip.PrefixFrom(ip, 128)},
+	}))
+	out, err = exec.Command("ip", "-6", "route", "show", "table", "all").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NotContains(t, string(out), tailnet.CoderServicePrefix.AsNetip().String()+" dev "+name)
+
+	// The workspace resolver is set.
+	nameserver := netip.MustParseAddr("fd7a:115c:a1e0::53")
+	require.NoError(t, stack.DNSConfigurator.SetDNS(dns.OSConfig{
+		Nameservers:   []netip.Addr{nameserver},
+		SearchDomains: []dnsname.FQDN{"coder."},
+	}))
+	data, err := os.ReadFile(resolvConf)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "nameserver "+nameserver.String())
+
+	// The resolv.conf is restored on close.
+	require.NoError(t, stack.DNSConfigurator.Close())
+	data, err = os.ReadFile(resolvConf)
+	require.NoError(t, err)
+	require.Equal(t, "nameserver 192.0.2.1\n", string(data))
+}
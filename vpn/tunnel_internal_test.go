@@ -14,7 +14,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
 	"tailscale.com/util/dnsname"
 
 	"github.com/coder/quartz"
@@ -70,6 +72,18 @@ func (f *fakeConn) CurrentWorkspaceState() (tailnet.WorkspaceUpdate, error) {
 	return f.state, nil
 }
 
+func (*fakeConn) DialContextTCP(context.Context, netip.AddrPort) (*gonet.TCPConn, error) {
+	return nil, xerrors.New("not implemented")
+}
+
+func (*fakeConn) DialContextUDP(context.Context, netip.AddrPort) (*gonet.UDPConn, error) {
+	return nil, xerrors.New("not implemented")
+}
+
+func (*fakeConn) AwaitReachable(context.Context, netip.Addr) bool {
+	return false
+}
+
 func (f *fakeConn) GetPeerDiagnostics(uuid.UUID) tailnet.PeerDiagnostics {
 	return tailnet.PeerDiagnostics{
 		LastWireguardHandshake: f.hsTime,